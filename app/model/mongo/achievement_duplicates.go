@@ -0,0 +1,27 @@
+package model
+
+// #1 proses: definisikan konstanta jenis kecocokan yang dipakai untuk deteksi prestasi duplikat
+const (
+	DuplicateMatchTitle               = "title"
+	DuplicateMatchCompetition         = "competition"
+	DuplicateMatchCertificationNumber = "certificationNumber"
+	DuplicateMatchAttachment          = "attachment"
+)
+
+// #2 proses: struct kandidat prestasi duplikat, dikembalikan sebagai peringatan ke mahasiswa dan penanda ke dosen wali
+type DuplicateCandidate struct {
+	AchievementID   string   `json:"achievementId,omitempty"`
+	StudentID       string   `json:"studentId,omitempty"`
+	Title           string   `json:"title,omitempty"`
+	AchievementType string   `json:"achievementType,omitempty"`
+	Status          string   `json:"status,omitempty"`
+	MatchedOn       []string `json:"matchedOn"`
+	SameStudent     bool     `json:"sameStudent"`
+}
+
+// #3 proses: struct satu kelompok prestasi yang memiliki kunci duplikat sama, dipakai untuk laporan duplikat admin
+type DuplicateGroup struct {
+	MatchedOn    string               `json:"matchedOn"`
+	Value        string               `json:"value"`
+	Achievements []DuplicateCandidate `json:"achievements"`
+}
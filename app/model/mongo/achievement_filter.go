@@ -0,0 +1,41 @@
+package model
+
+// #1 proses: import library time untuk rentang tanggal filter
+import "time"
+
+// #2 proses: definisikan nama dimensi facet yang dihitung di MongoDB
+const (
+	FacetAchievementType     = "achievementType"
+	FacetCompetitionLevel    = "competitionLevel"
+	FacetTags                = "tags"
+	FacetHasAttachments      = "hasAttachments"
+	FacetPoints              = "points"
+	FacetYear                = "year"
+	FacetCertificationStatus = "certificationStatus"
+)
+
+// #3 proses: struct filter untuk query daftar prestasi di MongoDB, IDs berisi satu batch hasil query PostgreSQL. Limit hanya dipakai sort field MongoDB untuk membatasi kandidat urutan per batch
+type AchievementFilter struct {
+	IDs                   []string
+	AchievementTypes      []string
+	CompetitionLevels     []string
+	Tags                  []string
+	DateFrom              *time.Time
+	DateTo                *time.Time
+	MinPoints             *int
+	MaxPoints             *int
+	HasAttachments        *bool
+	CertificationStatuses []string
+	CertificationSoonDays int
+	Now                   time.Time
+	SortField             string
+	SortDescending        bool
+	Limit                 int
+}
+
+// #4 proses: struct jumlah prestasi untuk satu nilai pada dimensi facet
+type FacetCount struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int    `json:"count"`
+}
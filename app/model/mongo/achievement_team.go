@@ -0,0 +1,74 @@
+package model
+
+// #1 proses: import library yang diperlukan untuk pembulatan poin dan time
+import (
+	"math"
+	"time"
+)
+
+// #2 proses: struct anggota tim prestasi, setiap anggota punya peran, porsi poin (persen), dan status konfirmasi partisipasi
+type TeamMember struct {
+	StudentID   string     `bson:"studentId" json:"studentId"`
+	Role        string     `bson:"role" json:"role"`
+	PointShare  float64    `bson:"pointShare" json:"pointShare"`
+	Confirmed   bool       `bson:"confirmed" json:"confirmed"`
+	ConfirmedAt *time.Time `bson:"confirmedAt,omitempty" json:"confirmedAt,omitempty"`
+}
+
+// #3 proses: struct catatan verifikasi prestasi tim oleh satu dosen wali
+type TeamVerification struct {
+	AdvisorID  string    `bson:"advisorId" json:"advisorId"`
+	VerifiedBy string    `bson:"verifiedBy" json:"verifiedBy"`
+	VerifiedAt time.Time `bson:"verifiedAt" json:"verifiedAt"`
+}
+
+// #4 proses: struct request anggota tim saat create atau update prestasi
+type TeamMemberRequest struct {
+	StudentID  string  `json:"studentId"`
+	Role       string  `json:"role"`
+	PointShare float64 `json:"pointShare"`
+}
+
+// #5 proses: struct response untuk konfirmasi partisipasi anggota tim
+type ConfirmTeamParticipationResponse struct {
+	Status string       `json:"status"`
+	Data   []TeamMember `json:"data"`
+}
+
+// #6 proses: cek apakah achievement adalah prestasi tim
+func (a *Achievement) IsTeam() bool {
+	return len(a.TeamMembers) > 0
+}
+
+// #7 proses: ambil data anggota tim berdasarkan student ID, return nil jika bukan anggota
+func (a *Achievement) FindTeamMember(studentID string) *TeamMember {
+	for i := range a.TeamMembers {
+		if a.TeamMembers[i].StudentID == studentID {
+			return &a.TeamMembers[i]
+		}
+	}
+	return nil
+}
+
+// #8 proses: cek apakah student adalah pemilik atau anggota tim prestasi
+func (a *Achievement) InvolvesStudent(studentID string) bool {
+	return a.StudentID == studentID || a.FindTeamMember(studentID) != nil
+}
+
+// #9 proses: hitung poin yang dikreditkan ke student, prestasi tim dibagi sesuai porsi poin anggota
+func (a *Achievement) PointsForStudent(studentID string) int {
+	if !a.IsTeam() {
+		if a.StudentID == studentID {
+			return a.Points
+		}
+		return 0
+	}
+
+	member := a.FindTeamMember(studentID)
+	if member == nil {
+		return 0
+	}
+
+	// #9a proses: pembulatan ke genap terdekat, sama dengan $round di aggregation MongoDB
+	return int(math.RoundToEven(float64(a.Points) * member.PointShare / 100))
+}
@@ -0,0 +1,101 @@
+package model
+
+// #1 proses: import library yang diperlukan untuk MongoDB dan time
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// #2 proses: definisikan konstanta tipe data custom field yang bisa dipakai di skema tipe prestasi
+const (
+	CustomFieldTypeText        = "text"
+	CustomFieldTypeNumber      = "number"
+	CustomFieldTypeDate        = "date"
+	CustomFieldTypeBoolean     = "boolean"
+	CustomFieldTypeSelect      = "select"
+	CustomFieldTypeMultiSelect = "multiselect"
+	CustomFieldTypeURL         = "url"
+)
+
+// #3 proses: struct definisi satu custom field, dipakai untuk validasi dan render form di frontend
+type CustomFieldDefinition struct {
+	Key       string   `bson:"key" json:"key"`
+	Label     string   `bson:"label" json:"label"`
+	Type      string   `bson:"type" json:"type"`
+	Required  bool     `bson:"required" json:"required"`
+	Options   []string `bson:"options,omitempty" json:"options,omitempty"`
+	Min       *float64 `bson:"min,omitempty" json:"min,omitempty"`
+	Max       *float64 `bson:"max,omitempty" json:"max,omitempty"`
+	MinLength *int     `bson:"minLength,omitempty" json:"minLength,omitempty"`
+	MaxLength *int     `bson:"maxLength,omitempty" json:"maxLength,omitempty"`
+	Pattern   string   `bson:"pattern,omitempty" json:"pattern,omitempty"`
+	HelpText  string   `bson:"helpText,omitempty" json:"helpText,omitempty"`
+}
+
+// #4 proses: struct tipe prestasi yang disimpan di collection achievement_types
+type AchievementTypeDefinition struct {
+	ID           primitive.ObjectID      `bson:"_id,omitempty" json:"id,omitempty"`
+	Code         string                  `bson:"code" json:"code"`
+	Name         string                  `bson:"name" json:"name"`
+	Description  string                  `bson:"description,omitempty" json:"description,omitempty"`
+	CustomFields []CustomFieldDefinition `bson:"customFields" json:"customFields"`
+	IsBuiltIn    bool                    `bson:"isBuiltIn" json:"isBuiltIn"`
+	IsActive     bool                    `bson:"isActive" json:"isActive"`
+	CreatedBy    string                  `bson:"createdBy,omitempty" json:"createdBy,omitempty"`
+	CreatedAt    time.Time               `bson:"createdAt" json:"createdAt"`
+	UpdatedAt    time.Time               `bson:"updatedAt" json:"updatedAt"`
+}
+
+// #4a proses: tipe prestasi bawaan yang di-seed saat migrasi, kode tipe ini tidak bisa dibuat ulang
+var BuiltInAchievementTypes = []AchievementTypeDefinition{
+	{Code: AchievementTypeAcademic, Name: "Akademik", Description: "Prestasi akademik seperti IPK dan penghargaan akademik"},
+	{Code: AchievementTypeCompetition, Name: "Kompetisi", Description: "Prestasi lomba dan kompetisi"},
+	{Code: AchievementTypeOrganization, Name: "Organisasi", Description: "Jabatan dan kegiatan organisasi"},
+	{Code: AchievementTypePublication, Name: "Publikasi", Description: "Publikasi jurnal, konferensi, dan buku"},
+	{Code: AchievementTypeCertification, Name: "Sertifikasi", Description: "Sertifikasi profesional"},
+	{Code: AchievementTypeOther, Name: "Lainnya", Description: "Prestasi lain yang tidak masuk tipe di atas"},
+}
+
+// #5 proses: struct request untuk membuat tipe prestasi baru oleh admin
+type CreateAchievementTypeRequest struct {
+	Code         string                  `json:"code"`
+	Name         string                  `json:"name"`
+	Description  string                  `json:"description,omitempty"`
+	CustomFields []CustomFieldDefinition `json:"customFields,omitempty"`
+}
+
+// #6 proses: struct request untuk update tipe prestasi, field kosong tidak diubah
+type UpdateAchievementTypeRequest struct {
+	Name         string                  `bson:"name,omitempty" json:"name,omitempty"`
+	Description  *string                 `bson:"description,omitempty" json:"description,omitempty"`
+	CustomFields []CustomFieldDefinition `bson:"customFields,omitempty" json:"customFields,omitempty"`
+	IsActive     *bool                   `bson:"isActive,omitempty" json:"isActive,omitempty"`
+}
+
+// #7 proses: struct skema form tipe prestasi untuk dirender dinamis oleh frontend
+type AchievementTypeSchema struct {
+	Code         string                  `json:"code"`
+	Name         string                  `json:"name"`
+	Description  string                  `json:"description,omitempty"`
+	DetailFields []DetailFieldRule       `json:"detailFields"`
+	CustomFields []CustomFieldDefinition `json:"customFields"`
+}
+
+// #8 proses: struct response untuk get all tipe prestasi
+type GetAllAchievementTypesResponse struct {
+	Status string                      `json:"status"`
+	Data   []AchievementTypeDefinition `json:"data"`
+}
+
+// #9 proses: struct response untuk get satu tipe prestasi
+type GetAchievementTypeResponse struct {
+	Status string                    `json:"status"`
+	Data   AchievementTypeDefinition `json:"data"`
+}
+
+// #10 proses: struct response untuk get skema form tipe prestasi
+type GetAchievementTypeSchemaResponse struct {
+	Status string                `json:"status"`
+	Data   AchievementTypeSchema `json:"data"`
+}
@@ -0,0 +1,80 @@
+package model
+
+// #1 proses: import library strings untuk menggabungkan pesan error
+import "strings"
+
+// #2 proses: definisikan konstanta jenis medali untuk prestasi tipe competition
+const (
+	MedalTypeGold   = "gold"
+	MedalTypeSilver = "silver"
+	MedalTypeBronze = "bronze"
+)
+
+// #3 proses: definisikan konstanta format khusus yang dicek oleh aturan validasi detail prestasi
+const (
+	DetailFormatISSN   = "issn"
+	DetailFormatDOI    = "doi"
+	DetailFormatPeriod = "period"
+	DetailFormatRank   = "rank"
+)
+
+// #4 proses: key aturan yang berlaku untuk semua tipe prestasi
+const AllAchievementTypes = "*"
+
+// #5 proses: struct aturan validasi untuk satu field detail prestasi
+type DetailFieldRule struct {
+	Field    string   `json:"field"`
+	Required bool     `json:"required"`
+	Enum     []string `json:"enum,omitempty"`
+	Format   string   `json:"format,omitempty"`
+}
+
+// #6 proses: aturan validasi detail prestasi per tipe, dipakai saat create, update, dan submit
+var AchievementDetailRules = map[string][]DetailFieldRule{
+	AllAchievementTypes: {
+		{Field: "competitionLevel", Enum: []string{CompetitionLevelInternational, CompetitionLevelNational, CompetitionLevelRegional, CompetitionLevelLocal}},
+		{Field: "publicationType", Enum: []string{PublicationTypeJournal, PublicationTypeConference, PublicationTypeBook}},
+		{Field: "medalType", Enum: []string{MedalTypeGold, MedalTypeSilver, MedalTypeBronze}},
+		{Field: "rank", Format: DetailFormatRank},
+		{Field: "issn", Format: DetailFormatISSN},
+		{Field: "doi", Format: DetailFormatDOI},
+		{Field: "period", Format: DetailFormatPeriod},
+	},
+	AchievementTypeCompetition: {
+		{Field: "competitionName", Required: true},
+		{Field: "competitionLevel", Required: true},
+	},
+	AchievementTypePublication: {
+		{Field: "publicationType", Required: true},
+		{Field: "publicationTitle", Required: true},
+	},
+	AchievementTypeOrganization: {
+		{Field: "organizationName", Required: true},
+		{Field: "position", Required: true},
+	},
+	AchievementTypeCertification: {
+		{Field: "certificationName", Required: true},
+		{Field: "issuedBy", Required: true},
+	},
+}
+
+// #7 proses: struct error untuk satu field yang tidak valid
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// #8 proses: struct error validasi yang berisi daftar field error, dikembalikan ke client sebagai response terstruktur
+type ValidationError struct {
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors"`
+}
+
+// #9 proses: implementasi interface error, gabungkan semua pesan field error
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldErr := range e.Errors {
+		messages = append(messages, fieldErr.Field+" "+fieldErr.Message)
+	}
+	return e.Message + ": " + strings.Join(messages, "; ")
+}
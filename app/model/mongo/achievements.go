@@ -38,12 +38,21 @@ type Period struct {
 	End   time.Time `bson:"end" json:"end"`
 }
 
-// #6 proses: struct untuk attachment file yang diupload untuk prestasi
+// #6 proses: struct untuk attachment file yang diupload untuk prestasi, ID tetap sama saat file diganti, DownloadURL dan PreviewURL diisi service saat response dan tidak disimpan, HasPreview menandai ada preview JPEG di samping file asli. ScanStatus kosong berarti file diupload saat pemindaian malware tidak aktif
 type Attachment struct {
-	FileName   string    `bson:"fileName" json:"fileName"`
-	FileURL    string    `bson:"fileUrl" json:"fileUrl"`
-	FileType   string    `bson:"fileType" json:"fileType"`
-	UploadedAt time.Time `bson:"uploadedAt" json:"uploadedAt"`
+	ID            string     `bson:"id,omitempty" json:"id,omitempty"`
+	FileName      string     `bson:"fileName" json:"fileName"`
+	FileURL       string     `bson:"fileUrl" json:"fileUrl"`
+	FileType      string     `bson:"fileType" json:"fileType"`
+	ContentHash   string     `bson:"contentHash,omitempty" json:"contentHash,omitempty"`
+	Size          int64      `bson:"size,omitempty" json:"size,omitempty"`
+	UploadedAt    time.Time  `bson:"uploadedAt" json:"uploadedAt"`
+	DownloadURL   string     `bson:"-" json:"downloadUrl,omitempty"`
+	HasPreview    bool       `bson:"hasPreview,omitempty" json:"-"`
+	PreviewURL    string     `bson:"-" json:"previewUrl,omitempty"`
+	ScanStatus    string     `bson:"scanStatus,omitempty" json:"scanStatus,omitempty"`
+	ScanSignature string     `bson:"scanSignature,omitempty" json:"scanSignature,omitempty"`
+	ScannedAt     *time.Time `bson:"scannedAt,omitempty" json:"scannedAt,omitempty"`
 }
 
 // #7 proses: struct untuk detail prestasi yang dinamis, field berbeda tergantung tipe prestasi
@@ -57,6 +66,7 @@ type AchievementDetails struct {
 	Authors             []string               `bson:"authors,omitempty" json:"authors,omitempty"`
 	Publisher           *string                `bson:"publisher,omitempty" json:"publisher,omitempty"`
 	ISSN                *string                `bson:"issn,omitempty" json:"issn,omitempty"`
+	DOI                 *string                `bson:"doi,omitempty" json:"doi,omitempty"`
 	OrganizationName    *string                `bson:"organizationName,omitempty" json:"organizationName,omitempty"`
 	Position            *string                `bson:"position,omitempty" json:"position,omitempty"`
 	Period              *Period                `bson:"period,omitempty" json:"period,omitempty"`
@@ -71,70 +81,93 @@ type AchievementDetails struct {
 	CustomFields        map[string]interface{} `bson:"customFields,omitempty" json:"customFields,omitempty"`
 }
 
-// #8 proses: struct utama untuk menyimpan data prestasi di MongoDB
+// #8 proses: struct untuk catatan override poin oleh dosen wali saat verifikasi
+type PointsOverride struct {
+	CalculatedPoints int       `bson:"calculatedPoints" json:"calculatedPoints"`
+	Points           int       `bson:"points" json:"points"`
+	Justification    string    `bson:"justification" json:"justification"`
+	OverriddenBy     string    `bson:"overriddenBy" json:"overriddenBy"`
+	OverriddenAt     time.Time `bson:"overriddenAt" json:"overriddenAt"`
+}
+
+// #9 proses: struct utama untuk menyimpan data prestasi di MongoDB, Version naik setiap isi prestasi diubah untuk optimistic concurrency
 type Achievement struct {
-	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	StudentID       string             `bson:"studentId" json:"studentId"`
-	AchievementType string             `bson:"achievementType" json:"achievementType"`
-	Title           string             `bson:"title" json:"title"`
-	Description     string             `bson:"description" json:"description"`
-	Details         AchievementDetails `bson:"details" json:"details"`
-	Attachments     []Attachment       `bson:"attachments,omitempty" json:"attachments,omitempty"`
-	Tags            []string           `bson:"tags,omitempty" json:"tags,omitempty"`
-	Points          int                `bson:"points" json:"points"`
-	DeletedAt       *time.Time         `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
-	CreatedAt       time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt       time.Time          `bson:"updatedAt" json:"updatedAt"`
+	ID                  primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	StudentID           string             `bson:"studentId" json:"studentId"`
+	AchievementType     string             `bson:"achievementType" json:"achievementType"`
+	Title               string             `bson:"title" json:"title"`
+	Description         string             `bson:"description" json:"description"`
+	Details             AchievementDetails `bson:"details" json:"details"`
+	Attachments         []Attachment       `bson:"attachments,omitempty" json:"attachments,omitempty"`
+	Tags                []string           `bson:"tags,omitempty" json:"tags,omitempty"`
+	Points              int                `bson:"points" json:"points"`
+	RubricVersion       int                `bson:"rubricVersion,omitempty" json:"rubricVersion,omitempty"`
+	PointsOverride      *PointsOverride    `bson:"pointsOverride,omitempty" json:"pointsOverride,omitempty"`
+	DuplicateKeys       []string           `bson:"duplicateKeys,omitempty" json:"-"`
+	TeamMembers         []TeamMember       `bson:"teamMembers,omitempty" json:"teamMembers,omitempty"`
+	DesignatedAdvisorID string             `bson:"designatedAdvisorId,omitempty" json:"designatedAdvisorId,omitempty"`
+	TeamVerifications   []TeamVerification `bson:"teamVerifications,omitempty" json:"teamVerifications,omitempty"`
+	DeletedAt           *time.Time         `bson:"deletedAt,omitempty" json:"deletedAt,omitempty"`
+	Version             int                `bson:"version" json:"version"`
+	CreatedAt           time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt           time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
 
-// #9 proses: struct untuk request create prestasi baru
+// #10 proses: struct untuk request create prestasi baru, attachment tidak bisa diisi di sini karena hanya ditulis server lewat endpoint upload
 type CreateAchievementRequest struct {
-	StudentID       string             `bson:"studentId" json:"studentId" validate:"required"`
-	AchievementType string             `bson:"achievementType" json:"achievementType" validate:"required,oneof=academic competition organization publication certification other"`
-	Title           string             `bson:"title" json:"title" validate:"required"`
-	Description     string             `bson:"description" json:"description" validate:"required"`
-	Details         AchievementDetails `bson:"details" json:"details"`
-	Attachments     []Attachment       `bson:"attachments,omitempty" json:"attachments,omitempty"`
-	Tags            []string           `bson:"tags,omitempty" json:"tags,omitempty"`
-	Points          int                `bson:"points" json:"points" validate:"required"`
+	StudentID           string              `bson:"studentId" json:"studentId" validate:"required"`
+	AchievementType     string              `bson:"achievementType" json:"achievementType" validate:"required"`
+	Title               string              `bson:"title" json:"title" validate:"required"`
+	Description         string              `bson:"description" json:"description" validate:"required"`
+	Details             AchievementDetails  `bson:"details" json:"details"`
+	Tags                []string            `bson:"tags,omitempty" json:"tags,omitempty"`
+	TeamMembers         []TeamMemberRequest `bson:"-" json:"teamMembers,omitempty"`
+	DesignatedAdvisorID string              `bson:"-" json:"designatedAdvisorId,omitempty"`
 }
 
-// #10 proses: struct untuk request update prestasi, semua field optional karena partial update
+// #11 proses: struct untuk request update prestasi, semua field optional karena partial update. Attachment hanya diubah lewat endpoint upload, replace, dan hapus attachment
 type UpdateAchievementRequest struct {
-	AchievementType string              `bson:"achievementType,omitempty" json:"achievementType,omitempty" validate:"omitempty,oneof=academic competition organization publication certification other"`
-	Title           string              `bson:"title,omitempty" json:"title,omitempty" validate:"omitempty"`
-	Description     string              `bson:"description,omitempty" json:"description,omitempty" validate:"omitempty"`
-	Details         *AchievementDetails `bson:"details,omitempty" json:"details,omitempty"`
-	Attachments     []Attachment        `bson:"attachments,omitempty" json:"attachments,omitempty"`
-	Tags            []string            `bson:"tags,omitempty" json:"tags,omitempty"`
-	Points          *int                `bson:"points,omitempty" json:"points,omitempty"`
+	AchievementType     string               `bson:"achievementType,omitempty" json:"achievementType,omitempty" validate:"omitempty"`
+	Title               string               `bson:"title,omitempty" json:"title,omitempty" validate:"omitempty"`
+	Description         string               `bson:"description,omitempty" json:"description,omitempty" validate:"omitempty"`
+	Details             *AchievementDetails  `bson:"details,omitempty" json:"details,omitempty"`
+	Tags                []string             `bson:"tags,omitempty" json:"tags,omitempty"`
+	TeamMembers         *[]TeamMemberRequest `bson:"-" json:"teamMembers,omitempty"`
+	DesignatedAdvisorID *string              `bson:"-" json:"designatedAdvisorId,omitempty"`
 }
 
-// #11 proses: struct response untuk get all achievements, return list semua prestasi
+// #12 proses: struct response untuk get all achievements, return list semua prestasi
 type GetAllAchievementsResponse struct {
 	Status string        `json:"status"`
 	Data   []Achievement `json:"data"`
 }
 
-// #12 proses: struct response untuk get achievement by ID, return satu prestasi
+// #13 proses: struct response untuk get achievement by ID, return satu prestasi
 type GetAchievementByIDResponse struct {
 	Status string      `json:"status"`
 	Data   Achievement `json:"data"`
 }
 
-// #13 proses: struct response untuk create achievement, return prestasi yang baru dibuat
+// #14 proses: struct response untuk create achievement, return prestasi yang baru dibuat
 type CreateAchievementResponse struct {
-	Status string      `json:"status"`
-	Data   Achievement `json:"data"`
+	Status            string               `json:"status"`
+	Data              Achievement          `json:"data"`
+	DuplicateWarnings []DuplicateCandidate `json:"duplicateWarnings,omitempty"`
 }
 
-// #14 proses: struct response untuk update achievement, return prestasi yang sudah diupdate
+// #15 proses: struct response untuk update achievement, return prestasi yang sudah diupdate
 type UpdateAchievementResponse struct {
 	Status string      `json:"status"`
 	Data   Achievement `json:"data"`
 }
 
-// #15 proses: struct response untuk delete achievement, hanya return status
+// #16 proses: struct response untuk delete achievement, hanya return status
 type DeleteAchievementResponse struct {
 	Status string `json:"status"`
 }
+
+// #17 proses: struct untuk request preview poin berdasarkan tipe dan detail prestasi
+type CalculatePointsRequest struct {
+	AchievementType string             `json:"achievementType" validate:"required"`
+	Details         AchievementDetails `json:"details"`
+}
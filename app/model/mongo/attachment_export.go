@@ -0,0 +1,28 @@
+package model
+
+// #1 proses: import library time untuk tanggal prestasi dan upload
+import "time"
+
+// #2 proses: struct hasil persiapan export attachment, daftar file sudah difilter dan dibatasi sesuai role sebelum ZIP mulai dikirim
+type AttachmentExport struct {
+	FileName string
+	Entries  []AttachmentExportEntry
+}
+
+// #3 proses: struct satu baris manifest export, Path adalah lokasi file di dalam ZIP dan Note diisi jika file tidak ikut dimasukkan
+type AttachmentExportEntry struct {
+	Path             string
+	Key              string
+	AchievementID    string
+	AchievementTitle string
+	AchievementType  string
+	Status           string
+	CreatedAt        time.Time
+	StudentID        string
+	StudentName      string
+	ProgramStudy     string
+	AcademicYear     string
+	AdvisorName      string
+	Attachment       Attachment
+	Note             string
+}
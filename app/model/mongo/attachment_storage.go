@@ -0,0 +1,134 @@
+package model
+
+// #1 proses: import library io, time, dan primitive untuk stream file, timestamp, dan ObjectID
+import (
+	"io"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// #2 proses: status hasil migrasi satu file attachment
+const (
+	StorageMigrationStatusMigrated = "migrated"
+	StorageMigrationStatusPending  = "pending"
+	StorageMigrationStatusMissing  = "missing"
+	StorageMigrationStatusSkipped  = "skipped"
+	StorageMigrationStatusFailed   = "failed"
+)
+
+// #3 proses: struct satu file attachment yang dipindah ke storage tujuan, pending berarti akan dipindah pada dry-run
+type StorageMigrationItem struct {
+	AchievementID string `json:"achievement_id"`
+	FileName      string `json:"file_name"`
+	Key           string `json:"key"`
+	OldURL        string `json:"old_url"`
+	NewURL        string `json:"new_url"`
+	Size          int64  `json:"size"`
+	Status        string `json:"status"`
+	Error         string `json:"error,omitempty"`
+}
+
+// #4 proses: ringkasan hasil satu kali migrasi file attachment antar storage
+type StorageMigrationResult struct {
+	SourceDriver string                 `json:"source_driver"`
+	TargetDriver string                 `json:"target_driver"`
+	DryRun       bool                   `json:"dry_run"`
+	DeleteSource bool                   `json:"delete_source"`
+	StartedAt    time.Time              `json:"started_at"`
+	Total        int                    `json:"total"`
+	Migrated     int                    `json:"migrated"`
+	Missing      int                    `json:"missing"`
+	Skipped      int                    `json:"skipped"`
+	Failed       int                    `json:"failed"`
+	Items        []StorageMigrationItem `json:"items"`
+}
+
+// #5 proses: konfigurasi attachment, LinkSecret dipakai untuk tanda tangan HMAC link download, LinkTTL adalah masa berlaku link, ScanEnabled menandai upload baru sebagai pending sampai dipindai, dan StudentQuota adalah batas total ukuran attachment per mahasiswa dalam byte (0 berarti tanpa batas)
+type AttachmentConfig struct {
+	LinkSecret   []byte
+	LinkTTL      time.Duration
+	ScanEnabled  bool
+	StudentQuota int64
+}
+
+// #6 proses: file attachment yang siap dikirim ke client, caller wajib menutup Body
+type AttachmentDownload struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	ModTime     time.Time
+	Body        io.ReadCloser
+}
+
+// #7 proses: link download attachment bertanda tangan yang berlaku sampai ExpiresAt
+type AttachmentLink struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// #8 proses: struct response untuk pembuatan link download attachment
+type AttachmentLinkResponse struct {
+	Status string         `json:"status"`
+	Data   AttachmentLink `json:"data"`
+}
+
+// #9 proses: hasil validasi file attachment, FileName sudah disanitasi dan FileType berasal dari isi file
+type AttachmentFileCheck struct {
+	FileName string
+	FileType string
+	MaxSize  int64
+}
+
+// #10 proses: error validasi file attachment, TooLarge dipetakan ke 413 dan selain itu ke 400
+type AttachmentValidationError struct {
+	Message  string
+	TooLarge bool
+}
+
+// #11 proses: implementasi interface error
+func (e *AttachmentValidationError) Error() string {
+	return e.Message
+}
+
+// #12 proses: status pemindaian malware attachment, file pending dan infected tidak bisa didownload oleh dosen wali dan admin
+const (
+	AttachmentScanPending  = "pending"
+	AttachmentScanClean    = "clean"
+	AttachmentScanInfected = "infected"
+)
+
+// #13 proses: ringkasan satu kali pemindaian attachment yang masih pending
+type AttachmentScanRunResult struct {
+	Scanned  int `json:"scanned"`
+	Clean    int `json:"clean"`
+	Infected int `json:"infected"`
+	Failed   int `json:"failed"`
+}
+
+// #14 proses: struct response daftar attachment prestasi setelah attachment dihapus
+type AttachmentListResponse struct {
+	Status string       `json:"status"`
+	Data   []Attachment `json:"data"`
+}
+
+// #15 proses: error kuota storage attachment mahasiswa terlampaui, dipetakan ke 413. Used dan Quota dalam byte
+type AttachmentQuotaError struct {
+	Message string
+	Used    int64
+	Quota   int64
+}
+
+// #16 proses: implementasi interface error
+func (e *AttachmentQuotaError) Error() string {
+	return e.Message
+}
+
+// #17 proses: reservasi kuota storage untuk file yang sedang disimpan, dihapus setelah attachment tercatat atau upload gagal. ExpiresAt membatasi umur reservasi yang tertinggal jika server berhenti di tengah upload
+type AttachmentQuotaReservation struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	StudentID string             `bson:"studentId" json:"student_id"`
+	Size      int64              `bson:"size" json:"size"`
+	ExpiresAt time.Time          `bson:"expiresAt" json:"expires_at"`
+	CreatedAt time.Time          `bson:"createdAt" json:"created_at"`
+}
@@ -0,0 +1,52 @@
+package model
+
+// #1 proses: import library MongoDB primitive dan time untuk ID dan timestamp
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// #2 proses: struct sesi upload bertahap, potongan file disimpan terpisah di storage sampai upload diselesaikan. AttachmentID diisi jika upload untuk mengganti attachment
+type AttachmentUpload struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	AchievementID string             `bson:"achievementId" json:"achievement_id"`
+	StudentID     string             `bson:"studentId" json:"-"`
+	AttachmentID  string             `bson:"attachmentId,omitempty" json:"attachment_id,omitempty"`
+	FileName      string             `bson:"fileName" json:"file_name"`
+	FileType      string             `bson:"fileType" json:"file_type"`
+	Size          int64              `bson:"size" json:"size"`
+	ChunkSize     int64              `bson:"chunkSize" json:"chunk_size"`
+	Offset        int64              `bson:"offset" json:"offset"`
+	ExpiresAt     time.Time          `bson:"expiresAt" json:"expires_at"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"created_at"`
+	UpdatedAt     time.Time          `bson:"updatedAt" json:"updated_at"`
+}
+
+// #3 proses: struct request untuk memulai upload bertahap, AttachmentID diisi untuk mengganti attachment yang sudah ada
+type CreateAttachmentUploadRequest struct {
+	FileName     string `json:"file_name"`
+	Size         int64  `json:"size"`
+	AttachmentID string `json:"attachment_id,omitempty"`
+}
+
+// #4 proses: struct response sesi upload bertahap
+type AttachmentUploadResponse struct {
+	Status string           `json:"status"`
+	Data   AttachmentUpload `json:"data"`
+}
+
+// #5 proses: konfigurasi upload bertahap, ChunkSize adalah ukuran potongan selain potongan terakhir, TTL adalah masa berlaku sesi sejak potongan terakhir diterima, dan MaxActive adalah jumlah sesi aktif per mahasiswa
+type AttachmentUploadConfig struct {
+	ChunkSize       int64
+	TTL             time.Duration
+	MaxActive       int
+	CleanupInterval time.Duration
+}
+
+// #6 proses: ringkasan satu kali pembersihan sesi upload bertahap yang kedaluwarsa
+type AttachmentUploadCleanupResult struct {
+	Expired       int `json:"expired"`
+	ChunksDeleted int `json:"chunks_deleted"`
+	Failed        int `json:"failed"`
+}
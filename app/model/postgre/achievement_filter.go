@@ -0,0 +1,50 @@
+package model
+
+// #1 proses: import library time untuk rentang tanggal filter
+import "time"
+
+// #2 proses: definisikan nama dimensi facet yang dihitung dari data PostgreSQL
+const (
+	FacetStatus       = "status"
+	FacetProgramStudy = "programStudy"
+	FacetAcademicYear = "academicYear"
+	FacetAdvisor      = "advisor"
+)
+
+// #3 proses: struct filter daftar prestasi dari query parameter, semua field list bersifat multi-select (OR di dalam satu dimensi, AND antar dimensi). RowLimit dan RowOffset membatasi baris reference yang diambil dari PostgreSQL, 0 berarti tanpa batas
+type AchievementListFilter struct {
+	Page                  int
+	Limit                 int
+	Statuses              []string
+	AchievementTypes      []string
+	CertificationStatuses []string
+	CompetitionLevels     []string
+	Tags                  []string
+	ProgramStudies        []string
+	AcademicYears         []string
+	AdvisorIDs            []string
+	StudentIDs            []string
+	DateFrom              *time.Time
+	DateTo                *time.Time
+	MinPoints             *int
+	MaxPoints             *int
+	HasAttachments        *bool
+	SortBy                string
+	SortOrder             string
+	ScopeStudentID        string
+	ScopeAdvisorID        string
+	CursorMode            bool
+	Cursor                *PageCursor
+	RowLimit              int
+	RowOffset             int
+}
+
+// #4 proses: struct satu baris hasil filter reference beserta data mahasiswa dan dosen wali untuk facet
+type AchievementListRow struct {
+	AchievementReference
+	StudentName  string `json:"student_name"`
+	ProgramStudy string `json:"program_study"`
+	AcademicYear string `json:"academic_year"`
+	AdvisorID    string `json:"advisor_id"`
+	AdvisorName  string `json:"advisor_name"`
+}
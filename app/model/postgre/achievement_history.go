@@ -0,0 +1,36 @@
+package model
+
+// #1 proses: import library time untuk handle timestamp
+import "time"
+
+// #2 proses: definisikan konstanta aksi yang dicatat di history prestasi
+const (
+	AchievementHistoryActionSubmitted     = "submitted"
+	AchievementHistoryActionWithdrawn     = "withdrawn"
+	AchievementHistoryActionReviewStarted = "review_started"
+	AchievementHistoryActionSLAReminder   = "sla_reminder"
+	AchievementHistoryActionSLAEscalated  = "sla_escalated"
+	AchievementHistoryActionDeleted       = "deleted"
+	AchievementHistoryActionRestored      = "restored"
+	AchievementHistoryActionReconciled    = "reconciled"
+)
+
+// #3 proses: struct satu catatan history prestasi, status adalah status prestasi setelah aksi dijalankan
+type AchievementHistory struct {
+	ID               string    `json:"id"`
+	AchievementRefID string    `json:"achievement_ref_id"`
+	Action           string    `json:"action"`
+	Status           string    `json:"status"`
+	ChangedBy        *string   `json:"changed_by"`
+	Note             *string   `json:"note"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// #4 proses: struct untuk request catat history prestasi baru
+type CreateAchievementHistoryRequest struct {
+	AchievementRefID string  `json:"achievement_ref_id"`
+	Action           string  `json:"action"`
+	Status           string  `json:"status"`
+	ChangedBy        *string `json:"changed_by"`
+	Note             *string `json:"note"`
+}
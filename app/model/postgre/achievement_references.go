@@ -1,7 +1,10 @@
 package model
 
-// #1 proses: import library time untuk handle timestamp
-import "time"
+// #1 proses: import library time untuk handle timestamp dan model MongoDB untuk peringatan duplikat
+import (
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	"time"
+)
 
 // #2 proses: definisikan konstanta status prestasi untuk workflow approval
 const (
@@ -12,7 +15,7 @@ const (
 	AchievementStatusDeleted   = "deleted"
 )
 
-// #3 proses: struct untuk menyimpan referensi prestasi di PostgreSQL, link ke MongoDB achievement
+// #3 proses: struct untuk menyimpan referensi prestasi di PostgreSQL, link ke MongoDB achievement. Version naik setiap status berubah
 type AchievementReference struct {
 	ID                 string     `json:"id"`
 	StudentID          string     `json:"student_id"`
@@ -22,6 +25,7 @@ type AchievementReference struct {
 	VerifiedAt         *time.Time `json:"verified_at"`
 	VerifiedBy         *string    `json:"verified_by"`
 	RejectionNote      *string    `json:"rejection_note"`
+	Version            int        `json:"version,omitempty"`
 	CreatedAt          time.Time  `json:"created_at"`
 	UpdatedAt          time.Time  `json:"updated_at"`
 }
@@ -39,8 +43,10 @@ type UpdateAchievementReferenceRequest struct {
 	RejectionNote string `json:"rejection_note"`
 }
 
-// #6 proses: struct untuk request verify prestasi, tidak perlu field tambahan
+// #6 proses: struct untuk request verify prestasi, poin optional untuk override hasil rubrik dengan justifikasi
 type VerifyAchievementRequest struct {
+	Points              *int   `json:"points,omitempty"`
+	PointsJustification string `json:"points_justification,omitempty"`
 }
 
 // #7 proses: struct untuk request reject prestasi, wajib ada alasan penolakan
@@ -68,8 +74,9 @@ type CreateAchievementReferenceResponse struct {
 
 // #11 proses: struct response untuk update achievement reference, return referensi yang sudah diupdate
 type UpdateAchievementReferenceResponse struct {
-	Status string               `json:"status"`
-	Data   AchievementReference `json:"data"`
+	Status            string                          `json:"status"`
+	Data              AchievementReference            `json:"data"`
+	DuplicateWarnings []modelmongo.DuplicateCandidate `json:"duplicateWarnings,omitempty"`
 }
 
 // #12 proses: struct response untuk delete achievement reference, hanya return status
@@ -77,14 +84,23 @@ type DeleteAchievementReferenceResponse struct {
 	Status string `json:"status"`
 }
 
-// #13 proses: struct response untuk verify achievement, return referensi yang sudah diverifikasi
+// #13 proses: struct response untuk verify achievement, return referensi yang sudah diverifikasi. Untuk prestasi tim, pending_advisor_ids berisi dosen wali yang belum memverifikasi
 type VerifyAchievementResponse struct {
-	Status string               `json:"status"`
-	Data   AchievementReference `json:"data"`
+	Status            string               `json:"status"`
+	Data              AchievementReference `json:"data"`
+	PendingAdvisorIDs []string             `json:"pending_advisor_ids,omitempty"`
+	ETag              string               `json:"etag,omitempty"`
 }
 
 // #14 proses: struct response untuk reject achievement, return referensi yang sudah ditolak
 type RejectAchievementResponse struct {
 	Status string               `json:"status"`
 	Data   AchievementReference `json:"data"`
+	ETag   string               `json:"etag,omitempty"`
+}
+
+// #15 proses: versi prestasi yang diharapkan client dari header If-Match, gabungan versi dokumen MongoDB dan reference PostgreSQL
+type AchievementVersion struct {
+	Achievement int
+	Reference   int
 }
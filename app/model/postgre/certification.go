@@ -0,0 +1,24 @@
+package model
+
+// #1 proses: import library time untuk handle durasi dan timestamp
+import "time"
+
+// #2 proses: definisikan status masa berlaku sertifikasi berdasarkan details.validUntil
+const (
+	CertificationStatusValid        = "valid"
+	CertificationStatusExpiringSoon = "expiring_soon"
+	CertificationStatusExpired      = "expired"
+)
+
+// #3 proses: konfigurasi pemantauan masa berlaku sertifikasi, AlertDays diurutkan dari yang terbesar
+type CertificationExpiryConfig struct {
+	AlertDays            []int         `json:"alert_days"`
+	CheckInterval        time.Duration `json:"check_interval"`
+	ExcludeExpiredPoints bool          `json:"exclude_expired_points"`
+}
+
+// #4 proses: ringkasan hasil satu kali pengecekan masa berlaku sertifikasi
+type CertificationExpiryCheckResult struct {
+	Checked  int `json:"checked"`
+	Notified int `json:"notified"`
+}
@@ -5,8 +5,13 @@ import "time"
 
 // #2 proses: definisikan konstanta tipe notifikasi yang tersedia
 const (
-	NotificationTypeAchievementRejected  = "achievement_rejected"
-	NotificationTypeAchievementSubmitted = "achievement_submitted"
+	NotificationTypeAchievementRejected   = "achievement_rejected"
+	NotificationTypeAchievementSubmitted  = "achievement_submitted"
+	NotificationTypeTeamInvitation        = "team_invitation"
+	NotificationTypeSLAReminder           = "achievement_sla_reminder"
+	NotificationTypeSLAEscalated          = "achievement_sla_escalated"
+	NotificationTypeCertificationExpiring = "certification_expiring"
+	NotificationTypeAttachmentInfected    = "attachment_infected"
 )
 
 // #3 proses: struct utama untuk menyimpan data notifikasi di database
@@ -39,13 +44,21 @@ type GetNotificationsResponse struct {
 	Status     string         `json:"status"`
 	Data       []Notification `json:"data"`
 	Pagination struct {
-		Page       int `json:"page"`
-		Limit      int `json:"limit"`
-		Total      int `json:"total"`
-		TotalPages int `json:"total_pages"`
+		Page       int    `json:"page"`
+		Limit      int    `json:"limit"`
+		Total      int    `json:"total"`
+		TotalPages int    `json:"total_pages"`
+		NextCursor string `json:"next_cursor,omitempty"`
 	} `json:"pagination"`
 }
 
+// #5b proses: struct response untuk get notifications dengan pagination cursor
+type GetNotificationsCursorResponse struct {
+	Status     string           `json:"status"`
+	Data       []Notification   `json:"data"`
+	Pagination CursorPagination `json:"pagination"`
+}
+
 // #6 proses: struct response untuk get unread count, return jumlah notifikasi belum dibaca
 type GetUnreadCountResponse struct {
 	Status string `json:"status"`
@@ -0,0 +1,17 @@
+package model
+
+// #1 proses: import library time untuk posisi cursor
+import "time"
+
+// #2 proses: posisi keyset hasil decode cursor, data berikutnya diambil setelah pasangan created_at dan id ini
+type PageCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// #3 proses: struct pagination mode cursor, tanpa total karena tidak menjalankan query COUNT
+type CursorPagination struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
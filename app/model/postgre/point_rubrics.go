@@ -0,0 +1,67 @@
+package model
+
+// #1 proses: import library time untuk handle timestamp
+import "time"
+
+// #2 proses: definisikan konstanta field detail prestasi yang bisa dipakai sebagai kondisi aturan poin
+const (
+	PointRuleFieldCompetitionLevel = "competitionLevel"
+	PointRuleFieldRank             = "rank"
+	PointRuleFieldMedalType        = "medalType"
+	PointRuleFieldPublicationType  = "publicationType"
+	PointRuleFieldPosition         = "position"
+)
+
+// #3 proses: struct untuk satu aturan poin, field kosong berarti poin dasar untuk tipe prestasi
+type PointRule struct {
+	AchievementType string `json:"achievement_type"`
+	Field           string `json:"field,omitempty"`
+	Value           string `json:"value,omitempty"`
+	Points          int    `json:"points"`
+}
+
+// #4 proses: struct utama untuk rubrik poin yang berversi, hanya satu rubrik yang aktif
+type PointRubric struct {
+	ID          string      `json:"id"`
+	Version     int         `json:"version"`
+	Name        string      `json:"name"`
+	Description *string     `json:"description"`
+	Rules       []PointRule `json:"rules"`
+	IsActive    bool        `json:"is_active"`
+	CreatedBy   *string     `json:"created_by"`
+	ActivatedAt *time.Time  `json:"activated_at"`
+	CreatedAt   time.Time   `json:"created_at"`
+}
+
+// #5 proses: struct untuk request create rubrik poin baru, bisa langsung diaktifkan
+type CreatePointRubricRequest struct {
+	Name        string      `json:"name" validate:"required"`
+	Description string      `json:"description"`
+	Rules       []PointRule `json:"rules" validate:"required"`
+	Activate    bool        `json:"activate"`
+}
+
+// #6 proses: struct hasil perhitungan poin beserta versi rubrik dan aturan yang cocok
+type PointCalculationResult struct {
+	Points        int         `json:"points"`
+	RubricVersion int         `json:"rubric_version"`
+	MatchedRules  []PointRule `json:"matched_rules"`
+}
+
+// #7 proses: struct response untuk get all rubrik poin, return list semua versi rubrik
+type GetAllPointRubricsResponse struct {
+	Status string        `json:"status"`
+	Data   []PointRubric `json:"data"`
+}
+
+// #8 proses: struct response untuk get rubrik poin, return satu rubrik
+type GetPointRubricResponse struct {
+	Status string      `json:"status"`
+	Data   PointRubric `json:"data"`
+}
+
+// #9 proses: struct response untuk hitung poin, return hasil perhitungan
+type CalculatePointsResponse struct {
+	Status string                 `json:"status"`
+	Data   PointCalculationResult `json:"data"`
+}
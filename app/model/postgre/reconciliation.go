@@ -0,0 +1,76 @@
+package model
+
+// #1 proses: import library time untuk handle durasi dan timestamp
+import "time"
+
+// #2 proses: kategori ketidakkonsistenan antara dokumen MongoDB dan reference PostgreSQL
+const (
+	ReconcileCategoryMongoWithoutReference = "mongo_without_reference"
+	ReconcileCategoryReferenceWithoutMongo = "reference_without_mongo"
+	ReconcileCategoryDeletedMismatch       = "deleted_status_mismatch"
+)
+
+// #3 proses: policy perbaikan per kategori, report berarti hanya dilaporkan tanpa diperbaiki
+const (
+	ReconcilePolicyReport          = "report"
+	ReconcilePolicyPurge           = "purge"
+	ReconcilePolicyAdopt           = "adopt"
+	ReconcilePolicyDeleteReference = "delete_reference"
+	ReconcilePolicyFollowReference = "follow_reference"
+	ReconcilePolicyFollowMongo     = "follow_mongo"
+)
+
+// #4 proses: policy yang dipakai untuk setiap kategori saat rekonsiliasi dijalankan
+type ReconcilePolicy struct {
+	MongoWithoutReference string `json:"mongo_without_reference"`
+	ReferenceWithoutMongo string `json:"reference_without_mongo"`
+	DeletedMismatch       string `json:"deleted_status_mismatch"`
+}
+
+// #5 proses: konfigurasi rekonsiliasi, dokumen MongoDB yang lebih muda dari GracePeriod dilewati karena bisa jadi create yang masih berjalan
+type ReconcileConfig struct {
+	DefaultPolicy ReconcilePolicy `json:"default_policy"`
+	GracePeriod   time.Duration   `json:"grace_period"`
+}
+
+// #6 proses: struct satu ketidakkonsistenan beserta aksi perbaikan dan hasilnya
+type ReconcileIssue struct {
+	Category           string `json:"category"`
+	MongoAchievementID string `json:"mongo_achievement_id"`
+	AchievementRefID   string `json:"achievement_ref_id,omitempty"`
+	StudentID          string `json:"student_id,omitempty"`
+	Title              string `json:"title,omitempty"`
+	Detail             string `json:"detail"`
+	Action             string `json:"action"`
+	Repaired           bool   `json:"repaired"`
+	Error              string `json:"error,omitempty"`
+}
+
+// #7 proses: ringkasan jumlah ketidakkonsistenan per kategori
+type ReconcileCategorySummary struct {
+	Category string `json:"category"`
+	Policy   string `json:"policy"`
+	Found    int    `json:"found"`
+	Repaired int    `json:"repaired"`
+	Failed   int    `json:"failed"`
+}
+
+// #8 proses: hasil satu kali rekonsiliasi
+type ReconcileResult struct {
+	CheckedAt       time.Time                  `json:"checked_at"`
+	MongoDocuments  int                        `json:"mongo_documents"`
+	References      int                        `json:"references"`
+	SkippedRecent   int                        `json:"skipped_recent"`
+	Policy          ReconcilePolicy            `json:"policy"`
+	Categories      []ReconcileCategorySummary `json:"categories"`
+	Issues          []ReconcileIssue           `json:"issues"`
+	TotalIssues     int                        `json:"total_issues"`
+	TotalRepaired   int                        `json:"total_repaired"`
+	TotalUnresolved int                        `json:"total_unresolved"`
+}
+
+// #9 proses: struct response untuk endpoint rekonsiliasi
+type ReconcileResponse struct {
+	Status string          `json:"status"`
+	Data   ReconcileResult `json:"data"`
+}
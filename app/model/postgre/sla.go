@@ -0,0 +1,52 @@
+package model
+
+// #1 proses: import library time untuk handle durasi dan timestamp
+import "time"
+
+// #2 proses: definisikan tahap SLA untuk prestasi yang terlalu lama menunggu verifikasi
+const (
+	SLAStageReminder  = "reminder"
+	SLAStageEscalated = "escalated"
+)
+
+// #3 proses: konfigurasi SLA verifikasi, batas waktu dihitung sejak prestasi di-submit
+type SLAConfig struct {
+	ReminderAfter      time.Duration `json:"reminder_after"`
+	EscalateAfter      time.Duration `json:"escalate_after"`
+	CheckInterval      time.Duration `json:"check_interval"`
+	FallbackVerifierID string        `json:"fallback_verifier_id"`
+}
+
+// #4 proses: struct satu prestasi yang melewati batas SLA beserta umurnya
+type OverdueAchievement struct {
+	AchievementRefID   string     `json:"achievement_ref_id"`
+	MongoAchievementID string     `json:"mongo_achievement_id"`
+	StudentID          string     `json:"student_id"`
+	StudentName        string     `json:"student_name"`
+	AdvisorID          string     `json:"advisor_id"`
+	AdvisorName        string     `json:"advisor_name"`
+	SubmittedAt        time.Time  `json:"submitted_at"`
+	AgeHours           int        `json:"age_hours"`
+	AgeDays            int        `json:"age_days"`
+	Stage              string     `json:"stage"`
+	RemindedAt         *time.Time `json:"reminded_at"`
+	EscalatedAt        *time.Time `json:"escalated_at"`
+}
+
+// #5 proses: ringkasan hasil satu kali pengecekan SLA
+type SLACheckResult struct {
+	Checked   int `json:"checked"`
+	Reminded  int `json:"reminded"`
+	Escalated int `json:"escalated"`
+}
+
+// #6 proses: struct response untuk daftar prestasi yang melewati batas SLA
+type GetOverdueAchievementsResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		ReminderAfterHours int                  `json:"reminder_after_hours"`
+		EscalateAfterHours int                  `json:"escalate_after_hours"`
+		Total              int                  `json:"total"`
+		Achievements       []OverdueAchievement `json:"achievements"`
+	} `json:"data"`
+}
@@ -0,0 +1,66 @@
+package model
+
+// #1 proses: import library time untuk handle durasi dan timestamp
+import "time"
+
+// #2 proses: konfigurasi retensi tempat sampah prestasi, prestasi yang dihapus lebih lama dari RetentionDays dihapus permanen
+type TrashRetentionConfig struct {
+	RetentionDays int           `json:"retention_days"`
+	PurgeInterval time.Duration `json:"purge_interval"`
+	DryRun        bool          `json:"dry_run"`
+}
+
+// #3 proses: struct referensi prestasi yang sudah dihapus beserta waktu hapus dan status sebelum dihapus
+type DeletedAchievementReference struct {
+	AchievementReference
+	DeletedAt          time.Time `json:"deleted_at"`
+	StatusBeforeDelete string    `json:"status_before_delete"`
+}
+
+// #4 proses: struct satu prestasi di tempat sampah untuk ditampilkan ke admin
+type DeletedAchievement struct {
+	DeletedAchievementReference
+	Title           string    `json:"title"`
+	AchievementType string    `json:"achievement_type"`
+	StudentName     string    `json:"student_name"`
+	PurgeAt         time.Time `json:"purge_at"`
+}
+
+// #5 proses: struct response untuk daftar tempat sampah dengan pagination
+type GetDeletedAchievementsResponse struct {
+	Status     string               `json:"status"`
+	Data       []DeletedAchievement `json:"data"`
+	Pagination struct {
+		Page       int `json:"page"`
+		Limit      int `json:"limit"`
+		Total      int `json:"total"`
+		TotalPages int `json:"total_pages"`
+	} `json:"pagination"`
+}
+
+// #6 proses: struct satu prestasi yang dihapus permanen atau akan dihapus pada dry-run
+type TrashPurgeItem struct {
+	AchievementRefID   string    `json:"achievement_ref_id"`
+	MongoAchievementID string    `json:"mongo_achievement_id"`
+	Title              string    `json:"title"`
+	DeletedAt          time.Time `json:"deleted_at"`
+	Files              []string  `json:"files"`
+	Error              string    `json:"error,omitempty"`
+}
+
+// #7 proses: ringkasan hasil satu kali purge tempat sampah
+type TrashPurgeResult struct {
+	DryRun        bool             `json:"dry_run"`
+	RetentionDays int              `json:"retention_days"`
+	Cutoff        time.Time        `json:"cutoff"`
+	Total         int              `json:"total"`
+	Purged        int              `json:"purged"`
+	FilesDeleted  int              `json:"files_deleted"`
+	Items         []TrashPurgeItem `json:"items"`
+}
+
+// #8 proses: struct response untuk purge tempat sampah
+type TrashPurgeResponse struct {
+	Status string           `json:"status"`
+	Data   TrashPurgeResult `json:"data"`
+}
@@ -1,8 +1,10 @@
 package repository
 
-// #1 proses: import library yang diperlukan untuk MongoDB, context, dan time
+// #1 proses: import library yang diperlukan untuk MongoDB, options query, context, regexp, dan time
 import (
 	"context"
+	"fmt"
+	"regexp"
 	"time"
 
 	model "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
@@ -10,6 +12,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // #2 proses: struct untuk hasil aggregasi top students berdasarkan points
@@ -19,11 +22,56 @@ type TopStudentResult struct {
 	AchievementCount int    `bson:"count" json:"achievement_count"`
 }
 
+// #2b proses: struct untuk hasil aggregasi kunci duplikat yang dipakai lebih dari satu achievement
+type DuplicateKeyGroup struct {
+	Key            string   `bson:"_id"`
+	AchievementIDs []string `bson:"achievementIds"`
+	Count          int      `bson:"count"`
+}
+
+// #2c proses: struct untuk hasil pencarian full-text beserta skor relevansi
+type AchievementSearchResult struct {
+	model.Achievement `bson:",inline"`
+	Score             float64 `bson:"score" json:"score"`
+}
+
+// #2d proses: struct untuk hasil hitung facet satu batch ID, MatchedIDs hanya berisi ID batch yang lolos semua filter untuk hitung facet PostgreSQL dan Achievements hanya berisi _id, points, dan title untuk digabung urutannya antar batch
+type AchievementFacetResult struct {
+	Achievements []model.Achievement
+	Total        int
+	MatchedIDs   []string
+	Facets       map[string][]model.FacetCount
+}
+
+// #2e proses: struct ringkas achievement untuk rekonsiliasi dengan PostgreSQL, hanya field yang dibutuhkan supaya scan seluruh collection tetap ringan
+type AchievementStoreState struct {
+	ID        primitive.ObjectID `bson:"_id"`
+	StudentID string             `bson:"studentId"`
+	Title     string             `bson:"title"`
+	CreatedAt time.Time          `bson:"createdAt"`
+	DeletedAt *time.Time         `bson:"deletedAt"`
+}
+
+// #2f proses: struct untuk hasil aggregasi pemakaian storage attachment per mahasiswa, hanya prestasi yang belum dihapus
+type StudentAttachmentUsage struct {
+	StudentID string `bson:"_id" json:"student_id"`
+	Files     int    `bson:"files" json:"files"`
+	Bytes     int64  `bson:"bytes" json:"bytes"`
+}
+
+// #2g proses: struct untuk total file attachment di storage, LogicalBytes dihitung per attachment dan StoredBytes per file unik setelah deduplikasi
+type AttachmentStorageTotals struct {
+	Attachments  int   `bson:"attachments" json:"attachments"`
+	UniqueFiles  int   `bson:"uniqueFiles" json:"unique_files"`
+	LogicalBytes int64 `bson:"logicalBytes" json:"logical_bytes"`
+	StoredBytes  int64 `bson:"storedBytes" json:"stored_bytes"`
+}
+
 // #3 proses: definisikan interface untuk operasi database achievement di MongoDB
 type IAchievementRepository interface {
 	CreateAchievement(ctx context.Context, achievement *model.Achievement) (*model.Achievement, error)
 	GetAchievementByID(ctx context.Context, id string) (*model.Achievement, error)
-	UpdateAchievement(ctx context.Context, id string, req model.UpdateAchievementRequest) (*model.Achievement, error)
+	UpdateAchievement(ctx context.Context, id string, expectedVersion int, req model.UpdateAchievementRequest) (*model.Achievement, error)
 	DeleteAchievement(ctx context.Context, id string) error
 	GetAchievementsByStudentID(ctx context.Context, studentID string) ([]model.Achievement, error)
 	GetAchievementsByIDs(ctx context.Context, ids []string) ([]model.Achievement, error)
@@ -31,24 +79,57 @@ type IAchievementRepository interface {
 	GetAchievementsByType(ctx context.Context) (map[string]int, error)
 	GetCompetitionLevelDistribution(ctx context.Context) (map[string]int, error)
 	GetTopStudentsByPoints(ctx context.Context, limit int) ([]TopStudentResult, error)
+	UpdateAchievementPoints(ctx context.Context, id string, points int, rubricVersion int, override *model.PointsOverride) error
+	UpdateDuplicateKeys(ctx context.Context, id string, keys []string) error
+	FindAchievementsByDuplicateKeys(ctx context.Context, excludeID string, keys []string, limit int) ([]model.Achievement, error)
+	GetDuplicateKeyGroups(ctx context.Context) ([]DuplicateKeyGroup, error)
+	UpdateTeamMembers(ctx context.Context, id string, members []model.TeamMember, designatedAdvisorID string) error
+	ConfirmTeamMember(ctx context.Context, id string, studentID string, confirmedAt time.Time) error
+	SetTeamVerifications(ctx context.Context, id string, verifications []model.TeamVerification) error
+	AddTeamVerification(ctx context.Context, id string, verification model.TeamVerification, otherAdvisorIDs []string, completes bool) (bool, error)
+	RemoveTeamVerification(ctx context.Context, id string, advisorID string) error
+	GetDeletedAchievementsByIDs(ctx context.Context, ids []string) ([]model.Achievement, error)
+	RestoreAchievement(ctx context.Context, id string) error
+	PurgeAchievement(ctx context.Context, id string) (bool, error)
+	GetAchievementsValidUntilBetween(ctx context.Context, from, to time.Time) ([]model.Achievement, error)
+	SearchAchievements(ctx context.Context, query string, achievementIDs []string, page, limit int) ([]AchievementSearchResult, int, error)
+	GetAchievementFacets(ctx context.Context, filter model.AchievementFilter) (*AchievementFacetResult, error)
+	GetMatchingAchievementIDs(ctx context.Context, filter model.AchievementFilter) ([]string, error)
+	GetAllAchievementStates(ctx context.Context) ([]AchievementStoreState, error)
+	GetAchievementsByAttachmentURLPrefix(ctx context.Context, prefix string) ([]model.Achievement, error)
+	UpdateAttachmentFileURL(ctx context.Context, id string, oldURL string, newURL string) (bool, error)
+	GetAchievementsByAttachmentScanStatus(ctx context.Context, status string, limit int) ([]model.Achievement, error)
+	UpdateAttachmentScanResult(ctx context.Context, id string, fileURL string, status string, signature string, newURL string) (bool, error)
+	RemoveAttachment(ctx context.Context, id string, attachmentID string) (*model.Achievement, error)
+	ReplaceAttachment(ctx context.Context, id string, attachmentID string, attachment model.Attachment) (*model.Achievement, error)
+	CountAttachmentFileReferences(ctx context.Context, fileURL string) (int64, error)
+	GetStudentAttachmentUsage(ctx context.Context, studentID string) (int64, error)
+	GetAttachmentUsageByStudent(ctx context.Context) ([]StudentAttachmentUsage, error)
+	GetAttachmentStorageTotals(ctx context.Context) (*AttachmentStorageTotals, error)
+	ReserveAttachmentQuota(ctx context.Context, studentID string, size int64, expiresAt time.Time) (string, error)
+	GetStudentReservedAttachmentQuota(ctx context.Context, studentID string, excludeID string, now time.Time) (int64, error)
+	ReleaseAttachmentQuota(ctx context.Context, id string) error
 }
 
 // #4 proses: struct repository untuk operasi database achievement di MongoDB
 type AchievementRepository struct {
-	collection *mongo.Collection
+	collection   *mongo.Collection
+	reservations *mongo.Collection
 }
 
 // #5 proses: constructor untuk membuat instance AchievementRepository baru
 func NewAchievementRepository(db *mongo.Database) IAchievementRepository {
 	return &AchievementRepository{
-		collection: db.Collection("achievements"),
+		collection:   db.Collection("achievements"),
+		reservations: db.Collection("attachment_quota_reservations"),
 	}
 }
 
 // #6 proses: buat achievement baru di MongoDB
 func (r *AchievementRepository) CreateAchievement(ctx context.Context, achievement *model.Achievement) (*model.Achievement, error) {
-	// #6a proses: reset ID dan set timestamp sebelum insert
+	// #6a proses: reset ID, mulai dari versi 1, dan set timestamp sebelum insert
 	achievement.ID = primitive.NilObjectID
+	achievement.Version = 1
 	achievement.CreatedAt = time.Now()
 	achievement.UpdatedAt = time.Now()
 
@@ -88,8 +169,8 @@ func (r *AchievementRepository) GetAchievementByID(ctx context.Context, id strin
 	return &achievement, nil
 }
 
-// #8 proses: update achievement yang sudah ada, partial update
-func (r *AchievementRepository) UpdateAchievement(ctx context.Context, id string, req model.UpdateAchievementRequest) (*model.Achievement, error) {
+// #8 proses: update achievement yang sudah ada, partial update. Update hanya berjalan jika versi dokumen masih sama dengan expectedVersion, return nil jika versi sudah berubah
+func (r *AchievementRepository) UpdateAchievement(ctx context.Context, id string, expectedVersion int, req model.UpdateAchievementRequest) (*model.Achievement, error) {
 	// #8a proses: convert string ID jadi ObjectID
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
@@ -101,7 +182,7 @@ func (r *AchievementRepository) UpdateAchievement(ctx context.Context, id string
 		"updatedAt": time.Now(),
 	}
 
-	// #8c proses: tambahkan field ke update document jika ada nilainya
+	// #8c proses: tambahkan field ke update document jika ada nilainya, attachments tidak pernah ditulis di sini supaya penghapusan file selalu lewat DeleteAttachment dan ReplaceAttachment yang mengecek refcount storage
 	if req.AchievementType != "" {
 		update["achievementType"] = req.AchievementType
 	}
@@ -114,27 +195,33 @@ func (r *AchievementRepository) UpdateAchievement(ctx context.Context, id string
 	if req.Details != nil {
 		update["details"] = req.Details
 	}
-	if req.Attachments != nil {
-		update["attachments"] = req.Attachments
-	}
 	if req.Tags != nil {
 		update["tags"] = req.Tags
 	}
-	if req.Points != nil {
-		update["points"] = *req.Points
-	}
 
-	// #8d proses: update document di MongoDB dengan $set operator
-	_, err = r.collection.UpdateOne(
+	// #8d proses: update document dengan $set dan naikkan versi, filter versi membuat cek dan update terjadi atomik
+	result, err := r.collection.UpdateOne(
 		ctx,
-		bson.M{"_id": objectID},
-		bson.M{"$set": update},
+		bson.M{
+			"_id":       objectID,
+			"deletedAt": bson.M{"$exists": false},
+			"version":   versionFilter(expectedVersion),
+		},
+		bson.M{
+			"$set": update,
+			"$inc": bson.M{"version": 1},
+		},
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	// #8e proses: ambil achievement yang sudah diupdate untuk return
+	// #8e proses: tidak ada dokumen yang cocok berarti prestasi sudah diubah request lain atau sudah dihapus
+	if result.MatchedCount == 0 {
+		return nil, nil
+	}
+
+	// #8f proses: ambil achievement yang sudah diupdate untuk return
 	return r.GetAchievementByID(ctx, id)
 }
 
@@ -159,11 +246,14 @@ func (r *AchievementRepository) DeleteAchievement(ctx context.Context, id string
 	return err
 }
 
-// #10 proses: ambil semua achievement milik student tertentu
+// #10 proses: ambil semua achievement milik student tertentu, termasuk prestasi tim di mana student menjadi anggota
 func (r *AchievementRepository) GetAchievementsByStudentID(ctx context.Context, studentID string) ([]model.Achievement, error) {
-	// #10a proses: query untuk cari achievement dengan filter studentId dan belum dihapus
+	// #10a proses: query untuk cari achievement dengan filter studentId atau anggota tim dan belum dihapus
 	cursor, err := r.collection.Find(ctx, bson.M{
-		"studentId": studentID,
+		"$or": bson.A{
+			bson.M{"studentId": studentID},
+			bson.M{"teamMembers.studentId": studentID},
+		},
 		"deletedAt": bson.M{"$exists": false},
 	})
 	if err != nil {
@@ -224,7 +314,7 @@ func (r *AchievementRepository) AddAttachmentToAchievement(ctx context.Context,
 		return nil, err
 	}
 
-	// #12b proses: update achievement dengan $push untuk tambahkan attachment ke array
+	// #12b proses: update achievement dengan $push untuk tambahkan attachment ke array, versi ikut naik karena isi prestasi berubah
 	_, err = r.collection.UpdateOne(
 		ctx,
 		bson.M{
@@ -234,6 +324,7 @@ func (r *AchievementRepository) AddAttachmentToAchievement(ctx context.Context,
 		bson.M{
 			"$push": bson.M{"attachments": attachment},
 			"$set":  bson.M{"updatedAt": time.Now()},
+			"$inc":  bson.M{"version": 1},
 		},
 	)
 	if err != nil {
@@ -335,17 +426,43 @@ func (r *AchievementRepository) GetTopStudentsByPoints(ctx context.Context, limi
 		limit = 10
 	}
 
-	// #15b proses: buat aggregation pipeline untuk sum points per student, sort, dan limit
+	// #15b proses: buat aggregation pipeline untuk sum points per student, prestasi tim dikreditkan ke setiap anggota sesuai porsi poin
 	pipeline := []bson.M{
 		{
 			"$match": bson.M{
 				"deletedAt": bson.M{"$exists": false},
 			},
 		},
+		{
+			"$project": bson.M{
+				"credits": bson.M{
+					"$cond": bson.A{
+						bson.M{"$gt": bson.A{bson.M{"$size": bson.M{"$ifNull": bson.A{"$teamMembers", bson.A{}}}}, 0}},
+						bson.M{
+							"$map": bson.M{
+								"input": "$teamMembers",
+								"as":    "member",
+								"in": bson.M{
+									"studentId": "$$member.studentId",
+									"points": bson.M{"$round": bson.A{
+										bson.M{"$divide": bson.A{bson.M{"$multiply": bson.A{"$points", "$$member.pointShare"}}, 100}},
+										0,
+									}},
+								},
+							},
+						},
+						bson.A{bson.M{"studentId": "$studentId", "points": "$points"}},
+					},
+				},
+			},
+		},
+		{
+			"$unwind": "$credits",
+		},
 		{
 			"$group": bson.M{
-				"_id":         "$studentId",
-				"totalPoints": bson.M{"$sum": "$points"},
+				"_id":         "$credits.studentId",
+				"totalPoints": bson.M{"$sum": "$credits.points"},
 				"count":       bson.M{"$sum": 1},
 			},
 		},
@@ -372,3 +489,1003 @@ func (r *AchievementRepository) GetTopStudentsByPoints(ctx context.Context, limi
 
 	return results, nil
 }
+
+// #16 proses: simpan poin hasil rubrik beserta versi rubrik dan override dari dosen wali jika ada
+func (r *AchievementRepository) UpdateAchievementPoints(ctx context.Context, id string, points int, rubricVersion int, override *model.PointsOverride) error {
+	// #16a proses: convert string ID jadi ObjectID
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	// #16b proses: set poin dan versi rubrik, override dihapus jika tidak ada
+	update := bson.M{
+		"$set": bson.M{
+			"points":        points,
+			"rubricVersion": rubricVersion,
+			"updatedAt":     time.Now(),
+		},
+	}
+	if override != nil {
+		update["$set"].(bson.M)["pointsOverride"] = override
+	} else {
+		update["$unset"] = bson.M{"pointsOverride": ""}
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	return err
+}
+
+// #17 proses: simpan kunci duplikat hasil normalisasi untuk deteksi prestasi ganda
+func (r *AchievementRepository) UpdateDuplicateKeys(ctx context.Context, id string, keys []string) error {
+	// #17a proses: convert string ID jadi ObjectID
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	// #17b proses: set kunci duplikat, hapus field jika tidak ada kunci
+	update := bson.M{"$set": bson.M{"duplicateKeys": keys}}
+	if len(keys) == 0 {
+		update = bson.M{"$unset": bson.M{"duplicateKeys": ""}}
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	return err
+}
+
+// #18 proses: cari achievement lain yang memiliki minimal satu kunci duplikat yang sama
+func (r *AchievementRepository) FindAchievementsByDuplicateKeys(ctx context.Context, excludeID string, keys []string, limit int) ([]model.Achievement, error) {
+	// #18a proses: jika tidak ada kunci, tidak ada kandidat duplikat
+	if len(keys) == 0 {
+		return []model.Achievement{}, nil
+	}
+
+	// #18b proses: validasi limit, set default 10 jika tidak valid
+	if limit <= 0 {
+		limit = 10
+	}
+
+	// #18c proses: filter dengan $in pada kunci duplikat, exclude achievement itu sendiri dan yang sudah dihapus
+	filter := bson.M{
+		"duplicateKeys": bson.M{"$in": keys},
+		"deletedAt":     bson.M{"$exists": false},
+	}
+	if objectID, err := primitive.ObjectIDFromHex(excludeID); err == nil {
+		filter["_id"] = bson.M{"$ne": objectID}
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetLimit(int64(limit)).SetSort(bson.M{"createdAt": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	// #18d proses: decode semua hasil ke slice achievements
+	var achievements []model.Achievement
+	if err = cursor.All(ctx, &achievements); err != nil {
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+// #19 proses: kelompokkan achievement berdasarkan kunci duplikat yang dipakai lebih dari satu achievement
+func (r *AchievementRepository) GetDuplicateKeyGroups(ctx context.Context) ([]DuplicateKeyGroup, error) {
+	// #19a proses: buat aggregation pipeline untuk unwind kunci duplikat, group per kunci, dan ambil yang count > 1
+	pipeline := []bson.M{
+		{
+			"$match": bson.M{
+				"deletedAt":     bson.M{"$exists": false},
+				"duplicateKeys": bson.M{"$exists": true, "$ne": bson.A{}},
+			},
+		},
+		{
+			"$unwind": "$duplicateKeys",
+		},
+		{
+			"$group": bson.M{
+				"_id":            "$duplicateKeys",
+				"achievementIds": bson.M{"$addToSet": bson.M{"$toString": "$_id"}},
+				"count":          bson.M{"$sum": 1},
+			},
+		},
+		{
+			"$match": bson.M{"count": bson.M{"$gt": 1}},
+		},
+		{
+			"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}},
+		},
+	}
+
+	// #19b proses: eksekusi aggregation pipeline
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	// #19c proses: decode semua hasil ke slice groups
+	var groups []DuplicateKeyGroup
+	if err = cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// #20 proses: simpan daftar anggota tim dan dosen wali yang ditunjuk untuk verifikasi prestasi tim
+func (r *AchievementRepository) UpdateTeamMembers(ctx context.Context, id string, members []model.TeamMember, designatedAdvisorID string) error {
+	// #20a proses: convert string ID jadi ObjectID
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	// #20b proses: set anggota tim, field dihapus jika prestasi bukan prestasi tim
+	set := bson.M{"updatedAt": time.Now()}
+	unset := bson.M{}
+	if len(members) > 0 {
+		set["teamMembers"] = members
+	} else {
+		unset["teamMembers"] = ""
+	}
+	if designatedAdvisorID != "" {
+		set["designatedAdvisorId"] = designatedAdvisorID
+	} else {
+		unset["designatedAdvisorId"] = ""
+	}
+
+	update := bson.M{"$set": set}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	return err
+}
+
+// #21 proses: tandai anggota tim sudah mengkonfirmasi partisipasi
+func (r *AchievementRepository) ConfirmTeamMember(ctx context.Context, id string, studentID string, confirmedAt time.Time) error {
+	// #21a proses: convert string ID jadi ObjectID
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	// #21b proses: update anggota tim yang cocok dengan positional operator
+	_, err = r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":                   objectID,
+			"teamMembers.studentId": studentID,
+		},
+		bson.M{
+			"$set": bson.M{
+				"teamMembers.$.confirmed":   true,
+				"teamMembers.$.confirmedAt": confirmedAt,
+				"updatedAt":                 time.Now(),
+			},
+		},
+	)
+	return err
+}
+
+// #22 proses: simpan daftar verifikasi dosen wali untuk prestasi tim, list kosong berarti reset verifikasi
+func (r *AchievementRepository) SetTeamVerifications(ctx context.Context, id string, verifications []model.TeamVerification) error {
+	// #22a proses: convert string ID jadi ObjectID
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	// #22b proses: set verifikasi, hapus field jika list kosong
+	update := bson.M{"$set": bson.M{"teamVerifications": verifications}}
+	if len(verifications) == 0 {
+		update = bson.M{"$unset": bson.M{"teamVerifications": ""}}
+	}
+
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	return err
+}
+
+// #23 proses: ambil beberapa achievement yang sudah di-soft delete berdasarkan list ID, dipakai untuk tempat sampah
+func (r *AchievementRepository) GetDeletedAchievementsByIDs(ctx context.Context, ids []string) ([]model.Achievement, error) {
+	// #23a proses: convert semua string ID jadi ObjectID, ID tidak valid dilewati
+	var objectIDs []primitive.ObjectID
+	for _, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	if len(objectIDs) == 0 {
+		return []model.Achievement{}, nil
+	}
+
+	// #23b proses: query dengan $in, hanya achievement yang punya deletedAt
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"_id":       bson.M{"$in": objectIDs},
+		"deletedAt": bson.M{"$exists": true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	// #23c proses: decode semua hasil ke slice achievements
+	var achievements []model.Achievement
+	if err = cursor.All(ctx, &achievements); err != nil {
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+// #24 proses: restore achievement yang di-soft delete dengan hapus field deletedAt
+func (r *AchievementRepository) RestoreAchievement(ctx context.Context, id string) error {
+	// #24a proses: convert string ID jadi ObjectID
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	// #24b proses: unset deletedAt supaya achievement terbaca lagi
+	_, err = r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID},
+		bson.M{
+			"$unset": bson.M{"deletedAt": ""},
+			"$set":   bson.M{"updatedAt": time.Now()},
+		},
+	)
+	return err
+}
+
+// #25 proses: hapus permanen achievement, hanya achievement yang sudah di-soft delete. Return false jika tidak ada dokumen yang terhapus
+func (r *AchievementRepository) PurgeAchievement(ctx context.Context, id string) (bool, error) {
+	// #25a proses: convert string ID jadi ObjectID
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	// #25b proses: delete document dengan filter deletedAt supaya achievement aktif tidak ikut terhapus
+	result, err := r.collection.DeleteOne(ctx, bson.M{
+		"_id":       objectID,
+		"deletedAt": bson.M{"$exists": true},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	// #25c proses: tidak ada dokumen terhapus berarti achievement sudah di-restore atau sudah di-purge
+	return result.DeletedCount > 0, nil
+}
+
+// #26 proses: ambil achievement aktif yang masa berlaku sertifikasinya berakhir di rentang waktu tertentu
+func (r *AchievementRepository) GetAchievementsValidUntilBetween(ctx context.Context, from, to time.Time) ([]model.Achievement, error) {
+	// #26a proses: query berdasarkan details.validUntil, achievement yang dihapus tidak ikut
+	cursor, err := r.collection.Find(ctx, bson.M{
+		"details.validUntil": bson.M{"$gte": from, "$lte": to},
+		"deletedAt":          bson.M{"$exists": false},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	// #26b proses: decode semua hasil ke slice achievements
+	var achievements []model.Achievement
+	if err = cursor.All(ctx, &achievements); err != nil {
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+// #27 proses: cari achievement dengan text index di antara ID yang lolos filter reference PostgreSQL, urut berdasarkan relevansi. Total dan halaman dihitung dari match yang sama dalam satu aggregation $facet
+func (r *AchievementRepository) SearchAchievements(ctx context.Context, query string, achievementIDs []string, page, limit int) ([]AchievementSearchResult, int, error) {
+	// #27a proses: konversi ID hasil query PostgreSQL, ID yang tidak valid dilewati
+	objectIDs := make([]primitive.ObjectID, 0, len(achievementIDs))
+	for _, id := range achievementIDs {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+	if len(objectIDs) == 0 {
+		return []AchievementSearchResult{}, 0, nil
+	}
+
+	// #27b proses: filter $text dan achievement yang belum dihapus, dibatasi ke ID dari PostgreSQL, skor relevansi diambil dari textScore
+	pipeline := []bson.M{
+		{"$match": bson.M{
+			"$text":     bson.M{"$search": query},
+			"deletedAt": bson.M{"$exists": false},
+			"_id":       bson.M{"$in": objectIDs},
+		}},
+		{"$addFields": bson.M{"score": bson.M{"$meta": "textScore"}}},
+		{"$facet": bson.M{
+			"total": []bson.M{{"$count": "count"}},
+			"data": []bson.M{
+				{"$sort": bson.D{{Key: "score", Value: -1}, {Key: "_id", Value: 1}}},
+				{"$skip": int64((page - 1) * limit)},
+				{"$limit": int64(limit)},
+			},
+		}},
+	}
+
+	// #27c proses: eksekusi aggregation, hasil $facet selalu satu dokumen
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var output []struct {
+		Data  []AchievementSearchResult `bson:"data"`
+		Total []struct {
+			Count int `bson:"count"`
+		} `bson:"total"`
+	}
+	if err = cursor.All(ctx, &output); err != nil {
+		return nil, 0, err
+	}
+
+	// #27d proses: pindahkan hasil halaman dan total ke return value
+	if len(output) == 0 || len(output[0].Total) == 0 {
+		return []AchievementSearchResult{}, 0, nil
+	}
+	return output[0].Data, output[0].Total[0].Count, nil
+}
+
+// #28 proses: hitung total, facet, dan kandidat urutan sort MongoDB untuk satu batch ID reference dalam satu aggregation $facet. Facet setiap dimensi dihitung tanpa filter dimensinya sendiri supaya pilihan lain di dimensi itu tetap muncul saat multi-select
+func (r *AchievementRepository) GetAchievementFacets(ctx context.Context, filter model.AchievementFilter) (*AchievementFacetResult, error) {
+	result := &AchievementFacetResult{Facets: map[string][]model.FacetCount{}}
+
+	// #28a proses: konversi ID batch, ID hanya dipakai sekali di $match awal
+	objectIDs := achievementObjectIDs(filter.IDs)
+	if len(objectIDs) == 0 {
+		return result, nil
+	}
+
+	// #28b proses: batas tanggal status sertifikasi sama dengan perhitungan certification_status di service
+	today, soonLimit := certificationDateBounds(filter)
+	matchAll := bson.M{"$match": buildAchievementFilterMatch(filter, "", today, soonLimit)}
+	matchExcept := func(dimension string) bson.M {
+		return bson.M{"$match": buildAchievementFilterMatch(filter, dimension, today, soonLimit)}
+	}
+
+	sortByCount := bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}
+	groupBy := func(dimension string, expression interface{}, stages ...bson.M) []bson.M {
+		return append(append([]bson.M{matchExcept(dimension)}, stages...),
+			bson.M{"$group": bson.M{"_id": expression, "count": bson.M{"$sum": 1}}},
+			sortByCount,
+		)
+	}
+
+	facets := bson.M{
+		"matched":                  []bson.M{matchAll, {"$project": bson.M{"_id": 1}}},
+		model.FacetAchievementType: groupBy(model.FacetAchievementType, "$achievementType"),
+		model.FacetCompetitionLevel: groupBy(model.FacetCompetitionLevel, "$details.competitionLevel",
+			bson.M{"$match": bson.M{"details.competitionLevel": bson.M{"$exists": true, "$ne": nil}}},
+		),
+		model.FacetTags:           groupBy(model.FacetTags, "$tags", bson.M{"$unwind": "$tags"}),
+		model.FacetHasAttachments: groupBy(model.FacetHasAttachments, bson.M{"$gt": []interface{}{bson.M{"$size": bson.M{"$ifNull": []interface{}{"$attachments", bson.A{}}}}, 0}}),
+		model.FacetPoints: groupBy(model.FacetPoints, bson.M{"$switch": bson.M{
+			"branches": []bson.M{
+				{"case": bson.M{"$lt": []interface{}{"$points", 10}}, "then": "0-9"},
+				{"case": bson.M{"$lt": []interface{}{"$points", 25}}, "then": "10-24"},
+				{"case": bson.M{"$lt": []interface{}{"$points", 50}}, "then": "25-49"},
+				{"case": bson.M{"$lt": []interface{}{"$points", 100}}, "then": "50-99"},
+			},
+			"default": "100+",
+		}}),
+		model.FacetYear: groupBy(model.FacetYear, bson.M{"$year": "$createdAt"}),
+		model.FacetCertificationStatus: groupBy(model.FacetCertificationStatus, bson.M{"$switch": bson.M{
+			"branches": []bson.M{
+				{"case": bson.M{"$lt": []interface{}{"$details.validUntil", today}}, "then": "expired"},
+				{"case": bson.M{"$lt": []interface{}{"$details.validUntil", soonLimit}}, "then": "expiring_soon"},
+			},
+			"default": "valid",
+		}}, bson.M{"$match": bson.M{"details.validUntil": bson.M{"$exists": true, "$ne": nil}}}),
+	}
+
+	// #28c proses: sort field MongoDB mengambil Limit item teratas batch, cukup field sort dan _id karena service menggabungkan antar batch lalu mengambil dokumen lengkap untuk halaman saja
+	if filter.SortField != "" && filter.Limit > 0 {
+		sortDirection := 1
+		if filter.SortDescending {
+			sortDirection = -1
+		}
+		facets["data"] = []bson.M{
+			matchAll,
+			{"$sort": bson.D{{Key: filter.SortField, Value: sortDirection}, {Key: "_id", Value: sortDirection}}},
+			{"$limit": filter.Limit},
+			{"$project": bson.M{"_id": 1, "points": 1, "title": 1}},
+		}
+	}
+
+	pipeline := []bson.M{
+		{"$match": bson.M{"_id": bson.M{"$in": objectIDs}, "deletedAt": bson.M{"$exists": false}}},
+		{"$facet": facets},
+	}
+
+	// #28d proses: eksekusi aggregation, hasil $facet selalu satu dokumen
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	type facetBucket struct {
+		ID    interface{} `bson:"_id"`
+		Count int         `bson:"count"`
+	}
+	var output []struct {
+		Data    []model.Achievement `bson:"data"`
+		Matched []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		} `bson:"matched"`
+		Facets map[string][]facetBucket `bson:",inline"`
+	}
+	if err = cursor.All(ctx, &output); err != nil {
+		return nil, err
+	}
+	if len(output) == 0 {
+		return result, nil
+	}
+
+	// #28e proses: pindahkan hasil aggregation ke struct result, nilai facet diubah ke string
+	result.Achievements = output[0].Data
+	result.Total = len(output[0].Matched)
+	for _, item := range output[0].Matched {
+		result.MatchedIDs = append(result.MatchedIDs, item.ID.Hex())
+	}
+	for name, buckets := range output[0].Facets {
+		counts := make([]model.FacetCount, 0, len(buckets))
+		for _, bucket := range buckets {
+			if bucket.ID == nil {
+				continue
+			}
+			counts = append(counts, model.FacetCount{Value: fmt.Sprint(bucket.ID), Count: bucket.Count})
+		}
+		result.Facets[name] = counts
+	}
+
+	return result, nil
+}
+
+// #29 proses: susun filter $match untuk daftar achievement, setiap dimensi multi-select memakai $in. Filter milik dimensi exclude tidak dipakai, string kosong berarti semua filter dipakai
+func buildAchievementFilterMatch(filter model.AchievementFilter, exclude string, today, soonLimit time.Time) bson.M {
+	match := bson.M{}
+
+	if len(filter.AchievementTypes) > 0 && exclude != model.FacetAchievementType {
+		match["achievementType"] = bson.M{"$in": filter.AchievementTypes}
+	}
+	if len(filter.CompetitionLevels) > 0 && exclude != model.FacetCompetitionLevel {
+		match["details.competitionLevel"] = bson.M{"$in": filter.CompetitionLevels}
+	}
+	if len(filter.Tags) > 0 && exclude != model.FacetTags {
+		match["tags"] = bson.M{"$in": filter.Tags}
+	}
+
+	// #29a proses: rentang tanggal berdasarkan createdAt dan jadi filter dimensi tahun, DateTo bersifat eksklusif
+	if (filter.DateFrom != nil || filter.DateTo != nil) && exclude != model.FacetYear {
+		createdAt := bson.M{}
+		if filter.DateFrom != nil {
+			createdAt["$gte"] = *filter.DateFrom
+		}
+		if filter.DateTo != nil {
+			createdAt["$lt"] = *filter.DateTo
+		}
+		match["createdAt"] = createdAt
+	}
+
+	if (filter.MinPoints != nil || filter.MaxPoints != nil) && exclude != model.FacetPoints {
+		points := bson.M{}
+		if filter.MinPoints != nil {
+			points["$gte"] = *filter.MinPoints
+		}
+		if filter.MaxPoints != nil {
+			points["$lte"] = *filter.MaxPoints
+		}
+		match["points"] = points
+	}
+
+	if filter.HasAttachments != nil && exclude != model.FacetHasAttachments {
+		if *filter.HasAttachments {
+			match["attachments.0"] = bson.M{"$exists": true}
+		} else {
+			match["attachments.0"] = bson.M{"$exists": false}
+		}
+	}
+
+	// #29b proses: status sertifikasi diterjemahkan ke rentang details.validUntil
+	if len(filter.CertificationStatuses) > 0 && exclude != model.FacetCertificationStatus {
+		var conditions []bson.M
+		for _, status := range filter.CertificationStatuses {
+			switch status {
+			case "expired":
+				conditions = append(conditions, bson.M{"details.validUntil": bson.M{"$lt": today}})
+			case "expiring_soon":
+				conditions = append(conditions, bson.M{"details.validUntil": bson.M{"$gte": today, "$lt": soonLimit}})
+			case "valid":
+				conditions = append(conditions, bson.M{"details.validUntil": bson.M{"$gte": soonLimit}})
+			}
+		}
+		if len(conditions) == 0 {
+			conditions = append(conditions, bson.M{"_id": bson.M{"$exists": false}})
+		}
+		match["$or"] = conditions
+	}
+
+	return match
+}
+
+// #30 proses: filter versi dokumen, dokumen lama yang dibuat sebelum ada field version dianggap versi 0
+func versionFilter(version int) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
+// #31 proses: ambil ID, pemilik, dan status soft delete semua achievement termasuk yang sudah dihapus, dipakai untuk rekonsiliasi dengan reference PostgreSQL
+func (r *AchievementRepository) GetAllAchievementStates(ctx context.Context) ([]AchievementStoreState, error) {
+	// #31a proses: projection hanya field yang dibutuhkan supaya attachment dan details tidak ikut dibaca
+	opts := options.Find().SetProjection(bson.M{
+		"_id":       1,
+		"studentId": 1,
+		"title":     1,
+		"createdAt": 1,
+		"deletedAt": 1,
+	})
+
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	// #31b proses: decode semua hasil ke slice states
+	states := []AchievementStoreState{}
+	if err = cursor.All(ctx, &states); err != nil {
+		return nil, err
+	}
+
+	return states, nil
+}
+
+// #32 proses: ambil semua achievement yang punya attachment dengan URL berawalan prefix, termasuk yang sudah di-soft delete, dipakai untuk migrasi storage
+func (r *AchievementRepository) GetAchievementsByAttachmentURLPrefix(ctx context.Context, prefix string) ([]model.Achievement, error) {
+	// #32a proses: cocokkan prefix URL dengan regex yang sudah di-escape
+	filter := bson.M{
+		"attachments.fileUrl": bson.M{"$regex": "^" + regexp.QuoteMeta(prefix)},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	// #32b proses: decode semua hasil ke slice achievements
+	achievements := []model.Achievement{}
+	if err = cursor.All(ctx, &achievements); err != nil {
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+// #33 proses: ganti URL file attachment setelah file dipindah ke storage lain, versi tidak naik karena isi prestasi tidak berubah. Return false jika URL lama sudah tidak ada
+func (r *AchievementRepository) UpdateAttachmentFileURL(ctx context.Context, id string, oldURL string, newURL string) (bool, error) {
+	// #33a proses: convert string ID jadi ObjectID
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	// #33b proses: update elemen attachment dengan URL lama memakai arrayFilters supaya attachment lain tidak tersentuh
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{
+		Filters: []interface{}{bson.M{"attachment.fileUrl": oldURL}},
+	})
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":                 objectID,
+			"attachments.fileUrl": oldURL,
+		},
+		bson.M{
+			"$set": bson.M{"attachments.$[attachment].fileUrl": newURL},
+		},
+		opts,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// #34 proses: ambil achievement yang punya attachment dengan status scan tertentu, termasuk prestasi di tempat sampah karena bisa direstore
+func (r *AchievementRepository) GetAchievementsByAttachmentScanStatus(ctx context.Context, status string, limit int) ([]model.Achievement, error) {
+	// #34a proses: urutkan dari yang paling lama diupdate supaya upload yang lebih dulu dipindai lebih dulu
+	opts := options.Find().SetSort(bson.M{"updatedAt": 1})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"attachments.scanStatus": status}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	// #34b proses: decode semua hasil ke slice achievements
+	achievements := []model.Achievement{}
+	if err = cursor.All(ctx, &achievements); err != nil {
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+// #35 proses: simpan hasil scan attachment yang masih pending, newURL diisi jika file dipindah ke karantina. Return false jika attachment sudah dihapus atau sudah dipindai
+func (r *AchievementRepository) UpdateAttachmentScanResult(ctx context.Context, id string, fileURL string, status string, signature string, newURL string) (bool, error) {
+	// #35a proses: convert string ID jadi ObjectID
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	// #35b proses: siapkan field yang diupdate, versi tidak naik karena isi prestasi tidak diubah mahasiswa
+	set := bson.M{
+		"attachments.$[attachment].scanStatus": status,
+		"attachments.$[attachment].scannedAt":  time.Now(),
+	}
+	if signature != "" {
+		set["attachments.$[attachment].scanSignature"] = signature
+	}
+	if newURL != "" {
+		set["attachments.$[attachment].fileUrl"] = newURL
+	}
+
+	// #35c proses: hanya attachment dengan URL ini yang masih pending yang diupdate
+	pending := bson.M{"fileUrl": fileURL, "scanStatus": model.AttachmentScanPending}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{
+		Filters: []interface{}{bson.M{"attachment.fileUrl": fileURL, "attachment.scanStatus": model.AttachmentScanPending}},
+	})
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":         objectID,
+			"attachments": bson.M{"$elemMatch": pending},
+		},
+		bson.M{"$set": set},
+		opts,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount > 0, nil
+}
+
+// #36 proses: hapus attachment berdasarkan ID attachment, versi ikut naik karena isi prestasi berubah. Return nil jika attachment sudah tidak ada
+func (r *AchievementRepository) RemoveAttachment(ctx context.Context, id string, attachmentID string) (*model.Achievement, error) {
+	// #36a proses: convert string ID jadi ObjectID
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// #36b proses: $pull attachment dengan ID tersebut dari prestasi yang belum dihapus
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":            objectID,
+			"deletedAt":      bson.M{"$exists": false},
+			"attachments.id": attachmentID,
+		},
+		bson.M{
+			"$pull": bson.M{"attachments": bson.M{"id": attachmentID}},
+			"$set":  bson.M{"updatedAt": time.Now()},
+			"$inc":  bson.M{"version": 1},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, nil
+	}
+
+	// #36c proses: ambil achievement yang sudah diupdate untuk return
+	return r.GetAchievementByID(ctx, id)
+}
+
+// #37 proses: ganti attachment dengan ID yang sama, versi ikut naik karena isi prestasi berubah. Return nil jika attachment sudah tidak ada
+func (r *AchievementRepository) ReplaceAttachment(ctx context.Context, id string, attachmentID string, attachment model.Attachment) (*model.Achievement, error) {
+	// #37a proses: convert string ID jadi ObjectID
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// #37b proses: timpa elemen attachment yang cocok memakai positional operator, ID attachment dipertahankan
+	attachment.ID = attachmentID
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{
+			"_id":            objectID,
+			"deletedAt":      bson.M{"$exists": false},
+			"attachments.id": attachmentID,
+		},
+		bson.M{
+			"$set": bson.M{"attachments.$": attachment, "updatedAt": time.Now()},
+			"$inc": bson.M{"version": 1},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, nil
+	}
+
+	// #37c proses: ambil achievement yang sudah diupdate untuk return
+	return r.GetAchievementByID(ctx, id)
+}
+
+// #38 proses: hitung prestasi yang masih mereferensikan URL file, termasuk prestasi di tempat sampah karena bisa direstore
+func (r *AchievementRepository) CountAttachmentFileReferences(ctx context.Context, fileURL string) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{"attachments.fileUrl": fileURL})
+}
+
+// #39 proses: jumlahkan ukuran attachment di prestasi mahasiswa yang belum dihapus, dipakai untuk cek kuota storage. Size hanya ditulis server saat upload sehingga tidak bisa dikecilkan lewat update prestasi
+func (r *AchievementRepository) GetStudentAttachmentUsage(ctx context.Context, studentID string) (int64, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{"studentId": studentID, "deletedAt": bson.M{"$exists": false}}},
+		{"$unwind": "$attachments"},
+		{"$group": bson.M{"_id": nil, "bytes": bson.M{"$sum": "$attachments.size"}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Bytes int64 `bson:"bytes"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+	return results[0].Bytes, nil
+}
+
+// #40 proses: pemakaian storage attachment per mahasiswa dari prestasi yang belum dihapus, diurutkan dari yang terbesar
+func (r *AchievementRepository) GetAttachmentUsageByStudent(ctx context.Context) ([]StudentAttachmentUsage, error) {
+	pipeline := []bson.M{
+		{"$match": bson.M{"deletedAt": bson.M{"$exists": false}}},
+		{"$unwind": "$attachments"},
+		{
+			"$group": bson.M{
+				"_id":   "$studentId",
+				"files": bson.M{"$sum": 1},
+				"bytes": bson.M{"$sum": "$attachments.size"},
+			},
+		},
+		{"$sort": bson.D{{Key: "bytes", Value: -1}, {Key: "_id", Value: 1}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []StudentAttachmentUsage{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// #41 proses: total attachment dan file unik di storage termasuk prestasi di tempat sampah, file dengan URL sama dihitung sekali untuk StoredBytes
+func (r *AchievementRepository) GetAttachmentStorageTotals(ctx context.Context) (*AttachmentStorageTotals, error) {
+	pipeline := []bson.M{
+		{"$unwind": "$attachments"},
+		{
+			"$group": bson.M{
+				"_id":        "$attachments.fileUrl",
+				"size":       bson.M{"$max": "$attachments.size"},
+				"references": bson.M{"$sum": 1},
+			},
+		},
+		{
+			"$group": bson.M{
+				"_id":          nil,
+				"attachments":  bson.M{"$sum": "$references"},
+				"uniqueFiles":  bson.M{"$sum": 1},
+				"logicalBytes": bson.M{"$sum": bson.M{"$multiply": bson.A{bson.M{"$ifNull": bson.A{"$size", 0}}, "$references"}}},
+				"storedBytes":  bson.M{"$sum": "$size"},
+			},
+		},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []AttachmentStorageTotals
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return &AttachmentStorageTotals{}, nil
+	}
+	return &results[0], nil
+}
+
+// #42 proses: tambah verifikasi satu dosen wali prestasi tim secara atomik. Filter memastikan dosen wali belum pernah memverifikasi dan completes sesuai keadaan dokumen: true hanya jika semua dosen wali lain sudah memverifikasi, false hanya jika masih ada yang belum. Return false jika kondisi tidak terpenuhi sehingga service membaca ulang dokumen
+func (r *AchievementRepository) AddTeamVerification(ctx context.Context, id string, verification model.TeamVerification, otherAdvisorIDs []string, completes bool) (bool, error) {
+	// #42a proses: convert string ID jadi ObjectID
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	// #42b proses: susun filter, $all dengan list kosong tidak cocok dengan dokumen apa pun sehingga hanya dipakai jika ada dosen wali lain
+	advisorFilter := bson.M{"$ne": verification.AdvisorID}
+	filter := bson.M{"_id": objectID, "deletedAt": bson.M{"$exists": false}}
+	if completes && len(otherAdvisorIDs) > 0 {
+		advisorFilter["$all"] = otherAdvisorIDs
+	} else if !completes {
+		filter["$nor"] = bson.A{bson.M{"teamVerifications.advisorId": bson.M{"$all": otherAdvisorIDs}}}
+	}
+	filter["teamVerifications.advisorId"] = advisorFilter
+
+	// #42c proses: $push hanya terjadi jika filter cocok, dua dosen wali yang memverifikasi bersamaan tidak saling menimpa
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$push": bson.M{"teamVerifications": verification}})
+	if err != nil {
+		return false, err
+	}
+
+	return result.ModifiedCount > 0, nil
+}
+
+// #43 proses: hapus verifikasi satu dosen wali prestasi tim, dipakai sebagai kompensasi saga verifikasi
+func (r *AchievementRepository) RemoveTeamVerification(ctx context.Context, id string, advisorID string) error {
+	// #43a proses: convert string ID jadi ObjectID
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	// #43b proses: $pull verifikasi milik dosen wali ini saja, verifikasi dosen wali lain tidak tersentuh
+	_, err = r.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$pull": bson.M{"teamVerifications": bson.M{"advisorId": advisorID}}})
+	return err
+}
+
+// #44 proses: catat reservasi kuota storage mahasiswa untuk file yang sedang disimpan, return ID reservasi
+func (r *AchievementRepository) ReserveAttachmentQuota(ctx context.Context, studentID string, size int64, expiresAt time.Time) (string, error) {
+	reservation := model.AttachmentQuotaReservation{
+		StudentID: studentID,
+		Size:      size,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+
+	result, err := r.reservations.InsertOne(ctx, reservation)
+	if err != nil {
+		return "", err
+	}
+	return result.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+// #45 proses: jumlahkan reservasi kuota mahasiswa yang belum kedaluwarsa selain reservasi excludeID, reservasi yang kedaluwarsa diabaikan walaupun belum dihapus TTL index
+func (r *AchievementRepository) GetStudentReservedAttachmentQuota(ctx context.Context, studentID string, excludeID string, now time.Time) (int64, error) {
+	match := bson.M{"studentId": studentID, "expiresAt": bson.M{"$gt": now}}
+	if objectID, err := primitive.ObjectIDFromHex(excludeID); err == nil {
+		match["_id"] = bson.M{"$ne": objectID}
+	}
+
+	pipeline := []bson.M{
+		{"$match": match},
+		{"$group": bson.M{"_id": nil, "bytes": bson.M{"$sum": "$size"}}},
+	}
+
+	cursor, err := r.reservations.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Bytes int64 `bson:"bytes"`
+	}
+	if err := cursor.All(ctx, &results); err != nil {
+		return 0, err
+	}
+	if len(results) == 0 {
+		return 0, nil
+	}
+	return results[0].Bytes, nil
+}
+
+// #46 proses: hapus reservasi kuota, ID yang tidak valid atau sudah dihapus tidak dianggap error
+func (r *AchievementRepository) ReleaseAttachmentQuota(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil
+	}
+
+	_, err = r.reservations.DeleteOne(ctx, bson.M{"_id": objectID})
+	return err
+}
+
+// #47 proses: ambil ID dari satu batch reference yang lolos semua filter MongoDB, hanya _id yang dibaca dan urutan tidak dijamin sehingga pemanggil mengurutkan ulang sesuai batch
+func (r *AchievementRepository) GetMatchingAchievementIDs(ctx context.Context, filter model.AchievementFilter) ([]string, error) {
+	objectIDs := achievementObjectIDs(filter.IDs)
+	if len(objectIDs) == 0 {
+		return []string{}, nil
+	}
+
+	today, soonLimit := certificationDateBounds(filter)
+	match := buildAchievementFilterMatch(filter, "", today, soonLimit)
+	match["_id"] = bson.M{"$in": objectIDs}
+	match["deletedAt"] = bson.M{"$exists": false}
+
+	cursor, err := r.collection.Find(ctx, match, options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var documents []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err = cursor.All(ctx, &documents); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(documents))
+	for _, document := range documents {
+		ids = append(ids, document.ID.Hex())
+	}
+	return ids, nil
+}
+
+// #48 proses: konversi list ID string ke ObjectID, ID yang tidak valid dilewati
+func achievementObjectIDs(ids []string) []primitive.ObjectID {
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+	return objectIDs
+}
+
+// #49 proses: batas hari ini dan batas expiring_soon dalam UTC, sama dengan perhitungan certification_status di service
+func certificationDateBounds(filter model.AchievementFilter) (time.Time, time.Time) {
+	now := filter.Now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return today, today.AddDate(0, 0, filter.CertificationSoonDays+1)
+}
@@ -0,0 +1,119 @@
+package repository
+
+// #1 proses: import library yang diperlukan untuk MongoDB, context, dan time
+import (
+	"context"
+	"time"
+
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// #2 proses: definisikan interface untuk operasi database tipe prestasi di MongoDB
+type IAchievementTypeRepository interface {
+	CreateAchievementType(ctx context.Context, achievementType *model.AchievementTypeDefinition) (*model.AchievementTypeDefinition, error)
+	GetAchievementTypeByCode(ctx context.Context, code string) (*model.AchievementTypeDefinition, error)
+	GetAllAchievementTypes(ctx context.Context, includeInactive bool) ([]model.AchievementTypeDefinition, error)
+	UpdateAchievementType(ctx context.Context, code string, req model.UpdateAchievementTypeRequest) (*model.AchievementTypeDefinition, error)
+}
+
+// #3 proses: struct repository untuk operasi database tipe prestasi di MongoDB
+type AchievementTypeRepository struct {
+	collection *mongo.Collection
+}
+
+// #4 proses: constructor untuk membuat instance AchievementTypeRepository baru
+func NewAchievementTypeRepository(db *mongo.Database) IAchievementTypeRepository {
+	return &AchievementTypeRepository{
+		collection: db.Collection("achievement_types"),
+	}
+}
+
+// #5 proses: buat tipe prestasi baru di MongoDB
+func (r *AchievementTypeRepository) CreateAchievementType(ctx context.Context, achievementType *model.AchievementTypeDefinition) (*model.AchievementTypeDefinition, error) {
+	// #5a proses: set timestamp sebelum insert
+	achievementType.CreatedAt = time.Now()
+	achievementType.UpdatedAt = time.Now()
+
+	// #5b proses: insert ke collection, index unik pada code mencegah kode ganda
+	if _, err := r.collection.InsertOne(ctx, achievementType); err != nil {
+		return nil, err
+	}
+
+	return r.GetAchievementTypeByCode(ctx, achievementType.Code)
+}
+
+// #6 proses: ambil tipe prestasi berdasarkan kode, return nil jika tidak ada
+func (r *AchievementTypeRepository) GetAchievementTypeByCode(ctx context.Context, code string) (*model.AchievementTypeDefinition, error) {
+	var achievementType model.AchievementTypeDefinition
+	err := r.collection.FindOne(ctx, bson.M{"code": code}).Decode(&achievementType)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &achievementType, nil
+}
+
+// #7 proses: ambil semua tipe prestasi, urut berdasarkan tipe bawaan lalu kode
+func (r *AchievementTypeRepository) GetAllAchievementTypes(ctx context.Context, includeInactive bool) ([]model.AchievementTypeDefinition, error) {
+	// #7a proses: filter hanya tipe aktif kecuali diminta semua
+	filter := bson.M{}
+	if !includeInactive {
+		filter["isActive"] = true
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "isBuiltIn", Value: -1}, {Key: "code", Value: 1}})
+	cursor, err := r.collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	// #7b proses: decode semua hasil ke slice
+	achievementTypes := []model.AchievementTypeDefinition{}
+	if err := cursor.All(ctx, &achievementTypes); err != nil {
+		return nil, err
+	}
+
+	return achievementTypes, nil
+}
+
+// #8 proses: update tipe prestasi berdasarkan kode, partial update
+func (r *AchievementTypeRepository) UpdateAchievementType(ctx context.Context, code string, req model.UpdateAchievementTypeRequest) (*model.AchievementTypeDefinition, error) {
+	// #8a proses: buat update document, mulai dari updatedAt
+	update := bson.M{
+		"updatedAt": time.Now(),
+	}
+
+	// #8b proses: tambahkan field ke update document jika ada nilainya
+	if req.Name != "" {
+		update["name"] = req.Name
+	}
+	if req.Description != nil {
+		update["description"] = *req.Description
+	}
+	if req.CustomFields != nil {
+		update["customFields"] = req.CustomFields
+	}
+	if req.IsActive != nil {
+		update["isActive"] = *req.IsActive
+	}
+
+	// #8c proses: update document dan cek apakah tipe prestasi ditemukan
+	result, err := r.collection.UpdateOne(ctx, bson.M{"code": code}, bson.M{"$set": update})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.MatchedCount == 0 {
+		return nil, nil
+	}
+
+	return r.GetAchievementTypeByCode(ctx, code)
+}
@@ -0,0 +1,123 @@
+package repository
+
+// #1 proses: import library yang diperlukan untuk MongoDB, context, dan time
+import (
+	"context"
+	"time"
+
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// #2 proses: definisikan interface untuk operasi database sesi upload bertahap di MongoDB
+type IAttachmentUploadRepository interface {
+	CreateUpload(ctx context.Context, upload *model.AttachmentUpload) (*model.AttachmentUpload, error)
+	GetUploadByID(ctx context.Context, id string) (*model.AttachmentUpload, error)
+	CountActiveUploads(ctx context.Context, studentID string, now time.Time) (int64, error)
+	AdvanceUploadOffset(ctx context.Context, id string, expectedOffset int64, newOffset int64, expiresAt time.Time) (bool, error)
+	DeleteUpload(ctx context.Context, id string) error
+	GetExpiredUploads(ctx context.Context, now time.Time, limit int) ([]model.AttachmentUpload, error)
+}
+
+// #3 proses: struct repository untuk operasi database sesi upload bertahap di MongoDB
+type AttachmentUploadRepository struct {
+	collection *mongo.Collection
+}
+
+// #4 proses: constructor untuk membuat instance AttachmentUploadRepository baru
+func NewAttachmentUploadRepository(db *mongo.Database) IAttachmentUploadRepository {
+	return &AttachmentUploadRepository{
+		collection: db.Collection("attachment_uploads"),
+	}
+}
+
+// #5 proses: buat sesi upload baru, offset mulai dari 0
+func (r *AttachmentUploadRepository) CreateUpload(ctx context.Context, upload *model.AttachmentUpload) (*model.AttachmentUpload, error) {
+	upload.ID = primitive.NilObjectID
+	upload.Offset = 0
+	upload.CreatedAt = time.Now()
+	upload.UpdatedAt = upload.CreatedAt
+
+	result, err := r.collection.InsertOne(ctx, upload)
+	if err != nil {
+		return nil, err
+	}
+
+	upload.ID = result.InsertedID.(primitive.ObjectID)
+	return upload, nil
+}
+
+// #6 proses: ambil sesi upload berdasarkan ID, return nil jika tidak ada atau ID bukan ObjectID karena ID berasal dari URL
+func (r *AttachmentUploadRepository) GetUploadByID(ctx context.Context, id string) (*model.AttachmentUpload, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, nil
+	}
+
+	var upload model.AttachmentUpload
+	if err := r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&upload); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &upload, nil
+}
+
+// #7 proses: hitung sesi upload mahasiswa yang belum kedaluwarsa
+func (r *AttachmentUploadRepository) CountActiveUploads(ctx context.Context, studentID string, now time.Time) (int64, error) {
+	return r.collection.CountDocuments(ctx, bson.M{
+		"studentId": studentID,
+		"expiresAt": bson.M{"$gt": now},
+	})
+}
+
+// #8 proses: majukan offset setelah potongan tersimpan, filter offset membuat potongan yang dikirim dua kali bersamaan hanya tercatat sekali. Return false jika offset sudah berubah
+func (r *AttachmentUploadRepository) AdvanceUploadOffset(ctx context.Context, id string, expectedOffset int64, newOffset int64, expiresAt time.Time) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	result, err := r.collection.UpdateOne(
+		ctx,
+		bson.M{"_id": objectID, "offset": expectedOffset},
+		bson.M{"$set": bson.M{"offset": newOffset, "expiresAt": expiresAt, "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// #9 proses: hapus sesi upload, potongan file di storage dihapus oleh service
+func (r *AttachmentUploadRepository) DeleteUpload(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	return err
+}
+
+// #10 proses: ambil sesi upload yang sudah kedaluwarsa untuk dibersihkan, urut dari yang paling lama
+func (r *AttachmentUploadRepository) GetExpiredUploads(ctx context.Context, now time.Time, limit int) ([]model.AttachmentUpload, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "expiresAt", Value: 1}}).SetLimit(int64(limit))
+	cursor, err := r.collection.Find(ctx, bson.M{"expiresAt": bson.M{"$lte": now}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	uploads := []model.AttachmentUpload{}
+	if err := cursor.All(ctx, &uploads); err != nil {
+		return nil, err
+	}
+	return uploads, nil
+}
@@ -4,8 +4,12 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	"strings"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // #2 proses: definisikan interface untuk operasi database achievement reference
@@ -21,11 +25,29 @@ type IAchievementReferenceRepository interface {
 	GetAchievementReferenceByStudentIDPaginated(ctx context.Context, studentID string, page, limit int) ([]model.AchievementReference, int, error)
 	GetAchievementReferencesByAdvisorIDPaginated(ctx context.Context, advisorID string, page, limit int) ([]model.AchievementReference, int, error)
 	GetAllAchievementReferencesPaginated(ctx context.Context, page, limit int, statusFilter string, sortBy string, sortOrder string) ([]model.AchievementReference, int, error)
-	UpdateAchievementReferenceVerify(ctx context.Context, id string, verifiedBy string) error
-	UpdateAchievementReferenceReject(ctx context.Context, id string, verifiedBy string, rejectionNote string) error
+	UpdateAchievementReferenceVerify(ctx context.Context, id string, verifiedBy string, expectedVersion int) (bool, error)
+	UpdateAchievementReferenceReject(ctx context.Context, id string, verifiedBy string, rejectionNote string, expectedVersion int) (bool, error)
 	GetAchievementStats(ctx context.Context) (int, int, error)
 	GetAchievementsByPeriod(ctx context.Context, startDate, endDate time.Time) (map[string]int, error)
 	GetAllAchievementMongoIDs(ctx context.Context) ([]string, error)
+	GetDeletedAchievementMongoIDs(ctx context.Context) ([]string, error)
+	CreateAchievementHistory(ctx context.Context, req model.CreateAchievementHistoryRequest) error
+	GetAchievementHistoryByRefID(ctx context.Context, achievementRefID string) ([]model.AchievementHistory, error)
+	WithdrawAchievementReference(ctx context.Context, id string) (bool, error)
+	GetSubmittedReferencesBefore(ctx context.Context, before time.Time) ([]model.AchievementReference, error)
+	SoftDeleteAchievementReference(ctx context.Context, id string) error
+	GetDeletedAchievementReferenceByMongoID(ctx context.Context, mongoID string) (*model.DeletedAchievementReference, error)
+	RestoreAchievementReference(ctx context.Context, id string) (string, error)
+	GetDeletedAchievementReferencesPaginated(ctx context.Context, page, limit int) ([]model.DeletedAchievementReference, int, error)
+	GetDeletedReferencesBefore(ctx context.Context, before time.Time) ([]model.DeletedAchievementReference, error)
+	PurgeAchievementReference(ctx context.Context, id string) (bool, error)
+	ClaimCertificationExpiryAlert(ctx context.Context, achievementRefID string, validUntil time.Time, daysBefore int) (bool, error)
+	ReleaseCertificationExpiryAlert(ctx context.Context, achievementRefID string, validUntil time.Time, daysBefore int) error
+	ClaimSLANotification(ctx context.Context, achievementRefID string, submittedAt time.Time, action string) (bool, error)
+	ReleaseSLANotification(ctx context.Context, achievementRefID string, submittedAt time.Time, action string) error
+	GetAchievementListRows(ctx context.Context, filter model.AchievementListFilter) ([]model.AchievementListRow, error)
+	GetAchievementReferencesByMongoIDs(ctx context.Context, mongoIDs []string) ([]model.AchievementReference, error)
+	GetAchievementFacetRows(ctx context.Context, filter model.AchievementListFilter, after *model.PageCursor, limit int) ([]model.AchievementListRow, error)
 }
 
 // #3 proses: struct repository untuk operasi database achievement reference
@@ -45,7 +67,7 @@ func (r *AchievementReferenceRepository) CreateAchievementReference(ctx context.
 		INSERT INTO achievement_references (student_id, mongo_achievement_id, status, created_at, updated_at)
 		VALUES ($1, $2, $3, NOW(), NOW())
 		RETURNING id, student_id, mongo_achievement_id, status, submitted_at, 
-		          verified_at, verified_by, rejection_note, created_at, updated_at
+		          verified_at, verified_by, rejection_note, version, created_at, updated_at
 	`
 
 	// #5b proses: eksekusi query dan scan hasil ke struct ref
@@ -53,7 +75,7 @@ func (r *AchievementReferenceRepository) CreateAchievementReference(ctx context.
 	err := r.db.QueryRowContext(ctx, query, req.StudentID, req.MongoAchievementID, req.Status).Scan(
 		&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status,
 		&ref.SubmittedAt, &ref.VerifiedAt, &ref.VerifiedBy, &ref.RejectionNote,
-		&ref.CreatedAt, &ref.UpdatedAt,
+		&ref.Version, &ref.CreatedAt, &ref.UpdatedAt,
 	)
 
 	if err != nil {
@@ -68,7 +90,7 @@ func (r *AchievementReferenceRepository) GetAchievementReferenceByMongoID(ctx co
 	// #6a proses: query untuk ambil achievement reference, filter yang status bukan deleted
 	query := `
 		SELECT id, student_id, mongo_achievement_id, status, submitted_at,
-		       verified_at, verified_by, rejection_note, created_at, updated_at
+		       verified_at, verified_by, rejection_note, version, created_at, updated_at
 		FROM achievement_references
 		WHERE mongo_achievement_id = $1 AND status != 'deleted'
 	`
@@ -78,7 +100,7 @@ func (r *AchievementReferenceRepository) GetAchievementReferenceByMongoID(ctx co
 	err := r.db.QueryRowContext(ctx, query, mongoID).Scan(
 		&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status,
 		&ref.SubmittedAt, &ref.VerifiedAt, &ref.VerifiedBy, &ref.RejectionNote,
-		&ref.CreatedAt, &ref.UpdatedAt,
+		&ref.Version, &ref.CreatedAt, &ref.UpdatedAt,
 	)
 
 	if err != nil {
@@ -93,7 +115,7 @@ func (r *AchievementReferenceRepository) GetAchievementReferenceByID(ctx context
 	// #7a proses: query untuk ambil achievement reference berdasarkan ID
 	query := `
 		SELECT id, student_id, mongo_achievement_id, status, submitted_at,
-		       verified_at, verified_by, rejection_note, created_at, updated_at
+		       verified_at, verified_by, rejection_note, version, created_at, updated_at
 		FROM achievement_references
 		WHERE id = $1
 	`
@@ -103,7 +125,7 @@ func (r *AchievementReferenceRepository) GetAchievementReferenceByID(ctx context
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status,
 		&ref.SubmittedAt, &ref.VerifiedAt, &ref.VerifiedBy, &ref.RejectionNote,
-		&ref.CreatedAt, &ref.UpdatedAt,
+		&ref.Version, &ref.CreatedAt, &ref.UpdatedAt,
 	)
 
 	if err != nil {
@@ -113,7 +135,7 @@ func (r *AchievementReferenceRepository) GetAchievementReferenceByID(ctx context
 	return ref, nil
 }
 
-// #8 proses: update status achievement reference, bisa sekaligus set submitted_at
+// #8 proses: update status achievement reference, bisa sekaligus set submitted_at. Setiap perubahan status menaikkan version
 func (r *AchievementReferenceRepository) UpdateAchievementReferenceStatus(ctx context.Context, id string, status string, submittedAt *time.Time) error {
 	// #8a proses: cek apakah submitted_at perlu diupdate juga
 	var query string
@@ -123,7 +145,7 @@ func (r *AchievementReferenceRepository) UpdateAchievementReferenceStatus(ctx co
 		// #8b proses: query untuk update status dan submitted_at
 		query = `
 			UPDATE achievement_references
-			SET status = $1, submitted_at = $2, updated_at = NOW()
+			SET status = $1, submitted_at = $2, version = version + 1, updated_at = NOW()
 			WHERE id = $3
 		`
 		_, err = r.db.ExecContext(ctx, query, status, submittedAt, id)
@@ -131,7 +153,7 @@ func (r *AchievementReferenceRepository) UpdateAchievementReferenceStatus(ctx co
 		// #8c proses: query untuk update status saja
 		query = `
 			UPDATE achievement_references
-			SET status = $1, updated_at = NOW()
+			SET status = $1, version = version + 1, updated_at = NOW()
 			WHERE id = $2
 		`
 		_, err = r.db.ExecContext(ctx, query, status, id)
@@ -489,28 +511,48 @@ func (r *AchievementReferenceRepository) GetAllAchievementReferencesPaginated(ct
 	return references, total, nil
 }
 
-// #16 proses: update status achievement reference jadi verified
-func (r *AchievementReferenceRepository) UpdateAchievementReferenceVerify(ctx context.Context, id string, verifiedBy string) error {
-	// #16a proses: query untuk update status jadi verified dan set verified_by serta verified_at
+// #16 proses: update status achievement reference jadi verified, hanya jika version masih sama dengan expectedVersion
+func (r *AchievementReferenceRepository) UpdateAchievementReferenceVerify(ctx context.Context, id string, verifiedBy string, expectedVersion int) (bool, error) {
+	// #16a proses: query update bersyarat untuk set status verified, verified_by, verified_at, dan naikkan version
 	query := `
 		UPDATE achievement_references
-		SET status = $1, verified_by = $2, verified_at = NOW(), updated_at = NOW()
-		WHERE id = $3
+		SET status = $1, verified_by = $2, verified_at = NOW(), version = version + 1, updated_at = NOW()
+		WHERE id = $3 AND version = $4
 	`
-	_, err := r.db.ExecContext(ctx, query, model.AchievementStatusVerified, verifiedBy, id)
-	return err
+	result, err := r.db.ExecContext(ctx, query, model.AchievementStatusVerified, verifiedBy, id, expectedVersion)
+	if err != nil {
+		return false, err
+	}
+
+	// #16b proses: tidak ada baris yang terupdate berarti reference sudah diubah request lain
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
 }
 
-// #17 proses: update status achievement reference jadi rejected dengan catatan penolakan
-func (r *AchievementReferenceRepository) UpdateAchievementReferenceReject(ctx context.Context, id string, verifiedBy string, rejectionNote string) error {
-	// #17a proses: query untuk update status jadi rejected dan set verified_by serta rejection_note
+// #17 proses: update status achievement reference jadi rejected dengan catatan penolakan, hanya jika version masih sama dengan expectedVersion
+func (r *AchievementReferenceRepository) UpdateAchievementReferenceReject(ctx context.Context, id string, verifiedBy string, rejectionNote string, expectedVersion int) (bool, error) {
+	// #17a proses: query update bersyarat untuk set status rejected, verified_by, rejection_note, dan naikkan version
 	query := `
 		UPDATE achievement_references
-		SET status = $1, verified_by = $2, rejection_note = $3, updated_at = NOW()
-		WHERE id = $4
+		SET status = $1, verified_by = $2, rejection_note = $3, version = version + 1, updated_at = NOW()
+		WHERE id = $4 AND version = $5
 	`
-	_, err := r.db.ExecContext(ctx, query, model.AchievementStatusRejected, verifiedBy, rejectionNote, id)
-	return err
+	result, err := r.db.ExecContext(ctx, query, model.AchievementStatusRejected, verifiedBy, rejectionNote, id, expectedVersion)
+	if err != nil {
+		return false, err
+	}
+
+	// #17b proses: tidak ada baris yang terupdate berarti reference sudah diubah request lain
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
 }
 
 // #18 proses: ambil statistik achievement, hitung total dan yang sudah verified
@@ -603,3 +645,591 @@ func (r *AchievementReferenceRepository) GetAllAchievementMongoIDs(ctx context.C
 
 	return mongoIDs, nil
 }
+
+// #21 proses: catat aksi pada history prestasi
+func (r *AchievementReferenceRepository) CreateAchievementHistory(ctx context.Context, req model.CreateAchievementHistoryRequest) error {
+	// #21a proses: query untuk insert history baru
+	query := `
+		INSERT INTO achievement_history (achievement_ref_id, action, status, changed_by, note, created_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+	`
+	_, err := r.db.ExecContext(ctx, query, req.AchievementRefID, req.Action, req.Status, req.ChangedBy, req.Note)
+	return err
+}
+
+// #22 proses: ambil semua history prestasi berdasarkan achievement reference ID, urut dari yang paling lama
+func (r *AchievementReferenceRepository) GetAchievementHistoryByRefID(ctx context.Context, achievementRefID string) ([]model.AchievementHistory, error) {
+	// #22a proses: query untuk ambil history berdasarkan achievement_ref_id
+	query := `
+		SELECT id, achievement_ref_id, action, status, changed_by, note, created_at
+		FROM achievement_history
+		WHERE achievement_ref_id = $1
+		ORDER BY created_at ASC
+	`
+
+	// #22b proses: eksekusi query dan ambil semua baris hasil
+	rows, err := r.db.QueryContext(ctx, query, achievementRefID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// #22c proses: loop semua hasil dan masukkan ke slice history
+	var history []model.AchievementHistory
+	for rows.Next() {
+		var entry model.AchievementHistory
+		err := rows.Scan(
+			&entry.ID, &entry.AchievementRefID, &entry.Action, &entry.Status,
+			&entry.ChangedBy, &entry.Note, &entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+// #23 proses: kembalikan prestasi submitted ke draft, hanya jika belum ada review sejak submit terakhir
+func (r *AchievementReferenceRepository) WithdrawAchievementReference(ctx context.Context, id string) (bool, error) {
+	// #23a proses: query update bersyarat supaya withdraw tidak balapan dengan dosen wali yang mulai review
+	query := `
+		UPDATE achievement_references ar
+		SET status = $1, version = version + 1, updated_at = NOW()
+		WHERE ar.id = $2 AND ar.status = $3
+		  AND NOT EXISTS (
+		      SELECT 1 FROM achievement_history h
+		      WHERE h.achievement_ref_id = ar.id
+		        AND h.action = $4
+		        AND h.created_at >= ar.submitted_at
+		  )
+	`
+
+	result, err := r.db.ExecContext(ctx, query,
+		model.AchievementStatusDraft, id, model.AchievementStatusSubmitted, model.AchievementHistoryActionReviewStarted,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	// #23b proses: tidak ada baris yang terupdate berarti status sudah berubah atau review sudah dimulai
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// #24 proses: ambil semua prestasi yang masih submitted dan di-submit sebelum batas waktu tertentu, urut dari yang paling lama menunggu
+func (r *AchievementReferenceRepository) GetSubmittedReferencesBefore(ctx context.Context, before time.Time) ([]model.AchievementReference, error) {
+	// #24a proses: query untuk ambil reference submitted yang submitted_at-nya sudah lewat batas
+	query := `
+		SELECT id, student_id, mongo_achievement_id, status, submitted_at,
+		       verified_at, verified_by, rejection_note, created_at, updated_at
+		FROM achievement_references
+		WHERE status = $1 AND submitted_at IS NOT NULL AND submitted_at <= $2
+		ORDER BY submitted_at ASC
+	`
+
+	// #24b proses: eksekusi query dan ambil semua baris hasil
+	rows, err := r.db.QueryContext(ctx, query, model.AchievementStatusSubmitted, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// #24c proses: loop semua hasil dan masukkan ke slice refs
+	var refs []model.AchievementReference
+	for rows.Next() {
+		var ref model.AchievementReference
+		err := rows.Scan(
+			&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status,
+			&ref.SubmittedAt, &ref.VerifiedAt, &ref.VerifiedBy, &ref.RejectionNote,
+			&ref.CreatedAt, &ref.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
+
+// #25 proses: soft delete achievement reference, simpan status sebelum dihapus supaya bisa di-restore
+func (r *AchievementReferenceRepository) SoftDeleteAchievementReference(ctx context.Context, id string) error {
+	// #25a proses: query untuk set status deleted beserta deleted_at dan status_before_delete
+	query := `
+		UPDATE achievement_references
+		SET status_before_delete = status, status = 'deleted', deleted_at = NOW(), version = version + 1, updated_at = NOW()
+		WHERE id = $1 AND status != 'deleted'
+	`
+
+	// #25b proses: eksekusi query dan cek apakah ada baris yang terupdate
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// #26 proses: ambil achievement reference yang sudah dihapus berdasarkan mongo achievement ID
+func (r *AchievementReferenceRepository) GetDeletedAchievementReferenceByMongoID(ctx context.Context, mongoID string) (*model.DeletedAchievementReference, error) {
+	// #26a proses: query untuk ambil reference deleted, data lama tanpa deleted_at memakai updated_at
+	query := `
+		SELECT id, student_id, mongo_achievement_id, status, submitted_at,
+		       verified_at, verified_by, rejection_note, created_at, updated_at,
+		       COALESCE(deleted_at, updated_at), COALESCE(status_before_delete::text, 'draft')
+		FROM achievement_references
+		WHERE mongo_achievement_id = $1 AND status = 'deleted'
+	`
+
+	// #26b proses: eksekusi query dan scan hasil ke struct ref
+	ref := new(model.DeletedAchievementReference)
+	err := r.db.QueryRowContext(ctx, query, mongoID).Scan(
+		&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status,
+		&ref.SubmittedAt, &ref.VerifiedAt, &ref.VerifiedBy, &ref.RejectionNote,
+		&ref.CreatedAt, &ref.UpdatedAt, &ref.DeletedAt, &ref.StatusBeforeDelete,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return ref, nil
+}
+
+// #27 proses: restore achievement reference ke status sebelum dihapus, return status hasil restore
+func (r *AchievementReferenceRepository) RestoreAchievementReference(ctx context.Context, id string) (string, error) {
+	// #27a proses: query update bersyarat, hanya reference yang masih deleted yang bisa di-restore
+	query := `
+		UPDATE achievement_references
+		SET status = COALESCE(status_before_delete, 'draft'), status_before_delete = NULL,
+		    deleted_at = NULL, version = version + 1, updated_at = NOW()
+		WHERE id = $1 AND status = 'deleted'
+		RETURNING status
+	`
+
+	// #27b proses: eksekusi query, sql.ErrNoRows berarti reference tidak ada di tempat sampah
+	var status string
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&status)
+	if err != nil {
+		return "", err
+	}
+
+	return status, nil
+}
+
+// #28 proses: ambil achievement reference di tempat sampah dengan pagination, urut dari yang terakhir dihapus
+func (r *AchievementReferenceRepository) GetDeletedAchievementReferencesPaginated(ctx context.Context, page, limit int) ([]model.DeletedAchievementReference, int, error) {
+	// #28a proses: hitung offset untuk pagination
+	offset := (page - 1) * limit
+
+	// #28b proses: query untuk hitung total reference deleted
+	countQuery := `SELECT COUNT(*) FROM achievement_references WHERE status = 'deleted'`
+	var total int
+	err := r.db.QueryRowContext(ctx, countQuery).Scan(&total)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// #28c proses: query untuk ambil reference deleted dengan limit dan offset
+	query := `
+		SELECT id, student_id, mongo_achievement_id, status, submitted_at,
+		       verified_at, verified_by, rejection_note, created_at, updated_at,
+		       COALESCE(deleted_at, updated_at), COALESCE(status_before_delete::text, 'draft')
+		FROM achievement_references
+		WHERE status = 'deleted'
+		ORDER BY COALESCE(deleted_at, updated_at) DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	refs, err := scanDeletedAchievementReferences(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return refs, total, nil
+}
+
+// #29 proses: ambil semua achievement reference yang dihapus sebelum batas waktu tertentu, dipakai untuk purge
+func (r *AchievementReferenceRepository) GetDeletedReferencesBefore(ctx context.Context, before time.Time) ([]model.DeletedAchievementReference, error) {
+	// #29a proses: query untuk ambil reference deleted yang sudah melewati masa retensi
+	query := `
+		SELECT id, student_id, mongo_achievement_id, status, submitted_at,
+		       verified_at, verified_by, rejection_note, created_at, updated_at,
+		       COALESCE(deleted_at, updated_at), COALESCE(status_before_delete::text, 'draft')
+		FROM achievement_references
+		WHERE status = 'deleted' AND COALESCE(deleted_at, updated_at) <= $1
+		ORDER BY COALESCE(deleted_at, updated_at) ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, before)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanDeletedAchievementReferences(rows)
+}
+
+// #30 proses: hapus permanen achievement reference yang ada di tempat sampah, history dan notifikasi ikut terhapus lewat ON DELETE CASCADE
+func (r *AchievementReferenceRepository) PurgeAchievementReference(ctx context.Context, id string) (bool, error) {
+	// #30a proses: query delete bersyarat supaya reference yang sudah di-restore tidak ikut terhapus
+	query := `DELETE FROM achievement_references WHERE id = $1 AND status = 'deleted'`
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+
+	// #30b proses: tidak ada baris yang terhapus berarti reference sudah di-restore atau sudah di-purge
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// #31 proses: scan semua baris hasil query reference deleted ke slice
+func scanDeletedAchievementReferences(rows *sql.Rows) ([]model.DeletedAchievementReference, error) {
+	var refs []model.DeletedAchievementReference
+	for rows.Next() {
+		var ref model.DeletedAchievementReference
+		err := rows.Scan(
+			&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status,
+			&ref.SubmittedAt, &ref.VerifiedAt, &ref.VerifiedBy, &ref.RejectionNote,
+			&ref.CreatedAt, &ref.UpdatedAt, &ref.DeletedAt, &ref.StatusBeforeDelete,
+		)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, ref)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return refs, nil
+}
+
+// #32 proses: tandai peringatan masa berlaku sertifikasi sudah dikirim, return false jika peringatan yang sama sudah pernah tercatat
+func (r *AchievementReferenceRepository) ClaimCertificationExpiryAlert(ctx context.Context, achievementRefID string, validUntil time.Time, daysBefore int) (bool, error) {
+	// #32a proses: insert dengan ON CONFLICT supaya setiap tanggal berlaku dan batas hari hanya diperingatkan sekali
+	query := `
+		INSERT INTO certification_expiry_alerts (achievement_ref_id, valid_until, days_before)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (achievement_ref_id, valid_until, days_before) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query, achievementRefID, validUntil, daysBefore)
+	if err != nil {
+		return false, err
+	}
+
+	// #32b proses: tidak ada baris baru berarti peringatan sudah pernah dikirim
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// #33 proses: hapus tanda peringatan masa berlaku sertifikasi supaya bisa dikirim ulang pada pengecekan berikutnya
+func (r *AchievementReferenceRepository) ReleaseCertificationExpiryAlert(ctx context.Context, achievementRefID string, validUntil time.Time, daysBefore int) error {
+	query := `
+		DELETE FROM certification_expiry_alerts
+		WHERE achievement_ref_id = $1 AND valid_until = $2 AND days_before = $3
+	`
+
+	_, err := r.db.ExecContext(ctx, query, achievementRefID, validUntil, daysBefore)
+	return err
+}
+
+// #34 proses: ambil reference aktif yang lolos filter PostgreSQL beserta data mahasiswa dan dosen wali, urut sesuai sortBy, dipakai sebagai batas ID untuk query MongoDB. Mode cursor memakai keyset dan RowLimit di query ini, RowOffset dipakai halaman yang diurutkan kolom PostgreSQL
+func (r *AchievementReferenceRepository) GetAchievementListRows(ctx context.Context, filter model.AchievementListFilter) ([]model.AchievementListRow, error) {
+	// #34a proses: susun kondisi WHERE dinamis, semua filter dipakai sebagai syarat
+	conditions, args := achievementListConditions(filter, false)
+
+	// #34b proses: validasi sortBy hanya kolom reference yang diizinkan, field MongoDB diurutkan di query MongoDB
+	allowedSortBy := map[string]bool{
+		"created_at":   true,
+		"updated_at":   true,
+		"submitted_at": true,
+		"status":       true,
+	}
+	sortBy := filter.SortBy
+	if !allowedSortBy[sortBy] {
+		sortBy = "created_at"
+	}
+	sortOrder := "DESC"
+	if filter.SortOrder == "ASC" {
+		sortOrder = "ASC"
+	}
+
+	// #34b1 proses: urutan created_at memakai mongo_achievement_id sebagai pemecah urutan supaya sama dengan keyset cursor, mode cursor mengambil baris setelah pasangan created_at dan mongo_achievement_id terakhir
+	orderBy := "ar." + sortBy + " " + sortOrder + " NULLS LAST, ar.id"
+	if sortBy == "created_at" {
+		orderBy = "ar.created_at " + sortOrder + ", ar.mongo_achievement_id " + sortOrder
+		if filter.CursorMode && filter.Cursor != nil {
+			operator := "<"
+			if sortOrder == "ASC" {
+				operator = ">"
+			}
+			args = append(args, filter.Cursor.CreatedAt, filter.Cursor.ID)
+			conditions = append(conditions, fmt.Sprintf("(ar.created_at, ar.mongo_achievement_id) %s ($%d, $%d)", operator, len(args)-1, len(args)))
+		}
+	}
+
+	// #34c proses: eksekusi query dengan LIMIT dan OFFSET jika diminta
+	return r.queryAchievementListRows(ctx, conditions, args, orderBy, filter.RowLimit, filter.RowOffset)
+}
+
+// #35 proses: ambil semua mongo achievement ID dari reference yang ada di tempat sampah, pasangan dari GetAllAchievementMongoIDs untuk rekonsiliasi
+func (r *AchievementReferenceRepository) GetDeletedAchievementMongoIDs(ctx context.Context) ([]string, error) {
+	// #35a proses: query untuk ambil mongo_achievement_id dengan status deleted
+	query := `
+		SELECT mongo_achievement_id
+		FROM achievement_references
+		WHERE status = 'deleted'
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// #35b proses: scan semua baris, error scan dikembalikan supaya rekonsiliasi tidak salah menganggap reference hilang
+	var mongoIDs []string
+	for rows.Next() {
+		var mongoID string
+		if err := rows.Scan(&mongoID); err != nil {
+			return nil, err
+		}
+		mongoIDs = append(mongoIDs, mongoID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return mongoIDs, nil
+}
+
+// #36 proses: tandai pengingat atau eskalasi SLA sudah dikirim untuk submit tertentu, return false jika sudah diklaim proses lain sehingga beberapa replika tidak mengirim notifikasi yang sama
+func (r *AchievementReferenceRepository) ClaimSLANotification(ctx context.Context, achievementRefID string, submittedAt time.Time, action string) (bool, error) {
+	// #36a proses: insert dengan ON CONFLICT supaya setiap submit dan tahap SLA hanya diklaim sekali
+	query := `
+		INSERT INTO sla_notification_claims (achievement_ref_id, submitted_at, action)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (achievement_ref_id, submitted_at, action) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query, achievementRefID, submittedAt, action)
+	if err != nil {
+		return false, err
+	}
+
+	// #36b proses: tidak ada baris baru berarti notifikasi sudah diklaim
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// #37 proses: hapus klaim notifikasi SLA supaya bisa dikirim ulang pada pengecekan berikutnya
+func (r *AchievementReferenceRepository) ReleaseSLANotification(ctx context.Context, achievementRefID string, submittedAt time.Time, action string) error {
+	query := `
+		DELETE FROM sla_notification_claims
+		WHERE achievement_ref_id = $1 AND submitted_at = $2 AND action = $3
+	`
+
+	_, err := r.db.ExecContext(ctx, query, achievementRefID, submittedAt, action)
+	return err
+}
+
+// #38 proses: ambil reference untuk banyak mongo achievement ID dalam satu query, termasuk yang deleted supaya pemanggil bisa melewati prestasi di tempat sampah
+func (r *AchievementReferenceRepository) GetAchievementReferencesByMongoIDs(ctx context.Context, mongoIDs []string) ([]model.AchievementReference, error) {
+	if len(mongoIDs) == 0 {
+		return []model.AchievementReference{}, nil
+	}
+
+	// #38a proses: query reference dengan mongo_achievement_id di dalam daftar ID
+	query := `
+		SELECT id, student_id, mongo_achievement_id, status, submitted_at,
+		       verified_at, verified_by, rejection_note, version, created_at, updated_at
+		FROM achievement_references
+		WHERE mongo_achievement_id = ANY($1)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(mongoIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// #38b proses: scan semua baris hasil ke slice references
+	references := []model.AchievementReference{}
+	for rows.Next() {
+		var ref model.AchievementReference
+		if err := rows.Scan(
+			&ref.ID, &ref.StudentID, &ref.MongoAchievementID, &ref.Status,
+			&ref.SubmittedAt, &ref.VerifiedAt, &ref.VerifiedBy, &ref.RejectionNote,
+			&ref.Version, &ref.CreatedAt, &ref.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		references = append(references, ref)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return references, nil
+}
+
+// #39 proses: ambil reference untuk hitung total dan facet per batch keyset created_at dan mongo_achievement_id dari yang terlama. Filter status, program studi, angkatan, dan dosen wali tidak dipakai sebagai syarat supaya facet setiap dimensi bisa dihitung tanpa filter dimensinya sendiri, service yang menentukan dimensi mana yang gagal
+func (r *AchievementReferenceRepository) GetAchievementFacetRows(ctx context.Context, filter model.AchievementListFilter, after *model.PageCursor, limit int) ([]model.AchievementListRow, error) {
+	conditions, args := achievementListConditions(filter, true)
+	if after != nil {
+		args = append(args, after.CreatedAt, after.ID)
+		conditions = append(conditions, fmt.Sprintf("(ar.created_at, ar.mongo_achievement_id) > ($%d, $%d)", len(args)-1, len(args)))
+	}
+
+	return r.queryAchievementListRows(ctx, conditions, args, "ar.created_at ASC, ar.mongo_achievement_id ASC", limit, 0)
+}
+
+// #40 proses: susun kondisi WHERE daftar prestasi, setiap dimensi multi-select memakai ANY. Dengan facetScan, filter dimensi facet PostgreSQL hanya membuang baris yang gagal lebih dari satu dimensi karena baris itu tidak dihitung di facet mana pun
+func achievementListConditions(filter model.AchievementListFilter, facetScan bool) ([]string, []interface{}) {
+	conditions := []string{"ar.status != 'deleted'"}
+	args := []interface{}{}
+	addCondition := func(format string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, fmt.Sprintf(format, len(args)))
+	}
+
+	if filter.ScopeStudentID != "" {
+		addCondition("ar.student_id = $%d", filter.ScopeStudentID)
+	}
+	if filter.ScopeAdvisorID != "" {
+		addCondition("s.advisor_id = $%d", filter.ScopeAdvisorID)
+	}
+
+	// #40a proses: kondisi dimensi facet dikumpulkan terpisah supaya bisa dipakai sebagai syarat atau dihitung jumlah yang gagal
+	facetConditions := []string{}
+	addFacetCondition := func(format string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		args = append(args, pq.Array(values))
+		facetConditions = append(facetConditions, fmt.Sprintf(format, len(args)))
+	}
+	addFacetCondition("ar.status::text = ANY($%d)", filter.Statuses)
+	addFacetCondition("s.program_study = ANY($%d)", filter.ProgramStudies)
+	addFacetCondition("s.academic_year = ANY($%d)", filter.AcademicYears)
+	addFacetCondition("s.advisor_id::text = ANY($%d)", filter.AdvisorIDs)
+
+	if !facetScan {
+		conditions = append(conditions, facetConditions...)
+	} else if len(facetConditions) > 1 {
+		// #40b proses: kolom NULL membuat ANY bernilai NULL sehingga dihitung gagal, sama seperti saat dipakai sebagai syarat
+		misses := make([]string, 0, len(facetConditions))
+		for _, condition := range facetConditions {
+			misses = append(misses, "CASE WHEN "+condition+" THEN 0 ELSE 1 END")
+		}
+		conditions = append(conditions, "("+strings.Join(misses, " + ")+") <= 1")
+	}
+
+	if len(filter.StudentIDs) > 0 {
+		addCondition("ar.student_id::text = ANY($%d)", pq.Array(filter.StudentIDs))
+	}
+
+	return conditions, args
+}
+
+// #41 proses: jalankan query daftar reference beserta data mahasiswa dan dosen wali, limit dan offset 0 berarti tanpa batas
+func (r *AchievementReferenceRepository) queryAchievementListRows(ctx context.Context, conditions []string, args []interface{}, orderBy string, limit int, offset int) ([]model.AchievementListRow, error) {
+	query := `
+		SELECT ar.id, ar.student_id, ar.mongo_achievement_id, ar.status, ar.submitted_at,
+		       ar.verified_at, ar.verified_by, ar.rejection_note, ar.created_at, ar.updated_at,
+		       COALESCE(su.full_name, ''), COALESCE(s.program_study, ''), COALESCE(s.academic_year, ''),
+		       COALESCE(s.advisor_id::text, ''), COALESCE(lu.full_name, '')
+		FROM achievement_references ar
+		INNER JOIN students s ON ar.student_id = s.id
+		LEFT JOIN users su ON s.user_id = su.id
+		LEFT JOIN lecturers l ON s.advisor_id = l.id
+		LEFT JOIN users lu ON l.user_id = lu.id
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + orderBy
+
+	// #41a proses: batasi jumlah baris supaya halaman dan batch tidak membaca semua reference
+	if limit > 0 {
+		args = append(args, limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
+	}
+	if offset > 0 {
+		args = append(args, offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
+	}
+
+	// #41b proses: eksekusi query dan scan semua baris
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []model.AchievementListRow
+	for rows.Next() {
+		var row model.AchievementListRow
+		err := rows.Scan(
+			&row.ID, &row.StudentID, &row.MongoAchievementID, &row.Status,
+			&row.SubmittedAt, &row.VerifiedAt, &row.VerifiedBy, &row.RejectionNote,
+			&row.CreatedAt, &row.UpdatedAt,
+			&row.StudentName, &row.ProgramStudy, &row.AcademicYear, &row.AdvisorID, &row.AdvisorName,
+		)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -11,9 +11,11 @@ import (
 type INotificationRepository interface {
 	CreateNotification(ctx context.Context, req model.CreateNotificationRequest) (*model.Notification, error)
 	GetNotificationsByUserIDPaginated(ctx context.Context, userID string, page, limit int) ([]model.Notification, int, error)
+	GetNotificationsByUserIDAfterCursor(ctx context.Context, userID string, cursor *model.PageCursor, limit int) ([]model.Notification, error)
 	GetUnreadCountByUserID(ctx context.Context, userID string) (int, error)
 	MarkAsRead(ctx context.Context, notificationID string, userID string) error
 	MarkAllAsRead(ctx context.Context, userID string) error
+	DeleteNotificationsByAchievement(ctx context.Context, achievementRefID string, notificationType string) (int64, error)
 }
 
 // #3 proses: struct repository untuk operasi database notification
@@ -90,7 +92,7 @@ func (r *NotificationRepository) GetNotificationsByUserIDPaginated(ctx context.C
 		SELECT id, user_id, type, title, message, achievement_id, mongo_achievement_id, is_read, read_at, created_at, updated_at
 		FROM notifications
 		WHERE user_id = $1
-		ORDER BY created_at DESC
+		ORDER BY created_at DESC, id DESC
 		LIMIT $2 OFFSET $3
 	`
 
@@ -197,3 +199,87 @@ func (r *NotificationRepository) MarkAllAsRead(ctx context.Context, userID strin
 	_, err := r.db.ExecContext(ctx, query, userID)
 	return err
 }
+
+// #10 proses: hapus notifikasi dengan tipe tertentu untuk satu prestasi, dipakai saat notifikasi sudah tidak berlaku
+func (r *NotificationRepository) DeleteNotificationsByAchievement(ctx context.Context, achievementRefID string, notificationType string) (int64, error) {
+	// #10a proses: query untuk delete notifikasi berdasarkan achievement_id dan type
+	query := `
+		DELETE FROM notifications
+		WHERE achievement_id = $1 AND type = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, achievementRefID, notificationType)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
+
+// #11 proses: ambil notifikasi user dengan keyset pagination, tanpa OFFSET dan COUNT supaya halaman dalam tetap cepat dan tidak bergeser saat ada notifikasi baru
+func (r *NotificationRepository) GetNotificationsByUserIDAfterCursor(ctx context.Context, userID string, cursor *model.PageCursor, limit int) ([]model.Notification, error) {
+	// #11a proses: cursor nil berarti halaman pertama, selain itu ambil baris setelah pasangan created_at dan id terakhir
+	query := `
+		SELECT id, user_id, type, title, message, achievement_id, mongo_achievement_id, is_read, read_at, created_at, updated_at
+		FROM notifications
+		WHERE user_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2
+	`
+	args := []interface{}{userID, limit}
+	if cursor != nil {
+		query = `
+			SELECT id, user_id, type, title, message, achievement_id, mongo_achievement_id, is_read, read_at, created_at, updated_at
+			FROM notifications
+			WHERE user_id = $1 AND (created_at, id) < ($2, $3)
+			ORDER BY created_at DESC, id DESC
+			LIMIT $4
+		`
+		args = []interface{}{userID, cursor.CreatedAt, cursor.ID, limit}
+	}
+
+	// #11b proses: eksekusi query dan ambil semua baris hasil
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// #11c proses: loop semua hasil dan masukkan ke slice notifications
+	var notifications []model.Notification
+	for rows.Next() {
+		var notif model.Notification
+		var achievementID sql.NullString
+		var mongoAchievementID sql.NullString
+		var readAt sql.NullTime
+
+		err := rows.Scan(
+			&notif.ID, &notif.UserID, &notif.Type, &notif.Title, &notif.Message,
+			&achievementID, &mongoAchievementID, &notif.IsRead, &readAt, &notif.CreatedAt, &notif.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// #11d proses: set field yang bisa null jika nilainya valid
+		if achievementID.Valid {
+			notif.AchievementID = &achievementID.String
+		}
+
+		if mongoAchievementID.Valid {
+			notif.MongoAchievementID = &mongoAchievementID.String
+		}
+
+		if readAt.Valid {
+			notif.ReadAt = &readAt.Time
+		}
+
+		notifications = append(notifications, notif)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
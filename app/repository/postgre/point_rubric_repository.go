@@ -0,0 +1,187 @@
+package repository
+
+// #1 proses: import library yang diperlukan untuk database, context, dan encoding json
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	model "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+)
+
+// #2 proses: definisikan interface untuk operasi database rubrik poin
+type IPointRubricRepository interface {
+	CreatePointRubric(ctx context.Context, req model.CreatePointRubricRequest, createdBy string) (*model.PointRubric, error)
+	GetActivePointRubric(ctx context.Context) (*model.PointRubric, error)
+	GetPointRubricByVersion(ctx context.Context, version int) (*model.PointRubric, error)
+	GetAllPointRubrics(ctx context.Context) ([]model.PointRubric, error)
+	ActivatePointRubric(ctx context.Context, version int) error
+}
+
+// #3 proses: struct repository untuk operasi database rubrik poin
+type PointRubricRepository struct {
+	db *sql.DB
+}
+
+// #4 proses: constructor untuk membuat instance PointRubricRepository baru
+func NewPointRubricRepository(db *sql.DB) IPointRubricRepository {
+	return &PointRubricRepository{db: db}
+}
+
+// #5 proses: buat rubrik poin baru dengan versi berikutnya, rubrik baru selalu tidak aktif dulu
+func (r *PointRubricRepository) CreatePointRubric(ctx context.Context, req model.CreatePointRubricRequest, createdBy string) (*model.PointRubric, error) {
+	// #5a proses: ubah rules jadi json untuk disimpan di kolom JSONB
+	rulesJSON, err := json.Marshal(req.Rules)
+	if err != nil {
+		return nil, err
+	}
+
+	// #5b proses: siapkan description dan created_by yang bisa null
+	var description interface{}
+	if req.Description != "" {
+		description = req.Description
+	}
+	var creator interface{}
+	if createdBy != "" {
+		creator = createdBy
+	}
+
+	// #5c proses: query insert dengan versi = versi terakhir + 1
+	query := `
+		INSERT INTO point_rubrics (version, name, description, rules, is_active, created_by, created_at)
+		VALUES ((SELECT COALESCE(MAX(version), 0) + 1 FROM point_rubrics), $1, $2, $3, false, $4, NOW())
+		RETURNING id, version, name, description, rules, is_active, created_by, activated_at, created_at
+	`
+
+	row := r.db.QueryRowContext(ctx, query, req.Name, description, rulesJSON, creator)
+	return scanPointRubric(row)
+}
+
+// #6 proses: ambil rubrik poin yang sedang aktif
+func (r *PointRubricRepository) GetActivePointRubric(ctx context.Context) (*model.PointRubric, error) {
+	query := `
+		SELECT id, version, name, description, rules, is_active, created_by, activated_at, created_at
+		FROM point_rubrics
+		WHERE is_active = true
+		LIMIT 1
+	`
+
+	row := r.db.QueryRowContext(ctx, query)
+	return scanPointRubric(row)
+}
+
+// #7 proses: ambil rubrik poin berdasarkan nomor versi
+func (r *PointRubricRepository) GetPointRubricByVersion(ctx context.Context, version int) (*model.PointRubric, error) {
+	query := `
+		SELECT id, version, name, description, rules, is_active, created_by, activated_at, created_at
+		FROM point_rubrics
+		WHERE version = $1
+	`
+
+	row := r.db.QueryRowContext(ctx, query, version)
+	return scanPointRubric(row)
+}
+
+// #8 proses: ambil semua versi rubrik poin, versi terbaru di atas
+func (r *PointRubricRepository) GetAllPointRubrics(ctx context.Context) ([]model.PointRubric, error) {
+	query := `
+		SELECT id, version, name, description, rules, is_active, created_by, activated_at, created_at
+		FROM point_rubrics
+		ORDER BY version DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// #8a proses: loop semua hasil dan masukkan ke slice rubrics
+	var rubrics []model.PointRubric
+	for rows.Next() {
+		rubric, err := scanPointRubric(rows)
+		if err != nil {
+			return nil, err
+		}
+		rubrics = append(rubrics, *rubric)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return rubrics, nil
+}
+
+// #9 proses: aktifkan rubrik versi tertentu dan nonaktifkan rubrik lain dalam satu transaction
+func (r *PointRubricRepository) ActivatePointRubric(ctx context.Context, version int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// #9a proses: nonaktifkan rubrik yang sedang aktif dulu supaya unique index tidak bentrok
+	_, err = tx.ExecContext(ctx, `UPDATE point_rubrics SET is_active = false WHERE is_active = true`)
+	if err != nil {
+		return err
+	}
+
+	// #9b proses: aktifkan rubrik versi yang diminta
+	result, err := tx.ExecContext(ctx, `UPDATE point_rubrics SET is_active = true, activated_at = NOW() WHERE version = $1`, version)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return tx.Commit()
+}
+
+// #10 proses: interface scanner supaya helper scan bisa dipakai oleh sql.Row dan sql.Rows
+type pointRubricScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// #11 proses: helper untuk scan satu baris rubrik poin
+func scanPointRubric(scanner pointRubricScanner) (*model.PointRubric, error) {
+	rubric := new(model.PointRubric)
+	var description sql.NullString
+	var createdBy sql.NullString
+	var activatedAt sql.NullTime
+	var rulesJSON []byte
+
+	err := scanner.Scan(
+		&rubric.ID, &rubric.Version, &rubric.Name, &description, &rulesJSON,
+		&rubric.IsActive, &createdBy, &activatedAt, &rubric.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// #11a proses: set field yang bisa null jika nilainya valid
+	if description.Valid {
+		rubric.Description = &description.String
+	}
+	if createdBy.Valid {
+		rubric.CreatedBy = &createdBy.String
+	}
+	if activatedAt.Valid {
+		rubric.ActivatedAt = &activatedAt.Time
+	}
+
+	// #11b proses: decode rules dari kolom JSONB
+	if len(rulesJSON) > 0 {
+		if err := json.Unmarshal(rulesJSON, &rubric.Rules); err != nil {
+			return nil, err
+		}
+	}
+
+	return rubric, nil
+}
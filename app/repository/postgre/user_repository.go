@@ -22,6 +22,7 @@ type IUserRepository interface {
 	GetAllRoles(ctx context.Context) ([]model.Role, error)
 	GetLecturerByUserID(ctx context.Context, userID string) (*model.Lecturer, error)
 	GetLecturerByID(ctx context.Context, id string) (*model.Lecturer, error)
+	GetActiveUserIDsByRoleName(ctx context.Context, roleName string) ([]string, error)
 }
 
 // #3 proses: struct repository untuk operasi database user
@@ -400,3 +401,38 @@ func (r *UserRepository) UpdateUserRole(ctx context.Context, id string, roleID s
 
 	return nil
 }
+
+// #19 proses: ambil ID semua user aktif yang memiliki role tertentu
+func (r *UserRepository) GetActiveUserIDsByRoleName(ctx context.Context, roleName string) ([]string, error) {
+	// #19a proses: query untuk ambil user ID dengan join ke tabel roles
+	query := `
+		SELECT u.id
+		FROM users u
+		JOIN roles r ON u.role_id = r.id
+		WHERE r.name = $1 AND u.is_active = true
+		ORDER BY u.created_at ASC
+	`
+
+	// #19b proses: eksekusi query dan ambil semua baris hasil
+	rows, err := r.db.QueryContext(ctx, query, roleName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// #19c proses: loop semua hasil dan masukkan ke slice ids
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
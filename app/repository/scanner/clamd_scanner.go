@@ -0,0 +1,118 @@
+package repository
+
+// #1 proses: import library yang diperlukan untuk bufio, context, binary, errors, fmt, io, net, strings, dan time
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// #2 proses: ukuran chunk INSTREAM, clamd menolak chunk yang lebih besar dari StreamMaxLength sehingga dikirim bertahap
+const clamdChunkSize = 64 * 1024
+
+// #3 proses: struct client clamd, network "tcp" atau "unix"
+type ClamdScanner struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// #4 proses: constructor untuk membuat instance ClamdScanner baru, address berupa tcp://host:port atau unix:///path/clamd.ctl
+func NewClamdScanner(address string, timeout time.Duration) (IMalwareScanner, error) {
+	network, addr, ok := strings.Cut(address, "://")
+	if !ok {
+		network, addr = "tcp", address
+	}
+	if (network != "tcp" && network != "unix") || addr == "" {
+		return nil, errors.New("alamat clamd tidak valid: " + address)
+	}
+
+	return &ClamdScanner{
+		network: network,
+		address: addr,
+		timeout: timeout,
+	}, nil
+}
+
+// #5 proses: nama scanner
+func (s *ClamdScanner) Name() string {
+	return "clamd"
+}
+
+// #6 proses: kirim isi file ke clamd dengan perintah INSTREAM lalu baca hasilnya
+func (s *ClamdScanner) Scan(ctx context.Context, body io.Reader) (*ScanResult, error) {
+	// #6a proses: buka koneksi ke clamd, deadline diambil dari context atau timeout scanner
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return nil, fmt.Errorf("gagal terhubung ke clamd: %w", err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(s.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	// #6b proses: koneksi ditutup jika context dibatalkan supaya read dan write tidak menunggu deadline
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	// #6c proses: kirim perintah dan isi file per chunk dengan prefix panjang 4 byte big-endian, chunk panjang 0 menandai akhir file
+	if _, err := io.WriteString(conn, "zINSTREAM\x00"); err != nil {
+		return nil, fmt.Errorf("gagal mengirim perintah ke clamd: %w", err)
+	}
+
+	buf := make([]byte, 4+clamdChunkSize)
+	for {
+		n, readErr := io.ReadFull(body, buf[4:])
+		if n > 0 {
+			binary.BigEndian.PutUint32(buf[:4], uint32(n))
+			if _, err := conn.Write(buf[:4+n]); err != nil {
+				return nil, fmt.Errorf("gagal mengirim file ke clamd: %w", err)
+			}
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return nil, fmt.Errorf("gagal membaca file: %w", readErr)
+		}
+	}
+
+	if _, err := conn.Write([]byte{0, 0, 0, 0}); err != nil {
+		return nil, fmt.Errorf("gagal mengirim file ke clamd: %w", err)
+	}
+
+	// #6d proses: baca balasan yang diakhiri byte null
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && !(errors.Is(err, io.EOF) && reply != "") {
+		return nil, fmt.Errorf("gagal membaca balasan clamd: %w", err)
+	}
+
+	return parseClamdReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// #7 proses: parsing balasan clamd, "stream: OK" berarti bersih dan "stream: <nama> FOUND" berarti terinfeksi
+func parseClamdReply(reply string) (*ScanResult, error) {
+	result := strings.TrimSpace(reply)
+	if _, after, ok := strings.Cut(result, ": "); ok {
+		result = after
+	}
+
+	switch {
+	case result == "OK":
+		return &ScanResult{}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return &ScanResult{Infected: true, Signature: strings.TrimSuffix(result, " FOUND")}, nil
+	default:
+		return nil, errors.New("clamd error: " + reply)
+	}
+}
@@ -0,0 +1,19 @@
+package repository
+
+// #1 proses: import library context dan io untuk stream file yang dipindai
+import (
+	"context"
+	"io"
+)
+
+// #2 proses: hasil pemindaian satu file, Signature berisi nama malware jika Infected
+type ScanResult struct {
+	Infected  bool
+	Signature string
+}
+
+// #3 proses: definisikan interface pemindai malware untuk file attachment, error berarti file belum bisa dipastikan aman dan perlu dipindai ulang
+type IMalwareScanner interface {
+	Name() string
+	Scan(ctx context.Context, body io.Reader) (*ScanResult, error)
+}
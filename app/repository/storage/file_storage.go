@@ -0,0 +1,37 @@
+package repository
+
+// #1 proses: import library yang diperlukan untuk context, errors, io, dan time
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// #2 proses: nama driver storage yang didukung, dipilih lewat STORAGE_DRIVER
+const (
+	StorageDriverLocal = "local"
+	StorageDriverS3    = "s3"
+)
+
+// #3 proses: error jika file tidak ada di storage, driver memetakan error masing-masing backend ke error ini
+var ErrFileNotFound = errors.New("file tidak ditemukan di storage")
+
+// #4 proses: metadata file yang tersimpan di storage
+type FileInfo struct {
+	Key         string    `json:"key"`
+	Size        int64     `json:"size"`
+	ContentType string    `json:"content_type"`
+	ModTime     time.Time `json:"mod_time"`
+}
+
+// #5 proses: definisikan interface storage file attachment, key adalah nama file tanpa folder dan URL adalah nilai yang disimpan di Attachment.FileURL
+type IFileStorage interface {
+	Driver() string
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, *FileInfo, error)
+	Delete(ctx context.Context, key string) error
+	Stat(ctx context.Context, key string) (*FileInfo, error)
+	URL(key string) string
+	KeyFromURL(fileURL string) (string, bool)
+}
@@ -0,0 +1,172 @@
+package repository
+
+// #1 proses: import library yang diperlukan untuk context, errors, io, mime, os, path, dan strings
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// #2 proses: struct storage di filesystem lokal, file disimpan langsung di folder root dan disajikan lewat urlPrefix
+type LocalFileStorage struct {
+	root      string
+	urlPrefix string
+}
+
+// #3 proses: constructor untuk membuat instance LocalFileStorage baru
+func NewLocalFileStorage(root string, urlPrefix string) IFileStorage {
+	if !strings.HasSuffix(urlPrefix, "/") {
+		urlPrefix += "/"
+	}
+	return &LocalFileStorage{root: root, urlPrefix: urlPrefix}
+}
+
+// #4 proses: nama driver
+func (s *LocalFileStorage) Driver() string {
+	return StorageDriverLocal
+}
+
+// #5 proses: simpan file ke file sementara lalu rename supaya pembaca tidak pernah melihat file setengah jadi
+func (s *LocalFileStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.root, 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.root, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// #6 proses: buka file untuk dibaca beserta metadatanya
+func (s *LocalFileStorage) Get(ctx context.Context, key string) (io.ReadCloser, *FileInfo, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, ErrFileNotFound
+		}
+		return nil, nil, err
+	}
+
+	info, err := s.fileInfo(key, file)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	return file, info, nil
+}
+
+// #7 proses: hapus file, file yang sudah tidak ada dianggap berhasil supaya hapus bisa diulang
+func (s *LocalFileStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// #8 proses: ambil metadata file tanpa membaca isinya
+func (s *LocalFileStorage) Stat(ctx context.Context, key string) (*FileInfo, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrFileNotFound
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	return s.fileInfo(key, file)
+}
+
+// #9 proses: URL file yang disimpan di attachment
+func (s *LocalFileStorage) URL(key string) string {
+	return s.urlPrefix + key
+}
+
+// #10 proses: ambil key dari URL attachment, false jika URL bukan milik storage ini
+func (s *LocalFileStorage) KeyFromURL(fileURL string) (string, bool) {
+	if !strings.HasPrefix(fileURL, s.urlPrefix) {
+		return "", false
+	}
+
+	key := strings.TrimPrefix(fileURL, s.urlPrefix)
+	if validateStorageKey(key) != nil {
+		return "", false
+	}
+	return key, true
+}
+
+// #11 proses: ubah key jadi path di dalam folder root, key dengan separator ditolak supaya tidak bisa keluar dari root
+func (s *LocalFileStorage) path(key string) (string, error) {
+	if err := validateStorageKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, key), nil
+}
+
+// #12 proses: metadata dari file yang sudah dibuka, content type ditebak dari ekstensi
+func (s *LocalFileStorage) fileInfo(key string, file *os.File) (*FileInfo, error) {
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if stat.IsDir() {
+		return nil, ErrFileNotFound
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return &FileInfo{
+		Key:         key,
+		Size:        stat.Size(),
+		ContentType: contentType,
+		ModTime:     stat.ModTime(),
+	}, nil
+}
+
+// #13 proses: validasi key storage, hanya satu segmen nama file tanpa separator dan bukan . atau ..
+func validateStorageKey(key string) error {
+	if key == "" || key == "." || key == ".." || strings.ContainsAny(key, `/\`) || strings.ContainsRune(key, 0) {
+		return errors.New("key storage tidak valid: " + key)
+	}
+	return nil
+}
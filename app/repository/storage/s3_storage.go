@@ -0,0 +1,346 @@
+package repository
+
+// #1 proses: import library yang diperlukan untuk context, crypto, encoding, errors, fmt, io, http, url, sort, strconv, strings, dan time
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// #2 proses: hash payload untuk request yang body-nya tidak ikut ditandatangani, dipakai saat upload stream
+const S3UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+// #3 proses: hash SHA-256 dari body kosong, dipakai untuk GET, HEAD, dan DELETE
+const s3EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// #4 proses: konfigurasi storage S3-compatible seperti MinIO atau Ceph RGW
+type S3Config struct {
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PublicURL string
+}
+
+// #5 proses: struct storage S3-compatible, request dikirim path-style ke endpoint/bucket/key dan ditandatangani AWS Signature Version 4
+type S3FileStorage struct {
+	config     S3Config
+	endpoint   *url.URL
+	publicURL  string
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// #6 proses: constructor untuk membuat instance S3FileStorage baru, httpClient nil berarti pakai http.DefaultClient
+func NewS3FileStorage(config S3Config, httpClient *http.Client) (IFileStorage, error) {
+	if config.Endpoint == "" || config.Bucket == "" || config.AccessKey == "" || config.SecretKey == "" {
+		return nil, errors.New("konfigurasi S3 tidak lengkap: endpoint, bucket, access key, dan secret key wajib diisi")
+	}
+
+	endpoint, err := url.Parse(strings.TrimSuffix(config.Endpoint, "/"))
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, errors.New("endpoint S3 tidak valid: " + config.Endpoint)
+	}
+
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+
+	publicURL := strings.TrimSuffix(config.PublicURL, "/")
+	if publicURL == "" {
+		publicURL = endpoint.String() + "/" + config.Bucket
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &S3FileStorage{
+		config:     config,
+		endpoint:   endpoint,
+		publicURL:  publicURL,
+		httpClient: httpClient,
+		now:        time.Now,
+	}, nil
+}
+
+// #7 proses: nama driver
+func (s *S3FileStorage) Driver() string {
+	return StorageDriverS3
+}
+
+// #8 proses: upload object dengan PUT, body di-stream tanpa dibaca ke memory sehingga payload tidak ikut ditandatangani
+func (s *S3FileStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	req, err := s.newRequest(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.do(req, S3UnsignedPayload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s3ResponseError("upload", key, resp)
+	}
+	return nil
+}
+
+// #9 proses: ambil object dengan GET, caller wajib menutup body
+func (s *S3FileStorage) Get(ctx context.Context, key string) (io.ReadCloser, *FileInfo, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.do(req, s3EmptyPayloadHash)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		if resp.StatusCode == http.StatusNotFound {
+			return nil, nil, ErrFileNotFound
+		}
+		return nil, nil, s3ResponseError("download", key, resp)
+	}
+
+	return resp.Body, s3FileInfo(key, resp), nil
+}
+
+// #10 proses: hapus object dengan DELETE, object yang sudah tidak ada dianggap berhasil
+func (s *S3FileStorage) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := s.do(req, s3EmptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s3ResponseError("hapus", key, resp)
+	}
+	return nil
+}
+
+// #11 proses: ambil metadata object dengan HEAD
+func (s *S3FileStorage) Stat(ctx context.Context, key string) (*FileInfo, error) {
+	req, err := s.newRequest(ctx, http.MethodHead, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.do(req, s3EmptyPayloadHash)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrFileNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, s3ResponseError("stat", key, resp)
+	}
+
+	return s3FileInfo(key, resp), nil
+}
+
+// #12 proses: URL publik object yang disimpan di attachment
+func (s *S3FileStorage) URL(key string) string {
+	return s.publicURL + "/" + s3URIEncode(key)
+}
+
+// #13 proses: ambil key dari URL attachment, false jika URL bukan milik bucket ini
+func (s *S3FileStorage) KeyFromURL(fileURL string) (string, bool) {
+	prefix := s.publicURL + "/"
+	if !strings.HasPrefix(fileURL, prefix) {
+		return "", false
+	}
+
+	key, err := url.PathUnescape(strings.TrimPrefix(fileURL, prefix))
+	if err != nil || validateStorageKey(key) != nil {
+		return "", false
+	}
+	return key, true
+}
+
+// #14 proses: buat request path-style ke endpoint/bucket/key
+func (s *S3FileStorage) newRequest(ctx context.Context, method string, key string, body io.Reader) (*http.Request, error) {
+	if err := validateStorageKey(key); err != nil {
+		return nil, err
+	}
+
+	basePath := strings.TrimSuffix(s.endpoint.EscapedPath(), "/")
+	target := s.endpoint.Scheme + "://" + s.endpoint.Host + basePath + "/" + s3URIEncode(s.config.Bucket) + "/" + s3URIEncode(key)
+
+	return http.NewRequestWithContext(ctx, method, target, body)
+}
+
+// #15 proses: tandatangani lalu kirim request
+func (s *S3FileStorage) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	SignS3Request(req, s.config.AccessKey, s.config.SecretKey, s.config.Region, payloadHash, s.now())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.New("gagal menghubungi storage S3: " + err.Error())
+	}
+	return resp, nil
+}
+
+// #16 proses: tandatangani request dengan AWS Signature Version 4 untuk service s3, header Authorization, x-amz-date, dan x-amz-content-sha256 ditulis ke request
+func SignS3Request(req *http.Request, accessKey string, secretKey string, region string, payloadHash string, now time.Time) {
+	// #16a proses: set header wajib sebelum header dikanonikalisasi
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	shortDate := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	// #16b proses: header yang ditandatangani adalah host, content-type, range, dan semua x-amz-*
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if lower == "content-type" || lower == "range" || strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	// #16c proses: susun canonical request
+	canonicalURI := req.URL.EscapedPath()
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		s3CanonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	// #16d proses: susun string to sign dan hitung signature dengan signing key turunan
+	scope := shortDate + "/" + region + "/s3/aws4_request"
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+secretKey), shortDate)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+scope+", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// #17 proses: HMAC-SHA256
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// #18 proses: query string kanonik, parameter diurutkan berdasarkan nama lalu nilai
+func s3CanonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		vals := append([]string(nil), values[key]...)
+		sort.Strings(vals)
+		for _, val := range vals {
+			parts = append(parts, s3URIEncode(key)+"="+s3URIEncode(val))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// #19 proses: URI encode sesuai aturan SigV4, hanya karakter unreserved yang tidak di-encode
+func s3URIEncode(value string) string {
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		if (ch >= 'A' && ch <= 'Z') || (ch >= 'a' && ch <= 'z') || (ch >= '0' && ch <= '9') || ch == '-' || ch == '_' || ch == '.' || ch == '~' {
+			builder.WriteByte(ch)
+		} else {
+			fmt.Fprintf(&builder, "%%%02X", ch)
+		}
+	}
+	return builder.String()
+}
+
+// #20 proses: metadata object dari header response
+func s3FileInfo(key string, resp *http.Response) *FileInfo {
+	info := &FileInfo{
+		Key:         key,
+		Size:        resp.ContentLength,
+		ContentType: resp.Header.Get("Content-Type"),
+	}
+
+	if info.Size < 0 {
+		if size, err := strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64); err == nil {
+			info.Size = size
+		}
+	}
+	if modTime, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.ModTime = modTime
+	}
+	if info.ContentType == "" {
+		info.ContentType = "application/octet-stream"
+	}
+
+	return info
+}
+
+// #21 proses: error dari response S3 yang gagal, potongan body ikut dilampirkan supaya kode error S3 terlihat
+func s3ResponseError(operation string, key string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("gagal %s file %s di storage S3: status %d: %s", operation, key, resp.StatusCode, strings.TrimSpace(string(body)))
+}
@@ -0,0 +1,705 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk bytes, context, crypto, database, encoding, errors, fmt, io, url, os, filepath, strconv, strings, time, model, repository MongoDB, dan repository storage
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorystorage "sistem-pelaporan-prestasi-mahasiswa/app/repository/storage"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// #2 proses: pesan error link download yang tidak valid atau kedaluwarsa, route memetakan pesan ini ke 403, akhiran key file upload yang belum dipindah ke key hash, dan umur maksimal reservasi kuota yang tertinggal
+const (
+	attachmentLinkInvalidMessage  = "akses ditolak. Link download tidak valid atau sudah kedaluwarsa"
+	attachmentTempSuffix          = ".part"
+	attachmentQuotaReservationTTL = time.Hour
+)
+
+// #3 proses: baca konfigurasi attachment dari environment variable. Di production (APP_ENV=production) ATTACHMENT_LINK_SECRET wajib diisi supaya semua instance server memakai secret yang sama, di luar production secret kosong diganti secret acak sehingga link lama tidak berlaku setelah server restart
+func LoadAttachmentConfig() (modelmongo.AttachmentConfig, error) {
+	secret := []byte(strings.TrimSpace(os.Getenv("ATTACHMENT_LINK_SECRET")))
+	if len(secret) == 0 {
+		if strings.EqualFold(strings.TrimSpace(os.Getenv("APP_ENV")), "production") {
+			return modelmongo.AttachmentConfig{}, errors.New("ATTACHMENT_LINK_SECRET wajib diisi jika APP_ENV=production")
+		}
+
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return modelmongo.AttachmentConfig{}, errors.New("error membuat secret link attachment: " + err.Error())
+		}
+		fmt.Println("ATTACHMENT_LINK_SECRET is not set, signed attachment links will not survive a restart")
+	}
+
+	return modelmongo.AttachmentConfig{
+		LinkSecret:   secret,
+		LinkTTL:      time.Duration(envInt("ATTACHMENT_LINK_TTL_MINUTES", 15, 1)) * time.Minute,
+		StudentQuota: LoadAttachmentQuota(),
+	}, nil
+}
+
+// #3a proses: baca kuota storage attachment per mahasiswa dari ATTACHMENT_QUOTA_MB, 0 berarti tanpa batas. Dipisah supaya laporan pemakaian storage bisa membaca kuota tanpa membuat secret link
+func LoadAttachmentQuota() int64 {
+	return int64(envInt("ATTACHMENT_QUOTA_MB", 200, 0)) * 1024 * 1024
+}
+
+// #4 proses: key storage sementara untuk file yang sedang diupload, timestamp nano supaya upload dengan nama file sama tidak saling menimpa. Setelah hash isi file diketahui file dipindah ke key dari hash
+func attachmentStorageKey(fileName string) string {
+	return fmt.Sprintf("%d-%s%s", time.Now().UnixNano(), fileName, attachmentTempSuffix)
+}
+
+// #4a proses: key storage file attachment dari hash SHA-256 isi file dan ekstensi nama file, file dengan isi sama dari upload mana pun memakai key yang sama
+func attachmentContentKey(contentHash string, fileName string) string {
+	return contentHash + strings.ToLower(filepath.Ext(fileName))
+}
+
+// #5 proses: ambil achievement beserta reference dengan aturan akses yang sama untuk detail prestasi dan download attachment
+func (s *AchievementService) loadViewableAchievement(ctx context.Context, userID string, roleID string, mongoID string) (*modelpostgre.AchievementReference, *modelmongo.Achievement, string, error) {
+	// #5a proses: ambil achievement reference untuk validasi akses
+	ref, err := s.achievementRefRepo.GetAchievementReferenceByMongoID(ctx, mongoID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil, "", errors.New("prestasi tidak ditemukan")
+		}
+		return nil, nil, "", err
+	}
+
+	// #5b proses: validasi akses berdasarkan role
+	roleName, err := s.userRepo.GetRoleName(ctx, roleID)
+	if err != nil {
+		return nil, nil, "", errors.New("error mengambil role name: " + err.Error())
+	}
+
+	// #5c proses: ambil achievement dari MongoDB, dibutuhkan untuk cek anggota tim
+	achievement, err := s.achievementRepo.GetAchievementByID(ctx, mongoID)
+	if err != nil {
+		return nil, nil, "", errors.New("error mengambil achievement dari database: " + err.Error())
+	}
+	if achievement == nil {
+		return nil, nil, "", errors.New("prestasi tidak ditemukan")
+	}
+
+	if roleName == "Mahasiswa" {
+		// #5d proses: mahasiswa hanya bisa lihat prestasi sendiri atau prestasi tim di mana dia menjadi anggota
+		studentID, err := s.studentRepo.GetStudentIDByUserID(ctx, userID)
+		if err != nil {
+			return nil, nil, "", errors.New("error mengambil data mahasiswa: " + err.Error())
+		}
+
+		if ref.StudentID != studentID && achievement.FindTeamMember(studentID) == nil {
+			return nil, nil, "", errors.New("akses ditolak. Anda hanya dapat melihat prestasi milik Anda sendiri")
+		}
+	} else if roleName == "Dosen Wali" {
+		// #5e proses: dosen wali hanya bisa lihat prestasi mahasiswa bimbingan, termasuk prestasi tim dengan anggota bimbingannya
+		lecturer, err := s.userRepo.GetLecturerByUserID(ctx, userID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, nil, "", errors.New("data dosen wali tidak ditemukan. Pastikan user memiliki profil dosen wali")
+			}
+			return nil, nil, "", err
+		}
+
+		isAdvisor, err := s.isAchievementAdvisor(ctx, achievement, ref.StudentID, lecturer.ID)
+		if err != nil {
+			return nil, nil, "", err
+		}
+
+		if !isAdvisor && !s.isEscalatedFallbackVerifier(ctx, ref, lecturer.ID) {
+			return nil, nil, "", errors.New("akses ditolak. Anda hanya dapat melihat prestasi mahasiswa bimbingan Anda")
+		}
+	} else if roleName != "Admin" {
+		return nil, nil, "", errors.New("akses ditolak. Role tidak memiliki akses untuk melihat prestasi")
+	}
+
+	return ref, achievement, roleName, nil
+}
+
+// #6 proses: download file attachment oleh user yang boleh melihat prestasinya
+func (s *AchievementService) GetAttachmentFile(ctx context.Context, userID string, roleID string, mongoID string, key string) (*modelmongo.AttachmentDownload, error) {
+	// #6a proses: validasi akses dengan aturan detail prestasi
+	_, achievement, roleName, err := s.loadViewableAchievement(ctx, userID, roleID, mongoID)
+	if err != nil {
+		return nil, err
+	}
+
+	// #6b proses: buka file dari storage, file yang belum bersih hanya bisa dibuka mahasiswa
+	return s.openAttachment(ctx, achievement, key, roleName)
+}
+
+// #7 proses: buat link download bertanda tangan yang berlaku singkat, untuk ditanam di frontend tanpa header Authorization
+func (s *AchievementService) CreateAttachmentLink(ctx context.Context, userID string, roleID string, mongoID string, key string) (*modelmongo.AttachmentLinkResponse, error) {
+	// #7a proses: validasi akses dengan aturan detail prestasi dan pastikan attachment ada
+	_, achievement, _, err := s.loadViewableAchievement(ctx, userID, roleID, mongoID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, _ := s.findAttachmentFile(achievement, key)
+	if attachment == nil {
+		return nil, errors.New("attachment tidak ditemukan")
+	}
+
+	// #7b proses: link bisa dibagikan ke siapa saja sehingga hanya dibuat untuk file yang sudah lolos scan
+	if err := checkAttachmentScanStatus(attachment, ""); err != nil {
+		return nil, err
+	}
+
+	// #7c proses: tanda tangani ID prestasi, key, dan waktu kedaluwarsa
+	expiresAt := time.Now().Add(s.attachmentConfig.LinkTTL).Truncate(time.Second)
+	expires := expiresAt.Unix()
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.attachmentSignature(mongoID, key, expires))
+
+	return &modelmongo.AttachmentLinkResponse{
+		Status: "success",
+		Data: modelmongo.AttachmentLink{
+			URL:       "/api/v1/attachments/" + url.PathEscape(mongoID) + "/" + url.PathEscape(key) + "?" + query.Encode(),
+			ExpiresAt: expiresAt,
+		},
+	}, nil
+}
+
+// #8 proses: download file attachment lewat link bertanda tangan, tanpa login
+func (s *AchievementService) GetSignedAttachmentFile(ctx context.Context, mongoID string, key string, expires int64, signature string) (*modelmongo.AttachmentDownload, error) {
+	// #8a proses: validasi signature dan masa berlaku, signature dibandingkan dengan waktu konstan
+	expected := s.attachmentSignature(mongoID, key, expires)
+	if !hmac.Equal([]byte(expected), []byte(signature)) || time.Now().Unix() > expires {
+		return nil, errors.New(attachmentLinkInvalidMessage)
+	}
+
+	// #8b proses: prestasi yang sudah dihapus tidak bisa didownload meskipun link masih berlaku
+	achievement, err := s.achievementRepo.GetAchievementByID(ctx, mongoID)
+	if err != nil {
+		return nil, errors.New("error mengambil achievement dari database: " + err.Error())
+	}
+	if achievement == nil {
+		return nil, errors.New("prestasi tidak ditemukan")
+	}
+
+	return s.openAttachment(ctx, achievement, key, "")
+}
+
+// #9 proses: buka file attachment atau preview-nya dari storage, hanya key yang masih tercatat di attachment prestasi dan lolos cek status scan yang bisa dibuka
+func (s *AchievementService) openAttachment(ctx context.Context, achievement *modelmongo.Achievement, key string, roleName string) (*modelmongo.AttachmentDownload, error) {
+	attachment, preview := s.findAttachmentFile(achievement, key)
+	if attachment == nil {
+		return nil, errors.New("attachment tidak ditemukan")
+	}
+
+	if err := checkAttachmentScanStatus(attachment, roleName); err != nil {
+		return nil, err
+	}
+
+	body, info, err := s.fileStorage.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, repositorystorage.ErrFileNotFound) {
+			return nil, errors.New("file attachment tidak ditemukan di storage")
+		}
+		return nil, errors.New("error membaca file attachment: " + err.Error())
+	}
+
+	fileName := attachment.FileName
+	contentType := attachment.FileType
+	if contentType == "" {
+		contentType = info.ContentType
+	}
+	if preview {
+		fileName = strings.TrimSuffix(fileName, filepath.Ext(fileName)) + "-preview.jpg"
+		contentType = attachmentPreviewContentType
+	}
+
+	return &modelmongo.AttachmentDownload{
+		FileName:    fileName,
+		ContentType: contentType,
+		Size:        info.Size,
+		ModTime:     info.ModTime,
+		Body:        body,
+	}, nil
+}
+
+// #10 proses: cari attachment berdasarkan key storage file asli atau key preview-nya, preview bernilai true jika key adalah key preview
+func (s *AchievementService) findAttachmentFile(achievement *modelmongo.Achievement, key string) (*modelmongo.Attachment, bool) {
+	for i := range achievement.Attachments {
+		attachmentKey, ok := s.fileStorage.KeyFromURL(achievement.Attachments[i].FileURL)
+		if !ok {
+			continue
+		}
+		if attachmentKey == key {
+			return &achievement.Attachments[i], false
+		}
+		if achievement.Attachments[i].HasPreview && attachmentPreviewKey(attachmentKey) == key {
+			return &achievement.Attachments[i], true
+		}
+	}
+	return nil, false
+}
+
+// #11 proses: isi DownloadURL dan PreviewURL setiap attachment dengan endpoint download yang memerlukan login, attachment yang dikarantina tidak diberi URL
+func (s *AchievementService) withDownloadURLs(mongoID string, attachments []modelmongo.Attachment) []modelmongo.Attachment {
+	result := make([]modelmongo.Attachment, len(attachments))
+	for i, attachment := range attachments {
+		result[i] = attachment
+		if attachment.ScanStatus == modelmongo.AttachmentScanInfected {
+			continue
+		}
+		if key, ok := s.fileStorage.KeyFromURL(attachment.FileURL); ok {
+			setAttachmentDownloadURLs(&result[i], mongoID, key)
+		}
+	}
+	return result
+}
+
+// #12 proses: path endpoint download attachment, preview memakai endpoint yang sama dengan key preview
+func attachmentDownloadPath(mongoID string, key string) string {
+	return "/api/v1/achievements/" + url.PathEscape(mongoID) + "/attachments/" + url.PathEscape(key)
+}
+
+func setAttachmentDownloadURLs(attachment *modelmongo.Attachment, mongoID string, key string) {
+	attachment.DownloadURL = attachmentDownloadPath(mongoID, key)
+	if attachment.HasPreview {
+		attachment.PreviewURL = attachmentDownloadPath(mongoID, attachmentPreviewKey(key))
+	}
+}
+
+// #13 proses: HMAC-SHA256 dari ID prestasi, key, dan waktu kedaluwarsa link
+func (s *AchievementService) attachmentSignature(mongoID string, key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.attachmentConfig.LinkSecret)
+	mac.Write([]byte(mongoID + "\n" + key + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// #14 proses: cek status scan malware attachment, file infected tidak bisa dibuka siapa pun dan file pending hanya bisa dibuka mahasiswa yang mengupload atau anggota timnya
+func checkAttachmentScanStatus(attachment *modelmongo.Attachment, roleName string) error {
+	switch attachment.ScanStatus {
+	case modelmongo.AttachmentScanInfected:
+		return errors.New("akses ditolak. File attachment terdeteksi malware dan sudah dikarantina")
+	case modelmongo.AttachmentScanPending:
+		if roleName != "Mahasiswa" {
+			return errors.New("akses ditolak. File attachment masih dipindai malware, coba lagi beberapa saat lagi")
+		}
+	}
+	return nil
+}
+
+// #15 proses: validasi ulang nama, ukuran, dan magic byte file, cek kuota storage mahasiswa, lalu simpan ke storage dengan key dari hash SHA-256 isi file, dipakai upload dan ganti attachment. Tipe file dari handler harus sama dengan tipe dari isi file, replacedSize adalah ukuran attachment yang diganti dan tidak dihitung ke kuota
+func (s *AchievementService) storeAttachmentFile(ctx context.Context, studentID string, replacedSize int64, fileName string, fileType string, size int64, content io.Reader) (*storedAttachmentFile, error) {
+	// #15a proses: validasi nama, ukuran, dan magic byte sebelum file disimpan
+	check, reader, err := newAttachmentReader(fileName, size, content)
+	if err != nil {
+		return nil, err
+	}
+	if fileType != "" && fileType != check.FileType {
+		return nil, &modelmongo.AttachmentValidationError{Message: "tipe file " + fileType + " tidak sesuai dengan isi file"}
+	}
+
+	// #15b proses: reservasi kuota dengan ukuran dari client sebelum file dibaca, reservasi dilepas di setiap jalur error dan setelah attachment tercatat
+	quota := s.attachmentConfig.StudentQuota
+	reservationID, err := reserveAttachmentQuota(ctx, s.achievementRepo, quota, studentID, replacedSize, size)
+	if err != nil {
+		return nil, err
+	}
+	reservations := []string{reservationID}
+	done := false
+	defer func() {
+		if !done {
+			releaseAttachmentQuota(ctx, s.achievementRepo, reservations)
+		}
+	}()
+
+	// #15c proses: file yang metadatanya dibersihkan dibaca ke memori (sudah dibatasi ukuran maksimal tipe file) lalu dibersihkan sebelum disimpan, sehingga file asli dengan EXIF, GPS, atau nama penulis tidak pernah masuk storage. Tipe lain langsung dialirkan ke storage
+	var body io.Reader = reader
+	var stripped []byte
+	putSize := size
+	if stripsAttachmentMetadata(check.FileType) {
+		stripped, err = readStrippedAttachment(reader, check.FileType)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(stripped)
+		putSize = int64(len(stripped))
+	}
+
+	// #15d proses: simpan file ke key sementara sambil menghitung hash, key sementara dihapus di setiap jalur error supaya tidak tertinggal di storage
+	tempKey := attachmentStorageKey(check.FileName)
+	hasher := sha256.New()
+	if err := s.fileStorage.Put(ctx, tempKey, io.TeeReader(body, hasher), putSize, check.FileType); err != nil {
+		s.deleteAttachmentFile(ctx, tempKey, false)
+		if reader.err != nil {
+			return nil, reader.err
+		}
+		return nil, errors.New("error menyimpan file: " + err.Error())
+	}
+	if err := reader.finish(); err != nil {
+		s.deleteAttachmentFile(ctx, tempKey, false)
+		return nil, err
+	}
+	contentHash := hex.EncodeToString(hasher.Sum(nil))
+	storedSize := reader.read
+	if stripped != nil {
+		storedSize = putSize
+	}
+
+	// #15e proses: file yang disimpan lebih besar dari ukuran yang direservasi (misalnya DOCX yang dikompres ulang) mereservasi selisihnya
+	if storedSize > size {
+		extraID, err := reserveAttachmentQuota(ctx, s.achievementRepo, quota, studentID, replacedSize, storedSize-size)
+		if err != nil {
+			s.deleteAttachmentFile(ctx, tempKey, false)
+			return nil, err
+		}
+		reservations = append(reservations, extraID)
+	}
+
+	// #15f proses: pindah file ke key hash, jika file dengan isi sama sudah ada maka file tersebut dipakai bersama
+	key := attachmentContentKey(contentHash, check.FileName)
+	if err := s.promoteAttachmentFile(ctx, tempKey, key, storedSize, check.FileType); err != nil {
+		s.deleteAttachmentFile(ctx, tempKey, false)
+		return nil, errors.New("error menyimpan file: " + err.Error())
+	}
+
+	// #15g proses: buat attachment object beserta hash, ukuran, dan preview. Jika scan malware aktif, attachment ditandai pending sampai dipindai scheduler
+	attachment := modelmongo.Attachment{
+		FileName:    check.FileName,
+		FileURL:     s.fileStorage.URL(key),
+		FileType:    check.FileType,
+		ContentHash: contentHash,
+		Size:        storedSize,
+		UploadedAt:  time.Now(),
+		HasPreview:  s.ensureAttachmentPreview(ctx, key, check.FileType),
+	}
+	if s.attachmentConfig.ScanEnabled {
+		attachment.ScanStatus = modelmongo.AttachmentScanPending
+	}
+
+	done = true
+	return &storedAttachmentFile{attachment: attachment, key: key, tempKey: tempKey, reservations: reservations}, nil
+}
+
+// #16 proses: hapus file attachment beserta preview-nya dari storage tanpa mengikuti pembatalan request, gagal hapus hanya dicatat
+func (s *AchievementService) deleteAttachmentFile(ctx context.Context, key string, hasPreview bool) {
+	keys := []string{key}
+	if hasPreview {
+		keys = append(keys, attachmentPreviewKey(key))
+	}
+	for _, k := range keys {
+		if err := s.fileStorage.Delete(context.WithoutCancel(ctx), k); err != nil {
+			fmt.Printf("Error removing attachment file %s: %v\n", k, err)
+		}
+	}
+}
+
+// #17 proses: hapus attachment prestasi oleh mahasiswa pemilik, file di storage ikut dihapus jika tidak dipakai prestasi lain
+func (s *AchievementService) DeleteAttachment(ctx context.Context, userID string, roleID string, mongoID string, attachmentID string) (*modelmongo.AttachmentListResponse, error) {
+	// #17a proses: validasi kepemilikan, status prestasi, dan pastikan attachment ada
+	_, oldAttachment, err := s.loadEditableAttachment(ctx, userID, mongoID, attachmentID, "dihapus")
+	if err != nil {
+		return nil, err
+	}
+
+	// #17b proses: keluarkan attachment dari prestasi, nil berarti attachment sudah dihapus request lain
+	updatedAchievement, err := s.achievementRepo.RemoveAttachment(ctx, mongoID, attachmentID)
+	if err != nil {
+		return nil, errors.New("error menghapus attachment dari prestasi: " + err.Error())
+	}
+	if updatedAchievement == nil {
+		return nil, errors.New("attachment tidak ditemukan")
+	}
+
+	// #17c proses: hitung ulang kunci duplikat karena hash attachment berubah, lalu lepas file lama
+	if err := s.refreshDuplicateKeys(ctx, updatedAchievement); err != nil {
+		return nil, errors.New("error menyimpan kunci duplikat prestasi: " + err.Error())
+	}
+	s.releaseAttachmentFile(ctx, oldAttachment)
+
+	return &modelmongo.AttachmentListResponse{
+		Status: "success",
+		Data:   s.withDownloadURLs(mongoID, updatedAchievement.Attachments),
+	}, nil
+}
+
+// #18 proses: ganti file attachment prestasi oleh mahasiswa pemilik, ID attachment tetap sama dan file lama dihapus dari storage jika tidak dipakai prestasi lain
+func (s *AchievementService) ReplaceAttachment(ctx context.Context, userID string, roleID string, mongoID string, attachmentID string, fileName string, fileType string, size int64, content io.Reader) (*modelmongo.Attachment, error) {
+	// #18a proses: validasi kepemilikan, status prestasi, dan pastikan attachment ada
+	ref, oldAttachment, err := s.loadEditableAttachment(ctx, userID, mongoID, attachmentID, "diganti")
+	if err != nil {
+		return nil, err
+	}
+
+	// #18b proses: validasi dan simpan file baru ke storage, ukuran file lama tidak dihitung ke kuota karena akan diganti
+	stored, err := s.storeAttachmentFile(ctx, ref.StudentID, oldAttachment.Size, fileName, fileType, size, content)
+	if err != nil {
+		return nil, err
+	}
+	attachment := stored.attachment
+	attachment.ID = attachmentID
+
+	// #18c proses: timpa attachment lama, file baru dilepas lagi jika gagal atau attachment sudah dihapus request lain
+	updatedAchievement, err := s.achievementRepo.ReplaceAttachment(ctx, mongoID, attachmentID, attachment)
+	if err != nil {
+		s.discardAttachmentFile(ctx, stored)
+		return nil, errors.New("error mengganti attachment prestasi: " + err.Error())
+	}
+	if updatedAchievement == nil {
+		s.discardAttachmentFile(ctx, stored)
+		return nil, errors.New("attachment tidak ditemukan")
+	}
+	s.commitAttachmentFile(ctx, stored)
+
+	// #18d proses: hitung ulang kunci duplikat karena hash attachment berubah, lalu lepas file lama
+	if err := s.refreshDuplicateKeys(ctx, updatedAchievement); err != nil {
+		return nil, errors.New("error menyimpan kunci duplikat prestasi: " + err.Error())
+	}
+	s.releaseAttachmentFile(ctx, oldAttachment)
+
+	setAttachmentDownloadURLs(&attachment, mongoID, stored.key)
+	return &attachment, nil
+}
+
+// #19 proses: ambil reference prestasi dan salinan attachment yang akan diubah, hanya mahasiswa pemilik dan hanya saat prestasi masih draft atau ditolak untuk direvisi
+func (s *AchievementService) loadEditableAttachment(ctx context.Context, userID string, mongoID string, attachmentID string, action string) (*modelpostgre.AchievementReference, modelmongo.Attachment, error) {
+	// #19a proses: ambil achievement reference untuk validasi
+	ref, err := s.achievementRefRepo.GetAchievementReferenceByMongoID(ctx, mongoID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, modelmongo.Attachment{}, errors.New("prestasi tidak ditemukan")
+		}
+		return nil, modelmongo.Attachment{}, err
+	}
+
+	// #19b proses: validasi ownership, anggota tim tidak bisa mengubah attachment
+	studentID, err := s.studentRepo.GetStudentIDByUserID(ctx, userID)
+	if err != nil {
+		return nil, modelmongo.Attachment{}, errors.New("error mengambil data mahasiswa: " + err.Error())
+	}
+
+	if ref.StudentID != studentID {
+		return nil, modelmongo.Attachment{}, errors.New("akses ditolak. Anda hanya dapat mengubah attachment prestasi milik Anda sendiri")
+	}
+
+	// #19c proses: attachment prestasi yang sedang diverifikasi atau sudah diverifikasi tidak boleh berubah
+	if ref.Status != modelpostgre.AchievementStatusDraft && ref.Status != modelpostgre.AchievementStatusRejected {
+		return nil, modelmongo.Attachment{}, errors.New("attachment hanya dapat " + action + " jika status prestasi adalah draft atau rejected")
+	}
+
+	// #19d proses: ambil achievement dan cari attachment berdasarkan ID
+	achievement, err := s.achievementRepo.GetAchievementByID(ctx, mongoID)
+	if err != nil {
+		return nil, modelmongo.Attachment{}, errors.New("error mengambil achievement dari database: " + err.Error())
+	}
+	if achievement == nil {
+		return nil, modelmongo.Attachment{}, errors.New("prestasi tidak ditemukan")
+	}
+
+	for i := range achievement.Attachments {
+		if achievement.Attachments[i].ID == attachmentID {
+			return ref, achievement.Attachments[i], nil
+		}
+	}
+	return nil, modelmongo.Attachment{}, errors.New("attachment tidak ditemukan")
+}
+
+// #20 proses: hapus file attachment dan preview-nya dari storage jika sudah tidak direferensikan prestasi mana pun. File karantina dan file dari storage lain dibiarkan karena bukan milik storage yang aktif
+func (s *AchievementService) releaseAttachmentFile(ctx context.Context, attachment modelmongo.Attachment) {
+	key, ok := s.fileStorage.KeyFromURL(attachment.FileURL)
+	if !ok {
+		return
+	}
+
+	count, err := s.achievementRepo.CountAttachmentFileReferences(ctx, attachment.FileURL)
+	if err != nil {
+		fmt.Printf("Error counting references to attachment file %s: %v\n", key, err)
+		return
+	}
+	if count > 0 {
+		return
+	}
+
+	s.deleteAttachmentFile(ctx, key, attachment.HasPreview)
+}
+
+// #21 proses: buat preview JPEG dari file yang sudah disimpan lalu simpan di samping file asli. Gagal membuat preview hanya dicatat karena attachment tetap bisa didownload
+func (s *AchievementService) storeAttachmentPreview(ctx context.Context, key string, fileType string) bool {
+	body, _, err := s.fileStorage.Get(ctx, key)
+	if err != nil {
+		fmt.Printf("Error reading attachment %s for preview: %v\n", key, err)
+		return false
+	}
+	preview, err := renderAttachmentPreview(fileType, body)
+	body.Close()
+	if err != nil {
+		fmt.Printf("Error rendering preview for attachment %s: %v\n", key, err)
+		return false
+	}
+	if preview == nil {
+		return false
+	}
+
+	if err := s.fileStorage.Put(ctx, attachmentPreviewKey(key), bytes.NewReader(preview), int64(len(preview)), attachmentPreviewContentType); err != nil {
+		fmt.Printf("Error storing preview for attachment %s: %v\n", key, err)
+		return false
+	}
+	return true
+}
+
+// #22 proses: file attachment yang sudah disimpan dengan key hash, file sementara tetap disimpan sampai attachment tercatat di prestasi
+type storedAttachmentFile struct {
+	attachment   modelmongo.Attachment
+	key          string
+	tempKey      string
+	reservations []string
+}
+
+// #23 proses: salin file sementara ke key hash jika file dengan isi sama belum ada di storage
+func (s *AchievementService) promoteAttachmentFile(ctx context.Context, tempKey string, key string, size int64, contentType string) error {
+	if info, err := s.fileStorage.Stat(ctx, key); err == nil && info.Size == size {
+		return nil
+	}
+
+	body, _, err := s.fileStorage.Get(ctx, tempKey)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	return s.fileStorage.Put(ctx, key, body, size, contentType)
+}
+
+// #24 proses: preview file yang isinya sama dipakai bersama, preview hanya dibuat jika belum ada
+func (s *AchievementService) ensureAttachmentPreview(ctx context.Context, key string, fileType string) bool {
+	if _, err := s.fileStorage.Stat(ctx, attachmentPreviewKey(key)); err == nil {
+		return true
+	}
+	return s.storeAttachmentPreview(ctx, key, fileType)
+}
+
+// #25 proses: setelah attachment tercatat, pastikan file key hash masih ada karena bisa terhapus oleh request lain yang melepas file yang sama sebelum attachment ini tercatat, lalu hapus file sementara
+func (s *AchievementService) commitAttachmentFile(ctx context.Context, stored *storedAttachmentFile) {
+	ctx = context.WithoutCancel(ctx)
+	releaseAttachmentQuota(ctx, s.achievementRepo, stored.reservations)
+	attachment := stored.attachment
+	if err := s.promoteAttachmentFile(ctx, stored.tempKey, stored.key, attachment.Size, attachment.FileType); err != nil {
+		fmt.Printf("Error restoring attachment file %s: %v\n", stored.key, err)
+		return
+	}
+	if attachment.HasPreview {
+		s.ensureAttachmentPreview(ctx, stored.key, attachment.FileType)
+	}
+	s.deleteAttachmentFile(ctx, stored.tempKey, false)
+}
+
+// #26 proses: attachment gagal dicatat, hapus file sementara dan lepas file key hash jika tidak dipakai attachment lain
+func (s *AchievementService) discardAttachmentFile(ctx context.Context, stored *storedAttachmentFile) {
+	releaseAttachmentQuota(ctx, s.achievementRepo, stored.reservations)
+	s.deleteAttachmentFile(ctx, stored.tempKey, false)
+	s.releaseAttachmentFile(context.WithoutCancel(ctx), stored.attachment)
+}
+
+// #27 proses: total ukuran attachment mahasiswa dikurangi ukuran attachment yang akan diganti, tidak dihitung jika kuota tidak dibatasi. Dipakai juga saat upload bertahap dimulai
+func attachmentQuotaUsage(ctx context.Context, achievementRepo repositorymongo.IAchievementRepository, quota int64, studentID string, replacedSize int64) (int64, error) {
+	if quota <= 0 {
+		return 0, nil
+	}
+
+	used, err := achievementRepo.GetStudentAttachmentUsage(ctx, studentID)
+	if err != nil {
+		return 0, errors.New("error menghitung pemakaian storage mahasiswa: " + err.Error())
+	}
+	return max(0, used-replacedSize), nil
+}
+
+// #28 proses: tolak file jika total ukuran attachment mahasiswa melebihi kuota. Ukuran dihitung dari ukuran file yang diupload meskipun isinya sama dengan file yang sudah tersimpan
+func checkAttachmentQuota(quota int64, used int64, size int64) error {
+	if quota <= 0 || used+size <= quota {
+		return nil
+	}
+	return &modelmongo.AttachmentQuotaError{
+		Message: fmt.Sprintf("kuota storage attachment terlampaui, terpakai %s dari %s dan file berukuran %s", formatMegabytes(used), formatMegabytes(quota), formatMegabytes(size)),
+		Used:    used,
+		Quota:   quota,
+	}
+}
+
+// #28a proses: reservasi kuota lalu cek pemakaian, reservasi dicatat lebih dulu baru reservasi lain dan pemakaian dibaca. Dua upload bersamaan pasti saling melihat reservasi, atau yang belakangan melihat attachment yang sudah tercatat karena reservasi baru dilepas setelah attachment tercatat, sehingga total tidak bisa melewati kuota. Return ID reservasi, kosong jika kuota tidak dibatasi
+func reserveAttachmentQuota(ctx context.Context, achievementRepo repositorymongo.IAchievementRepository, quota int64, studentID string, replacedSize int64, size int64) (string, error) {
+	if quota <= 0 {
+		return "", nil
+	}
+
+	now := time.Now()
+	reservationID, err := achievementRepo.ReserveAttachmentQuota(ctx, studentID, size, now.Add(attachmentQuotaReservationTTL))
+	if err != nil {
+		return "", errors.New("error mereservasi kuota storage mahasiswa: " + err.Error())
+	}
+
+	reserved, err := achievementRepo.GetStudentReservedAttachmentQuota(ctx, studentID, reservationID, now)
+	if err != nil {
+		releaseAttachmentQuota(ctx, achievementRepo, []string{reservationID})
+		return "", errors.New("error menghitung reservasi kuota storage mahasiswa: " + err.Error())
+	}
+
+	used, err := attachmentQuotaUsage(ctx, achievementRepo, quota, studentID, replacedSize)
+	if err == nil {
+		err = checkAttachmentQuota(quota, used+reserved, size)
+	}
+	if err != nil {
+		releaseAttachmentQuota(ctx, achievementRepo, []string{reservationID})
+		return "", err
+	}
+	return reservationID, nil
+}
+
+// #28b proses: lepas reservasi kuota tanpa mengikuti pembatalan request, gagal lepas hanya dicatat karena reservasi kedaluwarsa sendiri
+func releaseAttachmentQuota(ctx context.Context, achievementRepo repositorymongo.IAchievementRepository, reservationIDs []string) {
+	for _, id := range reservationIDs {
+		if id == "" {
+			continue
+		}
+		if err := achievementRepo.ReleaseAttachmentQuota(context.WithoutCancel(ctx), id); err != nil {
+			fmt.Printf("Error releasing attachment quota reservation %s: %v\n", id, err)
+		}
+	}
+}
+
+// #29 proses: format ukuran byte dalam MB untuk pesan error
+func formatMegabytes(size int64) string {
+	return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+}
+
+// #30 proses: baca seluruh isi file yang sudah divalidasi lalu bersihkan metadatanya, isi asli dikembalikan jika tidak ada metadata yang dibuang
+func readStrippedAttachment(reader *attachmentReader, fileType string) ([]byte, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		if reader.err != nil {
+			return nil, reader.err
+		}
+		return nil, errors.New("error membaca file: " + err.Error())
+	}
+	if err := reader.finish(); err != nil {
+		return nil, err
+	}
+
+	stripped, err := stripAttachmentMetadata(fileType, data)
+	if err != nil {
+		return nil, err
+	}
+	if stripped != nil {
+		return stripped, nil
+	}
+	return data, nil
+}
@@ -0,0 +1,435 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk bytes, zlib, errors, fmt, io, filepath, model, strings, dan unicode
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	"strings"
+	"unicode"
+)
+
+// #2 proses: tipe file attachment yang diizinkan per ekstensi, dengan batas ukuran dan pengecekan magic byte isi file
+type attachmentFileType struct {
+	mimeType string
+	maxSize  int64
+	magic    func(head []byte) bool
+}
+
+var attachmentFileTypes = map[string]attachmentFileType{
+	".pdf":  {mimeType: "application/pdf", maxSize: 50 * 1024 * 1024, magic: prefixMagic("%PDF-")},
+	".jpg":  {mimeType: "image/jpeg", maxSize: 5 * 1024 * 1024, magic: prefixMagic("\xff\xd8\xff")},
+	".jpeg": {mimeType: "image/jpeg", maxSize: 5 * 1024 * 1024, magic: prefixMagic("\xff\xd8\xff")},
+	".png":  {mimeType: "image/png", maxSize: 5 * 1024 * 1024, magic: prefixMagic("\x89PNG\r\n\x1a\n")},
+	".doc":  {mimeType: "application/msword", maxSize: 10 * 1024 * 1024, magic: prefixMagic("\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1")},
+	".docx": {mimeType: "application/vnd.openxmlformats-officedocument.wordprocessingml.document", maxSize: 10 * 1024 * 1024, magic: prefixMagic("PK\x03\x04")},
+	".mp4":  {mimeType: "video/mp4", maxSize: 250 * 1024 * 1024, magic: offsetMagic(4, "ftyp")},
+}
+
+// #3 proses: jumlah byte awal file yang dibaca untuk pengecekan magic byte dan panjang maksimal nama file tanpa ekstensi
+const (
+	attachmentSniffSize    = 512
+	attachmentMaxNameRunes = 100
+)
+
+// #4 proses: validasi nama, ukuran, dan seluruh isi file attachment, dipakai handler sebelum file diteruskan ke service
+func ValidateAttachmentFile(fileName string, size int64, content io.Reader) (*modelmongo.AttachmentFileCheck, error) {
+	check, reader, err := newAttachmentReader(fileName, size, content)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := reader.finish(); err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+// #5 proses: sanitasi nama file, hanya huruf, angka, titik, dan strip yang dipertahankan sehingga nama aman dipakai sebagai key storage dan header download
+func SanitizeAttachmentFileName(fileName string) string {
+	// #5a proses: buang path yang ikut terkirim dari browser lama, baik separator Unix maupun Windows
+	if i := strings.LastIndexAny(fileName, `/\`); i >= 0 {
+		fileName = fileName[i+1:]
+	}
+
+	ext := filepath.Ext(fileName)
+	base := strings.TrimSuffix(fileName, ext)
+
+	// #5b proses: ganti karakter lain termasuk karakter kontrol dan UTF-8 rusak dengan underscore, underscore berurutan digabung
+	var b strings.Builder
+	lastUnderscore := false
+	runes := 0
+	for _, r := range base {
+		if runes >= attachmentMaxNameRunes {
+			break
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '.' {
+			b.WriteRune(r)
+			lastUnderscore = false
+		} else if !lastUnderscore {
+			b.WriteByte('_')
+			lastUnderscore = true
+		}
+		runes++
+	}
+
+	// #5c proses: nama tidak boleh diawali titik supaya tidak menjadi file tersembunyi, nama kosong diganti "file"
+	name := strings.Trim(b.String(), "._-")
+	if name == "" {
+		name = "file"
+	}
+
+	return name + sanitizeAttachmentExt(ext)
+}
+
+// #6 proses: ekstensi file disimpan dalam huruf kecil, karakter selain huruf dan angka dibuang
+func sanitizeAttachmentExt(ext string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimPrefix(ext, ".")) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return ""
+	}
+	return "." + b.String()
+}
+
+// #7 proses: validasi nama, ukuran yang dikirim client, dan magic byte, lalu kembalikan reader yang memvalidasi sisa isi file sambil dibaca
+func newAttachmentReader(fileName string, size int64, content io.Reader) (*modelmongo.AttachmentFileCheck, *attachmentReader, error) {
+	// #7a proses: validasi nama dan ukuran sebelum isi file dibaca
+	check, err := checkAttachmentFileName(fileName, size)
+	if err != nil {
+		return nil, nil, err
+	}
+	ext := filepath.Ext(check.FileName)
+	fileType := attachmentFileTypes[ext]
+
+	// #7c proses: baca byte awal file untuk dicocokkan dengan magic byte tipe file dari ekstensi
+	head := make([]byte, attachmentSniffSize)
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, nil, errors.New("error membaca file: " + err.Error())
+	}
+	head = head[:n]
+
+	if n == 0 {
+		return nil, nil, &modelmongo.AttachmentValidationError{Message: "file kosong"}
+	}
+	if !fileType.magic(head) {
+		return nil, nil, &modelmongo.AttachmentValidationError{Message: "isi file tidak sesuai dengan ekstensi " + ext}
+	}
+
+	reader := &attachmentReader{
+		content: io.MultiReader(bytes.NewReader(head), content),
+		ext:     ext,
+		limit:   fileType.maxSize,
+	}
+	if ext == ".pdf" {
+		reader.pdf = &pdfNameScanner{}
+	}
+
+	return check, reader, nil
+}
+
+// #7b proses: tipe file ditentukan dari ekstensi nama file yang sudah disanitasi dan ukuran yang dikirim client ditolak sebelum isi file dibaca, dipakai juga saat upload bertahap dimulai
+func checkAttachmentFileName(fileName string, size int64) (*modelmongo.AttachmentFileCheck, error) {
+	name := SanitizeAttachmentFileName(fileName)
+	ext := filepath.Ext(name)
+	fileType, ok := attachmentFileTypes[ext]
+	if !ok {
+		return nil, &modelmongo.AttachmentValidationError{Message: "tipe file tidak diizinkan. Gunakan PDF, JPG, PNG, DOC, DOCX, atau MP4"}
+	}
+
+	if size > fileType.maxSize {
+		return nil, attachmentTooLargeError(ext, fileType.maxSize)
+	}
+
+	return &modelmongo.AttachmentFileCheck{
+		FileName: name,
+		FileType: fileType.mimeType,
+		MaxSize:  fileType.maxSize,
+	}, nil
+}
+
+// #8 proses: reader isi file attachment yang berhenti dengan error validasi jika ukuran melebihi batas atau PDF berisi JavaScript, sehingga file tidak perlu dibuffer seluruhnya
+type attachmentReader struct {
+	content io.Reader
+	ext     string
+	limit   int64
+	read    int64
+	pdf     *pdfNameScanner
+	err     error
+}
+
+func (r *attachmentReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	n, err := r.content.Read(p)
+	r.read += int64(n)
+	if r.read > r.limit {
+		r.err = attachmentTooLargeError(r.ext, r.limit)
+		return 0, r.err
+	}
+
+	if r.pdf != nil {
+		r.pdf.Write(p[:n])
+		if err == io.EOF {
+			r.pdf.close()
+		}
+		if pdfErr := r.pdf.result(); pdfErr != nil {
+			r.err = pdfErr
+			return 0, r.err
+		}
+	}
+
+	return n, err
+}
+
+// #9 proses: baca sisa isi file yang belum dibaca lalu kembalikan hasil validasi, dipanggil setelah storage selesai membaca karena storage bisa berhenti tepat di ukuran file tanpa membaca EOF
+func (r *attachmentReader) finish() error {
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		return err
+	}
+	if r.pdf != nil {
+		r.pdf.close()
+		r.err = r.pdf.result()
+	}
+	return r.err
+}
+
+// #10 proses: scanner nama PDF (token yang diawali "/") secara streaming untuk mencari action /JS dan name tree /JavaScript, escape #xx didekode supaya /J#61vaScript tetap terdeteksi. Isi object stream (/ObjStm) yang dikompres FlateDecode ditampung lalu di-inflate dan dipindai juga
+type pdfNameScanner struct {
+	inName   bool
+	name     []byte
+	overflow bool
+	hex      int
+	hexValue byte
+	found    string
+	err      error
+
+	objStm    bool
+	filters   []string
+	keyword   []byte
+	objStream *bytes.Buffer
+}
+
+// #10a proses: nama yang lebih panjang dari batas ini pasti bukan /JS atau /JavaScript sehingga tidak perlu disimpan utuh, dan batas ukuran object stream sebelum dan sesudah di-inflate supaya zip bomb tidak menghabiskan memori
+const (
+	pdfMaxNameLength       = 16
+	pdfMaxObjectStreamSize = 10 * 1024 * 1024
+	pdfMaxStreamFilters    = 4
+)
+
+func (s *pdfNameScanner) Write(p []byte) {
+	for _, c := range p {
+		s.trackStream(c)
+
+		// #10b proses: lanjutkan escape #xx, karakter bukan hex membatalkan escape dan diproses seperti biasa
+		if s.hex > 0 {
+			if v, ok := hexDigitValue(c); ok {
+				s.hexValue = s.hexValue<<4 | v
+				s.hex--
+				if s.hex == 0 {
+					s.appendName(s.hexValue)
+				}
+				continue
+			}
+			s.hex = 0
+		}
+
+		if s.inName && !isPDFDelimiter(c) {
+			if c == '#' {
+				s.hex = 2
+				s.hexValue = 0
+				continue
+			}
+			s.appendName(c)
+			continue
+		}
+
+		// #10c proses: delimiter menutup nama, "/" sekaligus membuka nama berikutnya
+		if s.inName {
+			s.finishName()
+		}
+		if c == '/' {
+			s.inName = true
+			s.name = s.name[:0]
+			s.overflow = false
+		}
+	}
+}
+
+func (s *pdfNameScanner) appendName(c byte) {
+	if len(s.name) >= pdfMaxNameLength {
+		s.overflow = true
+		return
+	}
+	s.name = append(s.name, c)
+}
+
+func (s *pdfNameScanner) finishName() {
+	if s.inName && !s.overflow {
+		switch name := string(s.name); {
+		case name == "JS" || name == "JavaScript":
+			if s.found == "" {
+				s.found = name
+			}
+		case name == "ObjStm":
+			s.objStm = true
+		case strings.HasSuffix(name, "Decode") && len(s.filters) < pdfMaxStreamFilters:
+			s.filters = append(s.filters, name)
+		}
+	}
+	s.inName = false
+	s.hex = 0
+}
+
+// #10d proses: keyword stream setelah dictionary /ObjStm membuka isi object stream. Isi ditampung sampai endstream, object stream tanpa filter sudah ikut terpindai sebagai teks biasa
+func (s *pdfNameScanner) trackStream(c byte) {
+	if s.objStream != nil {
+		if s.objStream.Len() == 0 && (c == '\r' || c == '\n') {
+			return
+		}
+		s.objStream.WriteByte(c)
+		if data := s.objStream.Bytes(); bytes.HasSuffix(data, []byte("endstream")) {
+			s.objStream = nil
+			s.scanObjectStream(data[:len(data)-len("endstream")])
+		} else if len(data) > pdfMaxObjectStreamSize {
+			s.objStream = nil
+			s.fail("object stream PDF terlalu besar untuk diperiksa")
+		}
+		return
+	}
+
+	s.keyword = append(s.keyword, c)
+	if len(s.keyword) > len("endstream") {
+		s.keyword = s.keyword[1:]
+	}
+	if !bytes.HasSuffix(s.keyword, []byte("stream")) || bytes.HasSuffix(s.keyword, []byte("endstream")) {
+		return
+	}
+
+	// #10e proses: hanya FlateDecode tunggal yang bisa di-inflate, filter lain pada object stream membuat isinya tidak bisa diperiksa sehingga file ditolak
+	if s.objStm {
+		switch {
+		case len(s.filters) == 1 && s.filters[0] == "FlateDecode":
+			s.objStream = &bytes.Buffer{}
+		case len(s.filters) > 0:
+			s.fail("object stream PDF memakai filter yang tidak didukung")
+		}
+	}
+	s.objStm = false
+	s.filters = s.filters[:0]
+}
+
+// #10f proses: inflate isi object stream dengan batas ukuran lalu pindai nama di dalamnya, object stream yang rusak atau terenkripsi tidak bisa diperiksa sehingga file ditolak
+func (s *pdfNameScanner) scanObjectStream(data []byte) {
+	reader, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		s.fail("object stream PDF tidak dapat dibaca")
+		return
+	}
+	defer reader.Close()
+
+	inner := &pdfNameScanner{}
+	buf := make([]byte, 32*1024)
+	total := 0
+	for {
+		n, err := reader.Read(buf)
+		total += n
+		if total > pdfMaxObjectStreamSize {
+			s.fail("object stream PDF terlalu besar untuk diperiksa")
+			return
+		}
+		inner.Write(buf[:n])
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			s.fail("object stream PDF tidak dapat dibaca")
+			return
+		}
+	}
+	inner.finishName()
+
+	if inner.found != "" && s.found == "" {
+		s.found = inner.found
+	}
+}
+
+func (s *pdfNameScanner) fail(message string) {
+	if s.err == nil {
+		s.err = &modelmongo.AttachmentValidationError{Message: message}
+	}
+}
+
+// #10g proses: tutup scanner di akhir file, object stream tanpa endstream tetap dipindai sebisanya
+func (s *pdfNameScanner) close() {
+	s.finishName()
+	if s.objStream != nil {
+		data := s.objStream.Bytes()
+		s.objStream = nil
+		s.scanObjectStream(data)
+	}
+}
+
+// #10h proses: hasil pemindaian, JavaScript didahulukan dari error object stream
+func (s *pdfNameScanner) result() error {
+	if s.found != "" {
+		return pdfJavaScriptError(s.found)
+	}
+	return s.err
+}
+
+// #11 proses: helper pengecekan magic byte di awal file atau di offset tertentu (MP4 menyimpan box ftyp setelah 4 byte ukuran box), karakter delimiter PDF, dan digit hex
+func prefixMagic(prefix string) func(head []byte) bool {
+	return func(head []byte) bool {
+		return bytes.HasPrefix(head, []byte(prefix))
+	}
+}
+
+func offsetMagic(offset int, magic string) func(head []byte) bool {
+	return func(head []byte) bool {
+		return len(head) >= offset && bytes.HasPrefix(head[offset:], []byte(magic))
+	}
+}
+
+func isPDFDelimiter(c byte) bool {
+	switch c {
+	case 0, '\t', '\n', '\f', '\r', ' ', '(', ')', '<', '>', '[', ']', '{', '}', '/', '%':
+		return true
+	}
+	return false
+}
+
+func hexDigitValue(c byte) (byte, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	}
+	return 0, false
+}
+
+// #12 proses: error validasi ukuran file dan PDF berisi JavaScript
+func attachmentTooLargeError(ext string, maxSize int64) error {
+	return &modelmongo.AttachmentValidationError{
+		Message:  fmt.Sprintf("ukuran file %s melebihi batas %d MB", strings.ToUpper(strings.TrimPrefix(ext, ".")), maxSize/(1024*1024)),
+		TooLarge: true,
+	}
+}
+
+func pdfJavaScriptError(name string) error {
+	return &modelmongo.AttachmentValidationError{Message: "file PDF berisi JavaScript (/" + name + ") dan tidak dapat diupload"}
+}
@@ -0,0 +1,206 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk context, model, repository, sort, strings, dan unicode
+import (
+	"context"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// #2 proses: batas jumlah kandidat duplikat yang dicek per prestasi
+const duplicateCandidateLimit = 10
+
+// #3 proses: normalisasi teks untuk pencocokan duplikat, huruf kecil, tanda baca dihapus, spasi dirapikan
+func NormalizeDuplicateText(value string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(value) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+		} else {
+			builder.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// #4 proses: bangun kunci duplikat dari judul, nama kompetisi dan tanggal event, nomor sertifikat, serta hash attachment
+func BuildDuplicateKeys(achievement *modelmongo.Achievement) []string {
+	keys := []string{}
+	seen := map[string]bool{}
+	addKey := func(key string) {
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	// #4a proses: judul hanya dicocokkan dengan prestasi milik mahasiswa yang sama karena judul umum seperti "Juara 1" sering dipakai
+	if title := NormalizeDuplicateText(achievement.Title); title != "" && achievement.StudentID != "" {
+		addKey(modelmongo.DuplicateMatchTitle + ":" + achievement.StudentID + ":" + title)
+	}
+
+	// #4b proses: nama kompetisi dan tanggal event dicocokkan lintas mahasiswa untuk menangkap anggota tim yang submit kemenangan yang sama
+	details := achievement.Details
+	if details.CompetitionName != nil && details.EventDate != nil {
+		if name := NormalizeDuplicateText(*details.CompetitionName); name != "" {
+			addKey(modelmongo.DuplicateMatchCompetition + ":" + name + ":" + details.EventDate.Format("2006-01-02"))
+		}
+	}
+
+	// #4c proses: nomor sertifikat dicocokkan tanpa spasi dan tanda baca
+	if details.CertificationNumber != nil {
+		if number := strings.ReplaceAll(NormalizeDuplicateText(*details.CertificationNumber), " ", ""); number != "" {
+			addKey(modelmongo.DuplicateMatchCertificationNumber + ":" + number)
+		}
+	}
+
+	// #4d proses: hash isi file attachment dicocokkan persis
+	for _, attachment := range achievement.Attachments {
+		if attachment.ContentHash != "" {
+			addKey(modelmongo.DuplicateMatchAttachment + ":" + strings.ToLower(attachment.ContentHash))
+		}
+	}
+
+	return keys
+}
+
+// #5 proses: pecah kunci duplikat jadi jenis kecocokan dan nilai yang dicocokkan
+func parseDuplicateKey(key string) (string, string) {
+	parts := strings.SplitN(key, ":", 2)
+	if len(parts) != 2 {
+		return key, ""
+	}
+
+	// #5a proses: kunci judul menyimpan student ID sebelum judul, ambil judulnya saja
+	if parts[0] == modelmongo.DuplicateMatchTitle {
+		if titleParts := strings.SplitN(parts[1], ":", 2); len(titleParts) == 2 {
+			return parts[0], titleParts[1]
+		}
+	}
+
+	return parts[0], parts[1]
+}
+
+// #6 proses: bangun kandidat duplikat dari achievement lain yang memiliki kunci sama, status diambil dari reference PostgreSQL
+func buildDuplicateCandidates(
+	refs map[string]modelpostgre.AchievementReference,
+	keys []string,
+	matches []modelmongo.Achievement,
+	studentID string,
+	redactOtherStudents bool,
+) []modelmongo.DuplicateCandidate {
+	keySet := map[string]bool{}
+	for _, key := range keys {
+		keySet[key] = true
+	}
+
+	candidates := []modelmongo.DuplicateCandidate{}
+	for _, match := range matches {
+		// #6a proses: tentukan jenis kecocokan dari irisan kunci duplikat
+		matchedOn := []string{}
+		seen := map[string]bool{}
+		for _, key := range match.DuplicateKeys {
+			if !keySet[key] {
+				continue
+			}
+			matchType, _ := parseDuplicateKey(key)
+			if !seen[matchType] {
+				seen[matchType] = true
+				matchedOn = append(matchedOn, matchType)
+			}
+		}
+		if len(matchedOn) == 0 {
+			continue
+		}
+		sort.Strings(matchedOn)
+
+		// #6b proses: ambil status dari reference, lewati prestasi yang sudah dihapus
+		status := ""
+		if ref, ok := refs[match.ID.Hex()]; ok {
+			if ref.Status == modelpostgre.AchievementStatusDeleted {
+				continue
+			}
+			status = ref.Status
+		}
+
+		sameStudent := match.StudentID == studentID
+
+		// #6c proses: mahasiswa tidak boleh melihat detail prestasi milik mahasiswa lain, hanya jenis kecocokan
+		if redactOtherStudents && !sameStudent {
+			candidates = append(candidates, modelmongo.DuplicateCandidate{
+				AchievementType: match.AchievementType,
+				MatchedOn:       matchedOn,
+				SameStudent:     false,
+			})
+			continue
+		}
+
+		candidates = append(candidates, modelmongo.DuplicateCandidate{
+			AchievementID:   match.ID.Hex(),
+			StudentID:       match.StudentID,
+			Title:           match.Title,
+			AchievementType: match.AchievementType,
+			Status:          status,
+			MatchedOn:       matchedOn,
+			SameStudent:     sameStudent,
+		})
+	}
+
+	return candidates
+}
+
+// #6d proses: ambil reference semua kandidat duplikat dalam satu query, dipetakan berdasarkan mongo achievement ID
+func loadDuplicateReferences(ctx context.Context, achievementRefRepo repositorypostgre.IAchievementReferenceRepository, ids []string) (map[string]modelpostgre.AchievementReference, error) {
+	refs := make(map[string]modelpostgre.AchievementReference, len(ids))
+	if len(ids) == 0 {
+		return refs, nil
+	}
+
+	references, err := achievementRefRepo.GetAchievementReferencesByMongoIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range references {
+		refs[ref.MongoAchievementID] = ref
+	}
+	return refs, nil
+}
+
+// #7 proses: cari kandidat duplikat untuk satu achievement, error pencarian tidak menggagalkan proses utama
+func (s *AchievementService) findDuplicateCandidates(ctx context.Context, achievement *modelmongo.Achievement, redactOtherStudents bool) []modelmongo.DuplicateCandidate {
+	keys := achievement.DuplicateKeys
+	if len(keys) == 0 {
+		keys = BuildDuplicateKeys(achievement)
+	}
+
+	matches, err := s.achievementRepo.FindAchievementsByDuplicateKeys(ctx, achievement.ID.Hex(), keys, duplicateCandidateLimit)
+	if err != nil || len(matches) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(matches))
+	for _, match := range matches {
+		ids = append(ids, match.ID.Hex())
+	}
+	refs, err := loadDuplicateReferences(ctx, s.achievementRefRepo, ids)
+	if err != nil {
+		return nil
+	}
+
+	candidates := buildDuplicateCandidates(refs, keys, matches, achievement.StudentID, redactOtherStudents)
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	return candidates
+}
+
+// #8 proses: hitung ulang dan simpan kunci duplikat achievement, dipanggil setiap kali data pembanding berubah
+func (s *AchievementService) refreshDuplicateKeys(ctx context.Context, achievement *modelmongo.Achievement) error {
+	achievement.DuplicateKeys = BuildDuplicateKeys(achievement)
+	return s.achievementRepo.UpdateDuplicateKeys(ctx, achievement.ID.Hex(), achievement.DuplicateKeys)
+}
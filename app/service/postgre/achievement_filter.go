@@ -0,0 +1,397 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk context, database, errors, model, repository, slices, sort, strings, dan time
+import (
+	"context"
+	"database/sql"
+	"errors"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// #2 proses: field sorting yang ada di MongoDB, field lain termasuk created_at diurutkan oleh query PostgreSQL supaya urutan sama dengan keyset cursor
+var mongoSortFields = map[string]string{
+	"points": "points",
+	"title":  "title",
+}
+
+// #2a proses: jumlah reference per batch saat menghitung total dan facet sekaligus batas ukuran batch saat mencari halaman, ID satu batch dikirim ke MongoDB sehingga ukuran query tetap kecil berapa pun jumlah prestasi
+const achievementBatchSize = 1000
+
+// #2b proses: jumlah maksimal nilai facet tags yang ditampilkan
+const achievementTagFacetLimit = 20
+
+// #2c proses: jumlah maksimal reference yang dibaca satu request mode cursor, sisanya dilanjutkan lewat next_cursor
+const achievementCursorScanLimit = 5000
+
+// #3 proses: ubah filter daftar prestasi menjadi filter MongoDB untuk satu batch ID hasil PostgreSQL
+func achievementFilterForMongo(filter modelpostgre.AchievementListFilter, ids []string, soonDays int) modelmongo.AchievementFilter {
+	mongoFilter := modelmongo.AchievementFilter{
+		IDs:                   ids,
+		AchievementTypes:      filter.AchievementTypes,
+		CompetitionLevels:     filter.CompetitionLevels,
+		Tags:                  filter.Tags,
+		DateFrom:              filter.DateFrom,
+		MinPoints:             filter.MinPoints,
+		MaxPoints:             filter.MaxPoints,
+		HasAttachments:        filter.HasAttachments,
+		CertificationStatuses: filter.CertificationStatuses,
+		CertificationSoonDays: soonDays,
+		Now:                   time.Now(),
+		SortField:             mongoSortFields[filter.SortBy],
+		SortDescending:        filter.SortOrder != "ASC",
+	}
+
+	// #3a proses: dateTo inklusif satu hari penuh, di MongoDB dipakai sebagai batas eksklusif hari berikutnya
+	if filter.DateTo != nil {
+		dateTo := dateOnly(*filter.DateTo).AddDate(0, 0, 1)
+		mongoFilter.DateTo = &dateTo
+	}
+
+	return mongoFilter
+}
+
+// #3b proses: cek apakah ada filter yang dijalankan di MongoDB, tanpa filter MongoDB setiap reference yang matchedSet PostgreSQL ikut tampil
+func hasMongoFilters(filter modelpostgre.AchievementListFilter) bool {
+	return len(filter.AchievementTypes) > 0 || len(filter.CompetitionLevels) > 0 || len(filter.Tags) > 0 ||
+		filter.DateFrom != nil || filter.DateTo != nil || filter.MinPoints != nil || filter.MaxPoints != nil ||
+		filter.HasAttachments != nil || len(filter.CertificationStatuses) > 0
+}
+
+// #4 proses: penghitung facet yang menjumlahkan hasil beberapa batch, semua dimensi selalu ada di response walaupun kosong
+type achievementFacetCounter struct {
+	counts       map[string]map[string]int
+	advisorNames map[string]string
+}
+
+// #4a proses: constructor penghitung facet dengan semua dimensi PostgreSQL dan MongoDB
+func newAchievementFacetCounter() *achievementFacetCounter {
+	counter := &achievementFacetCounter{counts: map[string]map[string]int{}, advisorNames: map[string]string{}}
+	for _, name := range []string{
+		modelpostgre.FacetStatus, modelpostgre.FacetProgramStudy, modelpostgre.FacetAcademicYear, modelpostgre.FacetAdvisor,
+		modelmongo.FacetAchievementType, modelmongo.FacetCompetitionLevel, modelmongo.FacetTags, modelmongo.FacetHasAttachments,
+		modelmongo.FacetPoints, modelmongo.FacetYear, modelmongo.FacetCertificationStatus,
+	} {
+		counter.counts[name] = map[string]int{}
+	}
+	return counter
+}
+
+// #4b proses: tambahkan facet MongoDB satu batch
+func (c *achievementFacetCounter) addMongoFacets(facets map[string][]modelmongo.FacetCount) {
+	for name, counts := range facets {
+		if c.counts[name] == nil {
+			continue
+		}
+		for _, count := range counts {
+			c.counts[name][count.Value] += count.Count
+		}
+	}
+}
+
+// #4c proses: hitung satu reference di facet PostgreSQL, only diisi jika reference hanya dihitung di dimensi yang filternya tidak matchedSet
+func (c *achievementFacetCounter) addReference(row modelpostgre.AchievementListRow, only string) {
+	values := map[string]string{
+		modelpostgre.FacetStatus:       row.Status,
+		modelpostgre.FacetProgramStudy: row.ProgramStudy,
+		modelpostgre.FacetAcademicYear: row.AcademicYear,
+		modelpostgre.FacetAdvisor:      row.AdvisorID,
+	}
+	for name, value := range values {
+		if only == "" || only == name {
+			c.counts[name][value]++
+		}
+	}
+	c.advisorNames[row.AdvisorID] = row.AdvisorName
+}
+
+// #4d proses: susun facet untuk response
+func (c *achievementFacetCounter) facets() map[string][]modelmongo.FacetCount {
+	facets := make(map[string][]modelmongo.FacetCount, len(c.counts))
+	for name, values := range c.counts {
+		facet := []modelmongo.FacetCount{}
+		for value, count := range values {
+			// #4e proses: nilai kosong (misalnya mahasiswa tanpa dosen wali) tidak ditampilkan sebagai pilihan filter
+			if value == "" {
+				continue
+			}
+			item := modelmongo.FacetCount{Value: value, Count: count}
+			if name == modelpostgre.FacetAdvisor {
+				item.Label = c.advisorNames[value]
+			}
+			facet = append(facet, item)
+		}
+
+		// #4f proses: urutkan dari jumlah terbanyak, nilai sama diurutkan alfabetis supaya stabil. Tags dibatasi setelah semua batch dijumlahkan
+		sort.Slice(facet, func(i, j int) bool {
+			if facet[i].Count != facet[j].Count {
+				return facet[i].Count > facet[j].Count
+			}
+			return facet[i].Value < facet[j].Value
+		})
+		if name == modelmongo.FacetTags && len(facet) > achievementTagFacetLimit {
+			facet = facet[:achievementTagFacetLimit]
+		}
+		facets[name] = facet
+	}
+
+	return facets
+}
+
+// #4g proses: dimensi facet PostgreSQL yang filternya tidak matchedSet untuk satu reference
+func failedReferenceDimensions(filter modelpostgre.AchievementListFilter, row modelpostgre.AchievementListRow) []string {
+	failed := []string{}
+	if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, row.Status) {
+		failed = append(failed, modelpostgre.FacetStatus)
+	}
+	if len(filter.ProgramStudies) > 0 && !slices.Contains(filter.ProgramStudies, row.ProgramStudy) {
+		failed = append(failed, modelpostgre.FacetProgramStudy)
+	}
+	if len(filter.AcademicYears) > 0 && !slices.Contains(filter.AcademicYears, row.AcademicYear) {
+		failed = append(failed, modelpostgre.FacetAcademicYear)
+	}
+	if len(filter.AdvisorIDs) > 0 && !slices.Contains(filter.AdvisorIDs, row.AdvisorID) {
+		failed = append(failed, modelpostgre.FacetAdvisor)
+	}
+	return failed
+}
+
+// #5 proses: cursor hanya bisa dipakai dengan urutan created_at karena keyset memakai created_at dan mongo_achievement_id
+func isCursorSortable(sortBy string) bool {
+	return sortBy == "" || sortBy == "created_at"
+}
+
+// #6 proses: batasi filter daftar prestasi berdasarkan role user, Mahasiswa hanya milik sendiri dan Dosen Wali hanya mahasiswa bimbingan. Dipakai daftar prestasi dan export attachment, return nama role
+func scopeAchievementListFilter(ctx context.Context, userRepo repositorypostgre.IUserRepository, studentRepo repositorypostgre.IStudentRepository, userID string, roleID string, filter *modelpostgre.AchievementListFilter) (string, error) {
+	// #6a proses: ambil role name untuk menentukan batas data yang boleh dilihat
+	roleName, err := userRepo.GetRoleName(ctx, roleID)
+	if err != nil {
+		return "", errors.New("error mengambil role name: " + err.Error())
+	}
+
+	filter.ScopeStudentID = ""
+	filter.ScopeAdvisorID = ""
+	if roleName == "Mahasiswa" {
+		// #6b proses: ambil student untuk batas references milik student
+		student, err := studentRepo.GetStudentByUserID(ctx, userID)
+		if err != nil {
+			return "", errors.New("error mengambil data student: " + err.Error())
+		}
+		filter.ScopeStudentID = student.ID
+	} else if roleName == "Dosen Wali" {
+		// #6c proses: ambil lecturer untuk batas references dari mahasiswa bimbingan
+		lecturer, err := userRepo.GetLecturerByUserID(ctx, userID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return "", errors.New("data dosen wali tidak ditemukan. Pastikan user memiliki profil dosen wali")
+			}
+			return "", err
+		}
+		filter.ScopeAdvisorID = lecturer.ID
+	} else if roleName != "Admin" {
+		return "", errors.New("akses ditolak. Role tidak memiliki akses untuk melihat prestasi")
+	}
+
+	return roleName, nil
+}
+
+// #7 proses: ambil reference satu halaman sesuai urutan PostgreSQL, mode cursor maju dengan keyset dan mode halaman dengan offset. Tanpa filter MongoDB skip dan limit langsung dipakai di query PostgreSQL, dengan filter MongoDB setiap batch disaring di MongoDB sampai terkumpul skip+want reference atau reference habis. Ukuran batch berlipat dua sampai achievementBatchSize supaya filter yang jarang cocok tidak membaca reference sedikit demi sedikit. Mode cursor berhenti setelah achievementCursorScanLimit reference dan mengembalikan posisi reference terakhir yang dibaca untuk dilanjutkan request berikutnya
+func (s *AchievementService) getAchievementPageRows(ctx context.Context, filter modelpostgre.AchievementListFilter, skip int, want int) ([]modelpostgre.AchievementListRow, *modelpostgre.PageCursor, error) {
+	if !hasMongoFilters(filter) {
+		filter.RowOffset = skip
+		filter.RowLimit = want
+		rows, err := s.achievementRefRepo.GetAchievementListRows(ctx, filter)
+		if err != nil {
+			return nil, nil, errors.New("error mengambil achievement references: " + err.Error())
+		}
+		return rows, nil, nil
+	}
+
+	filter.RowLimit = min(skip+want, achievementBatchSize)
+	rows := []modelpostgre.AchievementListRow{}
+	matched := 0
+	scanned := 0
+	for len(rows) < want {
+		// #7a proses: mode cursor yang sudah membaca batas reference berhenti di reference terakhir yang dibaca
+		if filter.CursorMode && scanned >= achievementCursorScanLimit {
+			return rows, filter.Cursor, nil
+		}
+
+		// #7b proses: ambil batch reference setelah cursor atau offset terakhir
+		batch, err := s.achievementRefRepo.GetAchievementListRows(ctx, filter)
+		if err != nil {
+			return nil, nil, errors.New("error mengambil achievement references: " + err.Error())
+		}
+		if len(batch) == 0 {
+			break
+		}
+		scanned += len(batch)
+
+		// #7c proses: saring ID batch dengan filter MongoDB, urutan tetap mengikuti batch PostgreSQL
+		ids := make([]string, 0, len(batch))
+		for _, row := range batch {
+			ids = append(ids, row.MongoAchievementID)
+		}
+		matchedIDs, err := s.achievementRepo.GetMatchingAchievementIDs(ctx, achievementFilterForMongo(filter, ids, certificationSoonDays(s.certificationConfig)))
+		if err != nil {
+			return nil, nil, errors.New("error menyaring achievements di MongoDB: " + err.Error())
+		}
+		matchedSet := make(map[string]bool, len(matchedIDs))
+		for _, id := range matchedIDs {
+			matchedSet[id] = true
+		}
+		for _, row := range batch {
+			if !matchedSet[row.MongoAchievementID] || len(rows) == want {
+				continue
+			}
+			if matched >= skip {
+				rows = append(rows, row)
+			}
+			matched++
+		}
+
+		// #7d proses: batch yang kurang dari LIMIT berarti reference sudah habis, selain itu maju ke baris terakhir batch dan perbesar batch berikutnya
+		if len(batch) < filter.RowLimit {
+			break
+		}
+		last := batch[len(batch)-1]
+		if filter.CursorMode {
+			filter.Cursor = &modelpostgre.PageCursor{CreatedAt: last.CreatedAt, ID: last.MongoAchievementID}
+		} else {
+			filter.RowOffset += len(batch)
+		}
+		filter.RowLimit = min(filter.RowLimit*2, achievementBatchSize)
+	}
+
+	return rows, nil, nil
+}
+
+// #8 proses: hasil hitung total dan facet, PageRows hanya diisi untuk sort field MongoDB
+type achievementFacetScan struct {
+	Total    int
+	Facets   map[string][]modelmongo.FacetCount
+	PageRows []modelpostgre.AchievementListRow
+}
+
+// #8a proses: kandidat urutan sort field MongoDB beserta reference-nya
+type achievementSortCandidate struct {
+	achievement modelmongo.Achievement
+	row         modelpostgre.AchievementListRow
+}
+
+// #9 proses: hitung total dan facet dari semua reference dalam scope per batch keyset. Reference yang matchedSet semua filter PostgreSQL dihitung di facet MongoDB dengan filter dimensi masing-masing dikecualikan, reference yang gagal tepat satu dimensi PostgreSQL hanya dihitung di facet dimensi itu. Sort field MongoDB sekaligus mengumpulkan kandidat teratas setiap batch untuk halaman
+func (s *AchievementService) getAchievementFacets(ctx context.Context, filter modelpostgre.AchievementListFilter) (*achievementFacetScan, error) {
+	soonDays := certificationSoonDays(s.certificationConfig)
+	sortField := mongoSortFields[filter.SortBy]
+	want := filter.Page * filter.Limit
+	counter := newAchievementFacetCounter()
+	scan := &achievementFacetScan{}
+	candidates := []achievementSortCandidate{}
+
+	var after *modelpostgre.PageCursor
+	for {
+		// #9a proses: ambil batch reference setelah batch sebelumnya
+		batch, err := s.achievementRefRepo.GetAchievementFacetRows(ctx, filter, after, achievementBatchSize)
+		if err != nil {
+			return nil, errors.New("error mengambil achievement references: " + err.Error())
+		}
+		if len(batch) == 0 {
+			break
+		}
+
+		// #9b proses: pisahkan reference yang matchedSet semua filter PostgreSQL dan yang gagal tepat satu dimensi
+		rowMap := make(map[string]modelpostgre.AchievementListRow, len(batch))
+		passedIDs := []string{}
+		nearIDs := []string{}
+		nearDimension := map[string]string{}
+		for _, row := range batch {
+			rowMap[row.MongoAchievementID] = row
+			switch failed := failedReferenceDimensions(filter, row); len(failed) {
+			case 0:
+				passedIDs = append(passedIDs, row.MongoAchievementID)
+			case 1:
+				nearIDs = append(nearIDs, row.MongoAchievementID)
+				nearDimension[row.MongoAchievementID] = failed[0]
+			}
+		}
+
+		// #9c proses: total, facet MongoDB, dan facet PostgreSQL dari reference yang matchedSet semua filter
+		if len(passedIDs) > 0 {
+			mongoFilter := achievementFilterForMongo(filter, passedIDs, soonDays)
+			if sortField != "" {
+				mongoFilter.Limit = want
+			}
+			result, err := s.achievementRepo.GetAchievementFacets(ctx, mongoFilter)
+			if err != nil {
+				return nil, errors.New("error menghitung facet achievements di MongoDB: " + err.Error())
+			}
+			scan.Total += result.Total
+			counter.addMongoFacets(result.Facets)
+			for _, id := range result.MatchedIDs {
+				counter.addReference(rowMap[id], "")
+			}
+			if sortField != "" {
+				for _, achievement := range result.Achievements {
+					candidates = append(candidates, achievementSortCandidate{achievement: achievement, row: rowMap[achievement.ID.Hex()]})
+				}
+				candidates = topSortCandidates(candidates, sortField, filter.SortOrder != "ASC", want)
+			}
+		}
+
+		// #9d proses: reference yang gagal satu dimensi PostgreSQL dihitung di dimensi itu jika matchedSet semua filter MongoDB
+		if len(nearIDs) > 0 {
+			matchedIDs, err := s.achievementRepo.GetMatchingAchievementIDs(ctx, achievementFilterForMongo(filter, nearIDs, soonDays))
+			if err != nil {
+				return nil, errors.New("error menyaring achievements di MongoDB: " + err.Error())
+			}
+			for _, id := range matchedIDs {
+				counter.addReference(rowMap[id], nearDimension[id])
+			}
+		}
+
+		if len(batch) < achievementBatchSize {
+			break
+		}
+		last := batch[len(batch)-1]
+		after = &modelpostgre.PageCursor{CreatedAt: last.CreatedAt, ID: last.MongoAchievementID}
+	}
+
+	// #9e proses: halaman sort field MongoDB diambil dari kandidat gabungan semua batch
+	scan.Facets = counter.facets()
+	skip := (filter.Page - 1) * filter.Limit
+	for i := skip; i < len(candidates); i++ {
+		scan.PageRows = append(scan.PageRows, candidates[i].row)
+	}
+
+	return scan, nil
+}
+
+// #10 proses: gabungkan kandidat sort field MongoDB lalu ambil limit teratas, urutan sama dengan $sort MongoDB yaitu field lalu _id dengan arah yang sama
+func topSortCandidates(candidates []achievementSortCandidate, sortField string, descending bool, limit int) []achievementSortCandidate {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i].achievement, candidates[j].achievement
+		compare := 0
+		switch sortField {
+		case "points":
+			compare = a.Points - b.Points
+		case "title":
+			compare = strings.Compare(a.Title, b.Title)
+		}
+		if compare == 0 {
+			compare = strings.Compare(a.ID.Hex(), b.ID.Hex())
+		}
+		if descending {
+			return compare > 0
+		}
+		return compare < 0
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	return candidates
+}
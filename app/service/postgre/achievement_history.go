@@ -0,0 +1,172 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk context, database, errors, fmt, model, dan time
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	"time"
+)
+
+// #2 proses: pesan error jika prestasi sudah mulai direview sehingga tidak bisa di-withdraw
+const withdrawReviewStartedMessage = "prestasi tidak dapat di-withdraw karena dosen wali sudah mulai mereview"
+
+// #3 proses: satu entry history beserta waktunya, dipakai untuk mengurutkan history
+type historyEntry struct {
+	at   time.Time
+	data map[string]interface{}
+}
+
+// #4 proses: catat aksi ke history prestasi, error pencatatan tidak menggagalkan proses utama
+func (s *AchievementService) recordHistory(ctx context.Context, refID string, action string, status string, changedBy string, note *string) {
+	req := modelpostgre.CreateAchievementHistoryRequest{
+		AchievementRefID: refID,
+		Action:           action,
+		Status:           status,
+		Note:             note,
+	}
+	if changedBy != "" {
+		req.ChangedBy = &changedBy
+	}
+
+	if err := s.achievementRefRepo.CreateAchievementHistory(ctx, req); err != nil {
+		fmt.Printf("Error recording achievement history: %v\n", err)
+	}
+}
+
+// #5 proses: cek apakah sudah ada review sejak prestasi terakhir kali di-submit
+func reviewStartedSince(history []modelpostgre.AchievementHistory, submittedAt *time.Time) bool {
+	return historyActionSince(history, modelpostgre.AchievementHistoryActionReviewStarted, submittedAt) != nil
+}
+
+// #5a proses: ambil waktu aksi pertama yang dicatat sejak prestasi terakhir kali di-submit, nil jika belum ada
+func historyActionSince(history []modelpostgre.AchievementHistory, action string, submittedAt *time.Time) *time.Time {
+	for _, entry := range history {
+		if entry.Action != action {
+			continue
+		}
+		if submittedAt == nil || !entry.CreatedAt.Before(*submittedAt) {
+			at := entry.CreatedAt
+			return &at
+		}
+	}
+	return nil
+}
+
+// #6 proses: tandai review dimulai saat dosen wali pertama kali membuka prestasi submitted, hanya dicatat sekali per submit
+func (s *AchievementService) markReviewStarted(ctx context.Context, ref *modelpostgre.AchievementReference, userID string) {
+	if ref.Status != modelpostgre.AchievementStatusSubmitted {
+		return
+	}
+
+	history, err := s.achievementRefRepo.GetAchievementHistoryByRefID(ctx, ref.ID)
+	if err != nil {
+		fmt.Printf("Error reading achievement history: %v\n", err)
+		return
+	}
+
+	if reviewStartedSince(history, ref.SubmittedAt) {
+		return
+	}
+
+	s.recordHistory(ctx, ref.ID, modelpostgre.AchievementHistoryActionReviewStarted, ref.Status, userID, nil)
+}
+
+// #7 proses: withdraw prestasi submitted kembali ke draft selama dosen wali belum mulai mereview
+func (s *AchievementService) WithdrawAchievement(ctx context.Context, userID string, roleID string, mongoID string) (*modelpostgre.UpdateAchievementReferenceResponse, error) {
+	// #7a proses: validasi user harus memiliki role Mahasiswa
+	roleName, err := s.userRepo.GetRoleName(ctx, roleID)
+	if err != nil {
+		return nil, errors.New("error mengambil role name: " + err.Error())
+	}
+
+	if roleName != "Mahasiswa" {
+		return nil, errors.New("akses ditolak. Hanya mahasiswa yang dapat withdraw prestasi")
+	}
+
+	// #7b proses: ambil achievement reference dan validasi status harus submitted
+	ref, err := s.achievementRefRepo.GetAchievementReferenceByMongoID(ctx, mongoID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("prestasi tidak ditemukan")
+		}
+		return nil, err
+	}
+
+	if ref.Status != modelpostgre.AchievementStatusSubmitted {
+		return nil, errors.New("prestasi hanya dapat di-withdraw jika status adalah submitted")
+	}
+
+	// #7c proses: ambil student ID dan validasi ownership
+	studentID, err := s.studentRepo.GetStudentIDByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("error mengambil data mahasiswa: " + err.Error())
+	}
+
+	if ref.StudentID != studentID {
+		return nil, errors.New("akses ditolak. Anda hanya dapat withdraw prestasi milik Anda sendiri")
+	}
+
+	// #7d proses: tolak withdraw jika dosen wali sudah membuka atau memverifikasi sebagian prestasi tim
+	history, err := s.achievementRefRepo.GetAchievementHistoryByRefID(ctx, ref.ID)
+	if err != nil {
+		return nil, errors.New("error mengambil history prestasi: " + err.Error())
+	}
+
+	if reviewStartedSince(history, ref.SubmittedAt) {
+		return nil, errors.New(withdrawReviewStartedMessage)
+	}
+
+	achievement, err := s.achievementRepo.GetAchievementByID(ctx, mongoID)
+	if err != nil {
+		return nil, errors.New("error mengambil data prestasi: " + err.Error())
+	}
+	if achievement != nil && len(achievement.TeamVerifications) > 0 {
+		return nil, errors.New(withdrawReviewStartedMessage)
+	}
+
+	// #7e proses: update status jadi draft dengan query bersyarat, gagal jika review dimulai di antara pengecekan dan update
+	withdrawn, err := s.achievementRefRepo.WithdrawAchievementReference(ctx, ref.ID)
+	if err != nil {
+		return nil, errors.New("error withdraw prestasi: " + err.Error())
+	}
+	if !withdrawn {
+		return nil, errors.New(withdrawReviewStartedMessage)
+	}
+
+	// #7f proses: catat withdraw di history dan tarik notifikasi pengajuan dari dosen wali
+	s.recordHistory(ctx, ref.ID, modelpostgre.AchievementHistoryActionWithdrawn, modelpostgre.AchievementStatusDraft, userID, nil)
+
+	if err := s.notificationService.RetractSubmissionNotification(ctx, ref.ID); err != nil {
+		fmt.Printf("Error retracting submission notification: %v\n", err)
+	}
+
+	// #7g proses: ambil reference yang sudah diupdate
+	updatedRef, err := s.achievementRefRepo.GetAchievementReferenceByID(ctx, ref.ID)
+	if err != nil {
+		return nil, errors.New("error mengambil data prestasi yang diupdate: " + err.Error())
+	}
+
+	// #7h proses: build response dengan reference yang sudah diupdate
+	return &modelpostgre.UpdateAchievementReferenceResponse{
+		Status: "success",
+		Data:   *updatedRef,
+	}, nil
+}
+
+// #8 proses: ambil nama lengkap user untuk ditampilkan di history, nil jika user tidak ditemukan
+func (s *AchievementService) userFullName(ctx context.Context, userID *string) *string {
+	if userID == nil {
+		return nil
+	}
+
+	user, err := s.userRepo.FindUserByID(ctx, *userID)
+	if err != nil || user == nil {
+		return nil
+	}
+
+	name := user.FullName
+	return &name
+}
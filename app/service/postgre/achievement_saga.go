@@ -0,0 +1,55 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk context, fmt, dan time
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// #2 proses: batas percobaan dan timeout untuk kompensasi, kompensasi memakai context sendiri karena context request bisa sudah habis saat langkah berikutnya gagal
+const (
+	sagaCompensationAttempts = 3
+	sagaCompensationTimeout  = 5 * time.Second
+)
+
+// #3 proses: satu langkah penulisan lintas MongoDB dan PostgreSQL, compensate membatalkan action jika langkah setelahnya gagal
+type sagaStep struct {
+	name       string
+	action     func(ctx context.Context) error
+	compensate func(ctx context.Context) error
+}
+
+// #4 proses: jalankan langkah saga berurutan, jika satu langkah gagal kompensasi langkah yang sudah berhasil dengan urutan terbalik lalu kembalikan error langkah yang gagal
+func runSaga(ctx context.Context, steps ...sagaStep) error {
+	for i, step := range steps {
+		if err := step.action(ctx); err != nil {
+			compensateSaga(ctx, steps[:i])
+			return err
+		}
+	}
+	return nil
+}
+
+// #5 proses: kompensasi langkah yang sudah berhasil, setiap kompensasi dicoba ulang beberapa kali. Yang tetap gagal dicatat supaya bisa diperbaiki lewat rekonsiliasi
+func compensateSaga(ctx context.Context, done []sagaStep) {
+	compensationCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sagaCompensationTimeout)
+	defer cancel()
+
+	for i := len(done) - 1; i >= 0; i-- {
+		step := done[i]
+		if step.compensate == nil {
+			continue
+		}
+
+		var err error
+		for attempt := 1; attempt <= sagaCompensationAttempts; attempt++ {
+			if err = step.compensate(compensationCtx); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			fmt.Printf("Error compensating saga step %s: %v\n", step.name, err)
+		}
+	}
+}
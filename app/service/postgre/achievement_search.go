@@ -0,0 +1,146 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk context, database, errors, strings, model, dan time
+import (
+	"context"
+	"database/sql"
+	"errors"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	"strings"
+	"time"
+)
+
+// #2 proses: cari prestasi dengan full-text search, hasil diurutkan berdasarkan relevansi dengan aturan akses yang sama seperti GetAchievements
+func (s *AchievementService) SearchAchievements(ctx context.Context, userID string, roleID string, query string, page, limit int) (map[string]interface{}, error) {
+	// #2a proses: validasi query pencarian tidak kosong
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errors.New("query pencarian wajib diisi")
+	}
+
+	// #2b proses: validasi dan set default untuk page dan limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	// #2c proses: tentukan mahasiswa yang prestasinya boleh dicari berdasarkan role
+	studentIDs, err := s.searchableStudentIDs(ctx, userID, roleID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []map[string]interface{}{}
+	total := 0
+
+	// #2d proses: ambil reference aktif yang boleh dicari, dosen wali tanpa mahasiswa bimbingan langsung dapat hasil kosong
+	var rows []modelpostgre.AchievementListRow
+	if studentIDs == nil || len(studentIDs) > 0 {
+		rows, err = s.achievementRefRepo.GetAchievementListRows(ctx, modelpostgre.AchievementListFilter{StudentIDs: studentIDs})
+		if err != nil {
+			return nil, errors.New("error mengambil achievement references: " + err.Error())
+		}
+	}
+
+	// #2e proses: jalankan pencarian di MongoDB hanya di antara ID reference aktif supaya total dan isi halaman berasal dari filter yang sama
+	if len(rows) > 0 {
+		ids := make([]string, 0, len(rows))
+		rowMap := make(map[string]modelpostgre.AchievementListRow, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row.MongoAchievementID)
+			rowMap[row.MongoAchievementID] = row
+		}
+
+		found, count, err := s.achievementRepo.SearchAchievements(ctx, query, ids, page, limit)
+		if err != nil {
+			return nil, errors.New("error mencari prestasi: " + err.Error())
+		}
+		total = count
+
+		for _, item := range found {
+			row := rowMap[item.ID.Hex()]
+
+			// #2f proses: build item hasil pencarian dengan status dan nama mahasiswa dari reference serta skor relevansi
+			result = append(result, map[string]interface{}{
+				"id":              item.ID.Hex(),
+				"studentId":       item.StudentID,
+				"achievementType": item.AchievementType,
+				"title":           item.Title,
+				"description":     item.Description,
+				"details":         item.Details,
+				"tags":            item.Tags,
+				"points":          item.Points,
+				"createdAt":       item.CreatedAt.Format(time.RFC3339),
+				"updatedAt":       item.UpdatedAt.Format(time.RFC3339),
+				"status":          row.Status,
+				"student_name":    row.StudentName,
+				"score":           item.Score,
+			})
+		}
+	}
+
+	// #2g proses: hitung total pages dan build response dengan pagination
+	totalPages := 0
+	if total > 0 {
+		totalPages = (total + limit - 1) / limit
+	}
+
+	return map[string]interface{}{
+		"status": "success",
+		"query":  query,
+		"data":   result,
+		"pagination": map[string]interface{}{
+			"page":        page,
+			"limit":       limit,
+			"total":       total,
+			"total_pages": totalPages,
+		},
+	}, nil
+}
+
+// #3 proses: kumpulkan student ID yang prestasinya boleh dicari, nil berarti admin boleh mencari semua prestasi
+func (s *AchievementService) searchableStudentIDs(ctx context.Context, userID string, roleID string) ([]string, error) {
+	roleName, err := s.userRepo.GetRoleName(ctx, roleID)
+	if err != nil {
+		return nil, errors.New("error mengambil role name: " + err.Error())
+	}
+
+	switch roleName {
+	case "Mahasiswa":
+		// #3a proses: mahasiswa hanya mencari prestasi milik sendiri
+		student, err := s.studentRepo.GetStudentByUserID(ctx, userID)
+		if err != nil {
+			return nil, errors.New("error mengambil data student: " + err.Error())
+		}
+		return []string{student.ID}, nil
+	case "Dosen Wali":
+		// #3b proses: dosen wali hanya mencari prestasi mahasiswa bimbingan
+		lecturer, err := s.userRepo.GetLecturerByUserID(ctx, userID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, errors.New("data dosen wali tidak ditemukan. Pastikan user memiliki profil dosen wali")
+			}
+			return nil, err
+		}
+
+		students, err := s.studentRepo.GetStudentsByAdvisorID(ctx, lecturer.ID)
+		if err != nil {
+			return nil, errors.New("error mengambil mahasiswa bimbingan: " + err.Error())
+		}
+
+		studentIDs := make([]string, 0, len(students))
+		for _, student := range students {
+			studentIDs = append(studentIDs, student.ID)
+		}
+		return studentIDs, nil
+	case "Admin":
+		return nil, nil
+	default:
+		return nil, errors.New("akses ditolak. Role tidak memiliki akses untuk melihat prestasi")
+	}
+}
@@ -1,42 +1,67 @@
 package service
 
-// #1 proses: import library yang diperlukan untuk context, database, errors, fmt, helper, dan time
+// #1 proses: import library yang diperlukan untuk context, database, errors, fmt, io, helper, repository storage, sort, strings, time, dan primitive ObjectID
 import (
 	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"io"
 	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
 	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
 	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
 	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	repositorystorage "sistem-pelaporan-prestasi-mahasiswa/app/repository/storage"
 	"sistem-pelaporan-prestasi-mahasiswa/helper"
+	"sort"
+	"strings"
 	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // #2 proses: definisikan interface untuk operasi achievement dengan integrasi PostgreSQL dan MongoDB
 type IAchievementService interface {
 	CreateAchievement(ctx context.Context, userID string, roleID string, req modelmongo.CreateAchievementRequest) (*modelmongo.CreateAchievementResponse, error)
 	SubmitAchievement(ctx context.Context, userID string, roleID string, mongoID string) (*modelpostgre.UpdateAchievementReferenceResponse, error)
-	VerifyAchievement(ctx context.Context, userID string, roleID string, mongoID string) (*modelpostgre.VerifyAchievementResponse, error)
-	RejectAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelpostgre.RejectAchievementRequest) (*modelpostgre.RejectAchievementResponse, error)
+	WithdrawAchievement(ctx context.Context, userID string, roleID string, mongoID string) (*modelpostgre.UpdateAchievementReferenceResponse, error)
+	VerifyAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelpostgre.VerifyAchievementRequest, expected modelpostgre.AchievementVersion) (*modelpostgre.VerifyAchievementResponse, error)
+	RejectAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelpostgre.RejectAchievementRequest, expected modelpostgre.AchievementVersion) (*modelpostgre.RejectAchievementResponse, error)
 	DeleteAchievement(ctx context.Context, userID string, roleID string, mongoID string) (*modelmongo.DeleteAchievementResponse, error)
-	GetAchievements(ctx context.Context, userID string, roleID string, page, limit int, statusFilter string, achievementTypeFilter string, sortBy string, sortOrder string) (map[string]interface{}, error)
+	GetAchievements(ctx context.Context, userID string, roleID string, filter modelpostgre.AchievementListFilter) (map[string]interface{}, error)
 	GetAchievementsByStudentID(ctx context.Context, studentID string, page, limit int) (map[string]interface{}, error)
 	GetAchievementByID(ctx context.Context, userID string, roleID string, mongoID string) (map[string]interface{}, error)
-	UpdateAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelmongo.UpdateAchievementRequest) (map[string]interface{}, error)
+	UpdateAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelmongo.UpdateAchievementRequest, expected modelpostgre.AchievementVersion) (map[string]interface{}, error)
 	GetAchievementStats(ctx context.Context) (map[string]interface{}, error)
-	UploadFile(ctx context.Context, userID string, roleID string, mongoID string, fileName string, fileURL string, fileType string) (*modelmongo.Attachment, error)
+	UploadFile(ctx context.Context, userID string, roleID string, mongoID string, fileName string, fileType string, size int64, content io.Reader) (*modelmongo.Attachment, error)
 	GetAchievementHistory(ctx context.Context, userID string, roleID string, mongoID string) (map[string]interface{}, error)
+	ConfirmTeamParticipation(ctx context.Context, userID string, roleID string, mongoID string) (*modelmongo.ConfirmTeamParticipationResponse, error)
+	GetDeletedAchievements(ctx context.Context, userID string, roleID string, page, limit int) (*modelpostgre.GetDeletedAchievementsResponse, error)
+	RestoreAchievement(ctx context.Context, userID string, roleID string, mongoID string) (*modelpostgre.UpdateAchievementReferenceResponse, error)
+	PurgeDeletedAchievements(ctx context.Context, userID string, roleID string, dryRun bool) (*modelpostgre.TrashPurgeResponse, error)
+	RunTrashPurge(ctx context.Context, retentionDays int, dryRun bool) (*modelpostgre.TrashPurgeResult, error)
+	SearchAchievements(ctx context.Context, userID string, roleID string, query string, page, limit int) (map[string]interface{}, error)
+	GetAttachmentFile(ctx context.Context, userID string, roleID string, mongoID string, key string) (*modelmongo.AttachmentDownload, error)
+	CreateAttachmentLink(ctx context.Context, userID string, roleID string, mongoID string, key string) (*modelmongo.AttachmentLinkResponse, error)
+	GetSignedAttachmentFile(ctx context.Context, mongoID string, key string, expires int64, signature string) (*modelmongo.AttachmentDownload, error)
+	DeleteAttachment(ctx context.Context, userID string, roleID string, mongoID string, attachmentID string) (*modelmongo.AttachmentListResponse, error)
+	ReplaceAttachment(ctx context.Context, userID string, roleID string, mongoID string, attachmentID string, fileName string, fileType string, size int64, content io.Reader) (*modelmongo.Attachment, error)
 }
 
-// #3 proses: struct service untuk achievement dengan dependency achievement MongoDB, achievement reference PostgreSQL, user, student, dan notification service
+// #3 proses: struct service untuk achievement dengan dependency achievement MongoDB, achievement reference PostgreSQL, user, student, notification service, point rubric service, achievement type service, storage file, konfigurasi attachment, konfigurasi SLA, konfigurasi retensi tempat sampah, dan konfigurasi masa berlaku sertifikasi
 type AchievementService struct {
-	achievementRepo     repositorymongo.IAchievementRepository
-	achievementRefRepo  repositorypostgre.IAchievementReferenceRepository
-	userRepo            repositorypostgre.IUserRepository
-	studentRepo         repositorypostgre.IStudentRepository
-	notificationService INotificationService
+	achievementRepo        repositorymongo.IAchievementRepository
+	achievementRefRepo     repositorypostgre.IAchievementReferenceRepository
+	userRepo               repositorypostgre.IUserRepository
+	studentRepo            repositorypostgre.IStudentRepository
+	notificationService    INotificationService
+	pointRubricService     IPointRubricService
+	achievementTypeService IAchievementTypeService
+	fileStorage            repositorystorage.IFileStorage
+	attachmentConfig       modelmongo.AttachmentConfig
+	slaConfig              modelpostgre.SLAConfig
+	trashConfig            modelpostgre.TrashRetentionConfig
+	certificationConfig    modelpostgre.CertificationExpiryConfig
 }
 
 // #4 proses: constructor untuk membuat instance AchievementService baru
@@ -46,13 +71,27 @@ func NewAchievementService(
 	userRepo repositorypostgre.IUserRepository,
 	studentRepo repositorypostgre.IStudentRepository,
 	notificationService INotificationService,
+	pointRubricService IPointRubricService,
+	achievementTypeService IAchievementTypeService,
+	fileStorage repositorystorage.IFileStorage,
+	attachmentConfig modelmongo.AttachmentConfig,
+	slaConfig modelpostgre.SLAConfig,
+	trashConfig modelpostgre.TrashRetentionConfig,
+	certificationConfig modelpostgre.CertificationExpiryConfig,
 ) IAchievementService {
 	return &AchievementService{
-		achievementRepo:     achievementRepo,
-		achievementRefRepo:  achievementRefRepo,
-		userRepo:            userRepo,
-		studentRepo:         studentRepo,
-		notificationService: notificationService,
+		achievementRepo:        achievementRepo,
+		achievementRefRepo:     achievementRefRepo,
+		userRepo:               userRepo,
+		studentRepo:            studentRepo,
+		notificationService:    notificationService,
+		pointRubricService:     pointRubricService,
+		achievementTypeService: achievementTypeService,
+		fileStorage:            fileStorage,
+		attachmentConfig:       attachmentConfig,
+		slaConfig:              slaConfig,
+		trashConfig:            trashConfig,
+		certificationConfig:    certificationConfig,
 	}
 }
 
@@ -104,57 +143,100 @@ func (s *AchievementService) CreateAchievement(ctx context.Context, userID strin
 		return nil, errors.New("description wajib diisi")
 	}
 
-	// #5f proses: validasi achievement type harus salah satu dari tipe yang diizinkan
-	validTypes := map[string]bool{
-		"academic":      true,
-		"competition":   true,
-		"organization":  true,
-		"publication":   true,
-		"certification": true,
-		"other":         true,
+	// #5f proses: validasi achievement type terdaftar dan aktif, lalu validasi detail dan custom field sesuai skema tipe
+	if err := s.achievementTypeService.ValidateAchievement(ctx, req.AchievementType, req.Details); err != nil {
+		return nil, err
 	}
 
-	if !validTypes[req.AchievementType] {
-		return nil, errors.New("achievement type tidak valid. Gunakan: academic, competition, organization, publication, certification, atau other")
+	// #5f1 proses: validasi anggota tim dan dosen wali yang ditunjuk jika prestasi adalah prestasi tim
+	teamMembers, err := s.buildTeamMembers(ctx, studentID, req.TeamMembers, req.DesignatedAdvisorID, nil)
+	if err != nil {
+		return nil, err
 	}
 
 	// #5g proses: set student ID ke request
 	req.StudentID = studentID
 
-	// #5h proses: buat achievement object untuk disimpan ke MongoDB
+	// #5h proses: hitung poin dari rubrik aktif, poin tidak lagi diisi oleh mahasiswa
+	calculation, err := s.pointRubricService.CalculatePoints(ctx, req.AchievementType, req.Details)
+	if err != nil {
+		return nil, errors.New("error menghitung poin prestasi: " + err.Error())
+	}
+
+	// #5i proses: buat achievement object untuk disimpan ke MongoDB
 	achievement := &modelmongo.Achievement{
 		StudentID:       req.StudentID,
 		AchievementType: req.AchievementType,
 		Title:           req.Title,
 		Description:     req.Description,
 		Details:         req.Details,
-		Attachments:     req.Attachments,
 		Tags:            req.Tags,
-		Points:          req.Points,
-	}
-
-	// #5i proses: simpan achievement ke MongoDB
-	createdAchievement, err := s.achievementRepo.CreateAchievement(ctx, achievement)
+		Points:          calculation.Points,
+		RubricVersion:   calculation.RubricVersion,
+	}
+	if teamMembers != nil {
+		achievement.TeamMembers = teamMembers
+		achievement.DesignatedAdvisorID = req.DesignatedAdvisorID
+	}
+	achievement.DuplicateKeys = BuildDuplicateKeys(achievement)
+
+	// #5j proses: simpan achievement ke MongoDB lalu reference draft di PostgreSQL sebagai satu saga, jika reference gagal dibuat dokumen MongoDB dihapus permanen supaya tidak jadi orphan
+	var createdAchievement *modelmongo.Achievement
+	var createdRef *modelpostgre.AchievementReference
+	err = runSaga(ctx,
+		sagaStep{
+			name: "create achievement",
+			action: func(ctx context.Context) error {
+				created, err := s.achievementRepo.CreateAchievement(ctx, achievement)
+				if err != nil {
+					return errors.New("error menyimpan prestasi ke database: " + err.Error())
+				}
+				createdAchievement = created
+				return nil
+			},
+			compensate: func(ctx context.Context) error {
+				// #5j1 proses: purge hanya menghapus dokumen yang sudah di-soft delete, jadi tandai terhapus dulu
+				if err := s.achievementRepo.DeleteAchievement(ctx, createdAchievement.ID.Hex()); err != nil {
+					return err
+				}
+				_, err := s.achievementRepo.PurgeAchievement(ctx, createdAchievement.ID.Hex())
+				return err
+			},
+		},
+		sagaStep{
+			// #5k proses: buat reference di PostgreSQL dengan status draft
+			name: "create achievement reference",
+			action: func(ctx context.Context) error {
+				ref, err := s.achievementRefRepo.CreateAchievementReference(ctx, modelpostgre.CreateAchievementReferenceRequest{
+					StudentID:          studentID,
+					MongoAchievementID: createdAchievement.ID.Hex(),
+					Status:             modelpostgre.AchievementStatusDraft,
+				})
+				if err != nil {
+					return errors.New("error membuat reference prestasi: " + err.Error())
+				}
+				createdRef = ref
+				return nil
+			},
+		},
+	)
 	if err != nil {
-		return nil, errors.New("error menyimpan prestasi ke database: " + err.Error())
+		return nil, err
 	}
 
-	// #5j proses: buat reference di PostgreSQL dengan status draft
-	refReq := modelpostgre.CreateAchievementReferenceRequest{
-		StudentID:          studentID,
-		MongoAchievementID: createdAchievement.ID.Hex(),
-		Status:             modelpostgre.AchievementStatusDraft,
+	// #5k1 proses: kirim undangan konfirmasi ke anggota tim
+	if createdAchievement.IsTeam() && createdRef != nil {
+		s.notifyTeamInvitations(ctx, createdAchievement, createdRef.ID)
 	}
 
-	_, err = s.achievementRefRepo.CreateAchievementReference(ctx, refReq)
-	if err != nil {
-		return nil, errors.New("error membuat reference prestasi: " + err.Error())
-	}
+	// #5l proses: cek kemungkinan duplikat sebagai peringatan, prestasi tetap tersimpan sebagai draft
+	duplicateWarnings := s.findDuplicateCandidates(ctx, createdAchievement, true)
 
-	// #5k proses: build response dengan achievement yang baru dibuat
+	// #5m proses: build response dengan achievement yang baru dibuat
 	response := &modelmongo.CreateAchievementResponse{
-		Status: "success",
-		Data:   *createdAchievement,
+		Status:            "success",
+		Data:              *createdAchievement,
+		DuplicateWarnings: duplicateWarnings,
 	}
 
 	return response, nil
@@ -196,13 +278,67 @@ func (s *AchievementService) SubmitAchievement(ctx context.Context, userID strin
 		return nil, errors.New("akses ditolak. Anda hanya dapat submit prestasi milik Anda sendiri")
 	}
 
-	// #6e proses: update status jadi submitted dan set submitted_at
-	now := time.Now()
-	err = s.achievementRefRepo.UpdateAchievementReferenceStatus(ctx, ref.ID, modelpostgre.AchievementStatusSubmitted, &now)
+	// #6d1 proses: ambil data prestasi dari MongoDB dan validasi detail sebelum di-submit
+	achievement, err := s.achievementRepo.GetAchievementByID(ctx, mongoID)
 	if err != nil {
-		return nil, errors.New("error mengupdate status prestasi: " + err.Error())
+		return nil, errors.New("error mengambil data prestasi: " + err.Error())
 	}
 
+	if achievement == nil {
+		return nil, errors.New("prestasi tidak ditemukan")
+	}
+
+	if err := s.achievementTypeService.ValidateAchievement(ctx, achievement.AchievementType, achievement.Details); err != nil {
+		return nil, err
+	}
+
+	// #6d1a proses: prestasi tim hanya bisa di-submit jika semua anggota sudah mengkonfirmasi partisipasi
+	if achievement.IsTeam() {
+		if pending := unconfirmedTeamMembers(achievement); len(pending) > 0 {
+			return nil, errors.New("semua anggota tim harus mengkonfirmasi partisipasi sebelum prestasi di-submit. Belum konfirmasi: " + strings.Join(pending, ", "))
+		}
+	}
+
+	// #6d2 proses: hitung ulang kunci duplikat (sekaligus backfill prestasi lama) lalu cek kemungkinan duplikat sebagai peringatan
+	if err := s.refreshDuplicateKeys(ctx, achievement); err != nil {
+		return nil, errors.New("error menyimpan kunci duplikat prestasi: " + err.Error())
+	}
+	duplicateWarnings := s.findDuplicateCandidates(ctx, achievement, true)
+
+	// #6e proses: reset verifikasi tim lama di MongoDB lalu update status jadi submitted di PostgreSQL sebagai satu saga, jika status gagal diupdate verifikasi tim dikembalikan
+	now := time.Now()
+	steps := []sagaStep{}
+	if achievement.IsTeam() {
+		previousVerifications := achievement.TeamVerifications
+		steps = append(steps, sagaStep{
+			name: "reset team verifications",
+			action: func(ctx context.Context) error {
+				if err := s.achievementRepo.SetTeamVerifications(ctx, mongoID, nil); err != nil {
+					return errors.New("error mereset verifikasi tim: " + err.Error())
+				}
+				return nil
+			},
+			compensate: func(ctx context.Context) error {
+				return s.achievementRepo.SetTeamVerifications(ctx, mongoID, previousVerifications)
+			},
+		})
+	}
+	steps = append(steps, sagaStep{
+		name: "submit achievement reference",
+		action: func(ctx context.Context) error {
+			if err := s.achievementRefRepo.UpdateAchievementReferenceStatus(ctx, ref.ID, modelpostgre.AchievementStatusSubmitted, &now); err != nil {
+				return errors.New("error mengupdate status prestasi: " + err.Error())
+			}
+			return nil
+		},
+	})
+	if err := runSaga(ctx, steps...); err != nil {
+		return nil, err
+	}
+
+	// #6e1 proses: catat submit di history supaya submit ulang setelah withdraw tetap terlacak
+	s.recordHistory(ctx, ref.ID, modelpostgre.AchievementHistoryActionSubmitted, modelpostgre.AchievementStatusSubmitted, userID, nil)
+
 	// #6f proses: ambil reference yang sudah diupdate
 	updatedRef, err := s.achievementRefRepo.GetAchievementReferenceByID(ctx, ref.ID)
 	if err != nil {
@@ -217,15 +353,16 @@ func (s *AchievementService) SubmitAchievement(ctx context.Context, userID strin
 
 	// #6h proses: build response dengan reference yang sudah diupdate
 	response := &modelpostgre.UpdateAchievementReferenceResponse{
-		Status: "success",
-		Data:   *updatedRef,
+		Status:            "success",
+		Data:              *updatedRef,
+		DuplicateWarnings: duplicateWarnings,
 	}
 
 	return response, nil
 }
 
-// #7 proses: verifikasi achievement oleh dosen wali, ubah status jadi verified
-func (s *AchievementService) VerifyAchievement(ctx context.Context, userID string, roleID string, mongoID string) (*modelpostgre.VerifyAchievementResponse, error) {
+// #7 proses: verifikasi achievement oleh dosen wali, ubah status jadi verified. Ditolak jika versi prestasi berbeda dengan expected dari If-Match
+func (s *AchievementService) VerifyAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelpostgre.VerifyAchievementRequest, expected modelpostgre.AchievementVersion) (*modelpostgre.VerifyAchievementResponse, error) {
 	// #7a proses: validasi user harus memiliki role Dosen Wali
 	roleName, err := s.userRepo.GetRoleName(ctx, roleID)
 	if err != nil {
@@ -250,7 +387,7 @@ func (s *AchievementService) VerifyAchievement(ctx context.Context, userID strin
 		return nil, errors.New("prestasi hanya dapat diverifikasi jika status adalah submitted")
 	}
 
-	// #7d proses: ambil lecturer dan student untuk validasi advisor relationship
+	// #7d proses: ambil lecturer untuk validasi advisor relationship
 	lecturer, err := s.userRepo.GetLecturerByUserID(ctx, userID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -259,39 +396,130 @@ func (s *AchievementService) VerifyAchievement(ctx context.Context, userID strin
 		return nil, err
 	}
 
-	student, err := s.studentRepo.GetStudentByID(ctx, ref.StudentID)
+	// #7e proses: ambil achievement dari MongoDB untuk validasi advisor dan menghitung poin final
+	achievement, err := s.achievementRepo.GetAchievementByID(ctx, mongoID)
 	if err != nil {
-		return nil, errors.New("error mengambil data student: " + err.Error())
+		return nil, errors.New("error mengambil achievement dari database: " + err.Error())
+	}
+	if achievement == nil {
+		return nil, errors.New("prestasi tidak ditemukan")
 	}
 
-	// #7e proses: validasi dosen wali adalah advisor dari student
-	if student.AdvisorID != lecturer.ID {
+	// #7f proses: validasi dosen wali adalah advisor dari student, untuk prestasi tim advisor dari salah satu anggota atau yang ditunjuk
+	isAdvisor, err := s.isAchievementAdvisor(ctx, achievement, ref.StudentID, lecturer.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdvisor && !s.isEscalatedFallbackVerifier(ctx, ref, lecturer.ID) {
 		return nil, errors.New("akses ditolak. Anda hanya dapat memverifikasi prestasi mahasiswa bimbingan Anda")
 	}
 
-	// #7f proses: update status jadi verified dengan set verified_by dan verified_at
-	err = s.achievementRefRepo.UpdateAchievementReferenceVerify(ctx, ref.ID, userID)
+	// #7f0 proses: tolak verifikasi jika mahasiswa atau dosen lain sudah mengubah prestasi sejak dosen wali membukanya
+	if err := checkAchievementVersion(achievement, ref, expected); err != nil {
+		return nil, err
+	}
+
+	// #7f1 proses: prestasi tim baru terverifikasi setelah semua dosen wali yang wajib memverifikasi, verifikator pengganti SLA langsung memfinalkan. Verifikasi dosen wali terakhir disimpan sebagai langkah pertama saga
+	var teamVerificationStep *sagaStep
+	if achievement.IsTeam() && isAdvisor {
+		pendingAdvisorIDs, step, err := s.recordTeamVerification(ctx, achievement, lecturer.ID, userID)
+		if err != nil {
+			return nil, err
+		}
+		teamVerificationStep = step
+
+		if len(pendingAdvisorIDs) > 0 {
+			return &modelpostgre.VerifyAchievementResponse{
+				Status:            "success",
+				Data:              *ref,
+				PendingAdvisorIDs: pendingAdvisorIDs,
+				ETag:              achievementETag(achievement, ref),
+			}, nil
+		}
+	}
+
+	// #7g proses: hitung ulang poin dengan rubrik aktif, versi rubrik ini dikunci saat verifikasi
+	calculation, err := s.pointRubricService.CalculatePoints(ctx, achievement.AchievementType, achievement.Details)
 	if err != nil {
-		return nil, errors.New("error memverifikasi prestasi: " + err.Error())
+		return nil, errors.New("error menghitung poin prestasi: " + err.Error())
+	}
+
+	// #7h proses: jika dosen wali mengisi poin berbeda dari hasil rubrik, wajib ada justifikasi
+	finalPoints := calculation.Points
+	var override *modelmongo.PointsOverride
+	if req.Points != nil && *req.Points != calculation.Points {
+		if *req.Points < 0 {
+			return nil, errors.New("poin tidak boleh negatif")
+		}
+		if strings.TrimSpace(req.PointsJustification) == "" {
+			return nil, errors.New("justifikasi wajib diisi jika poin diubah dari hasil rubrik")
+		}
+		finalPoints = *req.Points
+		override = &modelmongo.PointsOverride{
+			CalculatedPoints: calculation.Points,
+			Points:           *req.Points,
+			Justification:    strings.TrimSpace(req.PointsJustification),
+			OverriddenBy:     userID,
+			OverriddenAt:     time.Now(),
+		}
 	}
 
-	// #7g proses: ambil reference yang sudah diupdate
+	// #7i proses: simpan verifikasi tim terakhir dan poin final beserta versi rubrik ke MongoDB lalu update status di PostgreSQL sebagai satu saga, jika verifikasi gagal verifikasi tim dihapus lagi dan poin lama dikembalikan
+	previousPoints, previousRubricVersion, previousOverride := achievement.Points, achievement.RubricVersion, achievement.PointsOverride
+	steps := []sagaStep{}
+	if teamVerificationStep != nil {
+		steps = append(steps, *teamVerificationStep)
+	}
+	steps = append(steps,
+		sagaStep{
+			name: "update achievement points",
+			action: func(ctx context.Context) error {
+				if err := s.achievementRepo.UpdateAchievementPoints(ctx, mongoID, finalPoints, calculation.RubricVersion, override); err != nil {
+					return errors.New("error menyimpan poin prestasi: " + err.Error())
+				}
+				return nil
+			},
+			compensate: func(ctx context.Context) error {
+				return s.achievementRepo.UpdateAchievementPoints(ctx, mongoID, previousPoints, previousRubricVersion, previousOverride)
+			},
+		},
+		sagaStep{
+			// #7j proses: update status jadi verified dengan set verified_by dan verified_at, update bersyarat version supaya request yang balapan tidak saling menimpa
+			name: "verify achievement reference",
+			action: func(ctx context.Context) error {
+				verified, err := s.achievementRefRepo.UpdateAchievementReferenceVerify(ctx, ref.ID, userID, ref.Version)
+				if err != nil {
+					return errors.New("error memverifikasi prestasi: " + err.Error())
+				}
+				if !verified {
+					return errors.New(versionConflictMessage)
+				}
+				return nil
+			},
+		},
+	)
+	if err := runSaga(ctx, steps...); err != nil {
+		return nil, err
+	}
+
+	// #7k proses: ambil reference yang sudah diupdate
 	updatedRef, err := s.achievementRefRepo.GetAchievementReferenceByID(ctx, ref.ID)
 	if err != nil {
 		return nil, errors.New("error mengambil data prestasi yang diupdate: " + err.Error())
 	}
 
-	// #7h proses: build response dengan reference yang sudah diupdate
+	// #7l proses: build response dengan reference yang sudah diupdate
 	response := &modelpostgre.VerifyAchievementResponse{
 		Status: "success",
 		Data:   *updatedRef,
+		ETag:   achievementETag(achievement, updatedRef),
 	}
 
 	return response, nil
 }
 
-// #8 proses: tolak achievement oleh dosen wali dengan catatan penolakan
-func (s *AchievementService) RejectAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelpostgre.RejectAchievementRequest) (*modelpostgre.RejectAchievementResponse, error) {
+// #8 proses: tolak achievement oleh dosen wali dengan catatan penolakan. Ditolak jika versi prestasi berbeda dengan expected dari If-Match
+func (s *AchievementService) RejectAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelpostgre.RejectAchievementRequest, expected modelpostgre.AchievementVersion) (*modelpostgre.RejectAchievementResponse, error) {
 	// #8a proses: validasi user harus memiliki role Dosen Wali
 	roleName, err := s.userRepo.GetRoleName(ctx, roleID)
 	if err != nil {
@@ -321,7 +549,7 @@ func (s *AchievementService) RejectAchievement(ctx context.Context, userID strin
 		return nil, errors.New("prestasi hanya dapat ditolak jika status adalah submitted")
 	}
 
-	// #8e proses: ambil lecturer dan student untuk validasi advisor relationship
+	// #8e proses: ambil lecturer dan achievement untuk validasi advisor relationship
 	lecturer, err := s.userRepo.GetLecturerByUserID(ctx, userID)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -330,21 +558,37 @@ func (s *AchievementService) RejectAchievement(ctx context.Context, userID strin
 		return nil, err
 	}
 
-	student, err := s.studentRepo.GetStudentByID(ctx, ref.StudentID)
+	// #8e1 proses: achievement yang tidak ada di MongoDB diperlakukan sebagai prestasi individu supaya tetap bisa ditolak
+	achievement, err := s.achievementRepo.GetAchievementByID(ctx, mongoID)
 	if err != nil {
-		return nil, errors.New("error mengambil data student: " + err.Error())
+		return nil, errors.New("error mengambil achievement dari database: " + err.Error())
+	}
+	if achievement == nil {
+		achievement = &modelmongo.Achievement{}
 	}
 
-	// #8f proses: validasi dosen wali adalah advisor dari student
-	if student.AdvisorID != lecturer.ID {
+	// #8f proses: validasi dosen wali adalah advisor dari student, untuk prestasi tim advisor dari salah satu anggota atau yang ditunjuk
+	isAdvisor, err := s.isAchievementAdvisor(ctx, achievement, ref.StudentID, lecturer.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !isAdvisor && !s.isEscalatedFallbackVerifier(ctx, ref, lecturer.ID) {
 		return nil, errors.New("akses ditolak. Anda hanya dapat menolak prestasi mahasiswa bimbingan Anda")
 	}
 
-	// #8g proses: update status jadi rejected dengan set rejection note
-	err = s.achievementRefRepo.UpdateAchievementReferenceReject(ctx, ref.ID, userID, req.RejectionNote)
+	// #8f1 proses: tolak penolakan jika prestasi sudah diubah sejak dosen wali membukanya
+	if err := checkAchievementVersion(achievement, ref, expected); err != nil {
+		return nil, err
+	}
+
+	// #8g proses: update status jadi rejected dengan set rejection note, update bersyarat version supaya request yang balapan tidak saling menimpa
+	rejected, err := s.achievementRefRepo.UpdateAchievementReferenceReject(ctx, ref.ID, userID, req.RejectionNote, ref.Version)
 	if err != nil {
 		return nil, errors.New("error menolak prestasi: " + err.Error())
 	}
+	if !rejected {
+		return nil, errors.New(versionConflictMessage)
+	}
 
 	// #8h proses: ambil reference yang sudah diupdate
 	updatedRef, err := s.achievementRefRepo.GetAchievementReferenceByID(ctx, ref.ID)
@@ -352,16 +596,32 @@ func (s *AchievementService) RejectAchievement(ctx context.Context, userID strin
 		return nil, errors.New("error mengambil data prestasi yang diupdate: " + err.Error())
 	}
 
-	// #8i proses: buat notifikasi untuk student tentang penolakan
-	err = s.notificationService.CreateAchievementNotification(ctx, student.UserID, ref.MongoAchievementID, ref.ID, req.RejectionNote)
-	if err != nil {
-		fmt.Printf("Error creating notification for rejected achievement: %v\n", err)
+	// #8i proses: buat notifikasi untuk student tentang penolakan, prestasi tim dikirim ke semua anggota
+	studentIDs := []string{ref.StudentID}
+	for _, member := range achievement.TeamMembers {
+		if member.StudentID != ref.StudentID {
+			studentIDs = append(studentIDs, member.StudentID)
+		}
+	}
+
+	for _, studentID := range studentIDs {
+		student, err := s.studentRepo.GetStudentByID(ctx, studentID)
+		if err != nil {
+			fmt.Printf("Error creating notification for rejected achievement: %v\n", err)
+			continue
+		}
+
+		err = s.notificationService.CreateAchievementNotification(ctx, student.UserID, ref.MongoAchievementID, ref.ID, req.RejectionNote)
+		if err != nil {
+			fmt.Printf("Error creating notification for rejected achievement: %v\n", err)
+		}
 	}
 
 	// #8j proses: build response dengan reference yang sudah diupdate
 	response := &modelpostgre.RejectAchievementResponse{
 		Status: "success",
 		Data:   *updatedRef,
+		ETag:   achievementETag(achievement, updatedRef),
 	}
 
 	return response, nil
@@ -403,17 +663,45 @@ func (s *AchievementService) DeleteAchievement(ctx context.Context, userID strin
 		return nil, errors.New("akses ditolak. Anda hanya dapat menghapus prestasi milik Anda sendiri")
 	}
 
-	// #9e proses: soft delete achievement di MongoDB
-	err = s.achievementRepo.DeleteAchievement(ctx, mongoID)
+	// #9e proses: soft delete achievement di MongoDB lalu reference di PostgreSQL sebagai satu saga, jika reference gagal dihapus dokumen MongoDB di-restore
+	refAlreadyDeleted := false
+	err = runSaga(ctx,
+		sagaStep{
+			name: "delete achievement",
+			action: func(ctx context.Context) error {
+				if err := s.achievementRepo.DeleteAchievement(ctx, mongoID); err != nil {
+					return errors.New("error menghapus prestasi dari database: " + err.Error())
+				}
+				return nil
+			},
+			compensate: func(ctx context.Context) error {
+				// #9e1 proses: reference yang sudah terhapus oleh request lain berarti dokumen MongoDB memang harus tetap terhapus
+				if refAlreadyDeleted {
+					return nil
+				}
+				return s.achievementRepo.RestoreAchievement(ctx, mongoID)
+			},
+		},
+		sagaStep{
+			// #9f proses: update status reference jadi deleted, status sebelumnya disimpan supaya admin bisa restore dari tempat sampah
+			name: "delete achievement reference",
+			action: func(ctx context.Context) error {
+				err := s.achievementRefRepo.SoftDeleteAchievementReference(ctx, ref.ID)
+				if err == sql.ErrNoRows {
+					refAlreadyDeleted = true
+				}
+				if err != nil {
+					return errors.New("error mengupdate status prestasi menjadi deleted: " + err.Error())
+				}
+				return nil
+			},
+		},
+	)
 	if err != nil {
-		return nil, errors.New("error menghapus prestasi dari database: " + err.Error())
+		return nil, err
 	}
 
-	// #9f proses: update status reference jadi deleted
-	err = s.achievementRefRepo.UpdateAchievementReferenceStatus(ctx, ref.ID, modelpostgre.AchievementStatusDeleted, nil)
-	if err != nil {
-		return nil, errors.New("error mengupdate status prestasi menjadi deleted: " + err.Error())
-	}
+	s.recordHistory(ctx, ref.ID, modelpostgre.AchievementHistoryActionDeleted, modelpostgre.AchievementStatusDeleted, userID, nil)
 
 	// #9g proses: build response sukses
 	response := &modelmongo.DeleteAchievementResponse{
@@ -423,119 +711,84 @@ func (s *AchievementService) DeleteAchievement(ctx context.Context, userID strin
 	return response, nil
 }
 
-// #10 proses: ambil achievements dengan pagination dan filtering berdasarkan role user
-func (s *AchievementService) GetAchievements(ctx context.Context, userID string, roleID string, page, limit int, statusFilter string, achievementTypeFilter string, sortBy string, sortOrder string) (map[string]interface{}, error) {
+// #10 proses: ambil achievements dengan filter multi-select dan facet berdasarkan role user, filter PostgreSQL dan MongoDB dijalankan di database masing-masing
+func (s *AchievementService) GetAchievements(ctx context.Context, userID string, roleID string, filter modelpostgre.AchievementListFilter) (map[string]interface{}, error) {
 	// #10a proses: validasi dan set default untuk page dan limit
-	if page < 1 {
-		page = 1
+	if filter.Page < 1 {
+		filter.Page = 1
 	}
-	if limit < 1 {
-		limit = 10
+	if filter.Limit < 1 {
+		filter.Limit = 10
 	}
-	if limit > 100 {
-		limit = 100
+	if filter.Limit > 100 {
+		filter.Limit = 100
 	}
-
-	// #10b proses: ambil role name untuk menentukan query yang sesuai
-	roleName, err := s.userRepo.GetRoleName(ctx, roleID)
-	if err != nil {
-		return nil, errors.New("error mengambil role name: " + err.Error())
+	if filter.CursorMode && !isCursorSortable(filter.SortBy) {
+		return nil, errors.New("cursor hanya bisa dipakai dengan sortBy created_at")
 	}
 
-	var references []modelpostgre.AchievementReference
-	var total int
-
-	// #10c proses: ambil references berdasarkan role, Mahasiswa hanya lihat milik sendiri
-	if roleName == "Mahasiswa" {
-		// #10d proses: ambil student dan query references milik student
-		student, err := s.studentRepo.GetStudentByUserID(ctx, userID)
-		if err != nil {
-			return nil, errors.New("error mengambil data student: " + err.Error())
-		}
+	// #10b proses: batasi references berdasarkan role, Mahasiswa hanya lihat milik sendiri dan Dosen Wali mahasiswa bimbingan
+	if _, err := scopeAchievementListFilter(ctx, s.userRepo, s.studentRepo, userID, roleID, &filter); err != nil {
+		return nil, err
+	}
 
-		references, total, err = s.achievementRefRepo.GetAchievementReferenceByStudentIDPaginated(ctx, student.ID, page, limit)
+	// #10c proses: mode cursor mengambil satu reference lebih dari limit tanpa total dan facet, mode halaman menghitung total dan facet per batch lalu mengambil reference halaman sesuai sortBy
+	var rows []modelpostgre.AchievementListRow
+	var scan *achievementFacetScan
+	var nextCursor *modelpostgre.PageCursor
+	var err error
+	if filter.CursorMode {
+		rows, nextCursor, err = s.getAchievementPageRows(ctx, filter, 0, filter.Limit+1)
 		if err != nil {
-			return nil, errors.New("error mengambil achievement references: " + err.Error())
-		}
-	} else if roleName == "Dosen Wali" {
-		// #10e proses: ambil lecturer dan query references dari mahasiswa bimbingan
-		lecturer, err := s.userRepo.GetLecturerByUserID(ctx, userID)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				return nil, errors.New("data dosen wali tidak ditemukan. Pastikan user memiliki profil dosen wali")
-			}
 			return nil, err
 		}
-
-		references, total, err = s.achievementRefRepo.GetAchievementReferencesByAdvisorIDPaginated(ctx, lecturer.ID, page, limit)
-		if err != nil {
-			return nil, errors.New("error mengambil achievement references: " + err.Error())
-		}
-	} else if roleName == "Admin" {
-		// #10f proses: admin bisa lihat semua dengan filter dan sorting
-		references, total, err = s.achievementRefRepo.GetAllAchievementReferencesPaginated(ctx, page, limit, statusFilter, sortBy, sortOrder)
+	} else {
+		scan, err = s.getAchievementFacets(ctx, filter)
 		if err != nil {
-			return nil, errors.New("error mengambil achievement references: " + err.Error())
+			return nil, err
 		}
-	} else {
-		return nil, errors.New("akses ditolak. Role tidak memiliki akses untuk melihat prestasi")
-	}
-
-	// #10g proses: jika tidak ada references, return response kosong
-	if len(references) == 0 {
-		totalPages := 0
-		if total > 0 {
-			totalPages = (total + limit - 1) / limit
+		rows = scan.PageRows
+		if mongoSortFields[filter.SortBy] == "" {
+			rows, _, err = s.getAchievementPageRows(ctx, filter, (filter.Page-1)*filter.Limit, filter.Limit)
+			if err != nil {
+				return nil, err
+			}
 		}
-		return map[string]interface{}{
-			"status": "success",
-			"data":   []modelmongo.Achievement{},
-			"pagination": map[string]interface{}{
-				"page":        page,
-				"limit":       limit,
-				"total":       total,
-				"total_pages": totalPages,
-			},
-		}, nil
 	}
 
-	// #10h proses: kumpulkan semua mongo IDs dari references
-	var mongoIDs []string
-	for _, ref := range references {
-		mongoIDs = append(mongoIDs, ref.MongoAchievementID)
+	// #10d proses: mode cursor mengambil satu reference lebih, sisanya jadi penanda masih ada halaman berikutnya dan next_cursor ada di reference terakhir halaman. Halaman yang berhenti karena batas baca sudah membawa next_cursor di reference terakhir yang dibaca walaupun isinya kurang dari limit
+	if filter.CursorMode && len(rows) > filter.Limit {
+		rows = rows[:filter.Limit]
+		last := rows[len(rows)-1]
+		nextCursor = &modelpostgre.PageCursor{CreatedAt: last.CreatedAt, ID: last.MongoAchievementID}
 	}
 
-	// #10i proses: ambil achievements dari MongoDB berdasarkan IDs
-	achievements, err := s.achievementRepo.GetAchievementsByIDs(ctx, mongoIDs)
-	if err != nil {
-		return nil, errors.New("error mengambil achievements dari MongoDB: " + err.Error())
-	}
-
-	// #10j proses: buat map reference dan student untuk lookup cepat
-	referenceMap := make(map[string]modelpostgre.AchievementReference)
-	for _, ref := range references {
-		referenceMap[ref.MongoAchievementID] = ref
-	}
-
-	studentMap := make(map[string]modelpostgre.Student)
-	for _, ref := range references {
-		if _, exists := studentMap[ref.StudentID]; !exists {
-			student, err := s.studentRepo.GetStudentByID(ctx, ref.StudentID)
-			if err == nil && student != nil {
-				studentMap[ref.StudentID] = *student
-			}
+	// #10e proses: ambil dokumen lengkap hanya untuk reference di halaman ini, urutan mengikuti reference
+	achievementMap := map[string]modelmongo.Achievement{}
+	if len(rows) > 0 {
+		ids := make([]string, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row.MongoAchievementID)
+		}
+		achievements, err := s.achievementRepo.GetAchievementsByIDs(ctx, ids)
+		if err != nil {
+			return nil, errors.New("error mengambil achievements dari MongoDB: " + err.Error())
+		}
+		for _, achievement := range achievements {
+			achievementMap[achievement.ID.Hex()] = achievement
 		}
 	}
 
-	// #10k proses: build result dengan gabungkan data MongoDB dan PostgreSQL
-	var result []map[string]interface{}
-	for _, achievement := range achievements {
-		// #10l proses: filter berdasarkan achievement type jika ada
-		if achievementTypeFilter != "" && achievement.AchievementType != achievementTypeFilter {
+	// #10f proses: build result dengan gabungkan data MongoDB dan PostgreSQL
+	now := time.Now()
+	soonDays := certificationSoonDays(s.certificationConfig)
+	result := []map[string]interface{}{}
+	for _, row := range rows {
+		achievement, exists := achievementMap[row.MongoAchievementID]
+		if !exists {
 			continue
 		}
-
-		ref := referenceMap[achievement.ID.Hex()]
+		ref := row.AchievementReference
 		item := map[string]interface{}{
 			"id":              achievement.ID.Hex(),
 			"studentId":       achievement.StudentID,
@@ -551,8 +804,12 @@ func (s *AchievementService) GetAchievements(ctx context.Context, userID string,
 			"status":          ref.Status,
 		}
 
-		if student, exists := studentMap[ref.StudentID]; exists {
-			item["student_name"] = student.FullName
+		if row.StudentName != "" {
+			item["student_name"] = row.StudentName
+		}
+
+		if certStatus := certificationStatus(achievement.Details, now, soonDays); certStatus != "" {
+			item["certification_status"] = certStatus
 		}
 
 		if ref.SubmittedAt != nil {
@@ -576,26 +833,45 @@ func (s *AchievementService) GetAchievements(ctx context.Context, userID string,
 		result = append(result, item)
 	}
 
-	// #10m proses: jika ada filter achievement type, adjust total
-	if achievementTypeFilter != "" {
-		total = len(result)
+	// #10g proses: mode cursor mengembalikan next_cursor tanpa nomor halaman, total, dan facet
+	if filter.CursorMode {
+		pagination := modelpostgre.CursorPagination{Limit: filter.Limit, HasMore: nextCursor != nil}
+		if nextCursor != nil {
+			pagination.NextCursor = helper.EncodeCursor(nextCursor.CreatedAt, nextCursor.ID)
+		}
+
+		return map[string]interface{}{
+			"status":     "success",
+			"data":       result,
+			"pagination": pagination,
+		}, nil
 	}
 
-	// #10n proses: hitung total pages dan build response dengan pagination
+	// #10h proses: hitung total pages dan build response dengan pagination dan facet
+	total := scan.Total
 	totalPages := 0
 	if total > 0 {
-		totalPages = (total + limit - 1) / limit
+		totalPages = (total + filter.Limit - 1) / filter.Limit
+	}
+
+	pagination := map[string]interface{}{
+		"page":        filter.Page,
+		"limit":       filter.Limit,
+		"total":       total,
+		"total_pages": totalPages,
+	}
+
+	// #10i proses: urutan created_at menyertakan cursor supaya client bisa lanjut dengan mode cursor
+	if isCursorSortable(filter.SortBy) && len(rows) > 0 && filter.Page*filter.Limit < total {
+		last := rows[len(rows)-1]
+		pagination["next_cursor"] = helper.EncodeCursor(last.CreatedAt, last.MongoAchievementID)
 	}
 
 	return map[string]interface{}{
-		"status": "success",
-		"data":   result,
-		"pagination": map[string]interface{}{
-			"page":        page,
-			"limit":       limit,
-			"total":       total,
-			"total_pages": totalPages,
-		},
+		"status":     "success",
+		"data":       result,
+		"facets":     scan.Facets,
+		"pagination": pagination,
 	}, nil
 }
 
@@ -649,6 +925,8 @@ func (s *AchievementService) GetAchievementsByStudentID(ctx context.Context, stu
 		referenceMap[ref.MongoAchievementID] = ref
 	}
 
+	now := time.Now()
+	soonDays := certificationSoonDays(s.certificationConfig)
 	var result []map[string]interface{}
 	for _, achievement := range achievements {
 		ref := referenceMap[achievement.ID.Hex()]
@@ -667,6 +945,10 @@ func (s *AchievementService) GetAchievementsByStudentID(ctx context.Context, stu
 			"status":          ref.Status,
 		}
 
+		if certStatus := certificationStatus(achievement.Details, now, soonDays); certStatus != "" {
+			item["certification_status"] = certStatus
+		}
+
 		if ref.SubmittedAt != nil {
 			item["submitted_at"] = ref.SubmittedAt.Format(time.RFC3339)
 		}
@@ -707,62 +989,18 @@ func (s *AchievementService) GetAchievementsByStudentID(ctx context.Context, stu
 
 // #15 proses: ambil achievement detail berdasarkan ID dengan validasi akses
 func (s *AchievementService) GetAchievementByID(ctx context.Context, userID string, roleID string, mongoID string) (map[string]interface{}, error) {
-	// #15a proses: ambil achievement reference untuk validasi akses
-	ref, err := s.achievementRefRepo.GetAchievementReferenceByMongoID(ctx, mongoID)
+	// #15a proses: ambil achievement dan reference dengan validasi akses berdasarkan role
+	ref, achievement, roleName, err := s.loadViewableAchievement(ctx, userID, roleID, mongoID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, errors.New("prestasi tidak ditemukan")
-		}
 		return nil, err
 	}
 
-	// #15b proses: validasi akses berdasarkan role
-	roleName, err := s.userRepo.GetRoleName(ctx, roleID)
-	if err != nil {
-		return nil, errors.New("error mengambil role name: " + err.Error())
-	}
-
-	if roleName == "Mahasiswa" {
-		// #15c proses: mahasiswa hanya bisa lihat prestasi sendiri
-		studentID, err := s.studentRepo.GetStudentIDByUserID(ctx, userID)
-		if err != nil {
-			return nil, errors.New("error mengambil data mahasiswa: " + err.Error())
-		}
-
-		if ref.StudentID != studentID {
-			return nil, errors.New("akses ditolak. Anda hanya dapat melihat prestasi milik Anda sendiri")
-		}
-	} else if roleName == "Dosen Wali" {
-		// #15d proses: dosen wali hanya bisa lihat prestasi mahasiswa bimbingan
-		lecturer, err := s.userRepo.GetLecturerByUserID(ctx, userID)
-		if err != nil {
-			if err == sql.ErrNoRows {
-				return nil, errors.New("data dosen wali tidak ditemukan. Pastikan user memiliki profil dosen wali")
-			}
-			return nil, err
-		}
-
-		student, err := s.studentRepo.GetStudentByID(ctx, ref.StudentID)
-		if err != nil {
-			return nil, errors.New("error mengambil data student: " + err.Error())
-		}
-
-		if student.AdvisorID != lecturer.ID {
-			return nil, errors.New("akses ditolak. Anda hanya dapat melihat prestasi mahasiswa bimbingan Anda")
-		}
-	} else if roleName != "Admin" {
-		return nil, errors.New("akses ditolak. Role tidak memiliki akses untuk melihat prestasi")
-	}
-
-	// #15e proses: ambil achievement dari MongoDB
-	achievement, err := s.achievementRepo.GetAchievementByID(ctx, mongoID)
-	if err != nil {
-		return nil, errors.New("error mengambil achievement dari database: " + err.Error())
-	}
-	if achievement == nil {
-		return nil, errors.New("prestasi tidak ditemukan")
+	// #15b proses: dosen wali membuka prestasi submitted berarti review dimulai, mahasiswa tidak bisa withdraw lagi
+	if roleName == "Dosen Wali" {
+		s.markReviewStarted(ctx, ref, userID)
 	}
 
+	// #15c proses: build result dengan gabungkan data MongoDB dan reference, attachment diberi URL download
 	result := map[string]interface{}{
 		"id":              achievement.ID.Hex(),
 		"studentId":       achievement.StudentID,
@@ -770,14 +1008,29 @@ func (s *AchievementService) GetAchievementByID(ctx context.Context, userID stri
 		"title":           achievement.Title,
 		"description":     achievement.Description,
 		"details":         achievement.Details,
-		"attachments":     achievement.Attachments,
+		"attachments":     s.withDownloadURLs(mongoID, achievement.Attachments),
 		"tags":            achievement.Tags,
 		"points":          achievement.Points,
+		"rubricVersion":   achievement.RubricVersion,
 		"createdAt":       achievement.CreatedAt.Format(time.RFC3339),
 		"updatedAt":       achievement.UpdatedAt.Format(time.RFC3339),
 		"status":          ref.Status,
 	}
 
+	if achievement.PointsOverride != nil {
+		result["pointsOverride"] = achievement.PointsOverride
+	}
+
+	if achievement.IsTeam() {
+		result["teamMembers"] = achievement.TeamMembers
+		if achievement.DesignatedAdvisorID != "" {
+			result["designatedAdvisorId"] = achievement.DesignatedAdvisorID
+		}
+		if len(achievement.TeamVerifications) > 0 {
+			result["teamVerifications"] = achievement.TeamVerifications
+		}
+	}
+
 	if ref.SubmittedAt != nil {
 		result["submitted_at"] = ref.SubmittedAt.Format(time.RFC3339)
 	}
@@ -799,14 +1052,21 @@ func (s *AchievementService) GetAchievementByID(ctx context.Context, userID stri
 		result["rejection_note"] = *ref.RejectionNote
 	}
 
+	// #15d proses: tandai kemungkinan duplikat untuk dosen wali dan admin, mahasiswa hanya melihat detail duplikat milik sendiri
+	if duplicates := s.findDuplicateCandidates(ctx, achievement, roleName == "Mahasiswa"); len(duplicates) > 0 {
+		result["duplicates"] = duplicates
+	}
+
+	// #15e proses: sertakan ETag supaya client bisa mengirim If-Match saat update, verifikasi, atau penolakan
 	return map[string]interface{}{
 		"status": "success",
 		"data":   result,
+		"etag":   achievementETag(achievement, ref),
 	}, nil
 }
 
-// #16 proses: update achievement, hanya bisa jika status draft dan milik user sendiri
-func (s *AchievementService) UpdateAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelmongo.UpdateAchievementRequest) (map[string]interface{}, error) {
+// #16 proses: update achievement, hanya bisa jika status draft, milik user sendiri, dan versi masih sama dengan expected dari If-Match
+func (s *AchievementService) UpdateAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelmongo.UpdateAchievementRequest, expected modelpostgre.AchievementVersion) (map[string]interface{}, error) {
 	// #16a proses: validasi user harus memiliki role Mahasiswa
 	roleName, err := s.userRepo.GetRoleName(ctx, roleID)
 	if err != nil {
@@ -841,29 +1101,99 @@ func (s *AchievementService) UpdateAchievement(ctx context.Context, userID strin
 		return nil, errors.New("akses ditolak. Anda hanya dapat mengupdate prestasi milik Anda sendiri")
 	}
 
-	// #16e proses: jika ada achievement type, validasi harus salah satu tipe yang diizinkan
+	// #16e proses: gabungkan data lama dengan request lalu validasi tipe, detail, dan custom field sesuai skema tipe prestasi
+	existing, err := s.achievementRepo.GetAchievementByID(ctx, mongoID)
+	if err != nil {
+		return nil, errors.New("error mengambil data prestasi: " + err.Error())
+	}
+
+	if existing == nil {
+		return nil, errors.New("prestasi tidak ditemukan")
+	}
+
+	// #16e0 proses: tolak update jika prestasi sudah diubah di tab atau perangkat lain sejak data diambil
+	if err := checkAchievementVersion(existing, ref, expected); err != nil {
+		return nil, err
+	}
+
+	mergedType := existing.AchievementType
 	if req.AchievementType != "" {
-		validTypes := map[string]bool{
-			"academic":      true,
-			"competition":   true,
-			"organization":  true,
-			"publication":   true,
-			"certification": true,
-			"other":         true,
+		mergedType = req.AchievementType
+	}
+
+	mergedDetails := existing.Details
+	if req.Details != nil {
+		mergedDetails = *req.Details
+	}
+
+	if err := s.achievementTypeService.ValidateAchievement(ctx, mergedType, mergedDetails); err != nil {
+		return nil, err
+	}
+
+	// #16e1 proses: validasi perubahan anggota tim, anggota yang peran atau porsinya berubah wajib konfirmasi ulang
+	teamChanged := req.TeamMembers != nil || req.DesignatedAdvisorID != nil
+	var teamMembers []modelmongo.TeamMember
+	designatedAdvisorID := existing.DesignatedAdvisorID
+	if teamChanged {
+		memberReqs := make([]modelmongo.TeamMemberRequest, 0, len(existing.TeamMembers))
+		if req.TeamMembers != nil {
+			memberReqs = *req.TeamMembers
+		} else {
+			for _, member := range existing.TeamMembers {
+				memberReqs = append(memberReqs, modelmongo.TeamMemberRequest{StudentID: member.StudentID, Role: member.Role, PointShare: member.PointShare})
+			}
+		}
+		if req.DesignatedAdvisorID != nil {
+			designatedAdvisorID = strings.TrimSpace(*req.DesignatedAdvisorID)
 		}
 
-		if !validTypes[req.AchievementType] {
-			return nil, errors.New("achievement type tidak valid. Gunakan: academic, competition, organization, publication, certification, atau other")
+		teamMembers, err = s.buildTeamMembers(ctx, studentID, memberReqs, designatedAdvisorID, existing.TeamMembers)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	// #16f proses: update achievement di MongoDB
-	updatedAchievement, err := s.achievementRepo.UpdateAchievement(ctx, mongoID, req)
+	// #16f proses: update achievement di MongoDB, bersyarat versi supaya dua update yang balapan tidak saling menimpa
+	updatedAchievement, err := s.achievementRepo.UpdateAchievement(ctx, mongoID, expected.Achievement, req)
 	if err != nil {
 		return nil, errors.New("error mengupdate prestasi di database: " + err.Error())
 	}
+	if updatedAchievement == nil {
+		return nil, errors.New(versionConflictMessage)
+	}
 
-	// #16g proses: build result dengan gabungkan data MongoDB dan reference
+	// #16f1 proses: simpan anggota tim lalu kirim undangan konfirmasi ke anggota yang belum konfirmasi
+	if teamChanged {
+		if err := s.achievementRepo.UpdateTeamMembers(ctx, mongoID, teamMembers, designatedAdvisorID); err != nil {
+			return nil, errors.New("error menyimpan anggota tim: " + err.Error())
+		}
+		updatedAchievement.TeamMembers = teamMembers
+		updatedAchievement.DesignatedAdvisorID = designatedAdvisorID
+		if len(teamMembers) == 0 {
+			updatedAchievement.DesignatedAdvisorID = ""
+		}
+		s.notifyTeamInvitations(ctx, updatedAchievement, ref.ID)
+	}
+
+	// #16g proses: hitung ulang poin karena tipe atau detail prestasi bisa berubah
+	calculation, err := s.pointRubricService.CalculatePoints(ctx, updatedAchievement.AchievementType, updatedAchievement.Details)
+	if err != nil {
+		return nil, errors.New("error menghitung poin prestasi: " + err.Error())
+	}
+
+	err = s.achievementRepo.UpdateAchievementPoints(ctx, mongoID, calculation.Points, calculation.RubricVersion, nil)
+	if err != nil {
+		return nil, errors.New("error menyimpan poin prestasi: " + err.Error())
+	}
+	updatedAchievement.Points = calculation.Points
+	updatedAchievement.RubricVersion = calculation.RubricVersion
+
+	// #16g1 proses: hitung ulang kunci duplikat karena judul atau detail pembanding bisa berubah
+	if err := s.refreshDuplicateKeys(ctx, updatedAchievement); err != nil {
+		return nil, errors.New("error menyimpan kunci duplikat prestasi: " + err.Error())
+	}
+
+	// #16h proses: build result dengan gabungkan data MongoDB dan reference
 	result := map[string]interface{}{
 		"id":              updatedAchievement.ID.Hex(),
 		"studentId":       updatedAchievement.StudentID,
@@ -874,16 +1204,31 @@ func (s *AchievementService) UpdateAchievement(ctx context.Context, userID strin
 		"attachments":     updatedAchievement.Attachments,
 		"tags":            updatedAchievement.Tags,
 		"points":          updatedAchievement.Points,
+		"rubricVersion":   updatedAchievement.RubricVersion,
 		"createdAt":       updatedAchievement.CreatedAt.Format(time.RFC3339),
 		"updatedAt":       updatedAchievement.UpdatedAt.Format(time.RFC3339),
 		"status":          ref.Status,
 	}
 
-	// #16h proses: build response dengan achievement yang sudah diupdate
-	return map[string]interface{}{
+	if updatedAchievement.IsTeam() {
+		result["teamMembers"] = updatedAchievement.TeamMembers
+		if updatedAchievement.DesignatedAdvisorID != "" {
+			result["designatedAdvisorId"] = updatedAchievement.DesignatedAdvisorID
+		}
+	}
+
+	// #16i proses: build response dengan achievement yang sudah diupdate, sertakan peringatan duplikat jika ada
+	response := map[string]interface{}{
 		"status": "success",
 		"data":   result,
-	}, nil
+		"etag":   achievementETag(updatedAchievement, ref),
+	}
+
+	if duplicateWarnings := s.findDuplicateCandidates(ctx, updatedAchievement, true); len(duplicateWarnings) > 0 {
+		response["duplicateWarnings"] = duplicateWarnings
+	}
+
+	return response, nil
 }
 
 // #12 proses: ambil statistik achievement total dan verified
@@ -912,7 +1257,7 @@ func (s *AchievementService) GetAchievementStats(ctx context.Context) (map[strin
 }
 
 // #13 proses: upload file attachment ke achievement
-func (s *AchievementService) UploadFile(ctx context.Context, userID string, roleID string, mongoID string, fileName string, fileURL string, fileType string) (*modelmongo.Attachment, error) {
+func (s *AchievementService) UploadFile(ctx context.Context, userID string, roleID string, mongoID string, fileName string, fileType string, size int64, content io.Reader) (*modelmongo.Attachment, error) {
 	// #13a proses: ambil achievement reference untuk validasi
 	ref, err := s.achievementRefRepo.GetAchievementReferenceByMongoID(ctx, mongoID)
 	if err != nil {
@@ -937,19 +1282,30 @@ func (s *AchievementService) UploadFile(ctx context.Context, userID string, role
 		return nil, errors.New("attachment hanya dapat ditambahkan jika status prestasi adalah draft")
 	}
 
-	// #13d proses: buat attachment object dan tambahkan ke achievement
-	attachment := modelmongo.Attachment{
-		FileName:   fileName,
-		FileURL:    fileURL,
-		FileType:   fileType,
-		UploadedAt: time.Now(),
+	// #13d proses: validasi, cek kuota, dan simpan file ke storage. Attachment diberi ID tetap supaya bisa dihapus atau diganti
+	stored, err := s.storeAttachmentFile(ctx, studentID, 0, fileName, fileType, size, content)
+	if err != nil {
+		return nil, err
 	}
+	attachment := stored.attachment
+	attachment.ID = primitive.NewObjectID().Hex()
 
-	_, err = s.achievementRepo.AddAttachmentToAchievement(ctx, mongoID, attachment)
+	// #13e proses: tambahkan attachment ke achievement, file dan preview di storage dilepas lagi jika gagal
+	updatedAchievement, err := s.achievementRepo.AddAttachmentToAchievement(ctx, mongoID, attachment)
 	if err != nil {
+		s.discardAttachmentFile(ctx, stored)
 		return nil, errors.New("error menambahkan attachment ke prestasi: " + err.Error())
 	}
+	s.commitAttachmentFile(ctx, stored)
 
+	// #13f proses: hitung ulang kunci duplikat supaya hash attachment ikut dicocokkan
+	if updatedAchievement != nil {
+		if err := s.refreshDuplicateKeys(ctx, updatedAchievement); err != nil {
+			return nil, errors.New("error menyimpan kunci duplikat prestasi: " + err.Error())
+		}
+	}
+
+	setAttachmentDownloadURLs(&attachment, mongoID, stored.key)
 	return &attachment, nil
 }
 
@@ -1002,8 +1358,20 @@ func (s *AchievementService) GetAchievementHistory(ctx context.Context, userID s
 		return nil, errors.New("akses ditolak. Role tidak memiliki akses untuk melihat history prestasi")
 	}
 
-	// #14e proses: build history dari status changes berdasarkan timestamp di reference
-	var history []map[string]interface{}
+	// #14e proses: build history dari status changes berdasarkan timestamp di reference, ditambah aksi yang tercatat di tabel history
+	recorded, err := s.achievementRefRepo.GetAchievementHistoryByRefID(ctx, ref.ID)
+	if err != nil {
+		return nil, errors.New("error mengambil history prestasi: " + err.Error())
+	}
+
+	hasRecordedSubmit := false
+	for _, entry := range recorded {
+		if entry.Action == modelpostgre.AchievementHistoryActionSubmitted {
+			hasRecordedSubmit = true
+		}
+	}
+
+	var entries []historyEntry
 
 	// #14f proses: tambahkan entry untuk status draft saat pertama dibuat
 	draftEntry := map[string]interface{}{
@@ -1013,10 +1381,10 @@ func (s *AchievementService) GetAchievementHistory(ctx context.Context, userID s
 		"changed_by_name": nil,
 		"note":            nil,
 	}
-	history = append(history, draftEntry)
+	entries = append(entries, historyEntry{at: ref.CreatedAt, data: draftEntry})
 
-	// #14g proses: jika ada submitted_at, tambahkan entry untuk status submitted
-	if ref.SubmittedAt != nil {
+	// #14g proses: jika ada submitted_at dan submit belum tercatat di tabel history (data lama), tambahkan entry untuk status submitted
+	if ref.SubmittedAt != nil && !hasRecordedSubmit {
 		submittedEntry := map[string]interface{}{
 			"status":          modelpostgre.AchievementStatusSubmitted,
 			"changed_at":      ref.SubmittedAt.Format(time.RFC3339),
@@ -1024,7 +1392,7 @@ func (s *AchievementService) GetAchievementHistory(ctx context.Context, userID s
 			"changed_by_name": nil,
 			"note":            nil,
 		}
-		history = append(history, submittedEntry)
+		entries = append(entries, historyEntry{at: *ref.SubmittedAt, data: submittedEntry})
 	}
 
 	// #14h proses: jika status verified, tambahkan entry dengan info verified_by
@@ -1045,7 +1413,7 @@ func (s *AchievementService) GetAchievementHistory(ctx context.Context, userID s
 			"changed_by_name": verifiedByName,
 			"note":            nil,
 		}
-		history = append(history, verifiedEntry)
+		entries = append(entries, historyEntry{at: *ref.VerifiedAt, data: verifiedEntry})
 	}
 
 	// #14i proses: jika status rejected, tambahkan entry dengan rejection note
@@ -1066,7 +1434,7 @@ func (s *AchievementService) GetAchievementHistory(ctx context.Context, userID s
 			"changed_by_name": verifiedByName,
 			"note":            ref.RejectionNote,
 		}
-		history = append(history, rejectedEntry)
+		entries = append(entries, historyEntry{at: ref.UpdatedAt, data: rejectedEntry})
 	}
 
 	// #14j proses: jika status deleted, tambahkan entry untuk status deleted
@@ -1078,7 +1446,32 @@ func (s *AchievementService) GetAchievementHistory(ctx context.Context, userID s
 			"changed_by_name": nil,
 			"note":            nil,
 		}
-		history = append(history, deletedEntry)
+		entries = append(entries, historyEntry{at: ref.UpdatedAt, data: deletedEntry})
+	}
+
+	// #14j1 proses: tambahkan aksi yang tercatat seperti submit, withdraw, dan review dimulai
+	for _, entry := range recorded {
+		entries = append(entries, historyEntry{
+			at: entry.CreatedAt,
+			data: map[string]interface{}{
+				"status":          entry.Status,
+				"action":          entry.Action,
+				"changed_at":      entry.CreatedAt.Format(time.RFC3339),
+				"changed_by":      entry.ChangedBy,
+				"changed_by_name": s.userFullName(ctx, entry.ChangedBy),
+				"note":            entry.Note,
+			},
+		})
+	}
+
+	// #14j2 proses: urutkan history berdasarkan waktu
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].at.Before(entries[j].at)
+	})
+
+	history := make([]map[string]interface{}, 0, len(entries))
+	for _, entry := range entries {
+		history = append(history, entry.data)
 	}
 
 	// #14k proses: build response dengan history
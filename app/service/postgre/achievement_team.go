@@ -0,0 +1,344 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk context, database, errors, fmt, math, model, strings, dan time
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	"strings"
+	"time"
+)
+
+// #2 proses: batas minimal anggota, toleransi total porsi poin prestasi tim, dan batas percobaan ulang verifikasi tim yang balapan
+const (
+	minTeamMembers             = 2
+	teamShareTotal             = 100.0
+	teamShareTolerance         = 0.01
+	teamValidationTitle        = "data tim prestasi tidak valid"
+	teamVerificationAttempts   = 3
+	teamAlreadyVerifiedMessage = "prestasi tim ini sudah Anda verifikasi. Menunggu verifikasi dosen wali lain"
+)
+
+// #3 proses: validasi dan bangun daftar anggota tim dari request, pembuat prestasi otomatis terkonfirmasi
+func (s *AchievementService) buildTeamMembers(ctx context.Context, ownerStudentID string, reqs []modelmongo.TeamMemberRequest, designatedAdvisorID string, previous []modelmongo.TeamMember) ([]modelmongo.TeamMember, error) {
+	var fieldErrors []modelmongo.FieldError
+	seen := map[string]bool{}
+
+	// #3a proses: prestasi individu tidak boleh punya dosen wali yang ditunjuk
+	if len(reqs) == 0 {
+		if designatedAdvisorID != "" {
+			addFieldError(&fieldErrors, seen, "designatedAdvisorId", "hanya berlaku untuk prestasi tim")
+			return nil, &modelmongo.ValidationError{Message: teamValidationTitle, Errors: fieldErrors}
+		}
+		return nil, nil
+	}
+
+	if len(reqs) < minTeamMembers {
+		addFieldError(&fieldErrors, seen, "teamMembers", fmt.Sprintf("prestasi tim minimal memiliki %d anggota", minTeamMembers))
+	}
+
+	// #3b proses: simpan status konfirmasi lama supaya anggota yang peran dan porsinya tidak berubah tidak perlu konfirmasi ulang
+	previousMembers := map[string]modelmongo.TeamMember{}
+	for _, member := range previous {
+		previousMembers[member.StudentID] = member
+	}
+
+	now := time.Now()
+	members := make([]modelmongo.TeamMember, 0, len(reqs))
+	studentIDs := map[string]bool{}
+	advisorIDs := map[string]bool{}
+	totalShare := 0.0
+	ownerIncluded := false
+
+	for i, req := range reqs {
+		field := fmt.Sprintf("teamMembers[%d]", i)
+		studentID := strings.TrimSpace(req.StudentID)
+		role := strings.TrimSpace(req.Role)
+
+		// #3c proses: validasi student ID unik dan terdaftar
+		if studentID == "" {
+			addFieldError(&fieldErrors, seen, field+".studentId", "wajib diisi")
+			continue
+		}
+		if studentIDs[studentID] {
+			addFieldError(&fieldErrors, seen, field+".studentId", "anggota tim duplikat")
+			continue
+		}
+		studentIDs[studentID] = true
+
+		student, err := s.studentRepo.GetStudentByID(ctx, studentID)
+		if err != nil || student == nil {
+			if err != nil && err != sql.ErrNoRows {
+				return nil, errors.New("error mengambil data anggota tim: " + err.Error())
+			}
+			addFieldError(&fieldErrors, seen, field+".studentId", "mahasiswa tidak ditemukan")
+			continue
+		}
+		if student.AdvisorID != "" {
+			advisorIDs[student.AdvisorID] = true
+		}
+
+		// #3d proses: validasi peran dan porsi poin anggota
+		if role == "" {
+			addFieldError(&fieldErrors, seen, field+".role", "wajib diisi")
+		}
+		if req.PointShare <= 0 || req.PointShare > teamShareTotal {
+			addFieldError(&fieldErrors, seen, field+".pointShare", "harus lebih dari 0 dan maksimal 100")
+		}
+		totalShare += req.PointShare
+
+		member := modelmongo.TeamMember{
+			StudentID:  studentID,
+			Role:       role,
+			PointShare: req.PointShare,
+		}
+
+		// #3e proses: pembuat prestasi otomatis terkonfirmasi, anggota lain tetap terkonfirmasi jika peran dan porsi tidak berubah
+		if studentID == ownerStudentID {
+			ownerIncluded = true
+			member.Confirmed = true
+			member.ConfirmedAt = &now
+		} else if old, ok := previousMembers[studentID]; ok && old.Confirmed && old.Role == role && old.PointShare == req.PointShare {
+			member.Confirmed = true
+			member.ConfirmedAt = old.ConfirmedAt
+		}
+
+		members = append(members, member)
+	}
+
+	// #3f proses: pembuat prestasi wajib menjadi anggota dan total porsi poin harus 100
+	if !ownerIncluded {
+		addFieldError(&fieldErrors, seen, "teamMembers", "pembuat prestasi harus termasuk dalam anggota tim")
+	}
+	if math.Abs(totalShare-teamShareTotal) > teamShareTolerance {
+		addFieldError(&fieldErrors, seen, "teamMembers.pointShare", fmt.Sprintf("total porsi poin harus 100, saat ini %.2f", totalShare))
+	}
+
+	// #3g proses: dosen wali yang ditunjuk harus dosen wali dari salah satu anggota tim
+	if designatedAdvisorID != "" && !advisorIDs[designatedAdvisorID] {
+		addFieldError(&fieldErrors, seen, "designatedAdvisorId", "harus dosen wali dari salah satu anggota tim")
+	}
+
+	if len(fieldErrors) > 0 {
+		return nil, &modelmongo.ValidationError{Message: teamValidationTitle, Errors: fieldErrors}
+	}
+
+	return members, nil
+}
+
+// #4 proses: tentukan dosen wali yang wajib memverifikasi prestasi tim, dosen wali yang ditunjuk atau dosen wali setiap anggota
+func (s *AchievementService) requiredTeamAdvisors(ctx context.Context, achievement *modelmongo.Achievement) ([]string, error) {
+	if achievement.DesignatedAdvisorID != "" {
+		return []string{achievement.DesignatedAdvisorID}, nil
+	}
+
+	advisorIDs := []string{}
+	seen := map[string]bool{}
+	for _, member := range achievement.TeamMembers {
+		student, err := s.studentRepo.GetStudentByID(ctx, member.StudentID)
+		if err != nil {
+			return nil, errors.New("error mengambil data anggota tim: " + err.Error())
+		}
+		if student.AdvisorID != "" && !seen[student.AdvisorID] {
+			seen[student.AdvisorID] = true
+			advisorIDs = append(advisorIDs, student.AdvisorID)
+		}
+	}
+
+	if len(advisorIDs) == 0 {
+		return nil, errors.New("anggota tim belum memiliki dosen wali")
+	}
+
+	return advisorIDs, nil
+}
+
+// #5 proses: cek apakah dosen wali berhak memverifikasi atau melihat prestasi, prestasi tim memakai daftar dosen wali anggota
+func (s *AchievementService) isAchievementAdvisor(ctx context.Context, achievement *modelmongo.Achievement, ownerStudentID string, lecturerID string) (bool, error) {
+	if achievement != nil && achievement.IsTeam() {
+		advisorIDs, err := s.requiredTeamAdvisors(ctx, achievement)
+		if err != nil {
+			return false, err
+		}
+		return containsString(advisorIDs, lecturerID), nil
+	}
+
+	student, err := s.studentRepo.GetStudentByID(ctx, ownerStudentID)
+	if err != nil {
+		return false, errors.New("error mengambil data student: " + err.Error())
+	}
+
+	return student.AdvisorID == lecturerID, nil
+}
+
+// #6 proses: catat verifikasi dosen wali untuk prestasi tim, return daftar dosen wali yang belum memverifikasi. Jika verifikasi ini melengkapi semua dosen wali, penyimpanan tidak langsung dijalankan tetapi dikembalikan sebagai langkah saga supaya ikut dibatalkan jika poin atau status gagal disimpan
+func (s *AchievementService) recordTeamVerification(ctx context.Context, achievement *modelmongo.Achievement, lecturerID string, userID string) ([]string, *sagaStep, error) {
+	requiredAdvisorIDs, err := s.requiredTeamAdvisors(ctx, achievement)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	otherAdvisorIDs := []string{}
+	for _, advisorID := range requiredAdvisorIDs {
+		if advisorID != lecturerID {
+			otherAdvisorIDs = append(otherAdvisorIDs, advisorID)
+		}
+	}
+
+	mongoID := achievement.ID.Hex()
+	verification := modelmongo.TeamVerification{
+		AdvisorID:  lecturerID,
+		VerifiedBy: userID,
+		VerifiedAt: time.Now(),
+	}
+	verifications := achievement.TeamVerifications
+
+	for attempt := 0; attempt < teamVerificationAttempts; attempt++ {
+		// #6a proses: dosen wali yang sama tidak bisa memverifikasi dua kali
+		verified := map[string]bool{}
+		for _, existing := range verifications {
+			verified[existing.AdvisorID] = true
+		}
+		if verified[lecturerID] {
+			return nil, nil, errors.New(teamAlreadyVerifiedMessage)
+		}
+
+		// #6b proses: kumpulkan dosen wali lain yang belum memverifikasi
+		pending := []string{}
+		for _, advisorID := range otherAdvisorIDs {
+			if !verified[advisorID] {
+				pending = append(pending, advisorID)
+			}
+		}
+
+		// #6c proses: verifikasi terakhir dijalankan di saga, $push bersyarat gagal jika dosen wali ini sudah memverifikasi lewat request lain
+		if len(pending) == 0 {
+			return nil, &sagaStep{
+				name: "record final team verification",
+				action: func(ctx context.Context) error {
+					added, err := s.achievementRepo.AddTeamVerification(ctx, mongoID, verification, otherAdvisorIDs, true)
+					if err != nil {
+						return errors.New("error menyimpan verifikasi tim: " + err.Error())
+					}
+					if !added {
+						return errors.New(teamAlreadyVerifiedMessage)
+					}
+					return nil
+				},
+				compensate: func(ctx context.Context) error {
+					return s.achievementRepo.RemoveTeamVerification(ctx, mongoID, lecturerID)
+				},
+			}, nil
+		}
+
+		// #6d proses: verifikasi yang belum terakhir langsung disimpan, $push bersyarat gagal jika dosen wali lain melengkapi verifikasi di saat yang sama
+		added, err := s.achievementRepo.AddTeamVerification(ctx, mongoID, verification, otherAdvisorIDs, false)
+		if err != nil {
+			return nil, nil, errors.New("error menyimpan verifikasi tim: " + err.Error())
+		}
+		if added {
+			achievement.TeamVerifications = append(verifications, verification)
+			return pending, nil, nil
+		}
+
+		// #6e proses: baca ulang verifikasi terbaru lalu tentukan lagi apakah verifikasi ini yang terakhir
+		latest, err := s.achievementRepo.GetAchievementByID(ctx, mongoID)
+		if err != nil {
+			return nil, nil, errors.New("error mengambil achievement dari database: " + err.Error())
+		}
+		if latest == nil {
+			return nil, nil, errors.New("prestasi tidak ditemukan")
+		}
+		verifications = latest.TeamVerifications
+	}
+
+	return nil, nil, errors.New(versionConflictMessage)
+}
+
+// #7 proses: daftar anggota tim yang belum mengkonfirmasi partisipasi
+func unconfirmedTeamMembers(achievement *modelmongo.Achievement) []string {
+	pending := []string{}
+	for _, member := range achievement.TeamMembers {
+		if !member.Confirmed {
+			pending = append(pending, member.StudentID)
+		}
+	}
+	return pending
+}
+
+// #8 proses: kirim undangan konfirmasi ke anggota tim yang belum konfirmasi, error notifikasi tidak menggagalkan proses utama
+func (s *AchievementService) notifyTeamInvitations(ctx context.Context, achievement *modelmongo.Achievement, refID string) {
+	for _, studentID := range unconfirmedTeamMembers(achievement) {
+		if err := s.notificationService.CreateTeamInvitationNotification(ctx, studentID, achievement.ID.Hex(), refID); err != nil {
+			fmt.Printf("Error creating team invitation notification: %v\n", err)
+		}
+	}
+}
+
+// #9 proses: konfirmasi partisipasi anggota tim, hanya bisa saat prestasi masih draft
+func (s *AchievementService) ConfirmTeamParticipation(ctx context.Context, userID string, roleID string, mongoID string) (*modelmongo.ConfirmTeamParticipationResponse, error) {
+	// #9a proses: validasi user harus memiliki role Mahasiswa
+	roleName, err := s.userRepo.GetRoleName(ctx, roleID)
+	if err != nil {
+		return nil, errors.New("error mengambil role name: " + err.Error())
+	}
+
+	if roleName != "Mahasiswa" {
+		return nil, errors.New("akses ditolak. Hanya mahasiswa yang dapat mengkonfirmasi partisipasi tim")
+	}
+
+	// #9b proses: ambil achievement reference dan validasi status draft
+	ref, err := s.achievementRefRepo.GetAchievementReferenceByMongoID(ctx, mongoID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("prestasi tidak ditemukan")
+		}
+		return nil, err
+	}
+
+	if ref.Status != modelpostgre.AchievementStatusDraft {
+		return nil, errors.New("partisipasi tim hanya dapat dikonfirmasi jika status prestasi adalah draft")
+	}
+
+	// #9c proses: ambil achievement dan validasi prestasi tim
+	achievement, err := s.achievementRepo.GetAchievementByID(ctx, mongoID)
+	if err != nil {
+		return nil, errors.New("error mengambil data prestasi: " + err.Error())
+	}
+	if achievement == nil {
+		return nil, errors.New("prestasi tidak ditemukan")
+	}
+	if !achievement.IsTeam() {
+		return nil, errors.New("prestasi ini bukan prestasi tim")
+	}
+
+	// #9d proses: validasi user adalah anggota tim
+	studentID, err := s.studentRepo.GetStudentIDByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.New("error mengambil data mahasiswa: " + err.Error())
+	}
+
+	member := achievement.FindTeamMember(studentID)
+	if member == nil {
+		return nil, errors.New("akses ditolak. Anda bukan anggota tim prestasi ini")
+	}
+
+	// #9e proses: simpan konfirmasi jika belum pernah konfirmasi
+	if !member.Confirmed {
+		now := time.Now()
+		if err := s.achievementRepo.ConfirmTeamMember(ctx, mongoID, studentID, now); err != nil {
+			return nil, errors.New("error menyimpan konfirmasi partisipasi: " + err.Error())
+		}
+		member.Confirmed = true
+		member.ConfirmedAt = &now
+	}
+
+	// #9f proses: build response dengan daftar anggota tim terbaru
+	return &modelmongo.ConfirmTeamParticipationResponse{
+		Status: "success",
+		Data:   achievement.TeamMembers,
+	}, nil
+}
@@ -0,0 +1,368 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk context, database, errors, fmt, os, slices, strconv, strings, dan time
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// #2 proses: baca konfigurasi retensi tempat sampah dari environment variable, nilai kosong atau tidak valid memakai default
+func LoadTrashRetentionConfig() modelpostgre.TrashRetentionConfig {
+	dryRun, err := strconv.ParseBool(strings.TrimSpace(os.Getenv("TRASH_PURGE_DRY_RUN")))
+	if err != nil {
+		dryRun = false
+	}
+
+	return modelpostgre.TrashRetentionConfig{
+		RetentionDays: envInt("TRASH_RETENTION_DAYS", 30, 1),
+		PurgeInterval: time.Duration(envInt("TRASH_PURGE_INTERVAL_HOURS", 24, 0)) * time.Hour,
+		DryRun:        dryRun,
+	}
+}
+
+// #3 proses: jalankan purge tempat sampah secara berkala di background, interval 0 berarti scheduler dinonaktifkan
+func StartTrashPurgeScheduler(ctx context.Context, achievementService IAchievementService, config modelpostgre.TrashRetentionConfig) {
+	if config.PurgeInterval <= 0 {
+		fmt.Println("Trash purge scheduler dinonaktifkan")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(config.PurgeInterval)
+		defer ticker.Stop()
+
+		for {
+			// #3a proses: setiap purge diberi timeout supaya tidak menumpuk dengan purge berikutnya
+			purgeCtx, cancel := context.WithTimeout(ctx, config.PurgeInterval)
+			result, err := achievementService.RunTrashPurge(purgeCtx, config.RetentionDays, config.DryRun)
+			cancel()
+
+			if err != nil {
+				fmt.Printf("Error running trash purge: %v\n", err)
+			} else if result.Total > 0 {
+				fmt.Printf("Trash purge (dry run: %v): %d expired, %d purged, %d files deleted\n", result.DryRun, result.Total, result.Purged, result.FilesDeleted)
+			}
+
+			// #3b proses: tunggu tick berikutnya atau berhenti jika context dibatalkan
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// #4 proses: ambil daftar prestasi di tempat sampah dengan pagination, hanya untuk admin
+func (s *AchievementService) GetDeletedAchievements(ctx context.Context, userID string, roleID string, page, limit int) (*modelpostgre.GetDeletedAchievementsResponse, error) {
+	// #4a proses: validasi user harus memiliki role Admin
+	if err := s.requireAdmin(ctx, roleID, "akses ditolak. Hanya admin yang dapat melihat tempat sampah prestasi"); err != nil {
+		return nil, err
+	}
+
+	// #4b proses: validasi dan set default untuk page dan limit
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	// #4c proses: ambil reference deleted dari PostgreSQL
+	refs, total, err := s.achievementRefRepo.GetDeletedAchievementReferencesPaginated(ctx, page, limit)
+	if err != nil {
+		return nil, errors.New("error mengambil prestasi yang dihapus: " + err.Error())
+	}
+
+	// #4d proses: ambil data achievement dari MongoDB untuk title dan tipe
+	mongoIDs := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		mongoIDs = append(mongoIDs, ref.MongoAchievementID)
+	}
+
+	achievementMap := map[string]modelmongo.Achievement{}
+	if len(mongoIDs) > 0 {
+		achievements, err := s.achievementRepo.GetDeletedAchievementsByIDs(ctx, mongoIDs)
+		if err != nil {
+			return nil, errors.New("error mengambil data prestasi dari database: " + err.Error())
+		}
+		for _, achievement := range achievements {
+			achievementMap[achievement.ID.Hex()] = achievement
+		}
+	}
+
+	// #4e proses: gabungkan reference, achievement, nama mahasiswa, dan jadwal purge
+	retentionDays := s.trashConfig.RetentionDays
+	items := make([]modelpostgre.DeletedAchievement, 0, len(refs))
+	for _, ref := range refs {
+		item := modelpostgre.DeletedAchievement{
+			DeletedAchievementReference: ref,
+			PurgeAt:                     ref.DeletedAt.AddDate(0, 0, retentionDays),
+		}
+
+		if achievement, ok := achievementMap[ref.MongoAchievementID]; ok {
+			item.Title = achievement.Title
+			item.AchievementType = achievement.AchievementType
+		}
+
+		if student, err := s.studentRepo.GetStudentByID(ctx, ref.StudentID); err == nil && student != nil {
+			item.StudentName = student.FullName
+		}
+
+		items = append(items, item)
+	}
+
+	// #4f proses: build response dengan data dan pagination info
+	totalPages := 0
+	if total > 0 {
+		totalPages = (total + limit - 1) / limit
+	}
+
+	response := &modelpostgre.GetDeletedAchievementsResponse{
+		Status: "success",
+		Data:   items,
+	}
+	response.Pagination.Page = page
+	response.Pagination.Limit = limit
+	response.Pagination.Total = total
+	response.Pagination.TotalPages = totalPages
+
+	return response, nil
+}
+
+// #5 proses: restore prestasi dari tempat sampah ke status sebelum dihapus, hanya untuk admin
+func (s *AchievementService) RestoreAchievement(ctx context.Context, userID string, roleID string, mongoID string) (*modelpostgre.UpdateAchievementReferenceResponse, error) {
+	// #5a proses: validasi user harus memiliki role Admin
+	if err := s.requireAdmin(ctx, roleID, "akses ditolak. Hanya admin yang dapat me-restore prestasi"); err != nil {
+		return nil, err
+	}
+
+	// #5b proses: ambil reference yang ada di tempat sampah
+	ref, err := s.achievementRefRepo.GetDeletedAchievementReferenceByMongoID(ctx, mongoID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("prestasi tidak ditemukan di tempat sampah")
+		}
+		return nil, err
+	}
+
+	// #5c proses: restore achievement di MongoDB dulu supaya reference tidak menunjuk ke dokumen yang masih terhapus, restore MongoDB dan PostgreSQL dijalankan sebagai satu saga
+	var status string
+	refAlreadyRestored := false
+	err = runSaga(ctx,
+		sagaStep{
+			name: "restore achievement",
+			action: func(ctx context.Context) error {
+				if err := s.achievementRepo.RestoreAchievement(ctx, mongoID); err != nil {
+					return errors.New("error me-restore prestasi di database: " + err.Error())
+				}
+				return nil
+			},
+			compensate: func(ctx context.Context) error {
+				// #5c1 proses: reference yang sudah di-restore request lain berarti dokumen MongoDB memang harus tetap aktif
+				if refAlreadyRestored {
+					return nil
+				}
+				return s.achievementRepo.DeleteAchievement(ctx, mongoID)
+			},
+		},
+		sagaStep{
+			// #5d proses: restore reference ke status sebelum dihapus, jika gagal dokumen MongoDB di-soft delete lagi oleh kompensasi
+			name: "restore achievement reference",
+			action: func(ctx context.Context) error {
+				restoredStatus, err := s.achievementRefRepo.RestoreAchievementReference(ctx, ref.ID)
+				if err == sql.ErrNoRows {
+					refAlreadyRestored = true
+					return errors.New("prestasi tidak ditemukan di tempat sampah")
+				}
+				if err != nil {
+					return errors.New("error me-restore status prestasi: " + err.Error())
+				}
+				status = restoredStatus
+				return nil
+			},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// #5e proses: catat restore di history prestasi
+	s.recordHistory(ctx, ref.ID, modelpostgre.AchievementHistoryActionRestored, status, userID, nil)
+
+	// #5f proses: ambil reference yang sudah diupdate dan build response
+	updatedRef, err := s.achievementRefRepo.GetAchievementReferenceByID(ctx, ref.ID)
+	if err != nil {
+		return nil, errors.New("error mengambil data prestasi yang diupdate: " + err.Error())
+	}
+
+	return &modelpostgre.UpdateAchievementReferenceResponse{
+		Status: "success",
+		Data:   *updatedRef,
+	}, nil
+}
+
+// #6 proses: purge tempat sampah secara manual oleh admin, dry-run hanya menampilkan yang akan dihapus
+func (s *AchievementService) PurgeDeletedAchievements(ctx context.Context, userID string, roleID string, dryRun bool) (*modelpostgre.TrashPurgeResponse, error) {
+	// #6a proses: validasi user harus memiliki role Admin
+	if err := s.requireAdmin(ctx, roleID, "akses ditolak. Hanya admin yang dapat menghapus permanen prestasi"); err != nil {
+		return nil, err
+	}
+
+	// #6b proses: jalankan purge dengan masa retensi dari konfigurasi
+	result, err := s.RunTrashPurge(ctx, s.trashConfig.RetentionDays, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return &modelpostgre.TrashPurgeResponse{
+		Status: "success",
+		Data:   *result,
+	}, nil
+}
+
+// #7 proses: hapus permanen prestasi yang sudah di tempat sampah lebih lama dari masa retensi, beserta file attachment, dokumen MongoDB, dan reference
+func (s *AchievementService) RunTrashPurge(ctx context.Context, retentionDays int, dryRun bool) (*modelpostgre.TrashPurgeResult, error) {
+	// #7a proses: ambil reference yang dihapus sebelum batas retensi
+	cutoff := time.Now().AddDate(0, 0, -retentionDays)
+	refs, err := s.achievementRefRepo.GetDeletedReferencesBefore(ctx, cutoff)
+	if err != nil {
+		return nil, errors.New("error mengambil prestasi yang dihapus: " + err.Error())
+	}
+
+	result := &modelpostgre.TrashPurgeResult{
+		DryRun:        dryRun,
+		RetentionDays: retentionDays,
+		Cutoff:        cutoff,
+		Total:         len(refs),
+		Items:         []modelpostgre.TrashPurgeItem{},
+	}
+	if len(refs) == 0 {
+		return result, nil
+	}
+
+	// #7b proses: ambil dokumen MongoDB untuk title dan daftar file attachment
+	mongoIDs := make([]string, 0, len(refs))
+	for _, ref := range refs {
+		mongoIDs = append(mongoIDs, ref.MongoAchievementID)
+	}
+
+	achievements, err := s.achievementRepo.GetDeletedAchievementsByIDs(ctx, mongoIDs)
+	if err != nil {
+		return nil, errors.New("error mengambil data prestasi dari database: " + err.Error())
+	}
+
+	achievementMap := map[string]modelmongo.Achievement{}
+	for _, achievement := range achievements {
+		achievementMap[achievement.ID.Hex()] = achievement
+	}
+
+	for _, ref := range refs {
+		item := modelpostgre.TrashPurgeItem{
+			AchievementRefID:   ref.ID,
+			MongoAchievementID: ref.MongoAchievementID,
+			DeletedAt:          ref.DeletedAt,
+			Files:              []string{},
+		}
+
+		achievement, ok := achievementMap[ref.MongoAchievementID]
+		if ok {
+			item.Title = achievement.Title
+			for _, attachment := range achievement.Attachments {
+				key, ok := s.fileStorage.KeyFromURL(attachment.FileURL)
+				if !ok || slices.Contains(item.Files, key) {
+					continue
+				}
+
+				// #7b1 proses: file dengan isi sama bisa dipakai prestasi lain, file hanya dihapus jika prestasi ini satu-satunya yang mereferensikan
+				count, err := s.achievementRepo.CountAttachmentFileReferences(ctx, attachment.FileURL)
+				if err != nil {
+					fmt.Printf("Error counting references to attachment file %s: %v\n", key, err)
+					continue
+				}
+				if count <= 1 {
+					item.Files = append(item.Files, key)
+					if attachment.HasPreview {
+						item.Files = append(item.Files, attachmentPreviewKey(key))
+					}
+				}
+			}
+		}
+
+		// #7c proses: dry-run hanya mencatat apa yang akan dihapus
+		if dryRun {
+			result.Items = append(result.Items, item)
+			continue
+		}
+
+		// #7d proses: hapus dokumen MongoDB dulu dengan syarat masih di tempat sampah, dokumen yang tidak terhapus berarti sudah di-restore request lain sehingga reference dan file dibiarkan
+		if ok {
+			purged, err := s.achievementRepo.PurgeAchievement(ctx, ref.MongoAchievementID)
+			if err != nil {
+				item.Error = "error menghapus prestasi dari database: " + err.Error()
+				item.Files = []string{}
+				result.Items = append(result.Items, item)
+				continue
+			}
+			if !purged {
+				item.Files = []string{}
+				result.Items = append(result.Items, item)
+				continue
+			}
+		}
+
+		// #7e proses: hapus reference dengan syarat masih berstatus deleted, history dan notifikasi ikut terhapus lewat ON DELETE CASCADE
+		purged, err := s.achievementRefRepo.PurgeAchievementReference(ctx, ref.ID)
+		if err != nil {
+			item.Error = "error menghapus reference prestasi: " + err.Error()
+			item.Files = []string{}
+			result.Items = append(result.Items, item)
+			continue
+		}
+		if !purged {
+			item.Files = []string{}
+			result.Items = append(result.Items, item)
+			continue
+		}
+		result.Purged++
+
+		// #7f proses: file attachment baru dihapus setelah dokumen dan reference benar-benar terhapus, file yang gagal dihapus hanya di-log
+		for _, key := range item.Files {
+			if err := s.fileStorage.Delete(ctx, key); err != nil {
+				fmt.Printf("Error removing attachment file %s: %v\n", key, err)
+				continue
+			}
+			result.FilesDeleted++
+		}
+
+		result.Items = append(result.Items, item)
+	}
+
+	return result, nil
+}
+
+// #8 proses: validasi role user adalah Admin, return error akses ditolak dengan pesan yang diberikan
+func (s *AchievementService) requireAdmin(ctx context.Context, roleID string, deniedMessage string) error {
+	roleName, err := s.userRepo.GetRoleName(ctx, roleID)
+	if err != nil {
+		return errors.New("error mengambil role name: " + err.Error())
+	}
+
+	if roleName != "Admin" {
+		return errors.New(deniedMessage)
+	}
+
+	return nil
+}
@@ -0,0 +1,463 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk context, errors, fmt, net/url, regexp, sort, strconv, strings, time, model, dan repository
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// #2 proses: regex format kode tipe prestasi dan key custom field
+var (
+	achievementTypeCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+	customFieldKeyPattern      = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]{0,49}$`)
+)
+
+// #3 proses: definisikan interface untuk service tipe prestasi dan skema custom field
+type IAchievementTypeService interface {
+	GetAchievementTypes(ctx context.Context, includeInactive bool) (*modelmongo.GetAllAchievementTypesResponse, error)
+	GetAchievementTypeByCode(ctx context.Context, code string) (*modelmongo.GetAchievementTypeResponse, error)
+	GetAchievementTypeSchema(ctx context.Context, code string) (*modelmongo.GetAchievementTypeSchemaResponse, error)
+	CreateAchievementType(ctx context.Context, userID string, req modelmongo.CreateAchievementTypeRequest) (*modelmongo.GetAchievementTypeResponse, error)
+	UpdateAchievementType(ctx context.Context, code string, req modelmongo.UpdateAchievementTypeRequest) (*modelmongo.GetAchievementTypeResponse, error)
+	ValidateAchievement(ctx context.Context, achievementType string, details modelmongo.AchievementDetails) error
+	IsValidAchievementType(ctx context.Context, code string) (bool, error)
+}
+
+// #4 proses: struct service untuk tipe prestasi
+type AchievementTypeService struct {
+	achievementTypeRepo repositorymongo.IAchievementTypeRepository
+}
+
+// #5 proses: constructor untuk membuat instance AchievementTypeService baru
+func NewAchievementTypeService(achievementTypeRepo repositorymongo.IAchievementTypeRepository) IAchievementTypeService {
+	return &AchievementTypeService{
+		achievementTypeRepo: achievementTypeRepo,
+	}
+}
+
+// #6 proses: ambil semua tipe prestasi, tipe nonaktif hanya jika diminta
+func (s *AchievementTypeService) GetAchievementTypes(ctx context.Context, includeInactive bool) (*modelmongo.GetAllAchievementTypesResponse, error) {
+	achievementTypes, err := s.achievementTypeRepo.GetAllAchievementTypes(ctx, includeInactive)
+	if err != nil {
+		return nil, errors.New("error mengambil data tipe prestasi: " + err.Error())
+	}
+
+	return &modelmongo.GetAllAchievementTypesResponse{
+		Status: "success",
+		Data:   achievementTypes,
+	}, nil
+}
+
+// #7 proses: ambil tipe prestasi berdasarkan kode
+func (s *AchievementTypeService) GetAchievementTypeByCode(ctx context.Context, code string) (*modelmongo.GetAchievementTypeResponse, error) {
+	achievementType, err := s.getAchievementType(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	return &modelmongo.GetAchievementTypeResponse{
+		Status: "success",
+		Data:   *achievementType,
+	}, nil
+}
+
+// #8 proses: ambil skema form tipe prestasi, gabungan aturan detail bawaan dan custom field
+func (s *AchievementTypeService) GetAchievementTypeSchema(ctx context.Context, code string) (*modelmongo.GetAchievementTypeSchemaResponse, error) {
+	achievementType, err := s.getAchievementType(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	// #8a proses: ambil aturan detail khusus tipe, slice kosong jika tipe tidak punya aturan bawaan
+	detailFields := append([]modelmongo.DetailFieldRule{}, modelmongo.AchievementDetailRules[achievementType.Code]...)
+
+	customFields := achievementType.CustomFields
+	if customFields == nil {
+		customFields = []modelmongo.CustomFieldDefinition{}
+	}
+
+	return &modelmongo.GetAchievementTypeSchemaResponse{
+		Status: "success",
+		Data: modelmongo.AchievementTypeSchema{
+			Code:         achievementType.Code,
+			Name:         achievementType.Name,
+			Description:  achievementType.Description,
+			DetailFields: detailFields,
+			CustomFields: customFields,
+		},
+	}, nil
+}
+
+// #9 proses: buat tipe prestasi baru beserta skema custom field
+func (s *AchievementTypeService) CreateAchievementType(ctx context.Context, userID string, req modelmongo.CreateAchievementTypeRequest) (*modelmongo.GetAchievementTypeResponse, error) {
+	// #9a proses: validasi kode dan nama tipe prestasi
+	req.Code = strings.TrimSpace(req.Code)
+	if !achievementTypeCodePattern.MatchString(req.Code) {
+		return nil, errors.New("kode tipe prestasi tidak valid. Gunakan huruf kecil, angka, dan underscore (2-50 karakter)")
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, errors.New("nama tipe prestasi wajib diisi")
+	}
+
+	// #9b proses: validasi definisi custom field
+	if err := validateCustomFieldDefinitions(req.CustomFields); err != nil {
+		return nil, err
+	}
+
+	// #9c proses: cek kode belum dipakai tipe lain
+	existing, err := s.achievementTypeRepo.GetAchievementTypeByCode(ctx, req.Code)
+	if err != nil {
+		return nil, errors.New("error mengecek kode tipe prestasi: " + err.Error())
+	}
+
+	if existing != nil {
+		return nil, errors.New("kode tipe prestasi sudah digunakan")
+	}
+
+	customFields := req.CustomFields
+	if customFields == nil {
+		customFields = []modelmongo.CustomFieldDefinition{}
+	}
+
+	// #9d proses: simpan tipe prestasi baru dengan status aktif
+	created, err := s.achievementTypeRepo.CreateAchievementType(ctx, &modelmongo.AchievementTypeDefinition{
+		Code:         req.Code,
+		Name:         strings.TrimSpace(req.Name),
+		Description:  req.Description,
+		CustomFields: customFields,
+		IsActive:     true,
+		CreatedBy:    userID,
+	})
+	if err != nil {
+		return nil, errors.New("error menyimpan tipe prestasi: " + err.Error())
+	}
+
+	return &modelmongo.GetAchievementTypeResponse{
+		Status: "success",
+		Data:   *created,
+	}, nil
+}
+
+// #10 proses: update tipe prestasi, termasuk skema custom field dan status aktif
+func (s *AchievementTypeService) UpdateAchievementType(ctx context.Context, code string, req modelmongo.UpdateAchievementTypeRequest) (*modelmongo.GetAchievementTypeResponse, error) {
+	// #10a proses: validasi definisi custom field jika dikirim
+	if req.CustomFields != nil {
+		if err := validateCustomFieldDefinitions(req.CustomFields); err != nil {
+			return nil, err
+		}
+	}
+
+	// #10b proses: update tipe prestasi di MongoDB
+	updated, err := s.achievementTypeRepo.UpdateAchievementType(ctx, code, req)
+	if err != nil {
+		return nil, errors.New("error mengupdate tipe prestasi: " + err.Error())
+	}
+
+	if updated == nil {
+		return nil, errors.New("tipe prestasi tidak ditemukan")
+	}
+
+	return &modelmongo.GetAchievementTypeResponse{
+		Status: "success",
+		Data:   *updated,
+	}, nil
+}
+
+// #11 proses: cek apakah kode tipe prestasi terdaftar dan aktif
+func (s *AchievementTypeService) IsValidAchievementType(ctx context.Context, code string) (bool, error) {
+	achievementType, err := s.achievementTypeRepo.GetAchievementTypeByCode(ctx, code)
+	if err != nil {
+		return false, errors.New("error mengambil data tipe prestasi: " + err.Error())
+	}
+
+	return achievementType != nil && achievementType.IsActive, nil
+}
+
+// #12 proses: validasi tipe prestasi, aturan detail bawaan, dan custom field sesuai skema tipe
+func (s *AchievementTypeService) ValidateAchievement(ctx context.Context, achievementType string, details modelmongo.AchievementDetails) error {
+	// #12a proses: tipe prestasi harus terdaftar dan aktif
+	definition, err := s.achievementTypeRepo.GetAchievementTypeByCode(ctx, achievementType)
+	if err != nil {
+		return errors.New("error mengambil data tipe prestasi: " + err.Error())
+	}
+
+	if definition == nil || !definition.IsActive {
+		return errors.New("achievement type tidak valid. Gunakan kode tipe prestasi yang aktif dari GET /api/v1/achievement-types")
+	}
+
+	// #12b proses: jalankan aturan detail bawaan lalu gabungkan dengan error custom field
+	var fieldErrors []modelmongo.FieldError
+	if err := ValidateAchievementDetails(achievementType, details); err != nil {
+		var validationErr *modelmongo.ValidationError
+		if !errors.As(err, &validationErr) {
+			return err
+		}
+		fieldErrors = append(fieldErrors, validationErr.Errors...)
+	}
+
+	fieldErrors = append(fieldErrors, validateCustomFieldValues(definition.CustomFields, details.CustomFields)...)
+
+	if len(fieldErrors) == 0 {
+		return nil
+	}
+
+	return &modelmongo.ValidationError{
+		Message: "data prestasi tidak valid",
+		Errors:  fieldErrors,
+	}
+}
+
+// #13 proses: ambil tipe prestasi dari repository dan ubah nil jadi error tidak ditemukan
+func (s *AchievementTypeService) getAchievementType(ctx context.Context, code string) (*modelmongo.AchievementTypeDefinition, error) {
+	achievementType, err := s.achievementTypeRepo.GetAchievementTypeByCode(ctx, code)
+	if err != nil {
+		return nil, errors.New("error mengambil data tipe prestasi: " + err.Error())
+	}
+
+	if achievementType == nil {
+		return nil, errors.New("tipe prestasi tidak ditemukan")
+	}
+
+	return achievementType, nil
+}
+
+// #14 proses: validasi definisi custom field, key unik, tipe dikenal, options untuk select, dan batas nilai konsisten
+func validateCustomFieldDefinitions(fields []modelmongo.CustomFieldDefinition) error {
+	validTypes := map[string]bool{
+		modelmongo.CustomFieldTypeText:        true,
+		modelmongo.CustomFieldTypeNumber:      true,
+		modelmongo.CustomFieldTypeDate:        true,
+		modelmongo.CustomFieldTypeBoolean:     true,
+		modelmongo.CustomFieldTypeSelect:      true,
+		modelmongo.CustomFieldTypeMultiSelect: true,
+		modelmongo.CustomFieldTypeURL:         true,
+	}
+
+	seen := map[string]bool{}
+	for i, field := range fields {
+		position := fmt.Sprintf("custom field #%d", i+1)
+
+		if !customFieldKeyPattern.MatchString(field.Key) {
+			return errors.New(position + ": key tidak valid. Gunakan huruf, angka, dan underscore, diawali huruf")
+		}
+
+		if seen[field.Key] {
+			return errors.New(position + ": key " + field.Key + " duplikat")
+		}
+		seen[field.Key] = true
+
+		if strings.TrimSpace(field.Label) == "" {
+			return errors.New(position + ": label wajib diisi")
+		}
+
+		if !validTypes[field.Type] {
+			return errors.New(position + ": tipe field tidak valid. Gunakan: text, number, date, boolean, select, multiselect, atau url")
+		}
+
+		isSelect := field.Type == modelmongo.CustomFieldTypeSelect || field.Type == modelmongo.CustomFieldTypeMultiSelect
+		if isSelect && len(field.Options) == 0 {
+			return errors.New(position + ": options wajib diisi untuk tipe select dan multiselect")
+		}
+
+		if !isSelect && len(field.Options) > 0 {
+			return errors.New(position + ": options hanya untuk tipe select dan multiselect")
+		}
+
+		if field.Min != nil && field.Max != nil && *field.Min > *field.Max {
+			return errors.New(position + ": min tidak boleh lebih besar dari max")
+		}
+
+		if field.MinLength != nil && field.MaxLength != nil && *field.MinLength > *field.MaxLength {
+			return errors.New(position + ": minLength tidak boleh lebih besar dari maxLength")
+		}
+
+		if field.Pattern != "" {
+			if _, err := regexp.Compile(field.Pattern); err != nil {
+				return errors.New(position + ": pattern regex tidak valid: " + err.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// #15 proses: validasi nilai customFields terhadap skema tipe prestasi
+func validateCustomFieldValues(fields []modelmongo.CustomFieldDefinition, values map[string]interface{}) []modelmongo.FieldError {
+	// #15a proses: tipe tanpa skema custom field tetap menerima customFields bebas
+	if len(fields) == 0 {
+		return nil
+	}
+
+	var fieldErrors []modelmongo.FieldError
+	known := map[string]bool{}
+
+	for _, field := range fields {
+		known[field.Key] = true
+		name := "details.customFields." + field.Key
+
+		value, present := values[field.Key]
+		if !present || value == nil || value == "" {
+			if field.Required {
+				fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: name, Message: "wajib diisi"})
+			}
+			continue
+		}
+
+		if message := checkCustomFieldValue(field, value); message != "" {
+			fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: name, Message: message})
+		}
+	}
+
+	// #15b proses: tolak key yang tidak ada di skema, urutkan agar pesan error stabil
+	var unknown []string
+	for key := range values {
+		if !known[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		fieldErrors = append(fieldErrors, modelmongo.FieldError{Field: "details.customFields." + key, Message: "field tidak dikenal untuk tipe prestasi ini"})
+	}
+
+	return fieldErrors
+}
+
+// #16 proses: cek satu nilai custom field sesuai tipe dan batasan di definisi field
+func checkCustomFieldValue(field modelmongo.CustomFieldDefinition, value interface{}) string {
+	switch field.Type {
+	case modelmongo.CustomFieldTypeText, modelmongo.CustomFieldTypeURL:
+		text, ok := value.(string)
+		if !ok {
+			return "harus berupa teks"
+		}
+		if field.Type == modelmongo.CustomFieldTypeURL {
+			parsed, err := url.ParseRequestURI(text)
+			if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+				return "harus berupa URL http atau https yang valid"
+			}
+		}
+		length := len([]rune(text))
+		if field.MinLength != nil && length < *field.MinLength {
+			return "minimal " + strconv.Itoa(*field.MinLength) + " karakter"
+		}
+		if field.MaxLength != nil && length > *field.MaxLength {
+			return "maksimal " + strconv.Itoa(*field.MaxLength) + " karakter"
+		}
+		if field.Pattern != "" {
+			if pattern, err := regexp.Compile(field.Pattern); err == nil && !pattern.MatchString(text) {
+				return "format tidak sesuai pola " + field.Pattern
+			}
+		}
+
+	case modelmongo.CustomFieldTypeNumber:
+		number, ok := toFloat64(value)
+		if !ok {
+			return "harus berupa angka"
+		}
+		if field.Min != nil && number < *field.Min {
+			return "minimal " + strconv.FormatFloat(*field.Min, 'f', -1, 64)
+		}
+		if field.Max != nil && number > *field.Max {
+			return "maksimal " + strconv.FormatFloat(*field.Max, 'f', -1, 64)
+		}
+
+	case modelmongo.CustomFieldTypeDate:
+		if !isDateValue(value) {
+			return "harus berupa tanggal dengan format YYYY-MM-DD atau RFC3339"
+		}
+
+	case modelmongo.CustomFieldTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return "harus berupa true atau false"
+		}
+
+	case modelmongo.CustomFieldTypeSelect:
+		text, ok := value.(string)
+		if !ok || !containsString(field.Options, text) {
+			return "harus salah satu dari: " + strings.Join(field.Options, ", ")
+		}
+
+	case modelmongo.CustomFieldTypeMultiSelect:
+		items, ok := toStringSlice(value)
+		if !ok {
+			return "harus berupa daftar pilihan"
+		}
+		for _, item := range items {
+			if !containsString(field.Options, item) {
+				return "setiap pilihan harus salah satu dari: " + strings.Join(field.Options, ", ")
+			}
+		}
+	}
+
+	return ""
+}
+
+// #17 proses: konversi nilai angka dari JSON (float64) atau BSON (int32, int64) ke float64
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// #18 proses: cek nilai tanggal dari string JSON atau tanggal BSON
+func isDateValue(value interface{}) bool {
+	switch v := value.(type) {
+	case time.Time, primitive.DateTime:
+		return true
+	case string:
+		if _, err := time.Parse("2006-01-02", v); err == nil {
+			return true
+		}
+		_, err := time.Parse(time.RFC3339, v)
+		return err == nil
+	}
+	return false
+}
+
+// #19 proses: konversi array JSON ([]interface{}) atau BSON (primitive.A) ke slice string
+func toStringSlice(value interface{}) ([]string, bool) {
+	var items []interface{}
+	switch v := value.(type) {
+	case []interface{}:
+		items = v
+	case primitive.A:
+		items = v
+	case []string:
+		return v, true
+	default:
+		return nil, false
+	}
+
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		text, ok := item.(string)
+		if !ok {
+			return nil, false
+		}
+		result = append(result, text)
+	}
+	return result, true
+}
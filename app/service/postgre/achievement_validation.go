@@ -0,0 +1,149 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk regex, strings, dan model MongoDB
+import (
+	"regexp"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	"strings"
+)
+
+// #2 proses: regex format ISSN (1234-567X) dan DOI (10.xxxx/suffix)
+var (
+	issnPattern = regexp.MustCompile(`^\d{4}-\d{3}[\dX]$`)
+	doiPattern  = regexp.MustCompile(`^10\.\d{4,9}/\S+$`)
+)
+
+// #3 proses: validasi detail prestasi memakai aturan per tipe, return ValidationError jika ada field yang tidak valid
+func ValidateAchievementDetails(achievementType string, details modelmongo.AchievementDetails) error {
+	// #3a proses: gabungkan aturan umum dengan aturan khusus tipe prestasi
+	rules := append([]modelmongo.DetailFieldRule{}, modelmongo.AchievementDetailRules[modelmongo.AllAchievementTypes]...)
+	rules = append(rules, modelmongo.AchievementDetailRules[achievementType]...)
+
+	var fieldErrors []modelmongo.FieldError
+	seen := map[string]bool{}
+
+	for _, rule := range rules {
+		fieldName := "details." + rule.Field
+		value, present := detailFieldValue(details, rule.Field)
+
+		// #3b proses: cek field wajib
+		if rule.Required && (!present || strings.TrimSpace(value) == "") {
+			addFieldError(&fieldErrors, seen, fieldName, "wajib diisi untuk tipe "+achievementType)
+			continue
+		}
+
+		// #3c proses: cek nilai enum jika field diisi
+		if present && len(rule.Enum) > 0 && !containsString(rule.Enum, value) {
+			addFieldError(&fieldErrors, seen, fieldName, "harus salah satu dari: "+strings.Join(rule.Enum, ", "))
+			continue
+		}
+
+		// #3d proses: cek format khusus
+		if message := checkDetailFormat(rule.Format, details, value, present); message != "" {
+			addFieldError(&fieldErrors, seen, fieldName, message)
+		}
+	}
+
+	if len(fieldErrors) == 0 {
+		return nil
+	}
+
+	return &modelmongo.ValidationError{
+		Message: "data prestasi tidak valid",
+		Errors:  fieldErrors,
+	}
+}
+
+// #4 proses: cek format ISSN, DOI, urutan tanggal periode, dan peringkat
+func checkDetailFormat(format string, details modelmongo.AchievementDetails, value string, present bool) string {
+	switch format {
+	case modelmongo.DetailFormatISSN:
+		if present && !isValidISSN(value) {
+			return "format ISSN tidak valid, gunakan format 1234-567X dengan check digit yang benar"
+		}
+	case modelmongo.DetailFormatDOI:
+		if present && !doiPattern.MatchString(strings.TrimSpace(value)) {
+			return "format DOI tidak valid, gunakan format 10.xxxx/suffix"
+		}
+	case modelmongo.DetailFormatPeriod:
+		if details.Period != nil {
+			if details.Period.Start.IsZero() || details.Period.End.IsZero() {
+				return "start dan end wajib diisi"
+			}
+			if !details.Period.Start.Before(details.Period.End) {
+				return "start harus sebelum end"
+			}
+		}
+	case modelmongo.DetailFormatRank:
+		if details.Rank != nil && *details.Rank < 1 {
+			return "harus lebih besar dari 0"
+		}
+	}
+	return ""
+}
+
+// #5 proses: validasi ISSN dengan format dan check digit modulo 11
+func isValidISSN(issn string) bool {
+	issn = strings.ToUpper(strings.TrimSpace(issn))
+	if !issnPattern.MatchString(issn) {
+		return false
+	}
+
+	digits := strings.Replace(issn, "-", "", 1)
+	sum := 0
+	for i := 0; i < 7; i++ {
+		sum += int(digits[i]-'0') * (8 - i)
+	}
+
+	check := (11 - sum%11) % 11
+	last := digits[7]
+	if check == 10 {
+		return last == 'X'
+	}
+	return last == byte('0'+check)
+}
+
+// #6 proses: ambil nilai field detail prestasi berdasarkan nama field json
+func detailFieldValue(details modelmongo.AchievementDetails, field string) (string, bool) {
+	fields := map[string]*string{
+		"competitionName":     details.CompetitionName,
+		"competitionLevel":    details.CompetitionLevel,
+		"medalType":           details.MedalType,
+		"publicationType":     details.PublicationType,
+		"publicationTitle":    details.PublicationTitle,
+		"publisher":           details.Publisher,
+		"issn":                details.ISSN,
+		"doi":                 details.DOI,
+		"organizationName":    details.OrganizationName,
+		"position":            details.Position,
+		"certificationName":   details.CertificationName,
+		"issuedBy":            details.IssuedBy,
+		"certificationNumber": details.CertificationNumber,
+		"location":            details.Location,
+		"organizer":           details.Organizer,
+	}
+
+	if value, ok := fields[field]; ok && value != nil {
+		return *value, true
+	}
+	return "", false
+}
+
+// #7 proses: tambahkan field error, satu field hanya dilaporkan sekali
+func addFieldError(fieldErrors *[]modelmongo.FieldError, seen map[string]bool, field string, message string) {
+	if seen[field] {
+		return
+	}
+	seen[field] = true
+	*fieldErrors = append(*fieldErrors, modelmongo.FieldError{Field: field, Message: message})
+}
+
+// #8 proses: cek apakah value ada di dalam list
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
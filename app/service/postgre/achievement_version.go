@@ -0,0 +1,25 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk errors, model, dan helper ETag
+import (
+	"errors"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+)
+
+// #2 proses: pesan error jika prestasi sudah diubah request lain sejak ETag diambil client, route memetakan pesan ini ke 412
+const versionConflictMessage = "konflik versi: prestasi sudah diubah sejak terakhir diambil, muat ulang data terbaru lalu coba lagi"
+
+// #3 proses: ETag prestasi dari versi dokumen MongoDB dan versi reference PostgreSQL
+func achievementETag(achievement *modelmongo.Achievement, ref *modelpostgre.AchievementReference) string {
+	return helper.FormatETag(achievement.Version, ref.Version)
+}
+
+// #4 proses: bandingkan versi saat ini dengan versi dari If-Match, berbeda berarti client memegang data lama
+func checkAchievementVersion(achievement *modelmongo.Achievement, ref *modelpostgre.AchievementReference, expected modelpostgre.AchievementVersion) error {
+	if achievement.Version != expected.Achievement || ref.Version != expected.Reference {
+		return errors.New(versionConflictMessage)
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk archive zip, context, csv, errors, fmt, io, model, repository, strconv, strings, time, dan unicode
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	repositorystorage "sistem-pelaporan-prestasi-mahasiswa/app/repository/storage"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// #2 proses: nama file manifest di dalam ZIP dan kolomnya
+const attachmentExportManifestName = "manifest.csv"
+
+var attachmentExportManifestHeader = []string{
+	"path", "achievement_id", "achievement_title", "achievement_type", "status", "achievement_created_at",
+	"student_id", "student_name", "program_study", "academic_year", "advisor_name",
+	"attachment_id", "file_name", "file_type", "size", "content_hash", "uploaded_at", "note",
+}
+
+// #3 proses: tipe file yang isinya sudah terkompresi sehingga disimpan tanpa kompresi di ZIP
+var attachmentExportStoredTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"video/mp4":  true,
+	"application/vnd.openxmlformats-officedocument.wordprocessingml.document": true,
+}
+
+// #4 proses: definisikan interface untuk export attachment prestasi dalam satu file ZIP
+type IAttachmentExportService interface {
+	PrepareAttachmentExport(ctx context.Context, userID string, roleID string, filter modelpostgre.AchievementListFilter) (*modelmongo.AttachmentExport, error)
+	WriteAttachmentExport(ctx context.Context, export *modelmongo.AttachmentExport, w io.Writer) error
+}
+
+// #5 proses: struct service export attachment dengan dependency repository achievement, reference, user, student, dan storage attachment
+type AttachmentExportService struct {
+	achievementRepo    repositorymongo.IAchievementRepository
+	achievementRefRepo repositorypostgre.IAchievementReferenceRepository
+	userRepo           repositorypostgre.IUserRepository
+	studentRepo        repositorypostgre.IStudentRepository
+	fileStorage        repositorystorage.IFileStorage
+}
+
+// #6 proses: constructor untuk membuat instance AttachmentExportService baru
+func NewAttachmentExportService(
+	achievementRepo repositorymongo.IAchievementRepository,
+	achievementRefRepo repositorypostgre.IAchievementReferenceRepository,
+	userRepo repositorypostgre.IUserRepository,
+	studentRepo repositorypostgre.IStudentRepository,
+	fileStorage repositorystorage.IFileStorage,
+) IAttachmentExportService {
+	return &AttachmentExportService{
+		achievementRepo:    achievementRepo,
+		achievementRefRepo: achievementRefRepo,
+		userRepo:           userRepo,
+		studentRepo:        studentRepo,
+		fileStorage:        fileStorage,
+	}
+}
+
+// #7 proses: kumpulkan daftar attachment yang akan di-export sesuai filter dan batas role, dijalankan sebelum ZIP dikirim supaya error akses masih bisa dikirim sebagai status HTTP
+func (s *AttachmentExportService) PrepareAttachmentExport(ctx context.Context, userID string, roleID string, filter modelpostgre.AchievementListFilter) (*modelmongo.AttachmentExport, error) {
+	// #7a proses: batasi filter dengan aturan yang sama dengan daftar prestasi
+	roleName, err := scopeAchievementListFilter(ctx, s.userRepo, s.studentRepo, userID, roleID, &filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// #7b proses: filter mahasiswa, dosen wali, program studi, angkatan, dan status dijalankan di PostgreSQL
+	rows, err := s.achievementRefRepo.GetAchievementListRows(ctx, filter)
+	if err != nil {
+		return nil, errors.New("error mengambil achievement references: " + err.Error())
+	}
+
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.MongoAchievementID)
+	}
+	achievements := []modelmongo.Achievement{}
+	if len(ids) > 0 {
+		achievements, err = s.achievementRepo.GetAchievementsByIDs(ctx, ids)
+		if err != nil {
+			return nil, errors.New("error mengambil achievements dari MongoDB: " + err.Error())
+		}
+	}
+	achievementMap := make(map[string]modelmongo.Achievement, len(achievements))
+	for _, achievement := range achievements {
+		achievementMap[achievement.ID.Hex()] = achievement
+	}
+
+	// #7c proses: rentang tanggal berdasarkan tanggal prestasi dibuat, dateTo inklusif satu hari penuh seperti daftar prestasi
+	var dateTo *time.Time
+	if filter.DateTo != nil {
+		end := dateOnly(*filter.DateTo).AddDate(0, 0, 1)
+		dateTo = &end
+	}
+
+	// #7d proses: susun entry manifest mengikuti urutan reference, file yang tidak boleh atau tidak bisa diambil tetap dicatat dengan keterangan
+	export := &modelmongo.AttachmentExport{
+		FileName: "lampiran-prestasi-" + time.Now().Format("20060102-150405") + ".zip",
+	}
+	usedPaths := map[string]bool{attachmentExportManifestName: true}
+	for _, row := range rows {
+		achievement, ok := achievementMap[row.MongoAchievementID]
+		if !ok {
+			continue
+		}
+		if filter.DateFrom != nil && achievement.CreatedAt.Before(*filter.DateFrom) {
+			continue
+		}
+		if dateTo != nil && !achievement.CreatedAt.Before(*dateTo) {
+			continue
+		}
+
+		for _, attachment := range achievement.Attachments {
+			entry := modelmongo.AttachmentExportEntry{
+				AchievementID:    row.MongoAchievementID,
+				AchievementTitle: achievement.Title,
+				AchievementType:  achievement.AchievementType,
+				Status:           row.Status,
+				CreatedAt:        achievement.CreatedAt,
+				StudentID:        row.StudentID,
+				StudentName:      row.StudentName,
+				ProgramStudy:     row.ProgramStudy,
+				AcademicYear:     row.AcademicYear,
+				AdvisorName:      row.AdvisorName,
+				Attachment:       attachment,
+			}
+			entry.Path = attachmentExportPath(entry, usedPaths)
+
+			// #7e proses: file terinfeksi, file yang belum selesai dipindai untuk dosen wali dan admin, dan file di karantina tidak dimasukkan
+			if err := checkAttachmentScanStatus(&attachment, roleName); err != nil {
+				entry.Note = "tidak disertakan: " + strings.TrimPrefix(err.Error(), "akses ditolak. ")
+			} else if key, ok := s.fileStorage.KeyFromURL(attachment.FileURL); !ok {
+				entry.Note = "tidak disertakan: file tidak ada di storage attachment"
+			} else {
+				entry.Key = key
+			}
+
+			export.Entries = append(export.Entries, entry)
+		}
+	}
+
+	if len(export.Entries) == 0 {
+		return nil, errors.New("attachment tidak ditemukan untuk filter yang dipilih")
+	}
+	return export, nil
+}
+
+// #8 proses: tulis ZIP langsung ke writer, setiap file dibaca dari storage dan disalin satu per satu tanpa ditampung di memori. Manifest ditulis terakhir supaya file yang gagal dibaca ikut tercatat
+func (s *AttachmentExportService) WriteAttachmentExport(ctx context.Context, export *modelmongo.AttachmentExport, w io.Writer) error {
+	archive := zip.NewWriter(w)
+
+	for i := range export.Entries {
+		entry := &export.Entries[i]
+		if entry.Note != "" {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// #8a proses: file yang hilang dari storage dilewati dan dicatat di manifest, export lain tetap berjalan
+		body, _, err := s.fileStorage.Get(ctx, entry.Key)
+		if err != nil {
+			fmt.Printf("Error reading attachment %s for export: %v\n", entry.Key, err)
+			entry.Note = "tidak disertakan: file gagal dibaca dari storage"
+			continue
+		}
+
+		method := zip.Deflate
+		if attachmentExportStoredTypes[entry.Attachment.FileType] {
+			method = zip.Store
+		}
+		file, err := archive.CreateHeader(&zip.FileHeader{Name: entry.Path, Method: method, Modified: entry.Attachment.UploadedAt})
+		if err != nil {
+			body.Close()
+			return errors.New("error menulis file export: " + err.Error())
+		}
+
+		// #8b proses: error di tengah salin berarti ZIP sudah terpotong sehingga export dihentikan
+		_, err = io.Copy(file, body)
+		body.Close()
+		if err != nil {
+			return errors.New("error menulis file export: " + err.Error())
+		}
+	}
+
+	// #8c proses: tulis manifest CSV yang memetakan file ke prestasi dan mahasiswa
+	manifest, err := archive.Create(attachmentExportManifestName)
+	if err != nil {
+		return errors.New("error menulis manifest export: " + err.Error())
+	}
+	writer := csv.NewWriter(manifest)
+	writer.Write(attachmentExportManifestHeader)
+	for _, entry := range export.Entries {
+		path := entry.Path
+		if entry.Note != "" {
+			path = ""
+		}
+		writer.Write(csvSafeRecord([]string{
+			path,
+			entry.AchievementID,
+			entry.AchievementTitle,
+			entry.AchievementType,
+			entry.Status,
+			entry.CreatedAt.Format(time.RFC3339),
+			entry.StudentID,
+			entry.StudentName,
+			entry.ProgramStudy,
+			entry.AcademicYear,
+			entry.AdvisorName,
+			entry.Attachment.ID,
+			entry.Attachment.FileName,
+			entry.Attachment.FileType,
+			strconv.FormatInt(entry.Attachment.Size, 10),
+			entry.Attachment.ContentHash,
+			entry.Attachment.UploadedAt.Format(time.RFC3339),
+			entry.Note,
+		}))
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return errors.New("error menulis manifest export: " + err.Error())
+	}
+
+	return archive.Close()
+}
+
+// #9 proses: path file di ZIP dikelompokkan per program studi, mahasiswa, dan prestasi. Nama yang sama di folder yang sama diberi nomor
+func attachmentExportPath(entry modelmongo.AttachmentExportEntry, usedPaths map[string]bool) string {
+	studentFolder := attachmentExportSegment(entry.StudentName, "mahasiswa")
+	if len(entry.StudentID) >= 8 {
+		studentFolder += "-" + entry.StudentID[:8]
+	}
+	dir := attachmentExportSegment(entry.ProgramStudy, "tanpa-prodi") + "/" + studentFolder + "/" + entry.AchievementID + "/"
+
+	fileName := attachmentExportSegment(entry.Attachment.FileName, "lampiran")
+	path := dir + fileName
+	ext := ""
+	if i := strings.LastIndex(fileName, "."); i > 0 {
+		ext = fileName[i:]
+	}
+	for n := 2; usedPaths[path]; n++ {
+		path = dir + strings.TrimSuffix(fileName, ext) + "-" + strconv.Itoa(n) + ext
+	}
+	usedPaths[path] = true
+	return path
+}
+
+// #10 proses: bersihkan satu segmen path ZIP, karakter selain huruf, angka, titik, dan strip diganti underscore supaya tidak bisa keluar dari folder saat diekstrak
+func attachmentExportSegment(value string, fallback string) string {
+	segment := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '.' {
+			return r
+		}
+		return '_'
+	}, strings.TrimSpace(value))
+	segment = strings.Trim(segment, "._")
+	if segment == "" {
+		return fallback
+	}
+	return segment
+}
+
+// #11 proses: nilai yang diawali karakter formula spreadsheet diberi petik supaya tidak dieksekusi saat manifest dibuka di Excel
+func csvSafeRecord(record []string) []string {
+	for i, value := range record {
+		if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+			record[i] = "'" + value
+		}
+	}
+	return record
+}
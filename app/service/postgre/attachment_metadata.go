@@ -0,0 +1,494 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk archive zip, bytes, encoding, errors, image, io, dan regexp
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"regexp"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+)
+
+// #2 proses: kualitas JPEG saat gambar di-encode ulang untuk menerapkan orientasi EXIF, dan tag orientasi di IFD0 EXIF
+const (
+	attachmentReencodeQuality = 92
+	exifOrientationTag        = 0x0112
+)
+
+// #3 proses: chunk PNG yang berisi metadata teks, EXIF, dan waktu edit, chunk lain seperti warna dan gamma tetap disimpan
+var pngMetadataChunks = map[string]bool{
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"eXIf": true,
+	"tIME": true,
+}
+
+// #4 proses: elemen XML docProps DOCX yang berisi nama penulis, pengedit terakhir, perusahaan, dan manajer
+var docxMetadataPatterns = map[string][]*regexp.Regexp{
+	"docProps/core.xml": {
+		regexp.MustCompile(`(<dc:creator(?:\s[^>]*)?>)[^<]*(</dc:creator>)`),
+		regexp.MustCompile(`(<cp:lastModifiedBy(?:\s[^>]*)?>)[^<]*(</cp:lastModifiedBy>)`),
+	},
+	"docProps/app.xml": {
+		regexp.MustCompile(`(<Company(?:\s[^>]*)?>)[^<]*(</Company>)`),
+		regexp.MustCompile(`(<Manager(?:\s[^>]*)?>)[^<]*(</Manager>)`),
+	},
+}
+
+// #5 proses: key dictionary Info PDF yang berisi nama penulis dan aplikasi pembuat, dan key stream yang dikosongkan bersama isi XMP supaya stream tidak lagi dianggap terkompresi
+var (
+	pdfInfoMetadataKeys    = []string{"/Author", "/Creator", "/Producer"}
+	pdfXMPStreamFilterKeys = []string{"/Filter", "/DecodeParms"}
+)
+
+// #6 proses: cek apakah tipe file punya metadata yang dibersihkan sebelum disimpan
+func stripsAttachmentMetadata(fileType string) bool {
+	switch fileType {
+	case "image/jpeg", "image/png", "application/pdf", "application/vnd.openxmlformats-officedocument.wordprocessingml.document":
+		return true
+	}
+	return false
+}
+
+// #7 proses: hapus metadata EXIF, XMP, GPS, dan penulis dari isi file, return nil jika tidak ada yang diubah supaya file tanpa metadata tetap sama persis
+func stripAttachmentMetadata(fileType string, data []byte) ([]byte, error) {
+	switch fileType {
+	case "image/jpeg":
+		return stripJPEGMetadata(data)
+	case "image/png":
+		return stripPNGMetadata(data)
+	case "application/pdf":
+		return stripPDFMetadata(data), nil
+	case "application/vnd.openxmlformats-officedocument.wordprocessingml.document":
+		return stripDOCXMetadata(data)
+	}
+	return nil, nil
+}
+
+// #8 proses: buang segmen APP1 (EXIF dan XMP yang berisi GPS), APP3 sampai APP13 termasuk IPTC, APP15, komentar, MPF, dan data setelah EOI seperti thumbnail tambahan dari kamera HP. APP0 JFIF, profil warna ICC, dan APP14 Adobe tetap disimpan karena mempengaruhi warna
+func stripJPEGMetadata(data []byte) ([]byte, error) {
+	invalid := &modelmongo.AttachmentValidationError{Message: "struktur file JPEG tidak valid"}
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, invalid
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, 0xD8)
+	orientation := 1
+	changed := false
+	pos := 2
+
+	for {
+		// #8a proses: file berakhir tanpa EOI tetap diterima apa adanya seperti decoder JPEG pada umumnya
+		if pos >= len(data) {
+			break
+		}
+		if data[pos] != 0xFF || pos+1 >= len(data) {
+			return nil, invalid
+		}
+		marker := data[pos+1]
+		if marker == 0xFF {
+			pos++
+			continue
+		}
+
+		// #8b proses: marker tanpa panjang segmen
+		if marker == 0xD9 {
+			out = append(out, 0xFF, 0xD9)
+			changed = changed || pos+2 < len(data)
+			break
+		}
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			out = append(out, 0xFF, marker)
+			pos += 2
+			continue
+		}
+
+		if pos+4 > len(data) {
+			return nil, invalid
+		}
+		end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:]))
+		if end > len(data) || end < pos+4 {
+			return nil, invalid
+		}
+		segment := data[pos:end]
+		payload := segment[4:]
+
+		// #8c proses: orientasi dibaca dari EXIF sebelum segmen dibuang supaya bisa diterapkan ke piksel
+		if marker == 0xE1 && bytes.HasPrefix(payload, []byte("Exif\x00\x00")) {
+			if o := exifOrientation(payload[6:]); o != 0 {
+				orientation = o
+			}
+		}
+
+		drop := marker == 0xE1 || marker == 0xFE || marker == 0xEF ||
+			(marker >= 0xE3 && marker <= 0xED) ||
+			(marker == 0xE2 && bytes.HasPrefix(payload, []byte("MPF\x00")))
+		if drop {
+			changed = true
+			pos = end
+			continue
+		}
+		out = append(out, segment...)
+		pos = end
+
+		// #8d proses: setelah SOS salin data gambar sampai marker berikutnya, byte FF di data gambar selalu diikuti 00 atau marker RST
+		if marker == 0xDA {
+			scan := pos
+			for scan+1 < len(data) && (data[scan] != 0xFF || data[scan+1] == 0x00 || (data[scan+1] >= 0xD0 && data[scan+1] <= 0xD7)) {
+				scan++
+			}
+			if scan+1 >= len(data) {
+				scan = len(data)
+			}
+			out = append(out, data[pos:scan]...)
+			pos = scan
+		}
+	}
+
+	// #8e proses: orientasi selain normal diterapkan ke piksel lalu gambar di-encode ulang, karena tag orientasi ikut terbuang bersama EXIF
+	if orientation > 1 && orientation <= 8 {
+		img, err := decodeAttachmentImage(out)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, orientAttachmentImage(img, orientation), &jpeg.Options{Quality: attachmentReencodeQuality}); err != nil {
+			return nil, errors.New("error encode gambar: " + err.Error())
+		}
+		return buf.Bytes(), nil
+	}
+
+	if !changed {
+		return nil, nil
+	}
+	return out, nil
+}
+
+// #9 proses: buang chunk metadata PNG dan data setelah IEND, orientasi dari chunk eXIf diterapkan ke piksel seperti JPEG
+func stripPNGMetadata(data []byte) ([]byte, error) {
+	invalid := &modelmongo.AttachmentValidationError{Message: "struktur file PNG tidak valid"}
+	const signatureSize = 8
+	if len(data) < signatureSize {
+		return nil, invalid
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:signatureSize]...)
+	orientation := 1
+	changed := false
+	pos := signatureSize
+
+	for pos < len(data) {
+		if pos+12 > len(data) {
+			return nil, invalid
+		}
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		end := pos + 12 + length
+		if end > len(data) || end < pos {
+			return nil, invalid
+		}
+		chunkType := string(data[pos+4 : pos+8])
+
+		if chunkType == "eXIf" {
+			if o := exifOrientation(data[pos+8 : pos+8+length]); o != 0 {
+				orientation = o
+			}
+		}
+
+		if pngMetadataChunks[chunkType] {
+			changed = true
+		} else {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+
+		if chunkType == "IEND" {
+			changed = changed || pos < len(data)
+			break
+		}
+	}
+
+	if orientation > 1 && orientation <= 8 {
+		img, err := decodeAttachmentImage(out)
+		if err != nil {
+			return nil, err
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, orientAttachmentImage(img, orientation)); err != nil {
+			return nil, errors.New("error encode gambar: " + err.Error())
+		}
+		return buf.Bytes(), nil
+	}
+
+	if !changed {
+		return nil, nil
+	}
+	return out, nil
+}
+
+// #10 proses: baca tag orientasi dari IFD0 data TIFF EXIF, return 0 jika tidak ada atau data rusak
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+	count := int(order.Uint16(tiff[ifd:]))
+	for i := 0; i < count; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		// #10a proses: orientasi bertipe SHORT (3) dengan satu nilai yang disimpan langsung di field value
+		if order.Uint16(tiff[entry:]) == exifOrientationTag && order.Uint16(tiff[entry+2:]) == 3 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+	return 0
+}
+
+// #11 proses: decode gambar dengan cek dimensi lebih dulu, batasnya sama dengan preview supaya header raksasa tidak menghabiskan memori
+func decodeAttachmentImage(data []byte) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, &modelmongo.AttachmentValidationError{Message: "gambar tidak dapat dibaca: " + err.Error()}
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > attachmentPreviewMaxPixels {
+		return nil, &modelmongo.AttachmentValidationError{Message: "dimensi gambar terlalu besar"}
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, &modelmongo.AttachmentValidationError{Message: "gambar tidak dapat dibaca: " + err.Error()}
+	}
+	return img, nil
+}
+
+// #12 proses: terapkan orientasi EXIF 2 sampai 8 (cermin dan rotasi) ke piksel gambar
+func orientAttachmentImage(src image.Image, orientation int) *image.NRGBA {
+	bounds := src.Bounds()
+	rgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+
+	w, h := bounds.Dx(), bounds.Dy()
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			// #12a proses: posisi tujuan setiap piksel sesuai nilai orientasi
+			dx, dy := x, y
+			switch orientation {
+			case 2:
+				dx, dy = w-1-x, y
+			case 3:
+				dx, dy = w-1-x, h-1-y
+			case 4:
+				dx, dy = x, h-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = h-1-y, x
+			case 7:
+				dx, dy = h-1-y, w-1-x
+			case 8:
+				dx, dy = y, w-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):dst.PixOffset(dx, dy)+4], rgba.Pix[rgba.PixOffset(x, y):rgba.PixOffset(x, y)+4])
+		}
+	}
+	return dst
+}
+
+// #13 proses: kosongkan nama penulis di docProps DOCX lalu tulis ulang arsip ZIP, entry lain disalin tanpa dikompres ulang
+func stripDOCXMetadata(data []byte) ([]byte, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, &modelmongo.AttachmentValidationError{Message: "struktur file DOCX tidak valid: " + err.Error()}
+	}
+
+	// #13a proses: baca dan bersihkan docProps terlebih dulu, arsip hanya ditulis ulang jika ada nilai yang dikosongkan
+	replaced := map[string][]byte{}
+	for _, file := range archive.File {
+		patterns, ok := docxMetadataPatterns[file.Name]
+		if !ok {
+			continue
+		}
+		content, err := readZipFile(file)
+		if err != nil {
+			return nil, &modelmongo.AttachmentValidationError{Message: "struktur file DOCX tidak valid: " + err.Error()}
+		}
+		cleaned := content
+		for _, pattern := range patterns {
+			cleaned = pattern.ReplaceAll(cleaned, []byte("$1$2"))
+		}
+		if !bytes.Equal(cleaned, content) {
+			replaced[file.Name] = cleaned
+		}
+	}
+	if len(replaced) == 0 {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, file := range archive.File {
+		content, ok := replaced[file.Name]
+		if !ok {
+			if err := writer.Copy(file); err != nil {
+				return nil, errors.New("error menulis ulang file DOCX: " + err.Error())
+			}
+			continue
+		}
+
+		entry, err := writer.CreateHeader(&zip.FileHeader{Name: file.Name, Method: zip.Deflate, Modified: file.Modified})
+		if err != nil {
+			return nil, errors.New("error menulis ulang file DOCX: " + err.Error())
+		}
+		if _, err := entry.Write(content); err != nil {
+			return nil, errors.New("error menulis ulang file DOCX: " + err.Error())
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, errors.New("error menulis ulang file DOCX: " + err.Error())
+	}
+	return buf.Bytes(), nil
+}
+
+// #13b proses: baca isi entry ZIP dengan batas ukuran file DOCX, entry yang mengembang melebihi batas (zip bomb) ditolak tanpa dibaca seluruhnya
+func readZipFile(file *zip.File) ([]byte, error) {
+	limit := attachmentFileTypes[".docx"].maxSize
+	body, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > limit {
+		return nil, errors.New("entry " + file.Name + " melebihi batas ukuran setelah diekstrak")
+	}
+	return content, nil
+}
+
+// #14 proses: kosongkan nilai Author, Creator, dan Producer di dictionary Info yang ditunjuk trailer serta seluruh isi stream XMP /Metadata yang ditunjuk catalog, dengan spasi sepanjang nilai aslinya sehingga posisi object dan tabel xref PDF tidak berubah. Semua versi object dari incremental update ikut dibersihkan, isi halaman dan object lain tidak disentuh. Object di dalam object stream terkompresi tidak terjangkau
+func stripPDFMetadata(data []byte) []byte {
+	var out []byte
+	fill := func(start int, end int, value func(b byte) byte) {
+		for i := start; i < end; i++ {
+			if replaced := value(data[i]); replaced != data[i] {
+				if out == nil {
+					out = bytes.Clone(data)
+				}
+				out[i] = replaced
+			}
+		}
+	}
+	space := func(byte) byte { return ' ' }
+
+	// #14a proses: nilai Info berupa string literal (...) atau hex <...>, isi hex diganti 0 supaya tetap hex yang valid
+	for _, ref := range pdfTrailerReferences(data, "/Info") {
+		for _, offset := range pdfObjectOffsets(data, ref) {
+			dict, next := pdfValue(data[offset:])
+			dictStart := offset + next - len(dict)
+			for _, key := range pdfInfoMetadataKeys {
+				_, start, end := pdfDictEntryRange(dict, key)
+				if start < 0 || end-start < 2 {
+					continue
+				}
+				switch {
+				case dict[start] == '(':
+					fill(dictStart+start+1, dictStart+end-1, space)
+				case dict[start] == '<' && dict[start+1] != '<':
+					fill(dictStart+start+1, dictStart+end-1, func(b byte) byte {
+						if isPDFWhitespace(b) {
+							return b
+						}
+						return '0'
+					})
+				}
+			}
+		}
+	}
+
+	// #14b proses: XMP bisa berisi nama penulis, aplikasi, dan riwayat edit, seluruh isi stream diganti spasi. /Filter dan /DecodeParms ikut dikosongkan supaya stream yang semula terkompresi terbaca sebagai XMP kosong
+	for _, root := range pdfTrailerReferences(data, "/Root") {
+		for _, catalogOffset := range pdfObjectOffsets(data, root) {
+			catalog, _ := pdfValue(data[catalogOffset:])
+			ref := pdfDictValue(catalog, "/Metadata")
+			if !pdfReferencePrefixPattern.Match(ref) {
+				continue
+			}
+			for _, offset := range pdfObjectOffsets(data, ref) {
+				dict, start, end := pdfStreamAt(data, offset)
+				if start < 0 {
+					continue
+				}
+				fill(start, end, space)
+
+				dictStart := offset + bytes.Index(data[offset:], dict)
+				for _, key := range pdfXMPStreamFilterKeys {
+					if keyStart, _, valueEnd := pdfDictEntryRange(dict, key); keyStart >= 0 {
+						fill(dictStart+keyStart, dictStart+valueEnd, space)
+					}
+				}
+			}
+		}
+	}
+
+	return out
+}
+
+// #15 proses: cari kurung tutup string literal PDF dengan memperhitungkan escape dan kurung bersarang, return -1 jika tidak ditemukan
+func pdfLiteralStringEnd(data []byte, start int) int {
+	depth := 0
+	for i := start; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isPDFWhitespace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\r' || b == '\n' || b == '\f' || b == 0
+}
+
+func isPDFRegularChar(b byte) bool {
+	return !isPDFWhitespace(b) && !bytes.ContainsRune([]byte("()<>[]{}/%"), rune(b))
+}
@@ -0,0 +1,199 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk bytes, regexp, dan strconv
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+)
+
+// #2 proses: pola referensi object "N G R" (di mana saja dan di awal value)
+var (
+	pdfReferencePattern       = regexp.MustCompile(`(\d+)\s+(\d+)\s+R`)
+	pdfReferencePrefixPattern = regexp.MustCompile(`^\d+\s+\d+\s+R`)
+)
+
+// #3 proses: cari posisi isi semua definisi object "N G obj" yang ditunjuk referensi, incremental update menambahkan versi baru di akhir file sehingga definisi terakhir yang berlaku. Object yang disimpan di object stream terkompresi tidak ditemukan
+func pdfObjectOffsets(data []byte, ref []byte) []int {
+	match := pdfReferencePattern.FindSubmatch(ref)
+	if match == nil {
+		return nil
+	}
+	header := regexp.MustCompile(`(?:^|[^0-9])` + string(match[1]) + `\s+` + string(match[2]) + `\s+obj\b`)
+	offsets := []int{}
+	for _, found := range header.FindAllIndex(data, -1) {
+		offsets = append(offsets, found[1])
+	}
+	return offsets
+}
+
+func pdfObjectOffset(data []byte, ref []byte) int {
+	offsets := pdfObjectOffsets(data, ref)
+	if len(offsets) == 0 {
+		return -1
+	}
+	return offsets[len(offsets)-1]
+}
+
+// #4 proses: ambil value pertama dari isi object, return nil jika object tidak ditemukan
+func pdfObject(data []byte, ref []byte) []byte {
+	offset := pdfObjectOffset(data, ref)
+	if offset < 0 {
+		return nil
+	}
+	value, _ := pdfValue(data[offset:])
+	return value
+}
+
+// #5 proses: jika value berupa referensi "N G R" ambil value object yang ditunjuk, selain itu value dikembalikan apa adanya
+func pdfResolve(data []byte, value []byte) []byte {
+	if loc := pdfReferencePrefixPattern.FindIndex(value); loc != nil && loc[1] == len(value) {
+		return pdfObject(data, value)
+	}
+	return value
+}
+
+// #6 proses: baca satu value PDF dari awal b (dictionary, array, string, referensi, angka, atau nama), return value dan posisi setelahnya. Dictionary dan array diambil utuh termasuk isi bersarang
+func pdfValue(b []byte) ([]byte, int) {
+	start := 0
+	for start < len(b) && isPDFWhitespace(b[start]) {
+		start++
+	}
+	if start >= len(b) {
+		return nil, len(b)
+	}
+
+	switch {
+	case bytes.HasPrefix(b[start:], []byte("<<")), b[start] == '[':
+		// #6a proses: hitung kedalaman << >> dan [ ], string di dalamnya dilewati supaya kurung di dalam string tidak terhitung
+		depth := 0
+		for i := start; i < len(b); i++ {
+			switch {
+			case b[i] == '(':
+				end := pdfLiteralStringEnd(b, i)
+				if end < 0 {
+					return nil, len(b)
+				}
+				i = end
+			case bytes.HasPrefix(b[i:], []byte("<<")):
+				depth++
+				i++
+			case bytes.HasPrefix(b[i:], []byte(">>")):
+				depth--
+				i++
+			case b[i] == '<':
+				end := bytes.IndexByte(b[i:], '>')
+				if end < 0 {
+					return nil, len(b)
+				}
+				i += end
+			case b[i] == '[':
+				depth++
+			case b[i] == ']':
+				depth--
+			}
+			if depth == 0 {
+				return b[start : i+1], i + 1
+			}
+		}
+		return nil, len(b)
+	case b[start] == '(':
+		end := pdfLiteralStringEnd(b, start)
+		if end < 0 {
+			return nil, len(b)
+		}
+		return b[start : end+1], end + 1
+	case b[start] == '<':
+		end := bytes.IndexByte(b[start:], '>')
+		if end < 0 {
+			return nil, len(b)
+		}
+		return b[start : start+end+1], start + end + 1
+	}
+
+	// #6b proses: referensi "N G R" dibaca sebagai satu value, token lain dibaca sampai whitespace atau delimiter
+	if loc := pdfReferencePrefixPattern.FindIndex(b[start:]); loc != nil {
+		end := start + loc[1]
+		if end >= len(b) || !isPDFRegularChar(b[end]) {
+			return b[start:end], end
+		}
+	}
+	end := start + 1
+	for end < len(b) && isPDFRegularChar(b[end]) {
+		end++
+	}
+	return b[start:end], end
+}
+
+// #7 proses: ambil value dari key di dictionary tingkat teratas, key di dictionary bersarang tidak ikut dicocokkan. Return nil jika key tidak ada
+func pdfDictValue(dict []byte, key string) []byte {
+	_, valueStart, valueEnd := pdfDictEntryRange(dict, key)
+	if valueStart < 0 {
+		return nil
+	}
+	return dict[valueStart:valueEnd]
+}
+
+// #7a proses: posisi awal key, awal value, dan akhir value di dalam dictionary, dipakai untuk mengubah isi PDF di tempat. Return -1 jika key tidak ada
+func pdfDictEntryRange(dict []byte, key string) (int, int, int) {
+	if !bytes.HasPrefix(dict, []byte("<<")) {
+		return -1, -1, -1
+	}
+	pos := 2
+	for pos < len(dict) {
+		name, next := pdfValue(dict[pos:])
+		if name == nil || name[0] != '/' {
+			return -1, -1, -1
+		}
+		keyStart := pos + next - len(name)
+		pos += next
+		value, next := pdfValue(dict[pos:])
+		if value == nil {
+			return -1, -1, -1
+		}
+		pos += next
+		if string(name) == key {
+			return keyStart, pos - len(value), pos
+		}
+	}
+	return -1, -1, -1
+}
+
+// #8 proses: baca dictionary object di offset dan posisi awal serta akhir isi stream-nya di data, posisi -1 jika object bukan stream. Isi stream dimulai setelah keyword stream dan end-of-line
+func pdfStreamAt(data []byte, offset int) ([]byte, int, int) {
+	dict, next := pdfValue(data[offset:])
+	if !bytes.HasPrefix(dict, []byte("<<")) {
+		return dict, -1, -1
+	}
+
+	start := offset + next
+	for start < len(data) && isPDFWhitespace(data[start]) {
+		start++
+	}
+	if !bytes.HasPrefix(data[start:], []byte("stream")) {
+		return dict, -1, -1
+	}
+	start += len("stream")
+	if start < len(data) && data[start] == '\r' {
+		start++
+	}
+	if start < len(data) && data[start] == '\n' {
+		start++
+	}
+
+	length, err := strconv.Atoi(string(pdfResolve(data, pdfDictValue(dict, "/Length"))))
+	if err != nil || length < 0 || start+length > len(data) {
+		return dict, start, len(data)
+	}
+	return dict, start, start + length
+}
+
+// #9 proses: cari semua referensi object dari key di trailer atau dictionary xref stream, yang terakhir berlaku karena incremental update menulis trailer baru di akhir file
+func pdfTrailerReferences(data []byte, key string) [][]byte {
+	pattern := regexp.MustCompile(regexp.QuoteMeta(key) + `\s+(\d+\s+\d+\s+R)`)
+	refs := [][]byte{}
+	for _, found := range pattern.FindAllSubmatch(data, -1) {
+		refs = append(refs, found[1])
+	}
+	return refs
+}
@@ -0,0 +1,899 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk encoding binary, errors, dan math
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+)
+
+// #2 proses: batas interpreter charstring, kedalaman subroutine dan jumlah operator per glyph
+const (
+	pdfMaxCharstringDepth = 10
+	pdfMaxCharstringOps   = 20000
+)
+
+// #3 proses: font CFF (FontFile3 Type1C, CIDFontType0C, atau tabel "CFF " di OpenType), hanya satu font pertama di Name INDEX yang dipakai
+type pdfCFF struct {
+	charStrings [][]byte
+	globalSubrs [][]byte
+	privates    []pdfCFFPrivate
+	fdSelect    func(gid int) int
+	names       map[string]int
+	cidToGID    map[int]int
+	encoding    map[int]int
+	isCID       bool
+	fontMatrix  pdfMatrix
+}
+
+type pdfCFFPrivate struct {
+	subrs        [][]byte
+	defaultWidth float64
+	nominalWidth float64
+}
+
+// #4 proses: baca INDEX CFF (count, offSize, offset array, data), return isi tiap entri dan posisi setelah INDEX
+func readCFFIndex(data []byte, pos int) ([][]byte, int, error) {
+	if pos+2 > len(data) {
+		return nil, pos, errors.New("INDEX CFF terpotong")
+	}
+	count := int(binary.BigEndian.Uint16(data[pos:]))
+	if count == 0 {
+		return nil, pos + 2, nil
+	}
+	if pos+3 > len(data) {
+		return nil, pos, errors.New("INDEX CFF terpotong")
+	}
+	offSize := int(data[pos+2])
+	if offSize < 1 || offSize > 4 {
+		return nil, pos, errors.New("offSize INDEX CFF tidak valid")
+	}
+	offsetsStart := pos + 3
+	dataStart := offsetsStart + (count+1)*offSize - 1
+	if dataStart > len(data) {
+		return nil, pos, errors.New("INDEX CFF terpotong")
+	}
+	offset := func(i int) int {
+		value := 0
+		for _, b := range data[offsetsStart+i*offSize : offsetsStart+(i+1)*offSize] {
+			value = value<<8 | int(b)
+		}
+		return dataStart + value
+	}
+
+	items := make([][]byte, count)
+	for i := range items {
+		start, end := offset(i), offset(i+1)
+		if start > end || end > len(data) {
+			return nil, pos, errors.New("offset INDEX CFF tidak valid")
+		}
+		items[i] = data[start:end]
+	}
+	return items, offset(count), nil
+}
+
+// #5 proses: parse DICT CFF menjadi map operator ke operand, operator escape 12 x disimpan sebagai 1200+x
+func parseCFFDict(data []byte) map[int][]float64 {
+	dict := map[int][]float64{}
+	operands := []float64{}
+	for pos := 0; pos < len(data); {
+		b := int(data[pos])
+		switch {
+		case b <= 21:
+			op := b
+			pos++
+			if b == 12 && pos < len(data) {
+				op = 1200 + int(data[pos])
+				pos++
+			}
+			dict[op] = operands
+			operands = []float64{}
+		case b == 28 && pos+3 <= len(data):
+			operands = append(operands, float64(int16(binary.BigEndian.Uint16(data[pos+1:]))))
+			pos += 3
+		case b == 29 && pos+5 <= len(data):
+			operands = append(operands, float64(int32(binary.BigEndian.Uint32(data[pos+1:]))))
+			pos += 5
+		case b == 30:
+			value, next := parseCFFReal(data, pos+1)
+			operands = append(operands, value)
+			pos = next
+		case b >= 32 && b <= 246:
+			operands = append(operands, float64(b-139))
+			pos++
+		case b >= 247 && b <= 250 && pos+2 <= len(data):
+			operands = append(operands, float64((b-247)*256+int(data[pos+1])+108))
+			pos += 2
+		case b >= 251 && b <= 254 && pos+2 <= len(data):
+			operands = append(operands, float64(-(b-251)*256-int(data[pos+1])-108))
+			pos += 2
+		default:
+			pos++
+		}
+	}
+	return dict
+}
+
+// #5a proses: bilangan real DICT CFF dikodekan per nibble
+func parseCFFReal(data []byte, pos int) (float64, int) {
+	text := []byte{}
+	for pos < len(data) {
+		b := data[pos]
+		pos++
+		for _, nibble := range []byte{b >> 4, b & 0xf} {
+			switch {
+			case nibble <= 9:
+				text = append(text, '0'+nibble)
+			case nibble == 0xa:
+				text = append(text, '.')
+			case nibble == 0xb:
+				text = append(text, 'E')
+			case nibble == 0xc:
+				text = append(text, 'E', '-')
+			case nibble == 0xe:
+				text = append(text, '-')
+			case nibble == 0xf:
+				value, _ := parsePDFNumber(text)
+				return value, pos
+			}
+		}
+	}
+	value, _ := parsePDFNumber(text)
+	return value, pos
+}
+
+// #6 proses: parse font CFF, termasuk charset (nama glyph atau CID), encoding bawaan, Private DICT, dan FDArray untuk font CID-keyed
+func parseCFF(data []byte) (*pdfCFF, error) {
+	if len(data) < 4 {
+		return nil, errors.New("font CFF terlalu pendek")
+	}
+	pos := int(data[2])
+	_, pos, err := readCFFIndex(data, pos)
+	if err != nil {
+		return nil, err
+	}
+	topDicts, pos, err := readCFFIndex(data, pos)
+	if err != nil || len(topDicts) == 0 {
+		return nil, errors.New("Top DICT CFF tidak ditemukan")
+	}
+	stringsIndex, pos, err := readCFFIndex(data, pos)
+	if err != nil {
+		return nil, err
+	}
+	globalSubrs, _, err := readCFFIndex(data, pos)
+	if err != nil {
+		return nil, err
+	}
+
+	top := parseCFFDict(topDicts[0])
+	font := &pdfCFF{globalSubrs: globalSubrs, fontMatrix: pdfMatrix{0.001, 0, 0, 0.001, 0, 0}}
+	if values := top[1207]; len(values) == 6 {
+		if m, ok := matrixFromNumbers(values); ok {
+			font.fontMatrix = m
+		}
+	}
+
+	offsetOf := func(dict map[int][]float64, op int) (int, bool) {
+		values := dict[op]
+		if len(values) == 0 || values[len(values)-1] <= 0 || int(values[len(values)-1]) >= len(data) {
+			return 0, false
+		}
+		return int(values[len(values)-1]), true
+	}
+
+	charStringsOffset, ok := offsetOf(top, 17)
+	if !ok {
+		return nil, errors.New("CharStrings CFF tidak ditemukan")
+	}
+	font.charStrings, _, err = readCFFIndex(data, charStringsOffset)
+	if err != nil || len(font.charStrings) == 0 {
+		return nil, errors.New("CharStrings CFF tidak valid")
+	}
+
+	// #6a proses: Private DICT berisi Subrs lokal (offset relatif ke awal Private) dan lebar default/nominal
+	readPrivate := func(dict map[int][]float64) pdfCFFPrivate {
+		private := pdfCFFPrivate{}
+		values := dict[18]
+		if len(values) != 2 {
+			return private
+		}
+		size, offset := int(values[0]), int(values[1])
+		if offset < 0 || size < 0 || offset+size > len(data) {
+			return private
+		}
+		privateDict := parseCFFDict(data[offset : offset+size])
+		if v := privateDict[20]; len(v) > 0 {
+			private.defaultWidth = v[0]
+		}
+		if v := privateDict[21]; len(v) > 0 {
+			private.nominalWidth = v[0]
+		}
+		if v := privateDict[19]; len(v) > 0 && offset+int(v[0]) < len(data) && v[0] > 0 {
+			private.subrs, _, _ = readCFFIndex(data, offset+int(v[0]))
+		}
+		return private
+	}
+
+	_, font.isCID = top[1230]
+	if font.isCID {
+		if fdArrayOffset, ok := offsetOf(top, 1236); ok {
+			fdDicts, _, _ := readCFFIndex(data, fdArrayOffset)
+			for _, fd := range fdDicts {
+				font.privates = append(font.privates, readPrivate(parseCFFDict(fd)))
+			}
+		}
+		if fdSelectOffset, ok := offsetOf(top, 1237); ok {
+			font.fdSelect = parseCFFFDSelect(data, fdSelectOffset, len(font.charStrings))
+		}
+	} else {
+		font.privates = []pdfCFFPrivate{readPrivate(top)}
+	}
+	if len(font.privates) == 0 {
+		font.privates = []pdfCFFPrivate{{}}
+	}
+
+	// #6b proses: charset memetakan GID ke SID (font biasa) atau CID (font CID-keyed), charset predefined 0 adalah ISOAdobe dengan SID sama dengan GID
+	sids := make([]int, len(font.charStrings))
+	charsetOffset, custom := offsetOf(top, 15)
+	if custom && charsetOffset > 2 {
+		parseCFFCharset(data, charsetOffset, sids)
+	} else {
+		for gid := range sids {
+			sids[gid] = gid
+		}
+	}
+
+	stringName := func(sid int) string {
+		if sid < len(cffStandardStrings) {
+			return cffStandardStrings[sid]
+		}
+		// SID 229-390 adalah nama glyph expert yang tidak dimuat tabelnya
+		if index := sid - 391; index >= 0 && index < len(stringsIndex) {
+			return string(stringsIndex[index])
+		}
+		return ""
+	}
+	if font.isCID {
+		font.cidToGID = map[int]int{}
+		for gid, cid := range sids {
+			font.cidToGID[cid] = gid
+		}
+	} else {
+		font.names = map[string]int{}
+		for gid, sid := range sids {
+			if name := stringName(sid); name != "" {
+				if _, exists := font.names[name]; !exists {
+					font.names[name] = gid
+				}
+			}
+		}
+		font.encoding = parseCFFEncoding(data, top, font.names, sids)
+	}
+	return font, nil
+}
+
+// #6c proses: charset format 0 (daftar SID), 1 dan 2 (range dengan jumlah 8 atau 16 bit)
+func parseCFFCharset(data []byte, pos int, sids []int) {
+	if pos >= len(data) {
+		return
+	}
+	format := data[pos]
+	pos++
+	gid := 1
+	for gid < len(sids) && pos < len(data) {
+		if pos+2 > len(data) {
+			return
+		}
+		first := int(binary.BigEndian.Uint16(data[pos:]))
+		pos += 2
+		switch format {
+		case 0:
+			sids[gid] = first
+			gid++
+		case 1, 2:
+			count := 0
+			if format == 1 {
+				if pos >= len(data) {
+					return
+				}
+				count = int(data[pos])
+				pos++
+			} else {
+				if pos+2 > len(data) {
+					return
+				}
+				count = int(binary.BigEndian.Uint16(data[pos:]))
+				pos += 2
+			}
+			for i := 0; i <= count && gid < len(sids); i++ {
+				sids[gid] = first + i
+				gid++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// #6d proses: encoding bawaan CFF dari kode ke GID, predefined 0 adalah StandardEncoding lewat nama glyph dan format custom 0/1 memakai urutan GID ditambah supplement
+func parseCFFEncoding(data []byte, top map[int][]float64, names map[string]int, sids []int) map[int]int {
+	encoding := map[int]int{}
+	offset := 0
+	if values := top[16]; len(values) > 0 {
+		offset = int(values[0])
+	}
+	if offset <= 1 || offset >= len(data) {
+		for code, name := range pdfStandardEncoding {
+			if gid, ok := names[name]; ok && name != "" {
+				encoding[code] = gid
+			}
+		}
+		return encoding
+	}
+
+	pos := offset
+	format := data[pos]
+	pos++
+	if pos >= len(data) {
+		return encoding
+	}
+	switch format & 0x7f {
+	case 0:
+		count := int(data[pos])
+		pos++
+		for i := 0; i < count && pos < len(data); i++ {
+			encoding[int(data[pos])] = i + 1
+			pos++
+		}
+	case 1:
+		ranges := int(data[pos])
+		pos++
+		gid := 1
+		for i := 0; i < ranges && pos+2 <= len(data); i++ {
+			first, left := int(data[pos]), int(data[pos+1])
+			pos += 2
+			for code := first; code <= first+left; code++ {
+				encoding[code] = gid
+				gid++
+			}
+		}
+	}
+	if format&0x80 != 0 && pos < len(data) {
+		count := int(data[pos])
+		pos++
+		for i := 0; i < count && pos+3 <= len(data); i++ {
+			code := int(data[pos])
+			sid := int(binary.BigEndian.Uint16(data[pos+1:]))
+			pos += 3
+			for gid, glyphSID := range sids {
+				if glyphSID == sid {
+					encoding[code] = gid
+					break
+				}
+			}
+		}
+	}
+	return encoding
+}
+
+// #6e proses: FDSelect format 0 (satu byte per glyph) dan 3 (range), memilih Private DICT untuk glyph font CID-keyed
+func parseCFFFDSelect(data []byte, pos int, glyphs int) func(int) int {
+	if pos >= len(data) {
+		return nil
+	}
+	switch data[pos] {
+	case 0:
+		table := data[pos+1:]
+		return func(gid int) int {
+			if gid < len(table) {
+				return int(table[gid])
+			}
+			return 0
+		}
+	case 3:
+		if pos+3 > len(data) {
+			return nil
+		}
+		count := int(binary.BigEndian.Uint16(data[pos+1:]))
+		ranges := data[pos+3:]
+		return func(gid int) int {
+			for i := 0; i < count && i*3+5 <= len(ranges); i++ {
+				first := int(binary.BigEndian.Uint16(ranges[i*3:]))
+				next := int(binary.BigEndian.Uint16(ranges[i*3+3:]))
+				if gid >= first && gid < next {
+					return int(ranges[i*3+2])
+				}
+			}
+			return 0
+		}
+	}
+	return nil
+}
+
+// #7 proses: outline glyph dalam satuan 1/1000 em, koordinat charstring dikalikan FontMatrix
+func (f *pdfCFF) outline(gid int) *pdfPath {
+	if gid < 0 || gid >= len(f.charStrings) {
+		return nil
+	}
+	private := f.privates[0]
+	if f.fdSelect != nil {
+		if fd := f.fdSelect(gid); fd < len(f.privates) {
+			private = f.privates[fd]
+		}
+	}
+	m := f.fontMatrix.multiply(pdfMatrix{1000, 0, 0, 1000, 0, 0})
+	interpreter := &pdfType2Interpreter{font: f, private: private, path: &pdfPath{}, matrix: m}
+	interpreter.run(f.charStrings[gid], 0)
+	interpreter.path.closePath()
+	return interpreter.path
+}
+
+// #7a proses: advance glyph dari charstring (operand lebar opsional di operator pertama)
+func (f *pdfCFF) advance(gid int) (float64, bool) {
+	if gid < 0 || gid >= len(f.charStrings) {
+		return 0, false
+	}
+	private := f.privates[0]
+	if f.fdSelect != nil {
+		if fd := f.fdSelect(gid); fd < len(f.privates) {
+			private = f.privates[fd]
+		}
+	}
+	interpreter := &pdfType2Interpreter{font: f, private: private, path: &pdfPath{}, matrix: pdfIdentity, widthOnly: true}
+	interpreter.run(f.charStrings[gid], 0)
+	width := private.defaultWidth
+	if interpreter.hasWidth {
+		width = private.nominalWidth + interpreter.width
+	}
+	return width * f.fontMatrix[0] * 1000, true
+}
+
+// #8 proses: interpreter charstring Type 2, hint diabaikan kecuali untuk menghitung panjang hintmask
+type pdfType2Interpreter struct {
+	font      *pdfCFF
+	private   pdfCFFPrivate
+	path      *pdfPath
+	matrix    pdfMatrix
+	stack     []float64
+	x, y      float64
+	stems     int
+	widthSeen bool
+	hasWidth  bool
+	width     float64
+	widthOnly bool
+	ops       int
+	transient [32]float64
+	done      bool
+}
+
+func cffSubrBias(count int) int {
+	switch {
+	case count < 1240:
+		return 107
+	case count < 33900:
+		return 1131
+	}
+	return 32768
+}
+
+func (t *pdfType2Interpreter) moveTo(dx, dy float64) {
+	t.path.closePath()
+	t.x += dx
+	t.y += dy
+	p := t.matrix.apply(pdfPoint{t.x, t.y})
+	t.path.moveTo(p.x, p.y)
+}
+
+func (t *pdfType2Interpreter) lineTo(dx, dy float64) {
+	t.x += dx
+	t.y += dy
+	p := t.matrix.apply(pdfPoint{t.x, t.y})
+	t.path.lineTo(p.x, p.y)
+}
+
+func (t *pdfType2Interpreter) curveTo(dx1, dy1, dx2, dy2, dx3, dy3 float64) {
+	x1, y1 := t.x+dx1, t.y+dy1
+	x2, y2 := x1+dx2, y1+dy2
+	t.x, t.y = x2+dx3, y2+dy3
+	p1 := t.matrix.apply(pdfPoint{x1, y1})
+	p2 := t.matrix.apply(pdfPoint{x2, y2})
+	p3 := t.matrix.apply(pdfPoint{t.x, t.y})
+	t.path.curveTo(p1.x, p1.y, p2.x, p2.y, p3.x, p3.y)
+}
+
+// #8a proses: operand lebar hanya ada di operator pembersih stack pertama, terdeteksi dari jumlah operand yang berlebih
+func (t *pdfType2Interpreter) takeWidth(expectEven bool, expected int) {
+	if t.widthSeen {
+		return
+	}
+	t.widthSeen = true
+	extra := false
+	if expected >= 0 {
+		extra = len(t.stack) > expected
+	} else if expectEven {
+		extra = len(t.stack)%2 == 1
+	}
+	if extra && len(t.stack) > 0 {
+		t.hasWidth = true
+		t.width = t.stack[0]
+		t.stack = t.stack[1:]
+	}
+}
+
+func (t *pdfType2Interpreter) run(code []byte, depth int) {
+	if depth > pdfMaxCharstringDepth {
+		t.done = true
+		return
+	}
+	for pos := 0; pos < len(code) && !t.done; {
+		t.ops++
+		if t.ops > pdfMaxCharstringOps {
+			t.done = true
+			return
+		}
+		b := int(code[pos])
+		pos++
+
+		// #8b proses: operand angka
+		switch {
+		case b == 28:
+			if pos+2 > len(code) {
+				t.done = true
+				return
+			}
+			t.push(float64(int16(binary.BigEndian.Uint16(code[pos:]))))
+			pos += 2
+			continue
+		case b >= 32 && b <= 246:
+			t.push(float64(b - 139))
+			continue
+		case b >= 247 && b <= 250:
+			if pos >= len(code) {
+				t.done = true
+				return
+			}
+			t.push(float64((b-247)*256 + int(code[pos]) + 108))
+			pos++
+			continue
+		case b >= 251 && b <= 254:
+			if pos >= len(code) {
+				t.done = true
+				return
+			}
+			t.push(float64(-(b-251)*256 - int(code[pos]) - 108))
+			pos++
+			continue
+		case b == 255:
+			if pos+4 > len(code) {
+				t.done = true
+				return
+			}
+			t.push(float64(int32(binary.BigEndian.Uint32(code[pos:]))) / 65536)
+			pos += 4
+			continue
+		}
+
+		s := t.stack
+		switch b {
+		case 1, 3, 18, 23: // hstem, vstem, hstemhm, vstemhm
+			t.takeWidth(true, -1)
+			t.stems += len(t.stack) / 2
+		case 19, 20: // hintmask, cntrmask
+			t.takeWidth(true, -1)
+			t.stems += len(t.stack) / 2
+			pos += (t.stems + 7) / 8
+		case 21: // rmoveto
+			t.takeWidth(false, 2)
+			s = t.stack
+			if len(s) >= 2 {
+				t.moveTo(s[0], s[1])
+			}
+		case 22: // hmoveto
+			t.takeWidth(false, 1)
+			s = t.stack
+			if len(s) >= 1 {
+				t.moveTo(s[0], 0)
+			}
+		case 4: // vmoveto
+			t.takeWidth(false, 1)
+			s = t.stack
+			if len(s) >= 1 {
+				t.moveTo(0, s[0])
+			}
+		case 5: // rlineto
+			for i := 0; i+1 < len(s); i += 2 {
+				t.lineTo(s[i], s[i+1])
+			}
+		case 6, 7: // hlineto, vlineto
+			horizontal := b == 6
+			for _, v := range s {
+				if horizontal {
+					t.lineTo(v, 0)
+				} else {
+					t.lineTo(0, v)
+				}
+				horizontal = !horizontal
+			}
+		case 8: // rrcurveto
+			for i := 0; i+5 < len(s); i += 6 {
+				t.curveTo(s[i], s[i+1], s[i+2], s[i+3], s[i+4], s[i+5])
+			}
+		case 24: // rcurveline
+			i := 0
+			for ; i+5 < len(s)-2; i += 6 {
+				t.curveTo(s[i], s[i+1], s[i+2], s[i+3], s[i+4], s[i+5])
+			}
+			if i+1 < len(s) {
+				t.lineTo(s[i], s[i+1])
+			}
+		case 25: // rlinecurve
+			i := 0
+			for ; i+1 < len(s)-6; i += 2 {
+				t.lineTo(s[i], s[i+1])
+			}
+			if i+5 < len(s) {
+				t.curveTo(s[i], s[i+1], s[i+2], s[i+3], s[i+4], s[i+5])
+			}
+		case 26: // vvcurveto
+			i := 0
+			dx1 := 0.0
+			if len(s)%2 == 1 {
+				dx1 = s[0]
+				i = 1
+			}
+			for ; i+3 < len(s); i += 4 {
+				t.curveTo(dx1, s[i], s[i+1], s[i+2], 0, s[i+3])
+				dx1 = 0
+			}
+		case 27: // hhcurveto
+			i := 0
+			dy1 := 0.0
+			if len(s)%2 == 1 {
+				dy1 = s[0]
+				i = 1
+			}
+			for ; i+3 < len(s); i += 4 {
+				t.curveTo(s[i], dy1, s[i+1], s[i+2], s[i+3], 0)
+				dy1 = 0
+			}
+		case 30, 31: // vhcurveto, hvcurveto
+			horizontal := b == 31
+			for i := 0; i+3 < len(s); i += 4 {
+				last := 0.0
+				if len(s)-i == 5 {
+					last = s[i+4]
+				}
+				if horizontal {
+					t.curveTo(s[i], 0, s[i+1], s[i+2], last, s[i+3])
+				} else {
+					t.curveTo(0, s[i], s[i+1], s[i+2], s[i+3], last)
+				}
+				horizontal = !horizontal
+			}
+		case 10, 29: // callsubr, callgsubr
+			if len(s) == 0 {
+				t.done = true
+				return
+			}
+			subrs := t.private.subrs
+			if b == 29 {
+				subrs = t.font.globalSubrs
+			}
+			index := int(s[len(s)-1]) + cffSubrBias(len(subrs))
+			t.stack = s[:len(s)-1]
+			if index < 0 || index >= len(subrs) {
+				t.done = true
+				return
+			}
+			t.run(subrs[index], depth+1)
+			continue
+		case 11: // return
+			return
+		case 14: // endchar
+			t.takeWidth(false, 0)
+			if len(t.stack) >= 4 {
+				// endchar dengan 4 operand adalah seac: glyph dasar dan aksen dari StandardEncoding
+				n := len(t.stack)
+				t.seac(t.stack[n-4], t.stack[n-3], int(t.stack[n-2]), int(t.stack[n-1]), depth)
+			}
+			t.path.closePath()
+			t.done = true
+			return
+		case 12:
+			if pos >= len(code) {
+				t.done = true
+				return
+			}
+			t.escape(int(code[pos]))
+			pos++
+			continue
+		default:
+			// operator tidak dikenal, abaikan operand
+		}
+		if b != 1 && b != 3 && b != 18 && b != 23 && b != 19 && b != 20 {
+			t.widthSeen = true
+		}
+		t.stack = t.stack[:0]
+		if t.widthOnly && t.widthSeen {
+			t.done = true
+			return
+		}
+	}
+}
+
+func (t *pdfType2Interpreter) push(v float64) {
+	if len(t.stack) < 48 {
+		t.stack = append(t.stack, v)
+	}
+}
+
+// #8c proses: operator escape, flex digambar sebagai dua kurva dan operator aritmatika dijalankan di stack
+func (t *pdfType2Interpreter) escape(op int) {
+	s := t.stack
+	pop := func() float64 {
+		if len(t.stack) == 0 {
+			return 0
+		}
+		v := t.stack[len(t.stack)-1]
+		t.stack = t.stack[:len(t.stack)-1]
+		return v
+	}
+	clear := true
+	switch op {
+	case 35: // flex
+		if len(s) >= 12 {
+			t.curveTo(s[0], s[1], s[2], s[3], s[4], s[5])
+			t.curveTo(s[6], s[7], s[8], s[9], s[10], s[11])
+		}
+	case 34: // hflex
+		if len(s) >= 7 {
+			startY := t.y
+			t.curveTo(s[0], 0, s[1], s[2], s[3], 0)
+			t.curveTo(s[4], 0, s[5], startY-t.y, s[6], 0)
+		}
+	case 36: // hflex1
+		if len(s) >= 9 {
+			startY := t.y
+			t.curveTo(s[0], s[1], s[2], s[3], s[4], 0)
+			t.curveTo(s[5], 0, s[6], s[7], s[8], startY-(t.y+s[7]))
+		}
+	case 37: // flex1
+		if len(s) >= 11 {
+			dx := s[0] + s[2] + s[4] + s[6] + s[8]
+			dy := s[1] + s[3] + s[5] + s[7] + s[9]
+			startX, startY := t.x, t.y
+			t.curveTo(s[0], s[1], s[2], s[3], s[4], s[5])
+			x1, y1 := t.x+s[6], t.y+s[7]
+			x2, y2 := x1+s[8], y1+s[9]
+			var lastX, lastY float64
+			if math.Abs(dx) > math.Abs(dy) {
+				lastX, lastY = s[10], startY-y2
+			} else {
+				lastX, lastY = startX-x2, s[10]
+			}
+			t.curveTo(s[6], s[7], s[8], s[9], lastX, lastY)
+		}
+	default:
+		// operator aritmatika tidak membersihkan stack
+		clear = false
+		switch op {
+		case 3: // and
+			b, a := pop(), pop()
+			t.push(boolFloat(a != 0 && b != 0))
+		case 4: // or
+			b, a := pop(), pop()
+			t.push(boolFloat(a != 0 || b != 0))
+		case 5: // not
+			t.push(boolFloat(pop() == 0))
+		case 9: // abs
+			t.push(math.Abs(pop()))
+		case 10: // add
+			b, a := pop(), pop()
+			t.push(a + b)
+		case 11: // sub
+			b, a := pop(), pop()
+			t.push(a - b)
+		case 12: // div
+			b, a := pop(), pop()
+			if b != 0 {
+				t.push(a / b)
+			} else {
+				t.push(0)
+			}
+		case 14: // neg
+			t.push(-pop())
+		case 15: // eq
+			b, a := pop(), pop()
+			t.push(boolFloat(a == b))
+		case 18: // drop
+			pop()
+		case 20: // put
+			index, value := int(pop()), pop()
+			if index >= 0 && index < len(t.transient) {
+				t.transient[index] = value
+			}
+		case 21: // get
+			index := int(pop())
+			if index >= 0 && index < len(t.transient) {
+				t.push(t.transient[index])
+			} else {
+				t.push(0)
+			}
+		case 22: // ifelse
+			v2, v1, s2, s1 := pop(), pop(), pop(), pop()
+			if v1 <= v2 {
+				t.push(s1)
+			} else {
+				t.push(s2)
+			}
+		case 23: // random
+			t.push(0.5)
+		case 24: // mul
+			b, a := pop(), pop()
+			t.push(a * b)
+		case 26: // sqrt
+			t.push(math.Sqrt(math.Max(0, pop())))
+		case 27: // dup
+			if len(t.stack) > 0 {
+				t.push(t.stack[len(t.stack)-1])
+			}
+		case 28: // exch
+			b, a := pop(), pop()
+			t.push(b)
+			t.push(a)
+		case 29: // index
+			i := int(pop())
+			if i < 0 {
+				i = 0
+			}
+			if i < len(t.stack) {
+				t.push(t.stack[len(t.stack)-1-i])
+			}
+		case 30: // roll
+			j, n := int(pop()), int(pop())
+			if n > 0 && n <= len(t.stack) {
+				part := append([]float64(nil), t.stack[len(t.stack)-n:]...)
+				shift := ((j % n) + n) % n
+				for k := range part {
+					t.stack[len(t.stack)-n+(k+shift)%n] = part[k]
+				}
+			}
+		default:
+			clear = true
+		}
+	}
+	if clear {
+		t.widthSeen = true
+		t.stack = t.stack[:0]
+	}
+}
+
+func boolFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// #8d proses: seac menggambar glyph dasar lalu glyph aksen yang digeser (adx, ady), keduanya dicari lewat nama StandardEncoding
+func (t *pdfType2Interpreter) seac(adx, ady float64, baseCode, accentCode int, depth int) {
+	if t.font.names == nil || baseCode < 0 || baseCode > 255 || accentCode < 0 || accentCode > 255 {
+		return
+	}
+	draw := func(code int, dx, dy float64) {
+		gid, ok := t.font.names[pdfStandardEncoding[code]]
+		if !ok || gid >= len(t.font.charStrings) {
+			return
+		}
+		sub := &pdfType2Interpreter{
+			font: t.font, private: t.private, path: t.path,
+			matrix:    pdfMatrix{1, 0, 0, 1, dx, dy}.multiply(t.matrix),
+			widthSeen: true,
+			ops:       t.ops,
+		}
+		sub.run(t.font.charStrings[gid], depth+1)
+		t.ops = sub.ops
+	}
+	draw(baseCode, 0, 0)
+	draw(accentCode, adx, ady)
+}
@@ -0,0 +1,706 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk math
+import "math"
+
+// #2 proses: batas evaluasi fungsi PDF, kalkulator PostScript tipe 4 dihentikan setelah sejumlah operasi supaya loop tidak berakhir tidak mungkin terjadi dan stack tidak membengkak
+const (
+	pdfMaxFunctionSteps = 10000
+	pdfMaxFunctionStack = 100
+)
+
+// #3 proses: color space hasil resolve, semua warna akhirnya dikonversi ke RGB 0-1 untuk kanvas preview
+type pdfColorSpace struct {
+	kind       string
+	components int
+	base       *pdfColorSpace
+	lookup     []byte
+	hival      int
+	tint       pdfFunction
+	whitePoint [3]float64
+	lab        [4]float64
+}
+
+var (
+	pdfGraySpace = &pdfColorSpace{kind: "gray", components: 1}
+	pdfRGBSpace  = &pdfColorSpace{kind: "rgb", components: 3}
+	pdfCMYKSpace = &pdfColorSpace{kind: "cmyk", components: 4}
+)
+
+// #3a proses: warna awal sesuai spesifikasi setelah operator cs/CS, hitam untuk device space dan tint penuh untuk Separation/DeviceN
+func (cs *pdfColorSpace) initialColor() []float64 {
+	values := make([]float64, cs.components)
+	switch cs.kind {
+	case "cmyk":
+		values[3] = 1
+	case "tint":
+		for i := range values {
+			values[i] = 1
+		}
+	}
+	return values
+}
+
+// #3b proses: konversi nilai komponen ke RGB, komponen yang kurang dianggap 0
+func (cs *pdfColorSpace) toRGB(values []float64) pdfRGB {
+	get := func(i int) float64 {
+		if i < len(values) {
+			return values[i]
+		}
+		return 0
+	}
+
+	switch cs.kind {
+	case "gray":
+		g := clampUnit(get(0))
+		return pdfRGB{g, g, g}
+	case "rgb":
+		return pdfRGB{clampUnit(get(0)), clampUnit(get(1)), clampUnit(get(2))}
+	case "cmyk":
+		return cmykToRGB(get(0), get(1), get(2), get(3))
+	case "indexed":
+		index := int(math.Round(get(0)))
+		index = max(0, min(cs.hival, index))
+		n := cs.base.components
+		start := index * n
+		if start+n > len(cs.lookup) {
+			return pdfRGB{}
+		}
+		baseValues := make([]float64, n)
+		for i := range baseValues {
+			baseValues[i] = float64(cs.lookup[start+i]) / 255
+		}
+		if cs.base.kind == "lab" {
+			for i := 1; i < n && i < 3; i++ {
+				low, high := cs.base.lab[(i-1)*2], cs.base.lab[(i-1)*2+1]
+				baseValues[i] = low + baseValues[i]*(high-low)
+			}
+			baseValues[0] *= 100
+		}
+		return cs.base.toRGB(baseValues)
+	case "tint":
+		if cs.tint == nil {
+			g := 1 - clampUnit(get(0))
+			return pdfRGB{g, g, g}
+		}
+		return cs.base.toRGB(cs.tint(values))
+	case "lab":
+		return labToRGB(get(0), get(1), get(2), cs.whitePoint)
+	}
+	return pdfRGB{}
+}
+
+func cmykToRGB(c, m, y, k float64) pdfRGB {
+	c, m, y, k = clampUnit(c), clampUnit(m), clampUnit(y), clampUnit(k)
+	return pdfRGB{(1 - c) * (1 - k), (1 - m) * (1 - k), (1 - y) * (1 - k)}
+}
+
+// #3c proses: konversi CIE Lab ke sRGB lewat XYZ dengan white point color space
+func labToRGB(l, a, b float64, white [3]float64) pdfRGB {
+	if white[1] == 0 {
+		white = [3]float64{0.9505, 1, 1.089}
+	}
+	fy := (l + 16) / 116
+	fx := fy + a/500
+	fz := fy - b/200
+	inverse := func(t float64) float64 {
+		if t > 6.0/29.0 {
+			return t * t * t
+		}
+		return 3 * (6.0 / 29.0) * (6.0 / 29.0) * (t - 4.0/29.0)
+	}
+	x, y, z := white[0]*inverse(fx), white[1]*inverse(fy), white[2]*inverse(fz)
+	gamma := func(v float64) float64 {
+		if v <= 0.0031308 {
+			return clampUnit(12.92 * v)
+		}
+		return clampUnit(1.055*math.Pow(v, 1/2.4) - 0.055)
+	}
+	return pdfRGB{
+		gamma(3.2406*x - 1.5372*y - 0.4986*z),
+		gamma(-0.9689*x + 1.8758*y + 0.0415*z),
+		gamma(0.0557*x - 0.2040*y + 1.0570*z),
+	}
+}
+
+// #4 proses: resolve color space dari nama atau array, nama non-device dicari di /Resources /ColorSpace. Color space yang tidak dikenal diperlakukan sesuai jumlah komponennya
+func (d *pdfDocument) colorSpace(value interface{}, resources pdfDict, depth int) *pdfColorSpace {
+	if depth > 8 {
+		return pdfGraySpace
+	}
+	value = d.resolve(value)
+	if name, ok := value.(pdfName); ok {
+		switch name {
+		case "DeviceGray", "G", "CalGray":
+			return pdfGraySpace
+		case "DeviceRGB", "RGB", "CalRGB":
+			return pdfRGBSpace
+		case "DeviceCMYK", "CMYK":
+			return pdfCMYKSpace
+		case "Pattern":
+			return &pdfColorSpace{kind: "pattern"}
+		}
+		if named, ok := d.dict(resources["ColorSpace"])[name]; ok {
+			return d.colorSpace(named, resources, depth+1)
+		}
+		return pdfGraySpace
+	}
+
+	array, ok := value.(pdfArray)
+	if !ok || len(array) == 0 {
+		return pdfGraySpace
+	}
+	family := d.name(array[0])
+	arg := func(i int) interface{} {
+		if i < len(array) {
+			return array[i]
+		}
+		return nil
+	}
+
+	switch family {
+	case "CalGray":
+		return pdfGraySpace
+	case "CalRGB":
+		return pdfRGBSpace
+	case "Lab":
+		cs := &pdfColorSpace{kind: "lab", components: 3, lab: [4]float64{-100, 100, -100, 100}}
+		params := d.dict(arg(1))
+		copy(cs.whitePoint[:], d.numbers(params["WhitePoint"]))
+		if r := d.numbers(params["Range"]); len(r) == 4 {
+			copy(cs.lab[:], r)
+		}
+		return cs
+	case "ICCBased":
+		// #4a proses: profil ICC tidak diinterpretasi, cukup /Alternate atau jumlah komponen /N
+		params := d.dict(arg(1))
+		if alternate, ok := params["Alternate"]; ok {
+			return d.colorSpace(alternate, resources, depth+1)
+		}
+		switch int(d.number(params["N"], 3)) {
+		case 1:
+			return pdfGraySpace
+		case 4:
+			return pdfCMYKSpace
+		}
+		return pdfRGBSpace
+	case "Indexed", "I":
+		cs := &pdfColorSpace{kind: "indexed", components: 1}
+		cs.base = d.colorSpace(arg(1), resources, depth+1)
+		cs.hival = int(d.number(arg(2), 0))
+		switch lookup := d.resolve(arg(3)).(type) {
+		case pdfString:
+			cs.lookup = lookup
+		case *pdfStream:
+			cs.lookup, _, _ = d.decodeStream(lookup)
+		}
+		return cs
+	case "Separation", "DeviceN":
+		cs := &pdfColorSpace{kind: "tint", components: 1}
+		alternate := arg(2)
+		fn := arg(3)
+		if family == "DeviceN" {
+			cs.components = max(1, len(d.array(arg(1))))
+		} else if d.name(arg(1)) == "None" {
+			return &pdfColorSpace{kind: "none", components: 1}
+		}
+		cs.base = d.colorSpace(alternate, resources, depth+1)
+		cs.tint = d.function(fn, 0)
+		return cs
+	case "Pattern":
+		return &pdfColorSpace{kind: "pattern", base: d.colorSpace(arg(1), resources, depth+1)}
+	}
+	return d.colorSpace(family, resources, depth+1)
+}
+
+// #5 proses: fungsi PDF (tipe 0 sampled, 2 eksponensial, 3 stitching, 4 kalkulator PostScript) sebagai closure, array fungsi digabung menjadi satu fungsi dengan output berurutan
+type pdfFunction func(in []float64) []float64
+
+func (d *pdfDocument) function(value interface{}, depth int) pdfFunction {
+	if depth > 8 {
+		return nil
+	}
+	value = d.resolve(value)
+	if array, ok := value.(pdfArray); ok {
+		parts := []pdfFunction{}
+		for _, item := range array {
+			if fn := d.function(item, depth+1); fn != nil {
+				parts = append(parts, fn)
+			}
+		}
+		if len(parts) == 0 {
+			return nil
+		}
+		return func(in []float64) []float64 {
+			out := []float64{}
+			for _, part := range parts {
+				out = append(out, part(in)...)
+			}
+			return out
+		}
+	}
+
+	dict := d.dict(value)
+	if dict == nil {
+		return nil
+	}
+	domain := d.numbers(dict["Domain"])
+	rng := d.numbers(dict["Range"])
+	clip := func(values []float64, bounds []float64) []float64 {
+		for i := range values {
+			if 2*i+1 < len(bounds) {
+				values[i] = math.Max(bounds[2*i], math.Min(bounds[2*i+1], values[i]))
+			}
+		}
+		return values
+	}
+
+	var fn pdfFunction
+	switch int(d.number(dict["FunctionType"], -1)) {
+	case 0:
+		fn = d.sampledFunction(value, dict, domain, rng)
+	case 2:
+		fn = d.exponentialFunction(dict)
+	case 3:
+		fn = d.stitchingFunction(dict, domain, depth)
+	case 4:
+		fn = d.postScriptFunction(value, rng)
+	}
+	if fn == nil {
+		return nil
+	}
+	return func(in []float64) []float64 {
+		input := clip(append([]float64(nil), in...), domain)
+		return clip(fn(input), rng)
+	}
+}
+
+// #5a proses: fungsi sampled, input 1 dimensi diinterpolasi linear dan input lebih dari 1 dimensi memakai sampel terdekat
+func (d *pdfDocument) sampledFunction(value interface{}, dict pdfDict, domain, rng []float64) pdfFunction {
+	stream, ok := d.resolve(value).(*pdfStream)
+	if !ok {
+		return nil
+	}
+	data, _, err := d.decodeStream(stream)
+	if err != nil {
+		return nil
+	}
+	size := d.numbers(dict["Size"])
+	bps := int(d.number(dict["BitsPerSample"], 8))
+	inputs, outputs := len(domain)/2, len(rng)/2
+	if inputs == 0 || outputs == 0 || len(size) < inputs || bps <= 0 || bps > 32 {
+		return nil
+	}
+	encode := d.numbers(dict["Encode"])
+	if len(encode) < 2*inputs {
+		encode = make([]float64, 0, 2*inputs)
+		for i := 0; i < inputs; i++ {
+			encode = append(encode, 0, size[i]-1)
+		}
+	}
+	decode := d.numbers(dict["Decode"])
+	if len(decode) < 2*outputs {
+		decode = rng
+	}
+	maxSample := math.Pow(2, float64(bps)) - 1
+
+	sample := func(index, output int) float64 {
+		bit := (index*outputs + output) * bps
+		value := 0
+		for i := 0; i < bps; i++ {
+			byteIndex := (bit + i) / 8
+			if byteIndex >= len(data) {
+				return 0
+			}
+			value = value<<1 | int(data[byteIndex]>>(7-uint((bit+i)%8))&1)
+		}
+		return decode[2*output] + float64(value)/maxSample*(decode[2*output+1]-decode[2*output])
+	}
+
+	return func(in []float64) []float64 {
+		positions := make([]float64, inputs)
+		for i := 0; i < inputs; i++ {
+			x := 0.0
+			if i < len(in) {
+				x = in[i]
+			}
+			span := domain[2*i+1] - domain[2*i]
+			t := 0.0
+			if span != 0 {
+				t = (x - domain[2*i]) / span
+			}
+			e := encode[2*i] + t*(encode[2*i+1]-encode[2*i])
+			positions[i] = math.Max(0, math.Min(size[i]-1, e))
+		}
+
+		out := make([]float64, outputs)
+		if inputs == 1 {
+			lo := int(math.Floor(positions[0]))
+			hi := min(lo+1, int(size[0])-1)
+			frac := positions[0] - float64(lo)
+			for o := range out {
+				out[o] = sample(lo, o)*(1-frac) + sample(hi, o)*frac
+			}
+			return out
+		}
+		index, stride := 0, 1
+		for i := 0; i < inputs; i++ {
+			index += int(math.Round(positions[i])) * stride
+			stride *= int(size[i])
+		}
+		for o := range out {
+			out[o] = sample(index, o)
+		}
+		return out
+	}
+}
+
+// #5b proses: fungsi eksponensial C0 + x^N * (C1 - C0)
+func (d *pdfDocument) exponentialFunction(dict pdfDict) pdfFunction {
+	c0 := d.numbers(dict["C0"])
+	c1 := d.numbers(dict["C1"])
+	if len(c0) == 0 {
+		c0 = []float64{0}
+	}
+	if len(c1) == 0 {
+		c1 = []float64{1}
+	}
+	n := d.number(dict["N"], 1)
+	return func(in []float64) []float64 {
+		x := 0.0
+		if len(in) > 0 {
+			x = in[0]
+		}
+		xn := math.Pow(x, n)
+		out := make([]float64, min(len(c0), len(c1)))
+		for i := range out {
+			out[i] = c0[i] + xn*(c1[i]-c0[i])
+		}
+		return out
+	}
+}
+
+// #5c proses: fungsi stitching, input dipetakan ke subfungsi sesuai /Bounds lalu di-encode ke domain subfungsi
+func (d *pdfDocument) stitchingFunction(dict pdfDict, domain []float64, depth int) pdfFunction {
+	parts := []pdfFunction{}
+	for _, item := range d.array(dict["Functions"]) {
+		parts = append(parts, d.function(item, depth+1))
+	}
+	bounds := d.numbers(dict["Bounds"])
+	encode := d.numbers(dict["Encode"])
+	if len(parts) == 0 || len(domain) < 2 || len(bounds) != len(parts)-1 || len(encode) < 2*len(parts) {
+		return nil
+	}
+	return func(in []float64) []float64 {
+		x := 0.0
+		if len(in) > 0 {
+			x = in[0]
+		}
+		k := 0
+		for k < len(bounds) && x >= bounds[k] {
+			k++
+		}
+		low, high := domain[0], domain[1]
+		if k > 0 {
+			low = bounds[k-1]
+		}
+		if k < len(bounds) {
+			high = bounds[k]
+		}
+		t := 0.0
+		if high != low {
+			t = (x - low) / (high - low)
+		}
+		e := encode[2*k] + t*(encode[2*k+1]-encode[2*k])
+		if parts[k] == nil {
+			return nil
+		}
+		return parts[k]([]float64{e})
+	}
+}
+
+// #5d proses: kalkulator PostScript tipe 4, program di-parse sekali menjadi token dengan blok { } bersarang untuk if dan ifelse
+type pdfPSToken struct {
+	op     string
+	number float64
+	isNum  bool
+	block  []pdfPSToken
+}
+
+func (d *pdfDocument) postScriptFunction(value interface{}, rng []float64) pdfFunction {
+	stream, ok := d.resolve(value).(*pdfStream)
+	if !ok {
+		return nil
+	}
+	data, _, err := d.decodeStream(stream)
+	if err != nil {
+		return nil
+	}
+	lexer := &pdfLexer{data: data}
+	var parse func(depth int) []pdfPSToken
+	parse = func(depth int) []pdfPSToken {
+		tokens := []pdfPSToken{}
+		for depth < pdfMaxNesting {
+			tok, ok := lexer.token()
+			if !ok || tok == pdfKeyword("}") {
+				return tokens
+			}
+			switch value := tok.(type) {
+			case float64:
+				tokens = append(tokens, pdfPSToken{number: value, isNum: true})
+			case pdfKeyword:
+				if value == "{" {
+					tokens = append(tokens, pdfPSToken{block: parse(depth + 1)})
+				} else {
+					tokens = append(tokens, pdfPSToken{op: string(value)})
+				}
+			}
+		}
+		return tokens
+	}
+	program := parse(0)
+	if len(program) == 1 && program[0].block != nil {
+		program = program[0].block
+	}
+	outputs := len(rng) / 2
+
+	return func(in []float64) []float64 {
+		stack := append(make([]float64, 0, pdfMaxFunctionStack), in...)
+		steps := 0
+		runPostScript(program, &stack, &steps)
+		if len(stack) < outputs {
+			return make([]float64, outputs)
+		}
+		return append([]float64(nil), stack[len(stack)-outputs:]...)
+	}
+}
+
+// #5e proses: jalankan token kalkulator PostScript, boolean disimpan sebagai 1 dan 0. Operasi dengan operand kurang dihentikan tanpa panic
+func runPostScript(program []pdfPSToken, stack *[]float64, steps *int) bool {
+	pop := func() (float64, bool) {
+		if len(*stack) == 0 {
+			return 0, false
+		}
+		v := (*stack)[len(*stack)-1]
+		*stack = (*stack)[:len(*stack)-1]
+		return v, true
+	}
+	push := func(v float64) bool {
+		if len(*stack) >= pdfMaxFunctionStack {
+			return false
+		}
+		*stack = append(*stack, v)
+		return true
+	}
+	boolean := func(b bool) float64 {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	for i := 0; i < len(program); i++ {
+		*steps++
+		if *steps > pdfMaxFunctionSteps {
+			return false
+		}
+		tok := program[i]
+		if tok.isNum {
+			if !push(tok.number) {
+				return false
+			}
+			continue
+		}
+		if tok.block != nil {
+			// blok hanya dipakai oleh if/ifelse berikutnya
+			if i+1 < len(program) && program[i+1].op == "if" {
+				cond, ok := pop()
+				if !ok {
+					return false
+				}
+				if cond != 0 && !runPostScript(tok.block, stack, steps) {
+					return false
+				}
+				i++
+			} else if i+2 < len(program) && program[i+1].block != nil && program[i+2].op == "ifelse" {
+				cond, ok := pop()
+				if !ok {
+					return false
+				}
+				block := program[i+1].block
+				if cond != 0 {
+					block = tok.block
+				}
+				if !runPostScript(block, stack, steps) {
+					return false
+				}
+				i += 2
+			}
+			continue
+		}
+
+		switch tok.op {
+		case "true", "false":
+			push(boolean(tok.op == "true"))
+			continue
+		case "dup":
+			if len(*stack) == 0 || !push((*stack)[len(*stack)-1]) {
+				return false
+			}
+			continue
+		case "pop":
+			if _, ok := pop(); !ok {
+				return false
+			}
+			continue
+		case "exch":
+			n := len(*stack)
+			if n < 2 {
+				return false
+			}
+			(*stack)[n-1], (*stack)[n-2] = (*stack)[n-2], (*stack)[n-1]
+			continue
+		case "copy":
+			count, ok := pop()
+			n := len(*stack)
+			if !ok || count < 0 || int(count) > n || n+int(count) > pdfMaxFunctionStack {
+				return false
+			}
+			*stack = append(*stack, (*stack)[n-int(count):]...)
+			continue
+		case "index":
+			index, ok := pop()
+			n := len(*stack)
+			if !ok || index < 0 || int(index) >= n {
+				return false
+			}
+			push((*stack)[n-1-int(index)])
+			continue
+		case "roll":
+			j, ok1 := pop()
+			count, ok2 := pop()
+			n := len(*stack)
+			if !ok1 || !ok2 || count < 0 || int(count) > n {
+				return false
+			}
+			if c := int(count); c > 0 {
+				part := append([]float64(nil), (*stack)[n-c:]...)
+				shift := ((int(j) % c) + c) % c
+				for k := range part {
+					(*stack)[n-c+(k+shift)%c] = part[k]
+				}
+			}
+			continue
+		}
+
+		// operator unary
+		switch tok.op {
+		case "abs", "neg", "ceiling", "floor", "round", "truncate", "sqrt", "sin", "cos", "ln", "log", "cvi", "cvr", "not":
+			a, ok := pop()
+			if !ok {
+				return false
+			}
+			var r float64
+			switch tok.op {
+			case "abs":
+				r = math.Abs(a)
+			case "neg":
+				r = -a
+			case "ceiling":
+				r = math.Ceil(a)
+			case "floor":
+				r = math.Floor(a)
+			case "round":
+				r = math.Floor(a + 0.5)
+			case "truncate", "cvi":
+				r = math.Trunc(a)
+			case "sqrt":
+				r = math.Sqrt(math.Max(0, a))
+			case "sin":
+				r = math.Sin(a * math.Pi / 180)
+			case "cos":
+				r = math.Cos(a * math.Pi / 180)
+			case "ln":
+				r = math.Log(a)
+			case "log":
+				r = math.Log10(a)
+			case "cvr":
+				r = a
+			case "not":
+				if a == 0 || a == 1 {
+					r = boolean(a == 0)
+				} else {
+					r = float64(^int64(a))
+				}
+			}
+			push(r)
+			continue
+		}
+
+		// operator biner
+		b, ok1 := pop()
+		a, ok2 := pop()
+		if !ok1 || !ok2 {
+			return false
+		}
+		var r float64
+		switch tok.op {
+		case "add":
+			r = a + b
+		case "sub":
+			r = a - b
+		case "mul":
+			r = a * b
+		case "div":
+			if b == 0 {
+				return false
+			}
+			r = a / b
+		case "idiv":
+			if int64(b) == 0 {
+				return false
+			}
+			r = float64(int64(a) / int64(b))
+		case "mod":
+			if int64(b) == 0 {
+				return false
+			}
+			r = float64(int64(a) % int64(b))
+		case "exp":
+			r = math.Pow(a, b)
+		case "atan":
+			r = math.Atan2(a, b) * 180 / math.Pi
+			if r < 0 {
+				r += 360
+			}
+		case "and":
+			r = float64(int64(a) & int64(b))
+		case "or":
+			r = float64(int64(a) | int64(b))
+		case "xor":
+			r = float64(int64(a) ^ int64(b))
+		case "bitshift":
+			if b >= 0 {
+				r = float64(int64(a) << uint(min(63, int64(b))))
+			} else {
+				r = float64(int64(a) >> uint(min(63, -int64(b))))
+			}
+		case "eq":
+			r = boolean(a == b)
+		case "ne":
+			r = boolean(a != b)
+		case "gt":
+			r = boolean(a > b)
+		case "ge":
+			r = boolean(a >= b)
+		case "lt":
+			r = boolean(a < b)
+		case "le":
+			r = boolean(a <= b)
+		default:
+			return false
+		}
+		push(r)
+	}
+	return true
+}
@@ -0,0 +1,1068 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk bytes, zlib, flate, encoding, errors, io, regexp, dan strconv
+import (
+	"bytes"
+	"compress/flate"
+	"compress/zlib"
+	"encoding/ascii85"
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// #2 proses: tipe object PDF hasil parse untuk render preview, angka selalu float64 dan string literal maupun hex sudah didecode ke byte
+type (
+	pdfName    string
+	pdfString  []byte
+	pdfKeyword string
+	pdfArray   []interface{}
+	pdfDict    map[pdfName]interface{}
+)
+
+type pdfRef struct {
+	num int
+	gen int
+}
+
+type pdfStream struct {
+	dict pdfDict
+	raw  []byte
+}
+
+// #3 proses: batas parsing dan decode supaya PDF rusak atau sengaja dibuat besar tidak menghabiskan memori dan CPU
+const (
+	pdfMaxDecodedBytes = 64 << 20
+	pdfMaxNesting      = 64
+	pdfMaxResolveDepth = 32
+	pdfMaxXrefSections = 64
+)
+
+var errPDFLimit = errors.New("PDF melebihi batas render preview")
+
+var pdfObjectHeaderPattern = regexp.MustCompile(`(?:^|[^0-9])(\d+)\s+(\d+)\s+obj\b`)
+
+// #4 proses: lexer token PDF, dipakai untuk object di file maupun operator di content stream
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+// #4a proses: lewati whitespace dan komentar sampai token berikutnya
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		if isPDFWhitespace(c) {
+			l.pos++
+			continue
+		}
+		if c == '%' {
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+			continue
+		}
+		return
+	}
+}
+
+// #4b proses: baca satu token, return false di akhir data. Delimiter array dan dictionary dikembalikan sebagai pdfKeyword
+func (l *pdfLexer) token() (interface{}, bool) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, false
+	}
+
+	c := l.data[l.pos]
+	switch c {
+	case '/':
+		l.pos++
+		start := l.pos
+		for l.pos < len(l.data) && isPDFRegularChar(l.data[l.pos]) {
+			l.pos++
+		}
+		return pdfName(decodePDFName(l.data[start:l.pos])), true
+	case '(':
+		end := pdfLiteralStringEnd(l.data, l.pos)
+		if end < 0 {
+			end = len(l.data)
+		}
+		value := decodePDFLiteral(l.data[l.pos+1 : end])
+		l.pos = min(end+1, len(l.data))
+		return pdfString(value), true
+	case '<':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '<' {
+			l.pos += 2
+			return pdfKeyword("<<"), true
+		}
+		end := bytes.IndexByte(l.data[l.pos:], '>')
+		if end < 0 {
+			end = len(l.data) - l.pos
+		}
+		value := decodePDFHex(l.data[l.pos+1 : l.pos+end])
+		l.pos = min(l.pos+end+1, len(l.data))
+		return pdfString(value), true
+	case '>':
+		if l.pos+1 < len(l.data) && l.data[l.pos+1] == '>' {
+			l.pos += 2
+			return pdfKeyword(">>"), true
+		}
+		l.pos++
+		return pdfKeyword(">"), true
+	case '[', ']', '{', '}', ')':
+		l.pos++
+		return pdfKeyword(string(c)), true
+	}
+
+	start := l.pos
+	for l.pos < len(l.data) && isPDFRegularChar(l.data[l.pos]) {
+		l.pos++
+	}
+	word := l.data[start:l.pos]
+	if number, ok := parsePDFNumber(word); ok {
+		return number, true
+	}
+	return pdfKeyword(word), true
+}
+
+// #4c proses: baca satu object lengkap (array, dictionary, atau referensi "N G R"), keyword lain dikembalikan apa adanya
+func (l *pdfLexer) object(depth int) (interface{}, bool) {
+	if depth > pdfMaxNesting {
+		return nil, false
+	}
+	tok, ok := l.token()
+	if !ok {
+		return nil, false
+	}
+
+	switch value := tok.(type) {
+	case pdfKeyword:
+		switch value {
+		case "[":
+			array := pdfArray{}
+			for {
+				save := l.pos
+				next, ok := l.token()
+				if !ok || next == pdfKeyword("]") {
+					return array, true
+				}
+				l.pos = save
+				item, ok := l.object(depth + 1)
+				if !ok {
+					return array, true
+				}
+				array = append(array, item)
+			}
+		case "<<":
+			dict := pdfDict{}
+			for {
+				next, ok := l.token()
+				if !ok || next == pdfKeyword(">>") {
+					return dict, true
+				}
+				key, isName := next.(pdfName)
+				if !isName {
+					continue
+				}
+				item, ok := l.object(depth + 1)
+				if !ok {
+					return dict, true
+				}
+				if item != pdfKeyword("null") {
+					dict[key] = item
+				}
+			}
+		case "true":
+			return true, true
+		case "false":
+			return false, true
+		case "null":
+			return nil, true
+		}
+		return value, true
+	case float64:
+		// #4d proses: dua angka bulat diikuti R adalah referensi object
+		save := l.pos
+		if gen, ok := l.token(); ok {
+			if genNumber, isNumber := gen.(float64); isNumber {
+				if keyword, ok := l.token(); ok && keyword == pdfKeyword("R") {
+					return pdfRef{num: int(value), gen: int(genNumber)}, true
+				}
+			}
+		}
+		l.pos = save
+		return value, true
+	}
+	return tok, true
+}
+
+// #5 proses: decode nama PDF dengan escape #XX
+func decodePDFName(raw []byte) string {
+	if bytes.IndexByte(raw, '#') < 0 {
+		return string(raw)
+	}
+	out := make([]byte, 0, len(raw))
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '#' && i+2 < len(raw) {
+			hi, okHi := hexDigitValue(raw[i+1])
+			lo, okLo := hexDigitValue(raw[i+2])
+			if okHi && okLo {
+				out = append(out, hi<<4|lo)
+				i += 2
+				continue
+			}
+		}
+		out = append(out, raw[i])
+	}
+	return string(out)
+}
+
+// #6 proses: decode isi string literal, termasuk escape oktal, escape karakter, dan baris yang disambung backslash
+func decodePDFLiteral(raw []byte) []byte {
+	out := make([]byte, 0, len(raw))
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		if c != '\\' || i+1 >= len(raw) {
+			out = append(out, c)
+			continue
+		}
+		i++
+		switch e := raw[i]; e {
+		case 'n':
+			out = append(out, '\n')
+		case 'r':
+			out = append(out, '\r')
+		case 't':
+			out = append(out, '\t')
+		case 'b':
+			out = append(out, '\b')
+		case 'f':
+			out = append(out, '\f')
+		case '\r':
+			if i+1 < len(raw) && raw[i+1] == '\n' {
+				i++
+			}
+		case '\n':
+		default:
+			if e >= '0' && e <= '7' {
+				value := int(e - '0')
+				for n := 0; n < 2 && i+1 < len(raw) && raw[i+1] >= '0' && raw[i+1] <= '7'; n++ {
+					i++
+					value = value*8 + int(raw[i]-'0')
+				}
+				out = append(out, byte(value))
+			} else {
+				out = append(out, e)
+			}
+		}
+	}
+	return out
+}
+
+// #7 proses: decode string hex, whitespace diabaikan dan digit ganjil terakhir dianggap diikuti 0
+func decodePDFHex(raw []byte) []byte {
+	out := make([]byte, 0, len(raw)/2)
+	var hi byte
+	half := false
+	for _, c := range raw {
+		value, ok := hexDigitValue(c)
+		if !ok {
+			continue
+		}
+		if half {
+			out = append(out, hi<<4|value)
+		} else {
+			hi = value
+		}
+		half = !half
+	}
+	if half {
+		out = append(out, hi<<4)
+	}
+	return out
+}
+
+// #8 proses: parse angka PDF (bulat atau desimal, termasuk bentuk ".5" dan "-.5")
+func parsePDFNumber(word []byte) (float64, bool) {
+	if len(word) == 0 {
+		return 0, false
+	}
+	c := word[0]
+	if !(c >= '0' && c <= '9') && c != '-' && c != '+' && c != '.' {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(string(word), 64)
+	if err != nil {
+		// beberapa generator menulis "--5" atau "5-", ambil bagian angka yang valid
+		trimmed := bytes.TrimLeft(word, "+-")
+		if end := bytes.IndexAny(trimmed, "+-"); end > 0 {
+			trimmed = trimmed[:end]
+		}
+		value, err = strconv.ParseFloat(string(trimmed), 64)
+		if err != nil {
+			return 0, false
+		}
+		if c == '-' {
+			value = -value
+		}
+	}
+	return value, true
+}
+
+// #9 proses: dokumen PDF untuk render, object dicari lewat tabel xref (klasik maupun xref stream) dan object stream, dengan fallback memindai header "N G obj" jika xref rusak
+type pdfDocument struct {
+	data       []byte
+	xref       map[int]pdfXrefEntry
+	scanned    map[int]int
+	trailer    pdfDict
+	cache      map[int]interface{}
+	objStreams map[int]*pdfObjectStreamIndex
+	resolving  map[int]bool
+	decoded    int64
+}
+
+type pdfXrefEntry struct {
+	offset     int
+	stream     int
+	index      int
+	compressed bool
+}
+
+type pdfObjectStreamIndex struct {
+	data    []byte
+	offsets map[int]int
+}
+
+// #10 proses: buka dokumen PDF, trailer diambil dari xref terakhir atau dari pemindaian jika xref tidak bisa dibaca
+func openPDFDocument(data []byte) (*pdfDocument, error) {
+	doc := &pdfDocument{
+		data:       data,
+		xref:       map[int]pdfXrefEntry{},
+		cache:      map[int]interface{}{},
+		objStreams: map[int]*pdfObjectStreamIndex{},
+		resolving:  map[int]bool{},
+	}
+
+	doc.readXref()
+	if _, ok := doc.trailer["Root"]; !ok {
+		doc.scanObjects()
+		doc.trailer = doc.scannedTrailer()
+	}
+	if _, ok := doc.trailer["Root"]; !ok {
+		return nil, errors.New("catalog PDF tidak ditemukan")
+	}
+	if _, encrypted := doc.trailer["Encrypt"]; encrypted {
+		return nil, errors.New("PDF terenkripsi tidak bisa dibuatkan preview")
+	}
+	return doc, nil
+}
+
+// #10a proses: baca rantai xref mulai dari startxref terakhir lewat /Prev dan /XRefStm, entri yang lebih baru tidak ditimpa entri lama
+func (d *pdfDocument) readXref() {
+	index := bytes.LastIndex(d.data, []byte("startxref"))
+	if index < 0 {
+		return
+	}
+	lexer := &pdfLexer{data: d.data, pos: index + len("startxref")}
+	tok, ok := lexer.token()
+	offset, isNumber := tok.(float64)
+	if !ok || !isNumber {
+		return
+	}
+
+	visited := map[int]bool{}
+	pending := []int{int(offset)}
+	for len(pending) > 0 && len(visited) < pdfMaxXrefSections {
+		current := pending[0]
+		pending = pending[1:]
+		if current <= 0 || current >= len(d.data) || visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		trailer := d.readXrefSection(current)
+		if trailer == nil {
+			continue
+		}
+		if d.trailer == nil {
+			d.trailer = trailer
+		}
+		if stm, ok := trailer["XRefStm"].(float64); ok {
+			pending = append(pending, int(stm))
+		}
+		if prev, ok := trailer["Prev"].(float64); ok {
+			pending = append(pending, int(prev))
+		}
+	}
+}
+
+// #10b proses: baca satu bagian xref di offset, tabel klasik diakhiri dictionary trailer dan xref stream memakai dictionary stream sebagai trailer
+func (d *pdfDocument) readXrefSection(offset int) pdfDict {
+	lexer := &pdfLexer{data: d.data, pos: offset}
+	lexer.skipSpace()
+	if bytes.HasPrefix(d.data[lexer.pos:], []byte("xref")) {
+		lexer.pos += len("xref")
+		return d.readXrefTable(lexer)
+	}
+
+	object, ok := d.parseObjectAt(offset, -1)
+	stream, isStream := object.(*pdfStream)
+	if !ok || !isStream || stream.dict["Type"] != pdfName("XRef") {
+		return nil
+	}
+	d.readXrefStream(stream)
+	return stream.dict
+}
+
+// #10c proses: tabel xref klasik berisi subsection "start count" lalu baris "offset gen n|f"
+func (d *pdfDocument) readXrefTable(lexer *pdfLexer) pdfDict {
+	for {
+		tok, ok := lexer.token()
+		if !ok {
+			return nil
+		}
+		if tok == pdfKeyword("trailer") {
+			object, _ := lexer.object(0)
+			trailer, _ := object.(pdfDict)
+			return trailer
+		}
+		start, isNumber := tok.(float64)
+		countTok, ok := lexer.token()
+		count, countIsNumber := countTok.(float64)
+		if !isNumber || !ok || !countIsNumber || count < 0 {
+			return nil
+		}
+		for i := 0; i < int(count); i++ {
+			offsetTok, _ := lexer.token()
+			lexer.token()
+			kind, _ := lexer.token()
+			offset, isOffset := offsetTok.(float64)
+			if !isOffset {
+				return nil
+			}
+			num := int(start) + i
+			if _, exists := d.xref[num]; exists || kind != pdfKeyword("n") {
+				continue
+			}
+			d.xref[num] = pdfXrefEntry{offset: int(offset)}
+		}
+	}
+}
+
+// #10d proses: xref stream berisi baris biner dengan lebar kolom /W, tipe 1 object biasa dan tipe 2 object di dalam object stream
+func (d *pdfDocument) readXrefStream(stream *pdfStream) {
+	data, _, err := d.decodeStream(stream)
+	if err != nil {
+		return
+	}
+	widths := pdfNumbers(stream.dict["W"])
+	if len(widths) != 3 {
+		return
+	}
+	w := [3]int{int(widths[0]), int(widths[1]), int(widths[2])}
+	rowSize := w[0] + w[1] + w[2]
+	if rowSize <= 0 || w[0] < 0 || w[1] < 0 || w[2] < 0 {
+		return
+	}
+
+	sections := pdfNumbers(stream.dict["Index"])
+	if len(sections) < 2 {
+		size, _ := stream.dict["Size"].(float64)
+		sections = []float64{0, size}
+	}
+
+	pos := 0
+	for s := 0; s+1 < len(sections); s += 2 {
+		for i := 0; i < int(sections[s+1]); i++ {
+			if pos+rowSize > len(data) {
+				return
+			}
+			row := data[pos : pos+rowSize]
+			pos += rowSize
+
+			kind := 1
+			if w[0] > 0 {
+				kind = readPDFInt(row[:w[0]])
+			}
+			field2 := readPDFInt(row[w[0] : w[0]+w[1]])
+			field3 := readPDFInt(row[w[0]+w[1]:])
+
+			num := int(sections[s]) + i
+			if _, exists := d.xref[num]; exists {
+				continue
+			}
+			switch kind {
+			case 1:
+				d.xref[num] = pdfXrefEntry{offset: field2}
+			case 2:
+				d.xref[num] = pdfXrefEntry{compressed: true, stream: field2, index: field3}
+			}
+		}
+	}
+}
+
+func readPDFInt(b []byte) int {
+	value := 0
+	for _, c := range b {
+		value = value<<8 | int(c)
+	}
+	return value
+}
+
+// #10e proses: fallback untuk xref rusak, pindai semua header "N G obj" dan definisi terakhir yang berlaku
+func (d *pdfDocument) scanObjects() {
+	if d.scanned != nil {
+		return
+	}
+	d.scanned = map[int]int{}
+	for _, match := range pdfObjectHeaderPattern.FindAllSubmatchIndex(d.data, -1) {
+		num, err := strconv.Atoi(string(d.data[match[2]:match[3]]))
+		if err != nil {
+			continue
+		}
+		d.scanned[num] = match[2]
+	}
+}
+
+// #10f proses: trailer hasil pemindaian dari dictionary trailer atau xref stream terakhir yang punya /Root, jika tidak ada cari object /Catalog langsung
+func (d *pdfDocument) scannedTrailer() pdfDict {
+	for index := len(d.data); ; {
+		index = bytes.LastIndex(d.data[:index], []byte("trailer"))
+		if index < 0 {
+			break
+		}
+		lexer := &pdfLexer{data: d.data, pos: index + len("trailer")}
+		object, _ := lexer.object(0)
+		if trailer, ok := object.(pdfDict); ok {
+			if _, hasRoot := trailer["Root"]; hasRoot {
+				return trailer
+			}
+		}
+	}
+
+	nums := make([]int, 0, len(d.scanned))
+	for num := range d.scanned {
+		nums = append(nums, num)
+	}
+	var catalog interface{}
+	for _, num := range nums {
+		object := d.getObject(num)
+		switch value := object.(type) {
+		case *pdfStream:
+			if value.dict["Type"] == pdfName("XRef") {
+				if root, ok := value.dict["Root"]; ok {
+					return pdfDict{"Root": root}
+				}
+			}
+		case pdfDict:
+			if value["Type"] == pdfName("Catalog") && catalog == nil {
+				catalog = pdfRef{num: num}
+			}
+		}
+	}
+	if catalog != nil {
+		return pdfDict{"Root": catalog}
+	}
+
+	// #10g proses: catalog di dalam object stream hanya bisa ditemukan dengan membuka semua object stream
+	for _, num := range nums {
+		stream, ok := d.getObject(num).(*pdfStream)
+		if !ok || stream.dict["Type"] != pdfName("ObjStm") {
+			continue
+		}
+		index := d.objectStream(num)
+		if index == nil {
+			continue
+		}
+		for inner := range index.offsets {
+			if _, defined := d.scanned[inner]; !defined {
+				d.xref[inner] = pdfXrefEntry{compressed: true, stream: num, index: -1}
+			}
+			if dict, ok := d.getObject(inner).(pdfDict); ok && dict["Type"] == pdfName("Catalog") {
+				return pdfDict{"Root": pdfRef{num: inner}}
+			}
+		}
+	}
+	return pdfDict{}
+}
+
+// #11 proses: ambil object berdasarkan nomor, dari cache, xref, object stream, atau hasil pemindaian
+func (d *pdfDocument) getObject(num int) interface{} {
+	if object, ok := d.cache[num]; ok {
+		return object
+	}
+	if d.resolving[num] || len(d.resolving) > pdfMaxResolveDepth {
+		return nil
+	}
+	d.resolving[num] = true
+	defer delete(d.resolving, num)
+
+	var object interface{}
+	found := false
+	if entry, ok := d.xref[num]; ok {
+		if entry.compressed {
+			object, found = d.compressedObject(num, entry)
+		} else {
+			object, found = d.parseObjectAt(entry.offset, num)
+		}
+	}
+	if !found {
+		d.scanObjects()
+		if offset, ok := d.scanned[num]; ok {
+			object, found = d.parseObjectAt(offset, num)
+		}
+	}
+	d.cache[num] = object
+	return object
+}
+
+// #11a proses: object di dalam object stream dicari berdasarkan nomor object di header stream
+func (d *pdfDocument) compressedObject(num int, entry pdfXrefEntry) (interface{}, bool) {
+	index := d.objectStream(entry.stream)
+	if index == nil {
+		return nil, false
+	}
+	offset, ok := index.offsets[num]
+	if !ok || offset >= len(index.data) {
+		return nil, false
+	}
+	lexer := &pdfLexer{data: index.data, pos: offset}
+	return lexer.object(0)
+}
+
+// #11b proses: decode object stream sekali lalu simpan posisi tiap object, posisi di header relatif terhadap /First
+func (d *pdfDocument) objectStream(num int) *pdfObjectStreamIndex {
+	if index, ok := d.objStreams[num]; ok {
+		return index
+	}
+	d.objStreams[num] = nil
+
+	stream, ok := d.getObject(num).(*pdfStream)
+	if !ok {
+		return nil
+	}
+	data, _, err := d.decodeStream(stream)
+	if err != nil {
+		return nil
+	}
+	first, _ := stream.dict["First"].(float64)
+	count, _ := stream.dict["N"].(float64)
+	if first < 0 || int(first) > len(data) {
+		return nil
+	}
+
+	index := &pdfObjectStreamIndex{data: data, offsets: map[int]int{}}
+	lexer := &pdfLexer{data: data[:int(first)]}
+	for i := 0; i < int(count); i++ {
+		numTok, ok1 := lexer.token()
+		offsetTok, ok2 := lexer.token()
+		objectNum, isNum := numTok.(float64)
+		offset, isOffset := offsetTok.(float64)
+		if !ok1 || !ok2 || !isNum || !isOffset {
+			break
+		}
+		index.offsets[int(objectNum)] = int(first) + int(offset)
+	}
+	d.objStreams[num] = index
+	return index
+}
+
+// #11c proses: parse "N G obj" di offset, expectedNum -1 berarti nomor object tidak dicek. Dictionary yang diikuti keyword stream dibaca sebagai stream
+func (d *pdfDocument) parseObjectAt(offset int, expectedNum int) (interface{}, bool) {
+	if offset < 0 || offset >= len(d.data) {
+		return nil, false
+	}
+	lexer := &pdfLexer{data: d.data, pos: offset}
+	numTok, _ := lexer.token()
+	lexer.token()
+	objTok, _ := lexer.token()
+	num, isNum := numTok.(float64)
+	if !isNum || objTok != pdfKeyword("obj") || (expectedNum >= 0 && int(num) != expectedNum) {
+		return nil, false
+	}
+
+	object, ok := lexer.object(0)
+	if !ok {
+		return nil, false
+	}
+	dict, isDict := object.(pdfDict)
+	if !isDict {
+		return object, true
+	}
+
+	save := lexer.pos
+	if tok, _ := lexer.token(); tok != pdfKeyword("stream") {
+		lexer.pos = save
+		return dict, true
+	}
+	start := lexer.pos
+	if start < len(d.data) && d.data[start] == '\r' {
+		start++
+	}
+	if start < len(d.data) && d.data[start] == '\n' {
+		start++
+	}
+	return &pdfStream{dict: dict, raw: d.data[start:d.streamEnd(dict, start)]}, true
+}
+
+// #11d proses: akhir isi stream dari /Length jika diikuti endstream, selain itu cari keyword endstream berikutnya
+func (d *pdfDocument) streamEnd(dict pdfDict, start int) int {
+	if length, ok := d.resolve(dict["Length"]).(float64); ok && length >= 0 && start+int(length) <= len(d.data) {
+		end := start + int(length)
+		rest := bytes.TrimLeft(d.data[end:min(end+32, len(d.data))], " \t\r\n\f\x00")
+		if bytes.HasPrefix(rest, []byte("endstream")) {
+			return end
+		}
+	}
+	index := bytes.Index(d.data[start:], []byte("endstream"))
+	if index < 0 {
+		return len(d.data)
+	}
+	end := start + index
+	if end > start && d.data[end-1] == '\n' {
+		end--
+	}
+	if end > start && d.data[end-1] == '\r' {
+		end--
+	}
+	return end
+}
+
+// #12 proses: ikuti referensi sampai mendapat object langsung
+func (d *pdfDocument) resolve(value interface{}) interface{} {
+	for i := 0; i < pdfMaxResolveDepth; i++ {
+		ref, ok := value.(pdfRef)
+		if !ok {
+			return value
+		}
+		value = d.getObject(ref.num)
+	}
+	return nil
+}
+
+func (d *pdfDocument) dict(value interface{}) pdfDict {
+	switch resolved := d.resolve(value).(type) {
+	case pdfDict:
+		return resolved
+	case *pdfStream:
+		return resolved.dict
+	}
+	return nil
+}
+
+func (d *pdfDocument) array(value interface{}) pdfArray {
+	array, _ := d.resolve(value).(pdfArray)
+	return array
+}
+
+func (d *pdfDocument) number(value interface{}, fallback float64) float64 {
+	if number, ok := d.resolve(value).(float64); ok {
+		return number
+	}
+	return fallback
+}
+
+func (d *pdfDocument) name(value interface{}) pdfName {
+	name, _ := d.resolve(value).(pdfName)
+	return name
+}
+
+func (d *pdfDocument) numbers(value interface{}) []float64 {
+	array := d.array(value)
+	out := make([]float64, 0, len(array))
+	for _, item := range array {
+		out = append(out, d.number(item, 0))
+	}
+	return out
+}
+
+func pdfNumbers(value interface{}) []float64 {
+	array, _ := value.(pdfArray)
+	out := make([]float64, 0, len(array))
+	for _, item := range array {
+		number, _ := item.(float64)
+		out = append(out, number)
+	}
+	return out
+}
+
+// #13 proses: decode isi stream lewat semua filter, filter gambar (DCT, JPX, CCITT, JBIG2) tidak didecode dan namanya dikembalikan untuk decoder gambar
+func (d *pdfDocument) decodeStream(stream *pdfStream) ([]byte, pdfName, error) {
+	filters := []pdfName{}
+	switch value := d.resolve(stream.dict["Filter"]).(type) {
+	case pdfName:
+		filters = append(filters, value)
+	case pdfArray:
+		for _, item := range value {
+			filters = append(filters, d.name(item))
+		}
+	}
+	params := []pdfDict{}
+	switch value := d.resolve(stream.dict["DecodeParms"]).(type) {
+	case pdfDict:
+		params = append(params, value)
+	case pdfArray:
+		for _, item := range value {
+			params = append(params, d.dict(item))
+		}
+	}
+
+	data := stream.raw
+	for i, filter := range filters {
+		var param pdfDict
+		if i < len(params) {
+			param = params[i]
+		}
+		var err error
+		switch filter {
+		case "FlateDecode", "Fl":
+			data, err = d.inflate(data)
+			if err == nil {
+				data = d.unpredict(data, param)
+			}
+		case "LZWDecode", "LZW":
+			early := d.number(param["EarlyChange"], 1)
+			data, err = d.decodeLZW(data, early != 0)
+			if err == nil {
+				data = d.unpredict(data, param)
+			}
+		case "ASCIIHexDecode", "AHx":
+			end := bytes.IndexByte(data, '>')
+			if end < 0 {
+				end = len(data)
+			}
+			data = decodePDFHex(data[:end])
+		case "ASCII85Decode", "A85":
+			data, err = decodePDFASCII85(data)
+		case "RunLengthDecode", "RL":
+			data, err = d.decodeRunLength(data)
+		case "DCTDecode", "DCT", "JPXDecode", "CCITTFaxDecode", "CCF", "JBIG2Decode":
+			return data, filter, nil
+		default:
+			return nil, "", errors.New("filter PDF tidak didukung: " + string(filter))
+		}
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	return data, "", nil
+}
+
+// #13a proses: hitung total byte hasil decode di seluruh dokumen supaya stream yang mengembang berlebihan dihentikan
+func (d *pdfDocument) reserveDecoded(n int) error {
+	d.decoded += int64(n)
+	if d.decoded > pdfMaxDecodedBytes {
+		return errPDFLimit
+	}
+	return nil
+}
+
+// #13b proses: inflate zlib, data tanpa header zlib dicoba sebagai deflate mentah. Stream terpotong tetap dipakai sampai bagian yang terbaca
+func (d *pdfDocument) inflate(data []byte) ([]byte, error) {
+	var reader io.Reader
+	if zr, err := zlib.NewReader(bytes.NewReader(data)); err == nil {
+		reader = zr
+	} else {
+		reader = flate.NewReader(bytes.NewReader(data))
+	}
+
+	remaining := pdfMaxDecodedBytes - d.decoded
+	out, err := io.ReadAll(io.LimitReader(reader, remaining+1))
+	if err := d.reserveDecoded(len(out)); err != nil {
+		return nil, err
+	}
+	if err != nil && len(out) == 0 {
+		return nil, errors.New("error inflate stream PDF: " + err.Error())
+	}
+	return out, nil
+}
+
+// #13c proses: balik predictor PNG (>= 10) dan TIFF (2) per baris sesuai /Colors, /BitsPerComponent, dan /Columns
+func (d *pdfDocument) unpredict(data []byte, param pdfDict) []byte {
+	predictor := int(d.number(param["Predictor"], 1))
+	if predictor < 2 {
+		return data
+	}
+	colors := max(1, int(d.number(param["Colors"], 1)))
+	bpc := max(1, int(d.number(param["BitsPerComponent"], 8)))
+	columns := max(1, int(d.number(param["Columns"], 1)))
+	bpp := max(1, (colors*bpc+7)/8)
+	rowLen := (colors*bpc*columns + 7) / 8
+
+	if predictor == 2 {
+		if bpc != 8 {
+			return data
+		}
+		out := append([]byte(nil), data...)
+		for row := 0; row+rowLen <= len(out); row += rowLen {
+			for i := bpp; i < rowLen; i++ {
+				out[row+i] += out[row+i-bpp]
+			}
+		}
+		return out
+	}
+
+	out := make([]byte, 0, len(data))
+	prev := make([]byte, rowLen)
+	for pos := 0; pos+1 <= len(data); pos += rowLen + 1 {
+		filter := data[pos]
+		end := min(pos+1+rowLen, len(data))
+		row := make([]byte, rowLen)
+		copy(row, data[pos+1:end])
+		for i := 0; i < rowLen; i++ {
+			var left, upLeft byte
+			if i >= bpp {
+				left = row[i-bpp]
+				upLeft = prev[i-bpp]
+			}
+			up := prev[i]
+			switch filter {
+			case 1:
+				row[i] += left
+			case 2:
+				row[i] += up
+			case 3:
+				row[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				row[i] += pngPaeth(left, up, upLeft)
+			}
+		}
+		out = append(out, row...)
+		prev = row
+	}
+	return out
+}
+
+func pngPaeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := abs(p-int(a)), abs(p-int(b)), abs(p-int(c))
+	if pa <= pb && pa <= pc {
+		return a
+	}
+	if pb <= pc {
+		return b
+	}
+	return c
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// #13d proses: decode LZW dengan kode 9-12 bit, EarlyChange menaikkan lebar kode satu entri lebih awal seperti di spesifikasi PDF
+func (d *pdfDocument) decodeLZW(data []byte, early bool) ([]byte, error) {
+	const clearCode, endCode = 256, 257
+	table := make([][]byte, 0, 4096)
+	reset := func() {
+		table = table[:0]
+		for i := 0; i < 256; i++ {
+			table = append(table, []byte{byte(i)})
+		}
+		table = append(table, nil, nil)
+	}
+	reset()
+
+	out := []byte{}
+	width := 9
+	var bitBuf uint32
+	bitCount := 0
+	var prev []byte
+	for pos := 0; ; {
+		for bitCount < width && pos < len(data) {
+			bitBuf = bitBuf<<8 | uint32(data[pos])
+			bitCount += 8
+			pos++
+		}
+		if bitCount < width {
+			break
+		}
+		code := int(bitBuf>>(bitCount-width)) & (1<<width - 1)
+		bitCount -= width
+
+		switch {
+		case code == clearCode:
+			reset()
+			width = 9
+			prev = nil
+			continue
+		case code == endCode:
+			return out, d.reserveDecoded(len(out))
+		}
+
+		var entry []byte
+		switch {
+		case code < len(table) && table[code] != nil:
+			entry = table[code]
+		case code == len(table) && prev != nil:
+			entry = append(append([]byte(nil), prev...), prev[0])
+		default:
+			return out, d.reserveDecoded(len(out))
+		}
+		out = append(out, entry...)
+		if len(out) > pdfMaxDecodedBytes {
+			return nil, errPDFLimit
+		}
+		if prev != nil && len(table) < 4096 {
+			table = append(table, append(append([]byte(nil), prev...), entry[0]))
+		}
+		prev = entry
+
+		next := len(table)
+		if early {
+			next++
+		}
+		switch {
+		case next >= 2048:
+			width = 12
+		case next >= 1024:
+			width = 11
+		case next >= 512:
+			width = 10
+		}
+	}
+	return out, d.reserveDecoded(len(out))
+}
+
+// #13e proses: decode ASCII85, whitespace dan penanda akhir "~>" ditangani sebelum dikirim ke decoder standar
+func decodePDFASCII85(data []byte) ([]byte, error) {
+	if end := bytes.Index(data, []byte("~>")); end >= 0 {
+		data = data[:end]
+	}
+	data = bytes.TrimPrefix(bytes.TrimSpace(data), []byte("<~"))
+	out := make([]byte, 4*len(data)/5+4)
+	n, _, err := ascii85.Decode(out, data, true)
+	if err != nil {
+		return nil, errors.New("error decode ASCII85 stream PDF: " + err.Error())
+	}
+	return out[:n], nil
+}
+
+// #13f proses: decode RunLength, byte panjang 0-127 menyalin n+1 byte dan 129-255 mengulang byte berikutnya 257-n kali
+func (d *pdfDocument) decodeRunLength(data []byte) ([]byte, error) {
+	out := []byte{}
+	for i := 0; i < len(data); {
+		length := int(data[i])
+		i++
+		switch {
+		case length == 128:
+			return out, d.reserveDecoded(len(out))
+		case length < 128:
+			end := min(i+length+1, len(data))
+			out = append(out, data[i:end]...)
+			i = end
+		default:
+			if i < len(data) {
+				out = append(out, bytes.Repeat([]byte{data[i]}, 257-length)...)
+			}
+			i++
+		}
+		if len(out) > pdfMaxDecodedBytes {
+			return nil, errPDFLimit
+		}
+	}
+	return out, d.reserveDecoded(len(out))
+}
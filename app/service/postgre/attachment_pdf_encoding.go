@@ -0,0 +1,206 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk strconv dan strings
+import (
+	"strconv"
+	"strings"
+)
+
+// #2 proses: nama glyph string standar CFF untuk SID 0-228 (charset ISOAdobe), sekaligus sumber nama glyph StandardEncoding
+var cffStandardStrings = strings.Fields(`.notdef space exclam quotedbl numbersign dollar percent ampersand quoteright
+parenleft parenright asterisk plus comma hyphen period slash zero one two three four five six seven eight nine
+colon semicolon less equal greater question at A B C D E F G H I J K L M N O P Q R S T U V W X Y Z
+bracketleft backslash bracketright asciicircum underscore quoteleft a b c d e f g h i j k l m n o p q r s t u v w x y z
+braceleft bar braceright asciitilde exclamdown cent sterling fraction yen florin section currency quotesingle
+quotedblleft guillemotleft guilsinglleft guilsinglright fi fl endash dagger daggerdbl periodcentered paragraph
+bullet quotesinglbase quotedblbase quotedblright guillemotright ellipsis perthousand questiondown grave acute
+circumflex tilde macron breve dotaccent dieresis ring cedilla hungarumlaut ogonek caron emdash AE ordfeminine
+Lslash Oslash OE ordmasculine ae dotlessi lslash oslash oe germandbls onesuperior logicalnot mu trademark Eth
+onehalf plusminus Thorn onequarter divide brokenbar degree thorn threequarters twosuperior registered minus eth
+multiply threesuperior copyright Aacute Acircumflex Adieresis Agrave Aring Atilde Ccedilla Eacute Ecircumflex
+Edieresis Egrave Iacute Icircumflex Idieresis Igrave Ntilde Oacute Ocircumflex Odieresis Ograve Otilde Scaron
+Uacute Ucircumflex Udieresis Ugrave Yacute Ydieresis Zcaron aacute acircumflex adieresis agrave aring atilde
+ccedilla eacute ecircumflex edieresis egrave iacute icircumflex idieresis igrave ntilde oacute ocircumflex
+odieresis ograve otilde scaron uacute ucircumflex udieresis ugrave yacute ydieresis zcaron`)
+
+// #3 proses: tabel encoding dasar (kode 0-255 ke nama glyph), dibangun sekali saat package dimuat
+var (
+	pdfStandardEncoding = buildPDFStandardEncoding()
+	pdfWinAnsiEncoding  = buildPDFWinAnsiEncoding()
+	pdfMacRomanEncoding = buildPDFASCIIEncoding("quotesingle", "grave")
+	pdfGlyphUnicode     = buildPDFGlyphUnicode()
+)
+
+// #3a proses: kode 32-126 memakai nama ASCII, kode 39 dan 96 berbeda antar encoding
+func buildPDFASCIIEncoding(quote, backquote string) [256]string {
+	var table [256]string
+	for code := 32; code <= 126; code++ {
+		table[code] = cffStandardStrings[code-31]
+	}
+	table[39] = quote
+	table[96] = backquote
+	return table
+}
+
+// #3b proses: StandardEncoding Adobe, kode 161 ke atas diambil dari urutan string standar CFF
+func buildPDFStandardEncoding() [256]string {
+	table := buildPDFASCIIEncoding("quoteright", "quoteleft")
+	upper := map[int]string{
+		161: "exclamdown", 162: "cent", 163: "sterling", 164: "fraction", 165: "yen", 166: "florin",
+		167: "section", 168: "currency", 169: "quotesingle", 170: "quotedblleft", 171: "guillemotleft",
+		172: "guilsinglleft", 173: "guilsinglright", 174: "fi", 175: "fl", 177: "endash", 178: "dagger",
+		179: "daggerdbl", 180: "periodcentered", 182: "paragraph", 183: "bullet", 184: "quotesinglbase",
+		185: "quotedblbase", 186: "quotedblright", 187: "guillemotright", 188: "ellipsis", 189: "perthousand",
+		191: "questiondown", 193: "grave", 194: "acute", 195: "circumflex", 196: "tilde", 197: "macron",
+		198: "breve", 199: "dotaccent", 200: "dieresis", 202: "ring", 203: "cedilla", 205: "hungarumlaut",
+		206: "ogonek", 207: "caron", 208: "emdash", 225: "AE", 227: "ordfeminine", 232: "Lslash",
+		233: "Oslash", 234: "OE", 235: "ordmasculine", 241: "ae", 245: "dotlessi", 248: "lslash",
+		249: "oslash", 250: "oe", 251: "germandbls",
+	}
+	for code, name := range upper {
+		table[code] = name
+	}
+	return table
+}
+
+// #3c proses: WinAnsiEncoding, kode 128-159 mengikuti cp1252 dan 160-255 mengikuti Latin-1
+func buildPDFWinAnsiEncoding() [256]string {
+	table := buildPDFASCIIEncoding("quotesingle", "grave")
+	for code, name := range pdfWinAnsiHigh {
+		table[code] = name
+	}
+	latin := strings.Fields(`space exclamdown cent sterling currency yen brokenbar section dieresis copyright
+ordfeminine guillemotleft logicalnot hyphen registered macron degree plusminus twosuperior threesuperior acute mu
+paragraph periodcentered cedilla onesuperior ordmasculine guillemotright onequarter onehalf threequarters
+questiondown Agrave Aacute Acircumflex Atilde Adieresis Aring AE Ccedilla Egrave Eacute Ecircumflex Edieresis
+Igrave Iacute Icircumflex Idieresis Eth Ntilde Ograve Oacute Ocircumflex Otilde Odieresis multiply Oslash Ugrave
+Uacute Ucircumflex Udieresis Yacute Thorn germandbls agrave aacute acircumflex atilde adieresis aring ae ccedilla
+egrave eacute ecircumflex edieresis igrave iacute icircumflex idieresis eth ntilde ograve oacute ocircumflex
+otilde odieresis divide oslash ugrave uacute ucircumflex udieresis yacute thorn ydieresis`)
+	for i, name := range latin {
+		table[160+i] = name
+	}
+	return table
+}
+
+var pdfWinAnsiHigh = map[int]string{
+	128: "Euro", 130: "quotesinglbase", 131: "florin", 132: "quotedblbase", 133: "ellipsis", 134: "dagger",
+	135: "daggerdbl", 136: "circumflex", 137: "perthousand", 138: "Scaron", 139: "guilsinglleft", 140: "OE",
+	142: "Zcaron", 145: "quoteleft", 146: "quoteright", 147: "quotedblleft", 148: "quotedblright", 149: "bullet",
+	150: "endash", 151: "emdash", 152: "tilde", 153: "trademark", 154: "scaron", 155: "guilsinglright", 156: "oe",
+	158: "zcaron", 159: "Ydieresis",
+}
+
+var pdfCP1252High = map[int]rune{
+	128: 0x20AC, 130: 0x201A, 131: 0x0192, 132: 0x201E, 133: 0x2026, 134: 0x2020, 135: 0x2021, 136: 0x02C6,
+	137: 0x2030, 138: 0x0160, 139: 0x2039, 140: 0x0152, 142: 0x017D, 145: 0x2018, 146: 0x2019, 147: 0x201C,
+	148: 0x201D, 149: 0x2022, 150: 0x2013, 151: 0x2014, 152: 0x02DC, 153: 0x2122, 154: 0x0161, 155: 0x203A,
+	156: 0x0153, 158: 0x017E, 159: 0x0178,
+}
+
+// #3d proses: nama glyph ke unicode untuk mencari glyph TrueType lewat cmap unicode, diturunkan dari WinAnsi (cp1252) ditambah nama yang hanya ada di StandardEncoding
+func buildPDFGlyphUnicode() map[string]rune {
+	table := map[string]rune{}
+	for code, name := range pdfWinAnsiEncoding {
+		if name == "" {
+			continue
+		}
+		if _, exists := table[name]; exists {
+			continue
+		}
+		if r, ok := pdfCP1252High[code]; ok {
+			table[name] = r
+		} else {
+			table[name] = rune(code)
+		}
+	}
+	extra := map[string]rune{
+		"quoteright": 0x2019, "quoteleft": 0x2018, "fraction": 0x2044, "fi": 0xFB01, "fl": 0xFB02,
+		"dotlessi": 0x0131, "Lslash": 0x0141, "lslash": 0x0142, "breve": 0x02D8, "dotaccent": 0x02D9,
+		"ring": 0x02DA, "hungarumlaut": 0x02DD, "ogonek": 0x02DB, "caron": 0x02C7, "minus": 0x2212,
+		"nbspace": 0x00A0, "sfthyphen": 0x00AD, "Delta": 0x2206, "Omega": 0x2126, "mu1": 0x00B5,
+	}
+	for name, r := range extra {
+		table[name] = r
+	}
+	return table
+}
+
+// #4 proses: unicode dari nama glyph, termasuk bentuk uniXXXX dan uXXXX[XX] dari Adobe Glyph List
+func glyphNameUnicode(name string) (rune, bool) {
+	if r, ok := pdfGlyphUnicode[name]; ok {
+		return r, true
+	}
+	if dot := strings.IndexByte(name, '.'); dot > 0 {
+		name = name[:dot]
+	}
+	for _, prefix := range []string{"uni", "u"} {
+		if strings.HasPrefix(name, prefix) && len(name) >= len(prefix)+4 {
+			if value, err := strconv.ParseUint(name[len(prefix):len(prefix)+4], 16, 32); err == nil {
+				if prefix == "u" && len(name) <= 7 {
+					if long, err := strconv.ParseUint(name[1:], 16, 32); err == nil {
+						return rune(long), true
+					}
+				}
+				return rune(value), true
+			}
+		}
+	}
+	return 0, false
+}
+
+// #5 proses: encoding dasar berdasarkan nama /BaseEncoding
+func pdfBaseEncoding(name pdfName) ([256]string, bool) {
+	switch name {
+	case "WinAnsiEncoding":
+		return pdfWinAnsiEncoding, true
+	case "MacRomanEncoding", "MacExpertEncoding":
+		return pdfMacRomanEncoding, true
+	case "StandardEncoding":
+		return pdfStandardEncoding, true
+	}
+	return [256]string{}, false
+}
+
+// #6 proses: lebar glyph kode 32-126 untuk font standar yang tidak di-embed, varian bold dan italic memakai lebar regular sebagai pendekatan dan Courier selalu 600
+var (
+	pdfHelveticaWidths = []float64{
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556, 1015,
+		667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611,
+		278, 278, 278, 469, 556, 333,
+		556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556, 556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500,
+		334, 260, 334, 584,
+	}
+	pdfTimesWidths = []float64{
+		250, 333, 408, 500, 500, 833, 778, 180, 333, 333, 500, 564, 250, 333, 250, 278,
+		500, 500, 500, 500, 500, 500, 500, 500, 500, 500, 278, 278, 564, 564, 564, 444, 921,
+		722, 667, 667, 722, 611, 556, 722, 722, 333, 389, 722, 611, 889, 722, 722, 556, 722, 667, 556, 611, 722, 722, 944, 722, 722, 611,
+		333, 278, 333, 469, 500, 333,
+		444, 500, 444, 500, 444, 333, 500, 500, 278, 278, 500, 278, 778, 500, 500, 500, 500, 333, 389, 278, 500, 500, 722, 500, 500, 444,
+		480, 200, 480, 541,
+	}
+)
+
+// #6a proses: lebar glyph font standar berdasarkan nama BaseFont, false jika font bukan keluarga Helvetica, Times, atau Courier
+func standardFontWidth(baseFont string, code int) (float64, bool) {
+	base := baseFont
+	if plus := strings.IndexByte(base, '+'); plus == 6 {
+		base = base[plus+1:]
+	}
+	switch {
+	case strings.HasPrefix(base, "Courier"):
+		return 600, true
+	case strings.HasPrefix(base, "Helvetica"), strings.HasPrefix(base, "Arial"):
+		if code >= 32 && code <= 126 {
+			return pdfHelveticaWidths[code-32], true
+		}
+		return 556, true
+	case strings.HasPrefix(base, "Times"):
+		if code >= 32 && code <= 126 {
+			return pdfTimesWidths[code-32], true
+		}
+		return 500, true
+	}
+	return 0, false
+}
@@ -0,0 +1,511 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk bytes dan strings
+import (
+	"bytes"
+	"strings"
+)
+
+// #2 proses: font PDF hasil load untuk render teks. Outline glyph dalam satuan 1/1000 ruang teks, font tanpa program font ter-embed ditandai embedded false dan digambar sebagai kotak abu-abu selebar glyph
+type pdfFont struct {
+	composite    bool
+	vertical     bool
+	codespace    []pdfCodespaceRange
+	cidRanges    []pdfCIDRange
+	identity     bool
+	widths       map[int]float64
+	defaultWidth float64
+	embedded     bool
+	glyph        func(code, cid int) *pdfPath
+	advance      func(code, cid int) (float64, bool)
+	baseFont     string
+	type3        *pdfType3Font
+	cache        map[int]*pdfPath
+}
+
+type pdfCodespaceRange struct {
+	low, high []byte
+}
+
+type pdfCIDRange struct {
+	low, high []byte
+	cid       int
+}
+
+type pdfType3Font struct {
+	matrix    pdfMatrix
+	procs     map[int]*pdfStream
+	resources pdfDict
+}
+
+type pdfCharCode struct {
+	code   int
+	cid    int
+	length int
+}
+
+// #3 proses: pecah string teks menjadi kode karakter, font sederhana selalu 1 byte dan font Type0 mengikuti codespace CMap
+func (f *pdfFont) decode(text []byte) []pdfCharCode {
+	out := make([]pdfCharCode, 0, len(text))
+	if !f.composite {
+		for _, b := range text {
+			out = append(out, pdfCharCode{code: int(b), cid: int(b), length: 1})
+		}
+		return out
+	}
+
+	for pos := 0; pos < len(text); {
+		length := f.codeLength(text[pos:])
+		code := 0
+		for _, b := range text[pos : pos+length] {
+			code = code<<8 | int(b)
+		}
+		out = append(out, pdfCharCode{code: code, cid: f.codeToCID(text[pos:pos+length], code), length: length})
+		pos += length
+	}
+	return out
+}
+
+// #3a proses: panjang kode dari codespace range pertama yang cocok, tanpa codespace dianggap 2 byte seperti Identity-H
+func (f *pdfFont) codeLength(text []byte) int {
+	if len(f.codespace) == 0 {
+		return min(2, len(text))
+	}
+	for n := 1; n <= 4 && n <= len(text); n++ {
+		for _, r := range f.codespace {
+			if len(r.low) == n && bytesInRange(text[:n], r.low, r.high) {
+				return n
+			}
+		}
+	}
+	return 1
+}
+
+func bytesInRange(code, low, high []byte) bool {
+	if len(code) != len(low) || len(code) != len(high) {
+		return false
+	}
+	for i := range code {
+		if code[i] < low[i] || code[i] > high[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// #3b proses: kode ke CID lewat cidrange/cidchar CMap, CMap Identity memakai kode sebagai CID
+func (f *pdfFont) codeToCID(raw []byte, code int) int {
+	if f.identity {
+		return code
+	}
+	for _, r := range f.cidRanges {
+		if len(r.low) != len(raw) {
+			continue
+		}
+		low, high := bytesToInt(r.low), bytesToInt(r.high)
+		if code >= low && code <= high {
+			return r.cid + code - low
+		}
+	}
+	return 0
+}
+
+func bytesToInt(b []byte) int {
+	value := 0
+	for _, c := range b {
+		value = value<<8 | int(c)
+	}
+	return value
+}
+
+// #4 proses: lebar glyph dalam satuan 1/1000 ruang teks, urutannya /Widths atau /W, advance dari program font, lalu lebar default
+func (f *pdfFont) width(c pdfCharCode) float64 {
+	key := c.code
+	if f.composite {
+		key = c.cid
+	}
+	if w, ok := f.widths[key]; ok {
+		if f.type3 != nil {
+			return w * f.type3.matrix[0] * 1000
+		}
+		return w
+	}
+	if f.advance != nil {
+		if w, ok := f.advance(c.code, c.cid); ok {
+			return w
+		}
+	}
+	if !f.composite {
+		if w, ok := standardFontWidth(f.baseFont, c.code); ok {
+			return w
+		}
+	}
+	return f.defaultWidth
+}
+
+// #4a proses: outline glyph dengan cache per kode, nil jika glyph kosong atau font tidak ter-embed
+func (f *pdfFont) outline(c pdfCharCode) *pdfPath {
+	if f.glyph == nil {
+		return nil
+	}
+	key := c.code
+	if f.composite {
+		key = c.cid
+	}
+	if path, ok := f.cache[key]; ok {
+		return path
+	}
+	path := f.glyph(c.code, c.cid)
+	if path != nil && len(path.segments) == 0 {
+		path = nil
+	}
+	f.cache[key] = path
+	return path
+}
+
+// #5 proses: load font dari dictionary /Font, subtype Type0 untuk font komposit dan Type3 untuk glyph berupa content stream
+func (d *pdfDocument) loadFont(value interface{}) *pdfFont {
+	dict := d.dict(value)
+	if dict == nil {
+		return nil
+	}
+	font := &pdfFont{
+		widths:       map[int]float64{},
+		defaultWidth: 500,
+		cache:        map[int]*pdfPath{},
+		baseFont:     string(d.name(dict["BaseFont"])),
+	}
+	switch d.name(dict["Subtype"]) {
+	case "Type0":
+		d.loadCompositeFont(font, dict)
+	case "Type3":
+		d.loadType3Font(font, dict)
+	default:
+		d.loadSimpleFont(font, dict)
+	}
+	return font
+}
+
+// #5a proses: program font ter-embed dari FontDescriptor, FontFile (Type 1), FontFile2 (TrueType), atau FontFile3 (CFF atau OpenType)
+type pdfFontProgram struct {
+	trueType *pdfTrueType
+	cff      *pdfCFF
+	type1    *pdfType1
+}
+
+func (d *pdfDocument) fontProgram(descriptor pdfDict) pdfFontProgram {
+	program := pdfFontProgram{}
+	read := func(key pdfName) []byte {
+		stream, ok := d.resolve(descriptor[key]).(*pdfStream)
+		if !ok {
+			return nil
+		}
+		data, _, err := d.decodeStream(stream)
+		if err != nil {
+			return nil
+		}
+		return data
+	}
+
+	if data := read("FontFile"); data != nil {
+		program.type1, _ = parseType1(data)
+	}
+	if data := read("FontFile2"); data != nil {
+		program.trueType, _ = parseTrueType(data)
+	}
+	if data := read("FontFile3"); data != nil {
+		if bytes.HasPrefix(data, []byte("OTTO")) || bytes.HasPrefix(data, []byte{0, 1, 0, 0}) || bytes.HasPrefix(data, []byte("true")) {
+			if sfnt, err := parseSFNT(data); err == nil {
+				if cff := sfnt.tables["CFF "]; cff != nil {
+					program.cff, _ = parseCFF(cff)
+				} else {
+					program.trueType, _ = parseTrueType(data)
+				}
+			}
+		} else {
+			program.cff, _ = parseCFF(data)
+		}
+	}
+	return program
+}
+
+// #6 proses: font sederhana (Type1, MMType1, TrueType), kode 1 byte dipetakan ke glyph lewat encoding, Differences, dan cmap
+func (d *pdfDocument) loadSimpleFont(font *pdfFont, dict pdfDict) {
+	descriptor := d.dict(dict["FontDescriptor"])
+	program := d.fontProgram(descriptor)
+	flags := int(d.number(descriptor["Flags"], 0))
+	symbolic := flags&4 != 0
+
+	// #6a proses: encoding dasar dari nama /Encoding atau /BaseEncoding, Differences menimpa kode tertentu
+	var names [256]string
+	hasBase := false
+	hasDifferences := false
+	switch encoding := d.resolve(dict["Encoding"]).(type) {
+	case pdfName:
+		names, hasBase = pdfBaseEncoding(encoding)
+	case pdfDict:
+		names, hasBase = pdfBaseEncoding(d.name(encoding["BaseEncoding"]))
+		code := 0
+		for _, item := range d.array(encoding["Differences"]) {
+			switch value := d.resolve(item).(type) {
+			case float64:
+				code = int(value)
+			case pdfName:
+				if code >= 0 && code < 256 {
+					names[code] = string(value)
+					hasDifferences = true
+				}
+				code++
+			}
+		}
+	}
+	if !hasBase && !hasDifferences && !symbolic && program.type1 == nil && program.cff == nil {
+		names = pdfStandardEncoding
+	}
+
+	// #6b proses: lebar dari /Widths mulai /FirstChar, kode di luar array memakai /MissingWidth
+	first := int(d.number(dict["FirstChar"], 0))
+	for i, w := range d.array(dict["Widths"]) {
+		font.widths[first+i] = d.number(w, 0)
+	}
+	if missing, ok := d.resolve(descriptor["MissingWidth"]).(float64); ok {
+		font.defaultWidth = missing
+	}
+
+	switch {
+	case program.type1 != nil:
+		t1 := program.type1
+		nameOf := func(code int) string {
+			if code >= 0 && code < 256 && names[code] != "" {
+				return names[code]
+			}
+			return t1.encoding[code]
+		}
+		font.embedded = true
+		font.glyph = func(code, _ int) *pdfPath { return t1.outline(nameOf(code)) }
+		font.advance = func(code, _ int) (float64, bool) { return t1.advance(nameOf(code)) }
+	case program.cff != nil:
+		cff := program.cff
+		gidOf := func(code int) int {
+			if code >= 0 && code < 256 && names[code] != "" {
+				if gid, ok := cff.names[names[code]]; ok {
+					return gid
+				}
+			}
+			if cff.isCID {
+				return code
+			}
+			return cff.encoding[code]
+		}
+		font.embedded = true
+		font.glyph = func(code, _ int) *pdfPath { return cff.outline(gidOf(code)) }
+		font.advance = func(code, _ int) (float64, bool) { return cff.advance(gidOf(code)) }
+	case program.trueType != nil:
+		tt := program.trueType
+		gidOf := func(code int) int {
+			return trueTypeSimpleGID(tt, names, code, symbolic && !hasDifferences)
+		}
+		font.embedded = true
+		font.glyph = func(code, _ int) *pdfPath { return tt.outline(gidOf(code)) }
+		font.advance = func(code, _ int) (float64, bool) { return tt.advance(gidOf(code)) }
+	}
+}
+
+// #6c proses: glyph TrueType untuk font sederhana. Font non-simbolik dicari lewat unicode nama glyph di cmap (3,1), font simbolik lewat cmap (3,0) dengan prefix 0xF000 atau cmap Mac (1,0). Font tanpa cmap memakai kode sebagai GID
+func trueTypeSimpleGID(tt *pdfTrueType, names [256]string, code int, symbolic bool) int {
+	if len(tt.cmaps) == 0 {
+		return code
+	}
+	if !symbolic && code >= 0 && code < 256 && names[code] != "" {
+		if r, ok := glyphNameUnicode(names[code]); ok {
+			if gid, ok := tt.lookup(3, 1, int(r)); ok {
+				return gid
+			}
+		}
+	}
+	for _, candidate := range []struct{ platform, encoding, code int }{
+		{3, 0, code}, {3, 0, 0xF000 + code}, {1, 0, code}, {3, 1, code}, {0, 3, code},
+	} {
+		if gid, ok := tt.lookup(candidate.platform, candidate.encoding, candidate.code); ok {
+			return gid
+		}
+	}
+	return 0
+}
+
+// #7 proses: font komposit Type0, kode dipetakan ke CID lewat CMap /Encoding lalu ke glyph lewat CIDFont di /DescendantFonts
+func (d *pdfDocument) loadCompositeFont(font *pdfFont, dict pdfDict) {
+	font.composite = true
+	font.defaultWidth = 1000
+
+	switch encoding := d.resolve(dict["Encoding"]).(type) {
+	case pdfName:
+		// CMap predefined selain Identity (misalnya CMap CJK) diperlakukan seperti Identity 2 byte
+		font.identity = true
+		font.vertical = strings.HasSuffix(string(encoding), "-V")
+	case *pdfStream:
+		d.parseCMap(font, encoding, 0)
+		font.vertical = d.number(encoding.dict["WMode"], 0) == 1
+	}
+
+	descendants := d.array(dict["DescendantFonts"])
+	if len(descendants) == 0 {
+		return
+	}
+	cidFont := d.dict(descendants[0])
+	if dw, ok := d.resolve(cidFont["DW"]).(float64); ok {
+		font.defaultWidth = dw
+	}
+
+	// #7a proses: /W berisi "c [w1 w2 ...]" atau "cFirst cLast w"
+	w := d.array(cidFont["W"])
+	for i := 0; i < len(w); {
+		start, ok := d.resolve(w[i]).(float64)
+		if !ok || i+1 >= len(w) {
+			break
+		}
+		if list, isList := d.resolve(w[i+1]).(pdfArray); isList {
+			for j, item := range list {
+				font.widths[int(start)+j] = d.number(item, font.defaultWidth)
+			}
+			i += 2
+			continue
+		}
+		if i+2 >= len(w) {
+			break
+		}
+		end := d.number(w[i+1], start)
+		width := d.number(w[i+2], font.defaultWidth)
+		for cid := int(start); cid <= int(end) && cid-int(start) < 65536; cid++ {
+			font.widths[cid] = width
+		}
+		i += 3
+	}
+
+	program := d.fontProgram(d.dict(cidFont["FontDescriptor"]))
+	switch {
+	case program.trueType != nil:
+		tt := program.trueType
+		var cidToGID []byte
+		if stream, ok := d.resolve(cidFont["CIDToGIDMap"]).(*pdfStream); ok {
+			cidToGID, _, _ = d.decodeStream(stream)
+		}
+		gidOf := func(cid int) int {
+			if cidToGID != nil {
+				if cid*2+2 <= len(cidToGID) {
+					return int(cidToGID[cid*2])<<8 | int(cidToGID[cid*2+1])
+				}
+				return 0
+			}
+			return cid
+		}
+		font.embedded = true
+		font.glyph = func(_, cid int) *pdfPath { return tt.outline(gidOf(cid)) }
+	case program.cff != nil:
+		cff := program.cff
+		gidOf := func(cid int) int {
+			if cff.isCID {
+				return cff.cidToGID[cid]
+			}
+			return cid
+		}
+		font.embedded = true
+		font.glyph = func(_, cid int) *pdfPath { return cff.outline(gidOf(cid)) }
+	}
+}
+
+// #7b proses: parse CMap ter-embed, hanya codespacerange, cidrange, cidchar, dan usecmap Identity yang dibaca
+func (d *pdfDocument) parseCMap(font *pdfFont, stream *pdfStream, depth int) {
+	data, _, err := d.decodeStream(stream)
+	if err != nil || depth > 4 {
+		font.identity = true
+		return
+	}
+	if parent, ok := d.resolve(stream.dict["UseCMap"]).(*pdfStream); ok {
+		d.parseCMap(font, parent, depth+1)
+	}
+
+	lexer := &pdfLexer{data: data}
+	operands := []interface{}{}
+	for {
+		tok, ok := lexer.object(0)
+		if !ok {
+			return
+		}
+		keyword, isKeyword := tok.(pdfKeyword)
+		if !isKeyword {
+			operands = append(operands, tok)
+			continue
+		}
+		switch keyword {
+		case "begincodespacerange", "begincidrange", "begincidchar":
+			operands = operands[:0]
+			continue
+		case "endcodespacerange":
+			for i := 0; i+1 < len(operands); i += 2 {
+				low, okLow := operands[i].(pdfString)
+				high, okHigh := operands[i+1].(pdfString)
+				if okLow && okHigh && len(low) == len(high) && len(low) > 0 {
+					font.codespace = append(font.codespace, pdfCodespaceRange{low: low, high: high})
+				}
+			}
+		case "endcidrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				low, okLow := operands[i].(pdfString)
+				high, okHigh := operands[i+1].(pdfString)
+				cid, okCID := operands[i+2].(float64)
+				if okLow && okHigh && okCID {
+					font.cidRanges = append(font.cidRanges, pdfCIDRange{low: low, high: high, cid: int(cid)})
+				}
+			}
+		case "endcidchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				code, okCode := operands[i].(pdfString)
+				cid, okCID := operands[i+1].(float64)
+				if okCode && okCID {
+					font.cidRanges = append(font.cidRanges, pdfCIDRange{low: code, high: code, cid: int(cid)})
+				}
+			}
+		case "usecmap":
+			if len(operands) > 0 {
+				if name, ok := operands[len(operands)-1].(pdfName); ok && strings.HasPrefix(string(name), "Identity") {
+					font.identity = true
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+}
+
+// #8 proses: font Type3, glyph adalah content stream di /CharProcs yang dipilih lewat nama di Differences
+func (d *pdfDocument) loadType3Font(font *pdfFont, dict pdfDict) {
+	t3 := &pdfType3Font{
+		matrix:    pdfMatrix{0.001, 0, 0, 0.001, 0, 0},
+		procs:     map[int]*pdfStream{},
+		resources: d.dict(dict["Resources"]),
+	}
+	if m, ok := matrixFromNumbers(d.numbers(dict["FontMatrix"])); ok {
+		t3.matrix = m
+	}
+	procs := d.dict(dict["CharProcs"])
+	code := 0
+	for _, item := range d.array(d.dict(dict["Encoding"])["Differences"]) {
+		switch value := d.resolve(item).(type) {
+		case float64:
+			code = int(value)
+		case pdfName:
+			if stream, ok := d.resolve(procs[value]).(*pdfStream); ok {
+				t3.procs[code] = stream
+			}
+			code++
+		}
+	}
+
+	first := int(d.number(dict["FirstChar"], 0))
+	for i, w := range d.array(dict["Widths"]) {
+		font.widths[first+i] = d.number(w, 0)
+	}
+	font.defaultWidth = 0
+	font.embedded = true
+	font.type3 = t3
+}
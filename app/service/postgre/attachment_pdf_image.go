@@ -0,0 +1,186 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk bytes, jpeg, dan math
+import (
+	"bytes"
+	"image/jpeg"
+	"math"
+)
+
+// #2 proses: gambar PDF yang siap di-sample per piksel. Stencil mask (ImageMask) hanya berisi coverage dan diwarnai dengan warna fill aktif
+type pdfImage struct {
+	width   int
+	height  int
+	stencil bool
+	sample  func(x, y int) (pdfRGB, float64)
+}
+
+// #2a proses: warna pengganti untuk gambar dengan filter yang tidak bisa didecode di sini (JPX, CCITT, JBIG2), supaya posisi gambar tetap terlihat di preview
+var pdfPlaceholderGray = pdfRGB{0.85, 0.85, 0.85}
+
+// #3 proses: decode image XObject atau inline image, forceStencil dipakai untuk /Mask berupa stream. Data mentah dipakai langsung tanpa disalin ke bitmap baru supaya gambar besar tidak menggandakan memori
+func (d *pdfDocument) loadImage(dict pdfDict, stream *pdfStream, resources pdfDict, forceStencil bool, depth int) *pdfImage {
+	if depth > 2 {
+		return nil
+	}
+	get := func(long, short pdfName) interface{} {
+		if value, ok := dict[long]; ok {
+			return value
+		}
+		return dict[short]
+	}
+	width := int(d.number(get("Width", "W"), 0))
+	height := int(d.number(get("Height", "H"), 0))
+	if width <= 0 || height <= 0 || width*height > attachmentPreviewMaxPixels {
+		return nil
+	}
+	imageMask, _ := d.resolve(get("ImageMask", "IM")).(bool)
+	imageMask = imageMask || forceStencil
+	bpc := int(d.number(get("BitsPerComponent", "BPC"), 8))
+	if imageMask {
+		bpc = 1
+	}
+
+	data, filter, err := d.decodeStream(stream)
+	if err != nil {
+		return nil
+	}
+
+	img := &pdfImage{width: width, height: height, stencil: imageMask}
+	switch filter {
+	case "DCTDecode", "DCT":
+		if !d.decodeJPEGImage(img, data) {
+			return nil
+		}
+	case "":
+		if !d.decodeRawImage(img, dict, get, data, bpc, resources) {
+			return nil
+		}
+	default:
+		img.sample = func(int, int) (pdfRGB, float64) { return pdfPlaceholderGray, 1 }
+	}
+
+	// #3a proses: /SMask memberi alpha per piksel, /Mask berupa stream adalah stencil dan berupa array adalah color key (ditangani di decodeRawImage)
+	if smask, ok := d.resolve(dict["SMask"]).(*pdfStream); ok && !imageMask {
+		if alpha := d.loadImage(smask.dict, smask, resources, false, depth+1); alpha != nil {
+			img.sample = withImageAlpha(img, alpha, false)
+		}
+	} else if mask, ok := d.resolve(dict["Mask"]).(*pdfStream); ok && !imageMask {
+		if alpha := d.loadImage(mask.dict, mask, resources, true, depth+1); alpha != nil {
+			img.sample = withImageAlpha(img, alpha, true)
+		}
+	}
+	return img
+}
+
+// #3b proses: gabungkan alpha dari gambar mask yang ukurannya bisa berbeda, koordinat diskalakan proporsional
+func withImageAlpha(img *pdfImage, mask *pdfImage, stencil bool) func(x, y int) (pdfRGB, float64) {
+	base := img.sample
+	return func(x, y int) (pdfRGB, float64) {
+		col, alpha := base(x, y)
+		mx := min(mask.width-1, x*mask.width/img.width)
+		my := min(mask.height-1, y*mask.height/img.height)
+		maskColor, maskAlpha := mask.sample(mx, my)
+		if stencil {
+			return col, alpha * maskAlpha
+		}
+		return col, alpha * maskColor.r * maskAlpha
+	}
+}
+
+// #4 proses: decode JPEG dengan pengecekan dimensi dulu, JPEG CMYK (termasuk inversi Adobe) sudah ditangani image/jpeg sehingga cukup dikonversi lewat color model standar
+func (d *pdfDocument) decodeJPEGImage(img *pdfImage, data []byte) bool {
+	config, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	if err != nil || config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > attachmentPreviewMaxPixels {
+		return false
+	}
+	if err := d.reserveDecoded(config.Width * config.Height * 3); err != nil {
+		return false
+	}
+	decoded, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return false
+	}
+	bounds := decoded.Bounds()
+	img.width, img.height = bounds.Dx(), bounds.Dy()
+
+	img.sample = func(x, y int) (pdfRGB, float64) {
+		r, g, b, _ := decoded.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+		return pdfRGB{float64(r) / 0xffff, float64(g) / 0xffff, float64(b) / 0xffff}, 1
+	}
+	return true
+}
+
+// #5 proses: sample gambar mentah per komponen dengan BitsPerComponent 1-16 dan array /Decode, lalu konversi lewat color space
+func (d *pdfDocument) decodeRawImage(img *pdfImage, dict pdfDict, get func(long, short pdfName) interface{}, data []byte, bpc int, resources pdfDict) bool {
+	if bpc != 1 && bpc != 2 && bpc != 4 && bpc != 8 && bpc != 16 {
+		return false
+	}
+	cs := pdfGraySpace
+	if !img.stencil {
+		cs = d.colorSpace(get("ColorSpace", "CS"), resources, 0)
+	}
+	components := max(1, cs.components)
+	rowBytes := (img.width*components*bpc + 7) / 8
+	maxValue := math.Pow(2, float64(bpc)) - 1
+
+	decode := d.numbers(get("Decode", "D"))
+	if len(decode) < 2*components {
+		decode = make([]float64, 0, 2*components)
+		for i := 0; i < components; i++ {
+			if cs.kind == "indexed" {
+				decode = append(decode, 0, maxValue)
+			} else {
+				decode = append(decode, 0, 1)
+			}
+		}
+	}
+
+	raw := func(x, y, component int) int {
+		bit := y*rowBytes*8 + (x*components+component)*bpc
+		index := bit / 8
+		switch bpc {
+		case 8:
+			if index < len(data) {
+				return int(data[index])
+			}
+		case 16:
+			if index+1 < len(data) {
+				return int(data[index])<<8 | int(data[index+1])
+			}
+		default:
+			if index < len(data) {
+				shift := 8 - bpc - bit%8
+				return int(data[index]>>uint(shift)) & (1<<bpc - 1)
+			}
+		}
+		return 0
+	}
+
+	// #5a proses: /Mask array berisi rentang nilai mentah per komponen, piksel yang semua komponennya di dalam rentang dianggap transparan
+	colorKey := d.numbers(dict["Mask"])
+	if len(colorKey) < 2*components {
+		colorKey = nil
+	}
+
+	values := make([]float64, components)
+	img.sample = func(x, y int) (pdfRGB, float64) {
+		masked := colorKey != nil
+		for c := 0; c < components; c++ {
+			v := raw(x, y, c)
+			if masked && (float64(v) < colorKey[2*c] || float64(v) > colorKey[2*c+1]) {
+				masked = false
+			}
+			values[c] = decode[2*c] + float64(v)*(decode[2*c+1]-decode[2*c])/maxValue
+		}
+		if img.stencil {
+			// sample 0 berarti dicat untuk Decode [0 1]
+			return pdfRGB{}, 1 - values[0]
+		}
+		if masked {
+			return pdfRGB{}, 0
+		}
+		return cs.toRGB(values), 1
+	}
+	return true
+}
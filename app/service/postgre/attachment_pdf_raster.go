@@ -0,0 +1,740 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk image, math, dan sort
+import (
+	"image"
+	"math"
+	"sort"
+)
+
+// #2 proses: batas rasterizer, jumlah sub-scanline per piksel untuk anti-aliasing vertikal, jumlah titik maksimal satu path setelah kurva diratakan, dan bobot anggaran kerja tiap potongan stroke
+const (
+	pdfRasterSubsamples = 4
+	pdfMaxPathPoints    = 200000
+	pdfMaxCurveSegments = 64
+	pdfStrokePieceWork  = 32
+)
+
+// #3 proses: matriks transformasi PDF [a b c d e f], titik (x, y) dipetakan ke (a*x + c*y + e, b*x + d*y + f)
+type pdfMatrix [6]float64
+
+var pdfIdentity = pdfMatrix{1, 0, 0, 1, 0, 0}
+
+// #3a proses: m.multiply(n) berarti transformasi m dijalankan dulu lalu n, sama seperti urutan operator cm di PDF
+func (m pdfMatrix) multiply(n pdfMatrix) pdfMatrix {
+	return pdfMatrix{
+		m[0]*n[0] + m[1]*n[2],
+		m[0]*n[1] + m[1]*n[3],
+		m[2]*n[0] + m[3]*n[2],
+		m[2]*n[1] + m[3]*n[3],
+		m[4]*n[0] + m[5]*n[2] + n[4],
+		m[4]*n[1] + m[5]*n[3] + n[5],
+	}
+}
+
+func (m pdfMatrix) apply(p pdfPoint) pdfPoint {
+	return pdfPoint{m[0]*p.x + m[2]*p.y + m[4], m[1]*p.x + m[3]*p.y + m[5]}
+}
+
+// #3b proses: invers matriks, ok false jika matriks singular
+func (m pdfMatrix) invert() (pdfMatrix, bool) {
+	det := m[0]*m[3] - m[1]*m[2]
+	if det == 0 || math.IsNaN(det) || math.IsInf(det, 0) {
+		return pdfMatrix{}, false
+	}
+	return pdfMatrix{
+		m[3] / det,
+		-m[1] / det,
+		-m[2] / det,
+		m[0] / det,
+		(m[2]*m[5] - m[3]*m[4]) / det,
+		(m[1]*m[4] - m[0]*m[5]) / det,
+	}, true
+}
+
+// #3c proses: faktor skala rata-rata matriks, dipakai untuk ketebalan garis minimal dan jumlah segmen kurva
+func (m pdfMatrix) scale() float64 {
+	return math.Sqrt(math.Abs(m[0]*m[3] - m[1]*m[2]))
+}
+
+func matrixFromNumbers(values []float64) (pdfMatrix, bool) {
+	if len(values) != 6 {
+		return pdfMatrix{}, false
+	}
+	var m pdfMatrix
+	copy(m[:], values)
+	for _, v := range m {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return pdfMatrix{}, false
+		}
+	}
+	return m, true
+}
+
+type pdfPoint struct {
+	x, y float64
+}
+
+// #4 proses: path PDF dengan segmen move, line, cubic, dan close. Kurva quadratic dari font TrueType dikonversi ke cubic
+type pdfPathSegment struct {
+	op  byte
+	pts [3]pdfPoint
+}
+
+type pdfPath struct {
+	segments []pdfPathSegment
+	current  pdfPoint
+	start    pdfPoint
+	open     bool
+}
+
+func (p *pdfPath) moveTo(x, y float64) {
+	p.segments = append(p.segments, pdfPathSegment{op: 'M', pts: [3]pdfPoint{{x, y}}})
+	p.current = pdfPoint{x, y}
+	p.start = p.current
+	p.open = true
+}
+
+func (p *pdfPath) lineTo(x, y float64) {
+	if !p.open {
+		p.moveTo(p.current.x, p.current.y)
+	}
+	p.segments = append(p.segments, pdfPathSegment{op: 'L', pts: [3]pdfPoint{{x, y}}})
+	p.current = pdfPoint{x, y}
+}
+
+func (p *pdfPath) curveTo(x1, y1, x2, y2, x3, y3 float64) {
+	if !p.open {
+		p.moveTo(p.current.x, p.current.y)
+	}
+	p.segments = append(p.segments, pdfPathSegment{op: 'C', pts: [3]pdfPoint{{x1, y1}, {x2, y2}, {x3, y3}}})
+	p.current = pdfPoint{x3, y3}
+}
+
+func (p *pdfPath) quadTo(x1, y1, x2, y2 float64) {
+	c := p.current
+	p.curveTo(c.x+2.0/3.0*(x1-c.x), c.y+2.0/3.0*(y1-c.y), x2+2.0/3.0*(x1-x2), y2+2.0/3.0*(y1-y2), x2, y2)
+}
+
+func (p *pdfPath) closePath() {
+	if p.open {
+		p.segments = append(p.segments, pdfPathSegment{op: 'Z'})
+		p.current = p.start
+		p.open = false
+	}
+}
+
+func (p *pdfPath) rect(x, y, w, h float64) {
+	p.moveTo(x, y)
+	p.lineTo(x+w, y)
+	p.lineTo(x+w, y+h)
+	p.lineTo(x, y+h)
+	p.closePath()
+}
+
+// #4a proses: tambahkan path lain yang sudah ditransformasi, dipakai untuk outline glyph ke path clip teks
+func (p *pdfPath) appendTransformed(other *pdfPath, m pdfMatrix) {
+	for _, segment := range other.segments {
+		for i := range segment.pts {
+			segment.pts[i] = m.apply(segment.pts[i])
+		}
+		p.segments = append(p.segments, segment)
+	}
+}
+
+// #5 proses: subpath hasil perataan kurva, closed menandai subpath yang ditutup operator h atau close
+type pdfPolyline struct {
+	points []pdfPoint
+	closed bool
+}
+
+// #5a proses: ratakan kurva di ruang user, jumlah segmen dihitung dari panjang poligon kontrol setelah ditransformasi ke device supaya kurva kecil tidak dipecah berlebihan
+func (p *pdfPath) flatten(m pdfMatrix) ([]pdfPolyline, bool) {
+	var lines []pdfPolyline
+	var current *pdfPolyline
+	total := 0
+	last := pdfPoint{}
+	for _, segment := range p.segments {
+		switch segment.op {
+		case 'M':
+			lines = append(lines, pdfPolyline{points: []pdfPoint{segment.pts[0]}})
+			current = &lines[len(lines)-1]
+			last = segment.pts[0]
+			total++
+		case 'L':
+			if current == nil {
+				continue
+			}
+			current.points = append(current.points, segment.pts[0])
+			last = segment.pts[0]
+			total++
+		case 'C':
+			if current == nil {
+				continue
+			}
+			p0, p1, p2, p3 := last, segment.pts[0], segment.pts[1], segment.pts[2]
+			d0, d1, d2, d3 := m.apply(p0), m.apply(p1), m.apply(p2), m.apply(p3)
+			length := pointDistance(d0, d1) + pointDistance(d1, d2) + pointDistance(d2, d3)
+			n := min(pdfMaxCurveSegments, max(1, int(math.Ceil(math.Sqrt(length*2)))))
+			for i := 1; i <= n; i++ {
+				t := float64(i) / float64(n)
+				u := 1 - t
+				current.points = append(current.points, pdfPoint{
+					u*u*u*p0.x + 3*u*u*t*p1.x + 3*u*t*t*p2.x + t*t*t*p3.x,
+					u*u*u*p0.y + 3*u*u*t*p1.y + 3*u*t*t*p2.y + t*t*t*p3.y,
+				})
+			}
+			last = p3
+			total += n
+		case 'Z':
+			if current == nil {
+				continue
+			}
+			current.closed = true
+			last = current.points[0]
+			// #5b proses: segmen setelah close tanpa moveto dimulai lagi dari titik awal subpath
+			lines = append(lines, pdfPolyline{points: []pdfPoint{last}})
+			current = &lines[len(lines)-1]
+		}
+		if total > pdfMaxPathPoints {
+			return nil, false
+		}
+	}
+	return lines, true
+}
+
+func pointDistance(a, b pdfPoint) float64 {
+	return math.Hypot(a.x-b.x, a.y-b.y)
+}
+
+// #6 proses: kanvas tujuan rasterisasi dan sisa anggaran kerja satu halaman. Kerja dihitung per perpotongan edge dengan sub-scanline dan per piksel yang dicat, sehingga PDF berisi ribuan path raksasa berhenti dengan preview parsial alih-alih berjalan terlalu lama
+type pdfRasterTarget struct {
+	width  int
+	height int
+	budget int64
+}
+
+func (t *pdfRasterTarget) spend(work int) bool {
+	t.budget -= int64(work)
+	return t.budget >= 0
+}
+
+func (t *pdfRasterTarget) exhausted() bool {
+	return t.budget < 0
+}
+
+// #6a proses: coverage hasil rasterisasi path, nilai 0 sampai 1 per piksel di dalam bounding box
+type pdfCoverage struct {
+	bounds image.Rectangle
+	data   []float32
+}
+
+func (c *pdfCoverage) at(x, y int) float32 {
+	if !(image.Point{x, y}).In(c.bounds) {
+		return 0
+	}
+	return c.data[(y-c.bounds.Min.Y)*c.bounds.Dx()+x-c.bounds.Min.X]
+}
+
+type pdfEdge struct {
+	x0, y0, x1, y1 float64
+	dir            int
+}
+
+// #6b proses: rasterisasi poligon device dengan aturan nonzero atau even-odd, tiap baris dibagi beberapa sub-scanline dan span di tiap sub-scanline dihitung dengan cakupan horizontal pecahan
+func rasterizePolygons(polygons [][]pdfPoint, evenOdd bool, target *pdfRasterTarget) *pdfCoverage {
+	width, height := target.width, target.height
+	edges := []pdfEdge{}
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	for _, polygon := range polygons {
+		for i := range polygon {
+			a, b := polygon[i], polygon[(i+1)%len(polygon)]
+			if math.IsNaN(a.x) || math.IsNaN(a.y) || math.IsInf(a.x, 0) || math.IsInf(a.y, 0) {
+				return nil
+			}
+			minX, maxX = math.Min(minX, a.x), math.Max(maxX, a.x)
+			minY, maxY = math.Min(minY, a.y), math.Max(maxY, a.y)
+			if a.y == b.y {
+				continue
+			}
+			if a.y < b.y {
+				edges = append(edges, pdfEdge{a.x, a.y, b.x, b.y, 1})
+			} else {
+				edges = append(edges, pdfEdge{b.x, b.y, a.x, a.y, -1})
+			}
+		}
+	}
+
+	// #6c proses: koordinat dibatasi dulu sebelum diubah ke int supaya nilai raksasa tidak overflow
+	clampCoord := func(v float64) int {
+		return int(math.Max(-1, math.Min(float64(max(width, height))+1, v)))
+	}
+	bounds := image.Rect(clampCoord(math.Floor(minX)), clampCoord(math.Floor(minY)), clampCoord(math.Ceil(maxX))+1, clampCoord(math.Ceil(maxY))+1).
+		Intersect(image.Rect(0, 0, width, height))
+	if len(edges) == 0 || bounds.Empty() {
+		return nil
+	}
+
+	// #6d proses: biaya rasterisasi adalah jumlah perpotongan edge dengan sub-scanline di dalam bounds ditambah luas bounds
+	work := len(edges) + bounds.Dx()*bounds.Dy()
+	for _, edge := range edges {
+		rows := math.Min(edge.y1, float64(bounds.Max.Y)) - math.Max(edge.y0, float64(bounds.Min.Y))
+		if rows > 0 {
+			work += int(rows * pdfRasterSubsamples)
+		}
+	}
+	if !target.spend(work) {
+		return nil
+	}
+
+	sort.Slice(edges, func(i, j int) bool { return edges[i].y0 < edges[j].y0 })
+	coverage := &pdfCoverage{bounds: bounds, data: make([]float32, bounds.Dx()*bounds.Dy())}
+	active := []pdfEdge{}
+	next := 0
+	type crossing struct {
+		x   float64
+		dir int
+	}
+	crossings := []crossing{}
+	weight := float32(1) / pdfRasterSubsamples
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		row := coverage.data[(y-bounds.Min.Y)*bounds.Dx() : (y-bounds.Min.Y+1)*bounds.Dx()]
+		for s := 0; s < pdfRasterSubsamples; s++ {
+			sy := float64(y) + (float64(s)+0.5)/pdfRasterSubsamples
+
+			// #6e proses: edge aktif adalah edge dengan y0 <= sy < y1, edge yang sudah lewat dibuang
+			for next < len(edges) && edges[next].y0 <= sy {
+				active = append(active, edges[next])
+				next++
+			}
+			kept := active[:0]
+			crossings = crossings[:0]
+			for _, edge := range active {
+				if edge.y1 <= sy {
+					continue
+				}
+				kept = append(kept, edge)
+				t := (sy - edge.y0) / (edge.y1 - edge.y0)
+				crossings = append(crossings, crossing{edge.x0 + t*(edge.x1-edge.x0), edge.dir})
+			}
+			active = kept
+			sort.Slice(crossings, func(i, j int) bool { return crossings[i].x < crossings[j].x })
+
+			winding := 0
+			for i, c := range crossings {
+				if evenOdd {
+					winding ^= 1
+				} else {
+					winding += c.dir
+				}
+				if winding != 0 && i+1 < len(crossings) {
+					addCoverageSpan(row, c.x-float64(bounds.Min.X), crossings[i+1].x-float64(bounds.Min.X), weight)
+				}
+			}
+		}
+	}
+	return coverage
+}
+
+// #6f proses: tambahkan span [xa, xb) ke baris coverage, piksel di ujung span mendapat cakupan sesuai porsi yang tertutup
+func addCoverageSpan(row []float32, xa, xb float64, weight float32) {
+	xa = math.Max(0, xa)
+	xb = math.Min(float64(len(row)), xb)
+	if xb <= xa {
+		return
+	}
+	ia, ib := int(xa), int(xb)
+	if ia == ib {
+		row[ia] += float32(xb-xa) * weight
+		return
+	}
+	row[ia] += float32(float64(ia+1)-xa) * weight
+	for i := ia + 1; i < ib; i++ {
+		row[i] += weight
+	}
+	if ib < len(row) {
+		row[ib] += float32(xb-float64(ib)) * weight
+	}
+}
+
+// #7 proses: rasterisasi area fill path, subpath terbuka ditutup otomatis sesuai aturan fill PDF
+func fillCoverage(path *pdfPath, m pdfMatrix, evenOdd bool, target *pdfRasterTarget) *pdfCoverage {
+	lines, ok := path.flatten(m)
+	if !ok {
+		return nil
+	}
+	polygons := make([][]pdfPoint, 0, len(lines))
+	for _, line := range lines {
+		if len(line.points) < 3 {
+			continue
+		}
+		polygon := make([]pdfPoint, len(line.points))
+		for i, point := range line.points {
+			polygon[i] = m.apply(point)
+		}
+		polygons = append(polygons, polygon)
+	}
+	return rasterizePolygons(polygons, evenOdd, target)
+}
+
+// #8 proses: parameter stroke dari graphics state
+type pdfStrokeStyle struct {
+	width      float64
+	cap        int
+	join       int
+	miterLimit float64
+	dash       []float64
+	dashPhase  float64
+}
+
+// #8a proses: rasterisasi stroke dengan membangun outline di ruang user (quad tiap segmen, poligon join dan cap) lalu ditransformasi ke device, sehingga CTM tidak seragam tetap menghasilkan garis yang benar. Semua poligon dibuat searah supaya gabungannya dengan aturan nonzero tidak berlubang
+func strokeCoverage(path *pdfPath, m pdfMatrix, style pdfStrokeStyle, target *pdfRasterTarget) *pdfCoverage {
+	lines, ok := path.flatten(m)
+	if !ok {
+		return nil
+	}
+	if len(style.dash) > 0 {
+		lines = dashPolylines(lines, style.dash, style.dashPhase)
+	}
+	// #8a1 proses: membangun outline tiap potongan dash jauh lebih mahal daripada satu perpotongan scanline, sehingga ikut dibebankan ke anggaran
+	if !target.spend(len(lines) * pdfStrokePieceWork) {
+		return nil
+	}
+
+	// #8b proses: garis lebar 0 atau sangat tipis tetap digambar setebal satu piksel device
+	half := style.width / 2
+	if scale := m.scale(); scale > 0 && style.width*scale < 1 {
+		half = 0.5 / scale
+	}
+
+	polygons := [][]pdfPoint{}
+	add := func(polygon []pdfPoint) {
+		if polygonArea(polygon) < 0 {
+			for i, j := 0, len(polygon)-1; i < j; i, j = i+1, j-1 {
+				polygon[i], polygon[j] = polygon[j], polygon[i]
+			}
+		}
+		polygons = append(polygons, polygon)
+	}
+
+	for _, line := range lines {
+		points := dedupePoints(line.points)
+		if line.closed && len(points) > 1 && points[0] == points[len(points)-1] {
+			points = points[:len(points)-1]
+		}
+		if len(points) == 1 {
+			// #8c proses: subpath sepanjang nol hanya digambar untuk cap round dan square
+			switch style.cap {
+			case 1:
+				add(circlePolygon(points[0], half))
+			case 2:
+				p := points[0]
+				add([]pdfPoint{{p.x - half, p.y - half}, {p.x + half, p.y - half}, {p.x + half, p.y + half}, {p.x - half, p.y + half}})
+			}
+			continue
+		}
+
+		segmentCount := len(points) - 1
+		if line.closed && len(points) > 2 {
+			segmentCount = len(points)
+		}
+		for i := 0; i < segmentCount; i++ {
+			a, b := points[i], points[(i+1)%len(points)]
+			nx, ny := segmentNormal(a, b, half)
+			add([]pdfPoint{{a.x + nx, a.y + ny}, {b.x + nx, b.y + ny}, {b.x - nx, b.y - ny}, {a.x - nx, a.y - ny}})
+		}
+
+		// #8d proses: join di tiap titik tengah, subpath tertutup juga mendapat join di titik awal
+		for i := 0; i < len(points); i++ {
+			if !line.closed || len(points) <= 2 {
+				if i == 0 || i == len(points)-1 {
+					continue
+				}
+			}
+			prev := points[(i-1+len(points))%len(points)]
+			current := points[i]
+			next := points[(i+1)%len(points)]
+			if join := joinPolygon(prev, current, next, half, style); join != nil {
+				add(join)
+			}
+		}
+
+		if !line.closed || len(points) <= 2 {
+			addCap := func(end, toward pdfPoint) {
+				dx, dy := end.x-toward.x, end.y-toward.y
+				length := math.Hypot(dx, dy)
+				if length == 0 {
+					return
+				}
+				ux, uy := dx/length*half, dy/length*half
+				switch style.cap {
+				case 1:
+					add(circlePolygon(end, half))
+				case 2:
+					add([]pdfPoint{{end.x - uy, end.y + ux}, {end.x - uy + ux, end.y + ux + uy}, {end.x + uy + ux, end.y - ux + uy}, {end.x + uy, end.y - ux}})
+				}
+			}
+			addCap(points[0], points[1])
+			addCap(points[len(points)-1], points[len(points)-2])
+		}
+		if len(polygons) > pdfMaxPathPoints/4 {
+			return nil
+		}
+	}
+
+	for _, polygon := range polygons {
+		for i := range polygon {
+			polygon[i] = m.apply(polygon[i])
+		}
+	}
+	return rasterizePolygons(polygons, false, target)
+}
+
+func dedupePoints(points []pdfPoint) []pdfPoint {
+	out := make([]pdfPoint, 0, len(points))
+	for _, point := range points {
+		if len(out) == 0 || out[len(out)-1] != point {
+			out = append(out, point)
+		}
+	}
+	return out
+}
+
+func segmentNormal(a, b pdfPoint, half float64) (float64, float64) {
+	dx, dy := b.x-a.x, b.y-a.y
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return 0, 0
+	}
+	return -dy / length * half, dx / length * half
+}
+
+// #8e proses: poligon join di sisi luar sudut, miter dipakai selama rasio miter di bawah batas dan selain itu jatuh ke bevel
+func joinPolygon(prev, current, next pdfPoint, half float64, style pdfStrokeStyle) []pdfPoint {
+	n1x, n1y := segmentNormal(prev, current, half)
+	n2x, n2y := segmentNormal(current, next, half)
+	cross := (current.x-prev.x)*(next.y-current.y) - (current.y-prev.y)*(next.x-current.x)
+	if cross == 0 {
+		return nil
+	}
+	if style.join == 1 {
+		return circlePolygon(current, half)
+	}
+
+	// sisi luar sudut berlawanan dengan arah belokan
+	sign := -1.0
+	if cross < 0 {
+		sign = 1
+	}
+	a := pdfPoint{current.x + sign*n1x, current.y + sign*n1y}
+	b := pdfPoint{current.x + sign*n2x, current.y + sign*n2y}
+	if style.join == 0 {
+		d1x, d1y := current.x-prev.x, current.y-prev.y
+		d2x, d2y := next.x-current.x, next.y-current.y
+		cos := (d1x*d2x + d1y*d2y) / (math.Hypot(d1x, d1y) * math.Hypot(d2x, d2y))
+		theta := math.Acos(math.Max(-1, math.Min(1, -cos)))
+		limit := style.miterLimit
+		if limit < 1 {
+			limit = 10
+		}
+		if s := math.Sin(theta / 2); s > 0 && 1/s <= limit {
+			// titik miter adalah perpotongan dua tepi luar
+			denom := d1x*d2y - d1y*d2x
+			t := ((b.x-a.x)*d2y - (b.y-a.y)*d2x) / denom
+			miter := pdfPoint{a.x + t*d1x, a.y + t*d1y}
+			return []pdfPoint{current, a, miter, b}
+		}
+	}
+	return []pdfPoint{current, a, b}
+}
+
+func circlePolygon(center pdfPoint, radius float64) []pdfPoint {
+	const steps = 16
+	out := make([]pdfPoint, steps)
+	for i := range out {
+		angle := 2 * math.Pi * float64(i) / steps
+		out[i] = pdfPoint{center.x + radius*math.Cos(angle), center.y + radius*math.Sin(angle)}
+	}
+	return out
+}
+
+func polygonArea(points []pdfPoint) float64 {
+	area := 0.0
+	for i := range points {
+		a, b := points[i], points[(i+1)%len(points)]
+		area += a.x*b.y - b.x*a.y
+	}
+	return area / 2
+}
+
+// #8f proses: pecah polyline sesuai pola dash, phase menggeser awal pola. Pola yang semuanya nol dianggap garis solid
+func dashPolylines(lines []pdfPolyline, dash []float64, phase float64) []pdfPolyline {
+	total := 0.0
+	for _, value := range dash {
+		if value < 0 {
+			return lines
+		}
+		total += value
+	}
+	if total <= 0 {
+		return lines
+	}
+	if len(dash)%2 == 1 {
+		dash = append(append([]float64(nil), dash...), dash...)
+		total *= 2
+	}
+
+	out := []pdfPolyline{}
+	for _, line := range lines {
+		points := line.points
+		if line.closed && len(points) > 1 {
+			points = append(append([]pdfPoint(nil), points...), points[0])
+		}
+
+		index := 0
+		remaining := dash[0]
+		offset := math.Mod(phase, total)
+		if offset < 0 {
+			offset += total
+		}
+		for offset > 0 {
+			if offset < remaining {
+				remaining -= offset
+				break
+			}
+			offset -= remaining
+			index = (index + 1) % len(dash)
+			remaining = dash[index]
+		}
+
+		var current []pdfPoint
+		if index%2 == 0 && len(points) > 0 {
+			current = []pdfPoint{points[0]}
+		}
+		for i := 1; i < len(points); i++ {
+			a, b := points[i-1], points[i]
+			length := pointDistance(a, b)
+			pos := 0.0
+			for length-pos > remaining {
+				pos += remaining
+				t := pos / length
+				point := pdfPoint{a.x + t*(b.x-a.x), a.y + t*(b.y-a.y)}
+				if index%2 == 0 {
+					out = append(out, pdfPolyline{points: append(current, point)})
+					current = nil
+				} else {
+					current = []pdfPoint{point}
+				}
+				index = (index + 1) % len(dash)
+				remaining = dash[index]
+				if len(out) > pdfMaxPathPoints/4 {
+					return out
+				}
+			}
+			remaining -= length - pos
+			if index%2 == 0 {
+				current = append(current, b)
+			}
+		}
+		if len(current) > 1 {
+			out = append(out, pdfPolyline{points: current})
+		}
+	}
+	return out
+}
+
+// #9 proses: kanvas RGB halaman, dimulai putih seperti kertas
+type pdfCanvas struct {
+	img *image.RGBA
+}
+
+type pdfRGB struct {
+	r, g, b float64
+}
+
+func newPDFCanvas(width, height int) *pdfCanvas {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	return &pdfCanvas{img: img}
+}
+
+// #9a proses: campur satu piksel dengan warna dan alpha (source-over), alpha sudah termasuk coverage, clip, dan transparansi
+func (c *pdfCanvas) blend(x, y int, col pdfRGB, alpha float64) {
+	if alpha <= 0 {
+		return
+	}
+	if alpha > 1 {
+		alpha = 1
+	}
+	i := c.img.PixOffset(x, y)
+	pix := c.img.Pix[i : i+3 : i+3]
+	pix[0] = blendChannel(pix[0], col.r, alpha)
+	pix[1] = blendChannel(pix[1], col.g, alpha)
+	pix[2] = blendChannel(pix[2], col.b, alpha)
+}
+
+func blendChannel(dst uint8, src, alpha float64) uint8 {
+	value := float64(dst)*(1-alpha) + clampUnit(src)*255*alpha
+	return uint8(math.Max(0, math.Min(255, math.Round(value))))
+}
+
+func clampUnit(v float64) float64 {
+	if math.IsNaN(v) || v < 0 {
+		return 0
+	}
+	if v > 1 {
+		return 1
+	}
+	return v
+}
+
+// #9b proses: cat coverage dengan warna tunggal, dibatasi mask clip
+func (c *pdfCanvas) paint(coverage *pdfCoverage, clip *pdfCoverage, col pdfRGB, alpha float64) {
+	c.paintFunc(coverage, clip, func(int, int) (pdfRGB, float64) { return col, alpha })
+}
+
+// #9c proses: cat coverage dengan warna per piksel, dipakai untuk shading dan pola
+func (c *pdfCanvas) paintFunc(coverage *pdfCoverage, clip *pdfCoverage, shade func(x, y int) (pdfRGB, float64)) {
+	if coverage == nil {
+		return
+	}
+	bounds := coverage.bounds
+	if clip != nil {
+		bounds = bounds.Intersect(clip.bounds)
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			amount := float64(min(1, coverage.at(x, y)))
+			if clip != nil {
+				amount *= float64(clip.at(x, y))
+			}
+			if amount <= 0 {
+				continue
+			}
+			col, alpha := shade(x, y)
+			c.blend(x, y, col, alpha*amount)
+		}
+	}
+}
+
+// #10 proses: irisan mask clip dengan coverage path baru, clip nil berarti seluruh halaman dan coverage nil berarti path kosong
+func intersectCoverage(a, b *pdfCoverage) *pdfCoverage {
+	if b == nil {
+		return &pdfCoverage{}
+	}
+	if a == nil {
+		return b
+	}
+	bounds := a.bounds.Intersect(b.bounds)
+	out := &pdfCoverage{bounds: bounds, data: make([]float32, bounds.Dx()*bounds.Dy())}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			out.data[(y-bounds.Min.Y)*bounds.Dx()+x-bounds.Min.X] = min(1, a.at(x, y)) * min(1, b.at(x, y))
+		}
+	}
+	return out
+}
@@ -0,0 +1,1108 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk bytes, fmt, image, dan math
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"math"
+)
+
+// #2 proses: batas interpreter content stream, jumlah token content stream (termasuk glyph) dan anggaran kerja rasterisasi per halaman, resolusi grid shading fungsi, kedalaman form XObject dan glyph Type3, tumpukan q/Q, kedalaman page tree supaya /Kids yang saling menunjuk tidak membuat loop, serta ukuran halaman Letter jika MediaBox tidak valid
+const (
+	pdfMaxOperators     = 4000000
+	pdfMaxRasterWork    = 8000000
+	pdfShadingGrid      = 64
+	pdfMaxFormDepth     = 12
+	pdfMaxStateStack    = 256
+	pdfMaxPageTreeDepth = 32
+	pdfDefaultPageWidth = 612
+	pdfDefaultPageHigh  = 792
+)
+
+// #3 proses: graphics state PDF yang disimpan dan dipulihkan oleh q/Q, termasuk state teks
+type pdfGraphicsState struct {
+	ctm           pdfMatrix
+	patternBase   pdfMatrix
+	fillSpace     *pdfColorSpace
+	strokeSpace   *pdfColorSpace
+	fillColor     []float64
+	strokeColor   []float64
+	fillPattern   interface{}
+	strokePattern interface{}
+	fillAlpha     float64
+	strokeAlpha   float64
+	stroke        pdfStrokeStyle
+	clip          *pdfCoverage
+	charSpacing   float64
+	wordSpacing   float64
+	hScale        float64
+	leading       float64
+	rise          float64
+	font          *pdfFont
+	fontSize      float64
+	renderMode    int
+}
+
+// #4 proses: renderer satu halaman, path dan matriks teks bukan bagian graphics state sehingga disimpan di renderer
+type pdfRenderer struct {
+	doc        *pdfDocument
+	canvas     *pdfCanvas
+	target     *pdfRasterTarget
+	width      int
+	height     int
+	state      pdfGraphicsState
+	stack      []pdfGraphicsState
+	path       *pdfPath
+	clipRule   int
+	textMatrix pdfMatrix
+	lineMatrix pdfMatrix
+	textClip   *pdfPath
+	fonts      map[pdfRef]*pdfFont
+	ops        int
+}
+
+// #5 proses: render halaman pertama PDF ke bitmap dengan sisi terpanjang maxSide piksel. Return nil tanpa error jika PDF tidak punya halaman. Panic dari PDF rusak diubah menjadi error
+func renderPDFFirstPage(data []byte, maxSide int) (img *image.RGBA, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			img = nil
+			err = fmt.Errorf("error render PDF: %v", recovered)
+		}
+	}()
+
+	doc, err := openPDFDocument(data)
+	if err != nil {
+		return nil, err
+	}
+	page, inherited := doc.firstPage()
+	if page == nil {
+		return nil, nil
+	}
+
+	// #5a proses: area halaman dari CropBox (dibatasi MediaBox), Rotate kelipatan 90 derajat searah jarum jam
+	box := doc.pageBox(inherited["MediaBox"], []float64{0, 0, pdfDefaultPageWidth, pdfDefaultPageHigh})
+	if crop, ok := inherited["CropBox"]; ok {
+		cropBox := doc.pageBox(crop, box)
+		box = []float64{
+			math.Max(box[0], cropBox[0]), math.Max(box[1], cropBox[1]),
+			math.Min(box[2], cropBox[2]), math.Min(box[3], cropBox[3]),
+		}
+		if box[2] <= box[0] || box[3] <= box[1] {
+			box = doc.pageBox(inherited["MediaBox"], []float64{0, 0, pdfDefaultPageWidth, pdfDefaultPageHigh})
+		}
+	}
+	rotate := (int(doc.number(inherited["Rotate"], 0))%360 + 360) % 360 / 90 * 90
+
+	pageWidth, pageHeight := box[2]-box[0], box[3]-box[1]
+	if rotate == 90 || rotate == 270 {
+		pageWidth, pageHeight = pageHeight, pageWidth
+	}
+	scale := float64(maxSide) / math.Max(pageWidth, pageHeight)
+	width := max(1, int(math.Round(pageWidth*scale)))
+	height := max(1, int(math.Round(pageHeight*scale)))
+
+	// #5b proses: matriks halaman memetakan ruang user PDF (origin kiri bawah) ke piksel (origin kiri atas) sesuai rotasi
+	x0, y0, x1, y1, s := box[0], box[1], box[2], box[3], scale
+	var base pdfMatrix
+	switch rotate {
+	case 90:
+		base = pdfMatrix{0, s, s, 0, -y0 * s, -x0 * s}
+	case 180:
+		base = pdfMatrix{-s, 0, 0, s, x1 * s, -y0 * s}
+	case 270:
+		base = pdfMatrix{0, -s, -s, 0, y1 * s, x1 * s}
+	default:
+		base = pdfMatrix{s, 0, 0, -s, -x0 * s, y1 * s}
+	}
+
+	r := &pdfRenderer{
+		doc:    doc,
+		canvas: newPDFCanvas(width, height),
+		target: &pdfRasterTarget{width: width, height: height, budget: pdfMaxRasterWork},
+		width:  width,
+		height: height,
+		fonts:  map[pdfRef]*pdfFont{},
+	}
+	r.state = pdfGraphicsState{
+		ctm:         base,
+		patternBase: base,
+		fillSpace:   pdfGraySpace,
+		strokeSpace: pdfGraySpace,
+		fillColor:   []float64{0},
+		strokeColor: []float64{0},
+		fillAlpha:   1,
+		strokeAlpha: 1,
+		stroke:      pdfStrokeStyle{width: 1, miterLimit: 10},
+		hScale:      1,
+	}
+
+	resources := doc.dict(inherited["Resources"])
+	r.execute(doc.pageContents(page), resources, 0)
+	r.drawAnnotations(page, base)
+	return r.canvas.img, nil
+}
+
+// #6 proses: cari halaman pertama di page tree secara depth-first, atribut yang bisa diwariskan (Resources, MediaBox, CropBox, Rotate) dibawa dari node Pages
+func (d *pdfDocument) firstPage() (pdfDict, pdfDict) {
+	catalog := d.dict(d.trailer["Root"])
+	inherited := pdfDict{}
+	visited := map[pdfRef]bool{}
+
+	var walk func(value interface{}, inherited pdfDict, depth int) (pdfDict, pdfDict)
+	walk = func(value interface{}, inherited pdfDict, depth int) (pdfDict, pdfDict) {
+		if ref, ok := value.(pdfRef); ok {
+			if visited[ref] {
+				return nil, nil
+			}
+			visited[ref] = true
+		}
+		node := d.dict(value)
+		if node == nil || depth > pdfMaxPageTreeDepth {
+			return nil, nil
+		}
+		attrs := pdfDict{}
+		for key, item := range inherited {
+			attrs[key] = item
+		}
+		for _, key := range []pdfName{"Resources", "MediaBox", "CropBox", "Rotate"} {
+			if item, ok := node[key]; ok {
+				attrs[key] = item
+			}
+		}
+
+		kids, hasKids := d.resolve(node["Kids"]).(pdfArray)
+		if d.name(node["Type"]) == "Page" || (!hasKids && d.name(node["Type"]) != "Pages") {
+			return node, attrs
+		}
+		for _, kid := range kids {
+			if page, pageAttrs := walk(kid, attrs, depth+1); page != nil {
+				return page, pageAttrs
+			}
+		}
+		return nil, nil
+	}
+	if catalog == nil {
+		return nil, nil
+	}
+	return walk(catalog["Pages"], inherited, 0)
+}
+
+// #6a proses: rectangle halaman dinormalisasi supaya x0 < x1 dan y0 < y1
+func (d *pdfDocument) pageBox(value interface{}, fallback []float64) []float64 {
+	box := d.numbers(value)
+	if len(box) != 4 {
+		return fallback
+	}
+	x0, x1 := math.Min(box[0], box[2]), math.Max(box[0], box[2])
+	y0, y1 := math.Min(box[1], box[3]), math.Max(box[1], box[3])
+	if x1-x0 < 1 || y1-y0 < 1 || x1-x0 > 1e6 || y1-y0 > 1e6 {
+		return fallback
+	}
+	return []float64{x0, y0, x1, y1}
+}
+
+// #6b proses: gabungkan /Contents halaman (satu stream atau array stream) dipisah newline
+func (d *pdfDocument) pageContents(page pdfDict) []byte {
+	var streams []interface{}
+	switch contents := d.resolve(page["Contents"]).(type) {
+	case *pdfStream:
+		streams = append(streams, contents)
+	case pdfArray:
+		streams = contents
+	}
+	var out bytes.Buffer
+	for _, item := range streams {
+		stream, ok := d.resolve(item).(*pdfStream)
+		if !ok {
+			continue
+		}
+		data, _, err := d.decodeStream(stream)
+		if err != nil {
+			continue
+		}
+		out.Write(data)
+		out.WriteByte('\n')
+	}
+	return out.Bytes()
+}
+
+// #7 proses: jalankan content stream, operand dikumpulkan sampai bertemu operator. Rendering berhenti diam-diam setelah batas token atau anggaran kerja habis sehingga halaman berat tetap menghasilkan preview parsial
+func (r *pdfRenderer) execute(content []byte, resources pdfDict, depth int) {
+	lexer := &pdfLexer{data: content}
+	operands := make([]interface{}, 0, 8)
+	for r.ops < pdfMaxOperators && !r.target.exhausted() {
+		tok, ok := lexer.object(0)
+		if !ok {
+			return
+		}
+		r.ops++
+		op, isOperator := tok.(pdfKeyword)
+		if !isOperator {
+			if len(operands) < 64 {
+				operands = append(operands, tok)
+			}
+			continue
+		}
+		if op == "BI" {
+			r.inlineImage(lexer, resources)
+		} else {
+			r.operator(string(op), operands, resources, depth)
+		}
+		operands = operands[:0]
+	}
+}
+
+// #7a proses: ambil operand angka dari belakang (operand terakhir paling dekat dengan operator)
+func operandNumbers(operands []interface{}, n int) ([]float64, bool) {
+	if len(operands) < n {
+		return nil, false
+	}
+	out := make([]float64, n)
+	for i, item := range operands[len(operands)-n:] {
+		number, ok := item.(float64)
+		if !ok {
+			return nil, false
+		}
+		out[i] = number
+	}
+	return out, true
+}
+
+func (r *pdfRenderer) operator(op string, operands []interface{}, resources pdfDict, depth int) {
+	n := func(count int) ([]float64, bool) { return operandNumbers(operands, count) }
+	s := &r.state
+	if r.path == nil {
+		r.path = &pdfPath{}
+	}
+
+	switch op {
+	// #7b proses: graphics state
+	case "q":
+		if len(r.stack) < pdfMaxStateStack {
+			r.stack = append(r.stack, r.state)
+		}
+	case "Q":
+		if len(r.stack) > 0 {
+			r.state = r.stack[len(r.stack)-1]
+			r.stack = r.stack[:len(r.stack)-1]
+		}
+	case "cm":
+		if v, ok := n(6); ok {
+			if m, ok := matrixFromNumbers(v); ok {
+				s.ctm = m.multiply(s.ctm)
+			}
+		}
+	case "w":
+		if v, ok := n(1); ok {
+			s.stroke.width = math.Abs(v[0])
+		}
+	case "J":
+		if v, ok := n(1); ok {
+			s.stroke.cap = int(v[0])
+		}
+	case "j":
+		if v, ok := n(1); ok {
+			s.stroke.join = int(v[0])
+		}
+	case "M":
+		if v, ok := n(1); ok {
+			s.stroke.miterLimit = v[0]
+		}
+	case "d":
+		if len(operands) >= 2 {
+			s.stroke.dash = r.doc.numbers(operands[len(operands)-2])
+			s.stroke.dashPhase, _ = operands[len(operands)-1].(float64)
+		}
+	case "gs":
+		if len(operands) >= 1 {
+			if name, ok := operands[len(operands)-1].(pdfName); ok {
+				r.applyExtGState(r.doc.dict(r.doc.dict(resources["ExtGState"])[name]))
+			}
+		}
+
+	// #7c proses: konstruksi path
+	case "m":
+		if v, ok := n(2); ok {
+			r.path.moveTo(v[0], v[1])
+		}
+	case "l":
+		if v, ok := n(2); ok {
+			r.path.lineTo(v[0], v[1])
+		}
+	case "c":
+		if v, ok := n(6); ok {
+			r.path.curveTo(v[0], v[1], v[2], v[3], v[4], v[5])
+		}
+	case "v":
+		if v, ok := n(4); ok {
+			c := r.path.current
+			r.path.curveTo(c.x, c.y, v[0], v[1], v[2], v[3])
+		}
+	case "y":
+		if v, ok := n(4); ok {
+			r.path.curveTo(v[0], v[1], v[2], v[3], v[2], v[3])
+		}
+	case "h":
+		r.path.closePath()
+	case "re":
+		if v, ok := n(4); ok {
+			r.path.rect(v[0], v[1], v[2], v[3])
+		}
+
+	// #7d proses: painting path, clip dari W/W* diterapkan setelah path dicat
+	case "S":
+		r.strokePath()
+		r.endPath()
+	case "s":
+		r.path.closePath()
+		r.strokePath()
+		r.endPath()
+	case "f", "F", "f*":
+		r.fillPath(op == "f*")
+		r.endPath()
+	case "B", "B*":
+		r.fillPath(op == "B*")
+		r.strokePath()
+		r.endPath()
+	case "b", "b*":
+		r.path.closePath()
+		r.fillPath(op == "b*")
+		r.strokePath()
+		r.endPath()
+	case "n":
+		r.endPath()
+	case "W":
+		r.clipRule = 1
+	case "W*":
+		r.clipRule = 2
+
+	// #7e proses: warna
+	case "CS", "cs":
+		if len(operands) >= 1 {
+			cs := r.doc.colorSpace(operands[len(operands)-1], resources, 0)
+			if op == "cs" {
+				s.fillSpace, s.fillColor, s.fillPattern = cs, cs.initialColor(), nil
+			} else {
+				s.strokeSpace, s.strokeColor, s.strokePattern = cs, cs.initialColor(), nil
+			}
+		}
+	case "SC", "SCN", "sc", "scn":
+		values := []float64{}
+		var pattern interface{}
+		for _, item := range operands {
+			switch value := item.(type) {
+			case float64:
+				values = append(values, value)
+			case pdfName:
+				pattern = r.doc.dict(resources["Pattern"])[value]
+			}
+		}
+		if op == "sc" || op == "scn" {
+			s.fillColor, s.fillPattern = values, pattern
+		} else {
+			s.strokeColor, s.strokePattern = values, pattern
+		}
+	case "G", "g":
+		if v, ok := n(1); ok {
+			r.setDeviceColor(op == "g", pdfGraySpace, v)
+		}
+	case "RG", "rg":
+		if v, ok := n(3); ok {
+			r.setDeviceColor(op == "rg", pdfRGBSpace, v)
+		}
+	case "K", "k":
+		if v, ok := n(4); ok {
+			r.setDeviceColor(op == "k", pdfCMYKSpace, v)
+		}
+
+	// #7f proses: teks
+	case "BT":
+		r.textMatrix, r.lineMatrix = pdfIdentity, pdfIdentity
+		r.textClip = nil
+	case "ET":
+		if r.textClip != nil {
+			s.clip = intersectCoverage(s.clip, fillCoverage(r.textClip, pdfIdentity, false, r.target))
+			r.textClip = nil
+		}
+	case "Tc":
+		if v, ok := n(1); ok {
+			s.charSpacing = v[0]
+		}
+	case "Tw":
+		if v, ok := n(1); ok {
+			s.wordSpacing = v[0]
+		}
+	case "Tz":
+		if v, ok := n(1); ok {
+			s.hScale = v[0] / 100
+		}
+	case "TL":
+		if v, ok := n(1); ok {
+			s.leading = v[0]
+		}
+	case "Ts":
+		if v, ok := n(1); ok {
+			s.rise = v[0]
+		}
+	case "Tr":
+		if v, ok := n(1); ok {
+			s.renderMode = int(v[0])
+		}
+	case "Tf":
+		if len(operands) >= 2 {
+			if name, ok := operands[len(operands)-2].(pdfName); ok {
+				s.font = r.font(r.doc.dict(resources["Font"])[name])
+			}
+			s.fontSize, _ = operands[len(operands)-1].(float64)
+		}
+	case "Td", "TD":
+		if v, ok := n(2); ok {
+			if op == "TD" {
+				s.leading = -v[1]
+			}
+			r.lineMatrix = pdfMatrix{1, 0, 0, 1, v[0], v[1]}.multiply(r.lineMatrix)
+			r.textMatrix = r.lineMatrix
+		}
+	case "Tm":
+		if v, ok := n(6); ok {
+			if m, ok := matrixFromNumbers(v); ok {
+				r.textMatrix, r.lineMatrix = m, m
+			}
+		}
+	case "T*":
+		r.nextLine()
+	case "Tj":
+		if len(operands) >= 1 {
+			if text, ok := operands[len(operands)-1].(pdfString); ok {
+				r.showText(text, resources, depth)
+			}
+		}
+	case "'", "\"":
+		if op == "\"" {
+			if v, ok := operandNumbers(operands[:max(0, len(operands)-1)], 2); ok {
+				s.wordSpacing, s.charSpacing = v[0], v[1]
+			}
+		}
+		r.nextLine()
+		if len(operands) >= 1 {
+			if text, ok := operands[len(operands)-1].(pdfString); ok {
+				r.showText(text, resources, depth)
+			}
+		}
+	case "TJ":
+		if len(operands) >= 1 {
+			array, _ := operands[len(operands)-1].(pdfArray)
+			for _, item := range array {
+				switch value := item.(type) {
+				case pdfString:
+					r.showText(value, resources, depth)
+				case float64:
+					r.advanceText(-value / 1000 * s.fontSize)
+				}
+			}
+		}
+
+	// #7g proses: XObject dan shading
+	case "Do":
+		if len(operands) >= 1 {
+			if name, ok := operands[len(operands)-1].(pdfName); ok {
+				r.drawXObject(r.doc.dict(resources["XObject"])[name], resources, depth)
+			}
+		}
+	case "sh":
+		if len(operands) >= 1 {
+			if name, ok := operands[len(operands)-1].(pdfName); ok {
+				r.paintShading(r.doc.dict(resources["Shading"])[name], s.ctm)
+			}
+		}
+	}
+}
+
+func (r *pdfRenderer) setDeviceColor(fill bool, cs *pdfColorSpace, values []float64) {
+	if fill {
+		r.state.fillSpace, r.state.fillColor, r.state.fillPattern = cs, values, nil
+	} else {
+		r.state.strokeSpace, r.state.strokeColor, r.state.strokePattern = cs, values, nil
+	}
+}
+
+// #7h proses: ExtGState, hanya parameter garis, alpha konstan, dan font yang dipakai. Soft mask dan blend mode diabaikan
+func (r *pdfRenderer) applyExtGState(gs pdfDict) {
+	if gs == nil {
+		return
+	}
+	s := &r.state
+	if v, ok := r.doc.resolve(gs["LW"]).(float64); ok {
+		s.stroke.width = math.Abs(v)
+	}
+	if v, ok := r.doc.resolve(gs["LC"]).(float64); ok {
+		s.stroke.cap = int(v)
+	}
+	if v, ok := r.doc.resolve(gs["LJ"]).(float64); ok {
+		s.stroke.join = int(v)
+	}
+	if v, ok := r.doc.resolve(gs["ML"]).(float64); ok {
+		s.stroke.miterLimit = v
+	}
+	if dash := r.doc.array(gs["D"]); len(dash) == 2 {
+		s.stroke.dash = r.doc.numbers(dash[0])
+		s.stroke.dashPhase = r.doc.number(dash[1], 0)
+	}
+	if v, ok := r.doc.resolve(gs["CA"]).(float64); ok {
+		s.strokeAlpha = clampUnit(v)
+	}
+	if v, ok := r.doc.resolve(gs["ca"]).(float64); ok {
+		s.fillAlpha = clampUnit(v)
+	}
+	if font := r.doc.array(gs["Font"]); len(font) == 2 {
+		s.font = r.font(font[0])
+		s.fontSize = r.doc.number(font[1], s.fontSize)
+	}
+}
+
+// #8 proses: fill dan stroke path dengan warna atau pattern aktif, lalu terapkan clip yang tertunda
+func (r *pdfRenderer) fillPath(evenOdd bool) {
+	coverage := fillCoverage(r.path, r.state.ctm, evenOdd, r.target)
+	r.paintCoverage(coverage, true)
+}
+
+func (r *pdfRenderer) strokePath() {
+	coverage := strokeCoverage(r.path, r.state.ctm, r.state.stroke, r.target)
+	r.paintCoverage(coverage, false)
+}
+
+func (r *pdfRenderer) endPath() {
+	if r.clipRule != 0 {
+		coverage := fillCoverage(r.path, r.state.ctm, r.clipRule == 2, r.target)
+		r.state.clip = intersectCoverage(r.state.clip, coverage)
+		r.clipRule = 0
+	}
+	r.path = &pdfPath{}
+}
+
+// #8a proses: cat coverage dengan warna fill/stroke. Shading pattern dirender per piksel, tiling pattern didekati dengan abu-abu terang karena isi sel pola tidak dirender
+func (r *pdfRenderer) paintCoverage(coverage *pdfCoverage, fill bool) {
+	if coverage == nil {
+		return
+	}
+	s := &r.state
+	space, values, pattern, alpha := s.fillSpace, s.fillColor, s.fillPattern, s.fillAlpha
+	if !fill {
+		space, values, pattern, alpha = s.strokeSpace, s.strokeColor, s.strokePattern, s.strokeAlpha
+	}
+	if space.kind == "none" {
+		return
+	}
+	if space.kind != "pattern" {
+		r.canvas.paint(coverage, s.clip, space.toRGB(values), alpha)
+		return
+	}
+
+	patternDict := r.doc.dict(pattern)
+	if patternDict == nil {
+		return
+	}
+	matrix := s.patternBase
+	if m, ok := matrixFromNumbers(r.doc.numbers(patternDict["Matrix"])); ok {
+		matrix = m.multiply(s.patternBase)
+	}
+	switch int(r.doc.number(patternDict["PatternType"], 0)) {
+	case 2:
+		if shade := r.shadingSampler(patternDict["Shading"], matrix); shade != nil {
+			r.canvas.paintFunc(coverage, s.clip, func(x, y int) (pdfRGB, float64) {
+				col, ok := shade(float64(x)+0.5, float64(y)+0.5)
+				if !ok {
+					return col, 0
+				}
+				return col, alpha
+			})
+		}
+	default:
+		col := pdfRGB{0.75, 0.75, 0.75}
+		if int(r.doc.number(patternDict["PaintType"], 1)) == 2 && space.base != nil {
+			col = space.base.toRGB(values)
+		}
+		r.canvas.paint(coverage, s.clip, col, alpha)
+	}
+}
+
+// #9 proses: operator sh mengisi seluruh area clip dengan shading dalam ruang user saat ini
+func (r *pdfRenderer) paintShading(value interface{}, m pdfMatrix) {
+	shade := r.shadingSampler(value, m)
+	if shade == nil {
+		return
+	}
+	full := &pdfPath{}
+	full.rect(0, 0, float64(r.width), float64(r.height))
+	coverage := fillCoverage(full, pdfIdentity, false, r.target)
+	alpha := r.state.fillAlpha
+	r.canvas.paintFunc(coverage, r.state.clip, func(x, y int) (pdfRGB, float64) {
+		col, ok := shade(float64(x)+0.5, float64(y)+0.5)
+		if !ok {
+			return col, 0
+		}
+		return col, alpha
+	})
+}
+
+// #9a proses: sampler shading di koordinat device. Tipe 1 (fungsi), 2 (axial), dan 3 (radial) didukung, warna axial dan radial diambil dari tabel 256 langkah dan tipe 1 dari grid 64x64. Shading mesh (tipe 4-7) tidak dirender
+func (r *pdfRenderer) shadingSampler(value interface{}, m pdfMatrix) func(x, y float64) (pdfRGB, bool) {
+	dict := r.doc.dict(value)
+	if dict == nil {
+		return nil
+	}
+	inverse, ok := m.invert()
+	if !ok {
+		return nil
+	}
+	cs := r.doc.colorSpace(dict["ColorSpace"], nil, 0)
+	fn := r.doc.function(dict["Function"], 0)
+	if fn == nil {
+		return nil
+	}
+	coords := r.doc.numbers(dict["Coords"])
+	domain := r.doc.numbers(dict["Domain"])
+	extend := r.doc.array(dict["Extend"])
+	extendStart, extendEnd := false, false
+	if len(extend) == 2 {
+		extendStart, _ = r.doc.resolve(extend[0]).(bool)
+		extendEnd, _ = r.doc.resolve(extend[1]).(bool)
+	}
+
+	shadingType := int(r.doc.number(dict["ShadingType"], 0))
+	if shadingType == 1 {
+		if len(domain) != 4 {
+			domain = []float64{0, 1, 0, 1}
+		}
+		if sm, ok := matrixFromNumbers(r.doc.numbers(dict["Matrix"])); ok {
+			if inv, ok := sm.multiply(m).invert(); ok {
+				inverse = inv
+			}
+		}
+		// fungsi (bisa PostScript) di-sample pada grid tetap supaya biayanya tidak bergantung pada jumlah piksel
+		var grid [pdfShadingGrid][pdfShadingGrid]pdfRGB
+		for gy := range grid {
+			for gx := range grid[gy] {
+				u := domain[0] + (domain[1]-domain[0])*(float64(gx)+0.5)/pdfShadingGrid
+				v := domain[2] + (domain[3]-domain[2])*(float64(gy)+0.5)/pdfShadingGrid
+				grid[gy][gx] = cs.toRGB(fn([]float64{u, v}))
+			}
+		}
+		return func(x, y float64) (pdfRGB, bool) {
+			p := inverse.apply(pdfPoint{x, y})
+			if p.x < domain[0] || p.x > domain[1] || p.y < domain[2] || p.y > domain[3] || domain[1] <= domain[0] || domain[3] <= domain[2] {
+				return pdfRGB{}, false
+			}
+			gx := min(pdfShadingGrid-1, int((p.x-domain[0])/(domain[1]-domain[0])*pdfShadingGrid))
+			gy := min(pdfShadingGrid-1, int((p.y-domain[2])/(domain[3]-domain[2])*pdfShadingGrid))
+			return grid[gy][gx], true
+		}
+	}
+
+	if len(domain) != 2 {
+		domain = []float64{0, 1}
+	}
+	var lut [256]pdfRGB
+	for i := range lut {
+		t := domain[0] + (domain[1]-domain[0])*float64(i)/255
+		lut[i] = cs.toRGB(fn([]float64{t}))
+	}
+	lookup := func(s float64) (pdfRGB, bool) {
+		if s < 0 {
+			if !extendStart {
+				return pdfRGB{}, false
+			}
+			s = 0
+		}
+		if s > 1 {
+			if !extendEnd {
+				return pdfRGB{}, false
+			}
+			s = 1
+		}
+		return lut[int(math.Round(s*255))], true
+	}
+
+	switch {
+	case shadingType == 2 && len(coords) == 4:
+		dx, dy := coords[2]-coords[0], coords[3]-coords[1]
+		length := dx*dx + dy*dy
+		if length == 0 {
+			return nil
+		}
+		return func(x, y float64) (pdfRGB, bool) {
+			p := inverse.apply(pdfPoint{x, y})
+			return lookup(((p.x-coords[0])*dx + (p.y-coords[1])*dy) / length)
+		}
+	case shadingType == 3 && len(coords) == 6:
+		x0, y0, r0, x1, y1, r1 := coords[0], coords[1], coords[2], coords[3], coords[4], coords[5]
+		cdx, cdy, dr := x1-x0, y1-y0, r1-r0
+		a := cdx*cdx + cdy*cdy - dr*dr
+		return func(x, y float64) (pdfRGB, bool) {
+			p := inverse.apply(pdfPoint{x, y})
+			pdx, pdy := p.x-x0, p.y-y0
+			b := pdx*cdx + pdy*cdy + r0*dr
+			c := pdx*pdx + pdy*pdy - r0*r0
+			// #9b proses: cari s terbesar dengan |p - c(s)| = r(s) dan r(s) >= 0
+			candidates := []float64{}
+			if math.Abs(a) < 1e-9 {
+				if b != 0 {
+					candidates = append(candidates, c/(2*b))
+				}
+			} else {
+				disc := b*b - a*c
+				if disc < 0 {
+					return pdfRGB{}, false
+				}
+				sq := math.Sqrt(disc)
+				s1, s2 := (b+sq)/a, (b-sq)/a
+				candidates = append(candidates, math.Max(s1, s2), math.Min(s1, s2))
+			}
+			for _, s := range candidates {
+				if r0+s*dr < 0 {
+					continue
+				}
+				if (s < 0 && !extendStart) || (s > 1 && !extendEnd) {
+					continue
+				}
+				return lookup(s)
+			}
+			return pdfRGB{}, false
+		}
+	}
+	return nil
+}
+
+// #10 proses: cache font per referensi object supaya program font tidak di-parse ulang untuk tiap operator Tf
+func (r *pdfRenderer) font(value interface{}) *pdfFont {
+	ref, isRef := value.(pdfRef)
+	if isRef {
+		if font, ok := r.fonts[ref]; ok {
+			return font
+		}
+	}
+	font := r.doc.loadFont(value)
+	if isRef {
+		r.fonts[ref] = font
+	}
+	return font
+}
+
+func (r *pdfRenderer) nextLine() {
+	r.lineMatrix = pdfMatrix{1, 0, 0, 1, 0, -r.state.leading}.multiply(r.lineMatrix)
+	r.textMatrix = r.lineMatrix
+}
+
+// #10a proses: geser matriks teks sejauh amount (satuan ruang teks sebelum skala horizontal) searah penulisan font
+func (r *pdfRenderer) advanceText(amount float64) {
+	s := &r.state
+	if s.font != nil && s.font.vertical {
+		r.textMatrix = pdfMatrix{1, 0, 0, 1, 0, -amount}.multiply(r.textMatrix)
+		return
+	}
+	r.textMatrix = pdfMatrix{1, 0, 0, 1, amount * s.hScale, 0}.multiply(r.textMatrix)
+}
+
+// #11 proses: tampilkan string teks glyph per glyph. Glyph dengan outline diisi/distroke sesuai render mode, glyph Type3 dijalankan sebagai content stream, dan font yang tidak ter-embed digambar sebagai kotak setinggi x-height dengan alpha setengah
+func (r *pdfRenderer) showText(text []byte, resources pdfDict, depth int) {
+	s := &r.state
+	font := s.font
+	if font == nil {
+		return
+	}
+	for _, c := range font.decode(text) {
+		r.ops++
+		if r.ops > pdfMaxOperators || r.target.exhausted() {
+			return
+		}
+		width := font.width(c) / 1000
+		textState := pdfMatrix{s.fontSize * s.hScale, 0, 0, s.fontSize, 0, s.rise}
+		trm := textState.multiply(r.textMatrix).multiply(s.ctm)
+
+		switch {
+		case font.type3 != nil:
+			if proc, ok := font.type3.procs[c.code]; ok && s.renderMode != 3 && s.renderMode != 7 && depth < pdfMaxFormDepth {
+				procResources := font.type3.resources
+				if procResources == nil {
+					procResources = resources
+				}
+				r.drawType3Glyph(proc, font.type3.matrix.multiply(trm), procResources, depth)
+			}
+		case font.embedded:
+			if outline := font.outline(c); outline != nil {
+				r.paintGlyph(outline, pdfMatrix{0.001, 0, 0, 0.001, 0, 0}.multiply(trm))
+			}
+		default:
+			if !(c.length == 1 && c.code == 32) && s.renderMode != 3 && s.renderMode != 7 && width > 0 {
+				box := &pdfPath{}
+				box.rect(width*0.05, 0, width*0.85, 0.45)
+				if coverage := fillCoverage(box, trm, false, r.target); coverage != nil {
+					r.canvas.paint(coverage, s.clip, s.fillSpace.toRGB(s.fillColor), s.fillAlpha*0.5)
+				}
+			}
+		}
+
+		advance := width*s.fontSize + s.charSpacing
+		if c.length == 1 && c.code == 32 {
+			advance += s.wordSpacing
+		}
+		if font.vertical {
+			advance = s.fontSize + s.charSpacing
+		}
+		r.advanceText(advance)
+	}
+}
+
+// #11a proses: cat satu glyph sesuai render mode Tr (0 fill, 1 stroke, 2 fill dan stroke, 3 tidak terlihat, 4-7 ditambah clip)
+func (r *pdfRenderer) paintGlyph(outline *pdfPath, m pdfMatrix) {
+	mode := r.state.renderMode
+	if mode == 0 || mode == 2 || mode == 4 || mode == 6 {
+		r.paintCoverage(fillCoverage(outline, m, false, r.target), true)
+	}
+	if mode == 1 || mode == 2 || mode == 5 || mode == 6 {
+		// lebar garis dalam ruang user, ubah ke ruang glyph
+		style := r.state.stroke
+		if glyphScale := m.scale(); glyphScale > 0 {
+			style.width = style.width * r.state.ctm.scale() / glyphScale
+		}
+		r.paintCoverage(strokeCoverage(outline, m, style, r.target), false)
+	}
+	if mode >= 4 {
+		if r.textClip == nil {
+			r.textClip = &pdfPath{}
+		}
+		r.textClip.appendTransformed(outline, m)
+	}
+}
+
+// #11b proses: glyph Type3 dijalankan dengan CTM = matriks glyph dan graphics state yang dipulihkan setelahnya
+func (r *pdfRenderer) drawType3Glyph(proc *pdfStream, m pdfMatrix, resources pdfDict, depth int) {
+	data, _, err := r.doc.decodeStream(proc)
+	if err != nil {
+		return
+	}
+	saved, savedStack, savedPath := r.state, r.stack, r.path
+	savedText, savedLine := r.textMatrix, r.lineMatrix
+	r.state.ctm = m
+	r.stack = nil
+	r.path = &pdfPath{}
+	r.execute(data, resources, depth+1)
+	r.state, r.stack, r.path = saved, savedStack, savedPath
+	r.textMatrix, r.lineMatrix = savedText, savedLine
+}
+
+// #12 proses: XObject Image digambar di unit square CTM, XObject Form dijalankan dengan Matrix, clip BBox, dan Resources sendiri
+func (r *pdfRenderer) drawXObject(value interface{}, resources pdfDict, depth int) {
+	stream, ok := r.doc.resolve(value).(*pdfStream)
+	if !ok {
+		return
+	}
+	switch r.doc.name(stream.dict["Subtype"]) {
+	case "Image":
+		if img := r.doc.loadImage(stream.dict, stream, resources, false, 0); img != nil {
+			r.drawImage(img)
+		}
+	case "Form":
+		r.drawForm(stream, resources, depth)
+	}
+}
+
+func (r *pdfRenderer) drawForm(stream *pdfStream, resources pdfDict, depth int) {
+	if depth >= pdfMaxFormDepth {
+		return
+	}
+	data, _, err := r.doc.decodeStream(stream)
+	if err != nil {
+		return
+	}
+	saved, savedStack, savedPath := r.state, r.stack, r.path
+	r.stack = nil
+	r.path = &pdfPath{}
+	if m, ok := matrixFromNumbers(r.doc.numbers(stream.dict["Matrix"])); ok {
+		r.state.ctm = m.multiply(r.state.ctm)
+	}
+	r.state.patternBase = r.state.ctm
+	if bbox := r.doc.numbers(stream.dict["BBox"]); len(bbox) == 4 {
+		clip := &pdfPath{}
+		clip.rect(bbox[0], bbox[1], bbox[2]-bbox[0], bbox[3]-bbox[1])
+		r.state.clip = intersectCoverage(r.state.clip, fillCoverage(clip, r.state.ctm, false, r.target))
+	}
+	formResources := r.doc.dict(stream.dict["Resources"])
+	if formResources == nil {
+		formResources = resources
+	}
+	r.execute(data, formResources, depth+1)
+	r.state, r.stack, r.path = saved, savedStack, savedPath
+}
+
+// #12a proses: gambar image dengan inverse mapping, tiap piksel device di-sample beberapa titik di ruang image sehingga gambar yang diperkecil tetap halus
+func (r *pdfRenderer) drawImage(img *pdfImage) {
+	m := r.state.ctm
+	inverse, ok := m.invert()
+	if !ok {
+		return
+	}
+	unit := &pdfPath{}
+	unit.rect(0, 0, 1, 1)
+	coverage := fillCoverage(unit, m, false, r.target)
+	if coverage == nil {
+		return
+	}
+
+	// jumlah sampel per sumbu mengikuti rasio piksel image terhadap piksel device
+	devicePixels := math.Max(1, float64(coverage.bounds.Dx()*coverage.bounds.Dy()))
+	samples := min(4, max(2, int(math.Ceil(math.Sqrt(float64(img.width*img.height)/devicePixels)))))
+	fill := r.state.fillSpace.toRGB(r.state.fillColor)
+	alpha := r.state.fillAlpha
+
+	bounds := coverage.bounds
+	if r.state.clip != nil {
+		bounds = bounds.Intersect(r.state.clip.bounds)
+	}
+	if !r.target.spend(bounds.Dx() * bounds.Dy() * samples * samples) {
+		return
+	}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			var sumR, sumG, sumB, sumA float64
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					p := inverse.apply(pdfPoint{float64(x) + (float64(sx)+0.5)/float64(samples), float64(y) + (float64(sy)+0.5)/float64(samples)})
+					if p.x < 0 || p.x >= 1 || p.y < 0 || p.y >= 1 {
+						continue
+					}
+					ix := min(img.width-1, int(p.x*float64(img.width)))
+					iy := min(img.height-1, int((1-p.y)*float64(img.height)))
+					col, a := img.sample(ix, iy)
+					if img.stencil {
+						col = fill
+					}
+					sumR += col.r * a
+					sumG += col.g * a
+					sumB += col.b * a
+					sumA += a
+				}
+			}
+			if sumA <= 0 {
+				continue
+			}
+			amount := sumA / float64(samples*samples)
+			if r.state.clip != nil {
+				amount *= float64(r.state.clip.at(x, y))
+			}
+			r.canvas.blend(x, y, pdfRGB{sumR / sumA, sumG / sumA, sumB / sumA}, amount*alpha)
+		}
+	}
+}
+
+// #12b proses: inline image BI ... ID <data> EI, kunci singkatan diubah ke nama lengkap lalu didecode seperti image XObject
+var pdfInlineImageKeys = map[pdfName]pdfName{
+	"BPC": "BitsPerComponent", "CS": "ColorSpace", "D": "Decode", "DP": "DecodeParms",
+	"F": "Filter", "H": "Height", "IM": "ImageMask", "I": "Interpolate", "W": "Width",
+}
+
+func (r *pdfRenderer) inlineImage(lexer *pdfLexer, resources pdfDict) {
+	dict := pdfDict{}
+	for {
+		key, ok := lexer.object(0)
+		if !ok {
+			return
+		}
+		if key == pdfKeyword("ID") {
+			break
+		}
+		name, isName := key.(pdfName)
+		if !isName {
+			continue
+		}
+		value, ok := lexer.object(0)
+		if !ok {
+			return
+		}
+		if long, ok := pdfInlineImageKeys[name]; ok {
+			name = long
+		}
+		dict[name] = value
+	}
+
+	start := lexer.pos + 1
+	if start > len(lexer.data) {
+		return
+	}
+	end := inlineImageEnd(lexer.data, start)
+	lexer.pos = min(len(lexer.data), end+2)
+	if img := r.doc.loadImage(dict, &pdfStream{dict: dict, raw: lexer.data[start:end]}, resources, false, 0); img != nil {
+		r.drawImage(img)
+	}
+}
+
+// #12c proses: akhir data inline image adalah "EI" yang diapit whitespace (atau akhir stream)
+func inlineImageEnd(data []byte, start int) int {
+	for pos := start; pos+2 <= len(data); pos++ {
+		if data[pos] != 'E' || data[pos+1] != 'I' {
+			continue
+		}
+		if pos > start && !isPDFWhitespace(data[pos-1]) {
+			continue
+		}
+		if pos+2 == len(data) || isPDFWhitespace(data[pos+2]) {
+			end := pos
+			if end > start && isPDFWhitespace(data[end-1]) {
+				end--
+			}
+			return end
+		}
+	}
+	return len(data)
+}
+
+// #13 proses: gambar appearance stream normal (/AP /N) anotasi yang terlihat, BBox yang sudah ditransformasi Matrix dipetakan ke /Rect anotasi
+func (r *pdfRenderer) drawAnnotations(page pdfDict, base pdfMatrix) {
+	for _, item := range r.doc.array(page["Annots"]) {
+		annot := r.doc.dict(item)
+		if annot == nil || r.doc.name(annot["Subtype"]) == "Popup" {
+			continue
+		}
+		if flags := int(r.doc.number(annot["F"], 0)); flags&(2|32) != 0 {
+			continue
+		}
+		appearance := r.doc.resolve(r.doc.dict(annot["AP"])["N"])
+		if states, ok := appearance.(pdfDict); ok {
+			appearance = r.doc.resolve(states[r.doc.name(annot["AS"])])
+		}
+		stream, ok := appearance.(*pdfStream)
+		if !ok {
+			continue
+		}
+		rect := r.doc.numbers(annot["Rect"])
+		bbox := r.doc.numbers(stream.dict["BBox"])
+		if len(rect) != 4 || len(bbox) != 4 {
+			continue
+		}
+
+		formMatrix := pdfIdentity
+		if m, ok := matrixFromNumbers(r.doc.numbers(stream.dict["Matrix"])); ok {
+			formMatrix = m
+		}
+		minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+		for _, corner := range []pdfPoint{{bbox[0], bbox[1]}, {bbox[2], bbox[1]}, {bbox[0], bbox[3]}, {bbox[2], bbox[3]}} {
+			p := formMatrix.apply(corner)
+			minX, maxX = math.Min(minX, p.x), math.Max(maxX, p.x)
+			minY, maxY = math.Min(minY, p.y), math.Max(maxY, p.y)
+		}
+		if maxX-minX == 0 || maxY-minY == 0 {
+			continue
+		}
+		rx0, rx1 := math.Min(rect[0], rect[2]), math.Max(rect[0], rect[2])
+		ry0, ry1 := math.Min(rect[1], rect[3]), math.Max(rect[1], rect[3])
+		sx, sy := (rx1-rx0)/(maxX-minX), (ry1-ry0)/(maxY-minY)
+		fit := pdfMatrix{sx, 0, 0, sy, rx0 - minX*sx, ry0 - minY*sy}
+
+		saved := r.state
+		r.state.ctm = fit.multiply(base)
+		r.state.clip = nil
+		r.drawForm(stream, nil, 0)
+		r.state = saved
+	}
+}
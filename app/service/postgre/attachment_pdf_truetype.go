@@ -0,0 +1,435 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk encoding binary dan errors
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// #2 proses: font TrueType (FontFile2 atau tabel glyf di OpenType), hanya tabel yang dibutuhkan untuk outline dan lebar glyph yang dibaca
+type pdfTrueType struct {
+	data       []byte
+	tables     map[string][]byte
+	unitsPerEm float64
+	longLoca   bool
+	numGlyphs  int
+	numMetrics int
+	cmaps      []pdfTrueTypeCmap
+}
+
+type pdfTrueTypeCmap struct {
+	platform int
+	encoding int
+	table    []byte
+}
+
+const pdfMaxCompositeDepth = 8
+
+// #3 proses: parse direktori tabel sfnt, font dengan tabel CFF (OTTO) dikembalikan lewat tabel "CFF " untuk parser CFF
+func parseSFNT(data []byte) (*pdfTrueType, error) {
+	if len(data) < 12 {
+		return nil, errors.New("font sfnt terlalu pendek")
+	}
+	count := int(binary.BigEndian.Uint16(data[4:]))
+	font := &pdfTrueType{data: data, tables: map[string][]byte{}}
+	for i := 0; i < count; i++ {
+		record := 12 + i*16
+		if record+16 > len(data) {
+			break
+		}
+		tag := string(data[record : record+4])
+		offset := int(binary.BigEndian.Uint32(data[record+8:]))
+		length := int(binary.BigEndian.Uint32(data[record+12:]))
+		if offset < 0 || length < 0 || offset > len(data) {
+			continue
+		}
+		font.tables[tag] = data[offset:min(len(data), offset+length)]
+	}
+	return font, nil
+}
+
+// #3a proses: siapkan font TrueType untuk outline, tabel head, maxp, loca, dan glyf wajib ada
+func parseTrueType(data []byte) (*pdfTrueType, error) {
+	font, err := parseSFNT(data)
+	if err != nil {
+		return nil, err
+	}
+	head := font.tables["head"]
+	if len(head) < 54 || font.tables["glyf"] == nil || font.tables["loca"] == nil {
+		return nil, errors.New("tabel TrueType tidak lengkap")
+	}
+	font.unitsPerEm = float64(binary.BigEndian.Uint16(head[18:]))
+	if font.unitsPerEm == 0 {
+		font.unitsPerEm = 1000
+	}
+	font.longLoca = binary.BigEndian.Uint16(head[50:]) != 0
+	if maxp := font.tables["maxp"]; len(maxp) >= 6 {
+		font.numGlyphs = int(binary.BigEndian.Uint16(maxp[4:]))
+	}
+	if hhea := font.tables["hhea"]; len(hhea) >= 36 {
+		font.numMetrics = int(binary.BigEndian.Uint16(hhea[34:]))
+	}
+
+	// #3b proses: daftar subtable cmap, isi subtable dibaca saat lookup
+	if cmap := font.tables["cmap"]; len(cmap) >= 4 {
+		count := int(binary.BigEndian.Uint16(cmap[2:]))
+		for i := 0; i < count && 4+i*8+8 <= len(cmap); i++ {
+			record := cmap[4+i*8:]
+			offset := int(binary.BigEndian.Uint32(record[4:]))
+			if offset >= len(cmap) {
+				continue
+			}
+			font.cmaps = append(font.cmaps, pdfTrueTypeCmap{
+				platform: int(binary.BigEndian.Uint16(record[0:])),
+				encoding: int(binary.BigEndian.Uint16(record[2:])),
+				table:    cmap[offset:],
+			})
+		}
+	}
+	return font, nil
+}
+
+// #4 proses: cari glyph lewat subtable cmap dengan platform dan encoding tertentu, format 0, 4, 6, dan 12 didukung
+func (f *pdfTrueType) lookup(platform, encoding int, code int) (int, bool) {
+	for _, cmap := range f.cmaps {
+		if cmap.platform != platform || cmap.encoding != encoding {
+			continue
+		}
+		if gid := cmapLookup(cmap.table, code); gid > 0 {
+			return gid, true
+		}
+	}
+	return 0, false
+}
+
+func (f *pdfTrueType) hasCmap(platform, encoding int) bool {
+	for _, cmap := range f.cmaps {
+		if cmap.platform == platform && cmap.encoding == encoding {
+			return true
+		}
+	}
+	return false
+}
+
+func cmapLookup(table []byte, code int) int {
+	if len(table) < 4 {
+		return 0
+	}
+	u16 := func(offset int) int {
+		if offset < 0 || offset+2 > len(table) {
+			return 0
+		}
+		return int(binary.BigEndian.Uint16(table[offset:]))
+	}
+	u32 := func(offset int) int {
+		if offset < 0 || offset+4 > len(table) {
+			return 0
+		}
+		return int(binary.BigEndian.Uint32(table[offset:]))
+	}
+
+	switch u16(0) {
+	case 0:
+		if code >= 0 && code < 256 && 6+code < len(table) {
+			return int(table[6+code])
+		}
+	case 4:
+		segments := u16(6) / 2
+		endCodes := 14
+		startCodes := endCodes + segments*2 + 2
+		deltas := startCodes + segments*2
+		rangeOffsets := deltas + segments*2
+		for i := 0; i < segments; i++ {
+			if code > u16(endCodes+i*2) {
+				continue
+			}
+			start := u16(startCodes + i*2)
+			if code < start {
+				return 0
+			}
+			delta := u16(deltas + i*2)
+			rangeOffset := u16(rangeOffsets + i*2)
+			if rangeOffset == 0 {
+				return (code + delta) & 0xffff
+			}
+			gid := u16(rangeOffsets + i*2 + rangeOffset + (code-start)*2)
+			if gid == 0 {
+				return 0
+			}
+			return (gid + delta) & 0xffff
+		}
+	case 6:
+		first := u16(6)
+		count := u16(8)
+		if code >= first && code < first+count {
+			return u16(10 + (code-first)*2)
+		}
+	case 12:
+		groups := u32(12)
+		for i := 0; i < groups; i++ {
+			group := 16 + i*12
+			start, end := u32(group), u32(group+4)
+			if code >= start && code <= end {
+				return u32(group+8) + code - start
+			}
+		}
+	}
+	return 0
+}
+
+// #5 proses: lebar advance glyph dari hmtx dalam satuan 1/1000 em
+func (f *pdfTrueType) advance(gid int) (float64, bool) {
+	hmtx := f.tables["hmtx"]
+	if f.numMetrics == 0 || len(hmtx) < 4 {
+		return 0, false
+	}
+	index := min(gid, f.numMetrics-1)
+	if index*4+2 > len(hmtx) {
+		return 0, false
+	}
+	return float64(binary.BigEndian.Uint16(hmtx[index*4:])) * 1000 / f.unitsPerEm, true
+}
+
+// #6 proses: outline glyph dalam satuan 1/1000 em, kurva quadratic TrueType dengan titik off-curve beruntun dipecah di titik tengahnya
+func (f *pdfTrueType) outline(gid int) *pdfPath {
+	path := &pdfPath{}
+	scale := 1000 / f.unitsPerEm
+	f.appendGlyph(path, gid, pdfMatrix{scale, 0, 0, scale, 0, 0}, 0)
+	return path
+}
+
+func (f *pdfTrueType) glyphData(gid int) []byte {
+	loca := f.tables["loca"]
+	glyf := f.tables["glyf"]
+	var start, end int
+	if f.longLoca {
+		if gid < 0 || (gid+2)*4 > len(loca) {
+			return nil
+		}
+		start = int(binary.BigEndian.Uint32(loca[gid*4:]))
+		end = int(binary.BigEndian.Uint32(loca[gid*4+4:]))
+	} else {
+		if gid < 0 || (gid+2)*2 > len(loca) {
+			return nil
+		}
+		start = int(binary.BigEndian.Uint16(loca[gid*2:])) * 2
+		end = int(binary.BigEndian.Uint16(loca[gid*2+2:])) * 2
+	}
+	if start >= end || end > len(glyf) {
+		return nil
+	}
+	return glyf[start:end]
+}
+
+func (f *pdfTrueType) appendGlyph(path *pdfPath, gid int, m pdfMatrix, depth int) {
+	data := f.glyphData(gid)
+	if len(data) < 10 || depth > pdfMaxCompositeDepth {
+		return
+	}
+	contours := int(int16(binary.BigEndian.Uint16(data)))
+	if contours < 0 {
+		f.appendComposite(path, data[10:], m, depth)
+		return
+	}
+
+	// #6a proses: glyph sederhana berisi endPtsOfContours, instruksi (dilewati), flag dengan repeat, lalu koordinat x dan y delta
+	pos := 10
+	if pos+contours*2+2 > len(data) {
+		return
+	}
+	ends := make([]int, contours)
+	for i := range ends {
+		ends[i] = int(binary.BigEndian.Uint16(data[pos+i*2:]))
+	}
+	pos += contours * 2
+	pos += 2 + int(binary.BigEndian.Uint16(data[pos:]))
+	points := 0
+	if contours > 0 {
+		points = ends[contours-1] + 1
+	}
+	if points > 0xffff {
+		return
+	}
+
+	flags := make([]byte, 0, points)
+	for len(flags) < points && pos < len(data) {
+		flag := data[pos]
+		pos++
+		flags = append(flags, flag)
+		if flag&8 != 0 && pos < len(data) {
+			repeat := int(data[pos])
+			pos++
+			for r := 0; r < repeat && len(flags) < points; r++ {
+				flags = append(flags, flag)
+			}
+		}
+	}
+	if len(flags) < points {
+		return
+	}
+
+	readCoords := func(shortBit, sameBit byte) []int {
+		coords := make([]int, points)
+		value := 0
+		for i := 0; i < points; i++ {
+			flag := flags[i]
+			switch {
+			case flag&shortBit != 0:
+				if pos >= len(data) {
+					return nil
+				}
+				delta := int(data[pos])
+				pos++
+				if flag&sameBit == 0 {
+					delta = -delta
+				}
+				value += delta
+			case flag&sameBit == 0:
+				if pos+2 > len(data) {
+					return nil
+				}
+				value += int(int16(binary.BigEndian.Uint16(data[pos:])))
+				pos += 2
+			}
+			coords[i] = value
+		}
+		return coords
+	}
+	xs := readCoords(2, 16)
+	ys := readCoords(4, 32)
+	if xs == nil || ys == nil {
+		return
+	}
+
+	start := 0
+	for _, end := range ends {
+		if end < start || end >= points {
+			return
+		}
+		appendQuadContour(path, xs[start:end+1], ys[start:end+1], flags[start:end+1], m)
+		start = end + 1
+	}
+}
+
+// #6b proses: ubah satu kontur TrueType ke path, kontur yang diawali titik off-curve dimulai dari titik on-curve pertama atau titik tengah dua off-curve
+func appendQuadContour(path *pdfPath, xs, ys []int, flags []byte, m pdfMatrix) {
+	n := len(xs)
+	if n == 0 {
+		return
+	}
+	point := func(i int) pdfPoint {
+		i %= n
+		return m.apply(pdfPoint{float64(xs[i]), float64(ys[i])})
+	}
+	onCurve := func(i int) bool { return flags[i%n]&1 != 0 }
+
+	type contourPoint struct {
+		p  pdfPoint
+		on bool
+	}
+	first := -1
+	for i := 0; i < n; i++ {
+		if onCurve(i) {
+			first = i
+			break
+		}
+	}
+
+	// titik setelah titik awal sampai kembali ke titik awal
+	var start pdfPoint
+	sequence := make([]contourPoint, 0, n)
+	if first >= 0 {
+		start = point(first)
+		for k := 1; k < n; k++ {
+			sequence = append(sequence, contourPoint{point(first + k), onCurve(first + k)})
+		}
+	} else {
+		a, b := point(0), point(1)
+		start = pdfPoint{(a.x + b.x) / 2, (a.y + b.y) / 2}
+		for k := 1; k <= n; k++ {
+			sequence = append(sequence, contourPoint{point(k), false})
+		}
+	}
+
+	path.moveTo(start.x, start.y)
+	var control *pdfPoint
+	for _, item := range sequence {
+		p := item.p
+		if item.on {
+			if control != nil {
+				path.quadTo(control.x, control.y, p.x, p.y)
+			} else {
+				path.lineTo(p.x, p.y)
+			}
+			control = nil
+			continue
+		}
+		if control != nil {
+			mid := pdfPoint{(control.x + p.x) / 2, (control.y + p.y) / 2}
+			path.quadTo(control.x, control.y, mid.x, mid.y)
+		}
+		control = &p
+	}
+	if control != nil {
+		path.quadTo(control.x, control.y, start.x, start.y)
+	}
+	path.closePath()
+}
+
+// #6c proses: glyph komposit menggabungkan glyph lain dengan offset dan transformasi 2x2 opsional, offset berupa nomor titik (bukan xy) diabaikan
+func (f *pdfTrueType) appendComposite(path *pdfPath, data []byte, m pdfMatrix, depth int) {
+	pos := 0
+	for pos+4 <= len(data) {
+		flags := binary.BigEndian.Uint16(data[pos:])
+		gid := int(binary.BigEndian.Uint16(data[pos+2:]))
+		pos += 4
+
+		var dx, dy float64
+		if flags&1 != 0 {
+			if pos+4 > len(data) {
+				return
+			}
+			dx = float64(int16(binary.BigEndian.Uint16(data[pos:])))
+			dy = float64(int16(binary.BigEndian.Uint16(data[pos+2:])))
+			pos += 4
+		} else {
+			if pos+2 > len(data) {
+				return
+			}
+			dx = float64(int8(data[pos]))
+			dy = float64(int8(data[pos+1]))
+			pos += 2
+		}
+		if flags&2 == 0 {
+			dx, dy = 0, 0
+		}
+
+		f2dot14 := func() float64 {
+			if pos+2 > len(data) {
+				return 0
+			}
+			value := float64(int16(binary.BigEndian.Uint16(data[pos:]))) / 16384
+			pos += 2
+			return value
+		}
+		a, b, c, d := 1.0, 0.0, 0.0, 1.0
+		switch {
+		case flags&8 != 0:
+			a = f2dot14()
+			d = a
+		case flags&0x40 != 0:
+			a = f2dot14()
+			d = f2dot14()
+		case flags&0x80 != 0:
+			a = f2dot14()
+			b = f2dot14()
+			c = f2dot14()
+			d = f2dot14()
+		}
+
+		f.appendGlyph(path, gid, pdfMatrix{a, b, c, d, dx, dy}.multiply(m), depth+1)
+		if flags&0x20 == 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,497 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk bytes, encoding binary, dan errors
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+// #2 proses: font Type 1 (FontFile), bagian terenkripsi eexec berisi Subrs dan CharStrings per nama glyph
+type pdfType1 struct {
+	encoding    map[int]string
+	charStrings map[string][]byte
+	subrs       [][]byte
+	fontMatrix  pdfMatrix
+}
+
+// #3 proses: parse font Type 1, bagian clear text untuk encoding dan FontMatrix, bagian eexec untuk charstring
+func parseType1(data []byte) (*pdfType1, error) {
+	// #3a proses: format PFB (segmen diawali 0x80) diratakan dulu
+	if len(data) > 6 && data[0] == 0x80 {
+		data = flattenPFB(data)
+	}
+
+	index := bytes.Index(data, []byte("eexec"))
+	if index < 0 {
+		return nil, errors.New("bagian eexec font Type1 tidak ditemukan")
+	}
+	clear := data[:index]
+	encrypted := data[index+len("eexec"):]
+	for len(encrypted) > 0 && isPDFWhitespace(encrypted[0]) {
+		encrypted = encrypted[1:]
+	}
+	if isHexPrefix(encrypted) {
+		encrypted = decodePDFHex(encrypted)
+	}
+	private := decryptType1(encrypted, 55665, 4)
+
+	font := &pdfType1{
+		encoding:    parseType1Encoding(clear),
+		charStrings: map[string][]byte{},
+		fontMatrix:  pdfMatrix{0.001, 0, 0, 0.001, 0, 0},
+	}
+	if start := bytes.Index(clear, []byte("/FontMatrix")); start >= 0 {
+		lexer := &pdfLexer{data: clear, pos: start + len("/FontMatrix")}
+		if array, ok := lexer.object(0); ok {
+			if m, ok := matrixFromNumbers(pdfNumbers(array)); ok {
+				font.fontMatrix = m
+			}
+		}
+	}
+
+	lenIV := 4
+	if start := bytes.Index(private, []byte("/lenIV")); start >= 0 {
+		lexer := &pdfLexer{data: private, pos: start + len("/lenIV")}
+		if value, ok := lexer.token(); ok {
+			if number, isNumber := value.(float64); isNumber && number >= -1 {
+				lenIV = int(number)
+			}
+		}
+	}
+	decrypt := func(raw []byte) []byte {
+		if lenIV < 0 {
+			return raw
+		}
+		return decryptType1(raw, 4330, lenIV)
+	}
+
+	// #3b proses: Subrs berformat "dup i n RD <n byte> NP", CharStrings berformat "/nama n RD <n byte> ND"
+	if start := bytes.Index(private, []byte("/Subrs")); start >= 0 {
+		lexer := &pdfLexer{data: private, pos: start + len("/Subrs")}
+		for {
+			tok, ok := lexer.token()
+			if name, isName := tok.(pdfName); !ok || (isName && name == "CharStrings") {
+				break
+			}
+			if tok != pdfKeyword("dup") {
+				continue
+			}
+			indexTok, _ := lexer.token()
+			subrIndex, okIndex := indexTok.(float64)
+			raw := readType1Binary(lexer)
+			if !okIndex || raw == nil || subrIndex < 0 || subrIndex > 65535 {
+				continue
+			}
+			for len(font.subrs) <= int(subrIndex) {
+				font.subrs = append(font.subrs, nil)
+			}
+			font.subrs[int(subrIndex)] = decrypt(raw)
+		}
+	}
+	if start := bytes.Index(private, []byte("/CharStrings")); start >= 0 {
+		lexer := &pdfLexer{data: private, pos: start + len("/CharStrings")}
+		for {
+			tok, ok := lexer.token()
+			if !ok || tok == pdfKeyword("end") {
+				break
+			}
+			name, isName := tok.(pdfName)
+			if !isName {
+				continue
+			}
+			raw := readType1Binary(lexer)
+			if raw == nil {
+				continue
+			}
+			font.charStrings[string(name)] = decrypt(raw)
+		}
+	}
+	if len(font.charStrings) == 0 {
+		return nil, errors.New("CharStrings font Type1 kosong")
+	}
+	return font, nil
+}
+
+// #3c proses: baca "n RD " lalu n byte biner, RD bisa ditulis sebagai RD atau -|
+func readType1Binary(lexer *pdfLexer) []byte {
+	lengthTok, ok := lexer.token()
+	length, isNumber := lengthTok.(float64)
+	if !ok || !isNumber || length < 0 {
+		return nil
+	}
+	if _, ok := lexer.token(); !ok {
+		return nil
+	}
+	start := lexer.pos + 1
+	end := start + int(length)
+	if end > len(lexer.data) {
+		return nil
+	}
+	lexer.pos = end
+	return lexer.data[start:end]
+}
+
+func isHexPrefix(data []byte) bool {
+	if len(data) < 4 {
+		return false
+	}
+	for _, c := range data[:4] {
+		if _, ok := hexDigitValue(c); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// #3d proses: dekripsi eexec/charstring Type 1 dengan key r, byte awal sebanyak skip dibuang
+func decryptType1(data []byte, r uint16, skip int) []byte {
+	const c1, c2 = 52845, 22719
+	out := make([]byte, len(data))
+	for i, cipher := range data {
+		out[i] = cipher ^ byte(r>>8)
+		r = (uint16(cipher)+r)*c1 + c2
+	}
+	if skip > len(out) {
+		return nil
+	}
+	return out[skip:]
+}
+
+// #3e proses: gabungkan segmen PFB ASCII dan biner
+func flattenPFB(data []byte) []byte {
+	out := []byte{}
+	for pos := 0; pos+6 <= len(data) && data[pos] == 0x80; {
+		kind := data[pos+1]
+		if kind == 3 {
+			break
+		}
+		length := int(binary.LittleEndian.Uint32(data[pos+2:]))
+		start := pos + 6
+		end := min(len(data), start+length)
+		out = append(out, data[start:end]...)
+		pos = end
+	}
+	return out
+}
+
+// #3f proses: encoding bawaan font Type 1, StandardEncoding atau entri "dup kode /nama put"
+func parseType1Encoding(clear []byte) map[int]string {
+	encoding := map[int]string{}
+	start := bytes.Index(clear, []byte("/Encoding"))
+	if start < 0 {
+		return encoding
+	}
+	rest := clear[start+len("/Encoding"):]
+	lexer := &pdfLexer{data: rest}
+	if tok, _ := lexer.token(); tok == pdfKeyword("StandardEncoding") {
+		for code, name := range pdfStandardEncoding {
+			if name != "" {
+				encoding[code] = name
+			}
+		}
+		return encoding
+	}
+	for {
+		tok, ok := lexer.token()
+		if !ok || tok == pdfKeyword("readonly") || tok == pdfKeyword("def") {
+			return encoding
+		}
+		if tok != pdfKeyword("dup") {
+			continue
+		}
+		codeTok, _ := lexer.token()
+		nameTok, _ := lexer.token()
+		code, isCode := codeTok.(float64)
+		name, isName := nameTok.(pdfName)
+		if isCode && isName && code >= 0 && code < 256 {
+			encoding[int(code)] = string(name)
+		}
+	}
+}
+
+// #4 proses: outline glyph berdasarkan nama dalam satuan 1/1000 em
+func (f *pdfType1) outline(name string) *pdfPath {
+	code, ok := f.charStrings[name]
+	if !ok {
+		return nil
+	}
+	interpreter := &pdfType1Interpreter{font: f, path: &pdfPath{}, matrix: f.fontMatrix.multiply(pdfMatrix{1000, 0, 0, 1000, 0, 0})}
+	interpreter.run(code, 0)
+	interpreter.path.closePath()
+	return interpreter.path
+}
+
+// #4a proses: advance glyph dari operator hsbw atau sbw
+func (f *pdfType1) advance(name string) (float64, bool) {
+	code, ok := f.charStrings[name]
+	if !ok {
+		return 0, false
+	}
+	interpreter := &pdfType1Interpreter{font: f, path: &pdfPath{}, matrix: pdfIdentity, widthOnly: true}
+	interpreter.run(code, 0)
+	return interpreter.width * f.fontMatrix[0] * 1000, interpreter.hasWidth
+}
+
+// #5 proses: interpreter charstring Type 1, termasuk flex lewat OtherSubrs 0-2 dan seac untuk huruf beraksen
+type pdfType1Interpreter struct {
+	font      *pdfType1
+	path      *pdfPath
+	matrix    pdfMatrix
+	stack     []float64
+	psStack   []float64
+	x, y      float64
+	sbx       float64
+	width     float64
+	hasWidth  bool
+	widthOnly bool
+	flex      []pdfPoint
+	inFlex    bool
+	ops       int
+	done      bool
+}
+
+func (t *pdfType1Interpreter) point() pdfPoint {
+	return t.matrix.apply(pdfPoint{t.x, t.y})
+}
+
+func (t *pdfType1Interpreter) moveTo(dx, dy float64) {
+	t.x += dx
+	t.y += dy
+	if t.inFlex {
+		t.flex = append(t.flex, pdfPoint{t.x, t.y})
+		return
+	}
+	t.path.closePath()
+	p := t.point()
+	t.path.moveTo(p.x, p.y)
+}
+
+func (t *pdfType1Interpreter) lineTo(dx, dy float64) {
+	t.x += dx
+	t.y += dy
+	p := t.point()
+	t.path.lineTo(p.x, p.y)
+}
+
+func (t *pdfType1Interpreter) curveTo(dx1, dy1, dx2, dy2, dx3, dy3 float64) {
+	x1, y1 := t.x+dx1, t.y+dy1
+	x2, y2 := x1+dx2, y1+dy2
+	t.x, t.y = x2+dx3, y2+dy3
+	p1 := t.matrix.apply(pdfPoint{x1, y1})
+	p2 := t.matrix.apply(pdfPoint{x2, y2})
+	p3 := t.point()
+	t.path.curveTo(p1.x, p1.y, p2.x, p2.y, p3.x, p3.y)
+}
+
+func (t *pdfType1Interpreter) run(code []byte, depth int) {
+	if depth > pdfMaxCharstringDepth {
+		t.done = true
+		return
+	}
+	for pos := 0; pos < len(code) && !t.done; {
+		t.ops++
+		if t.ops > pdfMaxCharstringOps {
+			t.done = true
+			return
+		}
+		v := int(code[pos])
+		pos++
+
+		switch {
+		case v >= 32 && v <= 246:
+			t.push(float64(v - 139))
+			continue
+		case v >= 247 && v <= 250:
+			if pos >= len(code) {
+				return
+			}
+			t.push(float64((v-247)*256 + int(code[pos]) + 108))
+			pos++
+			continue
+		case v >= 251 && v <= 254:
+			if pos >= len(code) {
+				return
+			}
+			t.push(float64(-(v-251)*256 - int(code[pos]) - 108))
+			pos++
+			continue
+		case v == 255:
+			if pos+4 > len(code) {
+				return
+			}
+			t.push(float64(int32(binary.BigEndian.Uint32(code[pos:]))))
+			pos += 4
+			continue
+		}
+
+		s := t.stack
+		arg := func(i int) float64 {
+			if i < len(s) {
+				return s[i]
+			}
+			return 0
+		}
+		clearStack := true
+		switch v {
+		case 13: // hsbw
+			t.sbx = arg(0)
+			t.x, t.y = arg(0), 0
+			t.width, t.hasWidth = arg(1), true
+			if t.widthOnly {
+				t.done = true
+				return
+			}
+		case 21: // rmoveto
+			t.moveTo(arg(0), arg(1))
+		case 22: // hmoveto
+			t.moveTo(arg(0), 0)
+		case 4: // vmoveto
+			t.moveTo(0, arg(0))
+		case 5: // rlineto
+			t.lineTo(arg(0), arg(1))
+		case 6: // hlineto
+			t.lineTo(arg(0), 0)
+		case 7: // vlineto
+			t.lineTo(0, arg(0))
+		case 8: // rrcurveto
+			t.curveTo(arg(0), arg(1), arg(2), arg(3), arg(4), arg(5))
+		case 30: // vhcurveto
+			t.curveTo(0, arg(0), arg(1), arg(2), arg(3), 0)
+		case 31: // hvcurveto
+			t.curveTo(arg(0), 0, arg(1), arg(2), 0, arg(3))
+		case 9: // closepath
+			t.path.closePath()
+		case 10: // callsubr
+			if len(s) == 0 {
+				return
+			}
+			index := int(s[len(s)-1])
+			t.stack = s[:len(s)-1]
+			if index >= 0 && index < len(t.font.subrs) && t.font.subrs[index] != nil {
+				t.run(t.font.subrs[index], depth+1)
+			}
+			clearStack = false
+		case 11: // return
+			return
+		case 14: // endchar
+			t.path.closePath()
+			t.done = true
+			return
+		case 12:
+			if pos >= len(code) {
+				return
+			}
+			clearStack = t.escape(int(code[pos]), depth)
+			pos++
+		}
+		if clearStack {
+			t.stack = t.stack[:0]
+		}
+	}
+}
+
+func (t *pdfType1Interpreter) push(v float64) {
+	if len(t.stack) < 48 {
+		t.stack = append(t.stack, v)
+	}
+}
+
+// #5a proses: operator escape Type 1, return false jika stack tidak boleh dibersihkan (div, pop, callothersubr)
+func (t *pdfType1Interpreter) escape(op int, depth int) bool {
+	s := t.stack
+	arg := func(i int) float64 {
+		if i < len(s) {
+			return s[i]
+		}
+		return 0
+	}
+	switch op {
+	case 7: // sbw
+		t.sbx = arg(0)
+		t.x, t.y = arg(0), arg(1)
+		t.width, t.hasWidth = arg(2), true
+		if t.widthOnly {
+			t.done = true
+		}
+	case 6: // seac
+		t.seac(arg(0), arg(1), arg(2), int(arg(3)), int(arg(4)), depth)
+		t.done = true
+	case 12: // div
+		if len(s) >= 2 {
+			b, a := s[len(s)-1], s[len(s)-2]
+			t.stack = s[:len(s)-2]
+			if b != 0 {
+				t.push(a / b)
+			} else {
+				t.push(0)
+			}
+		}
+		return false
+	case 16: // callothersubr
+		if len(s) < 2 {
+			return true
+		}
+		other := int(s[len(s)-1])
+		count := int(s[len(s)-2])
+		rest := s[:len(s)-2]
+		if count < 0 || count > len(rest) {
+			count = len(rest)
+		}
+		args := rest[len(rest)-count:]
+		t.stack = rest[:len(rest)-count]
+		switch other {
+		case 1: // mulai flex
+			t.inFlex = true
+			t.flex = t.flex[:0]
+		case 2: // titik flex ditambahkan oleh rmoveto
+		case 0: // akhir flex: 7 titik, titik pertama adalah titik referensi
+			t.inFlex = false
+			if len(t.flex) >= 7 {
+				pts := t.flex
+				for _, segment := range [][3]pdfPoint{{pts[1], pts[2], pts[3]}, {pts[4], pts[5], pts[6]}} {
+					p1 := t.matrix.apply(segment[0])
+					p2 := t.matrix.apply(segment[1])
+					p3 := t.matrix.apply(segment[2])
+					t.path.curveTo(p1.x, p1.y, p2.x, p2.y, p3.x, p3.y)
+				}
+				t.x, t.y = pts[6].x, pts[6].y
+			}
+			t.psStack = []float64{t.y, t.x}
+			return false
+		}
+		// othersubr lain (hint replacement) mengembalikan argumennya lewat pop
+		t.psStack = t.psStack[:0]
+		for i := len(args) - 1; i >= 0; i-- {
+			t.psStack = append(t.psStack, args[i])
+		}
+		return false
+	case 17: // pop
+		if len(t.psStack) > 0 {
+			t.push(t.psStack[len(t.psStack)-1])
+			t.psStack = t.psStack[:len(t.psStack)-1]
+		}
+		return false
+	case 33: // setcurrentpoint
+		t.x, t.y = arg(0), arg(1)
+	}
+	return true
+}
+
+// #5b proses: seac Type 1, glyph aksen digeser (adx - asb, ady) dari origin glyph dasar
+func (t *pdfType1Interpreter) seac(asb, adx, ady float64, baseCode, accentCode int, depth int) {
+	draw := func(code int, dx, dy float64) {
+		if code < 0 || code > 255 {
+			return
+		}
+		charstring, ok := t.font.charStrings[pdfStandardEncoding[code]]
+		if !ok {
+			return
+		}
+		sub := &pdfType1Interpreter{font: t.font, path: t.path, matrix: pdfMatrix{1, 0, 0, 1, dx, dy}.multiply(t.matrix), ops: t.ops}
+		sub.run(charstring, depth+1)
+		t.ops = sub.ops
+	}
+	draw(baseCode, 0, 0)
+	draw(accentCode, adx-asb, ady)
+}
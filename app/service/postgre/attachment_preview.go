@@ -0,0 +1,125 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk bytes, errors, image, io, dan math
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+)
+
+// #2 proses: konfigurasi preview attachment, sisi terpanjang preview dan batas piksel gambar asal supaya gambar raksasa tidak menghabiskan memori
+const (
+	attachmentPreviewSuffix      = ".preview.jpg"
+	attachmentPreviewContentType = "image/jpeg"
+	attachmentPreviewMaxSide     = 320
+	attachmentPreviewQuality     = 80
+	attachmentPreviewMaxPixels   = 40 * 1000 * 1000
+)
+
+// #3 proses: key storage preview disimpan di samping file asli dengan akhiran tetap, sehingga cukup ditandai HasPreview di attachment
+func attachmentPreviewKey(key string) string {
+	return key + attachmentPreviewSuffix
+}
+
+// #4 proses: buat preview JPEG dari isi file attachment, return nil tanpa error jika tipe file tidak punya preview atau PDF tidak punya halaman
+func renderAttachmentPreview(fileType string, content io.Reader) ([]byte, error) {
+	var source []byte
+	switch fileType {
+	case "image/jpeg", "image/png":
+		data, err := io.ReadAll(content)
+		if err != nil {
+			return nil, errors.New("error membaca file: " + err.Error())
+		}
+		source = data
+	case "application/pdf":
+		// #4a proses: halaman pertama PDF dirasterisasi langsung seukuran preview sehingga tidak perlu diperkecil lagi
+		data, err := io.ReadAll(content)
+		if err != nil {
+			return nil, errors.New("error membaca file: " + err.Error())
+		}
+		page, err := renderPDFFirstPage(data, attachmentPreviewMaxSide)
+		if err != nil {
+			return nil, errors.New("error render halaman PDF: " + err.Error())
+		}
+		if page == nil {
+			return nil, nil
+		}
+		return encodeAttachmentPreview(page)
+	default:
+		return nil, nil
+	}
+
+	// #4b proses: cek dimensi sebelum decode supaya gambar dengan header raksasa ditolak tanpa mengalokasikan piksel
+	config, _, err := image.DecodeConfig(bytes.NewReader(source))
+	if err != nil {
+		return nil, errors.New("error membaca dimensi gambar: " + err.Error())
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > attachmentPreviewMaxPixels {
+		return nil, errors.New("dimensi gambar terlalu besar untuk dibuat preview")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(source))
+	if err != nil {
+		return nil, errors.New("error decode gambar: " + err.Error())
+	}
+
+	// #4c proses: perkecil gambar lalu simpan sebagai JPEG
+	return encodeAttachmentPreview(scalePreviewImage(img, attachmentPreviewMaxSide))
+}
+
+// #5 proses: encode bitmap preview sebagai JPEG
+func encodeAttachmentPreview(img image.Image) ([]byte, error) {
+	var out bytes.Buffer
+	if err := jpeg.Encode(&out, img, &jpeg.Options{Quality: attachmentPreviewQuality}); err != nil {
+		return nil, errors.New("error encode preview: " + err.Error())
+	}
+	return out.Bytes(), nil
+}
+
+// #6 proses: perkecil gambar dengan rata-rata beberapa titik sampel per piksel tujuan, piksel transparan PNG digabung dengan latar putih karena JPEG tidak punya alpha
+func scalePreviewImage(src image.Image, maxSide int) *image.RGBA {
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	scale := math.Max(float64(width), float64(height)) / float64(maxSide)
+	if scale < 1 {
+		scale = 1
+	}
+	dstWidth := max(1, int(math.Round(float64(width)/scale)))
+	dstHeight := max(1, int(math.Round(float64(height)/scale)))
+
+	// #6a proses: jumlah sampel per sumbu mengikuti skala, dibatasi 4 supaya gambar besar tetap cepat
+	samples := min(4, max(1, int(math.Ceil(scale))))
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		for x := 0; x < dstWidth; x++ {
+			var r, g, b uint32
+			for sy := 0; sy < samples; sy++ {
+				srcY := bounds.Min.Y + min(height-1, int((float64(y)+(float64(sy)+0.5)/float64(samples))*float64(height)/float64(dstHeight)))
+				for sx := 0; sx < samples; sx++ {
+					srcX := bounds.Min.X + min(width-1, int((float64(x)+(float64(sx)+0.5)/float64(samples))*float64(width)/float64(dstWidth)))
+					pr, pg, pb, pa := src.At(srcX, srcY).RGBA()
+					white := 0xffff - pa
+					r += pr + white
+					g += pg + white
+					b += pb + white
+				}
+			}
+
+			n := uint32(samples * samples)
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8((r / n) >> 8),
+				G: uint8((g / n) >> 8),
+				B: uint8((b / n) >> 8),
+				A: 0xff,
+			})
+		}
+	}
+	return dst
+}
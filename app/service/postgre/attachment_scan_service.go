@@ -0,0 +1,220 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk context, errors, fmt, model, repository, dan time
+import (
+	"context"
+	"errors"
+	"fmt"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	repositoryscanner "sistem-pelaporan-prestasi-mahasiswa/app/repository/scanner"
+	repositorystorage "sistem-pelaporan-prestasi-mahasiswa/app/repository/storage"
+	"time"
+)
+
+// #2 proses: jumlah prestasi dengan attachment pending yang diambil per putaran scan
+const attachmentScanBatchSize = 50
+
+// #3 proses: definisikan interface untuk pemindaian malware attachment yang masih pending
+type IAttachmentScanService interface {
+	ScanPendingAttachments(ctx context.Context) (*modelmongo.AttachmentScanRunResult, error)
+}
+
+// #4 proses: struct service scan attachment dengan dependency repository, notification service, storage attachment, storage karantina, dan scanner
+type AttachmentScanService struct {
+	achievementRepo     repositorymongo.IAchievementRepository
+	achievementRefRepo  repositorypostgre.IAchievementReferenceRepository
+	notificationService INotificationService
+	fileStorage         repositorystorage.IFileStorage
+	quarantineStorage   repositorystorage.IFileStorage
+	scanner             repositoryscanner.IMalwareScanner
+}
+
+// #5 proses: constructor untuk membuat instance AttachmentScanService baru
+func NewAttachmentScanService(
+	achievementRepo repositorymongo.IAchievementRepository,
+	achievementRefRepo repositorypostgre.IAchievementReferenceRepository,
+	notificationService INotificationService,
+	fileStorage repositorystorage.IFileStorage,
+	quarantineStorage repositorystorage.IFileStorage,
+	scanner repositoryscanner.IMalwareScanner,
+) IAttachmentScanService {
+	return &AttachmentScanService{
+		achievementRepo:     achievementRepo,
+		achievementRefRepo:  achievementRefRepo,
+		notificationService: notificationService,
+		fileStorage:         fileStorage,
+		quarantineStorage:   quarantineStorage,
+		scanner:             scanner,
+	}
+}
+
+// #6 proses: baca interval scan attachment dari environment variable, default 10 detik
+func LoadAttachmentScanInterval() time.Duration {
+	return time.Duration(envInt("ATTACHMENT_SCAN_INTERVAL_SECONDS", 10, 0)) * time.Second
+}
+
+// #7 proses: jalankan scan attachment pending secara berkala di background, upload baru menunggu paling lama satu interval sebelum dipindai
+func StartAttachmentScanScheduler(ctx context.Context, scanService IAttachmentScanService, interval time.Duration) {
+	if interval <= 0 {
+		fmt.Println("Attachment scan scheduler dinonaktifkan")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			// #7a proses: satu putaran scan diberi timeout supaya file yang macet tidak menahan scan berikutnya selamanya
+			scanCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+			result, err := scanService.ScanPendingAttachments(scanCtx)
+			cancel()
+
+			if err != nil {
+				fmt.Printf("Error scanning attachments: %v\n", err)
+			} else if result.Scanned > 0 {
+				fmt.Printf("Attachment scan: %d scanned, %d clean, %d infected, %d failed\n", result.Scanned, result.Clean, result.Infected, result.Failed)
+			}
+
+			// #7b proses: tunggu tick berikutnya atau berhenti jika context dibatalkan
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// #8 proses: pindai semua attachment pending, attachment yang gagal dipindai tetap pending dan dicoba lagi pada putaran berikutnya
+func (s *AttachmentScanService) ScanPendingAttachments(ctx context.Context) (*modelmongo.AttachmentScanRunResult, error) {
+	// #8a proses: ambil prestasi yang punya attachment pending
+	achievements, err := s.achievementRepo.GetAchievementsByAttachmentScanStatus(ctx, modelmongo.AttachmentScanPending, attachmentScanBatchSize)
+	if err != nil {
+		return nil, errors.New("error mengambil attachment pending dari database: " + err.Error())
+	}
+
+	result := &modelmongo.AttachmentScanRunResult{}
+	for i := range achievements {
+		for _, attachment := range achievements[i].Attachments {
+			if attachment.ScanStatus != modelmongo.AttachmentScanPending {
+				continue
+			}
+
+			// #8b proses: hitung ringkasan per status
+			result.Scanned++
+			status, err := s.scanAttachment(ctx, &achievements[i], attachment)
+			if err != nil {
+				fmt.Printf("Error scanning attachment %s of achievement %s: %v\n", attachment.FileURL, achievements[i].ID.Hex(), err)
+				result.Failed++
+				continue
+			}
+
+			switch status {
+			case modelmongo.AttachmentScanClean:
+				result.Clean++
+			case modelmongo.AttachmentScanInfected:
+				result.Infected++
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// #9 proses: pindai satu attachment dan simpan hasilnya, status kosong berarti attachment sudah berubah sejak daftar diambil
+func (s *AttachmentScanService) scanAttachment(ctx context.Context, achievement *modelmongo.Achievement, attachment modelmongo.Attachment) (string, error) {
+	// #9a proses: baca file dari storage lalu kirim ke scanner
+	key, ok := s.fileStorage.KeyFromURL(attachment.FileURL)
+	if !ok {
+		return "", errors.New("URL attachment bukan milik storage yang aktif")
+	}
+
+	body, _, err := s.fileStorage.Get(ctx, key)
+	if err != nil {
+		return "", errors.New("error membaca file attachment: " + err.Error())
+	}
+	scanResult, err := s.scanner.Scan(ctx, body)
+	body.Close()
+	if err != nil {
+		return "", fmt.Errorf("error memindai file dengan %s: %w", s.scanner.Name(), err)
+	}
+
+	// #9b proses: file bersih langsung ditandai clean dan bisa didownload dosen wali dan admin
+	if !scanResult.Infected {
+		updated, err := s.achievementRepo.UpdateAttachmentScanResult(ctx, achievement.ID.Hex(), attachment.FileURL, modelmongo.AttachmentScanClean, "", "")
+		if err != nil {
+			return "", errors.New("error menyimpan hasil scan: " + err.Error())
+		}
+		if !updated {
+			return "", nil
+		}
+		return modelmongo.AttachmentScanClean, nil
+	}
+
+	// #9c proses: file terinfeksi dipindah ke karantina dan mahasiswa diberi tahu
+	if err := s.quarantineAttachment(ctx, achievement, attachment, key, scanResult.Signature); err != nil {
+		return "", err
+	}
+	return modelmongo.AttachmentScanInfected, nil
+}
+
+// #10 proses: salin file terinfeksi ke storage karantina, ganti URL attachment ke karantina, lalu hapus file asal. File asal tetap tidak bisa didownload selama status masih pending jika salah satu langkah gagal
+func (s *AttachmentScanService) quarantineAttachment(ctx context.Context, achievement *modelmongo.Achievement, attachment modelmongo.Attachment, key string, signature string) error {
+	mongoID := achievement.ID.Hex()
+
+	// #10a proses: salin file ke storage karantina
+	body, info, err := s.fileStorage.Get(ctx, key)
+	if err != nil {
+		return errors.New("error membaca file attachment untuk karantina: " + err.Error())
+	}
+	err = s.quarantineStorage.Put(ctx, key, body, info.Size, attachment.FileType)
+	body.Close()
+	if err != nil {
+		return errors.New("error menyimpan file ke karantina: " + err.Error())
+	}
+
+	// #10b proses: simpan status infected dan URL karantina, salinan karantina dihapus lagi jika attachment sudah tidak pending dan salinan tersebut tidak dipakai attachment lain dengan isi yang sama
+	quarantineURL := s.quarantineStorage.URL(key)
+	updated, err := s.achievementRepo.UpdateAttachmentScanResult(ctx, mongoID, attachment.FileURL, modelmongo.AttachmentScanInfected, signature, quarantineURL)
+	if err != nil || !updated {
+		if count, countErr := s.achievementRepo.CountAttachmentFileReferences(context.WithoutCancel(ctx), quarantineURL); countErr == nil && count == 0 {
+			if deleteErr := s.quarantineStorage.Delete(context.WithoutCancel(ctx), key); deleteErr != nil {
+				fmt.Printf("Error removing quarantine copy %s: %v\n", key, deleteErr)
+			}
+		}
+		if err != nil {
+			return errors.New("error menyimpan hasil scan: " + err.Error())
+		}
+		return nil
+	}
+
+	// #10c proses: hapus file asal beserta preview-nya jika tidak ada attachment lain yang memakai file yang sama, preview tidak ikut dikarantina. Gagal hapus hanya dicatat karena URL attachment sudah menunjuk ke karantina
+	count, err := s.achievementRepo.CountAttachmentFileReferences(ctx, attachment.FileURL)
+	if err != nil {
+		fmt.Printf("Error counting references to infected attachment %s: %v\n", key, err)
+	} else if count == 0 {
+		if err := s.fileStorage.Delete(ctx, key); err != nil {
+			fmt.Printf("Error removing infected attachment %s: %v\n", key, err)
+		}
+		if attachment.HasPreview {
+			if err := s.fileStorage.Delete(ctx, attachmentPreviewKey(key)); err != nil {
+				fmt.Printf("Error removing infected attachment preview %s: %v\n", key, err)
+			}
+		}
+	}
+
+	// #10d proses: kirim notifikasi ke mahasiswa pemilik prestasi, gagal kirim notifikasi tidak membatalkan karantina
+	ref, err := s.achievementRefRepo.GetAchievementReferenceByMongoID(ctx, mongoID)
+	if err != nil {
+		fmt.Printf("Error getting achievement reference for infected attachment notification: %v\n", err)
+		return nil
+	}
+	if err := s.notificationService.CreateAttachmentInfectedNotification(ctx, ref.StudentID, mongoID, ref.ID, attachment.FileName); err != nil {
+		fmt.Printf("Error creating infected attachment notification: %v\n", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,227 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk context, errors, fmt, os, sort, strconv, strings, model, repository, dan time
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// #2 proses: definisikan interface untuk pemantauan masa berlaku sertifikasi
+type ICertificationService interface {
+	RunExpiryCheck(ctx context.Context) (*modelpostgre.CertificationExpiryCheckResult, error)
+}
+
+// #3 proses: struct service untuk masa berlaku sertifikasi dengan dependency achievement reference, achievement MongoDB, dan notification service
+type CertificationService struct {
+	achievementRefRepo  repositorypostgre.IAchievementReferenceRepository
+	achievementRepo     repositorymongo.IAchievementRepository
+	notificationService INotificationService
+	config              modelpostgre.CertificationExpiryConfig
+}
+
+// #4 proses: constructor untuk membuat instance CertificationService baru
+func NewCertificationService(
+	achievementRefRepo repositorypostgre.IAchievementReferenceRepository,
+	achievementRepo repositorymongo.IAchievementRepository,
+	notificationService INotificationService,
+	config modelpostgre.CertificationExpiryConfig,
+) ICertificationService {
+	return &CertificationService{
+		achievementRefRepo:  achievementRefRepo,
+		achievementRepo:     achievementRepo,
+		notificationService: notificationService,
+		config:              config,
+	}
+}
+
+// #5 proses: baca konfigurasi masa berlaku sertifikasi dari environment variable, nilai kosong atau tidak valid memakai default
+func LoadCertificationExpiryConfig() (modelpostgre.CertificationExpiryConfig, error) {
+	// #5a proses: parse daftar hari peringatan, contoh "30,7", angka tidak valid dilewati
+	alertDays := []int{}
+	seen := map[int]bool{}
+	for _, part := range strings.Split(os.Getenv("CERTIFICATION_EXPIRY_ALERT_DAYS"), ",") {
+		days, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || days < 1 || seen[days] {
+			continue
+		}
+		seen[days] = true
+		alertDays = append(alertDays, days)
+	}
+	if len(alertDays) == 0 {
+		alertDays = []int{30, 7}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(alertDays)))
+
+	excludeExpired, err := strconv.ParseBool(strings.TrimSpace(os.Getenv("CERTIFICATION_EXCLUDE_EXPIRED_POINTS")))
+	if err != nil {
+		excludeExpired = false
+	}
+
+	// #5b proses: validasi sekali saat load supaya service yang memakai AlertDays[0] tidak perlu memeriksa ulang
+	config := modelpostgre.CertificationExpiryConfig{
+		AlertDays:            alertDays,
+		CheckInterval:        time.Duration(envInt("CERTIFICATION_EXPIRY_CHECK_INTERVAL_HOURS", 24, 0)) * time.Hour,
+		ExcludeExpiredPoints: excludeExpired,
+	}
+	if err := ValidateCertificationExpiryConfig(config); err != nil {
+		return modelpostgre.CertificationExpiryConfig{}, err
+	}
+	return config, nil
+}
+
+// #5c proses: AlertDays wajib berisi minimal satu batas hari positif yang urut dari yang terbesar tanpa duplikat
+func ValidateCertificationExpiryConfig(config modelpostgre.CertificationExpiryConfig) error {
+	if len(config.AlertDays) == 0 {
+		return errors.New("konfigurasi sertifikasi tidak valid: batas hari peringatan tidak boleh kosong")
+	}
+	for i, days := range config.AlertDays {
+		if days < 1 {
+			return errors.New("konfigurasi sertifikasi tidak valid: batas hari peringatan harus lebih dari 0")
+		}
+		if i > 0 && days >= config.AlertDays[i-1] {
+			return errors.New("konfigurasi sertifikasi tidak valid: batas hari peringatan harus urut dari yang terbesar")
+		}
+	}
+	return nil
+}
+
+// #6 proses: jalankan pengecekan masa berlaku sertifikasi secara berkala di background, interval 0 berarti scheduler dinonaktifkan
+func StartCertificationExpiryScheduler(ctx context.Context, certificationService ICertificationService, interval time.Duration) {
+	if interval <= 0 {
+		fmt.Println("Certification expiry scheduler dinonaktifkan")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			// #6a proses: setiap pengecekan diberi timeout supaya tidak menumpuk dengan pengecekan berikutnya
+			checkCtx, cancel := context.WithTimeout(ctx, interval)
+			result, err := certificationService.RunExpiryCheck(checkCtx)
+			cancel()
+
+			if err != nil {
+				fmt.Printf("Error running certification expiry check: %v\n", err)
+			} else if result.Notified > 0 {
+				fmt.Printf("Certification expiry check: %d checked, %d notified\n", result.Checked, result.Notified)
+			}
+
+			// #6b proses: tunggu tick berikutnya atau berhenti jika context dibatalkan
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// #7 proses: kirim notifikasi ke mahasiswa untuk sertifikasi yang akan berakhir, setiap batas hari hanya dikirim sekali per tanggal berlaku
+func (s *CertificationService) RunExpiryCheck(ctx context.Context) (*modelpostgre.CertificationExpiryCheckResult, error) {
+	result := &modelpostgre.CertificationExpiryCheckResult{}
+	if len(s.config.AlertDays) == 0 {
+		return result, nil
+	}
+
+	// #7a proses: ambil sertifikasi yang berakhir dalam rentang batas hari terbesar
+	now := time.Now()
+	today := dateOnly(now)
+	achievements, err := s.achievementRepo.GetAchievementsValidUntilBetween(ctx, today, today.AddDate(0, 0, s.config.AlertDays[0]+1))
+	if err != nil {
+		return nil, errors.New("error mengambil sertifikasi yang akan berakhir: " + err.Error())
+	}
+
+	for _, achievement := range achievements {
+		if achievement.Details.ValidUntil == nil {
+			continue
+		}
+		result.Checked++
+
+		// #7b proses: pilih batas hari terkecil yang sudah terlewati, batas yang lebih besar dianggap sudah lewat
+		validUntil := dateOnly(*achievement.Details.ValidUntil)
+		daysLeft := certificationDaysLeft(validUntil, now)
+		daysBefore := 0
+		for _, days := range s.config.AlertDays {
+			if daysLeft >= 0 && daysLeft <= days {
+				daysBefore = days
+			}
+		}
+		if daysBefore == 0 {
+			continue
+		}
+
+		// #7c proses: prestasi yang dihapus tidak perlu diperingatkan
+		mongoID := achievement.ID.Hex()
+		ref, err := s.achievementRefRepo.GetAchievementReferenceByMongoID(ctx, mongoID)
+		if err != nil || ref == nil || ref.Status == modelpostgre.AchievementStatusDeleted {
+			continue
+		}
+
+		// #7d proses: tandai peringatan lebih dulu supaya pengecekan paralel tidak mengirim dua kali
+		claimed, err := s.achievementRefRepo.ClaimCertificationExpiryAlert(ctx, ref.ID, validUntil, daysBefore)
+		if err != nil {
+			fmt.Printf("Error claiming certification expiry alert: %v\n", err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		// #7e proses: kirim notifikasi, jika gagal hapus tanda supaya dicoba lagi pada pengecekan berikutnya
+		if err := s.notificationService.CreateCertificationExpiryNotification(ctx, ref.StudentID, mongoID, ref.ID, validUntil, daysLeft); err != nil {
+			fmt.Printf("Error creating certification expiry notification: %v\n", err)
+			if releaseErr := s.achievementRefRepo.ReleaseCertificationExpiryAlert(ctx, ref.ID, validUntil, daysBefore); releaseErr != nil {
+				fmt.Printf("Error releasing certification expiry alert: %v\n", releaseErr)
+			}
+			continue
+		}
+
+		result.Notified++
+	}
+
+	return result, nil
+}
+
+// #8 proses: tentukan status masa berlaku sertifikasi, kosong jika prestasi tidak punya validUntil
+func certificationStatus(details modelmongo.AchievementDetails, now time.Time, soonDays int) string {
+	if details.ValidUntil == nil {
+		return ""
+	}
+
+	daysLeft := certificationDaysLeft(*details.ValidUntil, now)
+	if daysLeft < 0 {
+		return modelpostgre.CertificationStatusExpired
+	}
+	if daysLeft <= soonDays {
+		return modelpostgre.CertificationStatusExpiringSoon
+	}
+	return modelpostgre.CertificationStatusValid
+}
+
+// #9 proses: hitung sisa hari kalender sampai validUntil, negatif jika sudah lewat
+func certificationDaysLeft(validUntil time.Time, now time.Time) int {
+	return int(dateOnly(validUntil).Sub(dateOnly(now)).Hours() / 24)
+}
+
+// #10 proses: potong waktu jadi tengah malam UTC supaya perbandingan tanggal tidak terpengaruh jam
+func dateOnly(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// #11 proses: batas hari untuk status expiring_soon mengikuti batas peringatan terbesar, AlertDays sudah divalidasi saat load
+func certificationSoonDays(config modelpostgre.CertificationExpiryConfig) int {
+	return config.AlertDays[0]
+}
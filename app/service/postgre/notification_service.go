@@ -1,23 +1,34 @@
 package service
 
-// #1 proses: import library yang diperlukan untuk context, database, errors, dan repository
+// #1 proses: import library yang diperlukan untuk context, database, errors, fmt, repository, helper, dan time
 import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
 	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
 	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
 	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+	"time"
 )
 
 // #2 proses: definisikan interface untuk operasi notifikasi
 type INotificationService interface {
 	GetNotifications(ctx context.Context, userID string, page, limit int) (*modelpostgre.GetNotificationsResponse, error)
+	GetNotificationsByCursor(ctx context.Context, userID string, cursor *modelpostgre.PageCursor, limit int) (*modelpostgre.GetNotificationsCursorResponse, error)
 	GetUnreadCount(ctx context.Context, userID string) (*modelpostgre.GetUnreadCountResponse, error)
 	MarkAsRead(ctx context.Context, notificationID string, userID string) (*modelpostgre.MarkAsReadResponse, error)
 	MarkAllAsRead(ctx context.Context, userID string) (*modelpostgre.MarkAllAsReadResponse, error)
 	CreateAchievementNotification(ctx context.Context, studentUserID string, mongoAchievementID string, achievementRefID string, rejectionNote string) error
 	CreateSubmissionNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string) error
+	CreateTeamInvitationNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string) error
+	RetractSubmissionNotification(ctx context.Context, achievementRefID string) error
+	CreateSLAReminderNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string, waitingDays int) error
+	CreateSLAEscalationNotification(ctx context.Context, recipientUserIDs []string, mongoAchievementID string, achievementRefID string, waitingDays int) error
+	CreateCertificationExpiryNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string, validUntil time.Time, daysLeft int) error
+	CreateAttachmentInfectedNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string, fileName string) error
 }
 
 // #3 proses: struct service untuk notifikasi dengan dependency notification, student, user, dan achievement repository
@@ -78,6 +89,12 @@ func (s *NotificationService) GetNotifications(ctx context.Context, userID strin
 	response.Pagination.Total = total
 	response.Pagination.TotalPages = totalPages
 
+	// #5e proses: sertakan cursor dari item terakhir supaya client bisa lanjut dengan mode cursor
+	if len(notifications) > 0 && page*limit < total {
+		last := notifications[len(notifications)-1]
+		response.Pagination.NextCursor = helper.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
 	return response, nil
 }
 
@@ -193,26 +210,61 @@ func (s *NotificationService) CreateAchievementNotification(ctx context.Context,
 	return err
 }
 
-// #10 proses: buat notifikasi untuk dosen wali ketika mahasiswa submit prestasi
+// #10 proses: buat notifikasi untuk dosen wali ketika mahasiswa submit prestasi, prestasi tim dikirim ke dosen wali yang ditunjuk atau dosen wali setiap anggota
 func (s *NotificationService) CreateSubmissionNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string) error {
-	// #10a proses: ambil student untuk dapat advisor ID
-	student, err := s.studentRepo.GetStudentByID(ctx, studentID)
+	// #10a proses: ambil achievement dari MongoDB untuk ambil title dan anggota tim, jika gagal diperlakukan sebagai prestasi individu
+	achievement, err := s.achievementRepo.GetAchievementByID(ctx, mongoAchievementID)
+	if err != nil || achievement == nil {
+		achievement = &modelmongo.Achievement{}
+	}
+
+	// #10b proses: kumpulkan advisor ID tujuan notifikasi
+	advisorIDs, err := s.submissionAdvisorIDs(ctx, achievement, studentID)
 	if err != nil {
 		return err
 	}
 
-	// #10b proses: jika student tidak punya advisor, tidak perlu buat notifikasi
-	if student.AdvisorID == "" {
-		return nil
+	// #10c proses: buat message notifikasi untuk dosen wali
+	title := achievementTitle(achievement)
+	message := "Mahasiswa bimbingan Anda telah mengajukan prestasi \"" + title + "\" untuk diverifikasi."
+	if achievement.IsTeam() {
+		message = "Mahasiswa bimbingan Anda telah mengajukan prestasi tim \"" + title + "\" untuk diverifikasi."
+	}
+
+	for _, advisorID := range advisorIDs {
+		// #10d proses: ambil lecturer berdasarkan advisor ID untuk dapat user ID
+		lecturer, err := s.userRepo.GetLecturerByID(ctx, advisorID)
+		if err != nil {
+			return err
+		}
+
+		// #10e proses: buat request notifikasi dan simpan ke database untuk dosen wali
+		req := modelpostgre.CreateNotificationRequest{
+			UserID:             lecturer.UserID,
+			Type:               modelpostgre.NotificationTypeAchievementSubmitted,
+			Title:              "Prestasi Baru Diajukan",
+			Message:            message,
+			AchievementID:      &achievementRefID,
+			MongoAchievementID: &mongoAchievementID,
+		}
+
+		if _, err := s.notifRepo.CreateNotification(ctx, req); err != nil {
+			return err
+		}
 	}
 
-	// #10c proses: ambil lecturer berdasarkan advisor ID untuk dapat user ID
-	lecturer, err := s.userRepo.GetLecturerByID(ctx, student.AdvisorID)
+	return nil
+}
+
+// #11 proses: buat notifikasi undangan untuk anggota tim supaya mengkonfirmasi partisipasi
+func (s *NotificationService) CreateTeamInvitationNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string) error {
+	// #11a proses: ambil student untuk dapat user ID
+	student, err := s.studentRepo.GetStudentByID(ctx, studentID)
 	if err != nil {
 		return err
 	}
 
-	// #10d proses: ambil achievement dari MongoDB untuk ambil title
+	// #11b proses: ambil achievement dari MongoDB untuk ambil title
 	achievement, err := s.achievementRepo.GetAchievementByID(ctx, mongoAchievementID)
 	if err != nil {
 		return err
@@ -221,20 +273,242 @@ func (s *NotificationService) CreateSubmissionNotification(ctx context.Context,
 		return errors.New("prestasi tidak ditemukan")
 	}
 
-	// #10e proses: set title dari achievement atau gunakan default
 	title := achievement.Title
 	if title == "" {
 		title = "Prestasi"
 	}
 
-	// #10f proses: buat message notifikasi untuk dosen wali
-	message := "Mahasiswa bimbingan Anda telah mengajukan prestasi \"" + title + "\" untuk diverifikasi."
+	// #11c proses: buat message notifikasi dengan peran anggota di tim
+	message := "Anda ditambahkan sebagai anggota tim pada prestasi \"" + title + "\". Silakan konfirmasi partisipasi Anda."
+	if member := achievement.FindTeamMember(studentID); member != nil && member.Role != "" {
+		message = "Anda ditambahkan sebagai " + member.Role + " pada prestasi tim \"" + title + "\". Silakan konfirmasi partisipasi Anda."
+	}
+
+	// #11d proses: buat request notifikasi dan simpan ke database untuk anggota tim
+	req := modelpostgre.CreateNotificationRequest{
+		UserID:             student.UserID,
+		Type:               modelpostgre.NotificationTypeTeamInvitation,
+		Title:              "Konfirmasi Anggota Tim",
+		Message:            message,
+		AchievementID:      &achievementRefID,
+		MongoAchievementID: &mongoAchievementID,
+	}
+
+	_, err = s.notifRepo.CreateNotification(ctx, req)
+	return err
+}
+
+// #12 proses: tarik notifikasi pengajuan prestasi dari dosen wali ketika mahasiswa withdraw prestasi
+func (s *NotificationService) RetractSubmissionNotification(ctx context.Context, achievementRefID string) error {
+	_, err := s.notifRepo.DeleteNotificationsByAchievement(ctx, achievementRefID, modelpostgre.NotificationTypeAchievementSubmitted)
+	return err
+}
+
+// #13 proses: buat notifikasi pengingat untuk dosen wali ketika prestasi terlalu lama menunggu verifikasi
+func (s *NotificationService) CreateSLAReminderNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string, waitingDays int) error {
+	// #13a proses: ambil achievement dari MongoDB, jika gagal diperlakukan sebagai prestasi individu
+	achievement, err := s.achievementRepo.GetAchievementByID(ctx, mongoAchievementID)
+	if err != nil || achievement == nil {
+		achievement = &modelmongo.Achievement{}
+	}
+
+	// #13b proses: kumpulkan advisor ID dengan aturan yang sama seperti notifikasi pengajuan
+	advisorIDs, err := s.submissionAdvisorIDs(ctx, achievement, studentID)
+	if err != nil {
+		return err
+	}
+
+	// #13c proses: buat message pengingat dengan lama menunggu
+	message := fmt.Sprintf("Prestasi \"%s\" dari mahasiswa bimbingan Anda sudah menunggu verifikasi selama %d hari.", achievementTitle(achievement), waitingDays)
+
+	for _, advisorID := range advisorIDs {
+		// #13d proses: ambil lecturer berdasarkan advisor ID untuk dapat user ID
+		lecturer, err := s.userRepo.GetLecturerByID(ctx, advisorID)
+		if err != nil {
+			return err
+		}
+
+		// #13e proses: buat request notifikasi pengingat dan simpan ke database
+		req := modelpostgre.CreateNotificationRequest{
+			UserID:             lecturer.UserID,
+			Type:               modelpostgre.NotificationTypeSLAReminder,
+			Title:              "Pengingat Verifikasi Prestasi",
+			Message:            message,
+			AchievementID:      &achievementRefID,
+			MongoAchievementID: &mongoAchievementID,
+		}
+
+		if _, err := s.notifRepo.CreateNotification(ctx, req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// #14 proses: buat notifikasi eskalasi untuk admin atau verifikator pengganti ketika dosen wali tidak menindaklanjuti prestasi
+func (s *NotificationService) CreateSLAEscalationNotification(ctx context.Context, recipientUserIDs []string, mongoAchievementID string, achievementRefID string, waitingDays int) error {
+	// #14a proses: ambil achievement dari MongoDB untuk ambil title
+	achievement, err := s.achievementRepo.GetAchievementByID(ctx, mongoAchievementID)
+	if err != nil || achievement == nil {
+		achievement = &modelmongo.Achievement{}
+	}
+
+	// #14b proses: buat message eskalasi dengan lama menunggu
+	message := fmt.Sprintf("Prestasi \"%s\" sudah menunggu verifikasi selama %d hari tanpa tindakan dari dosen wali dan dieskalasikan kepada Anda.", achievementTitle(achievement), waitingDays)
+
+	// #14c proses: buat notifikasi untuk setiap penerima eskalasi
+	for _, userID := range recipientUserIDs {
+		req := modelpostgre.CreateNotificationRequest{
+			UserID:             userID,
+			Type:               modelpostgre.NotificationTypeSLAEscalated,
+			Title:              "Eskalasi Verifikasi Prestasi",
+			Message:            message,
+			AchievementID:      &achievementRefID,
+			MongoAchievementID: &mongoAchievementID,
+		}
+
+		if _, err := s.notifRepo.CreateNotification(ctx, req); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// #15 proses: kumpulkan advisor ID yang bertanggung jawab memverifikasi prestasi, dosen wali yang ditunjuk atau dosen wali setiap anggota tim
+func (s *NotificationService) submissionAdvisorIDs(ctx context.Context, achievement *modelmongo.Achievement, studentID string) ([]string, error) {
+	if achievement.DesignatedAdvisorID != "" {
+		return []string{achievement.DesignatedAdvisorID}, nil
+	}
+
+	studentIDs := []string{studentID}
+	for _, member := range achievement.TeamMembers {
+		if member.StudentID != studentID {
+			studentIDs = append(studentIDs, member.StudentID)
+		}
+	}
+
+	advisorIDs := []string{}
+	seen := map[string]bool{}
+	for _, id := range studentIDs {
+		student, err := s.studentRepo.GetStudentByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		// #15a proses: student tanpa advisor dilewati
+		if student.AdvisorID != "" && !seen[student.AdvisorID] {
+			seen[student.AdvisorID] = true
+			advisorIDs = append(advisorIDs, student.AdvisorID)
+		}
+	}
+
+	return advisorIDs, nil
+}
+
+// #16 proses: ambil title prestasi atau gunakan default jika kosong
+func achievementTitle(achievement *modelmongo.Achievement) string {
+	if achievement.Title == "" {
+		return "Prestasi"
+	}
+	return achievement.Title
+}
+
+// #17 proses: buat notifikasi untuk mahasiswa ketika masa berlaku sertifikasinya akan berakhir
+func (s *NotificationService) CreateCertificationExpiryNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string, validUntil time.Time, daysLeft int) error {
+	// #17a proses: ambil student untuk dapat user ID
+	student, err := s.studentRepo.GetStudentByID(ctx, studentID)
+	if err != nil {
+		return err
+	}
+
+	// #17b proses: ambil achievement dari MongoDB untuk ambil title
+	achievement, err := s.achievementRepo.GetAchievementByID(ctx, mongoAchievementID)
+	if err != nil || achievement == nil {
+		achievement = &modelmongo.Achievement{}
+	}
+
+	// #17c proses: buat message dengan sisa hari dan tanggal berakhir
+	message := fmt.Sprintf("Masa berlaku sertifikasi \"%s\" akan berakhir dalam %d hari (%s). Segera perpanjang sertifikasi Anda.", achievementTitle(achievement), daysLeft, validUntil.Format("02-01-2006"))
+
+	// #17d proses: buat request notifikasi dan simpan ke database
+	req := modelpostgre.CreateNotificationRequest{
+		UserID:             student.UserID,
+		Type:               modelpostgre.NotificationTypeCertificationExpiring,
+		Title:              "Sertifikasi Akan Berakhir",
+		Message:            message,
+		AchievementID:      &achievementRefID,
+		MongoAchievementID: &mongoAchievementID,
+	}
+
+	_, err = s.notifRepo.CreateNotification(ctx, req)
+	return err
+}
+
+// #18 proses: ambil notifikasi user dengan pagination cursor, cursor nil berarti halaman pertama
+func (s *NotificationService) GetNotificationsByCursor(ctx context.Context, userID string, cursor *modelpostgre.PageCursor, limit int) (*modelpostgre.GetNotificationsCursorResponse, error) {
+	// #18a proses: validasi limit sama seperti mode page
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	// #18b proses: ambil satu baris lebih untuk mengetahui masih ada halaman berikutnya tanpa COUNT
+	notifications, err := s.notifRepo.GetNotificationsByUserIDAfterCursor(ctx, userID, cursor, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	hasMore := len(notifications) > limit
+	if hasMore {
+		notifications = notifications[:limit]
+	}
+	if notifications == nil {
+		notifications = []modelpostgre.Notification{}
+	}
+
+	// #18c proses: build response, next_cursor diisi dari item terakhir jika masih ada data
+	response := &modelpostgre.GetNotificationsCursorResponse{
+		Status: "success",
+		Data:   notifications,
+		Pagination: modelpostgre.CursorPagination{
+			Limit:   limit,
+			HasMore: hasMore,
+		},
+	}
+	if hasMore {
+		last := notifications[len(notifications)-1]
+		response.Pagination.NextCursor = helper.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return response, nil
+}
+
+// #19 proses: buat notifikasi untuk mahasiswa ketika attachment yang diupload terdeteksi malware dan dikarantina
+func (s *NotificationService) CreateAttachmentInfectedNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string, fileName string) error {
+	// #19a proses: ambil student untuk dapat user ID
+	student, err := s.studentRepo.GetStudentByID(ctx, studentID)
+	if err != nil {
+		return err
+	}
+
+	// #19b proses: ambil achievement dari MongoDB untuk ambil title
+	achievement, err := s.achievementRepo.GetAchievementByID(ctx, mongoAchievementID)
+	if err != nil || achievement == nil {
+		achievement = &modelmongo.Achievement{}
+	}
+
+	// #19c proses: buat message dengan nama file yang dikarantina
+	message := fmt.Sprintf("File \"%s\" pada prestasi \"%s\" terdeteksi malware dan sudah dikarantina sehingga tidak dapat dibuka. Silakan upload ulang file yang bersih.", fileName, achievementTitle(achievement))
 
-	// #10g proses: buat request notifikasi dan simpan ke database untuk dosen wali
+	// #19d proses: buat request notifikasi dan simpan ke database
 	req := modelpostgre.CreateNotificationRequest{
-		UserID:             lecturer.UserID,
-		Type:               modelpostgre.NotificationTypeAchievementSubmitted,
-		Title:              "Prestasi Baru Diajukan",
+		UserID:             student.UserID,
+		Type:               modelpostgre.NotificationTypeAttachmentInfected,
+		Title:              "Attachment Terdeteksi Malware",
 		Message:            message,
 		AchievementID:      &achievementRefID,
 		MongoAchievementID: &mongoAchievementID,
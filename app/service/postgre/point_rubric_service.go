@@ -0,0 +1,242 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk context, database, errors, strconv, strings, dan repository
+import (
+	"context"
+	"database/sql"
+	"errors"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"strconv"
+	"strings"
+)
+
+// #2 proses: definisikan interface untuk operasi rubrik poin dan perhitungan poin prestasi
+type IPointRubricService interface {
+	GetPointRubrics(ctx context.Context) (*modelpostgre.GetAllPointRubricsResponse, error)
+	GetActivePointRubric(ctx context.Context) (*modelpostgre.GetPointRubricResponse, error)
+	GetPointRubricByVersion(ctx context.Context, version int) (*modelpostgre.GetPointRubricResponse, error)
+	CreatePointRubric(ctx context.Context, userID string, req modelpostgre.CreatePointRubricRequest) (*modelpostgre.GetPointRubricResponse, error)
+	ActivatePointRubric(ctx context.Context, version int) (*modelpostgre.GetPointRubricResponse, error)
+	CalculatePoints(ctx context.Context, achievementType string, details modelmongo.AchievementDetails) (*modelpostgre.PointCalculationResult, error)
+}
+
+// #3 proses: struct service untuk rubrik poin dengan dependency point rubric repository dan achievement type service
+type PointRubricService struct {
+	pointRubricRepo        repositorypostgre.IPointRubricRepository
+	achievementTypeService IAchievementTypeService
+}
+
+// #4 proses: constructor untuk membuat instance PointRubricService baru
+func NewPointRubricService(pointRubricRepo repositorypostgre.IPointRubricRepository, achievementTypeService IAchievementTypeService) IPointRubricService {
+	return &PointRubricService{
+		pointRubricRepo:        pointRubricRepo,
+		achievementTypeService: achievementTypeService,
+	}
+}
+
+// #5 proses: ambil semua versi rubrik poin
+func (s *PointRubricService) GetPointRubrics(ctx context.Context) (*modelpostgre.GetAllPointRubricsResponse, error) {
+	rubrics, err := s.pointRubricRepo.GetAllPointRubrics(ctx)
+	if err != nil {
+		return nil, errors.New("error mengambil data rubrik poin: " + err.Error())
+	}
+
+	if rubrics == nil {
+		rubrics = []modelpostgre.PointRubric{}
+	}
+
+	return &modelpostgre.GetAllPointRubricsResponse{
+		Status: "success",
+		Data:   rubrics,
+	}, nil
+}
+
+// #6 proses: ambil rubrik poin yang sedang aktif
+func (s *PointRubricService) GetActivePointRubric(ctx context.Context) (*modelpostgre.GetPointRubricResponse, error) {
+	rubric, err := s.pointRubricRepo.GetActivePointRubric(ctx)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("belum ada rubrik poin yang aktif")
+		}
+		return nil, errors.New("error mengambil rubrik poin aktif: " + err.Error())
+	}
+
+	return &modelpostgre.GetPointRubricResponse{
+		Status: "success",
+		Data:   *rubric,
+	}, nil
+}
+
+// #7 proses: ambil rubrik poin berdasarkan nomor versi
+func (s *PointRubricService) GetPointRubricByVersion(ctx context.Context, version int) (*modelpostgre.GetPointRubricResponse, error) {
+	rubric, err := s.pointRubricRepo.GetPointRubricByVersion(ctx, version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("rubrik poin tidak ditemukan")
+		}
+		return nil, errors.New("error mengambil rubrik poin: " + err.Error())
+	}
+
+	return &modelpostgre.GetPointRubricResponse{
+		Status: "success",
+		Data:   *rubric,
+	}, nil
+}
+
+// #8 proses: buat rubrik poin versi baru, versi lama tetap disimpan supaya poin prestasi lama bisa ditelusuri
+func (s *PointRubricService) CreatePointRubric(ctx context.Context, userID string, req modelpostgre.CreatePointRubricRequest) (*modelpostgre.GetPointRubricResponse, error) {
+	// #8a proses: validasi nama dan aturan rubrik
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, errors.New("nama rubrik wajib diisi")
+	}
+
+	if len(req.Rules) == 0 {
+		return nil, errors.New("rubrik harus memiliki minimal satu aturan poin")
+	}
+
+	for i, rule := range req.Rules {
+		validType, err := s.achievementTypeService.IsValidAchievementType(ctx, rule.AchievementType)
+		if err != nil {
+			return nil, err
+		}
+
+		if !validType {
+			return nil, errors.New("aturan ke-" + strconv.Itoa(i+1) + " tidak valid: achievement type tidak valid")
+		}
+
+		if err := validatePointRule(rule); err != nil {
+			return nil, errors.New("aturan ke-" + strconv.Itoa(i+1) + " tidak valid: " + err.Error())
+		}
+	}
+
+	// #8b proses: simpan rubrik baru dengan versi berikutnya
+	rubric, err := s.pointRubricRepo.CreatePointRubric(ctx, req, userID)
+	if err != nil {
+		return nil, errors.New("error menyimpan rubrik poin: " + err.Error())
+	}
+
+	// #8c proses: aktifkan rubrik jika diminta
+	if req.Activate {
+		return s.ActivatePointRubric(ctx, rubric.Version)
+	}
+
+	return &modelpostgre.GetPointRubricResponse{
+		Status: "success",
+		Data:   *rubric,
+	}, nil
+}
+
+// #9 proses: aktifkan rubrik poin versi tertentu, rubrik lain otomatis nonaktif
+func (s *PointRubricService) ActivatePointRubric(ctx context.Context, version int) (*modelpostgre.GetPointRubricResponse, error) {
+	err := s.pointRubricRepo.ActivatePointRubric(ctx, version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("rubrik poin tidak ditemukan")
+		}
+		return nil, errors.New("error mengaktifkan rubrik poin: " + err.Error())
+	}
+
+	return s.GetPointRubricByVersion(ctx, version)
+}
+
+// #10 proses: hitung poin prestasi memakai rubrik yang sedang aktif
+func (s *PointRubricService) CalculatePoints(ctx context.Context, achievementType string, details modelmongo.AchievementDetails) (*modelpostgre.PointCalculationResult, error) {
+	rubric, err := s.pointRubricRepo.GetActivePointRubric(ctx)
+	if err != nil {
+		// #10a proses: jika belum ada rubrik aktif, poin 0 dengan versi rubrik 0
+		if err == sql.ErrNoRows {
+			return &modelpostgre.PointCalculationResult{
+				Points:        0,
+				RubricVersion: 0,
+				MatchedRules:  []modelpostgre.PointRule{},
+			}, nil
+		}
+		return nil, errors.New("error mengambil rubrik poin aktif: " + err.Error())
+	}
+
+	return CalculatePointsWithRubric(*rubric, achievementType, details), nil
+}
+
+// #11 proses: hitung poin dari rubrik, aturan tanpa field jadi poin dasar dan aturan yang cocok ditambahkan
+func CalculatePointsWithRubric(rubric modelpostgre.PointRubric, achievementType string, details modelmongo.AchievementDetails) *modelpostgre.PointCalculationResult {
+	result := &modelpostgre.PointCalculationResult{
+		RubricVersion: rubric.Version,
+		MatchedRules:  []modelpostgre.PointRule{},
+	}
+
+	for _, rule := range rubric.Rules {
+		if rule.AchievementType != achievementType {
+			continue
+		}
+
+		// #11a proses: aturan tanpa field selalu berlaku untuk tipe prestasi tersebut
+		if rule.Field == "" {
+			result.Points += rule.Points
+			result.MatchedRules = append(result.MatchedRules, rule)
+			continue
+		}
+
+		// #11b proses: aturan dengan field hanya berlaku jika nilai detail prestasi sama
+		value, ok := pointRuleFieldValue(details, rule.Field)
+		if ok && strings.EqualFold(strings.TrimSpace(value), strings.TrimSpace(rule.Value)) {
+			result.Points += rule.Points
+			result.MatchedRules = append(result.MatchedRules, rule)
+		}
+	}
+
+	return result
+}
+
+// #12 proses: ambil nilai field detail prestasi sebagai string untuk dicocokkan dengan aturan
+func pointRuleFieldValue(details modelmongo.AchievementDetails, field string) (string, bool) {
+	switch field {
+	case modelpostgre.PointRuleFieldCompetitionLevel:
+		if details.CompetitionLevel != nil {
+			return *details.CompetitionLevel, true
+		}
+	case modelpostgre.PointRuleFieldRank:
+		if details.Rank != nil {
+			return strconv.Itoa(*details.Rank), true
+		}
+	case modelpostgre.PointRuleFieldMedalType:
+		if details.MedalType != nil {
+			return *details.MedalType, true
+		}
+	case modelpostgre.PointRuleFieldPublicationType:
+		if details.PublicationType != nil {
+			return *details.PublicationType, true
+		}
+	case modelpostgre.PointRuleFieldPosition:
+		if details.Position != nil {
+			return *details.Position, true
+		}
+	}
+	return "", false
+}
+
+// #13 proses: validasi field, value, dan poin satu aturan sebelum rubrik disimpan, tipe prestasi dicek ke achievement type service
+func validatePointRule(rule modelpostgre.PointRule) error {
+	validFields := map[string]bool{
+		"": true,
+		modelpostgre.PointRuleFieldCompetitionLevel: true,
+		modelpostgre.PointRuleFieldRank:             true,
+		modelpostgre.PointRuleFieldMedalType:        true,
+		modelpostgre.PointRuleFieldPublicationType:  true,
+		modelpostgre.PointRuleFieldPosition:         true,
+	}
+	if !validFields[rule.Field] {
+		return errors.New("field aturan tidak dikenal: " + rule.Field)
+	}
+
+	if rule.Field != "" && strings.TrimSpace(rule.Value) == "" {
+		return errors.New("value wajib diisi jika field aturan diisi")
+	}
+
+	if rule.Points < 0 {
+		return errors.New("poin tidak boleh negatif")
+	}
+
+	return nil
+}
@@ -0,0 +1,559 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk context, database, errors, fmt, os, sort, strings, tabwriter, time, model, dan repository
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	repositorystorage "sistem-pelaporan-prestasi-mahasiswa/app/repository/storage"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// #2 proses: definisikan interface untuk rekonsiliasi data prestasi antara MongoDB dan PostgreSQL
+type IReconciliationService interface {
+	RunReconciliation(ctx context.Context, policy modelpostgre.ReconcilePolicy) (*modelpostgre.ReconcileResult, error)
+	GetReconciliationReport(ctx context.Context, userID string, roleID string) (*modelpostgre.ReconcileResponse, error)
+	RepairInconsistencies(ctx context.Context, userID string, roleID string, policy modelpostgre.ReconcilePolicy) (*modelpostgre.ReconcileResponse, error)
+}
+
+// #3 proses: struct service rekonsiliasi dengan dependency achievement reference, achievement MongoDB, dan user
+type ReconciliationService struct {
+	achievementRefRepo repositorypostgre.IAchievementReferenceRepository
+	achievementRepo    repositorymongo.IAchievementRepository
+	userRepo           repositorypostgre.IUserRepository
+	fileStorage        repositorystorage.IFileStorage
+	config             modelpostgre.ReconcileConfig
+}
+
+// #4 proses: constructor untuk membuat instance ReconciliationService baru
+func NewReconciliationService(
+	achievementRefRepo repositorypostgre.IAchievementReferenceRepository,
+	achievementRepo repositorymongo.IAchievementRepository,
+	userRepo repositorypostgre.IUserRepository,
+	fileStorage repositorystorage.IFileStorage,
+	config modelpostgre.ReconcileConfig,
+) IReconciliationService {
+	return &ReconciliationService{
+		achievementRefRepo: achievementRefRepo,
+		achievementRepo:    achievementRepo,
+		userRepo:           userRepo,
+		fileStorage:        fileStorage,
+		config:             config,
+	}
+}
+
+// #5 proses: baca konfigurasi rekonsiliasi dari environment variable, policy kosong berarti hanya laporan
+func LoadReconcileConfig() modelpostgre.ReconcileConfig {
+	return modelpostgre.ReconcileConfig{
+		DefaultPolicy: modelpostgre.ReconcilePolicy{
+			MongoWithoutReference: strings.TrimSpace(os.Getenv("RECONCILE_MONGO_ORPHAN_POLICY")),
+			ReferenceWithoutMongo: strings.TrimSpace(os.Getenv("RECONCILE_REFERENCE_ORPHAN_POLICY")),
+			DeletedMismatch:       strings.TrimSpace(os.Getenv("RECONCILE_DELETED_MISMATCH_POLICY")),
+		},
+		GracePeriod: time.Duration(envInt("RECONCILE_GRACE_MINUTES", 10, 0)) * time.Minute,
+	}
+}
+
+// #6 proses: policy yang diperbolehkan untuk setiap kategori
+var reconcileAllowedPolicies = map[string][]string{
+	modelpostgre.ReconcileCategoryMongoWithoutReference: {modelpostgre.ReconcilePolicyReport, modelpostgre.ReconcilePolicyPurge, modelpostgre.ReconcilePolicyAdopt},
+	modelpostgre.ReconcileCategoryReferenceWithoutMongo: {modelpostgre.ReconcilePolicyReport, modelpostgre.ReconcilePolicyDeleteReference},
+	modelpostgre.ReconcileCategoryDeletedMismatch:       {modelpostgre.ReconcilePolicyReport, modelpostgre.ReconcilePolicyFollowReference, modelpostgre.ReconcilePolicyFollowMongo},
+}
+
+// #7 proses: urutan kategori di laporan
+var reconcileCategories = []string{
+	modelpostgre.ReconcileCategoryMongoWithoutReference,
+	modelpostgre.ReconcileCategoryReferenceWithoutMongo,
+	modelpostgre.ReconcileCategoryDeletedMismatch,
+}
+
+// #8 proses: validasi policy per kategori, policy kosong diisi report supaya default tidak pernah mengubah data
+func NormalizeReconcilePolicy(policy modelpostgre.ReconcilePolicy) (modelpostgre.ReconcilePolicy, error) {
+	fields := map[string]*string{
+		modelpostgre.ReconcileCategoryMongoWithoutReference: &policy.MongoWithoutReference,
+		modelpostgre.ReconcileCategoryReferenceWithoutMongo: &policy.ReferenceWithoutMongo,
+		modelpostgre.ReconcileCategoryDeletedMismatch:       &policy.DeletedMismatch,
+	}
+
+	for _, category := range reconcileCategories {
+		value := fields[category]
+		*value = strings.ToLower(strings.TrimSpace(*value))
+		if *value == "" {
+			*value = modelpostgre.ReconcilePolicyReport
+		}
+
+		allowed := reconcileAllowedPolicies[category]
+		if !containsString(allowed, *value) {
+			return policy, errors.New("policy tidak valid untuk " + category + ": " + *value + ". Gunakan salah satu: " + strings.Join(allowed, ", "))
+		}
+	}
+
+	return policy, nil
+}
+
+// #9 proses: laporan ketidakkonsistenan tanpa perbaikan, hanya untuk admin
+func (s *ReconciliationService) GetReconciliationReport(ctx context.Context, userID string, roleID string) (*modelpostgre.ReconcileResponse, error) {
+	if err := s.requireAdmin(ctx, roleID); err != nil {
+		return nil, err
+	}
+
+	result, err := s.reconcile(ctx, modelpostgre.ReconcilePolicy{}, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &modelpostgre.ReconcileResponse{Status: "success", Data: *result}, nil
+}
+
+// #10 proses: rekonsiliasi dengan perbaikan sesuai policy dari admin
+func (s *ReconciliationService) RepairInconsistencies(ctx context.Context, userID string, roleID string, policy modelpostgre.ReconcilePolicy) (*modelpostgre.ReconcileResponse, error) {
+	if err := s.requireAdmin(ctx, roleID); err != nil {
+		return nil, err
+	}
+
+	result, err := s.reconcile(ctx, policy, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &modelpostgre.ReconcileResponse{Status: "success", Data: *result}, nil
+}
+
+// #11 proses: rekonsiliasi tanpa cek role untuk command line, policy kosong memakai default dari environment
+func (s *ReconciliationService) RunReconciliation(ctx context.Context, policy modelpostgre.ReconcilePolicy) (*modelpostgre.ReconcileResult, error) {
+	if policy == (modelpostgre.ReconcilePolicy{}) {
+		policy = s.config.DefaultPolicy
+	}
+	return s.reconcile(ctx, policy, "")
+}
+
+// #12 proses: validasi user harus memiliki role Admin
+func (s *ReconciliationService) requireAdmin(ctx context.Context, roleID string) error {
+	roleName, err := s.userRepo.GetRoleName(ctx, roleID)
+	if err != nil {
+		return errors.New("error mengambil role name: " + err.Error())
+	}
+
+	if roleName != "Admin" {
+		return errors.New("akses ditolak. Hanya admin yang dapat menjalankan rekonsiliasi data prestasi")
+	}
+
+	return nil
+}
+
+// #13 proses: scan kedua database, periksa ulang setiap kandidat lalu perbaiki sesuai policy
+func (s *ReconciliationService) reconcile(ctx context.Context, policy modelpostgre.ReconcilePolicy, changedBy string) (*modelpostgre.ReconcileResult, error) {
+	policy, err := NormalizeReconcilePolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	// #13a proses: baca reference PostgreSQL dulu, dokumen yang dibuat setelahnya tertangkap grace period bukan dianggap orphan
+	activeIDs, err := s.achievementRefRepo.GetAllAchievementMongoIDs(ctx)
+	if err != nil {
+		return nil, errors.New("error mengambil reference prestasi: " + err.Error())
+	}
+
+	deletedIDs, err := s.achievementRefRepo.GetDeletedAchievementMongoIDs(ctx)
+	if err != nil {
+		return nil, errors.New("error mengambil reference prestasi yang dihapus: " + err.Error())
+	}
+
+	refDeleted := make(map[string]bool, len(activeIDs)+len(deletedIDs))
+	for _, id := range activeIDs {
+		refDeleted[id] = false
+	}
+	for _, id := range deletedIDs {
+		refDeleted[id] = true
+	}
+
+	// #13b proses: baca semua dokumen MongoDB termasuk yang sudah di-soft delete
+	states, err := s.achievementRepo.GetAllAchievementStates(ctx)
+	if err != nil {
+		return nil, errors.New("error mengambil prestasi dari database: " + err.Error())
+	}
+
+	now := time.Now()
+	result := &modelpostgre.ReconcileResult{
+		CheckedAt:      now,
+		MongoDocuments: len(states),
+		References:     len(refDeleted),
+		Policy:         policy,
+		Issues:         []modelpostgre.ReconcileIssue{},
+	}
+
+	// #13c proses: kumpulkan kandidat dari perbandingan kedua scan
+	candidates := map[string]bool{}
+	mongoIDs := make(map[string]bool, len(states))
+	for _, state := range states {
+		id := state.ID.Hex()
+		mongoIDs[id] = true
+
+		deleted, ok := refDeleted[id]
+		if !ok || deleted != (state.DeletedAt != nil) {
+			candidates[id] = true
+		}
+	}
+	for id := range refDeleted {
+		if !mongoIDs[id] {
+			candidates[id] = true
+		}
+	}
+
+	sortedIDs := make([]string, 0, len(candidates))
+	for id := range candidates {
+		sortedIDs = append(sortedIDs, id)
+	}
+	sort.Strings(sortedIDs)
+
+	// #13d proses: periksa ulang setiap kandidat satu per satu, create atau delete yang sedang berjalan saat scan sudah konsisten di sini
+	for _, mongoID := range sortedIDs {
+		state, err := s.loadReconcileState(ctx, mongoID)
+		if err != nil {
+			fmt.Printf("Error reading reconciliation state for %s: %v\n", mongoID, err)
+			continue
+		}
+
+		category := state.category()
+		if category == "" {
+			continue
+		}
+		if category == modelpostgre.ReconcileCategoryMongoWithoutReference && now.Sub(state.achievement.CreatedAt) < s.config.GracePeriod {
+			result.SkippedRecent++
+			continue
+		}
+
+		issue := state.issue(mongoID, category)
+		issue.Action = reconcileCategoryPolicy(policy, category)
+
+		// #13e proses: perbaiki jika policy bukan report, kegagalan dicatat per item supaya item lain tetap diproses
+		if issue.Action != modelpostgre.ReconcilePolicyReport {
+			if err := s.repair(ctx, state, mongoID, category, issue.Action, changedBy); err != nil {
+				issue.Error = err.Error()
+			} else {
+				issue.Repaired = true
+			}
+		}
+
+		result.Issues = append(result.Issues, issue)
+	}
+
+	// #13f proses: hitung ringkasan per kategori
+	for _, category := range reconcileCategories {
+		summary := modelpostgre.ReconcileCategorySummary{
+			Category: category,
+			Policy:   reconcileCategoryPolicy(policy, category),
+		}
+		for _, issue := range result.Issues {
+			if issue.Category != category {
+				continue
+			}
+			summary.Found++
+			if issue.Repaired {
+				summary.Repaired++
+			} else if issue.Error != "" {
+				summary.Failed++
+			}
+		}
+		result.Categories = append(result.Categories, summary)
+		result.TotalIssues += summary.Found
+		result.TotalRepaired += summary.Repaired
+	}
+	result.TotalUnresolved = result.TotalIssues - result.TotalRepaired
+
+	return result, nil
+}
+
+// #14 proses: ambil policy untuk kategori tertentu
+func reconcileCategoryPolicy(policy modelpostgre.ReconcilePolicy, category string) string {
+	switch category {
+	case modelpostgre.ReconcileCategoryMongoWithoutReference:
+		return policy.MongoWithoutReference
+	case modelpostgre.ReconcileCategoryReferenceWithoutMongo:
+		return policy.ReferenceWithoutMongo
+	default:
+		return policy.DeletedMismatch
+	}
+}
+
+// #15 proses: kondisi terkini satu prestasi di kedua database, achievement atau ref nil berarti tidak ada
+type reconcileState struct {
+	achievement  *modelmongo.Achievement
+	mongoDeleted bool
+	ref          *modelpostgre.AchievementReference
+	refDeleted   bool
+}
+
+// #16 proses: baca kondisi terkini satu prestasi, dokumen dan reference yang dihapus ikut dibaca
+func (s *ReconciliationService) loadReconcileState(ctx context.Context, mongoID string) (*reconcileState, error) {
+	state := &reconcileState{}
+
+	// #16a proses: ID yang bukan ObjectID valid berarti dokumen MongoDB pasti tidak ada
+	if _, err := primitive.ObjectIDFromHex(mongoID); err == nil {
+		achievement, err := s.achievementRepo.GetAchievementByID(ctx, mongoID)
+		if err != nil {
+			return nil, err
+		}
+
+		if achievement == nil {
+			deleted, err := s.achievementRepo.GetDeletedAchievementsByIDs(ctx, []string{mongoID})
+			if err != nil {
+				return nil, err
+			}
+			if len(deleted) > 0 {
+				achievement = &deleted[0]
+				state.mongoDeleted = true
+			}
+		}
+		state.achievement = achievement
+	}
+
+	// #16b proses: cari reference aktif dulu, lalu reference di tempat sampah
+	ref, err := s.achievementRefRepo.GetAchievementReferenceByMongoID(ctx, mongoID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if err == nil && ref != nil {
+		state.ref = ref
+		return state, nil
+	}
+
+	deletedRef, err := s.achievementRefRepo.GetDeletedAchievementReferenceByMongoID(ctx, mongoID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+	if err == nil && deletedRef != nil {
+		state.ref = &deletedRef.AchievementReference
+		state.refDeleted = true
+	}
+
+	return state, nil
+}
+
+// #17 proses: tentukan kategori ketidakkonsistenan, kosong berarti kedua database sudah konsisten
+func (st *reconcileState) category() string {
+	switch {
+	case st.achievement != nil && st.ref == nil:
+		return modelpostgre.ReconcileCategoryMongoWithoutReference
+	case st.achievement == nil && st.ref != nil:
+		return modelpostgre.ReconcileCategoryReferenceWithoutMongo
+	case st.achievement != nil && st.mongoDeleted != st.refDeleted:
+		return modelpostgre.ReconcileCategoryDeletedMismatch
+	default:
+		return ""
+	}
+}
+
+// #18 proses: buat item laporan dari kondisi terkini beserta penjelasan singkat
+func (st *reconcileState) issue(mongoID string, category string) modelpostgre.ReconcileIssue {
+	issue := modelpostgre.ReconcileIssue{
+		Category:           category,
+		MongoAchievementID: mongoID,
+	}
+	if st.achievement != nil {
+		issue.StudentID = st.achievement.StudentID
+		issue.Title = st.achievement.Title
+	}
+	if st.ref != nil {
+		issue.AchievementRefID = st.ref.ID
+		issue.StudentID = st.ref.StudentID
+	}
+
+	switch category {
+	case modelpostgre.ReconcileCategoryMongoWithoutReference:
+		issue.Detail = "dokumen MongoDB tidak memiliki reference PostgreSQL"
+		if st.mongoDeleted {
+			issue.Detail = "dokumen MongoDB yang sudah dihapus tidak memiliki reference PostgreSQL"
+		}
+	case modelpostgre.ReconcileCategoryReferenceWithoutMongo:
+		issue.Detail = "reference berstatus " + st.ref.Status + " menunjuk ke dokumen MongoDB yang tidak ada"
+	default:
+		if st.mongoDeleted {
+			issue.Detail = "dokumen MongoDB sudah dihapus tetapi reference masih berstatus " + st.ref.Status
+		} else {
+			issue.Detail = "reference ada di tempat sampah tetapi dokumen MongoDB masih aktif"
+		}
+	}
+
+	return issue
+}
+
+// #19 proses: jalankan perbaikan sesuai kategori dan policy
+func (s *ReconciliationService) repair(ctx context.Context, st *reconcileState, mongoID string, category string, policy string, changedBy string) error {
+	switch category {
+	case modelpostgre.ReconcileCategoryMongoWithoutReference:
+		if policy == modelpostgre.ReconcilePolicyAdopt {
+			return s.adoptAchievement(ctx, st, mongoID, changedBy)
+		}
+		return s.purgeOrphanAchievement(ctx, st, mongoID)
+
+	case modelpostgre.ReconcileCategoryReferenceWithoutMongo:
+		// #19a proses: reference tanpa dokumen tidak bisa ditampilkan maupun di-restore, jadi dihapus permanen beserta history
+		if st.refDeleted {
+			if _, err := s.achievementRefRepo.PurgeAchievementReference(ctx, st.ref.ID); err != nil {
+				return errors.New("error menghapus reference prestasi: " + err.Error())
+			}
+			return nil
+		}
+		if err := s.achievementRefRepo.DeleteAchievementReference(ctx, st.ref.ID); err != nil {
+			return errors.New("error menghapus reference prestasi: " + err.Error())
+		}
+		return nil
+
+	default:
+		return s.alignDeletedStatus(ctx, st, mongoID, policy, changedBy)
+	}
+}
+
+// #20 proses: buat reference draft untuk dokumen orphan, dokumen yang sudah dihapus langsung dimasukkan ke tempat sampah
+func (s *ReconciliationService) adoptAchievement(ctx context.Context, st *reconcileState, mongoID string, changedBy string) error {
+	ref, err := s.achievementRefRepo.CreateAchievementReference(ctx, modelpostgre.CreateAchievementReferenceRequest{
+		StudentID:          st.achievement.StudentID,
+		MongoAchievementID: mongoID,
+		Status:             modelpostgre.AchievementStatusDraft,
+	})
+	if err != nil {
+		return errors.New("error membuat reference prestasi: " + err.Error())
+	}
+
+	status := modelpostgre.AchievementStatusDraft
+	if st.mongoDeleted {
+		if err := s.achievementRefRepo.SoftDeleteAchievementReference(ctx, ref.ID); err != nil {
+			return errors.New("error mengupdate status prestasi menjadi deleted: " + err.Error())
+		}
+		status = modelpostgre.AchievementStatusDeleted
+	}
+
+	s.recordReconcileHistory(ctx, ref.ID, status, changedBy, "reference dibuat ulang oleh rekonsiliasi untuk dokumen MongoDB tanpa reference")
+	return nil
+}
+
+// #21 proses: hapus permanen dokumen orphan beserta file attachment dan preview-nya yang tidak dipakai prestasi lain, purge hanya menghapus dokumen yang sudah di-soft delete
+func (s *ReconciliationService) purgeOrphanAchievement(ctx context.Context, st *reconcileState, mongoID string) error {
+	if !st.mongoDeleted {
+		if err := s.achievementRepo.DeleteAchievement(ctx, mongoID); err != nil {
+			return errors.New("error menghapus prestasi dari database: " + err.Error())
+		}
+	}
+
+	for _, attachment := range st.achievement.Attachments {
+		key, ok := s.fileStorage.KeyFromURL(attachment.FileURL)
+		if !ok {
+			continue
+		}
+		if count, err := s.achievementRepo.CountAttachmentFileReferences(ctx, attachment.FileURL); err != nil || count > 1 {
+			continue
+		}
+		if err := s.fileStorage.Delete(ctx, key); err != nil {
+			fmt.Printf("Error removing attachment file %s: %v\n", key, err)
+		}
+		if attachment.HasPreview {
+			if err := s.fileStorage.Delete(ctx, attachmentPreviewKey(key)); err != nil {
+				fmt.Printf("Error removing attachment preview %s: %v\n", key, err)
+			}
+		}
+	}
+
+	if _, err := s.achievementRepo.PurgeAchievement(ctx, mongoID); err != nil {
+		return errors.New("error menghapus prestasi dari database: " + err.Error())
+	}
+	return nil
+}
+
+// #22 proses: samakan status hapus, follow_reference mengikuti PostgreSQL sedangkan follow_mongo mengikuti MongoDB
+func (s *ReconciliationService) alignDeletedStatus(ctx context.Context, st *reconcileState, mongoID string, policy string, changedBy string) error {
+	if policy == modelpostgre.ReconcilePolicyFollowReference {
+		if st.refDeleted {
+			if err := s.achievementRepo.DeleteAchievement(ctx, mongoID); err != nil {
+				return errors.New("error menghapus prestasi dari database: " + err.Error())
+			}
+		} else if err := s.achievementRepo.RestoreAchievement(ctx, mongoID); err != nil {
+			return errors.New("error me-restore prestasi di database: " + err.Error())
+		}
+		return nil
+	}
+
+	// #22a proses: follow_mongo, reference dipindah ke atau dari tempat sampah supaya mengikuti dokumen MongoDB
+	if st.mongoDeleted {
+		if err := s.achievementRefRepo.SoftDeleteAchievementReference(ctx, st.ref.ID); err != nil {
+			return errors.New("error mengupdate status prestasi menjadi deleted: " + err.Error())
+		}
+		s.recordReconcileHistory(ctx, st.ref.ID, modelpostgre.AchievementStatusDeleted, changedBy, "reference dipindah ke tempat sampah oleh rekonsiliasi karena dokumen MongoDB sudah dihapus")
+		return nil
+	}
+
+	status, err := s.achievementRefRepo.RestoreAchievementReference(ctx, st.ref.ID)
+	if err != nil {
+		return errors.New("error me-restore status prestasi: " + err.Error())
+	}
+	s.recordReconcileHistory(ctx, st.ref.ID, status, changedBy, "reference di-restore oleh rekonsiliasi karena dokumen MongoDB masih aktif")
+	return nil
+}
+
+// #23 proses: catat perbaikan rekonsiliasi di history prestasi, changedBy kosong berarti dijalankan dari command line
+func (s *ReconciliationService) recordReconcileHistory(ctx context.Context, refID string, status string, changedBy string, note string) {
+	req := modelpostgre.CreateAchievementHistoryRequest{
+		AchievementRefID: refID,
+		Action:           modelpostgre.AchievementHistoryActionReconciled,
+		Status:           status,
+		Note:             &note,
+	}
+	if changedBy != "" {
+		req.ChangedBy = &changedBy
+	}
+
+	if err := s.achievementRefRepo.CreateAchievementHistory(ctx, req); err != nil {
+		fmt.Printf("Error recording achievement history: %v\n", err)
+	}
+}
+
+// #24 proses: format hasil rekonsiliasi jadi teks yang mudah dibaca untuk terminal
+func FormatReconcileReport(result *modelpostgre.ReconcileResult) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Rekonsiliasi MongoDB dan PostgreSQL (%s)\n", result.CheckedAt.Format("2006-01-02 15:04:05"))
+	fmt.Fprintf(&b, "Dokumen MongoDB: %d, reference PostgreSQL: %d, dilewati karena baru dibuat: %d\n\n", result.MongoDocuments, result.References, result.SkippedRecent)
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KATEGORI\tPOLICY\tDITEMUKAN\tDIPERBAIKI\tGAGAL")
+	for _, summary := range result.Categories {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\n", summary.Category, summary.Policy, summary.Found, summary.Repaired, summary.Failed)
+	}
+	w.Flush()
+
+	if len(result.Issues) == 0 {
+		b.WriteString("\nTidak ada ketidakkonsistenan.\n")
+		return b.String()
+	}
+
+	b.WriteString("\n")
+	for _, issue := range result.Issues {
+		outcome := "dilaporkan"
+		if issue.Repaired {
+			outcome = "diperbaiki (" + issue.Action + ")"
+		} else if issue.Error != "" {
+			outcome = "gagal (" + issue.Action + "): " + issue.Error
+		}
+
+		ref := issue.AchievementRefID
+		if ref == "" {
+			ref = "-"
+		}
+		fmt.Fprintf(&b, "[%s] mongo=%s ref=%s: %s, %s\n", issue.Category, issue.MongoAchievementID, ref, issue.Detail, outcome)
+	}
+
+	fmt.Fprintf(&b, "\nTotal: %d ditemukan, %d diperbaiki, %d belum selesai\n", result.TotalIssues, result.TotalRepaired, result.TotalUnresolved)
+	return b.String()
+}
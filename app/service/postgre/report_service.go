@@ -5,6 +5,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
 	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
 	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
 	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
@@ -18,15 +19,18 @@ type IReportService interface {
 	GetLecturerReport(ctx context.Context, lecturerID string) (map[string]interface{}, error)
 	GetCurrentStudentReport(ctx context.Context, userID string) (map[string]interface{}, error)
 	GetCurrentLecturerReport(ctx context.Context, userID string) (map[string]interface{}, error)
+	GetDuplicateReport(ctx context.Context, userID string, roleID string) (map[string]interface{}, error)
+	GetStorageUsageReport(ctx context.Context, userID string, roleID string) (map[string]interface{}, error)
 }
 
-// #3 proses: struct service untuk laporan dengan dependency achievement MongoDB, achievement reference PostgreSQL, student, user, dan lecturer repository
+// #3 proses: struct service untuk laporan dengan dependency achievement MongoDB, achievement reference PostgreSQL, student, user, lecturer repository, dan konfigurasi masa berlaku sertifikasi
 type ReportService struct {
 	achievementRepo    repositorymongo.IAchievementRepository
 	achievementRefRepo repositorypostgre.IAchievementReferenceRepository
 	studentRepo        repositorypostgre.IStudentRepository
 	userRepo           repositorypostgre.IUserRepository
 	lecturerRepo       repositorypostgre.ILecturerRepository
+	certConfig         modelpostgre.CertificationExpiryConfig
 }
 
 // #4 proses: constructor untuk membuat instance ReportService baru
@@ -36,6 +40,7 @@ func NewReportService(
 	studentRepo repositorypostgre.IStudentRepository,
 	userRepo repositorypostgre.IUserRepository,
 	lecturerRepo repositorypostgre.ILecturerRepository,
+	certConfig modelpostgre.CertificationExpiryConfig,
 ) IReportService {
 	return &ReportService{
 		achievementRepo:    achievementRepo,
@@ -43,6 +48,7 @@ func NewReportService(
 		studentRepo:        studentRepo,
 		userRepo:           userRepo,
 		lecturerRepo:       lecturerRepo,
+		certConfig:         certConfig,
 	}
 }
 
@@ -308,32 +314,58 @@ func (s *ReportService) GetStudentReport(ctx context.Context, studentID string)
 	}
 
 	// #6e proses: hitung statistik dan build achievement details
+	certConfig := s.certConfig
+	now := time.Now()
 	totalPoints := 0
 	verifiedCount := 0
+	expiredCount := 0
+	expiringSoonCount := 0
 	byType := make(map[string]int)
 	achievementDetails := make([]map[string]interface{}, 0)
 
 	for _, achievement := range achievements {
-		ref, exists := referenceMap[achievement.ID.Hex()]
+		ref, exists := s.resolveReference(ctx, referenceMap, achievement)
 		if !exists {
 			continue
 		}
 
-		totalPoints += achievement.Points
+		// #6e1 proses: prestasi tim hanya dikreditkan sesuai porsi poin student
+		points := achievement.PointsForStudent(studentID)
 		byType[achievement.AchievementType]++
 
 		if ref.Status == "verified" {
 			verifiedCount++
 		}
 
-		achievementDetails = append(achievementDetails, map[string]interface{}{
+		detail := map[string]interface{}{
 			"id":              achievement.ID.Hex(),
 			"title":           achievement.Title,
 			"achievementType": achievement.AchievementType,
 			"status":          ref.Status,
-			"points":          achievement.Points,
+			"points":          points,
 			"createdAt":       achievement.CreatedAt.Format(time.RFC3339),
-		})
+		}
+
+		// #6e2 proses: tandai sertifikasi yang sudah atau akan berakhir, sertifikasi expired tidak dihitung poinnya jika dikonfigurasi
+		certStatus := certificationStatus(achievement.Details, now, certificationSoonDays(certConfig))
+		if certStatus != "" {
+			detail["certification_status"] = certStatus
+			detail["valid_until"] = achievement.Details.ValidUntil.Format(time.RFC3339)
+		}
+
+		switch certStatus {
+		case modelpostgre.CertificationStatusExpired:
+			expiredCount++
+			if certConfig.ExcludeExpiredPoints {
+				detail["points_excluded"] = true
+				points = 0
+			}
+		case modelpostgre.CertificationStatusExpiringSoon:
+			expiringSoonCount++
+		}
+
+		totalPoints += points
+		achievementDetails = append(achievementDetails, detail)
 	}
 
 	// #6f proses: build response dengan data student, statistik, dan list achievements
@@ -350,6 +382,11 @@ func (s *ReportService) GetStudentReport(ctx context.Context, studentID string)
 				"total_points":       totalPoints,
 				"verified_count":     verifiedCount,
 				"by_type":            byType,
+				"certifications": map[string]interface{}{
+					"expired":                 expiredCount,
+					"expiring_soon":           expiringSoonCount,
+					"expired_points_excluded": certConfig.ExcludeExpiredPoints,
+				},
 			},
 			"achievements": achievementDetails,
 		},
@@ -417,13 +454,13 @@ func (s *ReportService) GetLecturerReport(ctx context.Context, lecturerID string
 
 		// #7g proses: hitung points dan count per student, lalu agregasi ke total
 		for _, achievement := range achievements {
-			_, exists := referenceMap[achievement.ID.Hex()]
-			if !exists {
+			if _, exists := s.resolveReference(ctx, referenceMap, achievement); !exists {
 				continue
 			}
 
-			totalPoints += achievement.Points
-			studentPoints += achievement.Points
+			points := achievement.PointsForStudent(student.ID)
+			totalPoints += points
+			studentPoints += points
 			totalAchievements++
 			studentAchievementCount++
 			byType[achievement.AchievementType]++
@@ -547,3 +584,183 @@ func (s *ReportService) GetCurrentLecturerReport(ctx context.Context, userID str
 
 	return s.GetLecturerReport(ctx, lecturer.ID)
 }
+
+// #10 proses: ambil laporan kemungkinan prestasi duplikat lintas semua mahasiswa, hanya untuk admin
+func (s *ReportService) GetDuplicateReport(ctx context.Context, userID string, roleID string) (map[string]interface{}, error) {
+	// #10a proses: validasi user harus memiliki role Admin
+	roleName, err := s.userRepo.GetRoleName(ctx, roleID)
+	if err != nil {
+		return nil, errors.New("error mengambil role name: " + err.Error())
+	}
+
+	if roleName != "Admin" {
+		return nil, errors.New("akses ditolak. Hanya admin yang dapat melihat laporan duplikat")
+	}
+
+	// #10b proses: ambil kelompok kunci duplikat yang dipakai lebih dari satu achievement
+	keyGroups, err := s.achievementRepo.GetDuplicateKeyGroups(ctx)
+	if err != nil {
+		return nil, errors.New("error mengambil data duplikat prestasi: " + err.Error())
+	}
+
+	// #10c proses: ambil semua achievement yang terlibat sekaligus
+	idSet := map[string]bool{}
+	ids := []string{}
+	for _, group := range keyGroups {
+		for _, id := range group.AchievementIDs {
+			if !idSet[id] {
+				idSet[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+
+	achievementMap := make(map[string]modelmongo.Achievement)
+	if len(ids) > 0 {
+		achievements, err := s.achievementRepo.GetAchievementsByIDs(ctx, ids)
+		if err != nil {
+			return nil, errors.New("error mengambil data prestasi: " + err.Error())
+		}
+		for _, achievement := range achievements {
+			achievementMap[achievement.ID.Hex()] = achievement
+		}
+	}
+
+	// #10c1 proses: ambil reference semua achievement yang terlibat dalam satu query untuk status dan prestasi yang sudah dihapus
+	refs, err := loadDuplicateReferences(ctx, s.achievementRefRepo, ids)
+	if err != nil {
+		return nil, errors.New("error mengambil achievement references: " + err.Error())
+	}
+
+	// #10d proses: build kelompok duplikat, lewati prestasi yang sudah dihapus dan kelompok yang tersisa satu prestasi
+	groups := []modelmongo.DuplicateGroup{}
+	for _, group := range keyGroups {
+		matchType, value := parseDuplicateKey(group.Key)
+
+		matches := []modelmongo.Achievement{}
+		for _, id := range group.AchievementIDs {
+			if achievement, ok := achievementMap[id]; ok {
+				matches = append(matches, achievement)
+			}
+		}
+
+		candidates := buildDuplicateCandidates(refs, []string{group.Key}, matches, "", false)
+		if len(candidates) < 2 {
+			continue
+		}
+
+		// #10e proses: tandai apakah semua prestasi dalam kelompok milik mahasiswa yang sama
+		sameStudent := true
+		for _, candidate := range candidates {
+			if candidate.StudentID != candidates[0].StudentID {
+				sameStudent = false
+			}
+		}
+		for i := range candidates {
+			candidates[i].SameStudent = sameStudent
+		}
+
+		groups = append(groups, modelmongo.DuplicateGroup{
+			MatchedOn:    matchType,
+			Value:        value,
+			Achievements: candidates,
+		})
+	}
+
+	// #10f proses: build response dengan daftar kelompok duplikat
+	return map[string]interface{}{
+		"status": "success",
+		"data": map[string]interface{}{
+			"totalGroups": len(groups),
+			"groups":      groups,
+		},
+	}, nil
+}
+
+// #11 proses: ambil reference dari map, prestasi tim milik mahasiswa lain diambil langsung berdasarkan mongo ID
+func (s *ReportService) resolveReference(ctx context.Context, referenceMap map[string]modelpostgre.AchievementReference, achievement modelmongo.Achievement) (modelpostgre.AchievementReference, bool) {
+	if ref, exists := referenceMap[achievement.ID.Hex()]; exists {
+		return ref, true
+	}
+
+	if !achievement.IsTeam() {
+		return modelpostgre.AchievementReference{}, false
+	}
+
+	ref, err := s.achievementRefRepo.GetAchievementReferenceByMongoID(ctx, achievement.ID.Hex())
+	if err != nil || ref == nil || ref.Status == "deleted" {
+		return modelpostgre.AchievementReference{}, false
+	}
+
+	referenceMap[achievement.ID.Hex()] = *ref
+	return *ref, true
+}
+
+// #12 proses: ambil laporan pemakaian storage attachment per mahasiswa beserta penghematan dari deduplikasi file, hanya untuk admin
+func (s *ReportService) GetStorageUsageReport(ctx context.Context, userID string, roleID string) (map[string]interface{}, error) {
+	// #12a proses: validasi user harus memiliki role Admin
+	roleName, err := s.userRepo.GetRoleName(ctx, roleID)
+	if err != nil {
+		return nil, errors.New("error mengambil role name: " + err.Error())
+	}
+
+	if roleName != "Admin" {
+		return nil, errors.New("akses ditolak. Hanya admin yang dapat melihat laporan pemakaian storage")
+	}
+
+	// #12b proses: ambil total attachment dan file unik, termasuk prestasi di tempat sampah karena filenya masih tersimpan
+	totals, err := s.achievementRepo.GetAttachmentStorageTotals(ctx)
+	if err != nil {
+		return nil, errors.New("error mengambil total storage attachment: " + err.Error())
+	}
+
+	// #12c proses: ambil pemakaian per mahasiswa dan data mahasiswa untuk nama dan NIM
+	usages, err := s.achievementRepo.GetAttachmentUsageByStudent(ctx)
+	if err != nil {
+		return nil, errors.New("error mengambil pemakaian storage mahasiswa: " + err.Error())
+	}
+
+	students, err := s.studentRepo.GetAllStudents(ctx)
+	if err != nil {
+		return nil, errors.New("error mengambil data mahasiswa: " + err.Error())
+	}
+	studentMap := make(map[string]modelpostgre.Student)
+	for _, student := range students {
+		studentMap[student.ID] = student
+	}
+
+	// #12d proses: build daftar pemakaian per mahasiswa, urutan dari pemakaian terbesar mengikuti repository
+	quota := LoadAttachmentQuota()
+	studentUsages := []map[string]interface{}{}
+	for _, usage := range usages {
+		student := studentMap[usage.StudentID]
+		entry := map[string]interface{}{
+			"id":         usage.StudentID,
+			"name":       student.FullName,
+			"student_id": student.StudentID,
+			"files":      usage.Files,
+			"bytes":      usage.Bytes,
+			"over_quota": quota > 0 && usage.Bytes > quota,
+		}
+		if quota > 0 {
+			entry["quota_used_percent"] = float64(usage.Bytes) * 100 / float64(quota)
+		}
+		studentUsages = append(studentUsages, entry)
+	}
+
+	// #12e proses: build response dengan total, kuota, dan pemakaian per mahasiswa
+	return map[string]interface{}{
+		"status": "success",
+		"data": map[string]interface{}{
+			"quota_bytes": quota,
+			"totals": map[string]interface{}{
+				"attachments":   totals.Attachments,
+				"unique_files":  totals.UniqueFiles,
+				"logical_bytes": totals.LogicalBytes,
+				"stored_bytes":  totals.StoredBytes,
+				"saved_bytes":   totals.LogicalBytes - totals.StoredBytes,
+			},
+			"students": studentUsages,
+		},
+	}, nil
+}
@@ -0,0 +1,333 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk context, errors, fmt, os, strconv, strings, repository, dan time
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// #2 proses: definisikan interface untuk pengecekan SLA verifikasi prestasi
+type ISLAService interface {
+	RunSLACheck(ctx context.Context) (*modelpostgre.SLACheckResult, error)
+	GetOverdueAchievements(ctx context.Context, userID string, roleID string) (*modelpostgre.GetOverdueAchievementsResponse, error)
+}
+
+// #3 proses: struct service untuk SLA dengan dependency achievement reference, achievement MongoDB, student, user, dan notification service
+type SLAService struct {
+	achievementRefRepo  repositorypostgre.IAchievementReferenceRepository
+	achievementRepo     repositorymongo.IAchievementRepository
+	studentRepo         repositorypostgre.IStudentRepository
+	userRepo            repositorypostgre.IUserRepository
+	notificationService INotificationService
+	config              modelpostgre.SLAConfig
+}
+
+// #4 proses: constructor untuk membuat instance SLAService baru
+func NewSLAService(
+	achievementRefRepo repositorypostgre.IAchievementReferenceRepository,
+	achievementRepo repositorymongo.IAchievementRepository,
+	studentRepo repositorypostgre.IStudentRepository,
+	userRepo repositorypostgre.IUserRepository,
+	notificationService INotificationService,
+	config modelpostgre.SLAConfig,
+) ISLAService {
+	return &SLAService{
+		achievementRefRepo:  achievementRefRepo,
+		achievementRepo:     achievementRepo,
+		studentRepo:         studentRepo,
+		userRepo:            userRepo,
+		notificationService: notificationService,
+		config:              config,
+	}
+}
+
+// #5 proses: baca konfigurasi SLA dari environment variable, nilai kosong atau tidak valid memakai default
+func LoadSLAConfig() modelpostgre.SLAConfig {
+	return modelpostgre.SLAConfig{
+		ReminderAfter:      time.Duration(envInt("SLA_REMINDER_HOURS", 72, 1)) * time.Hour,
+		EscalateAfter:      time.Duration(envInt("SLA_ESCALATION_HOURS", 168, 1)) * time.Hour,
+		CheckInterval:      time.Duration(envInt("SLA_CHECK_INTERVAL_MINUTES", 60, 0)) * time.Minute,
+		FallbackVerifierID: strings.TrimSpace(os.Getenv("SLA_FALLBACK_VERIFIER_ID")),
+	}
+}
+
+// #6 proses: baca environment variable bertipe angka dengan default dan batas minimum
+func envInt(key string, defaultValue int, minValue int) int {
+	value, err := strconv.Atoi(strings.TrimSpace(os.Getenv(key)))
+	if err != nil || value < minValue {
+		return defaultValue
+	}
+	return value
+}
+
+// #7 proses: jalankan pengecekan SLA secara berkala di background, interval 0 berarti scheduler dinonaktifkan
+func StartSLAScheduler(ctx context.Context, slaService ISLAService, interval time.Duration) {
+	if interval <= 0 {
+		fmt.Println("SLA scheduler dinonaktifkan")
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			// #7a proses: setiap pengecekan diberi timeout supaya tidak menumpuk dengan pengecekan berikutnya
+			checkCtx, cancel := context.WithTimeout(ctx, interval)
+			result, err := slaService.RunSLACheck(checkCtx)
+			cancel()
+
+			if err != nil {
+				fmt.Printf("Error running SLA check: %v\n", err)
+			} else if result.Reminded > 0 || result.Escalated > 0 {
+				fmt.Printf("SLA check: %d checked, %d reminded, %d escalated\n", result.Checked, result.Reminded, result.Escalated)
+			}
+
+			// #7b proses: tunggu tick berikutnya atau berhenti jika context dibatalkan
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// #8 proses: cari prestasi submitted yang melewati batas SLA, kirim pengingat ke dosen wali lalu eskalasi ke admin atau verifikator pengganti
+func (s *SLAService) RunSLACheck(ctx context.Context) (*modelpostgre.SLACheckResult, error) {
+	// #8a proses: ambil semua prestasi submitted yang sudah melewati batas pengingat
+	now := time.Now()
+	refs, err := s.achievementRefRepo.GetSubmittedReferencesBefore(ctx, now.Add(-s.config.ReminderAfter))
+	if err != nil {
+		return nil, errors.New("error mengambil prestasi yang menunggu verifikasi: " + err.Error())
+	}
+
+	result := &modelpostgre.SLACheckResult{}
+	for i := range refs {
+		ref := &refs[i]
+		result.Checked++
+
+		// #8b proses: ambil history supaya pengingat dan eskalasi hanya dikirim sekali per submit
+		history, err := s.achievementRefRepo.GetAchievementHistoryByRefID(ctx, ref.ID)
+		if err != nil {
+			fmt.Printf("Error reading achievement history: %v\n", err)
+			continue
+		}
+
+		age := now.Sub(*ref.SubmittedAt)
+		waitingDays := int(age.Hours()) / 24
+
+		// #8c proses: lewat batas eskalasi, kirim notifikasi ke admin atau verifikator pengganti
+		if age >= s.config.EscalateAfter {
+			if historyActionSince(history, modelpostgre.AchievementHistoryActionSLAEscalated, ref.SubmittedAt) != nil {
+				continue
+			}
+
+			recipients, err := s.escalationRecipients(ctx)
+			if err != nil {
+				fmt.Printf("Error resolving SLA escalation recipients: %v\n", err)
+				continue
+			}
+
+			// #8c1 proses: klaim eskalasi lebih dulu supaya replika lain yang menjalankan pengecekan bersamaan tidak mengirim notifikasi yang sama
+			if !s.claimSLANotification(ctx, ref, modelpostgre.AchievementHistoryActionSLAEscalated) {
+				continue
+			}
+
+			if err := s.notificationService.CreateSLAEscalationNotification(ctx, recipients, ref.MongoAchievementID, ref.ID, waitingDays); err != nil {
+				fmt.Printf("Error creating SLA escalation notification: %v\n", err)
+				s.releaseSLANotification(ctx, ref, modelpostgre.AchievementHistoryActionSLAEscalated)
+				continue
+			}
+
+			s.recordSLAHistory(ctx, ref.ID, modelpostgre.AchievementHistoryActionSLAEscalated, fmt.Sprintf("dieskalasikan setelah menunggu verifikasi selama %d hari", waitingDays))
+			result.Escalated++
+			continue
+		}
+
+		// #8d proses: lewat batas pengingat, kirim pengingat ke dosen wali
+		if historyActionSince(history, modelpostgre.AchievementHistoryActionSLAReminder, ref.SubmittedAt) != nil {
+			continue
+		}
+
+		// #8d1 proses: klaim pengingat lebih dulu, jika gagal dikirim klaim dilepas supaya dicoba lagi pada pengecekan berikutnya
+		if !s.claimSLANotification(ctx, ref, modelpostgre.AchievementHistoryActionSLAReminder) {
+			continue
+		}
+
+		if err := s.notificationService.CreateSLAReminderNotification(ctx, ref.StudentID, ref.MongoAchievementID, ref.ID, waitingDays); err != nil {
+			fmt.Printf("Error creating SLA reminder notification: %v\n", err)
+			s.releaseSLANotification(ctx, ref, modelpostgre.AchievementHistoryActionSLAReminder)
+			continue
+		}
+
+		s.recordSLAHistory(ctx, ref.ID, modelpostgre.AchievementHistoryActionSLAReminder, fmt.Sprintf("pengingat dikirim setelah menunggu verifikasi selama %d hari", waitingDays))
+		result.Reminded++
+	}
+
+	return result, nil
+}
+
+// #9 proses: ambil daftar prestasi yang melewati batas SLA beserta umurnya, hanya untuk admin
+func (s *SLAService) GetOverdueAchievements(ctx context.Context, userID string, roleID string) (*modelpostgre.GetOverdueAchievementsResponse, error) {
+	// #9a proses: validasi user harus memiliki role Admin
+	roleName, err := s.userRepo.GetRoleName(ctx, roleID)
+	if err != nil {
+		return nil, errors.New("error mengambil role name: " + err.Error())
+	}
+
+	if roleName != "Admin" {
+		return nil, errors.New("akses ditolak. Hanya admin yang dapat melihat prestasi yang melewati batas SLA")
+	}
+
+	// #9b proses: ambil semua prestasi submitted yang sudah melewati batas pengingat, urut dari yang paling lama
+	now := time.Now()
+	refs, err := s.achievementRefRepo.GetSubmittedReferencesBefore(ctx, now.Add(-s.config.ReminderAfter))
+	if err != nil {
+		return nil, errors.New("error mengambil prestasi yang menunggu verifikasi: " + err.Error())
+	}
+
+	overdue := []modelpostgre.OverdueAchievement{}
+	for _, ref := range refs {
+		age := now.Sub(*ref.SubmittedAt)
+
+		// #9c proses: tentukan tahap SLA berdasarkan umur prestasi
+		item := modelpostgre.OverdueAchievement{
+			AchievementRefID:   ref.ID,
+			MongoAchievementID: ref.MongoAchievementID,
+			StudentID:          ref.StudentID,
+			SubmittedAt:        *ref.SubmittedAt,
+			AgeHours:           int(age.Hours()),
+			AgeDays:            int(age.Hours()) / 24,
+			Stage:              modelpostgre.SLAStageReminder,
+		}
+		if age >= s.config.EscalateAfter {
+			item.Stage = modelpostgre.SLAStageEscalated
+		}
+
+		// #9d proses: ambil waktu pengingat dan eskalasi yang sudah dikirim sejak submit terakhir
+		history, err := s.achievementRefRepo.GetAchievementHistoryByRefID(ctx, ref.ID)
+		if err == nil {
+			item.RemindedAt = historyActionSince(history, modelpostgre.AchievementHistoryActionSLAReminder, ref.SubmittedAt)
+			item.EscalatedAt = historyActionSince(history, modelpostgre.AchievementHistoryActionSLAEscalated, ref.SubmittedAt)
+		}
+
+		// #9e proses: lengkapi nama mahasiswa dan dosen wali, dosen wali yang ditunjuk pada prestasi tim diutamakan
+		student, err := s.studentRepo.GetStudentByID(ctx, ref.StudentID)
+		if err == nil && student != nil {
+			item.StudentName = student.FullName
+			item.AdvisorID = student.AdvisorID
+		}
+
+		achievement, err := s.achievementRepo.GetAchievementByID(ctx, ref.MongoAchievementID)
+		if err == nil && achievement != nil && achievement.DesignatedAdvisorID != "" {
+			item.AdvisorID = achievement.DesignatedAdvisorID
+		}
+
+		if item.AdvisorID != "" {
+			item.AdvisorName = s.lecturerFullName(ctx, item.AdvisorID)
+		}
+
+		overdue = append(overdue, item)
+	}
+
+	// #9f proses: build response dengan batas SLA yang berlaku
+	response := &modelpostgre.GetOverdueAchievementsResponse{
+		Status: "success",
+	}
+	response.Data.ReminderAfterHours = int(s.config.ReminderAfter.Hours())
+	response.Data.EscalateAfterHours = int(s.config.EscalateAfter.Hours())
+	response.Data.Total = len(overdue)
+	response.Data.Achievements = overdue
+
+	return response, nil
+}
+
+// #10 proses: tentukan penerima eskalasi, verifikator pengganti jika dikonfigurasi, selain itu semua admin aktif
+func (s *SLAService) escalationRecipients(ctx context.Context) ([]string, error) {
+	if s.config.FallbackVerifierID != "" {
+		lecturer, err := s.userRepo.GetLecturerByID(ctx, s.config.FallbackVerifierID)
+		if err == nil && lecturer != nil {
+			return []string{lecturer.UserID}, nil
+		}
+		fmt.Printf("Error reading SLA fallback verifier, escalating to admins: %v\n", err)
+	}
+
+	adminIDs, err := s.userRepo.GetActiveUserIDsByRoleName(ctx, "Admin")
+	if err != nil {
+		return nil, err
+	}
+	if len(adminIDs) == 0 {
+		return nil, errors.New("tidak ada admin aktif untuk menerima eskalasi")
+	}
+
+	return adminIDs, nil
+}
+
+// #11 proses: catat pengingat atau eskalasi ke history prestasi, error pencatatan hanya di-log
+func (s *SLAService) recordSLAHistory(ctx context.Context, refID string, action string, note string) {
+	req := modelpostgre.CreateAchievementHistoryRequest{
+		AchievementRefID: refID,
+		Action:           action,
+		Status:           modelpostgre.AchievementStatusSubmitted,
+		Note:             &note,
+	}
+
+	if err := s.achievementRefRepo.CreateAchievementHistory(ctx, req); err != nil {
+		fmt.Printf("Error recording achievement history: %v\n", err)
+	}
+}
+
+// #11a proses: klaim notifikasi SLA per submit dan tahap, error klaim dianggap belum diklaim dan hanya di-log
+func (s *SLAService) claimSLANotification(ctx context.Context, ref *modelpostgre.AchievementReference, action string) bool {
+	claimed, err := s.achievementRefRepo.ClaimSLANotification(ctx, ref.ID, *ref.SubmittedAt, action)
+	if err != nil {
+		fmt.Printf("Error claiming SLA notification: %v\n", err)
+		return false
+	}
+	return claimed
+}
+
+func (s *SLAService) releaseSLANotification(ctx context.Context, ref *modelpostgre.AchievementReference, action string) {
+	if err := s.achievementRefRepo.ReleaseSLANotification(ctx, ref.ID, *ref.SubmittedAt, action); err != nil {
+		fmt.Printf("Error releasing SLA notification: %v\n", err)
+	}
+}
+
+// #12 proses: ambil nama lengkap dosen wali berdasarkan lecturer ID, kosong jika tidak ditemukan
+func (s *SLAService) lecturerFullName(ctx context.Context, lecturerID string) string {
+	lecturer, err := s.userRepo.GetLecturerByID(ctx, lecturerID)
+	if err != nil || lecturer == nil {
+		return ""
+	}
+
+	user, err := s.userRepo.FindUserByID(ctx, lecturer.UserID)
+	if err != nil || user == nil {
+		return ""
+	}
+
+	return user.FullName
+}
+
+// #13 proses: verifikator pengganti boleh memverifikasi atau menolak prestasi yang sudah dieskalasikan sejak submit terakhir
+func (s *AchievementService) isEscalatedFallbackVerifier(ctx context.Context, ref *modelpostgre.AchievementReference, lecturerID string) bool {
+	fallbackVerifierID := s.slaConfig.FallbackVerifierID
+	if fallbackVerifierID == "" || fallbackVerifierID != lecturerID {
+		return false
+	}
+
+	history, err := s.achievementRefRepo.GetAchievementHistoryByRefID(ctx, ref.ID)
+	if err != nil {
+		return false
+	}
+
+	return historyActionSince(history, modelpostgre.AchievementHistoryActionSLAEscalated, ref.SubmittedAt) != nil
+}
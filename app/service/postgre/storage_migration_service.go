@@ -0,0 +1,249 @@
+package service
+
+// #1 proses: import library yang diperlukan untuk context, errors, fmt, strings, tabwriter, time, model, dan repository
+import (
+	"context"
+	"errors"
+	"fmt"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorystorage "sistem-pelaporan-prestasi-mahasiswa/app/repository/storage"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// #2 proses: definisikan interface untuk memindahkan file attachment dari satu storage ke storage lain
+type IStorageMigrationService interface {
+	MigrateAttachments(ctx context.Context, dryRun bool, deleteSource bool) (*modelmongo.StorageMigrationResult, error)
+}
+
+// #3 proses: struct service migrasi storage dengan dependency repository achievement, storage asal, dan storage tujuan
+type StorageMigrationService struct {
+	achievementRepo repositorymongo.IAchievementRepository
+	source          repositorystorage.IFileStorage
+	target          repositorystorage.IFileStorage
+}
+
+// #4 proses: constructor untuk membuat instance StorageMigrationService baru
+func NewStorageMigrationService(
+	achievementRepo repositorymongo.IAchievementRepository,
+	source repositorystorage.IFileStorage,
+	target repositorystorage.IFileStorage,
+) IStorageMigrationService {
+	return &StorageMigrationService{
+		achievementRepo: achievementRepo,
+		source:          source,
+		target:          target,
+	}
+}
+
+// #5 proses: pindahkan semua file attachment yang masih di storage asal lalu ganti Attachment.FileURL, aman dijalankan ulang karena file yang sudah ada di tujuan tidak disalin lagi
+func (s *StorageMigrationService) MigrateAttachments(ctx context.Context, dryRun bool, deleteSource bool) (*modelmongo.StorageMigrationResult, error) {
+	// #5a proses: tolak migrasi ke storage yang sama karena URL tidak berubah dan hapus sumber akan menghapus file itu sendiri
+	if s.source.URL("") == s.target.URL("") {
+		return nil, errors.New("storage tujuan sama dengan storage asal")
+	}
+
+	result := &modelmongo.StorageMigrationResult{
+		SourceDriver: s.source.Driver(),
+		TargetDriver: s.target.Driver(),
+		DryRun:       dryRun,
+		DeleteSource: deleteSource,
+		StartedAt:    time.Now(),
+		Items:        []modelmongo.StorageMigrationItem{},
+	}
+
+	// #5b proses: ambil achievement yang masih punya attachment dengan URL storage asal, termasuk yang ada di tempat sampah
+	achievements, err := s.achievementRepo.GetAchievementsByAttachmentURLPrefix(ctx, s.source.URL(""))
+	if err != nil {
+		return nil, errors.New("error mengambil data prestasi dari database: " + err.Error())
+	}
+
+	for _, achievement := range achievements {
+		for _, attachment := range achievement.Attachments {
+			key, ok := s.source.KeyFromURL(attachment.FileURL)
+			if !ok {
+				continue
+			}
+
+			item := modelmongo.StorageMigrationItem{
+				AchievementID: achievement.ID.Hex(),
+				FileName:      attachment.FileName,
+				Key:           key,
+				OldURL:        attachment.FileURL,
+				NewURL:        s.target.URL(key),
+			}
+
+			s.migrateAttachment(ctx, &item, attachment.FileType, attachment.HasPreview, dryRun, deleteSource)
+
+			// #5c proses: hitung ringkasan per status
+			result.Total++
+			switch item.Status {
+			case modelmongo.StorageMigrationStatusMigrated:
+				result.Migrated++
+			case modelmongo.StorageMigrationStatusMissing:
+				result.Missing++
+			case modelmongo.StorageMigrationStatusSkipped:
+				result.Skipped++
+			case modelmongo.StorageMigrationStatusFailed:
+				result.Failed++
+			}
+			result.Items = append(result.Items, item)
+		}
+	}
+
+	return result, nil
+}
+
+// #6 proses: pindahkan satu file attachment, URL di MongoDB baru diganti setelah file di tujuan terverifikasi dan file asal baru dihapus setelah URL diganti
+func (s *StorageMigrationService) migrateAttachment(ctx context.Context, item *modelmongo.StorageMigrationItem, fileType string, hasPreview bool, dryRun bool, deleteSource bool) {
+	// #6a proses: file yang tidak ada di storage asal dilaporkan dan URL tidak diubah
+	sourceInfo, err := s.source.Stat(ctx, item.Key)
+	if err != nil {
+		if errors.Is(err, repositorystorage.ErrFileNotFound) {
+			item.Status = modelmongo.StorageMigrationStatusMissing
+			item.Error = "file tidak ditemukan di storage asal"
+			return
+		}
+		item.Status = modelmongo.StorageMigrationStatusFailed
+		item.Error = "error membaca file di storage asal: " + err.Error()
+		return
+	}
+	item.Size = sourceInfo.Size
+
+	// #6b proses: dry-run hanya mencatat file yang akan dipindah
+	if dryRun {
+		item.Status = modelmongo.StorageMigrationStatusPending
+		return
+	}
+
+	// #6c proses: salin file jika belum ada di tujuan dengan ukuran yang sama, file sisa migrasi sebelumnya yang terputus tidak disalin ulang
+	targetInfo, err := s.target.Stat(ctx, item.Key)
+	if err != nil || targetInfo.Size != sourceInfo.Size {
+		if err := s.copyFile(ctx, item.Key, fileType); err != nil {
+			item.Status = modelmongo.StorageMigrationStatusFailed
+			item.Error = err.Error()
+			return
+		}
+
+		// #6d proses: verifikasi ukuran file di tujuan sebelum URL diganti
+		targetInfo, err = s.target.Stat(ctx, item.Key)
+		if err != nil {
+			item.Status = modelmongo.StorageMigrationStatusFailed
+			item.Error = "error verifikasi file di storage tujuan: " + err.Error()
+			return
+		}
+		if targetInfo.Size != sourceInfo.Size {
+			item.Status = modelmongo.StorageMigrationStatusFailed
+			item.Error = fmt.Sprintf("ukuran file di storage tujuan tidak sama: %d, seharusnya %d", targetInfo.Size, sourceInfo.Size)
+			return
+		}
+	}
+
+	// #6e proses: salin preview ke samping file di tujuan karena key preview mengikuti key file asli, preview yang tidak ada di storage asal dilewati
+	if hasPreview {
+		if err := s.copyPreview(ctx, item.Key); err != nil {
+			item.Status = modelmongo.StorageMigrationStatusFailed
+			item.Error = err.Error()
+			return
+		}
+	}
+
+	// #6f proses: ganti URL attachment, false berarti attachment sudah diubah atau dihapus sejak daftar diambil
+	updated, err := s.achievementRepo.UpdateAttachmentFileURL(ctx, item.AchievementID, item.OldURL, item.NewURL)
+	if err != nil {
+		item.Status = modelmongo.StorageMigrationStatusFailed
+		item.Error = "error mengganti URL attachment: " + err.Error()
+		return
+	}
+	if !updated {
+		item.Status = modelmongo.StorageMigrationStatusSkipped
+		item.Error = "attachment sudah berubah sejak migrasi dimulai"
+		return
+	}
+
+	item.Status = modelmongo.StorageMigrationStatusMigrated
+
+	// #6g proses: hapus file asal dan preview-nya jika diminta dan tidak ada attachment lain yang masih memakai file yang sama di storage asal, gagal hapus tidak membatalkan migrasi karena URL sudah menunjuk ke tujuan
+	if deleteSource {
+		count, err := s.achievementRepo.CountAttachmentFileReferences(ctx, item.OldURL)
+		if err != nil {
+			item.Error = "file sudah dipindah tetapi gagal menghitung pemakaian file di storage asal: " + err.Error()
+			return
+		}
+		if count > 0 {
+			return
+		}
+		if err := s.source.Delete(ctx, item.Key); err != nil {
+			item.Error = "file sudah dipindah tetapi gagal dihapus dari storage asal: " + err.Error()
+		}
+		if hasPreview {
+			if err := s.source.Delete(ctx, attachmentPreviewKey(item.Key)); err != nil {
+				item.Error = "preview sudah dipindah tetapi gagal dihapus dari storage asal: " + err.Error()
+			}
+		}
+	}
+}
+
+// #7 proses: salin isi file dari storage asal ke storage tujuan secara streaming
+func (s *StorageMigrationService) copyFile(ctx context.Context, key string, fileType string) error {
+	body, info, err := s.source.Get(ctx, key)
+	if err != nil {
+		return errors.New("error membaca file di storage asal: " + err.Error())
+	}
+	defer body.Close()
+
+	contentType := fileType
+	if contentType == "" {
+		contentType = info.ContentType
+	}
+
+	if err := s.target.Put(ctx, key, body, info.Size, contentType); err != nil {
+		return errors.New("error menyimpan file di storage tujuan: " + err.Error())
+	}
+	return nil
+}
+
+// #8 proses: salin preview attachment jika ada di storage asal dan belum ada di tujuan dengan ukuran yang sama
+func (s *StorageMigrationService) copyPreview(ctx context.Context, key string) error {
+	previewKey := attachmentPreviewKey(key)
+	sourceInfo, err := s.source.Stat(ctx, previewKey)
+	if err != nil {
+		if errors.Is(err, repositorystorage.ErrFileNotFound) {
+			return nil
+		}
+		return errors.New("error membaca preview di storage asal: " + err.Error())
+	}
+
+	if targetInfo, err := s.target.Stat(ctx, previewKey); err == nil && targetInfo.Size == sourceInfo.Size {
+		return nil
+	}
+	return s.copyFile(ctx, previewKey, attachmentPreviewContentType)
+}
+
+// #9 proses: format hasil migrasi storage sebagai laporan teks untuk command line
+func FormatStorageMigrationReport(result *modelmongo.StorageMigrationResult) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Migrasi storage attachment %s ke %s (%s)\n", result.SourceDriver, result.TargetDriver, result.StartedAt.Format("2006-01-02 15:04:05"))
+	if result.DryRun {
+		b.WriteString("Dry run: tidak ada file yang dipindah\n")
+	}
+	b.WriteString("\n")
+
+	if len(result.Items) == 0 {
+		b.WriteString("Tidak ada attachment di storage asal.\n")
+		return b.String()
+	}
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PRESTASI\tKEY\tUKURAN\tSTATUS\tKETERANGAN")
+	for _, item := range result.Items {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", item.AchievementID, item.Key, item.Size, item.Status, item.Error)
+	}
+	w.Flush()
+
+	fmt.Fprintf(&b, "\nTotal: %d attachment, %d dipindah, %d tidak ditemukan, %d dilewati, %d gagal\n", result.Total, result.Migrated, result.Missing, result.Skipped, result.Failed)
+	return b.String()
+}
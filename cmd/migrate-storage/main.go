@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorystorage "sistem-pelaporan-prestasi-mahasiswa/app/repository/storage"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/config"
+	"sistem-pelaporan-prestasi-mahasiswa/database"
+	"time"
+)
+
+func main() {
+
+	config.LoadEnv()
+
+	sourceDir := flag.String("source-dir", database.LocalStorageDir(), "folder lokal tempat file attachment lama disimpan")
+	dryRun := flag.Bool("dry-run", false, "hanya tampilkan file yang akan dipindah")
+	deleteSource := flag.Bool("delete-source", false, "hapus file lokal setelah dipindah dan URL diganti")
+	format := flag.String("format", "text", "format output: text atau json")
+	timeout := flag.Duration("timeout", time.Hour, "batas waktu migrasi")
+	flag.Parse()
+
+	mongoDB := database.ConnectMongoDB()
+
+	source := repositorystorage.NewLocalFileStorage(*sourceDir, "/uploads/")
+	target := database.ConnectFileStorage()
+
+	migrationService := servicepostgre.NewStorageMigrationService(
+		repositorymongo.NewAchievementRepository(mongoDB),
+		source,
+		target,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	result, err := migrationService.MigrateAttachments(ctx, *dryRun, *deleteSource)
+	if err != nil {
+		log.Fatalf("Storage migration failed: %v", err)
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			log.Fatalf("Failed to encode result: %v", err)
+		}
+	} else {
+		fmt.Print(servicepostgre.FormatStorageMigrationReport(result))
+	}
+
+	if result.Failed > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/config"
+	"sistem-pelaporan-prestasi-mahasiswa/database"
+	"time"
+)
+
+func main() {
+
+	config.LoadEnv()
+	reconcileConfig := servicepostgre.LoadReconcileConfig()
+
+	mongoOrphans := flag.String("mongo-orphans", reconcileConfig.DefaultPolicy.MongoWithoutReference, "policy untuk dokumen MongoDB tanpa reference: report, purge, adopt")
+	referenceOrphans := flag.String("reference-orphans", reconcileConfig.DefaultPolicy.ReferenceWithoutMongo, "policy untuk reference tanpa dokumen MongoDB: report, delete_reference")
+	deletedMismatch := flag.String("deleted-mismatch", reconcileConfig.DefaultPolicy.DeletedMismatch, "policy untuk status hapus yang tidak sama: report, follow_reference, follow_mongo")
+	format := flag.String("format", "text", "format output: text atau json")
+	timeout := flag.Duration("timeout", 10*time.Minute, "batas waktu rekonsiliasi")
+	flag.Parse()
+
+	policy, err := servicepostgre.NormalizeReconcilePolicy(modelpostgre.ReconcilePolicy{
+		MongoWithoutReference: *mongoOrphans,
+		ReferenceWithoutMongo: *referenceOrphans,
+		DeletedMismatch:       *deletedMismatch,
+	})
+	if err != nil {
+		log.Fatalf("Invalid policy: %v", err)
+	}
+
+	postgresDB := database.ConnectDB()
+	defer postgresDB.Close()
+
+	mongoDB := database.ConnectMongoDB()
+
+	reconciliationService := servicepostgre.NewReconciliationService(
+		repositorypostgre.NewAchievementReferenceRepository(postgresDB),
+		repositorymongo.NewAchievementRepository(mongoDB),
+		repositorypostgre.NewUserRepository(postgresDB),
+		database.ConnectFileStorage(),
+		reconcileConfig,
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	result, err := reconciliationService.RunReconciliation(ctx, policy)
+	if err != nil {
+		log.Fatalf("Reconciliation failed: %v", err)
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(result); err != nil {
+			log.Fatalf("Failed to encode result: %v", err)
+		}
+	} else {
+		fmt.Print(servicepostgre.FormatReconcileReport(result))
+	}
+
+	if result.TotalUnresolved > 0 {
+		os.Exit(1)
+	}
+}
@@ -8,7 +8,7 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/cors"
 )
 
-// #2 proses: inisialisasi Fiber app untuk MongoDB dengan CORS, error handler, dan static files
+// #2 proses: inisialisasi Fiber app untuk MongoDB dengan CORS dan error handler, file attachment tidak disajikan sebagai static file dan hanya bisa diambil lewat endpoint download
 func NewApp() *fiber.App {
 	// #2a proses: buat Fiber app dengan body limit dan custom error handler
 	app := fiber.New(fiber.Config{
@@ -37,8 +37,5 @@ func NewApp() *fiber.App {
 		AllowCredentials: true,
 	}))
 
-	// #2c proses: setup static file serving untuk uploads directory
-	app.Static("/uploads", "./uploads")
-
 	return app
 }
@@ -1,20 +1,29 @@
 package database
 
-// #1 proses: import library yang diperlukan untuk context, database, fmt, log, time, dan MongoDB driver
+// #1 proses: import library yang diperlukan untuk context, database, fmt, log, model, time, dan MongoDB driver
 import (
 	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// nama text index achievements yang dipakai endpoint pencarian
+const achievementTextIndexName = "idx_achievement_text_search"
+
 const postgresSchemaSQL = `DROP EXTENSION IF EXISTS "uuid-ossp" CASCADE;
 
+DROP TABLE IF EXISTS sla_notification_claims CASCADE;
+DROP TABLE IF EXISTS certification_expiry_alerts CASCADE;
+DROP TABLE IF EXISTS point_rubrics CASCADE;
+DROP TABLE IF EXISTS achievement_history CASCADE;
 DROP TABLE IF EXISTS notifications CASCADE;
 DROP TABLE IF EXISTS achievement_references CASCADE;
 DROP TABLE IF EXISTS students CASCADE;
@@ -33,7 +42,7 @@ CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
 CREATE TYPE achievement_status AS ENUM ('draft', 'submitted', 'verified', 'rejected', 'deleted');
 
-CREATE TYPE notification_type AS ENUM ('achievement_rejected', 'achievement_submitted');
+CREATE TYPE notification_type AS ENUM ('achievement_rejected', 'achievement_submitted', 'team_invitation', 'achievement_sla_reminder', 'achievement_sla_escalated', 'certification_expiring', 'attachment_infected');
 
 CREATE TABLE roles (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -95,6 +104,9 @@ CREATE TABLE achievement_references (
     verified_at TIMESTAMP,
     verified_by UUID REFERENCES users(id) ON DELETE SET NULL,
     rejection_note TEXT,
+    deleted_at TIMESTAMP,
+    status_before_delete achievement_status,
+    version INTEGER NOT NULL DEFAULT 1,
     created_at TIMESTAMP DEFAULT NOW(),
     updated_at TIMESTAMP DEFAULT NOW()
 );
@@ -110,6 +122,8 @@ CREATE INDEX idx_lecturers_user_id ON lecturers(user_id);
 CREATE INDEX idx_achievement_references_student_id ON achievement_references(student_id);
 CREATE INDEX idx_achievement_references_status ON achievement_references(status);
 CREATE INDEX idx_achievement_references_verified_by ON achievement_references(verified_by);
+CREATE INDEX idx_achievement_references_deleted_at ON achievement_references(deleted_at) WHERE status = 'deleted';
+CREATE INDEX idx_achievement_references_created_mongo ON achievement_references(created_at DESC, mongo_achievement_id DESC);
 
 CREATE TABLE notifications (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -128,9 +142,54 @@ CREATE TABLE notifications (
 CREATE INDEX idx_notifications_user_id ON notifications(user_id);
 CREATE INDEX idx_notifications_is_read ON notifications(is_read);
 CREATE INDEX idx_notifications_created_at ON notifications(created_at);
+CREATE INDEX idx_notifications_user_created_id ON notifications(user_id, created_at DESC, id DESC);
 CREATE INDEX idx_notifications_achievement_id ON notifications(achievement_id);
 CREATE INDEX idx_notifications_mongo_achievement_id ON notifications(mongo_achievement_id);
 
+CREATE TABLE achievement_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_ref_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    action VARCHAR(30) NOT NULL,
+    status achievement_status NOT NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_achievement_history_ref_id ON achievement_history(achievement_ref_id, created_at);
+
+CREATE TABLE certification_expiry_alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_ref_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    valid_until DATE NOT NULL,
+    days_before INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (achievement_ref_id, valid_until, days_before)
+);
+
+CREATE TABLE sla_notification_claims (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_ref_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    submitted_at TIMESTAMP NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (achievement_ref_id, submitted_at, action)
+);
+
+CREATE TABLE point_rubrics (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    version INTEGER UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    rules JSONB NOT NULL DEFAULT '[]',
+    is_active BOOLEAN DEFAULT false,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    activated_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_point_rubrics_active ON point_rubrics(is_active) WHERE is_active = true;
+
 CREATE OR REPLACE FUNCTION update_updated_at_column()
 RETURNS TRIGGER AS $$
 BEGIN
@@ -152,6 +211,7 @@ const postgresSampleDataSQL = `-- Sample Data untuk PostgreSQL
 -- Jalankan file ini setelah menjalankan postgre_schema.sql
 
 -- Hapus data yang sudah ada (jika ada)
+DELETE FROM point_rubrics;
 DELETE FROM students;
 DELETE FROM lecturers;
 DELETE FROM role_permissions;
@@ -173,8 +233,11 @@ INSERT INTO permissions (name, resource, action, description) VALUES
 ('achievement:delete', 'achievement', 'delete', 'Menghapus data prestasi'),
 ('achievement:verify', 'achievement', 'verify', 'Memverifikasi prestasi'),
 ('user:manage', 'user', 'manage', 'Mengelola pengguna'),
+('rubric:manage', 'rubric', 'manage', 'Mengelola rubrik poin prestasi'),
+('achievement_type:manage', 'achievement_type', 'manage', 'Mengelola tipe prestasi dan skema custom field'),
 ('report:read', 'report', 'read', 'Membaca laporan prestasi'),
-('report:statistics', 'report', 'statistics', 'Melihat statistik prestasi');
+('report:statistics', 'report', 'statistics', 'Melihat statistik prestasi'),
+('report:reconcile', 'report', 'reconcile', 'Menjalankan dan memperbaiki rekonsiliasi data prestasi');
 
 -- Insert Role Permissions
 INSERT INTO role_permissions (role_id, permission_id)
@@ -183,8 +246,8 @@ FROM roles r
 CROSS JOIN permissions p
 WHERE (r.name = 'Admin' AND p.name IN (
     'achievement:create', 'achievement:read', 'achievement:update', 
-    'achievement:delete', 'achievement:verify', 'user:manage',
-    'report:read', 'report:statistics'
+    'achievement:delete', 'achievement:verify', 'user:manage', 'rubric:manage', 'achievement_type:manage',
+    'report:read', 'report:statistics', 'report:reconcile'
 ))
 OR (r.name = 'Mahasiswa' AND p.name IN (
     'achievement:create', 'achievement:read', 'achievement:update', 'achievement:delete',
@@ -195,6 +258,39 @@ OR (r.name = 'Dosen Wali' AND p.name IN (
     'report:read', 'report:statistics'
 ));
 
+-- Insert Rubrik Poin Default (versi 1, aktif)
+INSERT INTO point_rubrics (version, name, description, rules, is_active, activated_at)
+VALUES (
+    1,
+    'Rubrik Poin Default',
+    'Rubrik poin awal untuk semua tipe prestasi',
+    '[
+        {"achievement_type": "academic", "points": 20},
+        {"achievement_type": "competition", "points": 10},
+        {"achievement_type": "competition", "field": "competitionLevel", "value": "international", "points": 90},
+        {"achievement_type": "competition", "field": "competitionLevel", "value": "national", "points": 50},
+        {"achievement_type": "competition", "field": "competitionLevel", "value": "regional", "points": 25},
+        {"achievement_type": "competition", "field": "competitionLevel", "value": "local", "points": 10},
+        {"achievement_type": "competition", "field": "rank", "value": "1", "points": 30},
+        {"achievement_type": "competition", "field": "rank", "value": "2", "points": 20},
+        {"achievement_type": "competition", "field": "rank", "value": "3", "points": 10},
+        {"achievement_type": "competition", "field": "medalType", "value": "gold", "points": 15},
+        {"achievement_type": "competition", "field": "medalType", "value": "silver", "points": 10},
+        {"achievement_type": "competition", "field": "medalType", "value": "bronze", "points": 5},
+        {"achievement_type": "publication", "points": 20},
+        {"achievement_type": "publication", "field": "publicationType", "value": "journal", "points": 60},
+        {"achievement_type": "publication", "field": "publicationType", "value": "conference", "points": 40},
+        {"achievement_type": "publication", "field": "publicationType", "value": "book", "points": 50},
+        {"achievement_type": "organization", "points": 15},
+        {"achievement_type": "organization", "field": "position", "value": "ketua", "points": 25},
+        {"achievement_type": "organization", "field": "position", "value": "wakil ketua", "points": 15},
+        {"achievement_type": "certification", "points": 30},
+        {"achievement_type": "other", "points": 10}
+    ]',
+    true,
+    NOW()
+);
+
 -- Insert Users (1 Admin)
 -- Password: 123123123
 
@@ -278,6 +374,25 @@ func runMongoMigrations(db *mongo.Database) error {
 		return err
 	}
 
+	// #4d proses: reset collection achievement_types lalu buat index dan seed tipe prestasi bawaan
+	if err := dropCollectionIfExists(ctx, db, "achievement_types"); err != nil {
+		return err
+	}
+
+	if err := seedAchievementTypes(ctx, db); err != nil {
+		return err
+	}
+
+	// #4e proses: buat indexes untuk collection attachment_uploads, collection tidak di-drop karena potongan file sesi yang ada hanya dibersihkan lewat sesinya
+	if err := createAttachmentUploadIndexes(ctx, db); err != nil {
+		return err
+	}
+
+	// #4f proses: buat indexes untuk collection attachment_quota_reservations, reservasi yang kedaluwarsa dihapus otomatis oleh TTL index
+	if err := createAttachmentQuotaReservationIndexes(ctx, db); err != nil {
+		return err
+	}
+
 	log.Println("MongoDB migrations completed")
 	return nil
 }
@@ -309,7 +424,7 @@ func createAchievementIndexes(ctx context.Context, db *mongo.Database) error {
 	// #6a proses: ambil collection achievements
 	collection := db.Collection("achievements")
 
-	// #6b proses: definisikan index models untuk studentId, achievementType, createdAt, dan text search
+	// #6b proses: definisikan index models untuk studentId, achievementType, createdAt, text search, kunci duplikat, anggota tim, status scan attachment, dan URL file attachment untuk hitung referensi file
 	indexModels := []mongo.IndexModel{
 		{
 			Keys:    bson.D{{Key: "studentId", Value: 1}},
@@ -323,12 +438,22 @@ func createAchievementIndexes(ctx context.Context, db *mongo.Database) error {
 			Keys:    bson.D{{Key: "createdAt", Value: -1}},
 			Options: options.Index().SetName("idx_created_at"),
 		},
+		achievementTextIndexModel(),
+		{
+			Keys:    bson.D{{Key: "duplicateKeys", Value: 1}},
+			Options: options.Index().SetName("idx_duplicate_keys"),
+		},
 		{
-			Keys: bson.D{
-				{Key: "title", Value: "text"},
-				{Key: "description", Value: "text"},
-			},
-			Options: options.Index().SetName("idx_text_search"),
+			Keys:    bson.D{{Key: "teamMembers.studentId", Value: 1}},
+			Options: options.Index().SetName("idx_team_members_student"),
+		},
+		{
+			Keys:    bson.D{{Key: "attachments.scanStatus", Value: 1}},
+			Options: options.Index().SetName("idx_attachment_scan_status").SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "attachments.fileUrl", Value: 1}},
+			Options: options.Index().SetName("idx_attachment_file_url").SetSparse(true),
 		},
 	}
 
@@ -340,3 +465,204 @@ func createAchievementIndexes(ctx context.Context, db *mongo.Database) error {
 	log.Println("Created indexes for achievements collection")
 	return nil
 }
+
+// #7 proses: buat index unik code dan seed tipe prestasi bawaan di collection achievement_types
+func seedAchievementTypes(ctx context.Context, db *mongo.Database) error {
+	// #7a proses: ambil collection achievement_types dan buat index unik pada code
+	collection := db.Collection("achievement_types")
+
+	indexModel := mongo.IndexModel{
+		Keys:    bson.D{{Key: "code", Value: 1}},
+		Options: options.Index().SetName("idx_achievement_type_code").SetUnique(true),
+	}
+
+	if _, err := collection.Indexes().CreateOne(ctx, indexModel); err != nil {
+		return fmt.Errorf("create achievement type indexes: %w", err)
+	}
+
+	// #7b proses: insert tipe prestasi bawaan dengan status aktif
+	now := time.Now()
+	documents := make([]interface{}, 0, len(modelmongo.BuiltInAchievementTypes))
+	for _, achievementType := range modelmongo.BuiltInAchievementTypes {
+		achievementType.CustomFields = []modelmongo.CustomFieldDefinition{}
+		achievementType.IsBuiltIn = true
+		achievementType.IsActive = true
+		achievementType.CreatedAt = now
+		achievementType.UpdatedAt = now
+		documents = append(documents, achievementType)
+	}
+
+	if _, err := collection.InsertMany(ctx, documents); err != nil {
+		return fmt.Errorf("seed achievement types: %w", err)
+	}
+
+	log.Println("Seeded built-in achievement types")
+	return nil
+}
+
+// #8 proses: definisi text index achievements untuk pencarian, bobot title dan tags lebih tinggi supaya hasil lebih relevan
+func achievementTextIndexModel() mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "title", Value: "text"},
+			{Key: "description", Value: "text"},
+			{Key: "details.competitionName", Value: "text"},
+			{Key: "details.organizationName", Value: "text"},
+			{Key: "tags", Value: "text"},
+			{Key: "details.authors", Value: "text"},
+		},
+		// #8a proses: default_language none supaya kata bahasa Indonesia tidak di-stem atau dibuang sebagai stop word bahasa Inggris
+		Options: options.Index().
+			SetName(achievementTextIndexName).
+			SetDefaultLanguage("none").
+			SetWeights(bson.D{
+				{Key: "title", Value: 10},
+				{Key: "tags", Value: 5},
+				{Key: "details.competitionName", Value: 3},
+				{Key: "details.organizationName", Value: 3},
+				{Key: "details.authors", Value: 2},
+				{Key: "description", Value: 1},
+			}),
+	}
+}
+
+// #9 proses: pastikan text index achievements ada saat server start, text index lama dengan field berbeda di-drop dulu karena MongoDB hanya mengizinkan satu text index per collection
+func EnsureAchievementTextIndex(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	collection := db.Collection("achievements")
+
+	// #9a proses: cari text index lain berdasarkan key _fts
+	cursor, err := collection.Indexes().List(ctx)
+	if err != nil {
+		return fmt.Errorf("list achievement indexes: %w", err)
+	}
+
+	var indexes []struct {
+		Name string   `bson:"name"`
+		Key  bson.Raw `bson:"key"`
+	}
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return fmt.Errorf("read achievement indexes: %w", err)
+	}
+
+	for _, index := range indexes {
+		if _, err := index.Key.LookupErr("_fts"); err != nil || index.Name == achievementTextIndexName {
+			continue
+		}
+
+		if _, err := collection.Indexes().DropOne(ctx, index.Name); err != nil {
+			return fmt.Errorf("drop text index %s: %w", index.Name, err)
+		}
+		log.Printf("Dropped old text index: %s", index.Name)
+	}
+
+	// #9b proses: buat text index, tidak melakukan apa-apa jika index dengan definisi sama sudah ada
+	if _, err := collection.Indexes().CreateOne(ctx, achievementTextIndexModel()); err != nil {
+		return fmt.Errorf("create achievement text index: %w", err)
+	}
+
+	return nil
+}
+
+// #10 proses: beri ID ke attachment lama yang diupload sebelum attachment punya ID, supaya bisa dihapus dan diganti lewat API
+func EnsureAttachmentIDs(db *mongo.Database) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	collection := db.Collection("achievements")
+
+	// #10a proses: cari prestasi yang punya attachment tanpa ID, termasuk prestasi di tempat sampah
+	cursor, err := collection.Find(
+		ctx,
+		bson.M{"attachments": bson.M{"$elemMatch": bson.M{"id": bson.M{"$exists": false}}}},
+		options.Find().SetProjection(bson.M{"attachments.id": 1, "attachments.fileUrl": 1}),
+	)
+	if err != nil {
+		return fmt.Errorf("find attachments without id: %w", err)
+	}
+
+	var docs []struct {
+		ID          primitive.ObjectID `bson:"_id"`
+		Attachments []struct {
+			ID      string `bson:"id"`
+			FileURL string `bson:"fileUrl"`
+		} `bson:"attachments"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return fmt.Errorf("read attachments without id: %w", err)
+	}
+
+	// #10b proses: update per posisi array dengan syarat URL sama dan ID masih kosong, sehingga aman jika attachment berubah bersamaan
+	assigned := 0
+	for _, doc := range docs {
+		for i, attachment := range doc.Attachments {
+			if attachment.ID != "" {
+				continue
+			}
+
+			field := fmt.Sprintf("attachments.%d", i)
+			result, err := collection.UpdateOne(
+				ctx,
+				bson.M{
+					"_id":              doc.ID,
+					field + ".fileUrl": attachment.FileURL,
+					field + ".id":      bson.M{"$exists": false},
+				},
+				bson.M{"$set": bson.M{field + ".id": primitive.NewObjectID().Hex()}},
+			)
+			if err != nil {
+				return fmt.Errorf("assign attachment id: %w", err)
+			}
+			assigned += int(result.ModifiedCount)
+		}
+	}
+
+	if assigned > 0 {
+		log.Printf("Assigned ids to %d legacy attachments", assigned)
+	}
+	return nil
+}
+
+// #11 proses: buat indexes untuk collection attachment_uploads, expiresAt untuk pembersihan sesi kedaluwarsa dan studentId untuk hitung sesi aktif per mahasiswa
+func createAttachmentUploadIndexes(ctx context.Context, db *mongo.Database) error {
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("idx_upload_expires_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "studentId", Value: 1}, {Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("idx_upload_student_expires_at"),
+		},
+	}
+
+	if _, err := db.Collection("attachment_uploads").Indexes().CreateMany(ctx, indexModels); err != nil {
+		return fmt.Errorf("create attachment upload indexes: %w", err)
+	}
+
+	log.Println("Created indexes for attachment_uploads collection")
+	return nil
+}
+
+// #12 proses: buat indexes untuk collection attachment_quota_reservations, TTL index pada expiresAt membersihkan reservasi yang tertinggal dan studentId untuk menjumlahkan reservasi per mahasiswa
+func createAttachmentQuotaReservationIndexes(ctx context.Context, db *mongo.Database) error {
+	indexModels := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("idx_quota_reservation_expires_at").SetExpireAfterSeconds(0),
+		},
+		{
+			Keys:    bson.D{{Key: "studentId", Value: 1}, {Key: "expiresAt", Value: 1}},
+			Options: options.Index().SetName("idx_quota_reservation_student_expires_at"),
+		},
+	}
+
+	if _, err := db.Collection("attachment_quota_reservations").Indexes().CreateMany(ctx, indexModels); err != nil {
+		return fmt.Errorf("create attachment quota reservation indexes: %w", err)
+	}
+
+	log.Println("Created indexes for attachment_quota_reservations collection")
+	return nil
+}
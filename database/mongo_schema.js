@@ -14,13 +14,19 @@ use('sppm_2025');
 // {
 //   _id: ObjectId,
 //   studentId: String (UUID dari PostgreSQL students.id),
-//   achievementType: String, // 'academic', 'competition', 'organization', 'publication', 'certification', 'other'
+//   achievementType: String, // 'academic', 'competition', 'organization', 'publication', 'certification', 'other', atau kode tipe custom dari achievement_types
 //   title: String,
 //   description: String,
-//   details: Object (field dinamis berdasarkan achievementType),
-//   attachments: Array (optional),
+//   details: Object (field dinamis berdasarkan achievementType, divalidasi per tipe; publication bisa berisi issn dan doi),
+//   attachments: Array (optional, tiap attachment punya id tetap untuk hapus dan ganti, hasPreview jika ada preview JPEG <key>.preview.jpg di samping file, menyimpan contentHash SHA-256 isi file yang juga menjadi key storage <contentHash><ext> sehingga file dengan isi sama disimpan sekali, size dalam byte untuk kuota per mahasiswa, dan scanStatus 'pending', 'clean', atau 'infected', index idx_attachment_scan_status dan idx_attachment_file_url),
 //   tags: Array (optional),
-//   points: Number,
+//   points: Number (dihitung dari rubrik poin PostgreSQL point_rubrics),
+//   rubricVersion: Number (versi rubrik yang dipakai untuk menghitung points),
+//   pointsOverride: Object (optional, override poin oleh dosen wali beserta justifikasi),
+//   duplicateKeys: Array of String (kunci normalisasi untuk deteksi duplikat, index idx_duplicate_keys),
+//   teamMembers: Array (optional, prestasi tim: { studentId, role, pointShare (persen), confirmed, confirmedAt }),
+//   designatedAdvisorId: String (optional, dosen wali yang ditunjuk untuk memverifikasi prestasi tim),
+//   teamVerifications: Array (optional, { advisorId, verifiedBy, verifiedAt } per dosen wali yang sudah memverifikasi),
 //   createdAt: Date,
 //   updatedAt: Date
 // }
@@ -33,8 +39,8 @@ use('sppm_2025');
 // 2. Find achievements by type
 // db.achievements.find({ "achievement_type": "competition" })
 
-// 3. Find achievements with text search
-// db.achievements.find({ $text: { $search: "programming" } })
+// 3. Find achievements with text search (index idx_achievement_text_search: title, tags, details.competitionName, details.organizationName, details.authors, description)
+// db.achievements.find({ $text: { $search: "programming" } }, { score: { $meta: "textScore" } }).sort({ score: { $meta: "textScore" } })
 
 // 4. Find achievements by date range
 // db.achievements.find({
@@ -85,3 +91,53 @@ use('sppm_2025');
 // 15. Count achievements by type
 // db.achievements.countDocuments({ "achievementType": "competition" })
 
+// 15b. Find likely duplicates by certification number or attachment hash
+// db.achievements.find({ "duplicateKeys": { $in: ["certificationNumber:aws123", "attachment:<sha256>"] } })
+
+// 15c. Find achievements of a student including team achievements
+// db.achievements.find({ $or: [{ "studentId": "uuid-student" }, { "teamMembers.studentId": "uuid-student" }] })
+
+
+// Struktur Collection: achievement_types
+// (di-seed oleh migrasi dengan 6 tipe bawaan, index unik pada code)
+// {
+//   _id: ObjectId,
+//   code: String (unik, huruf kecil/angka/underscore, dipakai di achievements.achievementType),
+//   name: String,
+//   description: String (optional),
+//   customFields: Array of {
+//     key: String, label: String,
+//     type: String, // 'text', 'number', 'date', 'boolean', 'select', 'multiselect', 'url'
+//     required: Boolean, options: Array (select/multiselect),
+//     min: Number, max: Number, minLength: Number, maxLength: Number, pattern: String, helpText: String
+//   },
+//   isBuiltIn: Boolean,
+//   isActive: Boolean,
+//   createdBy: String (optional, UUID user admin),
+//   createdAt: Date,
+//   updatedAt: Date
+// }
+
+// 16. Find active achievement types
+// db.achievement_types.find({ "isActive": true })
+
+
+// Struktur Collection: attachment_uploads
+// (sesi upload bertahap untuk file besar, potongan file disimpan di storage attachment sebagai upload-<_id>-<nomor>.part, index idx_upload_expires_at dan idx_upload_student_expires_at)
+// {
+//   _id: ObjectId,
+//   achievementId: String (ObjectID achievement tujuan),
+//   studentId: String (UUID mahasiswa pemilik),
+//   attachmentId: String (optional, diisi jika upload untuk mengganti attachment),
+//   fileName: String (nama file hasil sanitasi),
+//   fileType: String (MIME type dari ekstensi),
+//   size: Number (ukuran file dalam byte),
+//   chunkSize: Number (ukuran potongan selain potongan terakhir),
+//   offset: Number (jumlah byte yang sudah diterima),
+//   expiresAt: Date (diperpanjang setiap potongan diterima),
+//   createdAt: Date,
+//   updatedAt: Date
+// }
+
+// 17. Find expired upload sessions
+// db.attachment_uploads.find({ "expiresAt": { $lte: new Date() } })
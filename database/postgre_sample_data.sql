@@ -2,6 +2,7 @@
 -- Jalankan file ini setelah menjalankan postgre_schema.sql
 
 -- Hapus data yang sudah ada (jika ada)
+DELETE FROM point_rubrics;
 DELETE FROM students;
 DELETE FROM lecturers;
 DELETE FROM role_permissions;
@@ -22,7 +23,10 @@ INSERT INTO permissions (name, resource, action, description) VALUES
 ('achievement:update', 'achievement', 'update', 'Mengupdate data prestasi'),
 ('achievement:delete', 'achievement', 'delete', 'Menghapus data prestasi'),
 ('achievement:verify', 'achievement', 'verify', 'Memverifikasi prestasi'),
-('user:manage', 'user', 'manage', 'Mengelola pengguna');
+('user:manage', 'user', 'manage', 'Mengelola pengguna'),
+('rubric:manage', 'rubric', 'manage', 'Mengelola rubrik poin prestasi'),
+('achievement_type:manage', 'achievement_type', 'manage', 'Mengelola tipe prestasi dan skema custom field'),
+('report:reconcile', 'report', 'reconcile', 'Menjalankan dan memperbaiki rekonsiliasi data prestasi');
 
 -- Insert Role Permissions
 INSERT INTO role_permissions (role_id, permission_id)
@@ -31,7 +35,8 @@ FROM roles r
 CROSS JOIN permissions p
 WHERE (r.name = 'Admin' AND p.name IN (
     'achievement:create', 'achievement:read', 'achievement:update', 
-    'achievement:delete', 'achievement:verify', 'user:manage'
+    'achievement:delete', 'achievement:verify', 'user:manage', 'rubric:manage', 'achievement_type:manage',
+    'report:reconcile'
 ))
 OR (r.name = 'Mahasiswa' AND p.name IN (
     'achievement:create', 'achievement:read', 'achievement:update', 'achievement:delete'
@@ -40,6 +45,39 @@ OR (r.name = 'Dosen Wali' AND p.name IN (
     'achievement:read', 'achievement:verify'
 ));
 
+-- Insert Rubrik Poin Default (versi 1, aktif)
+INSERT INTO point_rubrics (version, name, description, rules, is_active, activated_at)
+VALUES (
+    1,
+    'Rubrik Poin Default',
+    'Rubrik poin awal untuk semua tipe prestasi',
+    '[
+        {"achievement_type": "academic", "points": 20},
+        {"achievement_type": "competition", "points": 10},
+        {"achievement_type": "competition", "field": "competitionLevel", "value": "international", "points": 90},
+        {"achievement_type": "competition", "field": "competitionLevel", "value": "national", "points": 50},
+        {"achievement_type": "competition", "field": "competitionLevel", "value": "regional", "points": 25},
+        {"achievement_type": "competition", "field": "competitionLevel", "value": "local", "points": 10},
+        {"achievement_type": "competition", "field": "rank", "value": "1", "points": 30},
+        {"achievement_type": "competition", "field": "rank", "value": "2", "points": 20},
+        {"achievement_type": "competition", "field": "rank", "value": "3", "points": 10},
+        {"achievement_type": "competition", "field": "medalType", "value": "gold", "points": 15},
+        {"achievement_type": "competition", "field": "medalType", "value": "silver", "points": 10},
+        {"achievement_type": "competition", "field": "medalType", "value": "bronze", "points": 5},
+        {"achievement_type": "publication", "points": 20},
+        {"achievement_type": "publication", "field": "publicationType", "value": "journal", "points": 60},
+        {"achievement_type": "publication", "field": "publicationType", "value": "conference", "points": 40},
+        {"achievement_type": "publication", "field": "publicationType", "value": "book", "points": 50},
+        {"achievement_type": "organization", "points": 15},
+        {"achievement_type": "organization", "field": "position", "value": "ketua", "points": 25},
+        {"achievement_type": "organization", "field": "position", "value": "wakil ketua", "points": 15},
+        {"achievement_type": "certification", "points": 30},
+        {"achievement_type": "other", "points": 10}
+    ]',
+    true,
+    NOW()
+);
+
 -- Insert Users (1 Admin)
 -- Password: 123123123
 
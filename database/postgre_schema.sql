@@ -1,5 +1,9 @@
 DROP EXTENSION IF EXISTS "uuid-ossp" CASCADE;
 
+DROP TABLE IF EXISTS sla_notification_claims CASCADE;
+DROP TABLE IF EXISTS certification_expiry_alerts CASCADE;
+DROP TABLE IF EXISTS point_rubrics CASCADE;
+DROP TABLE IF EXISTS achievement_history CASCADE;
 DROP TABLE IF EXISTS notifications CASCADE;
 DROP TABLE IF EXISTS achievement_references CASCADE;
 DROP TABLE IF EXISTS students CASCADE;
@@ -18,7 +22,7 @@ CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
 CREATE TYPE achievement_status AS ENUM ('draft', 'submitted', 'verified', 'rejected', 'deleted');
 
-CREATE TYPE notification_type AS ENUM ('achievement_rejected', 'achievement_submitted');
+CREATE TYPE notification_type AS ENUM ('achievement_rejected', 'achievement_submitted', 'team_invitation', 'achievement_sla_reminder', 'achievement_sla_escalated', 'certification_expiring', 'attachment_infected');
 
 CREATE TABLE roles (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -80,6 +84,9 @@ CREATE TABLE achievement_references (
     verified_at TIMESTAMP,
     verified_by UUID REFERENCES users(id) ON DELETE SET NULL,
     rejection_note TEXT,
+    deleted_at TIMESTAMP,
+    status_before_delete achievement_status,
+    version INTEGER NOT NULL DEFAULT 1,
     created_at TIMESTAMP DEFAULT NOW(),
     updated_at TIMESTAMP DEFAULT NOW()
 );
@@ -109,12 +116,59 @@ CREATE INDEX idx_lecturers_user_id ON lecturers(user_id);
 CREATE INDEX idx_achievement_references_student_id ON achievement_references(student_id);
 CREATE INDEX idx_achievement_references_status ON achievement_references(status);
 CREATE INDEX idx_achievement_references_verified_by ON achievement_references(verified_by);
+CREATE INDEX idx_achievement_references_deleted_at ON achievement_references(deleted_at) WHERE status = 'deleted';
+CREATE INDEX idx_achievement_references_created_mongo ON achievement_references(created_at DESC, mongo_achievement_id DESC);
 CREATE INDEX idx_notifications_user_id ON notifications(user_id);
 CREATE INDEX idx_notifications_is_read ON notifications(is_read);
 CREATE INDEX idx_notifications_created_at ON notifications(created_at);
+CREATE INDEX idx_notifications_user_created_id ON notifications(user_id, created_at DESC, id DESC);
 CREATE INDEX idx_notifications_achievement_id ON notifications(achievement_id);
 CREATE INDEX idx_notifications_mongo_achievement_id ON notifications(mongo_achievement_id);
 
+CREATE TABLE achievement_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_ref_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    action VARCHAR(30) NOT NULL,
+    status achievement_status NOT NULL,
+    changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    note TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_achievement_history_ref_id ON achievement_history(achievement_ref_id, created_at);
+
+CREATE TABLE certification_expiry_alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_ref_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    valid_until DATE NOT NULL,
+    days_before INTEGER NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (achievement_ref_id, valid_until, days_before)
+);
+
+CREATE TABLE sla_notification_claims (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    achievement_ref_id UUID NOT NULL REFERENCES achievement_references(id) ON DELETE CASCADE,
+    submitted_at TIMESTAMP NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (achievement_ref_id, submitted_at, action)
+);
+
+CREATE TABLE point_rubrics (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    version INTEGER UNIQUE NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    rules JSONB NOT NULL DEFAULT '[]',
+    is_active BOOLEAN DEFAULT false,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    activated_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_point_rubrics_active ON point_rubrics(is_active) WHERE is_active = true;
+
 CREATE OR REPLACE FUNCTION update_updated_at_column()
 RETURNS TRIGGER AS $$
 BEGIN
@@ -0,0 +1,83 @@
+package database
+
+// #1 proses: import library yang diperlukan untuk fmt, log, os, strconv, time, dan repository scanner serta storage
+import (
+	"fmt"
+	"log"
+	"os"
+	repositoryscanner "sistem-pelaporan-prestasi-mahasiswa/app/repository/scanner"
+	repositorystorage "sistem-pelaporan-prestasi-mahasiswa/app/repository/storage"
+	"strconv"
+	"time"
+)
+
+// #2 proses: buat client clamd dari CLAMD_ADDRESS, nil berarti pemindaian malware tidak aktif
+func ConnectMalwareScanner() repositoryscanner.IMalwareScanner {
+	address := os.Getenv("CLAMD_ADDRESS")
+	if address == "" {
+		fmt.Println("CLAMD_ADDRESS is not set, attachment malware scanning is disabled")
+		return nil
+	}
+
+	// #2a proses: timeout per file dari CLAMD_TIMEOUT_SECONDS, default 60 detik
+	timeout := 60 * time.Second
+	if value, err := strconv.Atoi(os.Getenv("CLAMD_TIMEOUT_SECONDS")); err == nil && value > 0 {
+		timeout = time.Duration(value) * time.Second
+	}
+
+	scanner, err := repositoryscanner.NewClamdScanner(address, timeout)
+	if err != nil {
+		log.Fatal("Failed to configure clamd scanner:", err)
+	}
+
+	fmt.Println("Using clamd malware scanner at", address)
+	return scanner
+}
+
+// #3 proses: buat storage karantina untuk file attachment terinfeksi dengan driver yang sama seperti storage attachment
+func ConnectQuarantineStorage() repositorystorage.IFileStorage {
+	driver := os.Getenv("STORAGE_DRIVER")
+	if driver == "" {
+		driver = repositorystorage.StorageDriverLocal
+	}
+
+	switch driver {
+	case repositorystorage.StorageDriverLocal:
+		// #3a proses: folder karantina lokal terpisah dari folder uploads
+		quarantineDir := os.Getenv("STORAGE_QUARANTINE_DIR")
+		if quarantineDir == "" {
+			quarantineDir = "./quarantine"
+		}
+		if err := os.MkdirAll(quarantineDir, 0700); err != nil {
+			log.Fatalf("Failed to create quarantine directory: %v", err)
+		}
+
+		fmt.Println("Using local quarantine storage at", quarantineDir)
+		return repositorystorage.NewLocalFileStorage(quarantineDir, "/quarantine/")
+
+	case repositorystorage.StorageDriverS3:
+		// #3b proses: karantina S3 memakai bucket terpisah dari S3_QUARANTINE_BUCKET dengan kredensial yang sama
+		bucket := os.Getenv("S3_QUARANTINE_BUCKET")
+		if bucket == "" || bucket == os.Getenv("S3_BUCKET") {
+			log.Fatal("S3_QUARANTINE_BUCKET must be set to a bucket other than S3_BUCKET when CLAMD_ADDRESS is set")
+		}
+
+		storage, err := repositorystorage.NewS3FileStorage(repositorystorage.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    bucket,
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+		}, nil)
+		if err != nil {
+			log.Fatal("Failed to configure S3 quarantine storage:", err)
+		}
+
+		fmt.Println("Using S3 quarantine bucket", bucket)
+		return storage
+
+	default:
+		log.Fatalf("Unknown STORAGE_DRIVER %q, use local or s3", driver)
+		return nil
+	}
+}
@@ -0,0 +1,59 @@
+package database
+
+// #1 proses: import library yang diperlukan untuk fmt, log, os, dan repository storage
+import (
+	"fmt"
+	"log"
+	"os"
+	repositorystorage "sistem-pelaporan-prestasi-mahasiswa/app/repository/storage"
+)
+
+// #2 proses: buat storage file attachment sesuai STORAGE_DRIVER, default local di folder uploads
+func ConnectFileStorage() repositorystorage.IFileStorage {
+	// #2a proses: ambil driver dari environment variable atau gunakan default local
+	driver := os.Getenv("STORAGE_DRIVER")
+	if driver == "" {
+		driver = repositorystorage.StorageDriverLocal
+	}
+
+	switch driver {
+	case repositorystorage.StorageDriverLocal:
+		// #2b proses: siapkan folder lokal untuk menyimpan file yang diupload
+		localDir := LocalStorageDir()
+		if err := os.MkdirAll(localDir, 0755); err != nil {
+			log.Fatalf("Failed to create uploads directory: %v", err)
+		}
+
+		fmt.Println("Using local file storage at", localDir)
+		return repositorystorage.NewLocalFileStorage(localDir, "/uploads/")
+
+	case repositorystorage.StorageDriverS3:
+		// #2c proses: ambil konfigurasi S3-compatible dari environment variable
+		storage, err := repositorystorage.NewS3FileStorage(repositorystorage.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Region:    os.Getenv("S3_REGION"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+		}, nil)
+		if err != nil {
+			log.Fatal("Failed to configure S3 file storage:", err)
+		}
+
+		fmt.Println("Using S3 file storage at", os.Getenv("S3_ENDPOINT"))
+		return storage
+
+	default:
+		log.Fatalf("Unknown STORAGE_DRIVER %q, use local or s3", driver)
+		return nil
+	}
+}
+
+// #3 proses: folder storage lokal dari STORAGE_LOCAL_DIR atau default ./uploads
+func LocalStorageDir() string {
+	if dir := os.Getenv("STORAGE_LOCAL_DIR"); dir != "" {
+		return dir
+	}
+	return "./uploads"
+}
@@ -0,0 +1,36 @@
+package helper
+
+// #1 proses: import library yang diperlukan untuk base64, json, errors, dan time
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// #2 proses: isi cursor keyset, posisi terakhir yang sudah dikirim ke client berdasarkan created_at dan id
+type cursorPayload struct {
+	CreatedAt time.Time `json:"t"`
+	ID        string    `json:"id"`
+}
+
+// #3 proses: encode posisi terakhir menjadi cursor opaque yang aman dipakai di query string
+func EncodeCursor(createdAt time.Time, id string) string {
+	payload, _ := json.Marshal(cursorPayload{CreatedAt: createdAt.UTC(), ID: id})
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+// #4 proses: decode cursor dari client, cursor rusak atau dimodifikasi dikembalikan sebagai error
+func DecodeCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", errors.New("cursor tidak valid")
+	}
+
+	var payload cursorPayload
+	if err := json.Unmarshal(raw, &payload); err != nil || payload.ID == "" || payload.CreatedAt.IsZero() {
+		return time.Time{}, "", errors.New("cursor tidak valid")
+	}
+
+	return payload.CreatedAt, payload.ID, nil
+}
@@ -0,0 +1,39 @@
+package helper
+
+// #1 proses: import library yang diperlukan untuk errors, fmt, strconv, dan strings
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// #2 proses: buat ETag prestasi dari versi dokumen MongoDB dan versi reference PostgreSQL
+func FormatETag(achievementVersion int, referenceVersion int) string {
+	return fmt.Sprintf(`"%d-%d"`, achievementVersion, referenceVersion)
+}
+
+// #3 proses: baca ETag dari header If-Match, hanya ETag kuat dengan format yang dibuat FormatETag yang diterima
+func ParseETag(value string) (int, int, error) {
+	value = strings.TrimSpace(value)
+	if len(value) < 2 || !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) {
+		return 0, 0, errors.New("ETag tidak valid")
+	}
+
+	parts := strings.Split(value[1:len(value)-1], "-")
+	if len(parts) != 2 {
+		return 0, 0, errors.New("ETag tidak valid")
+	}
+
+	achievementVersion, err := strconv.Atoi(parts[0])
+	if err != nil || achievementVersion < 0 {
+		return 0, 0, errors.New("ETag tidak valid")
+	}
+
+	referenceVersion, err := strconv.Atoi(parts[1])
+	if err != nil || referenceVersion < 0 {
+		return 0, 0, errors.New("ETag tidak valid")
+	}
+
+	return achievementVersion, referenceVersion, nil
+}
@@ -161,3 +161,15 @@ func SanitizeSearch(search string) string {
 func IsEmptyString(s string) bool {
 	return strings.TrimSpace(s) == ""
 }
+
+// #23 proses: ambil query parameter multi-select yang dipisah koma, nilai kosong dibuang
+func GetQueryList(c *fiber.Ctx, key string) []string {
+	var values []string
+	for _, value := range strings.Split(c.Query(key), ",") {
+		value = strings.TrimSpace(value)
+		if value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
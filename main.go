@@ -12,8 +12,9 @@ package main
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token. Example: "Bearer {token}"
 
-// #2 proses: import library yang diperlukan untuk log, os, repository, service, config, database, middleware, route, dan uuid
+// #2 proses: import library yang diperlukan untuk context, log, os, repository, service, config, database, middleware, route, dan uuid
 import (
+	"context"
 	"log"
 	"os"
 	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
@@ -41,10 +42,11 @@ func main() {
 	serverInstanceID = uuid.New().String()
 	log.Printf("Server instance ID: %s", serverInstanceID)
 
-	// #4c proses: buat directory uploads jika belum ada untuk menyimpan file yang diupload
-	if err := os.MkdirAll("./uploads", 0755); err != nil {
-		log.Fatalf("Failed to create uploads directory: %v", err)
-	}
+	// #4c proses: siapkan storage file attachment sesuai STORAGE_DRIVER, local atau S3-compatible
+	fileStorage := database.ConnectFileStorage()
+
+	// #4c1 proses: siapkan scanner malware clamd jika CLAMD_ADDRESS diisi, beserta storage karantina untuk file terinfeksi
+	malwareScanner := database.ConnectMalwareScanner()
 
 	// #4d proses: connect ke PostgreSQL database dan defer close connection
 	postgresDB := database.ConnectDB()
@@ -53,6 +55,16 @@ func main() {
 	// #4e proses: connect ke MongoDB database
 	mongoDB := database.ConnectMongoDB()
 
+	// #4e1 proses: pastikan text index untuk pencarian prestasi tersedia, pencarian tidak bisa dipakai jika gagal
+	if err := database.EnsureAchievementTextIndex(mongoDB); err != nil {
+		log.Printf("Failed to ensure achievement text index: %v", err)
+	}
+
+	// #4e2 proses: beri ID ke attachment lama, attachment tanpa ID tidak bisa dihapus atau diganti jika gagal
+	if err := database.EnsureAttachmentIDs(mongoDB); err != nil {
+		log.Printf("Failed to ensure attachment ids: %v", err)
+	}
+
 	// #4f proses: inisialisasi Fiber app dengan config MongoDB
 	app := configmongo.NewApp()
 	app.Use(middleware.LoggerMiddleware)
@@ -66,7 +78,9 @@ func main() {
 	lecturerRepo := repositorypostgre.NewLecturerRepository(postgresDB)
 	achievementRefRepo := repositorypostgre.NewAchievementReferenceRepository(postgresDB)
 	achievementRepo := repositorymongo.NewAchievementRepository(mongoDB)
+	achievementTypeRepo := repositorymongo.NewAchievementTypeRepository(mongoDB)
 	notificationRepo := repositorypostgre.NewNotificationRepository(postgresDB)
+	pointRubricRepo := repositorypostgre.NewPointRubricRepository(postgresDB)
 
 	// #4h proses: inisialisasi semua service dengan dependency injection dari repository
 	authService := servicepostgre.NewAuthService(userRepo)
@@ -74,17 +88,65 @@ func main() {
 	studentService := servicepostgre.NewStudentService(studentRepo, userRepo, lecturerRepo)
 	lecturerService := servicepostgre.NewLecturerService(userRepo, lecturerRepo)
 	notificationService := servicepostgre.NewNotificationService(notificationRepo, studentRepo, userRepo, achievementRepo)
-	achievementService := servicepostgre.NewAchievementService(achievementRepo, achievementRefRepo, userRepo, studentRepo, notificationService)
-	reportService := servicepostgre.NewReportService(achievementRepo, achievementRefRepo, studentRepo, userRepo, lecturerRepo)
+	achievementTypeService := servicepostgre.NewAchievementTypeService(achievementTypeRepo)
+	pointRubricService := servicepostgre.NewPointRubricService(pointRubricRepo, achievementTypeService)
+	attachmentConfig, err := servicepostgre.LoadAttachmentConfig()
+	if err != nil {
+		log.Fatal("Failed to load attachment config:", err)
+	}
+	attachmentConfig.ScanEnabled = malwareScanner != nil
+	slaConfig := servicepostgre.LoadSLAConfig()
+	trashConfig := servicepostgre.LoadTrashRetentionConfig()
+	certificationConfig, err := servicepostgre.LoadCertificationExpiryConfig()
+	if err != nil {
+		log.Fatal("Failed to load certification expiry config:", err)
+	}
+	achievementService := servicepostgre.NewAchievementService(achievementRepo, achievementRefRepo, userRepo, studentRepo, notificationService, pointRubricService, achievementTypeService, fileStorage, attachmentConfig, slaConfig, trashConfig, certificationConfig)
+	reportService := servicepostgre.NewReportService(achievementRepo, achievementRefRepo, studentRepo, userRepo, lecturerRepo, certificationConfig)
+	slaService := servicepostgre.NewSLAService(achievementRefRepo, achievementRepo, studentRepo, userRepo, notificationService, slaConfig)
+
+	// #4h1 proses: jalankan scheduler SLA di background untuk pengingat dan eskalasi prestasi yang lama menunggu verifikasi
+	servicepostgre.StartSLAScheduler(context.Background(), slaService, slaConfig.CheckInterval)
+
+	// #4h2 proses: jalankan purge tempat sampah di background untuk menghapus permanen prestasi yang melewati masa retensi
+	servicepostgre.StartTrashPurgeScheduler(context.Background(), achievementService, trashConfig)
+
+	// #4h3 proses: jalankan pengecekan masa berlaku sertifikasi di background untuk peringatan ke mahasiswa
+	certificationService := servicepostgre.NewCertificationService(achievementRefRepo, achievementRepo, notificationService, certificationConfig)
+	servicepostgre.StartCertificationExpiryScheduler(context.Background(), certificationService, certificationConfig.CheckInterval)
+
+	// #4h4 proses: inisialisasi service rekonsiliasi MongoDB dan PostgreSQL untuk laporan dan perbaikan oleh admin
+	reconciliationService := servicepostgre.NewReconciliationService(achievementRefRepo, achievementRepo, userRepo, fileStorage, servicepostgre.LoadReconcileConfig())
+
+	// #4h5 proses: jalankan scan malware attachment pending di background, upload baru tidak bisa didownload dosen wali dan admin sampai dinyatakan bersih
+	if malwareScanner != nil {
+		attachmentScanService := servicepostgre.NewAttachmentScanService(achievementRepo, achievementRefRepo, notificationService, fileStorage, database.ConnectQuarantineStorage(), malwareScanner)
+		servicepostgre.StartAttachmentScanScheduler(context.Background(), attachmentScanService, servicepostgre.LoadAttachmentScanInterval())
+	}
+
+	// #4h6 proses: inisialisasi upload bertahap untuk file besar dan jalankan pembersihan sesi upload kedaluwarsa di background
+	attachmentUploadConfig := servicepostgre.LoadAttachmentUploadConfig()
+	attachmentUploadRepo := repositorymongo.NewAttachmentUploadRepository(mongoDB)
+	attachmentUploadService := servicepostgre.NewAttachmentUploadService(attachmentUploadRepo, achievementRepo, achievementRefRepo, studentRepo, achievementService, fileStorage, attachmentConfig, attachmentUploadConfig)
+	servicepostgre.StartAttachmentUploadCleanupScheduler(context.Background(), attachmentUploadService, attachmentUploadConfig.CleanupInterval)
+
+	// #4h7 proses: inisialisasi export ZIP attachment untuk laporan dosen wali dan admin
+	attachmentExportService := servicepostgre.NewAttachmentExportService(achievementRepo, achievementRefRepo, userRepo, studentRepo, fileStorage)
 
 	// #4i proses: register semua route dengan dependency injection dari service
 	routepostgre.AuthRoutes(app, authService, serverInstanceID)
 	routepostgre.UserRoutes(app, userService, studentService, lecturerService, postgresDB)
 	routepostgre.AchievementRoutes(app, achievementService, postgresDB)
+	routepostgre.AttachmentUploadRoutes(app, attachmentUploadService, postgresDB)
 	routepostgre.StudentRoutes(app, studentService, achievementService, postgresDB)
 	routepostgre.LecturerRoutes(app, lecturerService, studentService, postgresDB)
 	routepostgre.ReportRoutes(app, reportService, postgresDB)
+	routepostgre.AttachmentExportRoutes(app, attachmentExportService, postgresDB)
+	routepostgre.SLARoutes(app, slaService, postgresDB)
+	routepostgre.ReconciliationRoutes(app, reconciliationService, postgresDB)
 	routepostgre.NotificationRoutes(app, notificationService)
+	routepostgre.PointRubricRoutes(app, pointRubricService, postgresDB)
+	routepostgre.AchievementTypeRoutes(app, achievementTypeService, postgresDB)
 
 	// #4j proses: ambil port dari environment variable atau gunakan default 3001
 	port := os.Getenv("APP_PORT")
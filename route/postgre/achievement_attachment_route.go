@@ -0,0 +1,364 @@
+package route
+
+// #1 proses: import library yang diperlukan untuk context, errors, io, mime, http, url, model, service, strconv, strings, time, dan fiber
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DownloadAttachment godoc
+// @Summary Download attachment
+// @Description Mendownload file attachment prestasi. Aturan akses sama dengan detail prestasi: Mahasiswa pemilik atau anggota tim, Dosen Wali mahasiswa bimbingan, dan Admin. Key adalah nama file di storage, tersedia di field downloadUrl attachment. Preview JPEG memakai endpoint yang sama dengan key dari field previewUrl
+// @Tags Achievements
+// @Produce octet-stream
+// @Security Bearer
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param key path string true "Key file attachment"
+// @Success 200 {file} file
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /achievements/{id}/attachments/{key} [get]
+func DownloadAttachment(achievementService servicepostgre.IAchievementService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		roleID, ok := c.Locals("role_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		key, err := url.PathUnescape(c.Params("key"))
+		if err != nil || key == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": "Key attachment tidak valid.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+
+		download, err := achievementService.GetAttachmentFile(ctx, userID, roleID, c.Params("id"), key)
+		if err != nil {
+			cancel()
+			return attachmentErrorResponse(c, err)
+		}
+
+		return sendAttachment(c, download, cancel)
+	}
+}
+
+// CreateAttachmentLink godoc
+// @Summary Create signed attachment download link
+// @Description Membuat link download attachment bertanda tangan HMAC yang berlaku singkat (ATTACHMENT_LINK_TTL_MINUTES, default 15 menit) untuk ditanam di frontend, misalnya di tag img atau iframe. Aturan akses sama dengan detail prestasi
+// @Tags Achievements
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param key path string true "Key file attachment"
+// @Success 200 {object} modelmongo.AttachmentLinkResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /achievements/{id}/attachments/{key}/link [post]
+func CreateAttachmentLink(achievementService servicepostgre.IAchievementService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		roleID, ok := c.Locals("role_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		key, err := url.PathUnescape(c.Params("key"))
+		if err != nil || key == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": "Key attachment tidak valid.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		response, err := achievementService.CreateAttachmentLink(ctx, userID, roleID, c.Params("id"), key)
+		if err != nil {
+			return attachmentErrorResponse(c, err)
+		}
+
+		return c.JSON(response)
+	}
+}
+
+// DownloadSignedAttachment godoc
+// @Summary Download attachment with signed link
+// @Description Mendownload file attachment lewat link dari endpoint link attachment tanpa header Authorization. Link ditolak jika signature salah, sudah kedaluwarsa, atau attachment dan prestasi sudah dihapus
+// @Tags Achievements
+// @Produce octet-stream
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param key path string true "Key file attachment"
+// @Param expires query int true "Waktu kedaluwarsa link (Unix timestamp)"
+// @Param signature query string true "Signature HMAC-SHA256"
+// @Success 200 {file} file
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /attachments/{id}/{key} [get]
+func DownloadSignedAttachment(achievementService servicepostgre.IAchievementService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key, err := url.PathUnescape(c.Params("key"))
+		if err != nil || key == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": "Key attachment tidak valid.",
+			})
+		}
+
+		expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+		if err != nil {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"error":   "Akses ditolak",
+				"message": "Link download tidak valid atau sudah kedaluwarsa.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+
+		download, err := achievementService.GetSignedAttachmentFile(ctx, c.Params("id"), key, expires, c.Query("signature"))
+		if err != nil {
+			cancel()
+			return attachmentErrorResponse(c, err)
+		}
+
+		return sendAttachment(c, download, cancel)
+	}
+}
+
+// DeleteAttachment godoc
+// @Summary Delete attachment
+// @Description Menghapus attachment prestasi berdasarkan ID attachment. Hanya dapat diakses oleh Mahasiswa pemilik dengan permission achievement:update. Hanya dapat dihapus jika status prestasi adalah draft atau rejected (revisi). File di storage ikut dihapus jika tidak dipakai prestasi lain
+// @Tags Achievements
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param attachmentId path string true "Attachment ID"
+// @Success 200 {object} modelmongo.AttachmentListResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /achievements/{id}/attachments/{attachmentId} [delete]
+func DeleteAttachment(achievementService servicepostgre.IAchievementService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		roleID, ok := c.Locals("role_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		attachmentID := c.Params("attachmentId")
+		if attachmentID == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": "ID attachment wajib diisi.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		response, err := achievementService.DeleteAttachment(ctx, userID, roleID, c.Params("id"), attachmentID)
+		if err != nil {
+			return attachmentChangeErrorResponse(c, err)
+		}
+
+		return c.JSON(response)
+	}
+}
+
+// ReplaceAttachment godoc
+// @Summary Replace attachment
+// @Description Mengganti file attachment prestasi dengan ID attachment yang tetap sama. Hanya dapat diakses oleh Mahasiswa pemilik dengan permission achievement:update. Hanya dapat diganti jika status prestasi adalah draft atau rejected (revisi). Aturan file sama dengan upload attachment. File lama di storage dihapus jika tidak dipakai prestasi lain. Ukuran file lama tidak dihitung ke kuota storage mahasiswa
+// @Tags Achievements
+// @Accept multipart/form-data
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param attachmentId path string true "Attachment ID"
+// @Param file formData file true "Attachment file"
+// @Success 200 {object} modelmongo.Attachment
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 413 {object} map[string]string "Request Entity Too Large"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /achievements/{id}/attachments/{attachmentId} [put]
+func ReplaceAttachment(achievementService servicepostgre.IAchievementService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		roleID, ok := c.Locals("role_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		attachmentID := c.Params("attachmentId")
+		if attachmentID == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": "ID attachment wajib diisi.",
+			})
+		}
+
+		file, err := c.FormFile("file")
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": "File wajib diisi.",
+			})
+		}
+
+		content, err := file.Open()
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal mengambil data",
+				"message": "Error membaca file: " + err.Error(),
+			})
+		}
+		defer content.Close()
+
+		check, err := servicepostgre.ValidateAttachmentFile(file.Filename, file.Size, content)
+		if err != nil {
+			return attachmentChangeErrorResponse(c, err)
+		}
+
+		if _, err := content.Seek(0, io.SeekStart); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal mengambil data",
+				"message": "Error membaca file: " + err.Error(),
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		attachment, err := achievementService.ReplaceAttachment(ctx, userID, roleID, c.Params("id"), attachmentID, check.FileName, check.FileType, file.Size, content)
+		if err != nil {
+			return attachmentChangeErrorResponse(c, err)
+		}
+
+		return c.JSON(attachment)
+	}
+}
+
+// #2 proses: kirim file attachment sebagai stream, cancel dipanggil setelah body ditutup supaya request ke storage tidak terputus di tengah
+func sendAttachment(c *fiber.Ctx, download *modelmongo.AttachmentDownload, cancel context.CancelFunc) error {
+	c.Set(fiber.HeaderContentType, download.ContentType)
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{"filename": download.FileName}))
+	c.Set("X-Content-Type-Options", "nosniff")
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	if !download.ModTime.IsZero() {
+		c.Set(fiber.HeaderLastModified, download.ModTime.UTC().Format(http.TimeFormat))
+	}
+
+	return c.SendStream(&cancelOnClose{ReadCloser: download.Body, cancel: cancel}, int(download.Size))
+}
+
+// #3 proses: body stream yang membatalkan context request storage saat ditutup
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (r *cancelOnClose) Close() error {
+	defer r.cancel()
+	return r.ReadCloser.Close()
+}
+
+// #4 proses: mapping error service attachment ke status HTTP
+func attachmentErrorResponse(c *fiber.Ctx, err error) error {
+	if strings.Contains(err.Error(), "akses ditolak") {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error":   "Akses ditolak",
+			"message": err.Error(),
+		})
+	}
+	if strings.Contains(err.Error(), "tidak ditemukan") {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error":   "Attachment tidak ditemukan",
+			"message": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error":   "Gagal mengambil attachment",
+		"message": err.Error(),
+	})
+}
+
+// #5 proses: mapping error hapus dan ganti attachment, file tidak valid dan kuota terlampaui memakai mapping upload dan status prestasi yang tidak mengizinkan perubahan dikirim sebagai 400
+func attachmentChangeErrorResponse(c *fiber.Ctx, err error) error {
+	var validationErr *modelmongo.AttachmentValidationError
+	var quotaErr *modelmongo.AttachmentQuotaError
+	if errors.As(err, &validationErr) || errors.As(err, &quotaErr) {
+		return attachmentUploadErrorResponse(c, err)
+	}
+	if strings.Contains(err.Error(), "hanya dapat") && !strings.Contains(err.Error(), "akses ditolak") {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Attachment tidak dapat diubah",
+			"message": err.Error(),
+		})
+	}
+	return attachmentErrorResponse(c, err)
+}
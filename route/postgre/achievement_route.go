@@ -1,22 +1,23 @@
 package route
 
-// #1 proses: import library yang diperlukan untuk context, database, fmt, mime, os, path, model, service, helper, middleware, strings, time, dan fiber
+// #1 proses: import library yang diperlukan untuk context, database, errors, fmt, io, model, service, helper, middleware, strconv, strings, time, dan fiber
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"mime"
-	"os"
-	"path/filepath"
+	"io"
 	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
 	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
 	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
 	"sistem-pelaporan-prestasi-mahasiswa/helper"
 	middlewarepostgre "sistem-pelaporan-prestasi-mahasiswa/middleware/postgre"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // GetAchievementStats godoc
@@ -54,12 +55,27 @@ func GetAchievementStats(achievementService servicepostgre.IAchievementService)
 // @Security Bearer
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Limit per page" default(10)
-// @Param status query string false "Filter by status (draft, submitted, verified, rejected)"
-// @Param achievementType query string false "Filter by achievement type"
-// @Param sortBy query string false "Sort by field (created_at, updated_at, submitted_at, status)"
+// @Param status query string false "Filter by status, comma separated (draft, submitted, verified, rejected)"
+// @Param achievementType query string false "Filter by achievement type, comma separated"
+// @Param certificationStatus query string false "Filter by certification status, comma separated (valid, expiring_soon, expired)"
+// @Param competitionLevel query string false "Filter by competition level, comma separated"
+// @Param tags query string false "Filter by tags, comma separated (match any)"
+// @Param programStudy query string false "Filter by student program study, comma separated"
+// @Param academicYear query string false "Filter by student academic year, comma separated"
+// @Param advisorId query string false "Filter by advisor lecturer ID, comma separated"
+// @Param studentId query string false "Filter by student ID, comma separated"
+// @Param dateFrom query string false "Created on or after date (YYYY-MM-DD)"
+// @Param dateTo query string false "Created on or before date (YYYY-MM-DD)"
+// @Param minPoints query int false "Minimum points"
+// @Param maxPoints query int false "Maximum points"
+// @Param hasAttachments query bool false "Filter by attachment presence"
+// @Param sortBy query string false "Sort by field (created_at, updated_at, submitted_at, status, points, title)"
 // @Param sortOrder query string false "Sort order (ASC, DESC)" default(DESC)
+// @Param cursor query string false "Opaque cursor dari next_cursor response sebelumnya, parameter kosong untuk halaman pertama mode cursor (hanya sortBy created_at)"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string "Bad Request"
 // @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /achievements [get]
 func GetAchievements(achievementService servicepostgre.IAchievementService) fiber.Handler {
@@ -80,29 +96,93 @@ func GetAchievements(achievementService servicepostgre.IAchievementService) fibe
 			})
 		}
 
-		page := helper.GetQueryInt(c, "page", 1)
-		limit := helper.GetQueryInt(c, "limit", 10)
-		page, limit = helper.ValidatePagination(page, limit)
+		filter, err := parseAchievementListFilter(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Filter tidak valid",
+				"message": err.Error(),
+			})
+		}
 
-		statusFilter := helper.GetQueryString(c, "status", "")
-		achievementTypeFilter := helper.GetQueryString(c, "achievementType", "")
-		sortBy := helper.GetQueryString(c, "sortBy", "")
-		sortOrder := helper.GetQueryString(c, "sortOrder", "")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
 
-		if sortOrder != "" {
-			sortOrder = strings.ToUpper(sortOrder)
-			if sortOrder != "ASC" && sortOrder != "DESC" {
-				sortOrder = "DESC"
+		response, err := achievementService.GetAchievements(ctx, userID, roleID, filter)
+		if err != nil {
+			if strings.Contains(err.Error(), "akses ditolak") {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   "Akses ditolak",
+					"message": err.Error(),
+				})
 			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal mengambil data",
+				"message": err.Error(),
+			})
+		}
+
+		return c.JSON(response)
+	}
+}
+
+// SearchAchievements godoc
+// @Summary Search achievements
+// @Description Mencari prestasi dengan full-text search pada title, description, nama kompetisi atau organisasi, tags, dan authors. Hasil diurutkan berdasarkan relevansi dan dibatasi berdasarkan role user (Mahasiswa: milik sendiri, Dosen Wali: milik mahasiswa bimbingan, Admin: semua)
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param q query string true "Kata kunci pencarian"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Limit per page" default(10)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /achievements/search [get]
+func SearchAchievements(achievementService servicepostgre.IAchievementService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		roleID, ok := c.Locals("role_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+			})
 		}
 
+		query := helper.GetQueryString(c, "q", "")
+		page := helper.GetQueryInt(c, "page", 1)
+		limit := helper.GetQueryInt(c, "limit", 10)
+		page, limit = helper.ValidatePagination(page, limit)
+
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		response, err := achievementService.GetAchievements(ctx, userID, roleID, page, limit, statusFilter, achievementTypeFilter, sortBy, sortOrder)
+		response, err := achievementService.SearchAchievements(ctx, userID, roleID, query, page, limit)
 		if err != nil {
+			if strings.Contains(err.Error(), "wajib diisi") {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   "Permintaan tidak valid",
+					"message": err.Error(),
+				})
+			}
+			if strings.Contains(err.Error(), "akses ditolak") {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   "Akses ditolak",
+					"message": err.Error(),
+				})
+			}
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error":   "Gagal mengambil data",
+				"error":   "Gagal mencari prestasi",
 				"message": err.Error(),
 			})
 		}
@@ -113,7 +193,7 @@ func GetAchievements(achievementService servicepostgre.IAchievementService) fibe
 
 // GetAchievementByID godoc
 // @Summary Get achievement by ID
-// @Description Mengambil detail achievement berdasarkan ID. Akses dibatasi berdasarkan role (Mahasiswa: hanya milik sendiri, Dosen Wali: hanya mahasiswa bimbingan, Admin: semua)
+// @Description Mengambil detail achievement berdasarkan ID. Akses dibatasi berdasarkan role (Mahasiswa: hanya milik sendiri, Dosen Wali: hanya mahasiswa bimbingan, Admin: semua). Response menyertakan header ETag yang wajib dikirim sebagai If-Match saat update, verify, atau reject
 // @Tags Achievements
 // @Accept json
 // @Produce json
@@ -124,6 +204,7 @@ func GetAchievements(achievementService servicepostgre.IAchievementService) fibe
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 403 {object} map[string]string "Forbidden"
 // @Failure 404 {object} map[string]string "Not Found"
+// @Header 200 {string} ETag "Versi prestasi saat ini"
 // @Router /achievements/{id} [get]
 func GetAchievementByID(achievementService servicepostgre.IAchievementService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -162,6 +243,10 @@ func GetAchievementByID(achievementService servicepostgre.IAchievementService) f
 			})
 		}
 
+		if etag, ok := response["etag"].(string); ok {
+			c.Set(fiber.HeaderETag, etag)
+		}
+
 		return c.JSON(response)
 	}
 }
@@ -178,7 +263,7 @@ func GetAchievementByID(achievementService servicepostgre.IAchievementService) f
 // @Failure 400 {object} map[string]string "Bad Request"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 422 {object} map[string]string "Unprocessable Entity"
+// @Failure 422 {object} map[string]interface{} "Unprocessable Entity, berisi field errors jika detail prestasi tidak valid"
 // @Router /achievements [post]
 func CreateAchievement(achievementService servicepostgre.IAchievementService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -211,10 +296,7 @@ func CreateAchievement(achievementService servicepostgre.IAchievementService) fi
 
 		response, err := achievementService.CreateAchievement(ctx, userID, roleID, *req)
 		if err != nil {
-			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
-				"error":   "Gagal membuat prestasi",
-				"message": err.Error(),
-			})
+			return achievementValidationErrorResponse(c, "Gagal membuat prestasi", err)
 		}
 
 		return c.Status(fiber.StatusOK).JSON(response)
@@ -223,19 +305,22 @@ func CreateAchievement(achievementService servicepostgre.IAchievementService) fi
 
 // UpdateAchievement godoc
 // @Summary Update achievement
-// @Description Memperbarui achievement. Hanya dapat diakses oleh Mahasiswa pemilik dengan permission achievement:update. Hanya dapat diupdate jika status adalah draft
+// @Description Memperbarui achievement. Hanya dapat diakses oleh Mahasiswa pemilik dengan permission achievement:update. Hanya dapat diupdate jika status adalah draft. Header If-Match wajib berisi ETag dari GET /achievements/{id}
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param If-Match header string true "ETag prestasi dari GET /achievements/{id}"
 // @Param body body modelmongo.UpdateAchievementRequest true "Achievement data to update"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string "Bad Request"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 403 {object} map[string]string "Forbidden"
 // @Failure 404 {object} map[string]string "Not Found"
-// @Failure 422 {object} map[string]string "Unprocessable Entity"
+// @Failure 412 {object} map[string]string "Precondition Failed, prestasi sudah diubah sejak ETag diambil"
+// @Failure 422 {object} map[string]interface{} "Unprocessable Entity, berisi field errors jika detail prestasi tidak valid"
+// @Failure 428 {object} map[string]string "Precondition Required, header If-Match tidak dikirim"
 // @Router /achievements/{id} [put]
 func UpdateAchievement(achievementService servicepostgre.IAchievementService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -271,15 +356,24 @@ func UpdateAchievement(achievementService servicepostgre.IAchievementService) fi
 			})
 		}
 
+		expected, status, err := parseIfMatch(c)
+		if err != nil {
+			return c.Status(status).JSON(fiber.Map{
+				"error":   "Prasyarat gagal",
+				"message": err.Error(),
+			})
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		response, err := achievementService.UpdateAchievement(ctx, userID, roleID, mongoID, *req)
+		response, err := achievementService.UpdateAchievement(ctx, userID, roleID, mongoID, *req, expected)
 		if err != nil {
-			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
-				"error":   "Gagal mengupdate prestasi",
-				"message": err.Error(),
-			})
+			return achievementValidationErrorResponse(c, "Gagal mengupdate prestasi", err)
+		}
+
+		if etag, ok := response["etag"].(string); ok {
+			c.Set(fiber.HeaderETag, etag)
 		}
 
 		return c.JSON(response)
@@ -288,7 +382,7 @@ func UpdateAchievement(achievementService servicepostgre.IAchievementService) fi
 
 // UploadAttachment godoc
 // @Summary Upload attachment
-// @Description Mengupload file attachment untuk achievement. Hanya dapat diakses oleh Mahasiswa pemilik dengan permission achievement:update. Hanya dapat diupload jika status adalah draft. Format file: PDF, JPG, PNG, DOC, DOCX (max 10MB)
+// @Description Mengupload file attachment untuk achievement. Hanya dapat diakses oleh Mahasiswa pemilik dengan permission achievement:update. Hanya dapat diupload jika status adalah draft. Format file: PDF (max 50MB), DOC, DOCX (max 10MB), JPG, PNG (max 5MB), MP4 (max 250MB). Body request dibatasi 10MB, file yang lebih besar dikirim lewat endpoint uploads. Isi file harus sesuai dengan ekstensi dan PDF tidak boleh berisi JavaScript. Metadata EXIF, GPS, dan penulis dibuang sebelum file disimpan. Total ukuran attachment mahasiswa dibatasi ATTACHMENT_QUOTA_MB, upload yang melewati kuota ditolak dengan 413
 // @Tags Achievements
 // @Accept multipart/form-data
 // @Produce json
@@ -300,6 +394,7 @@ func UpdateAchievement(achievementService servicepostgre.IAchievementService) fi
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 403 {object} map[string]string "Forbidden"
 // @Failure 404 {object} map[string]string "Not Found"
+// @Failure 413 {object} map[string]string "Request Entity Too Large"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /achievements/{id}/attachments [post]
 func UploadAttachment(achievementService servicepostgre.IAchievementService) fiber.Handler {
@@ -336,79 +431,175 @@ func UploadAttachment(achievementService servicepostgre.IAchievementService) fib
 			})
 		}
 
-		fileType := mime.TypeByExtension(filepath.Ext(file.Filename))
-		if fileType == "" {
-			fileType = "application/octet-stream"
+		content, err := file.Open()
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal mengambil data",
+				"message": "Error membaca file: " + err.Error(),
+			})
+		}
+		defer content.Close()
+
+		check, err := servicepostgre.ValidateAttachmentFile(file.Filename, file.Size, content)
+		if err != nil {
+			return attachmentUploadErrorResponse(c, err)
+		}
+
+		if _, err := content.Seek(0, io.SeekStart); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal mengambil data",
+				"message": "Error membaca file: " + err.Error(),
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		attachment, err := achievementService.UploadFile(ctx, userID, roleID, mongoID, check.FileName, check.FileType, file.Size, content)
+		if err != nil {
+			return attachmentUploadErrorResponse(c, err)
+		}
+
+		return c.JSON(attachment)
+	}
+}
+
+// SubmitAchievement godoc
+// @Summary Submit achievement for verification
+// @Description Submit achievement untuk verifikasi oleh dosen wali. Hanya dapat diakses oleh Mahasiswa pemilik dengan permission achievement:update. Hanya dapat disubmit jika status adalah draft
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Success 200 {object} modelpostgre.UpdateAchievementReferenceResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 422 {object} map[string]interface{} "Unprocessable Entity, berisi field errors jika detail prestasi tidak valid"
+// @Router /achievements/{id}/submit [post]
+func SubmitAchievement(achievementService servicepostgre.IAchievementService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
 		}
 
-		allowedTypes := map[string]bool{
-			"application/pdf":    true,
-			"image/jpeg":         true,
-			"image/png":          true,
-			"application/msword": true,
-			"application/vnd.openxmlformats-officedocument.wordprocessingml.document": true,
+		roleID, ok := c.Locals("role_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+			})
 		}
 
-		if !allowedTypes[fileType] {
+		mongoID := c.Params("id")
+		if mongoID == "" {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 				"error":   "Permintaan tidak valid",
-				"message": "Tipe file tidak diizinkan. Gunakan PDF, JPG, PNG, DOC, atau DOCX.",
+				"message": "ID prestasi wajib diisi.",
 			})
 		}
 
-		timestamp := time.Now().Unix()
-		safeFilename := fmt.Sprintf("%d-%s", timestamp, file.Filename)
-		safeFilename = strings.ReplaceAll(safeFilename, " ", "_")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
 
-		uploadDir := "./uploads"
-		if err := os.MkdirAll(uploadDir, 0755); err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error":   "Gagal mengambil data",
-				"message": "Error membuat folder uploads: " + err.Error(),
+		response, err := achievementService.SubmitAchievement(ctx, userID, roleID, mongoID)
+		if err != nil {
+			return achievementValidationErrorResponse(c, "Gagal submit prestasi", err)
+		}
+
+		return c.Status(fiber.StatusOK).JSON(response)
+	}
+}
+
+// WithdrawAchievement godoc
+// @Summary Withdraw submitted achievement
+// @Description Menarik kembali achievement yang sudah disubmit menjadi draft. Hanya dapat diakses oleh Mahasiswa pemilik dengan permission achievement:update. Hanya dapat di-withdraw jika status adalah submitted dan dosen wali belum mulai mereview
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Success 200 {object} modelpostgre.UpdateAchievementReferenceResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 409 {object} map[string]string "Conflict, dosen wali sudah mulai mereview"
+// @Failure 422 {object} map[string]string "Unprocessable Entity"
+// @Router /achievements/{id}/withdraw [post]
+func WithdrawAchievement(achievementService servicepostgre.IAchievementService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
 			})
 		}
 
-		filePath := filepath.Join(uploadDir, safeFilename)
-		if err := c.SaveFile(file, filePath); err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error":   "Gagal mengambil data",
-				"message": "Error menyimpan file: " + err.Error(),
+		roleID, ok := c.Locals("role_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "Role ID tidak ditemukan. Silakan login ulang.",
 			})
 		}
 
-		fileURL := fmt.Sprintf("/uploads/%s", safeFilename)
+		mongoID := c.Params("id")
+		if mongoID == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": "ID prestasi wajib diisi.",
+			})
+		}
 
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		attachment, err := achievementService.UploadFile(ctx, userID, roleID, mongoID, file.Filename, fileURL, fileType)
+		response, err := achievementService.WithdrawAchievement(ctx, userID, roleID, mongoID)
 		if err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error":   "Gagal mengambil data",
+			if strings.Contains(err.Error(), "akses ditolak") {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   "Akses ditolak",
+					"message": err.Error(),
+				})
+			}
+			if strings.Contains(err.Error(), "sudah mulai mereview") {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error":   "Gagal withdraw prestasi",
+					"message": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error":   "Gagal withdraw prestasi",
 				"message": err.Error(),
 			})
 		}
 
-		return c.JSON(attachment)
+		return c.Status(fiber.StatusOK).JSON(response)
 	}
 }
 
-// SubmitAchievement godoc
-// @Summary Submit achievement for verification
-// @Description Submit achievement untuk verifikasi oleh dosen wali. Hanya dapat diakses oleh Mahasiswa pemilik dengan permission achievement:update. Hanya dapat disubmit jika status adalah draft
+// ConfirmTeamParticipation godoc
+// @Summary Confirm team achievement participation
+// @Description Konfirmasi partisipasi anggota pada prestasi tim. Hanya dapat diakses oleh Mahasiswa anggota tim dengan permission achievement:update. Hanya dapat dikonfirmasi jika status adalah draft. Prestasi tim baru bisa disubmit setelah semua anggota konfirmasi
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path string true "Achievement ID (MongoDB ObjectID)"
-// @Success 200 {object} modelpostgre.UpdateAchievementReferenceResponse
+// @Success 200 {object} modelmongo.ConfirmTeamParticipationResponse
 // @Failure 400 {object} map[string]string "Bad Request"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 403 {object} map[string]string "Forbidden"
-// @Failure 404 {object} map[string]string "Not Found"
 // @Failure 422 {object} map[string]string "Unprocessable Entity"
-// @Router /achievements/{id}/submit [post]
-func SubmitAchievement(achievementService servicepostgre.IAchievementService) fiber.Handler {
+// @Router /achievements/{id}/confirm [post]
+func ConfirmTeamParticipation(achievementService servicepostgre.IAchievementService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		userID, ok := c.Locals("user_id").(string)
 		if !ok {
@@ -437,10 +628,16 @@ func SubmitAchievement(achievementService servicepostgre.IAchievementService) fi
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		response, err := achievementService.SubmitAchievement(ctx, userID, roleID, mongoID)
+		response, err := achievementService.ConfirmTeamParticipation(ctx, userID, roleID, mongoID)
 		if err != nil {
+			if strings.Contains(err.Error(), "akses ditolak") {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   "Akses ditolak",
+					"message": err.Error(),
+				})
+			}
 			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
-				"error":   "Gagal submit prestasi",
+				"error":   "Gagal konfirmasi partisipasi tim",
 				"message": err.Error(),
 			})
 		}
@@ -451,18 +648,22 @@ func SubmitAchievement(achievementService servicepostgre.IAchievementService) fi
 
 // VerifyAchievement godoc
 // @Summary Verify achievement
-// @Description Memverifikasi achievement. Hanya dapat diakses oleh Dosen Wali dengan permission achievement:verify. Hanya dapat diverifikasi jika status adalah submitted
+// @Description Memverifikasi achievement. Hanya dapat diakses oleh Dosen Wali dengan permission achievement:verify. Hanya dapat diverifikasi jika status adalah submitted. Poin dihitung dari rubrik aktif dan bisa di-override dengan justifikasi. Header If-Match wajib berisi ETag dari GET /achievements/{id}
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param If-Match header string true "ETag prestasi dari GET /achievements/{id}"
+// @Param body body modelpostgre.VerifyAchievementRequest false "Override poin (optional)"
 // @Success 200 {object} modelpostgre.VerifyAchievementResponse
 // @Failure 400 {object} map[string]string "Bad Request"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 403 {object} map[string]string "Forbidden"
 // @Failure 404 {object} map[string]string "Not Found"
+// @Failure 412 {object} map[string]string "Precondition Failed, prestasi sudah diubah sejak ETag diambil"
 // @Failure 422 {object} map[string]string "Unprocessable Entity"
+// @Failure 428 {object} map[string]string "Precondition Required, header If-Match tidak dikirim"
 // @Router /achievements/{id}/verify [post]
 func VerifyAchievement(achievementService servicepostgre.IAchievementService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -490,36 +691,64 @@ func VerifyAchievement(achievementService servicepostgre.IAchievementService) fi
 			})
 		}
 
+		req := new(modelpostgre.VerifyAchievementRequest)
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(req); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error":   "Permintaan tidak valid",
+					"message": "Pastikan body permintaan Anda dalam format JSON yang benar.",
+				})
+			}
+		}
+
+		expected, status, err := parseIfMatch(c)
+		if err != nil {
+			return c.Status(status).JSON(fiber.Map{
+				"error":   "Prasyarat gagal",
+				"message": err.Error(),
+			})
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		response, err := achievementService.VerifyAchievement(ctx, userID, roleID, mongoID)
+		response, err := achievementService.VerifyAchievement(ctx, userID, roleID, mongoID, *req, expected)
 		if err != nil {
+			if strings.Contains(err.Error(), "konflik versi") {
+				return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+					"error":   "Gagal memverifikasi prestasi",
+					"message": err.Error(),
+				})
+			}
 			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
 				"error":   "Gagal memverifikasi prestasi",
 				"message": err.Error(),
 			})
 		}
 
+		c.Set(fiber.HeaderETag, response.ETag)
 		return c.Status(fiber.StatusOK).JSON(response)
 	}
 }
 
 // RejectAchievement godoc
 // @Summary Reject achievement
-// @Description Menolak achievement dengan catatan. Hanya dapat diakses oleh Dosen Wali dengan permission achievement:verify. Hanya dapat ditolak jika status adalah submitted
+// @Description Menolak achievement dengan catatan. Hanya dapat diakses oleh Dosen Wali dengan permission achievement:verify. Hanya dapat ditolak jika status adalah submitted. Header If-Match wajib berisi ETag dari GET /achievements/{id}
 // @Tags Achievements
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param If-Match header string true "ETag prestasi dari GET /achievements/{id}"
 // @Param body body modelpostgre.RejectAchievementRequest true "Rejection note"
 // @Success 200 {object} modelpostgre.RejectAchievementResponse
 // @Failure 400 {object} map[string]string "Bad Request"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 403 {object} map[string]string "Forbidden"
 // @Failure 404 {object} map[string]string "Not Found"
+// @Failure 412 {object} map[string]string "Precondition Failed, prestasi sudah diubah sejak ETag diambil"
 // @Failure 422 {object} map[string]string "Unprocessable Entity"
+// @Failure 428 {object} map[string]string "Precondition Required, header If-Match tidak dikirim"
 // @Router /achievements/{id}/reject [post]
 func RejectAchievement(achievementService servicepostgre.IAchievementService) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -555,17 +784,32 @@ func RejectAchievement(achievementService servicepostgre.IAchievementService) fi
 			})
 		}
 
+		expected, status, err := parseIfMatch(c)
+		if err != nil {
+			return c.Status(status).JSON(fiber.Map{
+				"error":   "Prasyarat gagal",
+				"message": err.Error(),
+			})
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		response, err := achievementService.RejectAchievement(ctx, userID, roleID, mongoID, *req)
+		response, err := achievementService.RejectAchievement(ctx, userID, roleID, mongoID, *req, expected)
 		if err != nil {
+			if strings.Contains(err.Error(), "konflik versi") {
+				return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+					"error":   "Gagal menolak prestasi",
+					"message": err.Error(),
+				})
+			}
 			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
 				"error":   "Gagal menolak prestasi",
 				"message": err.Error(),
 			})
 		}
 
+		c.Set(fiber.HeaderETag, response.ETag)
 		return c.Status(fiber.StatusOK).JSON(response)
 	}
 }
@@ -681,22 +925,352 @@ func DeleteAchievement(achievementService servicepostgre.IAchievementService) fi
 	}
 }
 
+// GetDeletedAchievements godoc
+// @Summary Get deleted achievements
+// @Description Mengambil daftar prestasi di tempat sampah beserta waktu hapus, status sebelum dihapus, dan jadwal hapus permanen. Hanya dapat diakses oleh Admin dengan permission achievement:delete
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Limit per page" default(10)
+// @Success 200 {object} modelpostgre.GetDeletedAchievementsResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /achievements/trash [get]
+func GetDeletedAchievements(achievementService servicepostgre.IAchievementService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		roleID, ok := c.Locals("role_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		page := helper.GetQueryInt(c, "page", 1)
+		limit := helper.GetQueryInt(c, "limit", 10)
+		page, limit = helper.ValidatePagination(page, limit)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		response, err := achievementService.GetDeletedAchievements(ctx, userID, roleID, page, limit)
+		if err != nil {
+			if strings.Contains(err.Error(), "akses ditolak") {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   "Akses ditolak",
+					"message": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal mengambil data",
+				"message": err.Error(),
+			})
+		}
+
+		return c.JSON(response)
+	}
+}
+
+// RestoreAchievement godoc
+// @Summary Restore deleted achievement
+// @Description Mengembalikan prestasi dari tempat sampah ke status sebelum dihapus. Hanya dapat diakses oleh Admin dengan permission achievement:delete
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Success 200 {object} modelpostgre.UpdateAchievementReferenceResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 422 {object} map[string]string "Unprocessable Entity"
+// @Router /achievements/{id}/restore [post]
+func RestoreAchievement(achievementService servicepostgre.IAchievementService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		roleID, ok := c.Locals("role_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		mongoID := c.Params("id")
+		if mongoID == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": "ID prestasi wajib diisi.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		response, err := achievementService.RestoreAchievement(ctx, userID, roleID, mongoID)
+		if err != nil {
+			if strings.Contains(err.Error(), "akses ditolak") {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   "Akses ditolak",
+					"message": err.Error(),
+				})
+			}
+			if strings.Contains(err.Error(), "tidak ditemukan di tempat sampah") {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error":   "Prestasi tidak ditemukan",
+					"message": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error":   "Gagal me-restore prestasi",
+				"message": err.Error(),
+			})
+		}
+
+		return c.Status(fiber.StatusOK).JSON(response)
+	}
+}
+
+// PurgeDeletedAchievements godoc
+// @Summary Purge deleted achievements
+// @Description Menghapus permanen prestasi yang sudah di tempat sampah lebih lama dari masa retensi, termasuk dokumen MongoDB, reference, notifikasi, dan file attachment. Default dry-run, kirim dry_run=false untuk benar-benar menghapus. Hanya dapat diakses oleh Admin dengan permission achievement:delete
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param dry_run query bool false "Hanya tampilkan prestasi yang akan dihapus" default(true)
+// @Success 200 {object} modelpostgre.TrashPurgeResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /achievements/trash/purge [post]
+func PurgeDeletedAchievements(achievementService servicepostgre.IAchievementService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		roleID, ok := c.Locals("role_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		dryRun := c.QueryBool("dry_run", true)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		response, err := achievementService.PurgeDeletedAchievements(ctx, userID, roleID, dryRun)
+		if err != nil {
+			if strings.Contains(err.Error(), "akses ditolak") {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   "Akses ditolak",
+					"message": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal menghapus permanen prestasi",
+				"message": err.Error(),
+			})
+		}
+
+		return c.JSON(response)
+	}
+}
+
 // #2 proses: setup semua route untuk achievement dengan middleware AuthRequired, PermissionRequired, dan RoleRequired
 func AchievementRoutes(app *fiber.App, achievementService servicepostgre.IAchievementService, db *sql.DB) {
 	app.Get("/api/v1/achievements/stats", GetAchievementStats(achievementService))
+	app.Get("/api/v1/attachments/:id/:key", DownloadSignedAttachment(achievementService))
 
 	achievements := app.Group("/api/v1/achievements", middlewarepostgre.AuthRequired())
 
 	achievements.Get("", GetAchievements(achievementService))
+	achievements.Get("/search", SearchAchievements(achievementService))
+	achievements.Get("/trash", middlewarepostgre.PermissionRequired(db, "achievement:delete"), GetDeletedAchievements(achievementService))
+	achievements.Post("/trash/purge", middlewarepostgre.PermissionRequired(db, "achievement:delete"), PurgeDeletedAchievements(achievementService))
 
 	achievements.Get("/:id", middlewarepostgre.PermissionRequired(db, "achievement:read"), GetAchievementByID(achievementService))
 
 	achievements.Post("", middlewarepostgre.PermissionRequired(db, "achievement:create"), CreateAchievement(achievementService))
 	achievements.Put("/:id", middlewarepostgre.PermissionRequired(db, "achievement:update"), UpdateAchievement(achievementService))
 	achievements.Post("/:id/attachments", middlewarepostgre.PermissionRequired(db, "achievement:update"), UploadAttachment(achievementService))
+	achievements.Get("/:id/attachments/:key", middlewarepostgre.PermissionRequired(db, "achievement:read"), DownloadAttachment(achievementService))
+	achievements.Post("/:id/attachments/:key/link", middlewarepostgre.PermissionRequired(db, "achievement:read"), CreateAttachmentLink(achievementService))
+	achievements.Put("/:id/attachments/:attachmentId", middlewarepostgre.PermissionRequired(db, "achievement:update"), ReplaceAttachment(achievementService))
+	achievements.Delete("/:id/attachments/:attachmentId", middlewarepostgre.PermissionRequired(db, "achievement:update"), DeleteAttachment(achievementService))
 	achievements.Post("/:id/submit", middlewarepostgre.PermissionRequired(db, "achievement:update"), SubmitAchievement(achievementService))
+	achievements.Post("/:id/withdraw", middlewarepostgre.PermissionRequired(db, "achievement:update"), WithdrawAchievement(achievementService))
+	achievements.Post("/:id/confirm", middlewarepostgre.PermissionRequired(db, "achievement:update"), ConfirmTeamParticipation(achievementService))
 	achievements.Post("/:id/verify", middlewarepostgre.PermissionRequired(db, "achievement:verify"), VerifyAchievement(achievementService))
 	achievements.Post("/:id/reject", middlewarepostgre.PermissionRequired(db, "achievement:verify"), RejectAchievement(achievementService))
 	achievements.Get("/:id/history", middlewarepostgre.PermissionRequired(db, "achievement:read"), GetAchievementHistory(achievementService))
 	achievements.Delete("/:id", middlewarepostgre.PermissionRequired(db, "achievement:delete"), DeleteAchievement(achievementService))
+	achievements.Post("/:id/restore", middlewarepostgre.PermissionRequired(db, "achievement:delete"), RestoreAchievement(achievementService))
+}
+
+// #3 proses: kirim response 422, sertakan daftar field error jika error berasal dari validasi detail prestasi. Konflik versi dikirim sebagai 412
+func achievementValidationErrorResponse(c *fiber.Ctx, title string, err error) error {
+	var validationErr *modelmongo.ValidationError
+	if errors.As(err, &validationErr) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error":   title,
+			"message": validationErr.Message,
+			"errors":  validationErr.Errors,
+		})
+	}
+
+	if strings.Contains(err.Error(), "konflik versi") {
+		return c.Status(fiber.StatusPreconditionFailed).JSON(fiber.Map{
+			"error":   title,
+			"message": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+		"error":   title,
+		"message": err.Error(),
+	})
+}
+
+// #4 proses: baca query parameter filter daftar prestasi, nilai multi-select dipisah koma dan format yang salah dikembalikan sebagai error
+func parseAchievementListFilter(c *fiber.Ctx) (modelpostgre.AchievementListFilter, error) {
+	page, limit := helper.ValidatePagination(helper.GetQueryInt(c, "page", 1), helper.GetQueryInt(c, "limit", 10))
+
+	filter := modelpostgre.AchievementListFilter{
+		Page:                  page,
+		Limit:                 limit,
+		Statuses:              helper.GetQueryList(c, "status"),
+		AchievementTypes:      helper.GetQueryList(c, "achievementType"),
+		CertificationStatuses: helper.GetQueryList(c, "certificationStatus"),
+		CompetitionLevels:     helper.GetQueryList(c, "competitionLevel"),
+		Tags:                  helper.GetQueryList(c, "tags"),
+		ProgramStudies:        helper.GetQueryList(c, "programStudy"),
+		AcademicYears:         helper.GetQueryList(c, "academicYear"),
+		AdvisorIDs:            helper.GetQueryList(c, "advisorId"),
+		StudentIDs:            helper.GetQueryList(c, "studentId"),
+		SortBy:                helper.GetQueryString(c, "sortBy", ""),
+		SortOrder:             strings.ToUpper(helper.GetQueryString(c, "sortOrder", "DESC")),
+	}
+	if filter.SortOrder != "ASC" {
+		filter.SortOrder = "DESC"
+	}
+
+	for key, target := range map[string]**time.Time{"dateFrom": &filter.DateFrom, "dateTo": &filter.DateTo} {
+		if value := c.Query(key); value != "" {
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return filter, fmt.Errorf("%s harus berformat YYYY-MM-DD", key)
+			}
+			*target = &date
+		}
+	}
+
+	for key, target := range map[string]**int{"minPoints": &filter.MinPoints, "maxPoints": &filter.MaxPoints} {
+		if value := c.Query(key); value != "" {
+			points, err := strconv.Atoi(value)
+			if err != nil {
+				return filter, fmt.Errorf("%s harus berupa angka", key)
+			}
+			*target = &points
+		}
+	}
+
+	if value := c.Query("hasAttachments"); value != "" {
+		hasAttachments, err := strconv.ParseBool(value)
+		if err != nil {
+			return filter, errors.New("hasAttachments harus bernilai true atau false")
+		}
+		filter.HasAttachments = &hasAttachments
+	}
+
+	if filter.DateFrom != nil && filter.DateTo != nil && filter.DateTo.Before(*filter.DateFrom) {
+		return filter, errors.New("dateTo tidak boleh sebelum dateFrom")
+	}
+	if filter.MinPoints != nil && filter.MaxPoints != nil && *filter.MaxPoints < *filter.MinPoints {
+		return filter, errors.New("maxPoints tidak boleh lebih kecil dari minPoints")
+	}
+
+	// #4a proses: mode cursor hanya untuk urutan created_at, ID di cursor harus ObjectID prestasi
+	cursorMode, cursor, err := parsePageCursor(c)
+	if err != nil {
+		return filter, err
+	}
+	if cursor != nil && !primitive.IsValidObjectID(cursor.ID) {
+		return filter, errors.New("cursor tidak valid")
+	}
+	if cursorMode && filter.SortBy != "" && filter.SortBy != "created_at" {
+		return filter, errors.New("cursor hanya bisa dipakai dengan sortBy created_at")
+	}
+	filter.CursorMode = cursorMode
+	filter.Cursor = cursor
+
+	return filter, nil
+}
+
+// #5 proses: baca header If-Match yang wajib untuk request yang mengubah prestasi, return status 428 jika tidak ada dan 412 jika bukan ETag prestasi
+func parseIfMatch(c *fiber.Ctx) (modelpostgre.AchievementVersion, int, error) {
+	ifMatch := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if ifMatch == "" {
+		return modelpostgre.AchievementVersion{}, fiber.StatusPreconditionRequired, errors.New("header If-Match wajib diisi dengan ETag dari detail prestasi")
+	}
+
+	achievementVersion, referenceVersion, err := helper.ParseETag(ifMatch)
+	if err != nil {
+		return modelpostgre.AchievementVersion{}, fiber.StatusPreconditionFailed, errors.New("If-Match tidak cocok dengan versi prestasi saat ini")
+	}
+
+	return modelpostgre.AchievementVersion{Achievement: achievementVersion, Reference: referenceVersion}, 0, nil
+}
+
+// #6 proses: mapping error upload attachment, file tidak valid dikirim sebagai 400, file terlalu besar dan kuota storage terlampaui sebagai 413
+func attachmentUploadErrorResponse(c *fiber.Ctx, err error) error {
+	var validationErr *modelmongo.AttachmentValidationError
+	if errors.As(err, &validationErr) {
+		status := fiber.StatusBadRequest
+		if validationErr.TooLarge {
+			status = fiber.StatusRequestEntityTooLarge
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error":   "File tidak valid",
+			"message": validationErr.Message,
+		})
+	}
+	var quotaErr *modelmongo.AttachmentQuotaError
+	if errors.As(err, &quotaErr) {
+		return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+			"error":   "Kuota storage terlampaui",
+			"message": quotaErr.Message,
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error":   "Gagal mengambil data",
+		"message": err.Error(),
+	})
 }
@@ -0,0 +1,230 @@
+package route
+
+// #1 proses: import library yang diperlukan untuk context, database, model, service, middleware, strings, time, dan fiber
+import (
+	"context"
+	"database/sql"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	middlewarepostgre "sistem-pelaporan-prestasi-mahasiswa/middleware/postgre"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetAchievementTypes godoc
+// @Summary Get all achievement types
+// @Description Mengambil semua tipe prestasi yang aktif. Gunakan includeInactive=true untuk ikut menampilkan tipe nonaktif
+// @Tags Achievement Types
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param includeInactive query bool false "Tampilkan tipe nonaktif"
+// @Success 200 {object} modelmongo.GetAllAchievementTypesResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /achievement-types [get]
+func GetAchievementTypes(achievementTypeService servicepostgre.IAchievementTypeService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		includeInactive := c.QueryBool("includeInactive", false)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		response, err := achievementTypeService.GetAchievementTypes(ctx, includeInactive)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal mengambil data",
+				"message": err.Error(),
+			})
+		}
+
+		return c.JSON(response)
+	}
+}
+
+// GetAchievementTypeByCode godoc
+// @Summary Get achievement type by code
+// @Description Mengambil tipe prestasi berdasarkan kode beserta definisi custom field
+// @Tags Achievement Types
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param code path string true "Kode tipe prestasi"
+// @Success 200 {object} modelmongo.GetAchievementTypeResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /achievement-types/{code} [get]
+func GetAchievementTypeByCode(achievementTypeService servicepostgre.IAchievementTypeService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		response, err := achievementTypeService.GetAchievementTypeByCode(ctx, c.Params("code"))
+		if err != nil {
+			if strings.Contains(err.Error(), "tidak ditemukan") {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error":   "Tipe prestasi tidak ditemukan",
+					"message": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal mengambil data",
+				"message": err.Error(),
+			})
+		}
+
+		return c.JSON(response)
+	}
+}
+
+// GetAchievementTypeSchema godoc
+// @Summary Get achievement type form schema
+// @Description Mengambil skema form tipe prestasi (field detail bawaan dan custom field) supaya frontend bisa merender form secara dinamis
+// @Tags Achievement Types
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param code path string true "Kode tipe prestasi"
+// @Success 200 {object} modelmongo.GetAchievementTypeSchemaResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /achievement-types/{code}/schema [get]
+func GetAchievementTypeSchema(achievementTypeService servicepostgre.IAchievementTypeService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		response, err := achievementTypeService.GetAchievementTypeSchema(ctx, c.Params("code"))
+		if err != nil {
+			if strings.Contains(err.Error(), "tidak ditemukan") {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error":   "Tipe prestasi tidak ditemukan",
+					"message": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal mengambil data",
+				"message": err.Error(),
+			})
+		}
+
+		return c.JSON(response)
+	}
+}
+
+// CreateAchievementType godoc
+// @Summary Create achievement type
+// @Description Membuat tipe prestasi baru beserta skema custom field. Hanya dapat diakses oleh admin dengan permission achievement_type:manage
+// @Tags Achievement Types
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param body body modelmongo.CreateAchievementTypeRequest true "Achievement type data"
+// @Success 201 {object} modelmongo.GetAchievementTypeResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 409 {object} map[string]string "Conflict"
+// @Failure 422 {object} map[string]string "Unprocessable Entity"
+// @Router /achievement-types [post]
+func CreateAchievementType(achievementTypeService servicepostgre.IAchievementTypeService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		req := new(modelmongo.CreateAchievementTypeRequest)
+		if err := c.BodyParser(req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": "Pastikan body permintaan Anda dalam format JSON yang benar.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		response, err := achievementTypeService.CreateAchievementType(ctx, userID, *req)
+		if err != nil {
+			if strings.Contains(err.Error(), "sudah digunakan") {
+				return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+					"error":   "Gagal membuat tipe prestasi",
+					"message": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error":   "Gagal membuat tipe prestasi",
+				"message": err.Error(),
+			})
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(response)
+	}
+}
+
+// UpdateAchievementType godoc
+// @Summary Update achievement type
+// @Description Memperbarui nama, deskripsi, skema custom field, atau status aktif tipe prestasi. Hanya dapat diakses oleh admin dengan permission achievement_type:manage
+// @Tags Achievement Types
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param code path string true "Kode tipe prestasi"
+// @Param body body modelmongo.UpdateAchievementTypeRequest true "Achievement type data to update"
+// @Success 200 {object} modelmongo.GetAchievementTypeResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 422 {object} map[string]string "Unprocessable Entity"
+// @Router /achievement-types/{code} [put]
+func UpdateAchievementType(achievementTypeService servicepostgre.IAchievementTypeService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		req := new(modelmongo.UpdateAchievementTypeRequest)
+		if err := c.BodyParser(req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": "Pastikan body permintaan Anda dalam format JSON yang benar.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		response, err := achievementTypeService.UpdateAchievementType(ctx, c.Params("code"), *req)
+		if err != nil {
+			if strings.Contains(err.Error(), "tidak ditemukan") {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error":   "Tipe prestasi tidak ditemukan",
+					"message": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error":   "Gagal mengupdate tipe prestasi",
+				"message": err.Error(),
+			})
+		}
+
+		return c.JSON(response)
+	}
+}
+
+// #2 proses: setup semua route untuk tipe prestasi, route kelola tipe butuh permission achievement_type:manage
+func AchievementTypeRoutes(app *fiber.App, achievementTypeService servicepostgre.IAchievementTypeService, db *sql.DB) {
+	achievementTypes := app.Group("/api/v1/achievement-types", middlewarepostgre.AuthRequired())
+
+	achievementTypes.Get("", GetAchievementTypes(achievementTypeService))
+	achievementTypes.Get("/:code", GetAchievementTypeByCode(achievementTypeService))
+	achievementTypes.Get("/:code/schema", GetAchievementTypeSchema(achievementTypeService))
+
+	achievementTypes.Post("", middlewarepostgre.PermissionRequired(db, "achievement_type:manage"), CreateAchievementType(achievementTypeService))
+	achievementTypes.Put("/:code", middlewarepostgre.PermissionRequired(db, "achievement_type:manage"), UpdateAchievementType(achievementTypeService))
+}
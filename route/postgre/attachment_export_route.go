@@ -0,0 +1,120 @@
+package route
+
+// #1 proses: import library yang diperlukan untuk context, database, errors, fmt, io, mime, model, service, middleware, time, dan fiber
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+	middlewarepostgre "sistem-pelaporan-prestasi-mahasiswa/middleware/postgre"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ExportAttachments godoc
+// @Summary Export achievement attachments as ZIP
+// @Description Mengunduh semua attachment prestasi sesuai filter dalam satu file ZIP yang dikirim secara streaming. File dikelompokkan per program studi, mahasiswa, dan prestasi, dan manifest.csv di dalam ZIP memetakan setiap file ke prestasi dan mahasiswanya. File yang belum selesai dipindai, terinfeksi, atau gagal dibaca tidak dimasukkan dan dicatat di kolom note manifest. Mahasiswa hanya mendapat attachment miliknya, Dosen Wali hanya mahasiswa bimbingannya, Admin semua. Filter yang bisa dipilih lebih dari satu dipisah koma
+// @Tags Reports
+// @Produce application/zip
+// @Security Bearer
+// @Param studentId query string false "Filter student ID"
+// @Param advisorId query string false "Filter ID dosen wali"
+// @Param programStudy query string false "Filter program studi"
+// @Param academicYear query string false "Filter angkatan"
+// @Param status query string false "Filter status prestasi (draft, submitted, verified, rejected)"
+// @Param dateFrom query string false "Tanggal prestasi dibuat mulai (YYYY-MM-DD)"
+// @Param dateTo query string false "Tanggal prestasi dibuat sampai (YYYY-MM-DD)"
+// @Success 200 {file} file "ZIP attachment beserta manifest.csv"
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /reports/attachments/export [get]
+func ExportAttachments(exportService servicepostgre.IAttachmentExportService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		roleID, ok := c.Locals("role_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		filter, err := parseAttachmentExportFilter(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": err.Error(),
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+
+		export, err := exportService.PrepareAttachmentExport(ctx, userID, roleID, filter)
+		if err != nil {
+			cancel()
+			return attachmentErrorResponse(c, err)
+		}
+
+		reader, writer := io.Pipe()
+		go func() {
+			writer.CloseWithError(exportService.WriteAttachmentExport(ctx, export, writer))
+		}()
+
+		c.Set(fiber.HeaderContentType, "application/zip")
+		c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": export.FileName}))
+		c.Set("X-Content-Type-Options", "nosniff")
+		c.Set(fiber.HeaderCacheControl, "private, no-store")
+
+		return c.SendStream(&cancelOnClose{ReadCloser: reader, cancel: cancel})
+	}
+}
+
+// #2 proses: baca query parameter filter export, hanya dimensi mahasiswa, dosen wali, program studi, angkatan, status, dan tanggal yang dipakai
+func parseAttachmentExportFilter(c *fiber.Ctx) (modelpostgre.AchievementListFilter, error) {
+	filter := modelpostgre.AchievementListFilter{
+		Statuses:       helper.GetQueryList(c, "status"),
+		ProgramStudies: helper.GetQueryList(c, "programStudy"),
+		AcademicYears:  helper.GetQueryList(c, "academicYear"),
+		AdvisorIDs:     helper.GetQueryList(c, "advisorId"),
+		StudentIDs:     helper.GetQueryList(c, "studentId"),
+		SortBy:         "created_at",
+		SortOrder:      "ASC",
+	}
+
+	for key, target := range map[string]**time.Time{"dateFrom": &filter.DateFrom, "dateTo": &filter.DateTo} {
+		if value := c.Query(key); value != "" {
+			date, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return filter, fmt.Errorf("%s harus berformat YYYY-MM-DD", key)
+			}
+			*target = &date
+		}
+	}
+
+	if filter.DateFrom != nil && filter.DateTo != nil && filter.DateTo.Before(*filter.DateFrom) {
+		return filter, errors.New("dateTo tidak boleh sebelum dateFrom")
+	}
+
+	return filter, nil
+}
+
+// #3 proses: daftarkan route export attachment, wajib login dan memiliki permission achievement:read
+func AttachmentExportRoutes(app *fiber.App, exportService servicepostgre.IAttachmentExportService, db *sql.DB) {
+	app.Get("/api/v1/reports/attachments/export", middlewarepostgre.AuthRequired(), middlewarepostgre.PermissionRequired(db, "achievement:read"), ExportAttachments(exportService))
+}
@@ -0,0 +1,260 @@
+package route
+
+// #1 proses: import library yang diperlukan untuk context, model, service, middleware, strconv, strings, time, dan fiber
+import (
+	"context"
+	"database/sql"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	middlewarepostgre "sistem-pelaporan-prestasi-mahasiswa/middleware/postgre"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CreateAttachmentUpload godoc
+// @Summary Start resumable attachment upload
+// @Description Memulai upload attachment bertahap untuk file besar (di atas 10MB, misalnya video atau PDF hasil scan). Nama file, ukuran, status prestasi, dan kuota dicek di awal. Isi attachment_id untuk mengganti attachment yang sudah ada. Response berisi id upload dan chunk_size yang wajib dipakai client. Hanya dapat diakses oleh Mahasiswa pemilik prestasi
+// @Tags Achievements
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param body body modelmongo.CreateAttachmentUploadRequest true "Nama dan ukuran file"
+// @Success 201 {object} modelmongo.AttachmentUploadResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 413 {object} map[string]string "Request Entity Too Large"
+// @Failure 429 {object} map[string]string "Too Many Requests"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /achievements/{id}/uploads [post]
+func CreateAttachmentUpload(uploadService servicepostgre.IAttachmentUploadService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		var req modelmongo.CreateAttachmentUploadRequest
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": "Format body tidak valid: " + err.Error(),
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		response, err := uploadService.CreateUpload(ctx, userID, c.Params("id"), req)
+		if err != nil {
+			return attachmentUploadSessionErrorResponse(c, err)
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(response)
+	}
+}
+
+// GetAttachmentUpload godoc
+// @Summary Get resumable attachment upload
+// @Description Mengambil status upload bertahap. Header Upload-Offset berisi jumlah byte yang sudah diterima server, dipakai client untuk melanjutkan upload yang terputus
+// @Tags Achievements
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param uploadId path string true "Upload ID"
+// @Success 200 {object} modelmongo.AttachmentUploadResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /achievements/{id}/uploads/{uploadId} [get]
+func GetAttachmentUpload(uploadService servicepostgre.IAttachmentUploadService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		response, err := uploadService.GetUpload(ctx, userID, c.Params("id"), c.Params("uploadId"))
+		if err != nil {
+			return attachmentUploadSessionErrorResponse(c, err)
+		}
+
+		c.Set("Upload-Offset", strconv.FormatInt(response.Data.Offset, 10))
+		return c.JSON(response)
+	}
+}
+
+// UploadAttachmentChunk godoc
+// @Summary Upload attachment chunk
+// @Description Mengirim satu potongan file sebagai body mentah. Header Upload-Offset wajib sama dengan offset yang sudah diterima server dan setiap potongan selain yang terakhir harus berukuran chunk_size. Jika offset tidak sesuai, ambil status upload lalu lanjutkan dari offset terbaru
+// @Tags Achievements
+// @Accept octet-stream
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param uploadId path string true "Upload ID"
+// @Param Upload-Offset header int true "Offset potongan dalam byte"
+// @Success 200 {object} modelmongo.AttachmentUploadResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 409 {object} map[string]string "Conflict"
+// @Failure 413 {object} map[string]string "Request Entity Too Large"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /achievements/{id}/uploads/{uploadId} [patch]
+func UploadAttachmentChunk(uploadService servicepostgre.IAttachmentUploadService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		offset, err := strconv.ParseInt(c.Get("Upload-Offset"), 10, 64)
+		if err != nil || offset < 0 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": "Header Upload-Offset wajib diisi dengan angka.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		response, err := uploadService.AppendChunk(ctx, userID, c.Params("id"), c.Params("uploadId"), offset, c.Body())
+		if err != nil {
+			return attachmentUploadSessionErrorResponse(c, err)
+		}
+
+		c.Set("Upload-Offset", strconv.FormatInt(response.Data.Offset, 10))
+		return c.JSON(response)
+	}
+}
+
+// CompleteAttachmentUpload godoc
+// @Summary Complete resumable attachment upload
+// @Description Menggabungkan semua potongan lalu menyimpannya sebagai attachment dengan validasi isi file, kuota, dan scan malware yang sama dengan upload biasa. Jika upload dibuat dengan attachment_id, attachment tersebut diganti. File yang tidak valid membuat sesi upload dihapus
+// @Tags Achievements
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param uploadId path string true "Upload ID"
+// @Success 200 {object} modelmongo.Attachment
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 413 {object} map[string]string "Request Entity Too Large"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /achievements/{id}/uploads/{uploadId}/complete [post]
+func CompleteAttachmentUpload(uploadService servicepostgre.IAttachmentUploadService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		roleID, ok := c.Locals("role_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+		defer cancel()
+
+		attachment, err := uploadService.CompleteUpload(ctx, userID, roleID, c.Params("id"), c.Params("uploadId"))
+		if err != nil {
+			return attachmentUploadSessionErrorResponse(c, err)
+		}
+
+		return c.JSON(attachment)
+	}
+}
+
+// CancelAttachmentUpload godoc
+// @Summary Cancel resumable attachment upload
+// @Description Membatalkan upload bertahap dan menghapus potongan file yang sudah diterima
+// @Tags Achievements
+// @Produce json
+// @Security Bearer
+// @Param id path string true "Achievement ID (MongoDB ObjectID)"
+// @Param uploadId path string true "Upload ID"
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /achievements/{id}/uploads/{uploadId} [delete]
+func CancelAttachmentUpload(uploadService servicepostgre.IAttachmentUploadService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		if err := uploadService.CancelUpload(ctx, userID, c.Params("id"), c.Params("uploadId")); err != nil {
+			return attachmentUploadSessionErrorResponse(c, err)
+		}
+
+		return c.JSON(fiber.Map{
+			"status":  "success",
+			"message": "Upload dibatalkan",
+		})
+	}
+}
+
+// #2 proses: mapping error upload bertahap, offset yang tidak sesuai dikirim sebagai 409 dan batas sesi aktif sebagai 429, selebihnya sama dengan ganti attachment
+func attachmentUploadSessionErrorResponse(c *fiber.Ctx, err error) error {
+	if strings.Contains(err.Error(), "offset upload tidak sesuai") {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":   "Offset tidak sesuai",
+			"message": err.Error(),
+		})
+	}
+	if strings.Contains(err.Error(), "terlalu banyak upload") {
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"error":   "Terlalu banyak upload",
+			"message": err.Error(),
+		})
+	}
+	return attachmentChangeErrorResponse(c, err)
+}
+
+// #3 proses: setup route upload bertahap dengan middleware AuthRequired dan PermissionRequired yang sama dengan upload attachment
+func AttachmentUploadRoutes(app *fiber.App, uploadService servicepostgre.IAttachmentUploadService, db *sql.DB) {
+	app.Post("/api/v1/achievements/:id/uploads", middlewarepostgre.AuthRequired(), middlewarepostgre.PermissionRequired(db, "achievement:update"), CreateAttachmentUpload(uploadService))
+	app.Get("/api/v1/achievements/:id/uploads/:uploadId", middlewarepostgre.AuthRequired(), middlewarepostgre.PermissionRequired(db, "achievement:update"), GetAttachmentUpload(uploadService))
+	app.Patch("/api/v1/achievements/:id/uploads/:uploadId", middlewarepostgre.AuthRequired(), middlewarepostgre.PermissionRequired(db, "achievement:update"), UploadAttachmentChunk(uploadService))
+	app.Post("/api/v1/achievements/:id/uploads/:uploadId/complete", middlewarepostgre.AuthRequired(), middlewarepostgre.PermissionRequired(db, "achievement:update"), CompleteAttachmentUpload(uploadService))
+	app.Delete("/api/v1/achievements/:id/uploads/:uploadId", middlewarepostgre.AuthRequired(), middlewarepostgre.PermissionRequired(db, "achievement:update"), CancelAttachmentUpload(uploadService))
+}
@@ -1,9 +1,10 @@
 package route
 
-// #1 proses: import library yang diperlukan untuk context, service, helper, middleware, time, dan fiber
+// #1 proses: import library yang diperlukan untuk context, errors, model, service, helper, middleware, time, dan fiber
 import (
 	"context"
-	_ "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	"errors"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
 	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
 	"sistem-pelaporan-prestasi-mahasiswa/helper"
 	middlewarepostgre "sistem-pelaporan-prestasi-mahasiswa/middleware/postgre"
@@ -14,14 +15,16 @@ import (
 
 // GetNotifications godoc
 // @Summary Get notifications
-// @Description Mengambil daftar notifikasi user dengan pagination
+// @Description Mengambil daftar notifikasi user dengan pagination page atau cursor. Jika parameter cursor ada (boleh kosong untuk halaman pertama), response memakai pagination cursor tanpa total
 // @Tags Notifications
 // @Accept json
 // @Produce json
 // @Security Bearer
 // @Param page query int false "Page number" default(1)
 // @Param limit query int false "Limit per page" default(10)
+// @Param cursor query string false "Opaque cursor dari next_cursor response sebelumnya"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string "Bad Request"
 // @Failure 401 {object} map[string]string "Unauthorized"
 // @Failure 500 {object} map[string]string "Internal Server Error"
 // @Router /notifications [get]
@@ -39,9 +42,32 @@ func GetNotifications(notificationService servicepostgre.INotificationService) f
 		limit := helper.GetQueryInt(c, "limit", 10)
 		page, limit = helper.ValidatePagination(page, limit)
 
+		cursorMode, cursor, err := parsePageCursor(c)
+		if err == nil && cursor != nil && !helper.IsValidUUID(cursor.ID) {
+			err = errors.New("cursor tidak valid")
+		}
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Cursor tidak valid",
+				"message": err.Error(),
+			})
+		}
+
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
+		if cursorMode {
+			response, err := notificationService.GetNotificationsByCursor(ctx, userID, cursor, limit)
+			if err != nil {
+				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+					"error":   "Gagal mengambil data",
+					"message": "Error mengambil notifications: " + err.Error(),
+				})
+			}
+
+			return c.Status(fiber.StatusOK).JSON(response)
+		}
+
 		response, err := notificationService.GetNotifications(ctx, userID, page, limit)
 		if err != nil {
 			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -181,3 +207,22 @@ func NotificationRoutes(app *fiber.App, notificationService servicepostgre.INoti
 	notifications.Put("/:id/read", MarkNotificationAsRead(notificationService))
 	notifications.Put("/read-all", MarkAllNotificationsAsRead(notificationService))
 }
+
+// #3 proses: cek mode cursor dari query parameter, cursor kosong berarti halaman pertama dan cursor rusak dikembalikan sebagai error
+func parsePageCursor(c *fiber.Ctx) (bool, *modelpostgre.PageCursor, error) {
+	if !c.Context().QueryArgs().Has("cursor") {
+		return false, nil, nil
+	}
+
+	value := c.Query("cursor")
+	if value == "" {
+		return true, nil, nil
+	}
+
+	createdAt, id, err := helper.DecodeCursor(value)
+	if err != nil {
+		return true, nil, err
+	}
+
+	return true, &modelpostgre.PageCursor{CreatedAt: createdAt, ID: id}, nil
+}
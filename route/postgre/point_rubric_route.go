@@ -0,0 +1,278 @@
+package route
+
+// #1 proses: import library yang diperlukan untuk context, database, model, service, middleware, strconv, strings, time, dan fiber
+import (
+	"context"
+	"database/sql"
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	middlewarepostgre "sistem-pelaporan-prestasi-mahasiswa/middleware/postgre"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetPointRubrics godoc
+// @Summary Get all point rubrics
+// @Description Mengambil semua versi rubrik poin prestasi
+// @Tags Point Rubrics
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} modelpostgre.GetAllPointRubricsResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /point-rubrics [get]
+func GetPointRubrics(pointRubricService servicepostgre.IPointRubricService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		response, err := pointRubricService.GetPointRubrics(ctx)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal mengambil data",
+				"message": err.Error(),
+			})
+		}
+
+		return c.JSON(response)
+	}
+}
+
+// GetActivePointRubric godoc
+// @Summary Get active point rubric
+// @Description Mengambil rubrik poin yang sedang aktif dan dipakai untuk menghitung poin prestasi
+// @Tags Point Rubrics
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} modelpostgre.GetPointRubricResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /point-rubrics/active [get]
+func GetActivePointRubric(pointRubricService servicepostgre.IPointRubricService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		response, err := pointRubricService.GetActivePointRubric(ctx)
+		if err != nil {
+			if strings.Contains(err.Error(), "belum ada") {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error":   "Rubrik poin tidak ditemukan",
+					"message": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal mengambil data",
+				"message": err.Error(),
+			})
+		}
+
+		return c.JSON(response)
+	}
+}
+
+// GetPointRubricByVersion godoc
+// @Summary Get point rubric by version
+// @Description Mengambil rubrik poin berdasarkan nomor versi, dipakai untuk menelusuri poin prestasi lama
+// @Tags Point Rubrics
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param version path int true "Versi rubrik"
+// @Success 200 {object} modelpostgre.GetPointRubricResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /point-rubrics/{version} [get]
+func GetPointRubricByVersion(pointRubricService servicepostgre.IPointRubricService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		version, err := strconv.Atoi(c.Params("version"))
+		if err != nil || version < 1 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": "Versi rubrik harus berupa angka positif.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		response, err := pointRubricService.GetPointRubricByVersion(ctx, version)
+		if err != nil {
+			if strings.Contains(err.Error(), "tidak ditemukan") {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error":   "Rubrik poin tidak ditemukan",
+					"message": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal mengambil data",
+				"message": err.Error(),
+			})
+		}
+
+		return c.JSON(response)
+	}
+}
+
+// CreatePointRubric godoc
+// @Summary Create point rubric
+// @Description Membuat rubrik poin versi baru. Hanya dapat diakses oleh admin dengan permission rubric:manage. Versi lama tetap disimpan
+// @Tags Point Rubrics
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param body body modelpostgre.CreatePointRubricRequest true "Rubric data"
+// @Success 201 {object} modelpostgre.GetPointRubricResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 422 {object} map[string]string "Unprocessable Entity"
+// @Router /point-rubrics [post]
+func CreatePointRubric(pointRubricService servicepostgre.IPointRubricService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		req := new(modelpostgre.CreatePointRubricRequest)
+		if err := c.BodyParser(req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": "Pastikan body permintaan Anda dalam format JSON yang benar.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		response, err := pointRubricService.CreatePointRubric(ctx, userID, *req)
+		if err != nil {
+			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+				"error":   "Gagal membuat rubrik poin",
+				"message": err.Error(),
+			})
+		}
+
+		return c.Status(fiber.StatusCreated).JSON(response)
+	}
+}
+
+// ActivatePointRubric godoc
+// @Summary Activate point rubric
+// @Description Mengaktifkan rubrik poin versi tertentu untuk perhitungan poin berikutnya. Hanya dapat diakses oleh admin dengan permission rubric:manage
+// @Tags Point Rubrics
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param version path int true "Versi rubrik"
+// @Success 200 {object} modelpostgre.GetPointRubricResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 404 {object} map[string]string "Not Found"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /point-rubrics/{version}/activate [post]
+func ActivatePointRubric(pointRubricService servicepostgre.IPointRubricService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		version, err := strconv.Atoi(c.Params("version"))
+		if err != nil || version < 1 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": "Versi rubrik harus berupa angka positif.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		response, err := pointRubricService.ActivatePointRubric(ctx, version)
+		if err != nil {
+			if strings.Contains(err.Error(), "tidak ditemukan") {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+					"error":   "Rubrik poin tidak ditemukan",
+					"message": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal mengaktifkan rubrik poin",
+				"message": err.Error(),
+			})
+		}
+
+		return c.JSON(response)
+	}
+}
+
+// CalculatePoints godoc
+// @Summary Calculate achievement points
+// @Description Menghitung preview poin prestasi berdasarkan tipe dan detail memakai rubrik yang sedang aktif
+// @Tags Point Rubrics
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param body body modelmongo.CalculatePointsRequest true "Tipe dan detail prestasi"
+// @Success 200 {object} modelpostgre.CalculatePointsResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /point-rubrics/calculate [post]
+func CalculatePoints(pointRubricService servicepostgre.IPointRubricService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		req := new(modelmongo.CalculatePointsRequest)
+		if err := c.BodyParser(req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": "Pastikan body permintaan Anda dalam format JSON yang benar.",
+			})
+		}
+
+		if req.AchievementType == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Permintaan tidak valid",
+				"message": "achievementType wajib diisi.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		result, err := pointRubricService.CalculatePoints(ctx, req.AchievementType, req.Details)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal menghitung poin",
+				"message": err.Error(),
+			})
+		}
+
+		return c.JSON(modelpostgre.CalculatePointsResponse{
+			Status: "success",
+			Data:   *result,
+		})
+	}
+}
+
+// #2 proses: setup semua route untuk rubrik poin, route kelola rubrik butuh permission rubric:manage
+func PointRubricRoutes(app *fiber.App, pointRubricService servicepostgre.IPointRubricService, db *sql.DB) {
+	rubrics := app.Group("/api/v1/point-rubrics", middlewarepostgre.AuthRequired())
+
+	rubrics.Get("", GetPointRubrics(pointRubricService))
+	rubrics.Get("/active", GetActivePointRubric(pointRubricService))
+	rubrics.Post("/calculate", CalculatePoints(pointRubricService))
+	rubrics.Get("/:version", GetPointRubricByVersion(pointRubricService))
+
+	rubrics.Post("", middlewarepostgre.PermissionRequired(db, "rubric:manage"), CreatePointRubric(pointRubricService))
+	rubrics.Post("/:version/activate", middlewarepostgre.PermissionRequired(db, "rubric:manage"), ActivatePointRubric(pointRubricService))
+}
@@ -0,0 +1,145 @@
+package route
+
+// #1 proses: import library yang diperlukan untuk context, database, model, service, middleware, strings, time, dan fiber
+import (
+	"context"
+	"database/sql"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	middlewarepostgre "sistem-pelaporan-prestasi-mahasiswa/middleware/postgre"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetReconciliationReport godoc
+// @Summary Get MongoDB and PostgreSQL reconciliation report
+// @Description Memeriksa konsistensi dokumen prestasi MongoDB dengan reference PostgreSQL tanpa mengubah data. Ketidakkonsistenan dikelompokkan per kategori: mongo_without_reference, reference_without_mongo, dan deleted_status_mismatch. Gunakan format=text untuk laporan teks. Hanya dapat diakses oleh Admin
+// @Tags Reports
+// @Accept json
+// @Produce json,plain
+// @Security Bearer
+// @Param format query string false "Format output: json (default) atau text"
+// @Success 200 {object} model.ReconcileResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /reports/reconciliation [get]
+func GetReconciliationReport(reconciliationService servicepostgre.IReconciliationService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		roleID, ok := c.Locals("role_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+		defer cancel()
+
+		response, err := reconciliationService.GetReconciliationReport(ctx, userID, roleID)
+		if err != nil {
+			return reconciliationErrorResponse(c, err)
+		}
+
+		return reconciliationResponse(c, response)
+	}
+}
+
+// RepairInconsistencies godoc
+// @Summary Repair MongoDB and PostgreSQL inconsistencies
+// @Description Menjalankan rekonsiliasi dan memperbaiki ketidakkonsistenan sesuai policy per kategori. mongo_without_reference: report, purge, adopt. reference_without_mongo: report, delete_reference. deleted_status_mismatch: report, follow_reference, follow_mongo. Policy kosong berarti report. Setiap item diperiksa ulang sebelum diperbaiki. Hanya dapat diakses oleh Admin
+// @Tags Reports
+// @Accept json
+// @Produce json,plain
+// @Security Bearer
+// @Param body body model.ReconcilePolicy true "Policy perbaikan per kategori"
+// @Param format query string false "Format output: json (default) atau text"
+// @Success 200 {object} model.ReconcileResponse
+// @Failure 400 {object} map[string]string "Bad Request"
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /reports/reconciliation/repair [post]
+func RepairInconsistencies(reconciliationService servicepostgre.IReconciliationService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		roleID, ok := c.Locals("role_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		var policy modelpostgre.ReconcilePolicy
+		if err := c.BodyParser(&policy); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error":   "Request tidak valid",
+				"message": "Format body tidak valid: " + err.Error(),
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		response, err := reconciliationService.RepairInconsistencies(ctx, userID, roleID, policy)
+		if err != nil {
+			return reconciliationErrorResponse(c, err)
+		}
+
+		return reconciliationResponse(c, response)
+	}
+}
+
+// #2 proses: kirim hasil rekonsiliasi sebagai JSON atau teks sesuai query format
+func reconciliationResponse(c *fiber.Ctx, response *modelpostgre.ReconcileResponse) error {
+	if strings.EqualFold(c.Query("format"), "text") {
+		c.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+		return c.SendString(servicepostgre.FormatReconcileReport(&response.Data))
+	}
+	return c.JSON(response)
+}
+
+// #3 proses: mapping error service rekonsiliasi ke status HTTP
+func reconciliationErrorResponse(c *fiber.Ctx, err error) error {
+	if strings.Contains(err.Error(), "akses ditolak") {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error":   "Akses ditolak",
+			"message": err.Error(),
+		})
+	}
+	if strings.Contains(err.Error(), "policy tidak valid") {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Request tidak valid",
+			"message": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error":   "Gagal menjalankan rekonsiliasi",
+		"message": err.Error(),
+	})
+}
+
+// #4 proses: setup route rekonsiliasi dengan middleware AuthRequired dan PermissionRequired, berada di bawah prefix reports
+func ReconciliationRoutes(app *fiber.App, reconciliationService servicepostgre.IReconciliationService, db *sql.DB) {
+	app.Get("/api/v1/reports/reconciliation", middlewarepostgre.AuthRequired(), middlewarepostgre.PermissionRequired(db, "report:reconcile"), GetReconciliationReport(reconciliationService))
+	app.Post("/api/v1/reports/reconciliation/repair", middlewarepostgre.AuthRequired(), middlewarepostgre.PermissionRequired(db, "report:reconcile"), RepairInconsistencies(reconciliationService))
+}
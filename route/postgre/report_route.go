@@ -1,11 +1,12 @@
 package route
 
-// #1 proses: import library yang diperlukan untuk context, database, service, middleware, time, dan fiber
+// #1 proses: import library yang diperlukan untuk context, database, service, middleware, strings, time, dan fiber
 import (
 	"context"
 	"database/sql"
 	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
 	middlewarepostgre "sistem-pelaporan-prestasi-mahasiswa/middleware/postgre"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -203,6 +204,108 @@ func GetLecturerReport(reportService servicepostgre.IReportService) fiber.Handle
 	}
 }
 
+// GetDuplicateReport godoc
+// @Summary Get duplicate achievement report
+// @Description Mengambil kelompok prestasi yang kemungkinan duplikat lintas semua mahasiswa berdasarkan judul, nama kompetisi dan tanggal event, nomor sertifikat, dan hash attachment. Hanya dapat diakses oleh Admin
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /reports/duplicates [get]
+func GetDuplicateReport(reportService servicepostgre.IReportService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		roleID, ok := c.Locals("role_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		response, err := reportService.GetDuplicateReport(ctx, userID, roleID)
+		if err != nil {
+			if strings.Contains(err.Error(), "akses ditolak") {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   "Akses ditolak",
+					"message": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal mengambil data",
+				"message": err.Error(),
+			})
+		}
+
+		return c.JSON(response)
+	}
+}
+
+// GetStorageUsageReport godoc
+// @Summary Get attachment storage usage report
+// @Description Mengambil pemakaian storage attachment per mahasiswa, kuota per mahasiswa, dan penghematan dari file dengan isi sama yang hanya disimpan sekali. Hanya dapat diakses oleh Admin
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /reports/storage-usage [get]
+func GetStorageUsageReport(reportService servicepostgre.IReportService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		roleID, ok := c.Locals("role_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		response, err := reportService.GetStorageUsageReport(ctx, userID, roleID)
+		if err != nil {
+			if strings.Contains(err.Error(), "akses ditolak") {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   "Akses ditolak",
+					"message": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal mengambil data",
+				"message": err.Error(),
+			})
+		}
+
+		return c.JSON(response)
+	}
+}
+
 // #2 proses: setup semua route untuk laporan dengan middleware AuthRequired
 func ReportRoutes(app *fiber.App, reportService servicepostgre.IReportService, db *sql.DB) {
 	reports := app.Group("/api/v1/reports", middlewarepostgre.AuthRequired())
@@ -212,4 +315,6 @@ func ReportRoutes(app *fiber.App, reportService servicepostgre.IReportService, d
 	reports.Get("/student/:id", GetStudentReport(reportService))
 	reports.Get("/lecturer", GetCurrentLecturerReport(reportService))
 	reports.Get("/lecturer/:id", GetLecturerReport(reportService))
+	reports.Get("/duplicates", GetDuplicateReport(reportService))
+	reports.Get("/storage-usage", GetStorageUsageReport(reportService))
 }
@@ -0,0 +1,69 @@
+package route
+
+// #1 proses: import library yang diperlukan untuk context, database, service, middleware, strings, time, dan fiber
+import (
+	"context"
+	"database/sql"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	middlewarepostgre "sistem-pelaporan-prestasi-mahasiswa/middleware/postgre"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetOverdueAchievements godoc
+// @Summary Get overdue achievements
+// @Description Mengambil prestasi submitted yang sudah melewati batas SLA verifikasi beserta umurnya, tahap SLA (reminder atau escalated), dan waktu pengingat atau eskalasi dikirim. Hanya dapat diakses oleh Admin
+// @Tags Reports
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Success 200 {object} model.GetOverdueAchievementsResponse
+// @Failure 401 {object} map[string]string "Unauthorized"
+// @Failure 403 {object} map[string]string "Forbidden"
+// @Failure 500 {object} map[string]string "Internal Server Error"
+// @Router /reports/overdue [get]
+func GetOverdueAchievements(slaService servicepostgre.ISLAService) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, ok := c.Locals("user_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "User ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		roleID, ok := c.Locals("role_id").(string)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error":   "Tidak diizinkan",
+				"message": "Role ID tidak ditemukan. Silakan login ulang.",
+			})
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		response, err := slaService.GetOverdueAchievements(ctx, userID, roleID)
+		if err != nil {
+			if strings.Contains(err.Error(), "akses ditolak") {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"error":   "Akses ditolak",
+					"message": err.Error(),
+				})
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error":   "Gagal mengambil data",
+				"message": err.Error(),
+			})
+		}
+
+		return c.JSON(response)
+	}
+}
+
+// #2 proses: setup route SLA dengan middleware AuthRequired dan PermissionRequired, daftar prestasi overdue berada di bawah prefix reports
+func SLARoutes(app *fiber.App, slaService servicepostgre.ISLAService, db *sql.DB) {
+	app.Get("/api/v1/reports/overdue", middlewarepostgre.AuthRequired(), middlewarepostgre.PermissionRequired(db, "report:read"), GetOverdueAchievements(slaService))
+}
@@ -0,0 +1,61 @@
+package model_test
+
+import (
+	"testing"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+)
+
+func TestAchievement_PointsForStudent(t *testing.T) {
+	individual := modelmongo.Achievement{StudentID: "student-1", Points: 50}
+
+	if got := individual.PointsForStudent("student-1"); got != 50 {
+		t.Errorf("Expected owner to get 50 points, got %d", got)
+	}
+	if got := individual.PointsForStudent("student-2"); got != 0 {
+		t.Errorf("Expected other student to get 0 points, got %d", got)
+	}
+
+	team := modelmongo.Achievement{
+		StudentID: "student-1",
+		Points:    75,
+		TeamMembers: []modelmongo.TeamMember{
+			{StudentID: "student-1", Role: "Ketua", PointShare: 50},
+			{StudentID: "student-2", Role: "Anggota", PointShare: 30},
+			{StudentID: "student-3", Role: "Anggota", PointShare: 20},
+		},
+	}
+
+	testCases := map[string]int{
+		"student-1": 38,
+		"student-2": 22,
+		"student-3": 15,
+		"student-4": 0,
+	}
+
+	for studentID, want := range testCases {
+		if got := team.PointsForStudent(studentID); got != want {
+			t.Errorf("PointsForStudent(%q) = %d, want %d", studentID, got, want)
+		}
+	}
+}
+
+func TestAchievement_InvolvesStudent(t *testing.T) {
+	team := modelmongo.Achievement{
+		StudentID: "student-1",
+		TeamMembers: []modelmongo.TeamMember{
+			{StudentID: "student-1", PointShare: 50},
+			{StudentID: "student-2", PointShare: 50},
+		},
+	}
+
+	if !team.IsTeam() {
+		t.Error("Expected achievement with members to be a team achievement")
+	}
+	if !team.InvolvesStudent("student-2") {
+		t.Error("Expected team member to be involved")
+	}
+	if team.InvolvesStudent("student-3") {
+		t.Error("Expected non-member not to be involved")
+	}
+}
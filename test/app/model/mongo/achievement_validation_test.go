@@ -0,0 +1,59 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+)
+
+func TestValidationError_Error(t *testing.T) {
+	err := &modelmongo.ValidationError{
+		Message: "data prestasi tidak valid",
+		Errors: []modelmongo.FieldError{
+			{Field: "details.competitionName", Message: "wajib diisi untuk tipe competition"},
+			{Field: "details.issn", Message: "format ISSN tidak valid"},
+		},
+	}
+
+	expected := "data prestasi tidak valid: details.competitionName wajib diisi untuk tipe competition; details.issn format ISSN tidak valid"
+	if err.Error() != expected {
+		t.Errorf("Expected '%s', got '%s'", expected, err.Error())
+	}
+}
+
+func TestValidationError_JSON(t *testing.T) {
+	err := modelmongo.ValidationError{
+		Message: "data prestasi tidak valid",
+		Errors:  []modelmongo.FieldError{{Field: "details.doi", Message: "format DOI tidak valid"}},
+	}
+
+	data, marshalErr := json.Marshal(err)
+	if marshalErr != nil {
+		t.Fatalf("Failed to marshal ValidationError: %v", marshalErr)
+	}
+
+	expected := `{"message":"data prestasi tidak valid","errors":[{"field":"details.doi","message":"format DOI tidak valid"}]}`
+	if string(data) != expected {
+		t.Errorf("Expected %s, got %s", expected, string(data))
+	}
+}
+
+func TestAchievementDetailRules_RequiredFields(t *testing.T) {
+	required := map[string][]string{}
+	for achievementType, rules := range modelmongo.AchievementDetailRules {
+		for _, rule := range rules {
+			if rule.Required {
+				required[achievementType] = append(required[achievementType], rule.Field)
+			}
+		}
+	}
+
+	if len(required[modelmongo.AchievementTypeCompetition]) != 2 {
+		t.Errorf("Expected 2 required fields for competition, got %v", required[modelmongo.AchievementTypeCompetition])
+	}
+
+	if len(required[modelmongo.AllAchievementTypes]) != 0 {
+		t.Errorf("Expected no required fields for all types, got %v", required[modelmongo.AllAchievementTypes])
+	}
+}
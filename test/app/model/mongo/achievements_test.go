@@ -577,12 +577,6 @@ func TestCreateAchievementRequest_StructCreation(t *testing.T) {
 		CompetitionLevel: &competitionLevel,
 		Rank:             &rank,
 	}
-	attachment := modelmongo.Attachment{
-		FileName:   "document.pdf",
-		FileURL:    "https://example.com/files/document.pdf",
-		FileType:   "application/pdf",
-		UploadedAt: time.Now(),
-	}
 	tags := []string{"programming", "competition"}
 
 	req := modelmongo.CreateAchievementRequest{
@@ -591,9 +585,7 @@ func TestCreateAchievementRequest_StructCreation(t *testing.T) {
 		Title:           "Juara 1 Lomba Programming",
 		Description:     "Menjadi juara 1 dalam lomba programming nasional",
 		Details:         details,
-		Attachments:     []modelmongo.Attachment{attachment},
 		Tags:            tags,
-		Points:          100,
 	}
 
 	if req.StudentID != "student-id-1" {
@@ -605,12 +597,6 @@ func TestCreateAchievementRequest_StructCreation(t *testing.T) {
 	if req.Title != "Juara 1 Lomba Programming" {
 		t.Errorf("Expected Title 'Juara 1 Lomba Programming', got '%s'", req.Title)
 	}
-	if req.Points != 100 {
-		t.Errorf("Expected Points 100, got %d", req.Points)
-	}
-	if len(req.Attachments) != 1 {
-		t.Errorf("Expected 1 attachment, got %d", len(req.Attachments))
-	}
 }
 
 func TestCreateAchievementRequest_JSONMarshalling(t *testing.T) {
@@ -620,9 +606,7 @@ func TestCreateAchievementRequest_JSONMarshalling(t *testing.T) {
 		Title:           "Juara 1 Lomba Programming",
 		Description:     "Menjadi juara 1 dalam lomba programming nasional",
 		Details:         modelmongo.AchievementDetails{},
-		Attachments:     []modelmongo.Attachment{},
 		Tags:            []string{"programming"},
-		Points:          100,
 	}
 
 	jsonData, err := json.Marshal(req)
@@ -644,9 +628,6 @@ func TestCreateAchievementRequest_JSONMarshalling(t *testing.T) {
 	if result["title"] != "Juara 1 Lomba Programming" {
 		t.Errorf("Expected title 'Juara 1 Lomba Programming', got '%v'", result["title"])
 	}
-	if result["points"] != float64(100) {
-		t.Errorf("Expected points 100, got '%v'", result["points"])
-	}
 }
 
 func TestCreateAchievementRequest_JSONUnmarshalling(t *testing.T) {
@@ -672,23 +653,45 @@ func TestCreateAchievementRequest_JSONUnmarshalling(t *testing.T) {
 	if req.AchievementType != "academic" {
 		t.Errorf("Expected AchievementType 'academic', got '%s'", req.AchievementType)
 	}
-	if req.Points != 100 {
-		t.Errorf("Expected Points 100, got %d", req.Points)
+}
+
+func TestAchievementRequests_IgnoreClientAttachments(t *testing.T) {
+	body := []byte(`{
+		"title": "Juara 1 Lomba Programming",
+		"attachments": [{"fileName": "curian.pdf", "fileUrl": "/uploads/1700000000-milik-orang-lain.pdf", "size": 0, "scanStatus": "clean"}]
+	}`)
+
+	var createReq modelmongo.CreateAchievementRequest
+	if err := json.Unmarshal(body, &createReq); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var updateReq modelmongo.UpdateAchievementRequest
+	if err := json.Unmarshal(body, &updateReq); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for name, req := range map[string]interface{}{"create": createReq, "update": updateReq} {
+		jsonData, err := json.Marshal(req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		var result map[string]interface{}
+		if err := json.Unmarshal(jsonData, &result); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+
+		if _, exists := result["attachments"]; exists {
+			t.Errorf("Expected %s request to drop client attachments, got %v", name, result["attachments"])
+		}
 	}
 }
 
 func TestUpdateAchievementRequest_StructCreation(t *testing.T) {
-	points := 150
 	competitionName := "Lomba Programming"
 	details := &modelmongo.AchievementDetails{
 		CompetitionName: &competitionName,
 	}
-	attachment := modelmongo.Attachment{
-		FileName:   "new-document.pdf",
-		FileURL:    "https://example.com/files/new-document.pdf",
-		FileType:   "application/pdf",
-		UploadedAt: time.Now(),
-	}
 	tags := []string{"programming", "updated"}
 
 	req := modelmongo.UpdateAchievementRequest{
@@ -696,9 +699,7 @@ func TestUpdateAchievementRequest_StructCreation(t *testing.T) {
 		Title:           "Updated Title",
 		Description:     "Updated Description",
 		Details:         details,
-		Attachments:     []modelmongo.Attachment{attachment},
 		Tags:            tags,
-		Points:          &points,
 	}
 
 	if req.AchievementType != modelmongo.AchievementTypeCompetition {
@@ -707,21 +708,16 @@ func TestUpdateAchievementRequest_StructCreation(t *testing.T) {
 	if req.Title != "Updated Title" {
 		t.Errorf("Expected Title 'Updated Title', got '%s'", req.Title)
 	}
-	if req.Points == nil || *req.Points != 150 {
-		t.Errorf("Expected Points 150, got '%v'", req.Points)
-	}
 	if req.Details == nil {
 		t.Error("Expected Details to be non-nil")
 	}
 }
 
 func TestUpdateAchievementRequest_JSONMarshalling(t *testing.T) {
-	points := 150
 	req := modelmongo.UpdateAchievementRequest{
 		AchievementType: modelmongo.AchievementTypeCompetition,
 		Title:           "Updated Title",
 		Description:     "Updated Description",
-		Points:          &points,
 	}
 
 	jsonData, err := json.Marshal(req)
@@ -740,9 +736,6 @@ func TestUpdateAchievementRequest_JSONMarshalling(t *testing.T) {
 	if result["title"] != "Updated Title" {
 		t.Errorf("Expected title 'Updated Title', got '%v'", result["title"])
 	}
-	if result["points"] != float64(150) {
-		t.Errorf("Expected points 150, got '%v'", result["points"])
-	}
 }
 
 func TestUpdateAchievementRequest_JSONMarshallingOmitempty(t *testing.T) {
@@ -763,9 +756,6 @@ func TestUpdateAchievementRequest_JSONMarshallingOmitempty(t *testing.T) {
 	if _, exists := result["achievementType"]; exists {
 		t.Error("Expected achievementType to be omitted when empty")
 	}
-	if _, exists := result["points"]; exists {
-		t.Error("Expected points to be omitted when nil")
-	}
 	if _, exists := result["details"]; exists {
 		t.Error("Expected details to be omitted when nil")
 	}
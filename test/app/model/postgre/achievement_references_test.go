@@ -342,10 +342,17 @@ func TestUpdateAchievementReferenceRequest_JSONMarshalling(t *testing.T) {
 }
 
 func TestVerifyAchievementRequest_StructCreation(t *testing.T) {
-	req := modelpostgre.VerifyAchievementRequest{}
+	points := 120
+	req := modelpostgre.VerifyAchievementRequest{
+		Points:              &points,
+		PointsJustification: "Kompetisi diikuti oleh lebih dari 50 negara",
+	}
 
-	if req == (modelpostgre.VerifyAchievementRequest{}) {
-		// Empty struct is valid
+	if req.Points == nil || *req.Points != 120 {
+		t.Errorf("Expected Points 120, got %v", req.Points)
+	}
+	if req.PointsJustification != "Kompetisi diikuti oleh lebih dari 50 negara" {
+		t.Errorf("Expected PointsJustification to be set, got '%s'", req.PointsJustification)
 	}
 }
 
@@ -367,6 +374,22 @@ func TestVerifyAchievementRequest_JSONMarshalling(t *testing.T) {
 	}
 }
 
+func TestVerifyAchievementRequest_JSONUnmarshalling(t *testing.T) {
+	jsonStr := `{"points": 120, "points_justification": "Kompetisi tingkat dunia"}`
+
+	var req modelpostgre.VerifyAchievementRequest
+	if err := json.Unmarshal([]byte(jsonStr), &req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if req.Points == nil || *req.Points != 120 {
+		t.Errorf("Expected Points 120, got %v", req.Points)
+	}
+	if req.PointsJustification != "Kompetisi tingkat dunia" {
+		t.Errorf("Expected PointsJustification 'Kompetisi tingkat dunia', got '%s'", req.PointsJustification)
+	}
+}
+
 func TestRejectAchievementRequest_StructCreation(t *testing.T) {
 	req := modelpostgre.RejectAchievementRequest{
 		RejectionNote: "Data tidak lengkap",
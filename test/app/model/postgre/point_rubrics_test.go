@@ -0,0 +1,150 @@
+package model_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+)
+
+func TestPointRuleFieldConstants(t *testing.T) {
+	if modelpostgre.PointRuleFieldCompetitionLevel != "competitionLevel" {
+		t.Errorf("Expected PointRuleFieldCompetitionLevel 'competitionLevel', got '%s'", modelpostgre.PointRuleFieldCompetitionLevel)
+	}
+	if modelpostgre.PointRuleFieldRank != "rank" {
+		t.Errorf("Expected PointRuleFieldRank 'rank', got '%s'", modelpostgre.PointRuleFieldRank)
+	}
+	if modelpostgre.PointRuleFieldMedalType != "medalType" {
+		t.Errorf("Expected PointRuleFieldMedalType 'medalType', got '%s'", modelpostgre.PointRuleFieldMedalType)
+	}
+	if modelpostgre.PointRuleFieldPublicationType != "publicationType" {
+		t.Errorf("Expected PointRuleFieldPublicationType 'publicationType', got '%s'", modelpostgre.PointRuleFieldPublicationType)
+	}
+	if modelpostgre.PointRuleFieldPosition != "position" {
+		t.Errorf("Expected PointRuleFieldPosition 'position', got '%s'", modelpostgre.PointRuleFieldPosition)
+	}
+}
+
+func TestPointRule_JSONMarshallingOmitempty(t *testing.T) {
+	rule := modelpostgre.PointRule{
+		AchievementType: "academic",
+		Points:          20,
+	}
+
+	jsonData, err := json.Marshal(rule)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result["achievement_type"] != "academic" {
+		t.Errorf("Expected achievement_type 'academic', got '%v'", result["achievement_type"])
+	}
+	if result["points"] != float64(20) {
+		t.Errorf("Expected points 20, got '%v'", result["points"])
+	}
+	if _, exists := result["field"]; exists {
+		t.Error("Expected field to be omitted when empty")
+	}
+	if _, exists := result["value"]; exists {
+		t.Error("Expected value to be omitted when empty")
+	}
+}
+
+func TestPointRubric_StructCreation(t *testing.T) {
+	now := time.Now()
+	description := "Rubrik semester ganjil"
+	rubric := modelpostgre.PointRubric{
+		ID:          "rubric-id-1",
+		Version:     2,
+		Name:        "Rubrik 2025",
+		Description: &description,
+		Rules: []modelpostgre.PointRule{
+			{AchievementType: "competition", Points: 10},
+			{AchievementType: "competition", Field: modelpostgre.PointRuleFieldCompetitionLevel, Value: "national", Points: 50},
+		},
+		IsActive:    true,
+		ActivatedAt: &now,
+		CreatedAt:   now,
+	}
+
+	if rubric.Version != 2 {
+		t.Errorf("Expected Version 2, got %d", rubric.Version)
+	}
+	if len(rubric.Rules) != 2 {
+		t.Errorf("Expected 2 rules, got %d", len(rubric.Rules))
+	}
+	if !rubric.IsActive {
+		t.Error("Expected IsActive true")
+	}
+	if rubric.Description == nil || *rubric.Description != "Rubrik semester ganjil" {
+		t.Errorf("Expected Description 'Rubrik semester ganjil', got '%v'", rubric.Description)
+	}
+}
+
+func TestCreatePointRubricRequest_JSONUnmarshalling(t *testing.T) {
+	jsonStr := `{
+		"name": "Rubrik 2025",
+		"description": "Rubrik baru",
+		"rules": [
+			{"achievement_type": "publication", "points": 20},
+			{"achievement_type": "publication", "field": "publicationType", "value": "journal", "points": 60}
+		],
+		"activate": true
+	}`
+
+	var req modelpostgre.CreatePointRubricRequest
+	if err := json.Unmarshal([]byte(jsonStr), &req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if req.Name != "Rubrik 2025" {
+		t.Errorf("Expected Name 'Rubrik 2025', got '%s'", req.Name)
+	}
+	if len(req.Rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(req.Rules))
+	}
+	if req.Rules[1].Field != modelpostgre.PointRuleFieldPublicationType || req.Rules[1].Value != "journal" {
+		t.Errorf("Expected second rule publicationType=journal, got %s=%s", req.Rules[1].Field, req.Rules[1].Value)
+	}
+	if !req.Activate {
+		t.Error("Expected Activate true")
+	}
+}
+
+func TestCalculatePointsResponse_JSONMarshalling(t *testing.T) {
+	resp := modelpostgre.CalculatePointsResponse{
+		Status: "success",
+		Data: modelpostgre.PointCalculationResult{
+			Points:        60,
+			RubricVersion: 1,
+			MatchedRules:  []modelpostgre.PointRule{{AchievementType: "competition", Points: 60}},
+		},
+	}
+
+	jsonData, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(jsonData, &result); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data, ok := result["data"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected data to be an object")
+	}
+	if data["points"] != float64(60) {
+		t.Errorf("Expected points 60, got '%v'", data["points"])
+	}
+	if data["rubric_version"] != float64(1) {
+		t.Errorf("Expected rubric_version 1, got '%v'", data["rubric_version"])
+	}
+}
@@ -2,6 +2,7 @@ package repository_test
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -141,6 +142,186 @@ func TestAchievementRepository_GetAchievementByID_NotFound(t *testing.T) {
 	}
 }
 
+func TestAchievementRepository_UpdateAchievement_VersionCheck(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestMongoDB(t)
+	defer db.Client().Disconnect(context.Background())
+
+	repo := repositorymongo.NewAchievementRepository(db)
+	ctx := context.Background()
+
+	created, err := repo.CreateAchievement(ctx, &modelmongo.Achievement{
+		StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+		AchievementType: "academic",
+		Title:           "Judul Awal",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test achievement: %v", err)
+	}
+	defer cleanupTestData(t, db, created.ID.Hex())
+
+	if created.Version != 1 {
+		t.Fatalf("Expected new achievement at version 1, got %d", created.Version)
+	}
+
+	updated, err := repo.UpdateAchievement(ctx, created.ID.Hex(), 1, modelmongo.UpdateAchievementRequest{Title: "Tab Pertama"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if updated == nil || updated.Version != 2 {
+		t.Fatalf("Expected version 2 after update, got %+v", updated)
+	}
+
+	// tab kedua masih memegang versi 1, update-nya tidak boleh menimpa
+	stale, err := repo.UpdateAchievement(ctx, created.ID.Hex(), 1, modelmongo.UpdateAchievementRequest{Title: "Tab Kedua"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if stale != nil {
+		t.Fatal("Expected stale update to be rejected")
+	}
+
+	current, _ := repo.GetAchievementByID(ctx, created.ID.Hex())
+	if current.Title != "Tab Pertama" {
+		t.Errorf("Expected title from first update, got %s", current.Title)
+	}
+}
+
+func TestAchievementRepository_UpdateAchievement_IgnoresClientScanStatus(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestMongoDB(t)
+	defer db.Client().Disconnect(context.Background())
+
+	repo := repositorymongo.NewAchievementRepository(db)
+	ctx := context.Background()
+
+	created, err := repo.CreateAchievement(ctx, &modelmongo.Achievement{
+		StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+		AchievementType: "academic",
+		Title:           "Judul Awal",
+		Attachments: []modelmongo.Attachment{
+			{ID: "att-1", FileName: "a.pdf", FileURL: "/uploads/hash-a.pdf", ScanStatus: modelmongo.AttachmentScanPending},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test achievement: %v", err)
+	}
+	defer cleanupTestData(t, db, created.ID.Hex())
+
+	// body PUT dari klien mencoba menandai file yang belum dipindai sebagai bersih
+	var req modelmongo.UpdateAchievementRequest
+	body := `{"title": "Judul Baru", "attachments": [{"id": "att-1", "fileName": "a.pdf", "fileUrl": "/uploads/hash-a.pdf", "scanStatus": "clean"}]}`
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := repo.UpdateAchievement(ctx, created.ID.Hex(), created.Version, req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	current, _ := repo.GetAchievementByID(ctx, created.ID.Hex())
+	if current.Title != "Judul Baru" {
+		t.Errorf("Expected title updated, got %s", current.Title)
+	}
+	if len(current.Attachments) != 1 || current.Attachments[0].ScanStatus != modelmongo.AttachmentScanPending {
+		t.Errorf("Expected scan status to stay pending, got %+v", current.Attachments)
+	}
+}
+
+func TestAchievementRepository_GetStudentAttachmentUsage_IgnoresForgedSize(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestMongoDB(t)
+	defer db.Client().Disconnect(context.Background())
+
+	repo := repositorymongo.NewAchievementRepository(db)
+	ctx := context.Background()
+
+	studentID := "550e8400-e29b-41d4-a716-446655440047"
+	created, err := repo.CreateAchievement(ctx, &modelmongo.Achievement{
+		StudentID:       studentID,
+		AchievementType: "academic",
+		Title:           "Kuota",
+		Attachments: []modelmongo.Attachment{
+			{ID: "att-1", FileName: "a.pdf", FileURL: "/uploads/hash-a.pdf", Size: 4096},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test achievement: %v", err)
+	}
+	defer cleanupTestData(t, db, created.ID.Hex())
+
+	// body PUT dari klien mencoba mengecilkan size supaya lolos kuota
+	var req modelmongo.UpdateAchievementRequest
+	body := `{"title": "Kuota", "attachments": [{"id": "att-1", "fileName": "a.pdf", "fileUrl": "/uploads/hash-a.pdf", "size": 0}]}`
+	if err := json.Unmarshal([]byte(body), &req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := repo.UpdateAchievement(ctx, created.ID.Hex(), created.Version, req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	usage, err := repo.GetStudentAttachmentUsage(ctx, studentID)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if usage != 4096 {
+		t.Errorf("Expected usage from stored size 4096, got %d", usage)
+	}
+}
+
+func TestAchievementRepository_UpdateAchievement_KeepsAttachments(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestMongoDB(t)
+	defer db.Client().Disconnect(context.Background())
+
+	repo := repositorymongo.NewAchievementRepository(db)
+	ctx := context.Background()
+
+	created, err := repo.CreateAchievement(ctx, &modelmongo.Achievement{
+		StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+		AchievementType: "academic",
+		Title:           "Judul Awal",
+		Attachments: []modelmongo.Attachment{
+			{ID: "att-1", FileName: "a.pdf", FileURL: "/uploads/hash-a.pdf", HasPreview: true},
+			{ID: "att-2", FileName: "b.pdf", FileURL: "/uploads/hash-b.pdf"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test achievement: %v", err)
+	}
+	defer cleanupTestData(t, db, created.ID.Hex())
+
+	// body PUT dari klien mengosongkan attachments, file tidak boleh lepas dari prestasi tanpa lewat DeleteAttachment
+	var req modelmongo.UpdateAchievementRequest
+	if err := json.Unmarshal([]byte(`{"title": "Judul Baru", "attachments": []}`), &req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := repo.UpdateAchievement(ctx, created.ID.Hex(), created.Version, req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	current, _ := repo.GetAchievementByID(ctx, created.ID.Hex())
+	if len(current.Attachments) != 2 {
+		t.Errorf("Expected both attachments kept, got %+v", current.Attachments)
+	}
+}
+
 func TestAchievementRepository_DeleteAchievement_Success(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping integration test in short mode")
@@ -181,6 +362,53 @@ func TestAchievementRepository_DeleteAchievement_Success(t *testing.T) {
 	}
 }
 
+func TestAchievementRepository_GetAllAchievementStates_IncludesDeleted(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestMongoDB(t)
+	defer db.Client().Disconnect(context.Background())
+
+	repo := repositorymongo.NewAchievementRepository(db)
+	ctx := context.Background()
+
+	active, err := repo.CreateAchievement(ctx, &modelmongo.Achievement{StudentID: "550e8400-e29b-41d4-a716-446655440000", AchievementType: "academic", Title: "Aktif"})
+	if err != nil {
+		t.Fatalf("Failed to create test achievement: %v", err)
+	}
+	defer cleanupTestData(t, db, active.ID.Hex())
+
+	deleted, err := repo.CreateAchievement(ctx, &modelmongo.Achievement{StudentID: "550e8400-e29b-41d4-a716-446655440000", AchievementType: "academic", Title: "Dihapus"})
+	if err != nil {
+		t.Fatalf("Failed to create test achievement: %v", err)
+	}
+	defer cleanupTestData(t, db, deleted.ID.Hex())
+
+	if err := repo.DeleteAchievement(ctx, deleted.ID.Hex()); err != nil {
+		t.Fatalf("Failed to delete test achievement: %v", err)
+	}
+
+	states, err := repo.GetAllAchievementStates(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	found := map[primitive.ObjectID]bool{}
+	for _, state := range states {
+		switch state.ID {
+		case active.ID:
+			found[state.ID] = state.DeletedAt == nil
+		case deleted.ID:
+			found[state.ID] = state.DeletedAt != nil
+		}
+	}
+
+	if !found[active.ID] || !found[deleted.ID] {
+		t.Errorf("Expected active and deleted achievements with matching deletedAt, got %v", found)
+	}
+}
+
 func cleanupTestData(t *testing.T, db *mongo.Database, id string) {
 	ctx := context.Background()
 	objectID, err := primitive.ObjectIDFromHex(id)
@@ -194,3 +422,414 @@ func cleanupTestData(t *testing.T, db *mongo.Database, id string) {
 		t.Logf("Failed to cleanup test data: %v", err)
 	}
 }
+
+func TestAchievementRepository_FindAchievementsByDuplicateKeys_Success(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestMongoDB(t)
+	defer db.Client().Disconnect(context.Background())
+
+	repo := repositorymongo.NewAchievementRepository(db)
+	ctx := context.Background()
+
+	first, err := repo.CreateAchievement(ctx, &modelmongo.Achievement{
+		StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+		AchievementType: "certification",
+		Title:           "Sertifikat AWS",
+		DuplicateKeys:   []string{"certificationNumber:aws123"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create achievement: %v", err)
+	}
+	defer cleanupTestData(t, db, first.ID.Hex())
+
+	second, err := repo.CreateAchievement(ctx, &modelmongo.Achievement{
+		StudentID:       "660e8400-e29b-41d4-a716-446655440000",
+		AchievementType: "certification",
+		Title:           "AWS Cloud Practitioner",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create achievement: %v", err)
+	}
+	defer cleanupTestData(t, db, second.ID.Hex())
+
+	if err := repo.UpdateDuplicateKeys(ctx, second.ID.Hex(), []string{"certificationNumber:aws123"}); err != nil {
+		t.Fatalf("Failed to update duplicate keys: %v", err)
+	}
+
+	matches, err := repo.FindAchievementsByDuplicateKeys(ctx, second.ID.Hex(), []string{"certificationNumber:aws123"}, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(matches) != 1 || matches[0].ID != first.ID {
+		t.Errorf("Expected only the first achievement as match, got %+v", matches)
+	}
+
+	groups, err := repo.GetDuplicateKeyGroups(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	found := false
+	for _, group := range groups {
+		if group.Key == "certificationNumber:aws123" && group.Count >= 2 {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected duplicate group for certificationNumber:aws123, got %+v", groups)
+	}
+}
+
+func TestAchievementRepository_GetAchievementFacets_Success(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestMongoDB(t)
+	defer db.Client().Disconnect(context.Background())
+
+	repo := repositorymongo.NewAchievementRepository(db)
+	ctx := context.Background()
+
+	national := "national"
+	first, err := repo.CreateAchievement(ctx, &modelmongo.Achievement{
+		StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+		AchievementType: "competition",
+		Title:           "Juara 1 Robotik",
+		Details:         modelmongo.AchievementDetails{CompetitionLevel: &national},
+		Tags:            []string{"robotik"},
+		Points:          40,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create achievement: %v", err)
+	}
+	defer cleanupTestData(t, db, first.ID.Hex())
+
+	second, err := repo.CreateAchievement(ctx, &modelmongo.Achievement{
+		StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+		AchievementType: "academic",
+		Title:           "IPK Terbaik",
+		Points:          5,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create achievement: %v", err)
+	}
+	defer cleanupTestData(t, db, second.ID.Hex())
+
+	minPoints := 10
+	result, err := repo.GetAchievementFacets(ctx, modelmongo.AchievementFilter{
+		IDs:       []string{second.ID.Hex(), first.ID.Hex()},
+		MinPoints: &minPoints,
+		Now:       time.Now(),
+		SortField: "points",
+		Limit:     10,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Total != 1 || len(result.Achievements) != 1 || result.Achievements[0].ID != first.ID || result.Achievements[0].Points != 40 {
+		t.Fatalf("Expected only the competition achievement, got %+v", result)
+	}
+
+	if len(result.MatchedIDs) != 1 || result.MatchedIDs[0] != first.ID.Hex() {
+		t.Errorf("Expected matched IDs to contain the competition achievement, got %v", result.MatchedIDs)
+	}
+
+	levels := result.Facets[modelmongo.FacetCompetitionLevel]
+	if len(levels) != 1 || levels[0].Value != "national" || levels[0].Count != 1 {
+		t.Errorf("Expected competition level facet national=1, got %+v", levels)
+	}
+
+	types := result.Facets[modelmongo.FacetAchievementType]
+	if len(types) != 1 || types[0].Value != "competition" {
+		t.Errorf("Expected achievement type facet filtered by points, got %+v", types)
+	}
+
+	// facet points tidak memakai filter points sendiri sehingga rentang lain tetap terlihat
+	points := result.Facets[modelmongo.FacetPoints]
+	if len(points) != 2 {
+		t.Errorf("Expected points facet without own filter, got %+v", points)
+	}
+}
+
+func TestAchievementRepository_GetMatchingAchievementIDs_Success(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestMongoDB(t)
+	defer db.Client().Disconnect(context.Background())
+
+	repo := repositorymongo.NewAchievementRepository(db)
+	ctx := context.Background()
+
+	first, err := repo.CreateAchievement(ctx, &modelmongo.Achievement{
+		StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+		AchievementType: "competition",
+		Title:           "Juara 1 Robotik",
+		Tags:            []string{"robotik"},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create achievement: %v", err)
+	}
+	defer cleanupTestData(t, db, first.ID.Hex())
+
+	second, err := repo.CreateAchievement(ctx, &modelmongo.Achievement{
+		StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+		AchievementType: "academic",
+		Title:           "IPK Terbaik",
+	})
+	if err != nil {
+		t.Fatalf("Failed to create achievement: %v", err)
+	}
+	defer cleanupTestData(t, db, second.ID.Hex())
+
+	ids, err := repo.GetMatchingAchievementIDs(ctx, modelmongo.AchievementFilter{
+		IDs:  []string{first.ID.Hex(), second.ID.Hex(), "invalid-id"},
+		Tags: []string{"robotik"},
+		Now:  time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(ids) != 1 || ids[0] != first.ID.Hex() {
+		t.Errorf("Expected only the tagged achievement, got %v", ids)
+	}
+}
+
+func TestAchievementRepository_UpdateAttachmentFileURL_Success(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestMongoDB(t)
+	defer db.Client().Disconnect(context.Background())
+
+	repo := repositorymongo.NewAchievementRepository(db)
+	ctx := context.Background()
+
+	created, err := repo.CreateAchievement(ctx, &modelmongo.Achievement{
+		StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+		AchievementType: "academic",
+		Title:           "Migrasi Storage",
+		Attachments: []modelmongo.Attachment{
+			{FileName: "a.pdf", FileURL: "/uploads/1-a.pdf"},
+			{FileName: "b.pdf", FileURL: "https://files.example.ac.id/prestasi/2-b.pdf"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test achievement: %v", err)
+	}
+	defer cleanupTestData(t, db, created.ID.Hex())
+
+	achievements, err := repo.GetAchievementsByAttachmentURLPrefix(ctx, "/uploads/")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	found := false
+	for _, achievement := range achievements {
+		if achievement.ID == created.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Expected achievement with local attachment to be listed")
+	}
+
+	updated, err := repo.UpdateAttachmentFileURL(ctx, created.ID.Hex(), "/uploads/1-a.pdf", "https://files.example.ac.id/prestasi/1-a.pdf")
+	if err != nil || !updated {
+		t.Fatalf("Expected attachment URL updated, got %v, %v", updated, err)
+	}
+
+	updated, err = repo.UpdateAttachmentFileURL(ctx, created.ID.Hex(), "/uploads/1-a.pdf", "https://files.example.ac.id/prestasi/1-a.pdf")
+	if err != nil || updated {
+		t.Errorf("Expected second update to match nothing, got %v, %v", updated, err)
+	}
+
+	achievement, err := repo.GetAchievementByID(ctx, created.ID.Hex())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if achievement.Attachments[0].FileURL != "https://files.example.ac.id/prestasi/1-a.pdf" || achievement.Attachments[1].FileURL != "https://files.example.ac.id/prestasi/2-b.pdf" {
+		t.Errorf("Expected only first attachment URL to change, got %+v", achievement.Attachments)
+	}
+	if achievement.Version != created.Version {
+		t.Errorf("Expected version unchanged, got %d want %d", achievement.Version, created.Version)
+	}
+}
+
+func TestAchievementRepository_UpdateAttachmentScanResult_Success(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestMongoDB(t)
+	defer db.Client().Disconnect(context.Background())
+
+	repo := repositorymongo.NewAchievementRepository(db)
+	ctx := context.Background()
+
+	created, err := repo.CreateAchievement(ctx, &modelmongo.Achievement{
+		StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+		AchievementType: "academic",
+		Title:           "Scan Attachment",
+		Attachments: []modelmongo.Attachment{
+			{FileName: "a.pdf", FileURL: "/uploads/1-a.pdf", ScanStatus: modelmongo.AttachmentScanPending},
+			{FileName: "b.pdf", FileURL: "/uploads/2-b.pdf", ScanStatus: modelmongo.AttachmentScanPending},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test achievement: %v", err)
+	}
+	defer cleanupTestData(t, db, created.ID.Hex())
+
+	achievements, err := repo.GetAchievementsByAttachmentScanStatus(ctx, modelmongo.AttachmentScanPending, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	found := false
+	for _, achievement := range achievements {
+		if achievement.ID == created.ID {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Expected achievement with pending attachment to be listed")
+	}
+
+	updated, err := repo.UpdateAttachmentScanResult(ctx, created.ID.Hex(), "/uploads/2-b.pdf", modelmongo.AttachmentScanInfected, "Eicar-Test-Signature", "/quarantine/2-b.pdf")
+	if err != nil || !updated {
+		t.Fatalf("Expected scan result saved, got %v, %v", updated, err)
+	}
+
+	// attachment yang sudah punya hasil scan tidak ditimpa lagi
+	updated, err = repo.UpdateAttachmentScanResult(ctx, created.ID.Hex(), "/quarantine/2-b.pdf", modelmongo.AttachmentScanClean, "", "")
+	if err != nil || updated {
+		t.Errorf("Expected scanned attachment not updated again, got %v, %v", updated, err)
+	}
+
+	achievement, err := repo.GetAchievementByID(ctx, created.ID.Hex())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	first, second := achievement.Attachments[0], achievement.Attachments[1]
+	if first.ScanStatus != modelmongo.AttachmentScanPending || first.FileURL != "/uploads/1-a.pdf" {
+		t.Errorf("Expected first attachment untouched, got %+v", first)
+	}
+	if second.ScanStatus != modelmongo.AttachmentScanInfected || second.FileURL != "/quarantine/2-b.pdf" || second.ScanSignature != "Eicar-Test-Signature" || second.ScannedAt == nil {
+		t.Errorf("Expected second attachment quarantined, got %+v", second)
+	}
+}
+
+func TestAchievementRepository_RemoveAndReplaceAttachment_Success(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestMongoDB(t)
+	defer db.Client().Disconnect(context.Background())
+
+	repo := repositorymongo.NewAchievementRepository(db)
+	ctx := context.Background()
+
+	created, err := repo.CreateAchievement(ctx, &modelmongo.Achievement{
+		StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+		AchievementType: "academic",
+		Title:           "Ganti Attachment",
+		Attachments: []modelmongo.Attachment{
+			{ID: "att-1", FileName: "a.pdf", FileURL: "/uploads/1-a.pdf"},
+			{ID: "att-2", FileName: "b.pdf", FileURL: "/uploads/2-b.pdf"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test achievement: %v", err)
+	}
+	defer cleanupTestData(t, db, created.ID.Hex())
+
+	replaced, err := repo.ReplaceAttachment(ctx, created.ID.Hex(), "att-1", modelmongo.Attachment{FileName: "c.pdf", FileURL: "/uploads/3-c.pdf"})
+	if err != nil || replaced == nil {
+		t.Fatalf("Expected attachment replaced, got %v, %v", replaced, err)
+	}
+	if replaced.Attachments[0].ID != "att-1" || replaced.Attachments[0].FileURL != "/uploads/3-c.pdf" || replaced.Version != 2 {
+		t.Errorf("Unexpected replaced achievement %+v", replaced)
+	}
+
+	count, err := repo.CountAttachmentFileReferences(ctx, "/uploads/1-a.pdf")
+	if err != nil || count != 0 {
+		t.Errorf("Expected old file unreferenced, got %d, %v", count, err)
+	}
+
+	removed, err := repo.RemoveAttachment(ctx, created.ID.Hex(), "att-2")
+	if err != nil || removed == nil {
+		t.Fatalf("Expected attachment removed, got %v, %v", removed, err)
+	}
+	if len(removed.Attachments) != 1 || removed.Attachments[0].ID != "att-1" || removed.Version != 3 {
+		t.Errorf("Unexpected achievement after remove %+v", removed)
+	}
+
+	removed, err = repo.RemoveAttachment(ctx, created.ID.Hex(), "att-2")
+	if err != nil || removed != nil {
+		t.Errorf("Expected nil for missing attachment, got %v, %v", removed, err)
+	}
+}
+
+func TestAchievementRepository_AttachmentUsage_Success(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestMongoDB(t)
+	defer db.Client().Disconnect(context.Background())
+
+	repo := repositorymongo.NewAchievementRepository(db)
+	ctx := context.Background()
+	studentID := "550e8400-e29b-41d4-a716-446655440099"
+
+	created, err := repo.CreateAchievement(ctx, &modelmongo.Achievement{
+		StudentID:       studentID,
+		AchievementType: "academic",
+		Title:           "Kuota Attachment",
+		Attachments: []modelmongo.Attachment{
+			{ID: "att-1", FileName: "a.pdf", FileURL: "/uploads/abc.pdf", Size: 100},
+			{ID: "att-2", FileName: "salinan.pdf", FileURL: "/uploads/abc.pdf", Size: 100},
+			{ID: "att-3", FileName: "lama.pdf", FileURL: "/uploads/1-lama.pdf"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create test achievement: %v", err)
+	}
+	defer cleanupTestData(t, db, created.ID.Hex())
+
+	used, err := repo.GetStudentAttachmentUsage(ctx, studentID)
+	if err != nil || used != 200 {
+		t.Errorf("Expected 200 bytes used, got %d, %v", used, err)
+	}
+
+	usages, err := repo.GetAttachmentUsageByStudent(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	found := false
+	for _, usage := range usages {
+		if usage.StudentID == studentID {
+			found = true
+			if usage.Files != 3 || usage.Bytes != 200 {
+				t.Errorf("Unexpected usage %+v", usage)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("Expected usage for %s, got %+v", studentID, usages)
+	}
+
+	totals, err := repo.GetAttachmentStorageTotals(ctx)
+	if err != nil || totals.Attachments < 3 || totals.LogicalBytes-totals.StoredBytes < 100 {
+		t.Errorf("Expected shared file counted once in stored bytes, got %+v, %v", totals, err)
+	}
+}
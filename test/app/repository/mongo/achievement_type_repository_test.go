@@ -0,0 +1,89 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+)
+
+func cleanupAchievementType(t *testing.T, db *mongo.Database, code string) {
+	if _, err := db.Collection("achievement_types").DeleteMany(context.Background(), bson.M{"code": code}); err != nil {
+		t.Logf("Failed to cleanup achievement type %s: %v", code, err)
+	}
+}
+
+func TestAchievementTypeRepository_CreateAndUpdate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestMongoDB(t)
+	defer db.Client().Disconnect(context.Background())
+
+	repo := repositorymongo.NewAchievementTypeRepository(db)
+	ctx := context.Background()
+	defer cleanupAchievementType(t, db, "test_patent")
+
+	created, err := repo.CreateAchievementType(ctx, &modelmongo.AchievementTypeDefinition{
+		Code:     "test_patent",
+		Name:     "Paten",
+		IsActive: true,
+		CustomFields: []modelmongo.CustomFieldDefinition{
+			{Key: "patentNumber", Label: "Nomor Paten", Type: modelmongo.CustomFieldTypeText, Required: true},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if created == nil || len(created.CustomFields) != 1 {
+		t.Fatalf("Expected created type with 1 custom field, got %+v", created)
+	}
+
+	inactive := false
+	updated, err := repo.UpdateAchievementType(ctx, "test_patent", modelmongo.UpdateAchievementTypeRequest{IsActive: &inactive})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if updated == nil || updated.IsActive {
+		t.Errorf("Expected inactive type after update, got %+v", updated)
+	}
+
+	activeTypes, err := repo.GetAllAchievementTypes(ctx, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, achievementType := range activeTypes {
+		if achievementType.Code == "test_patent" {
+			t.Error("Expected inactive type to be excluded from active list")
+		}
+	}
+}
+
+func TestAchievementTypeRepository_GetAchievementTypeByCode_NotFound(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	db := setupTestMongoDB(t)
+	defer db.Client().Disconnect(context.Background())
+
+	repo := repositorymongo.NewAchievementTypeRepository(db)
+
+	result, err := repo.GetAchievementTypeByCode(context.Background(), "does_not_exist")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result != nil {
+		t.Errorf("Expected nil, got %+v", result)
+	}
+}
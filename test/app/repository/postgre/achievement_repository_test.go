@@ -10,6 +10,7 @@ import (
 	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
 )
 
 func TestAchievementReferenceRepository_CreateAchievementReference_Success(t *testing.T) {
@@ -29,13 +30,13 @@ func TestAchievementReferenceRepository_CreateAchievementReference_Success(t *te
 	expectedCreatedAt := time.Now()
 	expectedUpdatedAt := time.Now()
 
-	rows := sqlmock.NewRows([]string{"id", "student_id", "mongo_achievement_id", "status", "submitted_at", "verified_at", "verified_by", "rejection_note", "created_at", "updated_at"}).
-		AddRow(expectedID, req.StudentID, req.MongoAchievementID, req.Status, nil, nil, nil, nil, expectedCreatedAt, expectedUpdatedAt)
+	rows := sqlmock.NewRows([]string{"id", "student_id", "mongo_achievement_id", "status", "submitted_at", "verified_at", "verified_by", "rejection_note", "version", "created_at", "updated_at"}).
+		AddRow(expectedID, req.StudentID, req.MongoAchievementID, req.Status, nil, nil, nil, nil, 1, expectedCreatedAt, expectedUpdatedAt)
 
 	mock.ExpectQuery(`INSERT INTO achievement_references \(student_id, mongo_achievement_id, status, created_at, updated_at\)
 		VALUES \(\$1, \$2, \$3, NOW\(\), NOW\(\)\)
 		RETURNING id, student_id, mongo_achievement_id, status, submitted_at, 
-		          verified_at, verified_by, rejection_note, created_at, updated_at`).
+		          verified_at, verified_by, rejection_note, version, created_at, updated_at`).
 		WithArgs(req.StudentID, req.MongoAchievementID, req.Status).
 		WillReturnRows(rows)
 
@@ -79,13 +80,13 @@ func TestAchievementReferenceRepository_GetAchievementReferenceByMongoID_Success
 		UpdatedAt:          time.Now(),
 	}
 
-	rows := sqlmock.NewRows([]string{"id", "student_id", "mongo_achievement_id", "status", "submitted_at", "verified_at", "verified_by", "rejection_note", "created_at", "updated_at"}).
+	rows := sqlmock.NewRows([]string{"id", "student_id", "mongo_achievement_id", "status", "submitted_at", "verified_at", "verified_by", "rejection_note", "version", "created_at", "updated_at"}).
 		AddRow(expectedRef.ID, expectedRef.StudentID, expectedRef.MongoAchievementID, expectedRef.Status,
 			expectedRef.SubmittedAt, expectedRef.VerifiedAt, expectedRef.VerifiedBy, expectedRef.RejectionNote,
-			expectedRef.CreatedAt, expectedRef.UpdatedAt)
+			3, expectedRef.CreatedAt, expectedRef.UpdatedAt)
 
 	mock.ExpectQuery(`SELECT id, student_id, mongo_achievement_id, status, submitted_at,
-		       verified_at, verified_by, rejection_note, created_at, updated_at
+		       verified_at, verified_by, rejection_note, version, created_at, updated_at
 		FROM achievement_references
 		WHERE mongo_achievement_id = \$1 AND status != 'deleted'`).
 		WithArgs(mongoID).
@@ -105,6 +106,10 @@ func TestAchievementReferenceRepository_GetAchievementReferenceByMongoID_Success
 		t.Errorf("Expected MongoAchievementID %s, got %s", mongoID, ref.MongoAchievementID)
 	}
 
+	if ref.Version != 3 {
+		t.Errorf("Expected Version 3, got %d", ref.Version)
+	}
+
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Unfulfilled expectations: %v", err)
 	}
@@ -120,7 +125,7 @@ func TestAchievementReferenceRepository_GetAchievementReferenceByMongoID_NotFoun
 	mongoID := "507f1f77bcf86cd799439011"
 
 	mock.ExpectQuery(`SELECT id, student_id, mongo_achievement_id, status, submitted_at,
-		       verified_at, verified_by, rejection_note, created_at, updated_at
+		       verified_at, verified_by, rejection_note, version, created_at, updated_at
 		FROM achievement_references
 		WHERE mongo_achievement_id = \$1 AND status != 'deleted'`).
 		WithArgs(mongoID).
@@ -157,7 +162,7 @@ func TestAchievementReferenceRepository_UpdateAchievementReferenceStatus_Success
 	submittedAt := time.Now()
 
 	mock.ExpectExec(`UPDATE achievement_references
-		SET status = \$1, submitted_at = \$2, updated_at = NOW\(\)
+		SET status = \$1, submitted_at = \$2, version = version \+ 1, updated_at = NOW\(\)
 		WHERE id = \$3`).
 		WithArgs(status, submittedAt, refID).
 		WillReturnResult(sqlmock.NewResult(0, 1))
@@ -232,3 +237,608 @@ func TestAchievementReferenceRepository_GetAchievementReferenceByStudentID_Succe
 		t.Errorf("Unfulfilled expectations: %v", err)
 	}
 }
+
+func TestAchievementReferenceRepository_GetAchievementHistoryByRefID_Success(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewAchievementReferenceRepository(db)
+	ctx := context.Background()
+
+	refID := "550e8400-e29b-41d4-a716-446655440001"
+	userID := "550e8400-e29b-41d4-a716-446655440002"
+
+	rows := sqlmock.NewRows([]string{"id", "achievement_ref_id", "action", "status", "changed_by", "note", "created_at"}).
+		AddRow("history-id-1", refID, modelpostgre.AchievementHistoryActionSubmitted, modelpostgre.AchievementStatusSubmitted, userID, nil, time.Now()).
+		AddRow("history-id-2", refID, modelpostgre.AchievementHistoryActionWithdrawn, modelpostgre.AchievementStatusDraft, userID, nil, time.Now())
+
+	mock.ExpectQuery(`SELECT id, achievement_ref_id, action, status, changed_by, note, created_at
+		FROM achievement_history
+		WHERE achievement_ref_id = \$1
+		ORDER BY created_at ASC`).
+		WithArgs(refID).
+		WillReturnRows(rows)
+
+	history, err := repo.GetAchievementHistoryByRefID(ctx, refID)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(history) != 2 || history[1].Action != modelpostgre.AchievementHistoryActionWithdrawn {
+		t.Errorf("Expected submitted and withdrawn entries, got %+v", history)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestAchievementReferenceRepository_WithdrawAchievementReference(t *testing.T) {
+	testCases := []struct {
+		name         string
+		rowsAffected int64
+		want         bool
+	}{
+		{name: "review belum dimulai", rowsAffected: 1, want: true},
+		{name: "review sudah dimulai", rowsAffected: 0, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			defer db.Close()
+
+			repo := repositorypostgre.NewAchievementReferenceRepository(db)
+			ctx := context.Background()
+
+			refID := "550e8400-e29b-41d4-a716-446655440001"
+
+			mock.ExpectExec(`UPDATE achievement_references ar
+		SET status = \$1, version = version \+ 1, updated_at = NOW\(\)
+		WHERE ar.id = \$2 AND ar.status = \$3`).
+				WithArgs(modelpostgre.AchievementStatusDraft, refID, modelpostgre.AchievementStatusSubmitted, modelpostgre.AchievementHistoryActionReviewStarted).
+				WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+
+			withdrawn, err := repo.WithdrawAchievementReference(ctx, refID)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if withdrawn != tc.want {
+				t.Errorf("Expected withdrawn %v, got %v", tc.want, withdrawn)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAchievementReferenceRepository_UpdateAchievementReferenceVerify_VersionCheck(t *testing.T) {
+	testCases := []struct {
+		name         string
+		rowsAffected int64
+		want         bool
+	}{
+		{name: "versi masih sama", rowsAffected: 1, want: true},
+		{name: "versi sudah berubah", rowsAffected: 0, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			defer db.Close()
+
+			repo := repositorypostgre.NewAchievementReferenceRepository(db)
+			ctx := context.Background()
+
+			refID := "550e8400-e29b-41d4-a716-446655440001"
+			verifiedBy := "550e8400-e29b-41d4-a716-446655440002"
+
+			mock.ExpectExec(`UPDATE achievement_references
+		SET status = \$1, verified_by = \$2, verified_at = NOW\(\), version = version \+ 1, updated_at = NOW\(\)
+		WHERE id = \$3 AND version = \$4`).
+				WithArgs(modelpostgre.AchievementStatusVerified, verifiedBy, refID, 2).
+				WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+
+			verified, err := repo.UpdateAchievementReferenceVerify(ctx, refID, verifiedBy, 2)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if verified != tc.want {
+				t.Errorf("Expected verified %v, got %v", tc.want, verified)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAchievementReferenceRepository_GetSubmittedReferencesBefore_Success(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewAchievementReferenceRepository(db)
+	ctx := context.Background()
+
+	before := time.Now().Add(-72 * time.Hour)
+	studentID := "550e8400-e29b-41d4-a716-446655440000"
+
+	rows := sqlmock.NewRows([]string{"id", "student_id", "mongo_achievement_id", "status", "submitted_at", "verified_at", "verified_by", "rejection_note", "created_at", "updated_at"}).
+		AddRow("ref-id-1", studentID, "mongo-id-1", modelpostgre.AchievementStatusSubmitted, timePtr(before.Add(-24*time.Hour)), nil, nil, nil, time.Now(), time.Now())
+
+	mock.ExpectQuery(`SELECT id, student_id, mongo_achievement_id, status, submitted_at,
+		       verified_at, verified_by, rejection_note, created_at, updated_at
+		FROM achievement_references
+		WHERE status = \$1 AND submitted_at IS NOT NULL AND submitted_at <= \$2
+		ORDER BY submitted_at ASC`).
+		WithArgs(modelpostgre.AchievementStatusSubmitted, before).
+		WillReturnRows(rows)
+
+	refs, err := repo.GetSubmittedReferencesBefore(ctx, before)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(refs) != 1 || refs[0].ID != "ref-id-1" {
+		t.Errorf("Expected ref-id-1, got %+v", refs)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestAchievementReferenceRepository_SoftDeleteAchievementReference(t *testing.T) {
+	testCases := []struct {
+		name         string
+		rowsAffected int64
+		wantErr      error
+	}{
+		{name: "reference aktif", rowsAffected: 1, wantErr: nil},
+		{name: "reference sudah dihapus", rowsAffected: 0, wantErr: sql.ErrNoRows},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			defer db.Close()
+
+			repo := repositorypostgre.NewAchievementReferenceRepository(db)
+			ctx := context.Background()
+
+			refID := "550e8400-e29b-41d4-a716-446655440001"
+
+			mock.ExpectExec(`UPDATE achievement_references
+		SET status_before_delete = status, status = 'deleted', deleted_at = NOW\(\), version = version \+ 1, updated_at = NOW\(\)
+		WHERE id = \$1 AND status != 'deleted'`).
+				WithArgs(refID).
+				WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+
+			err := repo.SoftDeleteAchievementReference(ctx, refID)
+
+			if err != tc.wantErr {
+				t.Errorf("Expected error %v, got %v", tc.wantErr, err)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAchievementReferenceRepository_RestoreAchievementReference_Success(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewAchievementReferenceRepository(db)
+	ctx := context.Background()
+
+	refID := "550e8400-e29b-41d4-a716-446655440001"
+
+	rows := sqlmock.NewRows([]string{"status"}).
+		AddRow(modelpostgre.AchievementStatusVerified)
+
+	mock.ExpectQuery(`UPDATE achievement_references
+		SET status = COALESCE\(status_before_delete, 'draft'\), status_before_delete = NULL,
+		    deleted_at = NULL, version = version \+ 1, updated_at = NOW\(\)
+		WHERE id = \$1 AND status = 'deleted'
+		RETURNING status`).
+		WithArgs(refID).
+		WillReturnRows(rows)
+
+	status, err := repo.RestoreAchievementReference(ctx, refID)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if status != modelpostgre.AchievementStatusVerified {
+		t.Errorf("Expected status verified, got %s", status)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestAchievementReferenceRepository_GetDeletedReferencesBefore_Success(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewAchievementReferenceRepository(db)
+	ctx := context.Background()
+
+	before := time.Now().AddDate(0, 0, -30)
+	deletedAt := before.Add(-24 * time.Hour)
+	studentID := "550e8400-e29b-41d4-a716-446655440000"
+
+	rows := sqlmock.NewRows([]string{"id", "student_id", "mongo_achievement_id", "status", "submitted_at", "verified_at", "verified_by", "rejection_note", "created_at", "updated_at", "deleted_at", "status_before_delete"}).
+		AddRow("ref-id-1", studentID, "mongo-id-1", modelpostgre.AchievementStatusDeleted, nil, nil, nil, nil, time.Now(), time.Now(), deletedAt, modelpostgre.AchievementStatusDraft)
+
+	mock.ExpectQuery(`FROM achievement_references
+		WHERE status = 'deleted' AND COALESCE\(deleted_at, updated_at\) <= \$1
+		ORDER BY COALESCE\(deleted_at, updated_at\) ASC`).
+		WithArgs(before).
+		WillReturnRows(rows)
+
+	refs, err := repo.GetDeletedReferencesBefore(ctx, before)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(refs) != 1 || refs[0].ID != "ref-id-1" || !refs[0].DeletedAt.Equal(deletedAt) || refs[0].StatusBeforeDelete != modelpostgre.AchievementStatusDraft {
+		t.Errorf("Expected deleted ref-id-1, got %+v", refs)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestAchievementReferenceRepository_PurgeAchievementReference(t *testing.T) {
+	testCases := []struct {
+		name         string
+		rowsAffected int64
+		want         bool
+	}{
+		{name: "masih di tempat sampah", rowsAffected: 1, want: true},
+		{name: "sudah di-restore", rowsAffected: 0, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			defer db.Close()
+
+			repo := repositorypostgre.NewAchievementReferenceRepository(db)
+			ctx := context.Background()
+
+			refID := "550e8400-e29b-41d4-a716-446655440001"
+
+			mock.ExpectExec(`DELETE FROM achievement_references WHERE id = \$1 AND status = 'deleted'`).
+				WithArgs(refID).
+				WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+
+			purged, err := repo.PurgeAchievementReference(ctx, refID)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if purged != tc.want {
+				t.Errorf("Expected purged %v, got %v", tc.want, purged)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAchievementReferenceRepository_ClaimCertificationExpiryAlert(t *testing.T) {
+	testCases := []struct {
+		name         string
+		rowsAffected int64
+		want         bool
+	}{
+		{name: "peringatan baru", rowsAffected: 1, want: true},
+		{name: "peringatan sudah dikirim", rowsAffected: 0, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			defer db.Close()
+
+			repo := repositorypostgre.NewAchievementReferenceRepository(db)
+			ctx := context.Background()
+
+			refID := "550e8400-e29b-41d4-a716-446655440001"
+			validUntil := time.Date(2026, 11, 20, 0, 0, 0, 0, time.UTC)
+
+			mock.ExpectExec(`INSERT INTO certification_expiry_alerts \(achievement_ref_id, valid_until, days_before\)
+		VALUES \(\$1, \$2, \$3\)
+		ON CONFLICT \(achievement_ref_id, valid_until, days_before\) DO NOTHING`).
+				WithArgs(refID, validUntil, 7).
+				WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+
+			claimed, err := repo.ClaimCertificationExpiryAlert(ctx, refID, validUntil, 7)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if claimed != tc.want {
+				t.Errorf("Expected claimed %v, got %v", tc.want, claimed)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAchievementReferenceRepository_ClaimSLANotification(t *testing.T) {
+	testCases := []struct {
+		name         string
+		rowsAffected int64
+		want         bool
+	}{
+		{name: "notifikasi baru", rowsAffected: 1, want: true},
+		{name: "notifikasi sudah diklaim", rowsAffected: 0, want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := setupTestDB(t)
+			defer db.Close()
+
+			repo := repositorypostgre.NewAchievementReferenceRepository(db)
+			ctx := context.Background()
+
+			refID := "550e8400-e29b-41d4-a716-446655440001"
+			submittedAt := time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC)
+
+			mock.ExpectExec(`INSERT INTO sla_notification_claims \(achievement_ref_id, submitted_at, action\)
+		VALUES \(\$1, \$2, \$3\)
+		ON CONFLICT \(achievement_ref_id, submitted_at, action\) DO NOTHING`).
+				WithArgs(refID, submittedAt, "sla_reminder").
+				WillReturnResult(sqlmock.NewResult(0, tc.rowsAffected))
+
+			claimed, err := repo.ClaimSLANotification(ctx, refID, submittedAt, "sla_reminder")
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if claimed != tc.want {
+				t.Errorf("Expected claimed %v, got %v", tc.want, claimed)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestAchievementReferenceRepository_GetAchievementListRows_Filters(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewAchievementReferenceRepository(db)
+	ctx := context.Background()
+
+	now := time.Now()
+	rows := sqlmock.NewRows([]string{
+		"id", "student_id", "mongo_achievement_id", "status", "submitted_at",
+		"verified_at", "verified_by", "rejection_note", "created_at", "updated_at",
+		"student_name", "program_study", "academic_year", "advisor_id", "advisor_name",
+	}).AddRow(
+		"ref-id-1", "student-id-1", "507f1f77bcf86cd799439011", "verified", now,
+		now, nil, nil, now, now,
+		"Budi", "Teknik Informatika", "2022", "lecturer-id-1", "Dr. Sari",
+	)
+
+	mock.ExpectQuery(`WHERE ar.status != 'deleted' AND s.advisor_id = \$1 AND ar.status::text = ANY\(\$2\) AND s.program_study = ANY\(\$3\)\s+ORDER BY ar.submitted_at ASC NULLS LAST, ar.id`).
+		WithArgs("lecturer-id-1", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(rows)
+
+	result, err := repo.GetAchievementListRows(ctx, modelpostgre.AchievementListFilter{
+		ScopeAdvisorID: "lecturer-id-1",
+		Statuses:       []string{"submitted", "verified"},
+		ProgramStudies: []string{"Teknik Informatika"},
+		SortBy:         "submitted_at",
+		SortOrder:      "ASC",
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result) != 1 || result[0].ProgramStudy != "Teknik Informatika" || result[0].AdvisorName != "Dr. Sari" {
+		t.Errorf("Expected row with student and advisor data, got %+v", result)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestAchievementReferenceRepository_GetAchievementListRows_InvalidSortFallsBack(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewAchievementReferenceRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectQuery(`WHERE ar.status != 'deleted'\s+ORDER BY ar.created_at DESC, ar.mongo_achievement_id DESC$`).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	if _, err := repo.GetAchievementListRows(ctx, modelpostgre.AchievementListFilter{SortBy: "points; DROP TABLE users"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestAchievementReferenceRepository_GetAchievementListRows_CursorKeysetAndLimit(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewAchievementReferenceRepository(db)
+	ctx := context.Background()
+
+	cursor := &modelpostgre.PageCursor{CreatedAt: time.Now(), ID: "507f1f77bcf86cd799439011"}
+	mock.ExpectQuery(`WHERE ar.status != 'deleted' AND ar.status::text = ANY\(\$1\) AND \(ar.created_at, ar.mongo_achievement_id\) < \(\$2, \$3\)\s+ORDER BY ar.created_at DESC, ar.mongo_achievement_id DESC LIMIT \$4`).
+		WithArgs(sqlmock.AnyArg(), cursor.CreatedAt, cursor.ID, 11).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	if _, err := repo.GetAchievementListRows(ctx, modelpostgre.AchievementListFilter{
+		Statuses:   []string{"verified"},
+		CursorMode: true,
+		Cursor:     cursor,
+		RowLimit:   11,
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestAchievementReferenceRepository_GetAchievementListRows_OffsetPage(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewAchievementReferenceRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectQuery(`ORDER BY ar.updated_at DESC NULLS LAST, ar.id LIMIT \$1 OFFSET \$2`).
+		WithArgs(10, 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	if _, err := repo.GetAchievementListRows(ctx, modelpostgre.AchievementListFilter{
+		SortBy:    "updated_at",
+		RowLimit:  10,
+		RowOffset: 20,
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestAchievementReferenceRepository_GetAchievementFacetRows_AllowsOneFailedDimension(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewAchievementReferenceRepository(db)
+	ctx := context.Background()
+
+	// filter dimensi facet tidak jadi syarat langsung, hanya baris yang gagal lebih dari satu dimensi yang dibuang
+	after := &modelpostgre.PageCursor{CreatedAt: time.Now(), ID: "507f1f77bcf86cd799439011"}
+	mock.ExpectQuery(`WHERE ar.status != 'deleted' AND ar.student_id = \$1 AND \(CASE WHEN ar.status::text = ANY\(\$2\) THEN 0 ELSE 1 END \+ CASE WHEN s.program_study = ANY\(\$3\) THEN 0 ELSE 1 END\) <= 1 AND \(ar.created_at, ar.mongo_achievement_id\) > \(\$4, \$5\)\s+ORDER BY ar.created_at ASC, ar.mongo_achievement_id ASC LIMIT \$6$`).
+		WithArgs("student-id-1", sqlmock.AnyArg(), sqlmock.AnyArg(), after.CreatedAt, after.ID, 1000).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	if _, err := repo.GetAchievementFacetRows(ctx, modelpostgre.AchievementListFilter{
+		ScopeStudentID: "student-id-1",
+		Statuses:       []string{"verified"},
+		ProgramStudies: []string{"Teknik Informatika"},
+		SortBy:         "updated_at",
+	}, after, 1000); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestAchievementReferenceRepository_GetDeletedAchievementMongoIDs_Success(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewAchievementReferenceRepository(db)
+	ctx := context.Background()
+
+	rows := sqlmock.NewRows([]string{"mongo_achievement_id"}).
+		AddRow("mongo-id-1").
+		AddRow("mongo-id-2")
+
+	mock.ExpectQuery(`SELECT mongo_achievement_id
+		FROM achievement_references
+		WHERE status = 'deleted'`).
+		WillReturnRows(rows)
+
+	ids, err := repo.GetDeletedAchievementMongoIDs(ctx)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(ids) != 2 || ids[0] != "mongo-id-1" || ids[1] != "mongo-id-2" {
+		t.Errorf("Expected deleted mongo IDs, got %v", ids)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestAchievementReferenceRepository_GetAchievementReferencesByMongoIDs(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewAchievementReferenceRepository(db)
+	ctx := context.Background()
+
+	mongoIDs := []string{"507f1f77bcf86cd799439011", "507f1f77bcf86cd799439012"}
+	now := time.Now()
+
+	rows := sqlmock.NewRows([]string{"id", "student_id", "mongo_achievement_id", "status", "submitted_at", "verified_at", "verified_by", "rejection_note", "version", "created_at", "updated_at"}).
+		AddRow("550e8400-e29b-41d4-a716-446655440001", "550e8400-e29b-41d4-a716-446655440000", mongoIDs[0], modelpostgre.AchievementStatusVerified,
+			nil, nil, nil, nil, 2, now, now).
+		AddRow("550e8400-e29b-41d4-a716-446655440002", "550e8400-e29b-41d4-a716-446655440000", mongoIDs[1], modelpostgre.AchievementStatusDeleted,
+			nil, nil, nil, nil, 4, now, now)
+
+	mock.ExpectQuery(`SELECT id, student_id, mongo_achievement_id, status, submitted_at,
+		       verified_at, verified_by, rejection_note, version, created_at, updated_at
+		FROM achievement_references
+		WHERE mongo_achievement_id = ANY\(\$1\)`).
+		WithArgs(pq.Array(mongoIDs)).
+		WillReturnRows(rows)
+
+	refs, err := repo.GetAchievementReferencesByMongoIDs(ctx, mongoIDs)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(refs) != 2 || refs[1].Status != modelpostgre.AchievementStatusDeleted {
+		t.Errorf("Expected 2 references including deleted, got %+v", refs)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
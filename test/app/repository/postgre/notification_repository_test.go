@@ -92,7 +92,7 @@ func TestNotificationRepository_GetNotificationsByUserIDPaginated_Success(t *tes
 	mock.ExpectQuery(`SELECT id, user_id, type, title, message, achievement_id, mongo_achievement_id, is_read, read_at, created_at, updated_at
 		FROM notifications
 		WHERE user_id = \$1
-		ORDER BY created_at DESC
+		ORDER BY created_at DESC, id DESC
 		LIMIT \$2 OFFSET \$3`).
 		WithArgs(userID, limit, 0).
 		WillReturnRows(dataRows)
@@ -202,3 +202,70 @@ func TestNotificationRepository_MarkAllAsRead_Success(t *testing.T) {
 		t.Errorf("Unfulfilled expectations: %v", err)
 	}
 }
+
+func TestNotificationRepository_DeleteNotificationsByAchievement_Success(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewNotificationRepository(db)
+	ctx := context.Background()
+
+	refID := "550e8400-e29b-41d4-a716-446655440001"
+
+	mock.ExpectExec(`DELETE FROM notifications
+		WHERE achievement_id = \$1 AND type = \$2`).
+		WithArgs(refID, modelpostgre.NotificationTypeAchievementSubmitted).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	deleted, err := repo.DeleteNotificationsByAchievement(ctx, refID, modelpostgre.NotificationTypeAchievementSubmitted)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if deleted != 2 {
+		t.Errorf("Expected 2 deleted notifications, got %d", deleted)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestNotificationRepository_GetNotificationsByUserIDAfterCursor(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewNotificationRepository(db)
+	ctx := context.Background()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	cursor := &modelpostgre.PageCursor{
+		CreatedAt: time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC),
+		ID:        "660e8400-e29b-41d4-a716-446655440000",
+	}
+
+	dataRows := sqlmock.NewRows([]string{"id", "user_id", "type", "title", "message", "achievement_id", "mongo_achievement_id", "is_read", "read_at", "created_at", "updated_at"}).
+		AddRow("notif-id-3", userID, "achievement_submitted", "Title 3", "Message 3", nil, nil, false, nil, time.Now(), time.Now())
+
+	// keyset tanpa COUNT dan OFFSET
+	mock.ExpectQuery(`WHERE user_id = \$1 AND \(created_at, id\) < \(\$2, \$3\)
+			ORDER BY created_at DESC, id DESC
+			LIMIT \$4`).
+		WithArgs(userID, cursor.CreatedAt, cursor.ID, 11).
+		WillReturnRows(dataRows)
+
+	notifs, err := repo.GetNotificationsByUserIDAfterCursor(ctx, userID, cursor, 11)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(notifs) != 1 || notifs[0].ID != "notif-id-3" {
+		t.Errorf("Expected notif-id-3, got %+v", notifs)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
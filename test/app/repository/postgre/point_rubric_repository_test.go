@@ -0,0 +1,178 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorypostgre "sistem-pelaporan-prestasi-mahasiswa/app/repository/postgre"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var pointRubricColumns = []string{"id", "version", "name", "description", "rules", "is_active", "created_by", "activated_at", "created_at"}
+
+func TestPointRubricRepository_GetActivePointRubric_Success(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewPointRubricRepository(db)
+	ctx := context.Background()
+
+	rules := `[{"achievement_type":"competition","points":10},{"achievement_type":"competition","field":"competitionLevel","value":"national","points":50}]`
+	rows := sqlmock.NewRows(pointRubricColumns).
+		AddRow("rubric-id-1", 1, "Rubrik Poin Default", nil, []byte(rules), true, nil, time.Now(), time.Now())
+
+	mock.ExpectQuery(`SELECT id, version, name, description, rules, is_active, created_by, activated_at, created_at
+		FROM point_rubrics
+		WHERE is_active = true
+		LIMIT 1`).
+		WillReturnRows(rows)
+
+	rubric, err := repo.GetActivePointRubric(ctx)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if rubric.Version != 1 {
+		t.Errorf("Expected Version 1, got %d", rubric.Version)
+	}
+
+	if len(rubric.Rules) != 2 {
+		t.Fatalf("Expected 2 rules, got %d", len(rubric.Rules))
+	}
+
+	if rubric.Rules[1].Field != modelpostgre.PointRuleFieldCompetitionLevel || rubric.Rules[1].Points != 50 {
+		t.Errorf("Expected competitionLevel rule with 50 points, got %+v", rubric.Rules[1])
+	}
+
+	if rubric.Description != nil {
+		t.Errorf("Expected nil Description, got %v", *rubric.Description)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPointRubricRepository_GetActivePointRubric_NotFound(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewPointRubricRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectQuery(`SELECT id, version, name, description, rules, is_active, created_by, activated_at, created_at
+		FROM point_rubrics
+		WHERE is_active = true`).
+		WillReturnError(sql.ErrNoRows)
+
+	rubric, err := repo.GetActivePointRubric(ctx)
+
+	if err != sql.ErrNoRows {
+		t.Errorf("Expected sql.ErrNoRows, got %v", err)
+	}
+
+	if rubric != nil {
+		t.Errorf("Expected nil rubric, got %v", rubric)
+	}
+}
+
+func TestPointRubricRepository_CreatePointRubric_Success(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewPointRubricRepository(db)
+	ctx := context.Background()
+
+	req := modelpostgre.CreatePointRubricRequest{
+		Name:  "Rubrik 2025",
+		Rules: []modelpostgre.PointRule{{AchievementType: "academic", Points: 25}},
+	}
+	createdBy := "550e8400-e29b-41d4-a716-446655440000"
+
+	rows := sqlmock.NewRows(pointRubricColumns).
+		AddRow("rubric-id-2", 2, req.Name, nil, []byte(`[{"achievement_type":"academic","points":25}]`), false, createdBy, nil, time.Now())
+
+	mock.ExpectQuery(`INSERT INTO point_rubrics \(version, name, description, rules, is_active, created_by, created_at\)
+		VALUES \(\(SELECT COALESCE\(MAX\(version\), 0\) \+ 1 FROM point_rubrics\), \$1, \$2, \$3, false, \$4, NOW\(\)\)`).
+		WithArgs(req.Name, nil, []byte(`[{"achievement_type":"academic","points":25}]`), createdBy).
+		WillReturnRows(rows)
+
+	rubric, err := repo.CreatePointRubric(ctx, req, createdBy)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if rubric.Version != 2 {
+		t.Errorf("Expected Version 2, got %d", rubric.Version)
+	}
+
+	if rubric.IsActive {
+		t.Error("Expected new rubric to be inactive")
+	}
+
+	if rubric.CreatedBy == nil || *rubric.CreatedBy != createdBy {
+		t.Errorf("Expected CreatedBy %s, got %v", createdBy, rubric.CreatedBy)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPointRubricRepository_ActivatePointRubric_Success(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewPointRubricRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE point_rubrics SET is_active = false WHERE is_active = true`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE point_rubrics SET is_active = true, activated_at = NOW\(\) WHERE version = \$1`).
+		WithArgs(2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := repo.ActivatePointRubric(ctx, 2)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPointRubricRepository_ActivatePointRubric_NotFound(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewPointRubricRepository(db)
+	ctx := context.Background()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`UPDATE point_rubrics SET is_active = false WHERE is_active = true`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE point_rubrics SET is_active = true, activated_at = NOW\(\) WHERE version = \$1`).
+		WithArgs(99).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err := repo.ActivatePointRubric(ctx, 99)
+
+	if err != sql.ErrNoRows {
+		t.Errorf("Expected sql.ErrNoRows, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
@@ -676,3 +676,37 @@ func TestUserRepository_GetLecturerByID_Success(t *testing.T) {
 		t.Errorf("Unfulfilled expectations: %v", err)
 	}
 }
+
+func TestUserRepository_GetActiveUserIDsByRoleName_Success(t *testing.T) {
+	db, mock := setupTestDB(t)
+	defer db.Close()
+
+	repo := repositorypostgre.NewUserRepository(db)
+	ctx := context.Background()
+
+	rows := sqlmock.NewRows([]string{"id"}).
+		AddRow("admin-user-id-1").
+		AddRow("admin-user-id-2")
+
+	mock.ExpectQuery(`SELECT u.id
+		FROM users u
+		JOIN roles r ON u.role_id = r.id
+		WHERE r.name = \$1 AND u.is_active = true
+		ORDER BY u.created_at ASC`).
+		WithArgs("Admin").
+		WillReturnRows(rows)
+
+	ids, err := repo.GetActiveUserIDsByRoleName(ctx, "Admin")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(ids) != 2 || ids[0] != "admin-user-id-1" {
+		t.Errorf("Expected 2 admin IDs, got %v", ids)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
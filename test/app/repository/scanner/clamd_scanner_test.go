@@ -0,0 +1,184 @@
+package repository_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	repositoryscanner "sistem-pelaporan-prestasi-mahasiswa/app/repository/scanner"
+)
+
+// string uji EICAR dipecah supaya file test ini sendiri tidak ditandai antivirus
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$` + "EICAR-STANDARD-" + `ANTIVIRUS-TEST-FILE!$H+H*`
+
+// fakeClamd menjalankan protokol INSTREAM clamd secara minimal: file yang berisi string EICAR dianggap terinfeksi
+type fakeClamd struct {
+	listener  net.Listener
+	maxStream int
+	received  chan []byte
+}
+
+func startFakeClamd(t *testing.T, network string, address string, maxStream int) *fakeClamd {
+	t.Helper()
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	daemon := &fakeClamd{listener: listener, maxStream: maxStream, received: make(chan []byte, 10)}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go daemon.handle(conn)
+		}
+	}()
+	return daemon
+}
+
+func (d *fakeClamd) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	command, err := reader.ReadString(0)
+	if err != nil || command != "zINSTREAM\x00" {
+		conn.Write([]byte("UNKNOWN COMMAND\x00"))
+		return
+	}
+
+	var data bytes.Buffer
+	for {
+		var size uint32
+		if err := binary.Read(reader, binary.BigEndian, &size); err != nil {
+			return
+		}
+		if size == 0 {
+			break
+		}
+		if data.Len()+int(size) > d.maxStream {
+			conn.Write([]byte("INSTREAM size limit exceeded. ERROR\x00"))
+			return
+		}
+		if _, err := io.CopyN(&data, reader, int64(size)); err != nil {
+			return
+		}
+	}
+
+	d.received <- data.Bytes()
+	if bytes.Contains(data.Bytes(), []byte("EICAR-STANDARD-"+"ANTIVIRUS-TEST-FILE")) {
+		conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+		return
+	}
+	conn.Write([]byte("stream: OK\x00"))
+}
+
+func TestClamdScanner_CleanAndInfected(t *testing.T) {
+	daemon := startFakeClamd(t, "tcp", "127.0.0.1:0", 10*1024*1024)
+	scanner, err := repositoryscanner.NewClamdScanner("tcp://"+daemon.listener.Addr().String(), 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create scanner: %v", err)
+	}
+
+	// file lebih besar dari satu chunk supaya pengiriman bertahap ikut diuji
+	clean := bytes.Repeat([]byte("%PDF-1.4 sertifikat "), 10000)
+	result, err := scanner.Scan(context.Background(), bytes.NewReader(clean))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Infected {
+		t.Errorf("Expected clean result, got %+v", result)
+	}
+	if received := <-daemon.received; !bytes.Equal(received, clean) {
+		t.Errorf("Daemon received %d bytes, expected %d", len(received), len(clean))
+	}
+
+	result, err = scanner.Scan(context.Background(), strings.NewReader(eicar))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.Infected || result.Signature != "Eicar-Test-Signature" {
+		t.Errorf("Expected EICAR detection, got %+v", result)
+	}
+}
+
+func TestClamdScanner_UnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "clamd.ctl")
+	startFakeClamd(t, "unix", socket, 1024)
+
+	scanner, err := repositoryscanner.NewClamdScanner("unix://"+socket, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Failed to create scanner: %v", err)
+	}
+
+	result, err := scanner.Scan(context.Background(), strings.NewReader("bersih"))
+	if err != nil || result.Infected {
+		t.Errorf("Expected clean result, got %+v %v", result, err)
+	}
+}
+
+func TestClamdScanner_ErrorReplyIsNotClean(t *testing.T) {
+	daemon := startFakeClamd(t, "tcp", "127.0.0.1:0", 16)
+	scanner, _ := repositoryscanner.NewClamdScanner(daemon.listener.Addr().String(), 5*time.Second)
+
+	if result, err := scanner.Scan(context.Background(), strings.NewReader(strings.Repeat("x", 64))); err == nil {
+		t.Errorf("Expected error for size limit reply, got %+v", result)
+	}
+}
+
+func TestClamdScanner_UnreachableDaemon(t *testing.T) {
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	address := listener.Addr().String()
+	listener.Close()
+
+	scanner, _ := repositoryscanner.NewClamdScanner(address, time.Second)
+	if _, err := scanner.Scan(context.Background(), strings.NewReader("x")); err == nil {
+		t.Errorf("Expected error for unreachable daemon")
+	}
+}
+
+func TestClamdScanner_ContextCancelStopsScan(t *testing.T) {
+	// daemon yang menerima koneksi tetapi tidak pernah membalas
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			go io.Copy(io.Discard, conn)
+		}
+	}()
+
+	scanner, _ := repositoryscanner.NewClamdScanner(listener.Addr().String(), time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if _, err := scanner.Scan(ctx, strings.NewReader("x")); err == nil {
+		t.Errorf("Expected error when context is cancelled")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("Scan did not stop after context cancel")
+	}
+}
+
+func TestNewClamdScanner_InvalidAddress(t *testing.T) {
+	for _, address := range []string{"", "udp://127.0.0.1:3310", "tcp://"} {
+		if _, err := repositoryscanner.NewClamdScanner(address, time.Second); err == nil {
+			t.Errorf("Expected error for address %q", address)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	repositorystorage "sistem-pelaporan-prestasi-mahasiswa/app/repository/storage"
+)
+
+func TestLocalFileStorage_PutGetStatDelete(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	storage := repositorystorage.NewLocalFileStorage(dir, "/uploads")
+
+	if err := storage.Put(ctx, "1-sertifikat.pdf", strings.NewReader("%PDF-1.4 isi"), 12, "application/pdf"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	info, err := storage.Stat(ctx, "1-sertifikat.pdf")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if info.Size != 12 || info.ContentType != "application/pdf" {
+		t.Errorf("Unexpected file info: %+v", info)
+	}
+
+	body, _, err := storage.Get(ctx, "1-sertifikat.pdf")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+	if string(data) != "%PDF-1.4 isi" {
+		t.Errorf("Expected stored content, got %q", data)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected temp file removed after put, got %d entries", len(entries))
+	}
+
+	if err := storage.Delete(ctx, "1-sertifikat.pdf"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := storage.Delete(ctx, "1-sertifikat.pdf"); err != nil {
+		t.Errorf("Expected deleting missing file to succeed, got %v", err)
+	}
+	if _, err := storage.Stat(ctx, "1-sertifikat.pdf"); !errors.Is(err, repositorystorage.ErrFileNotFound) {
+		t.Errorf("Expected ErrFileNotFound, got %v", err)
+	}
+}
+
+func TestLocalFileStorage_RejectsPathTraversal(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	storage := repositorystorage.NewLocalFileStorage(filepath.Join(dir, "uploads"), "/uploads/")
+
+	for _, key := range []string{"../rahasia.txt", "a/b.pdf", `..\b.pdf`, "..", ""} {
+		if err := storage.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+			t.Errorf("Expected key %q rejected", key)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "rahasia.txt")); !os.IsNotExist(err) {
+		t.Errorf("Expected no file written outside root")
+	}
+}
+
+func TestLocalFileStorage_URLAndKeyFromURL(t *testing.T) {
+	storage := repositorystorage.NewLocalFileStorage(t.TempDir(), "/uploads/")
+
+	if url := storage.URL("1-a.pdf"); url != "/uploads/1-a.pdf" {
+		t.Errorf("Expected /uploads/1-a.pdf, got %s", url)
+	}
+
+	if key, ok := storage.KeyFromURL("/uploads/1-a.pdf"); !ok || key != "1-a.pdf" {
+		t.Errorf("Expected key 1-a.pdf, got %q %v", key, ok)
+	}
+
+	for _, url := range []string{"https://cdn.example.com/1-a.pdf", "/uploads/../etc/passwd", "/uploads/"} {
+		if _, ok := storage.KeyFromURL(url); ok {
+			t.Errorf("Expected URL %q not owned by local storage", url)
+		}
+	}
+}
@@ -0,0 +1,927 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	attachmentTestStudentID = "550e8400-e29b-41d4-a716-446655440000"
+	attachmentTestPDF       = "%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\n%%EOF\n"
+)
+
+func newAttachmentTestService(achievementRepo *mockAchievementRepo, status string, fileStorage *mockFileStorage) servicepostgre.IAchievementService {
+	return newAttachmentTestServiceWithConfig(achievementRepo, status, fileStorage, testAttachmentConfig())
+}
+
+func newAttachmentTestServiceWithConfig(achievementRepo *mockAchievementRepo, status string, fileStorage *mockFileStorage, config modelmongo.AttachmentConfig) servicepostgre.IAchievementService {
+	return servicepostgre.NewAchievementService(
+		achievementRepo,
+		&mockAchievementRefRepo{
+			byMongoID: &modelpostgre.AchievementReference{
+				ID:                 "ref-id-1",
+				StudentID:          attachmentTestStudentID,
+				MongoAchievementID: "mongo-id-1",
+				Status:             status,
+			},
+		},
+		&mockUserRepo{roleName: "Mahasiswa"},
+		&mockStudentRepo{studentIDByUserID: attachmentTestStudentID},
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		fileStorage,
+		config,
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+}
+
+func TestLoadAttachmentConfig_LinkSecret(t *testing.T) {
+	testCases := []struct {
+		name       string
+		appEnv     string
+		secret     string
+		wantErr    bool
+		wantSecret string
+	}{
+		{name: "production dengan secret", appEnv: "production", secret: "rahasia-bersama", wantSecret: "rahasia-bersama"},
+		{name: "production tanpa secret", appEnv: "production", wantErr: true},
+		{name: "development tanpa secret", appEnv: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv("APP_ENV", tc.appEnv)
+			t.Setenv("ATTACHMENT_LINK_SECRET", tc.secret)
+
+			config, err := servicepostgre.LoadAttachmentConfig()
+
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Expected error %v, got %v", tc.wantErr, err)
+			}
+			if tc.wantErr {
+				return
+			}
+			if tc.wantSecret != "" && string(config.LinkSecret) != tc.wantSecret {
+				t.Errorf("Expected secret from environment, got %q", config.LinkSecret)
+			}
+			if tc.wantSecret == "" && len(config.LinkSecret) != 32 {
+				t.Errorf("Expected random 32 byte secret, got %d bytes", len(config.LinkSecret))
+			}
+		})
+	}
+}
+
+func TestUploadFile_StoresFileAndHashesContent(t *testing.T) {
+	ctx := context.Background()
+	fileStorage := &mockFileStorage{}
+	service := newAttachmentTestService(&mockAchievementRepo{
+		byID: &modelmongo.Achievement{ID: primitive.NewObjectID(), StudentID: attachmentTestStudentID, Title: "Juara"},
+	}, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	attachment, err := service.UploadFile(ctx, "user-id-1", "role-id-1", "mongo-id-1", "sertifikat lomba.pdf", "application/pdf", int64(len(attachmentTestPDF)), strings.NewReader(attachmentTestPDF))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(fileStorage.objects) != 1 {
+		t.Fatalf("Expected one stored object, got %d", len(fileStorage.objects))
+	}
+
+	hash := sha256.Sum256([]byte(attachmentTestPDF))
+	key := hex.EncodeToString(hash[:]) + ".pdf"
+	if string(fileStorage.objects[key]) != attachmentTestPDF {
+		t.Errorf("Expected file stored under content hash key %s, got %v", key, fileStorage.objects)
+	}
+	if attachment.FileURL != "/uploads/"+key {
+		t.Errorf("Expected URL from storage, got %s", attachment.FileURL)
+	}
+	if attachment.ContentHash != hex.EncodeToString(hash[:]) {
+		t.Errorf("Unexpected content hash %s", attachment.ContentHash)
+	}
+	if attachment.Size != int64(len(attachmentTestPDF)) {
+		t.Errorf("Expected size %d, got %d", len(attachmentTestPDF), attachment.Size)
+	}
+	if attachment.ID == "" {
+		t.Errorf("Expected attachment ID to be assigned")
+	}
+}
+
+func TestUploadFile_RemovesStoredFileWhenAttachmentFails(t *testing.T) {
+	ctx := context.Background()
+	fileStorage := &mockFileStorage{}
+	service := newAttachmentTestService(&mockAchievementRepo{addAttachmentErr: errors.New("mongo down")}, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	if _, err := service.UploadFile(ctx, "user-id-1", "role-id-1", "mongo-id-1", "a.pdf", "application/pdf", int64(len(attachmentTestPDF)), strings.NewReader(attachmentTestPDF)); err == nil {
+		t.Fatalf("Expected error")
+	}
+
+	if len(fileStorage.objects) != 0 || len(fileStorage.deleted) != 2 {
+		t.Errorf("Expected temporary and stored file removed, objects=%d deleted=%v", len(fileStorage.objects), fileStorage.deleted)
+	}
+}
+
+func TestUploadFile_NotDraftDoesNotStore(t *testing.T) {
+	ctx := context.Background()
+	fileStorage := &mockFileStorage{}
+	service := newAttachmentTestService(&mockAchievementRepo{}, modelpostgre.AchievementStatusSubmitted, fileStorage)
+
+	if _, err := service.UploadFile(ctx, "user-id-1", "role-id-1", "mongo-id-1", "a.pdf", "application/pdf", int64(len(attachmentTestPDF)), strings.NewReader(attachmentTestPDF)); err == nil {
+		t.Fatalf("Expected error for non-draft achievement")
+	}
+
+	if len(fileStorage.objects) != 0 {
+		t.Errorf("Expected nothing stored, got %d objects", len(fileStorage.objects))
+	}
+}
+
+func TestUploadFile_RejectsContentNotMatchingExtension(t *testing.T) {
+	ctx := context.Background()
+	fileStorage := &mockFileStorage{}
+	service := newAttachmentTestService(&mockAchievementRepo{}, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	_, err := service.UploadFile(ctx, "user-id-1", "role-id-1", "mongo-id-1", "sertifikat.pdf", "application/pdf", 4, strings.NewReader("MZ\x90\x00"))
+
+	var validationErr *modelmongo.AttachmentValidationError
+	if !errors.As(err, &validationErr) || validationErr.TooLarge {
+		t.Fatalf("Expected content validation error, got %v", err)
+	}
+	if len(fileStorage.objects) != 0 {
+		t.Errorf("Expected nothing stored, got %d objects", len(fileStorage.objects))
+	}
+}
+
+func TestUploadFile_RejectsPDFWithJavaScript(t *testing.T) {
+	ctx := context.Background()
+	fileStorage := &mockFileStorage{}
+	service := newAttachmentTestService(&mockAchievementRepo{}, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	content := "%PDF-1.7\n" + strings.Repeat("% padding\n", 200) + "2 0 obj << /S /J#61vaScript /JS (app.alert(1)) >> endobj\n%%EOF\n"
+	_, err := service.UploadFile(ctx, "user-id-1", "role-id-1", "mongo-id-1", "a.pdf", "application/pdf", int64(len(content)), strings.NewReader(content))
+
+	if err == nil || !strings.Contains(err.Error(), "JavaScript") {
+		t.Fatalf("Expected JavaScript rejection, got %v", err)
+	}
+	if len(fileStorage.objects) != 0 {
+		t.Errorf("Expected nothing stored, got %d objects", len(fileStorage.objects))
+	}
+}
+
+func TestUploadFile_StopsReadingOversizedBody(t *testing.T) {
+	ctx := context.Background()
+	fileStorage := &mockFileStorage{}
+	service := newAttachmentTestService(&mockAchievementRepo{}, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	// ukuran yang dikirim client lebih kecil dari isi file sebenarnya
+	content := io.MultiReader(strings.NewReader("\x89PNG\r\n\x1a\n"), io.LimitReader(zeroReader{}, 6*1024*1024))
+	_, err := service.UploadFile(ctx, "user-id-1", "role-id-1", "mongo-id-1", "foto.png", "image/png", 1024, content)
+
+	var validationErr *modelmongo.AttachmentValidationError
+	if !errors.As(err, &validationErr) || !validationErr.TooLarge {
+		t.Fatalf("Expected too large error, got %v", err)
+	}
+	if len(fileStorage.objects) != 0 {
+		t.Errorf("Expected nothing stored, got %d objects", len(fileStorage.objects))
+	}
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func newDownloadTestService(refStudentID string) (servicepostgre.IAchievementService, *mockFileStorage) {
+	fileStorage := &mockFileStorage{objects: map[string][]byte{"1-sertifikat.pdf": []byte("%PDF-1.4")}}
+	achievementRepo := &mockAchievementRepo{
+		byID: &modelmongo.Achievement{
+			ID:        primitive.NewObjectID(),
+			StudentID: refStudentID,
+			Attachments: []modelmongo.Attachment{
+				{FileName: "sertifikat.pdf", FileURL: "/uploads/1-sertifikat.pdf", FileType: "application/pdf"},
+			},
+		},
+	}
+
+	service := servicepostgre.NewAchievementService(
+		achievementRepo,
+		&mockAchievementRefRepo{
+			byMongoID: &modelpostgre.AchievementReference{ID: "ref-id-1", StudentID: refStudentID, MongoAchievementID: "mongo-id-1", Status: modelpostgre.AchievementStatusVerified},
+		},
+		&mockUserRepo{roleName: "Mahasiswa"},
+		&mockStudentRepo{studentIDByUserID: attachmentTestStudentID},
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		fileStorage,
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+	return service, fileStorage
+}
+
+func TestGetAttachmentFile_OwnerCanDownload(t *testing.T) {
+	service, _ := newDownloadTestService(attachmentTestStudentID)
+
+	download, err := service.GetAttachmentFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "1-sertifikat.pdf")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer download.Body.Close()
+
+	if download.FileName != "sertifikat.pdf" || download.ContentType != "application/pdf" || download.Size != 8 {
+		t.Errorf("Unexpected download %+v", download)
+	}
+}
+
+func TestGetAttachmentFile_OtherStudentDenied(t *testing.T) {
+	service, _ := newDownloadTestService("student-lain")
+
+	_, err := service.GetAttachmentFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "1-sertifikat.pdf")
+	if err == nil || !strings.Contains(err.Error(), "akses ditolak") {
+		t.Errorf("Expected access denied, got %v", err)
+	}
+}
+
+func TestGetAttachmentFile_UnknownKeyNotFound(t *testing.T) {
+	service, _ := newDownloadTestService(attachmentTestStudentID)
+
+	_, err := service.GetAttachmentFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "2-lain.pdf")
+	if err == nil || !strings.Contains(err.Error(), "tidak ditemukan") {
+		t.Errorf("Expected not found, got %v", err)
+	}
+}
+
+func TestAttachmentLink_SignedDownload(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newDownloadTestService(attachmentTestStudentID)
+
+	link, err := service.CreateAttachmentLink(ctx, "user-id-1", "role-id-1", "mongo-id-1", "1-sertifikat.pdf")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	parsed, err := url.Parse(link.Data.URL)
+	if err != nil {
+		t.Fatalf("Invalid link URL %s: %v", link.Data.URL, err)
+	}
+	if parsed.Path != "/api/v1/attachments/mongo-id-1/1-sertifikat.pdf" {
+		t.Errorf("Unexpected link path %s", parsed.Path)
+	}
+
+	expires, _ := strconv.ParseInt(parsed.Query().Get("expires"), 10, 64)
+	signature := parsed.Query().Get("signature")
+	if expires != link.Data.ExpiresAt.Unix() || time.Until(link.Data.ExpiresAt) > 15*time.Minute {
+		t.Errorf("Unexpected expiry %v", link.Data.ExpiresAt)
+	}
+
+	download, err := service.GetSignedAttachmentFile(ctx, "mongo-id-1", "1-sertifikat.pdf", expires, signature)
+	if err != nil {
+		t.Fatalf("Expected signed download, got %v", err)
+	}
+	download.Body.Close()
+
+	// signature tidak berlaku untuk key lain, waktu kedaluwarsa lain, atau signature yang diubah
+	cases := []struct {
+		key       string
+		expires   int64
+		signature string
+	}{
+		{"2-lain.pdf", expires, signature},
+		{"1-sertifikat.pdf", expires + 3600, signature},
+		{"1-sertifikat.pdf", expires, strings.Repeat("0", len(signature))},
+	}
+	for _, tc := range cases {
+		if _, err := service.GetSignedAttachmentFile(ctx, "mongo-id-1", tc.key, tc.expires, tc.signature); err == nil || !strings.Contains(err.Error(), "akses ditolak") {
+			t.Errorf("Expected tampered link rejected for %+v, got %v", tc, err)
+		}
+	}
+}
+
+func TestAttachmentLink_ExpiredRejected(t *testing.T) {
+	ctx := context.Background()
+	fileStorage := &mockFileStorage{}
+	expiredConfig := modelmongo.AttachmentConfig{LinkSecret: []byte("test-attachment-secret"), LinkTTL: -time.Minute}
+	service := servicepostgre.NewAchievementService(
+		&mockAchievementRepo{byID: &modelmongo.Achievement{ID: primitive.NewObjectID(), Attachments: []modelmongo.Attachment{{FileURL: "/uploads/1-a.pdf"}}}},
+		&mockAchievementRefRepo{byMongoID: &modelpostgre.AchievementReference{StudentID: attachmentTestStudentID}},
+		&mockUserRepo{roleName: "Mahasiswa"},
+		&mockStudentRepo{studentIDByUserID: attachmentTestStudentID},
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		fileStorage,
+		expiredConfig,
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	link, err := service.CreateAttachmentLink(ctx, "user-id-1", "role-id-1", "mongo-id-1", "1-a.pdf")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	parsed, _ := url.Parse(link.Data.URL)
+	expires, _ := strconv.ParseInt(parsed.Query().Get("expires"), 10, 64)
+
+	if _, err := service.GetSignedAttachmentFile(ctx, "mongo-id-1", "1-a.pdf", expires, parsed.Query().Get("signature")); err == nil || !strings.Contains(err.Error(), "akses ditolak") {
+		t.Errorf("Expected expired link rejected, got %v", err)
+	}
+}
+
+func newScanStatusTestService(roleName string, scanStatus string, scanEnabled bool) (servicepostgre.IAchievementService, *mockFileStorage) {
+	fileStorage := &mockFileStorage{objects: map[string][]byte{"1-sertifikat.pdf": []byte(attachmentTestPDF)}}
+	config := testAttachmentConfig()
+	config.ScanEnabled = scanEnabled
+
+	service := servicepostgre.NewAchievementService(
+		&mockAchievementRepo{
+			byID: &modelmongo.Achievement{
+				ID:        primitive.NewObjectID(),
+				StudentID: attachmentTestStudentID,
+				Attachments: []modelmongo.Attachment{
+					{FileName: "sertifikat.pdf", FileURL: "/uploads/1-sertifikat.pdf", FileType: "application/pdf", ScanStatus: scanStatus},
+				},
+			},
+		},
+		&mockAchievementRefRepo{
+			byMongoID: &modelpostgre.AchievementReference{ID: "ref-id-1", StudentID: attachmentTestStudentID, MongoAchievementID: "mongo-id-1", Status: modelpostgre.AchievementStatusDraft},
+		},
+		&mockUserRepo{roleName: roleName},
+		&mockStudentRepo{studentIDByUserID: attachmentTestStudentID},
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		fileStorage,
+		config,
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+	return service, fileStorage
+}
+
+func TestUploadFile_PendingWhenScanEnabled(t *testing.T) {
+	service, _ := newScanStatusTestService("Mahasiswa", "", true)
+
+	attachment, err := service.UploadFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "a.pdf", "application/pdf", int64(len(attachmentTestPDF)), strings.NewReader(attachmentTestPDF))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if attachment.ScanStatus != modelmongo.AttachmentScanPending {
+		t.Errorf("Expected pending scan status, got %q", attachment.ScanStatus)
+	}
+}
+
+func TestGetAttachmentFile_ScanStatus(t *testing.T) {
+	cases := []struct {
+		roleName   string
+		scanStatus string
+		allowed    bool
+	}{
+		{"Mahasiswa", modelmongo.AttachmentScanPending, true},
+		{"Admin", modelmongo.AttachmentScanPending, false},
+		{"Admin", modelmongo.AttachmentScanClean, true},
+		{"Admin", "", true},
+		{"Mahasiswa", modelmongo.AttachmentScanInfected, false},
+		{"Admin", modelmongo.AttachmentScanInfected, false},
+	}
+
+	for _, tc := range cases {
+		service, _ := newScanStatusTestService(tc.roleName, tc.scanStatus, true)
+		download, err := service.GetAttachmentFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "1-sertifikat.pdf")
+		if tc.allowed {
+			if err != nil {
+				t.Errorf("%s/%q: expected download allowed, got %v", tc.roleName, tc.scanStatus, err)
+				continue
+			}
+			download.Body.Close()
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), "akses ditolak") {
+			t.Errorf("%s/%q: expected access denied, got %v", tc.roleName, tc.scanStatus, err)
+		}
+	}
+}
+
+func TestAttachmentLink_PendingFileHasNoLink(t *testing.T) {
+	service, _ := newScanStatusTestService("Mahasiswa", modelmongo.AttachmentScanPending, true)
+
+	if _, err := service.CreateAttachmentLink(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "1-sertifikat.pdf"); err == nil || !strings.Contains(err.Error(), "masih dipindai") {
+		t.Errorf("Expected pending file link rejected, got %v", err)
+	}
+}
+
+func newAttachmentChangeTestRepo() *mockAchievementRepo {
+	return &mockAchievementRepo{
+		byID: &modelmongo.Achievement{
+			ID:        primitive.NewObjectID(),
+			StudentID: attachmentTestStudentID,
+			Attachments: []modelmongo.Attachment{
+				{ID: "att-1", FileName: "lama.pdf", FileURL: "/uploads/1-lama.pdf", FileType: "application/pdf"},
+				{ID: "att-2", FileName: "foto.png", FileURL: "/uploads/2-foto.png", FileType: "image/png"},
+			},
+		},
+	}
+}
+
+func newAttachmentChangeTestStorage() *mockFileStorage {
+	return &mockFileStorage{objects: map[string][]byte{
+		"1-lama.pdf": []byte(attachmentTestPDF),
+		"2-foto.png": []byte("\x89PNG\r\n\x1a\n"),
+	}}
+}
+
+func TestDeleteAttachment_RemovesUnreferencedFile(t *testing.T) {
+	achievementRepo := newAttachmentChangeTestRepo()
+	fileStorage := newAttachmentChangeTestStorage()
+	service := newAttachmentTestService(achievementRepo, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	response, err := service.DeleteAttachment(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "att-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(response.Data) != 1 || response.Data[0].ID != "att-2" || response.Data[0].DownloadURL == "" {
+		t.Errorf("Expected remaining attachment att-2 with download URL, got %+v", response.Data)
+	}
+	if len(fileStorage.deleted) != 1 || fileStorage.deleted[0] != "1-lama.pdf" {
+		t.Errorf("Expected 1-lama.pdf removed from storage, got %v", fileStorage.deleted)
+	}
+}
+
+func TestDeleteAttachment_KeepsFileReferencedElsewhere(t *testing.T) {
+	achievementRepo := newAttachmentChangeTestRepo()
+	achievementRepo.fileRefs = map[string]int64{"/uploads/1-lama.pdf": 1}
+	fileStorage := newAttachmentChangeTestStorage()
+	service := newAttachmentTestService(achievementRepo, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	if _, err := service.DeleteAttachment(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "att-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if achievementRepo.removedAttachmentID != "att-1" {
+		t.Errorf("Expected att-1 removed from achievement, got %q", achievementRepo.removedAttachmentID)
+	}
+	if len(fileStorage.deleted) != 0 {
+		t.Errorf("Expected referenced file kept, deleted %v", fileStorage.deleted)
+	}
+}
+
+func TestDeleteAttachment_StatusRules(t *testing.T) {
+	tests := []struct {
+		status  string
+		allowed bool
+	}{
+		{modelpostgre.AchievementStatusDraft, true},
+		{modelpostgre.AchievementStatusRejected, true},
+		{modelpostgre.AchievementStatusSubmitted, false},
+		{modelpostgre.AchievementStatusVerified, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			achievementRepo := newAttachmentChangeTestRepo()
+			service := newAttachmentTestService(achievementRepo, tt.status, newAttachmentChangeTestStorage())
+
+			_, err := service.DeleteAttachment(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "att-1")
+			if tt.allowed && err != nil {
+				t.Errorf("Expected delete allowed, got %v", err)
+			}
+			if !tt.allowed && (err == nil || !strings.Contains(err.Error(), "draft atau rejected")) {
+				t.Errorf("Expected status error, got %v", err)
+			}
+			if !tt.allowed && achievementRepo.removedAttachmentID != "" {
+				t.Errorf("Expected attachment kept")
+			}
+		})
+	}
+}
+
+func TestDeleteAttachment_UnknownAttachmentNotFound(t *testing.T) {
+	service := newAttachmentTestService(newAttachmentChangeTestRepo(), modelpostgre.AchievementStatusDraft, newAttachmentChangeTestStorage())
+
+	_, err := service.DeleteAttachment(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "att-lain")
+	if err == nil || !strings.Contains(err.Error(), "tidak ditemukan") {
+		t.Errorf("Expected not found, got %v", err)
+	}
+}
+
+func TestReplaceAttachment_KeepsIDAndReleasesOldFile(t *testing.T) {
+	achievementRepo := newAttachmentChangeTestRepo()
+	fileStorage := newAttachmentChangeTestStorage()
+	service := newAttachmentTestService(achievementRepo, modelpostgre.AchievementStatusRejected, fileStorage)
+
+	content := "%PDF-1.7\nrevisi\n%%EOF\n"
+	attachment, err := service.ReplaceAttachment(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "att-1", "revisi.pdf", "application/pdf", int64(len(content)), strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if attachment.ID != "att-1" || attachment.FileName != "revisi.pdf" || attachment.DownloadURL == "" {
+		t.Errorf("Unexpected replaced attachment %+v", attachment)
+	}
+	if achievementRepo.replacedAttachment == nil || achievementRepo.replacedAttachment.FileURL != attachment.FileURL {
+		t.Errorf("Expected replacement saved, got %+v", achievementRepo.replacedAttachment)
+	}
+	if _, ok := fileStorage.objects["1-lama.pdf"]; ok {
+		t.Errorf("Expected old file removed from storage")
+	}
+	if len(fileStorage.objects) != 2 {
+		t.Errorf("Expected new file stored next to 2-foto.png, got %d objects", len(fileStorage.objects))
+	}
+}
+
+func TestReplaceAttachment_InvalidFileKeepsOldAttachment(t *testing.T) {
+	achievementRepo := newAttachmentChangeTestRepo()
+	fileStorage := newAttachmentChangeTestStorage()
+	service := newAttachmentTestService(achievementRepo, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	_, err := service.ReplaceAttachment(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "att-1", "revisi.pdf", "application/pdf", 4, strings.NewReader("MZ\x90\x00"))
+
+	var validationErr *modelmongo.AttachmentValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+	if achievementRepo.replacedAttachment != nil || len(fileStorage.deleted) != 0 || len(fileStorage.objects) != 2 {
+		t.Errorf("Expected old attachment untouched, replaced=%+v deleted=%v", achievementRepo.replacedAttachment, fileStorage.deleted)
+	}
+}
+
+func TestReplaceAttachment_OtherStudentDenied(t *testing.T) {
+	fileStorage := newAttachmentChangeTestStorage()
+	service := servicepostgre.NewAchievementService(
+		newAttachmentChangeTestRepo(),
+		&mockAchievementRefRepo{
+			byMongoID: &modelpostgre.AchievementReference{ID: "ref-id-1", StudentID: "student-lain", MongoAchievementID: "mongo-id-1", Status: modelpostgre.AchievementStatusDraft},
+		},
+		&mockUserRepo{roleName: "Mahasiswa"},
+		&mockStudentRepo{studentIDByUserID: attachmentTestStudentID},
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		fileStorage,
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	_, err := service.ReplaceAttachment(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "att-1", "revisi.pdf", "application/pdf", int64(len(attachmentTestPDF)), strings.NewReader(attachmentTestPDF))
+	if err == nil || !strings.Contains(err.Error(), "akses ditolak") {
+		t.Errorf("Expected access denied, got %v", err)
+	}
+	if len(fileStorage.objects) != 2 {
+		t.Errorf("Expected nothing stored, got %d objects", len(fileStorage.objects))
+	}
+}
+
+func testAttachmentImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 200, A: 255})
+		}
+	}
+	return img
+}
+
+func storedPreview(t *testing.T, fileStorage *mockFileStorage) (string, image.Image) {
+	t.Helper()
+	for key, data := range fileStorage.objects {
+		if strings.HasSuffix(key, ".preview.jpg") {
+			img, err := jpeg.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("Expected preview to be a JPEG, got %v", err)
+			}
+			return key, img
+		}
+	}
+	t.Fatalf("Expected preview stored, objects: %d", len(fileStorage.objects))
+	return "", nil
+}
+
+func TestUploadFile_GeneratesImagePreview(t *testing.T) {
+	var content bytes.Buffer
+	if err := png.Encode(&content, testAttachmentImage(800, 400)); err != nil {
+		t.Fatalf("Failed to encode PNG: %v", err)
+	}
+
+	fileStorage := &mockFileStorage{}
+	service := newAttachmentTestService(&mockAchievementRepo{}, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	attachment, err := service.UploadFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "foto.png", "image/png", int64(content.Len()), bytes.NewReader(content.Bytes()))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	key, preview := storedPreview(t, fileStorage)
+	if preview.Bounds().Dx() != 320 || preview.Bounds().Dy() != 160 {
+		t.Errorf("Expected 320x160 preview, got %v", preview.Bounds())
+	}
+	if !attachment.HasPreview || attachment.PreviewURL != "/api/v1/achievements/mongo-id-1/attachments/"+key {
+		t.Errorf("Expected preview URL for %s, got %+v", key, attachment)
+	}
+}
+
+// testPDFWithPages membuat PDF dengan satu gambar JPEG seukuran halaman per halaman, object gambar ditulis terbalik sehingga gambar halaman terakhir muncul paling awal di file
+func testPDFWithPages(t *testing.T, sizes ...[2]int) string {
+	t.Helper()
+	count := len(sizes)
+	kids := []string{}
+	objects := []string{}
+	for i, size := range sizes {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 3+i))
+		objects = append(objects, fmt.Sprintf("%d 0 obj\n<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %d %d] /Resources << /XObject << /Im0 %d 0 R >> >> /Contents %d 0 R >>\nendobj\n", 3+i, size[0], size[1], 3+count+i, 3+2*count+i))
+	}
+	for i := count - 1; i >= 0; i-- {
+		var scan bytes.Buffer
+		if err := jpeg.Encode(&scan, testAttachmentImage(sizes[i][0], sizes[i][1]), nil); err != nil {
+			t.Fatalf("Failed to encode JPEG: %v", err)
+		}
+		objects = append(objects, fmt.Sprintf("%d 0 obj\n<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>\nstream\n%s\nendstream\nendobj\n", 3+count+i, sizes[i][0], sizes[i][1], scan.Len(), scan.Bytes()))
+	}
+	for i, size := range sizes {
+		draw := fmt.Sprintf("q %d 0 0 %d 0 0 cm /Im0 Do Q", size[0], size[1])
+		objects = append(objects, fmt.Sprintf("%d 0 obj\n<< /Length %d >>\nstream\n%s\nendstream\nendobj\n", 3+2*count+i, len(draw), draw))
+	}
+	return "%PDF-1.4\n1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n" +
+		fmt.Sprintf("2 0 obj\n<< /Type /Pages /Kids [%s] /Count %d >>\nendobj\n", strings.Join(kids, " "), count) +
+		strings.Join(objects, "") +
+		"trailer\n<< /Root 1 0 R >>\n%%EOF\n"
+}
+
+func TestUploadFile_GeneratesPDFPreviewFromFirstPage(t *testing.T) {
+	content := testPDFWithPages(t, [2]int{400, 600}, [2]int{600, 400})
+
+	fileStorage := &mockFileStorage{}
+	service := newAttachmentTestService(&mockAchievementRepo{}, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	attachment, err := service.UploadFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "sertifikat.pdf", "application/pdf", int64(len(content)), strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// halaman 1 berukuran 400x600 pt dengan gambar (x, y, 200) yang menutupi seluruh halaman
+	_, preview := storedPreview(t, fileStorage)
+	if preview.Bounds().Dx() != 213 || preview.Bounds().Dy() != 320 {
+		t.Errorf("Expected 213x320 preview of page 1, got %v", preview.Bounds())
+	}
+	r, _, b, _ := preview.At(106, 160).RGBA()
+	if r>>8 < 176 || r>>8 > 224 || b>>8 < 176 || b>>8 > 224 {
+		t.Errorf("Expected page 1 image drawn in preview, got r=%d b=%d", r>>8, b>>8)
+	}
+	if attachment.PreviewURL == "" {
+		t.Errorf("Expected preview URL")
+	}
+}
+
+func TestUploadFile_PDFImageOutsidePageTreeHasNoPreview(t *testing.T) {
+	var scan bytes.Buffer
+	if err := jpeg.Encode(&scan, testAttachmentImage(400, 600), nil); err != nil {
+		t.Fatalf("Failed to encode JPEG: %v", err)
+	}
+	content := fmt.Sprintf("%%PDF-1.4\n1 0 obj << /Type /XObject /Subtype /Image /Width 400 /Height 600 /Filter /DCTDecode /Length %d >>\nstream\n%s\nendstream\nendobj\n%%%%EOF\n", scan.Len(), scan.Bytes())
+
+	fileStorage := &mockFileStorage{}
+	service := newAttachmentTestService(&mockAchievementRepo{}, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	attachment, err := service.UploadFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "sertifikat.pdf", "application/pdf", int64(len(content)), strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if attachment.HasPreview || len(fileStorage.objects) != 1 {
+		t.Errorf("Expected no preview without page tree, got %+v", attachment)
+	}
+}
+
+func TestUploadFile_PDFWithoutPagesHasNoPreview(t *testing.T) {
+	fileStorage := &mockFileStorage{}
+	service := newAttachmentTestService(&mockAchievementRepo{}, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	attachment, err := service.UploadFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "a.pdf", "application/pdf", int64(len(attachmentTestPDF)), strings.NewReader(attachmentTestPDF))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if attachment.HasPreview || attachment.PreviewURL != "" || len(fileStorage.objects) != 1 {
+		t.Errorf("Expected no preview, got %+v with %d objects", attachment, len(fileStorage.objects))
+	}
+}
+
+func TestGetAttachmentFile_Preview(t *testing.T) {
+	achievementRepo := newAttachmentChangeTestRepo()
+	achievementRepo.byID.Attachments[0].HasPreview = true
+	fileStorage := newAttachmentChangeTestStorage()
+	fileStorage.objects["1-lama.pdf.preview.jpg"] = []byte("\xff\xd8\xff preview")
+	fileStorage.objects["2-foto.png.preview.jpg"] = []byte("\xff\xd8\xff preview")
+	service := newAttachmentTestService(achievementRepo, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	download, err := service.GetAttachmentFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "1-lama.pdf.preview.jpg")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer download.Body.Close()
+
+	if download.FileName != "lama-preview.jpg" || download.ContentType != "image/jpeg" {
+		t.Errorf("Unexpected preview download %+v", download)
+	}
+
+	// preview tanpa penanda HasPreview di attachment tidak bisa dibuka
+	if _, err := service.GetAttachmentFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "2-foto.png.preview.jpg"); err == nil {
+		t.Errorf("Expected preview without flag to be rejected")
+	}
+}
+
+func TestDeleteAttachment_RemovesPreview(t *testing.T) {
+	achievementRepo := newAttachmentChangeTestRepo()
+	achievementRepo.byID.Attachments[0].HasPreview = true
+	fileStorage := newAttachmentChangeTestStorage()
+	fileStorage.objects["1-lama.pdf.preview.jpg"] = []byte("\xff\xd8\xff")
+	service := newAttachmentTestService(achievementRepo, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	if _, err := service.DeleteAttachment(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "att-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, ok := fileStorage.objects["1-lama.pdf.preview.jpg"]; ok {
+		t.Errorf("Expected preview removed, deleted %v", fileStorage.deleted)
+	}
+}
+
+func TestUploadFile_StoresIdenticalContentOnce(t *testing.T) {
+	fileStorage := &mockFileStorage{}
+	service := newAttachmentTestService(&mockAchievementRepo{
+		byID: &modelmongo.Achievement{ID: primitive.NewObjectID(), StudentID: attachmentTestStudentID},
+	}, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	first, err := service.UploadFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "a.pdf", "application/pdf", int64(len(attachmentTestPDF)), strings.NewReader(attachmentTestPDF))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second, err := service.UploadFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "salinan.pdf", "application/pdf", int64(len(attachmentTestPDF)), strings.NewReader(attachmentTestPDF))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if first.FileURL != second.FileURL || first.ID == second.ID {
+		t.Errorf("Expected two attachments sharing one file, got %+v and %+v", first, second)
+	}
+	if second.FileName != "salinan.pdf" {
+		t.Errorf("Expected each attachment to keep its own file name, got %s", second.FileName)
+	}
+	if len(fileStorage.objects) != 1 {
+		t.Errorf("Expected one stored object without temporary files, got %v", fileStorage.objects)
+	}
+}
+
+func TestDeleteAttachment_KeepsFileSharedByAnotherAttachment(t *testing.T) {
+	achievementRepo := newAttachmentChangeTestRepo()
+	achievementRepo.byID.Attachments = append(achievementRepo.byID.Attachments, modelmongo.Attachment{
+		ID: "att-3", FileName: "lama-salinan.pdf", FileURL: "/uploads/1-lama.pdf", FileType: "application/pdf",
+	})
+	fileStorage := newAttachmentChangeTestStorage()
+	service := newAttachmentTestService(achievementRepo, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	if _, err := service.DeleteAttachment(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "att-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, ok := fileStorage.objects["1-lama.pdf"]; !ok {
+		t.Errorf("Expected file shared with att-3 kept, deleted %v", fileStorage.deleted)
+	}
+}
+
+func TestUploadFile_RejectsWhenQuotaExceeded(t *testing.T) {
+	config := testAttachmentConfig()
+	config.StudentQuota = 100
+	fileStorage := &mockFileStorage{}
+	service := newAttachmentTestServiceWithConfig(&mockAchievementRepo{studentUsage: 90}, modelpostgre.AchievementStatusDraft, fileStorage, config)
+
+	_, err := service.UploadFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "a.pdf", "application/pdf", int64(len(attachmentTestPDF)), strings.NewReader(attachmentTestPDF))
+
+	var quotaErr *modelmongo.AttachmentQuotaError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("Expected quota error, got %v", err)
+	}
+	if quotaErr.Used != 90 || quotaErr.Quota != 100 {
+		t.Errorf("Unexpected quota error %+v", quotaErr)
+	}
+	if len(fileStorage.objects) != 0 {
+		t.Errorf("Expected nothing stored, got %v", fileStorage.objects)
+	}
+}
+
+func TestUploadFile_CountsQuotaReservedByConcurrentUpload(t *testing.T) {
+	size := int64(len(attachmentTestPDF))
+	config := testAttachmentConfig()
+	config.StudentQuota = 50 + size - 1
+	fileStorage := &mockFileStorage{}
+	achievementRepo := &mockAchievementRepo{reservations: map[string]int64{"other-upload": 50}}
+	service := newAttachmentTestServiceWithConfig(achievementRepo, modelpostgre.AchievementStatusDraft, fileStorage, config)
+
+	_, err := service.UploadFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "a.pdf", "application/pdf", size, strings.NewReader(attachmentTestPDF))
+
+	var quotaErr *modelmongo.AttachmentQuotaError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("Expected quota error, got %v", err)
+	}
+	if quotaErr.Used != 50 {
+		t.Errorf("Expected reservation of the other upload counted as used, got %+v", quotaErr)
+	}
+	if len(achievementRepo.reservations) != 1 || achievementRepo.reservations["other-upload"] != 50 {
+		t.Errorf("Expected own reservation released and other kept, got %v", achievementRepo.reservations)
+	}
+	if len(fileStorage.objects) != 0 {
+		t.Errorf("Expected nothing stored, got %v", fileStorage.objects)
+	}
+}
+
+func TestUploadFile_ReleasesQuotaReservation(t *testing.T) {
+	config := testAttachmentConfig()
+	config.StudentQuota = 1024 * 1024
+
+	testCases := []struct {
+		name    string
+		storage *mockFileStorage
+		wantErr bool
+	}{
+		{name: "setelah attachment tercatat", storage: &mockFileStorage{}},
+		{name: "saat penyimpanan gagal", storage: &mockFileStorage{putErr: errors.New("storage penuh")}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			achievementRepo := &mockAchievementRepo{}
+			service := newAttachmentTestServiceWithConfig(achievementRepo, modelpostgre.AchievementStatusDraft, tc.storage, config)
+
+			_, err := service.UploadFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "a.pdf", "application/pdf", int64(len(attachmentTestPDF)), strings.NewReader(attachmentTestPDF))
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Expected error %v, got %v", tc.wantErr, err)
+			}
+
+			if len(achievementRepo.releasedReservations) != 1 || len(achievementRepo.reservations) != 0 {
+				t.Errorf("Expected reservation released, got reservations %v released %v", achievementRepo.reservations, achievementRepo.releasedReservations)
+			}
+		})
+	}
+}
+
+func TestUploadFile_UnlimitedQuotaSkipsUsage(t *testing.T) {
+	service := newAttachmentTestService(&mockAchievementRepo{studentUsage: 1 << 40}, modelpostgre.AchievementStatusDraft, &mockFileStorage{})
+
+	if _, err := service.UploadFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "a.pdf", "application/pdf", int64(len(attachmentTestPDF)), strings.NewReader(attachmentTestPDF)); err != nil {
+		t.Errorf("Expected no error without quota, got %v", err)
+	}
+}
+
+func TestReplaceAttachment_QuotaExcludesReplacedFile(t *testing.T) {
+	content := "%PDF-1.7\nrevisi\n%%EOF\n"
+	config := testAttachmentConfig()
+	config.StudentQuota = 70
+
+	achievementRepo := newAttachmentChangeTestRepo()
+	achievementRepo.byID.Attachments[0].Size = 40
+	achievementRepo.studentUsage = 60
+	service := newAttachmentTestServiceWithConfig(achievementRepo, modelpostgre.AchievementStatusDraft, newAttachmentChangeTestStorage(), config)
+
+	// 60 - 40 + 22 masih di bawah kuota 70
+	if _, err := service.ReplaceAttachment(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "att-1", "revisi.pdf", "application/pdf", int64(len(content)), strings.NewReader(content)); err != nil {
+		t.Fatalf("Expected replacement within quota, got %v", err)
+	}
+
+	// upload baru dihitung penuh, 60 + 22 melebihi kuota
+	achievementRepo = newAttachmentChangeTestRepo()
+	achievementRepo.studentUsage = 60
+	service = newAttachmentTestServiceWithConfig(achievementRepo, modelpostgre.AchievementStatusDraft, newAttachmentChangeTestStorage(), config)
+	var quotaErr *modelmongo.AttachmentQuotaError
+	if _, err := service.UploadFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "revisi.pdf", "application/pdf", int64(len(content)), strings.NewReader(content)); !errors.As(err, &quotaErr) {
+		t.Errorf("Expected quota error for new upload, got %v", err)
+	}
+}
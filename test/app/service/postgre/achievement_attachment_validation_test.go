@@ -0,0 +1,167 @@
+package service_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+)
+
+func TestSanitizeAttachmentFileName(t *testing.T) {
+	cases := map[string]string{
+		"sertifikat lomba.pdf":            "sertifikat_lomba.pdf",
+		`C:\Users\budi\Sertifikat.PDF`:    "Sertifikat.pdf",
+		"../../etc/passwd.png":            "passwd.png",
+		".htaccess":                       "file.htaccess",
+		"piagam\u202egpj.exe.png":         "piagam_gpj.exe.png",
+		"juara   1 (nasional)!!.docx":     "juara_1_nasional.docx",
+		"Prestasi Ñandú.jpg":              "Prestasi_Ñandú.jpg",
+		"nama\x00file\r\n.pdf":            "nama_file.pdf",
+		strings.Repeat("a", 300) + ".pdf": strings.Repeat("a", 100) + ".pdf",
+		"tanpa-ekstensi":                  "tanpa-ekstensi",
+		"foto.p%n/g":                      "g",
+	}
+
+	for input, expected := range cases {
+		if got := servicepostgre.SanitizeAttachmentFileName(input); got != expected {
+			t.Errorf("SanitizeAttachmentFileName(%q) = %q, expected %q", input, got, expected)
+		}
+	}
+}
+
+func TestValidateAttachmentFile_SniffsContent(t *testing.T) {
+	cases := []struct {
+		name     string
+		content  string
+		fileType string
+		valid    bool
+	}{
+		{"a.pdf", "%PDF-1.5\n%%EOF", "application/pdf", true},
+		{"a.jpeg", "\xff\xd8\xff\xe0\x00\x10JFIF", "image/jpeg", true},
+		{"a.png", "\x89PNG\r\n\x1a\n\x00\x00", "image/png", true},
+		{"a.doc", "\xd0\xcf\x11\xe0\xa1\xb1\x1a\xe1\x00", "application/msword", true},
+		{"a.docx", "PK\x03\x04\x14\x00", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", true},
+		{"a.mp4", "\x00\x00\x00\x20ftypisom", "video/mp4", true},
+		{"a.mp4", "\x00\x00\x00\x20moov", "", false},
+		{"a.pdf", "MZ\x90\x00", "", false},
+		{"a.png", "\xff\xd8\xff\xe0", "", false},
+		{"a.docx", "%PDF-1.4", "", false},
+		{"a.exe", "MZ\x90\x00", "", false},
+		{"a.pdf", "", "", false},
+	}
+
+	for _, tc := range cases {
+		check, err := servicepostgre.ValidateAttachmentFile(tc.name, int64(len(tc.content)), strings.NewReader(tc.content))
+		if tc.valid {
+			if err != nil || check.FileType != tc.fileType {
+				t.Errorf("%s %q: expected %s, got %+v %v", tc.name, tc.content, tc.fileType, check, err)
+			}
+			continue
+		}
+
+		var validationErr *modelmongo.AttachmentValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("%s %q: expected validation error, got %v", tc.name, tc.content, err)
+		}
+	}
+}
+
+func TestValidateAttachmentFile_SizeLimitPerType(t *testing.T) {
+	_, err := servicepostgre.ValidateAttachmentFile("foto.jpg", 5*1024*1024+1, strings.NewReader("\xff\xd8\xff"))
+	var validationErr *modelmongo.AttachmentValidationError
+	if !errors.As(err, &validationErr) || !validationErr.TooLarge {
+		t.Errorf("Expected too large error for 5MB+ JPG, got %v", err)
+	}
+
+	if _, err := servicepostgre.ValidateAttachmentFile("laporan.pdf", 6*1024*1024, strings.NewReader("%PDF-1.4")); err != nil {
+		t.Errorf("Expected 6MB PDF allowed, got %v", err)
+	}
+
+	_, err = servicepostgre.ValidateAttachmentFile("laporan.pdf", 50*1024*1024+1, strings.NewReader("%PDF-1.4"))
+	if !errors.As(err, &validationErr) || !validationErr.TooLarge {
+		t.Errorf("Expected too large error for 50MB+ PDF, got %v", err)
+	}
+
+	if _, err := servicepostgre.ValidateAttachmentFile("video.mp4", 200*1024*1024, strings.NewReader("\x00\x00\x00\x18ftypmp42")); err != nil {
+		t.Errorf("Expected 200MB MP4 allowed, got %v", err)
+	}
+}
+
+func TestValidateAttachmentFile_DetectsPDFJavaScript(t *testing.T) {
+	cases := map[string]bool{
+		"<< /OpenAction << /S /JavaScript /JS (app.alert(1)) >> >>": true,
+		"<< /Names << /JavaScript 5 0 R >> >>":                      true,
+		"<</S/JS(x)>>":                                              true,
+		"<< /S /J#61vaScript >>":                                    true,
+		"<< /#4A#53 (x) >>":                                         true,
+		"%%EOF /JS":                                                 true,
+		"<< /JSON 1 /JavaScripts 2 /Font /F1 >>":                    false,
+		"(teks /JSX tentang JavaScript biasa)":                      false,
+	}
+
+	for body, hasJS := range cases {
+		content := "%PDF-1.7\n" + body
+		// baca satu byte per Read untuk memastikan nama yang terpotong antar chunk tetap terdeteksi
+		_, err := servicepostgre.ValidateAttachmentFile("a.pdf", int64(len(content)), iotest.OneByteReader(strings.NewReader(content)))
+		if hasJS && (err == nil || !strings.Contains(err.Error(), "JavaScript")) {
+			t.Errorf("%q: expected JavaScript rejection, got %v", body, err)
+		}
+		if !hasJS && err != nil {
+			t.Errorf("%q: expected valid PDF, got %v", body, err)
+		}
+	}
+}
+
+func testPDFObjectStream(t *testing.T, filter string, objects string) string {
+	t.Helper()
+	var compressed bytes.Buffer
+	writer := zlib.NewWriter(&compressed)
+	writer.Write([]byte(objects))
+	writer.Close()
+	return "%PDF-1.7\n1 0 obj\n<< /Type /Catalog /Pages 2 0 R /OpenAction 3 0 R >>\nendobj\n" +
+		"5 0 obj\n<< /Type /ObjStm /N 1 /First 4 " + filter + " >>\nstream\n" +
+		compressed.String() + "\nendstream\nendobj\n%%EOF\n"
+}
+
+func TestValidateAttachmentFile_DetectsJavaScriptInObjectStream(t *testing.T) {
+	cases := []struct {
+		name    string
+		filter  string
+		objects string
+		message string
+	}{
+		{"compressed OpenAction JavaScript", "/Filter /FlateDecode", "3 0 << /S /JavaScript /JS (app.alert(1)) >>", "JavaScript"},
+		{"hex escaped name", "/Filter [/FlateDecode]", "3 0 << /S /J#61vaScript >>", "JavaScript"},
+		{"unsupported filter", "/Filter [/ASCII85Decode /FlateDecode]", "3 0 << /S /URI >>", "filter"},
+	}
+
+	for _, tc := range cases {
+		content := testPDFObjectStream(t, tc.filter, tc.objects)
+		_, err := servicepostgre.ValidateAttachmentFile("a.pdf", int64(len(content)), iotest.OneByteReader(strings.NewReader(content)))
+		if err == nil || !strings.Contains(err.Error(), tc.message) {
+			t.Errorf("%s: expected rejection containing %q, got %v", tc.name, tc.message, err)
+		}
+	}
+}
+
+func TestValidateAttachmentFile_AcceptsCleanObjectStream(t *testing.T) {
+	content := testPDFObjectStream(t, "/Filter /FlateDecode", "2 0 << /Type /Pages /Kids [] /Count 0 >> 3 0 << /S /URI /URI (https://example.com) >>")
+
+	if _, err := servicepostgre.ValidateAttachmentFile("a.pdf", int64(len(content)), strings.NewReader(content)); err != nil {
+		t.Errorf("Expected valid PDF, got %v", err)
+	}
+}
+
+func TestValidateAttachmentFile_RejectsCorruptObjectStream(t *testing.T) {
+	content := "%PDF-1.7\n5 0 obj\n<< /Type /ObjStm /Filter /FlateDecode >>\nstream\nbukan zlib\nendstream\nendobj\n"
+
+	_, err := servicepostgre.ValidateAttachmentFile("a.pdf", int64(len(content)), strings.NewReader(content))
+	if err == nil || !strings.Contains(err.Error(), "object stream") {
+		t.Errorf("Expected corrupt object stream rejection, got %v", err)
+	}
+}
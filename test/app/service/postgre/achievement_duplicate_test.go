@@ -0,0 +1,390 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNormalizeDuplicateText(t *testing.T) {
+	testCases := map[string]string{
+		"Juara 1 GEMASTIK 2024!":  "juara 1 gemastik 2024",
+		"  Lomba   Web--Design  ": "lomba web design",
+		"Sertifikat: AWS/Cloud.":  "sertifikat aws cloud",
+		"":                        "",
+	}
+
+	for input, want := range testCases {
+		if got := servicepostgre.NormalizeDuplicateText(input); got != want {
+			t.Errorf("NormalizeDuplicateText(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestBuildDuplicateKeys(t *testing.T) {
+	eventDate := time.Date(2024, 10, 12, 9, 0, 0, 0, time.UTC)
+
+	achievement := &modelmongo.Achievement{
+		StudentID: "student-1",
+		Title:     "Juara 1 Gemastik!",
+		Details: modelmongo.AchievementDetails{
+			CompetitionName:     stringPtr("GEMASTIK  2024"),
+			EventDate:           &eventDate,
+			CertificationNumber: stringPtr("AWS-123 456"),
+		},
+		Attachments: []modelmongo.Attachment{
+			{FileName: "sertifikat.pdf", ContentHash: "ABCDEF"},
+			{FileName: "sertifikat-copy.pdf", ContentHash: "abcdef"},
+		},
+	}
+
+	keys := servicepostgre.BuildDuplicateKeys(achievement)
+
+	expected := []string{
+		"title:student-1:juara 1 gemastik",
+		"competition:gemastik 2024:2024-10-12",
+		"certificationNumber:aws123456",
+		"attachment:abcdef",
+	}
+
+	if len(keys) != len(expected) {
+		t.Fatalf("Expected keys %v, got %v", expected, keys)
+	}
+
+	for i, key := range expected {
+		if keys[i] != key {
+			t.Errorf("Expected key #%d to be %q, got %q", i+1, key, keys[i])
+		}
+	}
+}
+
+func TestCreateAchievement_DuplicateWarningsRedactOtherStudents(t *testing.T) {
+	ctx := setupTestContext()
+
+	eventDate := time.Date(2024, 10, 12, 0, 0, 0, 0, time.UTC)
+	studentID := "550e8400-e29b-41d4-a716-446655440000"
+
+	ownID := primitive.NewObjectID()
+
+	mockAchievementRepo := &mockAchievementRepo{
+		duplicateMatches: []modelmongo.Achievement{
+			{
+				ID:              primitive.NewObjectID(),
+				StudentID:       "660e8400-e29b-41d4-a716-446655440000",
+				AchievementType: "competition",
+				Title:           "Juara 1 Gemastik Tim",
+				DuplicateKeys:   []string{"competition:gemastik:2024-10-12"},
+			},
+			{
+				ID:              ownID,
+				StudentID:       studentID,
+				AchievementType: "competition",
+				Title:           "Juara 1 Gemastik",
+				DuplicateKeys:   []string{"title:" + studentID + ":juara 1 gemastik", "competition:gemastik:2024-10-12"},
+			},
+		},
+	}
+
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		byMongoIDs: []modelpostgre.AchievementReference{
+			{ID: "ref-id-1", MongoAchievementID: ownID.Hex(), Status: modelpostgre.AchievementStatusVerified},
+		},
+	}
+
+	service := servicepostgre.NewAchievementService(
+		mockAchievementRepo,
+		mockAchievementRefRepo,
+		&mockUserRepo{roleName: "Mahasiswa"},
+		&mockStudentRepo{
+			studentIDByUserID: studentID,
+			byID:              &modelpostgre.Student{ID: studentID},
+		},
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	result, err := service.CreateAchievement(ctx, "user-id-1", "role-id-1", modelmongo.CreateAchievementRequest{
+		AchievementType: "competition",
+		Title:           "Juara 1 Gemastik",
+		Description:     "Juara 1 kategori pengembangan perangkat lunak",
+		Details: modelmongo.AchievementDetails{
+			CompetitionName:  stringPtr("Gemastik"),
+			CompetitionLevel: stringPtr(modelmongo.CompetitionLevelNational),
+			EventDate:        &eventDate,
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.DuplicateWarnings) != 2 {
+		t.Fatalf("Expected 2 duplicate warnings, got %+v", result.DuplicateWarnings)
+	}
+
+	other := result.DuplicateWarnings[0]
+	if other.SameStudent || other.AchievementID != "" || other.Title != "" || other.StudentID != "" {
+		t.Errorf("Expected redacted warning for other student, got %+v", other)
+	}
+
+	if len(other.MatchedOn) != 1 || other.MatchedOn[0] != modelmongo.DuplicateMatchCompetition {
+		t.Errorf("Expected match on competition, got %v", other.MatchedOn)
+	}
+
+	own := result.DuplicateWarnings[1]
+	if !own.SameStudent || own.Title != "Juara 1 Gemastik" || own.Status != modelpostgre.AchievementStatusVerified {
+		t.Errorf("Expected full warning for own achievement, got %+v", own)
+	}
+
+	if len(own.MatchedOn) != 2 {
+		t.Errorf("Expected match on competition and title, got %v", own.MatchedOn)
+	}
+
+	if len(mockAchievementRefRepo.mongoIDLookups) != 1 || len(mockAchievementRefRepo.mongoIDLookups[0]) != 2 {
+		t.Errorf("Expected one batched reference lookup for 2 candidates, got %v", mockAchievementRefRepo.mongoIDLookups)
+	}
+}
+
+func TestGetAchievementByID_SkipsDeletedDuplicates(t *testing.T) {
+	ctx := setupTestContext()
+
+	studentID := "550e8400-e29b-41d4-a716-446655440000"
+	deletedID := primitive.NewObjectID()
+	activeID := primitive.NewObjectID()
+
+	mockAchievementRepo := &mockAchievementRepo{
+		byID: &modelmongo.Achievement{
+			ID:              primitive.NewObjectID(),
+			StudentID:       studentID,
+			AchievementType: "certification",
+			Title:           "AWS Cloud Practitioner",
+			Details: modelmongo.AchievementDetails{
+				CertificationNumber: stringPtr("AWS-123"),
+			},
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		duplicateMatches: []modelmongo.Achievement{
+			{ID: deletedID, StudentID: studentID, AchievementType: "certification", Title: "AWS Lama", DuplicateKeys: []string{"certificationNumber:aws123"}},
+			{ID: activeID, StudentID: studentID, AchievementType: "certification", Title: "AWS Baru", DuplicateKeys: []string{"certificationNumber:aws123"}},
+		},
+	}
+
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		byMongoID: &modelpostgre.AchievementReference{
+			ID:                 "ref-id-1",
+			StudentID:          studentID,
+			MongoAchievementID: "mongo-id-1",
+			Status:             modelpostgre.AchievementStatusDraft,
+		},
+		byMongoIDs: []modelpostgre.AchievementReference{
+			{ID: "ref-id-2", MongoAchievementID: deletedID.Hex(), Status: modelpostgre.AchievementStatusDeleted},
+			{ID: "ref-id-3", MongoAchievementID: activeID.Hex(), Status: modelpostgre.AchievementStatusSubmitted},
+		},
+	}
+
+	service := servicepostgre.NewAchievementService(
+		mockAchievementRepo,
+		mockAchievementRefRepo,
+		&mockUserRepo{roleName: "Admin"},
+		&mockStudentRepo{byID: &modelpostgre.Student{ID: studentID}},
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	result, err := service.GetAchievementByID(ctx, "user-id-1", "role-id-1", "mongo-id-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data := result["data"].(map[string]interface{})
+	duplicates, ok := data["duplicates"].([]modelmongo.DuplicateCandidate)
+	if !ok || len(duplicates) != 1 || duplicates[0].AchievementID != activeID.Hex() || duplicates[0].Status != modelpostgre.AchievementStatusSubmitted {
+		t.Fatalf("Expected only the active duplicate, got %+v", data["duplicates"])
+	}
+
+	if len(mockAchievementRefRepo.mongoIDLookups) != 1 {
+		t.Errorf("Expected one batched reference lookup, got %d", len(mockAchievementRefRepo.mongoIDLookups))
+	}
+}
+
+func TestGetAchievementByID_FlagsDuplicatesForAdvisor(t *testing.T) {
+	ctx := setupTestContext()
+
+	studentID := "550e8400-e29b-41d4-a716-446655440000"
+
+	mockAchievementRepo := &mockAchievementRepo{
+		byID: &modelmongo.Achievement{
+			ID:              primitive.NewObjectID(),
+			StudentID:       studentID,
+			AchievementType: "certification",
+			Title:           "AWS Cloud Practitioner",
+			Details: modelmongo.AchievementDetails{
+				CertificationNumber: stringPtr("AWS-123"),
+			},
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		},
+		duplicateMatches: []modelmongo.Achievement{
+			{
+				ID:              primitive.NewObjectID(),
+				StudentID:       "660e8400-e29b-41d4-a716-446655440000",
+				AchievementType: "certification",
+				Title:           "Sertifikat AWS",
+				DuplicateKeys:   []string{"certificationNumber:aws123"},
+			},
+		},
+	}
+
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		byMongoID: &modelpostgre.AchievementReference{
+			ID:                 "ref-id-1",
+			StudentID:          studentID,
+			MongoAchievementID: "mongo-id-1",
+			Status:             modelpostgre.AchievementStatusSubmitted,
+		},
+	}
+
+	service := servicepostgre.NewAchievementService(
+		mockAchievementRepo,
+		mockAchievementRefRepo,
+		&mockUserRepo{
+			roleName:         "Dosen Wali",
+			lecturerByUserID: &modelpostgre.Lecturer{ID: "lecturer-id-1"},
+		},
+		&mockStudentRepo{
+			byID: &modelpostgre.Student{ID: studentID, AdvisorID: "lecturer-id-1"},
+		},
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	result, err := service.GetAchievementByID(ctx, "user-id-1", "role-id-1", "mongo-id-1")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data := result["data"].(map[string]interface{})
+	duplicates, ok := data["duplicates"].([]modelmongo.DuplicateCandidate)
+	if !ok || len(duplicates) != 1 {
+		t.Fatalf("Expected 1 duplicate flag, got %+v", data["duplicates"])
+	}
+
+	if duplicates[0].Title != "Sertifikat AWS" || duplicates[0].MatchedOn[0] != modelmongo.DuplicateMatchCertificationNumber {
+		t.Errorf("Expected unredacted certification number match, got %+v", duplicates[0])
+	}
+}
+
+func TestGetDuplicateReport_Success(t *testing.T) {
+	ctx := setupTestContext()
+
+	firstID := primitive.NewObjectID()
+	secondID := primitive.NewObjectID()
+
+	mockAchievementRepo := &mockReportServiceAchievementRepo{
+		duplicateGroups: []repositorymongo.DuplicateKeyGroup{
+			{
+				Key:            "attachment:abcdef",
+				AchievementIDs: []string{firstID.Hex(), secondID.Hex()},
+				Count:          2,
+			},
+		},
+		byIDs: []modelmongo.Achievement{
+			{ID: firstID, StudentID: "student-1", Title: "Sertifikat A", DuplicateKeys: []string{"attachment:abcdef"}},
+			{ID: secondID, StudentID: "student-2", Title: "Sertifikat B", DuplicateKeys: []string{"attachment:abcdef"}},
+		},
+	}
+
+	mockAchievementRefRepo := &mockReportServiceAchievementRefRepo{
+		byMongoIDs: []modelpostgre.AchievementReference{
+			{ID: "ref-id-1", MongoAchievementID: firstID.Hex(), Status: modelpostgre.AchievementStatusVerified},
+			{ID: "ref-id-2", MongoAchievementID: secondID.Hex(), Status: modelpostgre.AchievementStatusSubmitted},
+		},
+	}
+
+	service := servicepostgre.NewReportService(
+		mockAchievementRepo,
+		mockAchievementRefRepo,
+		&mockReportServiceStudentRepo{},
+		&mockReportServiceUserRepo{roleName: "Admin"},
+		&mockReportServiceLecturerRepo{},
+		newTestCertificationConfig(),
+	)
+
+	result, err := service.GetDuplicateReport(ctx, "user-id-1", "role-id-1")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data := result["data"].(map[string]interface{})
+	groups := data["groups"].([]modelmongo.DuplicateGroup)
+
+	if len(groups) != 1 {
+		t.Fatalf("Expected 1 duplicate group, got %+v", groups)
+	}
+
+	if groups[0].MatchedOn != modelmongo.DuplicateMatchAttachment || groups[0].Value != "abcdef" {
+		t.Errorf("Expected attachment group with value abcdef, got %+v", groups[0])
+	}
+
+	if len(groups[0].Achievements) != 2 || groups[0].Achievements[0].SameStudent {
+		t.Errorf("Expected 2 achievements from different students, got %+v", groups[0].Achievements)
+	}
+
+	if groups[0].Achievements[0].Status != modelpostgre.AchievementStatusVerified {
+		t.Errorf("Expected status from batched references, got %+v", groups[0].Achievements[0])
+	}
+
+	if mockAchievementRefRepo.mongoIDLookups != 1 {
+		t.Errorf("Expected one batched reference lookup, got %d", mockAchievementRefRepo.mongoIDLookups)
+	}
+}
+
+func TestGetDuplicateReport_NotAdmin(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := servicepostgre.NewReportService(
+		&mockReportServiceAchievementRepo{},
+		&mockReportServiceAchievementRefRepo{},
+		&mockReportServiceStudentRepo{},
+		&mockReportServiceUserRepo{roleName: "Dosen Wali"},
+		&mockReportServiceLecturerRepo{},
+		newTestCertificationConfig(),
+	)
+
+	_, err := service.GetDuplicateReport(ctx, "user-id-1", "role-id-1")
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if !contains(err.Error(), "akses ditolak") {
+		t.Errorf("Expected 'akses ditolak' error, got: %v", err)
+	}
+}
@@ -0,0 +1,534 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newListRow(mongoID string, status string, programStudy string, advisorID string, advisorName string) modelpostgre.AchievementListRow {
+	return modelpostgre.AchievementListRow{
+		AchievementReference: modelpostgre.AchievementReference{
+			ID:                 "ref-" + mongoID,
+			StudentID:          "student-id-1",
+			MongoAchievementID: mongoID,
+			Status:             status,
+		},
+		StudentName:  "Budi",
+		ProgramStudy: programStudy,
+		AcademicYear: "2022",
+		AdvisorID:    advisorID,
+		AdvisorName:  advisorName,
+	}
+}
+
+func newListService(achievementRepo *mockAchievementRepo, refRepo *mockAchievementRefRepo, userRepo *mockUserRepo, studentRepo *mockStudentRepo) servicepostgre.IAchievementService {
+	return servicepostgre.NewAchievementService(
+		achievementRepo,
+		refRepo,
+		userRepo,
+		studentRepo,
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+}
+
+func TestGetAchievements_StudentScope(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRefRepo := &mockAchievementRefRepo{}
+	service := newListService(
+		&mockAchievementRepo{},
+		mockAchievementRefRepo,
+		&mockUserRepo{roleName: "Mahasiswa"},
+		&mockStudentRepo{byUserID: &modelpostgre.Student{ID: "student-id-1"}},
+	)
+
+	// scope dari request diabaikan, mahasiswa selalu dibatasi ke prestasi sendiri
+	_, err := service.GetAchievements(ctx, "user-id-1", "role-id-1", modelpostgre.AchievementListFilter{ScopeAdvisorID: "lecturer-id-9"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockAchievementRefRepo.listFilter.ScopeStudentID != "student-id-1" || mockAchievementRefRepo.listFilter.ScopeAdvisorID != "" {
+		t.Errorf("Expected scope to own student only, got %+v", mockAchievementRefRepo.listFilter)
+	}
+}
+
+func TestGetAchievements_AdvisorScope(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRefRepo := &mockAchievementRefRepo{}
+	service := newListService(
+		&mockAchievementRepo{},
+		mockAchievementRefRepo,
+		&mockUserRepo{roleName: "Dosen Wali", lecturerByUserID: &modelpostgre.Lecturer{ID: "lecturer-id-1"}},
+		&mockStudentRepo{},
+	)
+
+	if _, err := service.GetAchievements(ctx, "user-id-1", "role-id-1", modelpostgre.AchievementListFilter{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockAchievementRefRepo.listFilter.ScopeAdvisorID != "lecturer-id-1" {
+		t.Errorf("Expected scope to advisor lecturer-id-1, got %+v", mockAchievementRefRepo.listFilter)
+	}
+}
+
+func TestGetAchievements_ForwardsFiltersToMongo(t *testing.T) {
+	ctx := setupTestContext()
+
+	firstID := primitive.NewObjectID().Hex()
+	secondID := primitive.NewObjectID().Hex()
+	mockAchievementRepo := &mockAchievementRepo{}
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		listRows: []modelpostgre.AchievementListRow{
+			newListRow(firstID, modelpostgre.AchievementStatusVerified, "Teknik Informatika", "lecturer-id-1", "Dr. Sari"),
+			newListRow(secondID, modelpostgre.AchievementStatusSubmitted, "Sistem Informasi", "lecturer-id-1", "Dr. Sari"),
+		},
+	}
+	service := newListService(mockAchievementRepo, mockAchievementRefRepo, &mockUserRepo{roleName: "Admin"}, &mockStudentRepo{})
+
+	dateTo := time.Date(2025, 6, 30, 0, 0, 0, 0, time.UTC)
+	minPoints := 10
+	hasAttachments := true
+	_, err := service.GetAchievements(ctx, "admin-id", "role-id-1", modelpostgre.AchievementListFilter{
+		Page:              2,
+		Limit:             5,
+		ProgramStudies:    []string{"Teknik Informatika", "Sistem Informasi"},
+		CompetitionLevels: []string{"national", "international"},
+		Tags:              []string{"robotik"},
+		DateTo:            &dateTo,
+		MinPoints:         &minPoints,
+		HasAttachments:    &hasAttachments,
+		SortBy:            "points",
+		SortOrder:         "ASC",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockAchievementRefRepo.facetRowFilter.ProgramStudies) != 2 {
+		t.Errorf("Expected program study filter passed to PostgreSQL, got %+v", mockAchievementRefRepo.facetRowFilter)
+	}
+
+	mongoFilter := mockAchievementRepo.facetFilter
+	if mongoFilter == nil {
+		t.Fatal("Expected MongoDB query to run")
+	}
+
+	if len(mongoFilter.IDs) != 2 || mongoFilter.IDs[0] != firstID || mongoFilter.IDs[1] != secondID {
+		t.Errorf("Expected IDs of the PostgreSQL batch, got %v", mongoFilter.IDs)
+	}
+
+	// sort field MongoDB mengambil kandidat sampai akhir halaman yang diminta dari setiap batch
+	if mongoFilter.Limit != 10 {
+		t.Errorf("Expected candidate limit 10, got %d", mongoFilter.Limit)
+	}
+
+	if mongoFilter.SortField != "points" || mongoFilter.SortDescending {
+		t.Errorf("Expected ascending sort on points, got %q descending %v", mongoFilter.SortField, mongoFilter.SortDescending)
+	}
+
+	if mongoFilter.DateTo == nil || !mongoFilter.DateTo.Equal(time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected exclusive dateTo 2025-07-01, got %v", mongoFilter.DateTo)
+	}
+
+	if len(mongoFilter.CompetitionLevels) != 2 || len(mongoFilter.Tags) != 1 || mongoFilter.MinPoints == nil || mongoFilter.HasAttachments == nil {
+		t.Errorf("Expected MongoDB filters forwarded, got %+v", mongoFilter)
+	}
+
+	if len(mockAchievementRefRepo.listFilters) != 0 {
+		t.Errorf("Expected no PostgreSQL page query for MongoDB sort, got %d", len(mockAchievementRefRepo.listFilters))
+	}
+}
+
+func TestGetAchievements_FacetsAndPagination(t *testing.T) {
+	ctx := setupTestContext()
+
+	matched := primitive.NewObjectID()
+	otherStatus := primitive.NewObjectID().Hex()
+	matchedRow := newListRow(matched.Hex(), modelpostgre.AchievementStatusVerified, "Teknik Informatika", "lecturer-id-1", "Dr. Sari")
+	mockAchievementRepo := &mockAchievementRepo{
+		facetResult: &repositorymongo.AchievementFacetResult{
+			Total:      11,
+			MatchedIDs: []string{matched.Hex()},
+			Facets: map[string][]modelmongo.FacetCount{
+				modelmongo.FacetAchievementType: {{Value: "competition", Count: 11}},
+			},
+		},
+		byIDs: []modelmongo.Achievement{{ID: matched, StudentID: "student-id-1", Title: "Juara 1 Robotik", AchievementType: "competition"}},
+	}
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		listRows: []modelpostgre.AchievementListRow{
+			matchedRow,
+			newListRow(otherStatus, modelpostgre.AchievementStatusDraft, "Sistem Informasi", "", ""),
+		},
+		listRowBatches: [][]modelpostgre.AchievementListRow{{matchedRow}},
+	}
+	service := newListService(mockAchievementRepo, mockAchievementRefRepo, &mockUserRepo{roleName: "Admin"}, &mockStudentRepo{})
+
+	result, err := service.GetAchievements(ctx, "admin-id", "role-id-1", modelpostgre.AchievementListFilter{
+		Page:     1,
+		Limit:    10,
+		Statuses: []string{modelpostgre.AchievementStatusVerified},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data := result["data"].([]map[string]interface{})
+	if len(data) != 1 || data[0]["student_name"] != "Budi" || data[0]["status"] != modelpostgre.AchievementStatusVerified {
+		t.Errorf("Expected one verified item with student name, got %+v", data)
+	}
+
+	pagination := result["pagination"].(map[string]interface{})
+	if pagination["total"] != 11 || pagination["total_pages"] != 2 {
+		t.Errorf("Expected total from MongoDB count, got %+v", pagination)
+	}
+
+	// MongoDB hanya menerima reference yang lolos semua filter PostgreSQL
+	if ids := mockAchievementRepo.facetFilter.IDs; len(ids) != 1 || ids[0] != matched.Hex() {
+		t.Errorf("Expected facet query only for matched reference, got %v", ids)
+	}
+
+	facets := result["facets"].(map[string][]modelmongo.FacetCount)
+	if len(facets[modelmongo.FacetAchievementType]) != 1 {
+		t.Errorf("Expected MongoDB facet passed through, got %+v", facets[modelmongo.FacetAchievementType])
+	}
+
+	// facet status tidak memakai filter status sendiri sehingga pilihan lain tetap terlihat
+	status := facets[modelpostgre.FacetStatus]
+	if len(status) != 2 {
+		t.Errorf("Expected status facet without own filter, got %+v", status)
+	}
+
+	// dimensi lain tetap memakai filter status
+	programStudy := facets[modelpostgre.FacetProgramStudy]
+	if len(programStudy) != 1 || programStudy[0].Value != "Teknik Informatika" || programStudy[0].Count != 1 {
+		t.Errorf("Expected program study facet only for matched achievement, got %+v", programStudy)
+	}
+
+	advisor := facets[modelpostgre.FacetAdvisor]
+	if len(advisor) != 1 || advisor[0].Label != "Dr. Sari" {
+		t.Errorf("Expected advisor facet labelled with name, got %+v", advisor)
+	}
+
+	if mockAchievementRefRepo.listFilter.RowLimit != 10 || mockAchievementRefRepo.listFilter.RowOffset != 0 {
+		t.Errorf("Expected page limited in PostgreSQL, got %+v", mockAchievementRefRepo.listFilter)
+	}
+}
+
+func TestGetAchievements_PushesPageDownToPostgres(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRepo := &mockAchievementRepo{}
+	mockAchievementRefRepo := &mockAchievementRefRepo{}
+	service := newListService(mockAchievementRepo, mockAchievementRefRepo, &mockUserRepo{roleName: "Admin"}, &mockStudentRepo{})
+
+	if _, err := service.GetAchievements(ctx, "admin-id", "role-id-1", modelpostgre.AchievementListFilter{
+		Page:     3,
+		Limit:    5,
+		Statuses: []string{modelpostgre.AchievementStatusSubmitted},
+		SortBy:   "submitted_at",
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockAchievementRefRepo.listFilters) != 1 || mockAchievementRefRepo.listFilter.RowOffset != 10 || mockAchievementRefRepo.listFilter.RowLimit != 5 {
+		t.Errorf("Expected one PostgreSQL query with offset 10 limit 5, got %+v", mockAchievementRefRepo.listFilters)
+	}
+
+	if len(mockAchievementRepo.matchFilters) != 0 {
+		t.Errorf("Expected no MongoDB filtering without MongoDB filters, got %d", len(mockAchievementRepo.matchFilters))
+	}
+}
+
+func TestGetAchievements_PageWithMongoFiltersWalksBatches(t *testing.T) {
+	ctx := setupTestContext()
+
+	achievements := []modelmongo.Achievement{}
+	rows := []modelpostgre.AchievementListRow{}
+	for i := 0; i < 3; i++ {
+		achievement := modelmongo.Achievement{ID: primitive.NewObjectID()}
+		achievements = append(achievements, achievement)
+		rows = append(rows, newListRow(achievement.ID.Hex(), modelpostgre.AchievementStatusVerified, "Teknik Informatika", "", ""))
+	}
+
+	mockAchievementRepo := &mockAchievementRepo{
+		matchingIDs: map[string]bool{rows[1].MongoAchievementID: true, rows[2].MongoAchievementID: true},
+		byIDs:       []modelmongo.Achievement{achievements[2]},
+	}
+	mockAchievementRefRepo := &mockAchievementRefRepo{listRowBatches: [][]modelpostgre.AchievementListRow{rows[:2], rows[2:]}}
+	service := newListService(mockAchievementRepo, mockAchievementRefRepo, &mockUserRepo{roleName: "Admin"}, &mockStudentRepo{})
+
+	result, err := service.GetAchievements(ctx, "admin-id", "role-id-1", modelpostgre.AchievementListFilter{
+		Page:  2,
+		Limit: 1,
+		Tags:  []string{"robotik"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// halaman kedua berarti item kedua yang lolos MongoDB, batch berikutnya diambil setelah offset batch pertama
+	filters := mockAchievementRefRepo.listFilters
+	if len(filters) != 2 || filters[0].RowLimit != 2 || filters[1].RowOffset != 2 {
+		t.Fatalf("Expected 2 PostgreSQL batches advancing by offset, got %+v", filters)
+	}
+
+	data := result["data"].([]map[string]interface{})
+	if len(data) != 1 || data[0]["id"] != achievements[2].ID.Hex() {
+		t.Errorf("Expected second matched achievement, got %v", data)
+	}
+}
+
+func TestGetAchievements_MongoSortMergesBatches(t *testing.T) {
+	ctx := setupTestContext()
+
+	firstBatch := []modelpostgre.AchievementListRow{}
+	base := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	for i := 0; i < 1000; i++ {
+		row := newListRow(primitive.NewObjectID().Hex(), modelpostgre.AchievementStatusVerified, "Teknik Informatika", "", "")
+		row.CreatedAt = base.Add(time.Duration(i) * time.Minute)
+		firstBatch = append(firstBatch, row)
+	}
+	low := modelmongo.Achievement{ID: primitive.NewObjectID(), Points: 10}
+	high := modelmongo.Achievement{ID: primitive.NewObjectID(), Points: 90}
+	firstBatch[0].MongoAchievementID = low.ID.Hex()
+	secondBatch := []modelpostgre.AchievementListRow{newListRow(high.ID.Hex(), modelpostgre.AchievementStatusVerified, "Teknik Informatika", "", "")}
+
+	mockAchievementRepo := &mockAchievementRepo{
+		facetResults: []*repositorymongo.AchievementFacetResult{
+			{Achievements: []modelmongo.Achievement{low}, Total: 1000},
+			{Achievements: []modelmongo.Achievement{high}, Total: 1},
+		},
+		byIDs: []modelmongo.Achievement{low},
+	}
+	mockAchievementRefRepo := &mockAchievementRefRepo{facetRowBatches: [][]modelpostgre.AchievementListRow{firstBatch, secondBatch}}
+	service := newListService(mockAchievementRepo, mockAchievementRefRepo, &mockUserRepo{roleName: "Admin"}, &mockStudentRepo{})
+
+	result, err := service.GetAchievements(ctx, "admin-id", "role-id-1", modelpostgre.AchievementListFilter{Page: 2, Limit: 1, SortBy: "points"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	afters := mockAchievementRefRepo.facetRowAfters
+	if len(afters) != 2 || afters[0] != nil || afters[1] == nil || afters[1].ID != firstBatch[999].MongoAchievementID {
+		t.Fatalf("Expected second batch after last reference of first batch, got %+v", afters)
+	}
+
+	// points tertinggi dari batch kedua ada di halaman pertama, halaman kedua berisi points berikutnya dari batch pertama
+	data := result["data"].([]map[string]interface{})
+	if len(data) != 1 || data[0]["id"] != low.ID.Hex() {
+		t.Errorf("Expected lower points achievement on second page, got %v", data)
+	}
+
+	if total := result["pagination"].(map[string]interface{})["total"]; total != 1001 {
+		t.Errorf("Expected total summed across batches, got %v", total)
+	}
+}
+
+func TestGetAchievements_NoReferencesSkipsMongo(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRepo := &mockAchievementRepo{}
+	service := newListService(mockAchievementRepo, &mockAchievementRefRepo{}, &mockUserRepo{roleName: "Admin"}, &mockStudentRepo{})
+
+	result, err := service.GetAchievements(ctx, "admin-id", "role-id-1", modelpostgre.AchievementListFilter{Statuses: []string{"rejected"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockAchievementRepo.facetFilter != nil {
+		t.Error("Expected MongoDB query skipped when no reference matches")
+	}
+
+	if data := result["data"].([]map[string]interface{}); len(data) != 0 {
+		t.Errorf("Expected empty data, got %+v", data)
+	}
+}
+
+func TestGetAchievements_UnknownRole(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := newListService(&mockAchievementRepo{}, &mockAchievementRefRepo{}, &mockUserRepo{roleName: "Tamu"}, &mockStudentRepo{})
+
+	_, err := service.GetAchievements(ctx, "user-id-1", "role-id-1", modelpostgre.AchievementListFilter{})
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
+
+func TestGetAchievements_CursorMode(t *testing.T) {
+	ctx := setupTestContext()
+
+	base := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	achievements := []modelmongo.Achievement{
+		{ID: primitive.NewObjectID(), Title: "Prestasi 3", CreatedAt: base},
+		{ID: primitive.NewObjectID(), Title: "Prestasi 2", CreatedAt: base.Add(-time.Hour)},
+		{ID: primitive.NewObjectID(), Title: "Prestasi 1", CreatedAt: base.Add(-2 * time.Hour)},
+	}
+	rows := []modelpostgre.AchievementListRow{}
+	for i, achievement := range achievements {
+		row := newListRow(achievement.ID.Hex(), modelpostgre.AchievementStatusVerified, "Teknik Informatika", "", "")
+		row.CreatedAt = base.Add(-time.Duration(i) * time.Minute)
+		rows = append(rows, row)
+	}
+
+	mockAchievementRepo := &mockAchievementRepo{byIDs: achievements}
+	mockRefRepo := &mockAchievementRefRepo{listRows: rows}
+	service := newListService(mockAchievementRepo, mockRefRepo, &mockUserRepo{roleName: "Admin"}, &mockStudentRepo{})
+
+	cursor := &modelpostgre.PageCursor{CreatedAt: base.Add(time.Hour), ID: primitive.NewObjectID().Hex()}
+	result, err := service.GetAchievements(ctx, "admin-id", "role-id-1", modelpostgre.AchievementListFilter{
+		Page:       3,
+		Limit:      2,
+		CursorMode: true,
+		Cursor:     cursor,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockRefRepo.listFilters) != 1 || mockRefRepo.listFilter.RowLimit != 3 || mockRefRepo.listFilter.Cursor != cursor {
+		t.Errorf("Expected one PostgreSQL batch with keyset and limit+1, got %d batches %+v", len(mockRefRepo.listFilters), mockRefRepo.listFilter)
+	}
+
+	if len(mockAchievementRepo.matchFilters) != 0 || mockAchievementRepo.facetFilter != nil {
+		t.Error("Expected no MongoDB filtering, total, or facets in cursor mode without MongoDB filters")
+	}
+
+	data := result["data"].([]map[string]interface{})
+	if len(data) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(data))
+	}
+	if _, exists := result["facets"]; exists {
+		t.Error("Expected no facets in cursor mode")
+	}
+
+	pagination := result["pagination"].(modelpostgre.CursorPagination)
+	if !pagination.HasMore {
+		t.Error("Expected has_more")
+	}
+
+	createdAt, id, err := helper.DecodeCursor(pagination.NextCursor)
+	if err != nil || id != achievements[1].ID.Hex() || !createdAt.Equal(rows[1].CreatedAt) {
+		t.Errorf("Expected next_cursor at second reference, got %s %v %v", id, createdAt, err)
+	}
+}
+
+func TestGetAchievements_CursorFetchesNextBatchWhenMongoFiltersRows(t *testing.T) {
+	ctx := setupTestContext()
+
+	base := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	achievements := []modelmongo.Achievement{}
+	rows := []modelpostgre.AchievementListRow{}
+	for i := 0; i < 5; i++ {
+		achievement := modelmongo.Achievement{ID: primitive.NewObjectID(), CreatedAt: base.Add(-time.Duration(i) * time.Hour)}
+		row := newListRow(achievement.ID.Hex(), modelpostgre.AchievementStatusVerified, "Teknik Informatika", "", "")
+		row.CreatedAt = achievement.CreatedAt
+		achievements = append(achievements, achievement)
+		rows = append(rows, row)
+	}
+
+	mockAchievementRepo := &mockAchievementRepo{
+		matchingIDs: map[string]bool{achievements[0].ID.Hex(): true, achievements[4].ID.Hex(): true},
+		byIDs:       []modelmongo.Achievement{achievements[0], achievements[4]},
+	}
+	mockRefRepo := &mockAchievementRefRepo{listRowBatches: [][]modelpostgre.AchievementListRow{rows[:3], rows[3:]}}
+	service := newListService(mockAchievementRepo, mockRefRepo, &mockUserRepo{roleName: "Admin"}, &mockStudentRepo{})
+
+	result, err := service.GetAchievements(ctx, "admin-id", "role-id-1", modelpostgre.AchievementListFilter{Limit: 2, CursorMode: true, Tags: []string{"robotik"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockRefRepo.listFilters) != 2 {
+		t.Fatalf("Expected 2 PostgreSQL batches, got %d", len(mockRefRepo.listFilters))
+	}
+	next := mockRefRepo.listFilters[1].Cursor
+	if next == nil || next.ID != rows[2].MongoAchievementID || !next.CreatedAt.Equal(rows[2].CreatedAt) {
+		t.Errorf("Expected second batch after last row of first batch, got %+v", next)
+	}
+
+	data := result["data"].([]map[string]interface{})
+	if len(data) != 2 || data[1]["id"] != achievements[4].ID.Hex() {
+		t.Errorf("Expected items from both batches, got %v", data)
+	}
+	if result["pagination"].(modelpostgre.CursorPagination).HasMore {
+		t.Error("Expected no more pages after short batch")
+	}
+}
+
+func TestGetAchievements_CursorStopsAtScanLimit(t *testing.T) {
+	ctx := setupTestContext()
+
+	base := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	rows := []modelpostgre.AchievementListRow{}
+	for i := 0; i < 1000; i++ {
+		row := newListRow(primitive.NewObjectID().Hex(), modelpostgre.AchievementStatusVerified, "Teknik Informatika", "", "")
+		row.CreatedAt = base.Add(-time.Duration(i) * time.Minute)
+		rows = append(rows, row)
+	}
+
+	// tidak ada reference yang lolos filter MongoDB, request berhenti setelah batas baca
+	mockAchievementRepo := &mockAchievementRepo{matchingIDs: map[string]bool{}}
+	mockRefRepo := &mockAchievementRefRepo{listRows: rows}
+	service := newListService(mockAchievementRepo, mockRefRepo, &mockUserRepo{roleName: "Admin"}, &mockStudentRepo{})
+
+	result, err := service.GetAchievements(ctx, "admin-id", "role-id-1", modelpostgre.AchievementListFilter{Limit: 2, CursorMode: true, Tags: []string{"robotik"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// ukuran batch berlipat dua dari limit+1
+	filters := mockRefRepo.listFilters
+	if len(filters) != 5 {
+		t.Fatalf("Expected scan to stop after 5 batches, got %d", len(filters))
+	}
+	for i, limit := range []int{3, 6, 12, 24, 48} {
+		if filters[i].RowLimit != limit {
+			t.Errorf("Expected batch %d limit %d, got %d", i, limit, filters[i].RowLimit)
+		}
+	}
+
+	if data := result["data"].([]map[string]interface{}); len(data) != 0 {
+		t.Errorf("Expected empty page, got %v", data)
+	}
+
+	pagination := result["pagination"].(modelpostgre.CursorPagination)
+	if !pagination.HasMore {
+		t.Error("Expected has_more when scan stops before references run out")
+	}
+	createdAt, id, err := helper.DecodeCursor(pagination.NextCursor)
+	if err != nil || id != rows[999].MongoAchievementID || !createdAt.Equal(rows[999].CreatedAt) {
+		t.Errorf("Expected next_cursor at last scanned reference, got %s %v %v", id, createdAt, err)
+	}
+}
+
+func TestGetAchievements_CursorRequiresCreatedAtSort(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := newListService(&mockAchievementRepo{}, &mockAchievementRefRepo{}, &mockUserRepo{roleName: "Admin"}, &mockStudentRepo{})
+
+	_, err := service.GetAchievements(ctx, "admin-id", "role-id-1", modelpostgre.AchievementListFilter{CursorMode: true, SortBy: "points"})
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
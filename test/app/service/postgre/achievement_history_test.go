@@ -0,0 +1,268 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newWithdrawService(refRepo *mockAchievementRefRepo, achievementRepo *mockAchievementRepo, notificationService *mockNotificationService) servicepostgre.IAchievementService {
+	return servicepostgre.NewAchievementService(
+		achievementRepo,
+		refRepo,
+		&mockUserRepo{roleName: "Mahasiswa"},
+		&mockStudentRepo{studentIDByUserID: "550e8400-e29b-41d4-a716-446655440000"},
+		notificationService,
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+}
+
+func newSubmittedRef(submittedAt time.Time) *modelpostgre.AchievementReference {
+	return &modelpostgre.AchievementReference{
+		ID:                 "ref-id-1",
+		StudentID:          "550e8400-e29b-41d4-a716-446655440000",
+		MongoAchievementID: "mongo-id-1",
+		Status:             modelpostgre.AchievementStatusSubmitted,
+		SubmittedAt:        &submittedAt,
+	}
+}
+
+func TestWithdrawAchievement_Success(t *testing.T) {
+	ctx := setupTestContext()
+
+	submittedAt := time.Now().Add(-1 * time.Hour)
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		byMongoID: newSubmittedRef(submittedAt),
+		byID: &modelpostgre.AchievementReference{
+			ID:     "ref-id-1",
+			Status: modelpostgre.AchievementStatusDraft,
+		},
+		history: []modelpostgre.AchievementHistory{
+			{Action: modelpostgre.AchievementHistoryActionReviewStarted, CreatedAt: submittedAt.Add(-24 * time.Hour)},
+		},
+	}
+	mockNotificationService := &mockNotificationService{}
+
+	service := newWithdrawService(mockAchievementRefRepo, &mockAchievementRepo{}, mockNotificationService)
+
+	result, err := service.WithdrawAchievement(ctx, "user-id-1", "role-id-1", "mongo-id-1")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Data.Status != modelpostgre.AchievementStatusDraft {
+		t.Errorf("Expected status draft, got %s", result.Data.Status)
+	}
+
+	if len(mockAchievementRefRepo.recordedHistory) != 1 || mockAchievementRefRepo.recordedHistory[0].Action != modelpostgre.AchievementHistoryActionWithdrawn {
+		t.Errorf("Expected withdrawn history entry, got %+v", mockAchievementRefRepo.recordedHistory)
+	}
+
+	if len(mockNotificationService.retractedRefIDs) != 1 || mockNotificationService.retractedRefIDs[0] != "ref-id-1" {
+		t.Errorf("Expected submission notification retracted for ref-id-1, got %v", mockNotificationService.retractedRefIDs)
+	}
+}
+
+func TestWithdrawAchievement_ReviewStarted(t *testing.T) {
+	ctx := setupTestContext()
+
+	submittedAt := time.Now().Add(-1 * time.Hour)
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		byMongoID: newSubmittedRef(submittedAt),
+		history: []modelpostgre.AchievementHistory{
+			{Action: modelpostgre.AchievementHistoryActionReviewStarted, CreatedAt: submittedAt.Add(10 * time.Minute)},
+		},
+	}
+
+	service := newWithdrawService(mockAchievementRefRepo, &mockAchievementRepo{}, &mockNotificationService{})
+
+	_, err := service.WithdrawAchievement(ctx, "user-id-1", "role-id-1", "mongo-id-1")
+
+	if err == nil || !contains(err.Error(), "sudah mulai mereview") {
+		t.Errorf("Expected review started error, got %v", err)
+	}
+
+	if len(mockAchievementRefRepo.recordedHistory) != 0 {
+		t.Errorf("Expected no history recorded, got %+v", mockAchievementRefRepo.recordedHistory)
+	}
+}
+
+func TestWithdrawAchievement_TeamPartiallyVerified(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRepo := &mockAchievementRepo{
+		byID: &modelmongo.Achievement{
+			ID: primitive.NewObjectID(),
+			TeamVerifications: []modelmongo.TeamVerification{
+				{AdvisorID: "lecturer-id-1", VerifiedAt: time.Now()},
+			},
+		},
+	}
+
+	service := newWithdrawService(&mockAchievementRefRepo{byMongoID: newSubmittedRef(time.Now())}, mockAchievementRepo, &mockNotificationService{})
+
+	_, err := service.WithdrawAchievement(ctx, "user-id-1", "role-id-1", "mongo-id-1")
+
+	if err == nil || !contains(err.Error(), "sudah mulai mereview") {
+		t.Errorf("Expected review started error, got %v", err)
+	}
+}
+
+func TestWithdrawAchievement_ReviewStartedConcurrently(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		byMongoID:       newSubmittedRef(time.Now()),
+		withdrawBlocked: true,
+	}
+	mockNotificationService := &mockNotificationService{}
+
+	service := newWithdrawService(mockAchievementRefRepo, &mockAchievementRepo{}, mockNotificationService)
+
+	_, err := service.WithdrawAchievement(ctx, "user-id-1", "role-id-1", "mongo-id-1")
+
+	if err == nil || !contains(err.Error(), "sudah mulai mereview") {
+		t.Errorf("Expected review started error, got %v", err)
+	}
+
+	if len(mockNotificationService.retractedRefIDs) != 0 {
+		t.Error("Expected notification not retracted when withdraw fails")
+	}
+}
+
+func TestWithdrawAchievement_NotSubmitted(t *testing.T) {
+	ctx := setupTestContext()
+
+	ref := newSubmittedRef(time.Now())
+	ref.Status = modelpostgre.AchievementStatusVerified
+
+	service := newWithdrawService(&mockAchievementRefRepo{byMongoID: ref}, &mockAchievementRepo{}, &mockNotificationService{})
+
+	_, err := service.WithdrawAchievement(ctx, "user-id-1", "role-id-1", "mongo-id-1")
+
+	if err == nil || !contains(err.Error(), "status adalah submitted") {
+		t.Errorf("Expected status error, got %v", err)
+	}
+}
+
+func TestGetAchievementByID_AdvisorMarksReviewStarted(t *testing.T) {
+	ctx := setupTestContext()
+
+	studentID := "550e8400-e29b-41d4-a716-446655440000"
+	mockAchievementRefRepo := &mockAchievementRefRepo{byMongoID: newSubmittedRef(time.Now())}
+
+	service := servicepostgre.NewAchievementService(
+		&mockAchievementRepo{
+			byID: &modelmongo.Achievement{
+				ID:        primitive.NewObjectID(),
+				StudentID: studentID,
+				Title:     "Juara 1 Lomba",
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			},
+		},
+		mockAchievementRefRepo,
+		&mockUserRepo{
+			roleName:         "Dosen Wali",
+			lecturerByUserID: &modelpostgre.Lecturer{ID: "lecturer-id-1"},
+		},
+		&mockStudentRepo{byID: &modelpostgre.Student{ID: studentID, AdvisorID: "lecturer-id-1"}},
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	if _, err := service.GetAchievementByID(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockAchievementRefRepo.recordedHistory) != 1 || mockAchievementRefRepo.recordedHistory[0].Action != modelpostgre.AchievementHistoryActionReviewStarted {
+		t.Errorf("Expected review_started history entry, got %+v", mockAchievementRefRepo.recordedHistory)
+	}
+}
+
+func TestGetAchievementHistory_IncludesWithdrawals(t *testing.T) {
+	ctx := setupTestContext()
+
+	createdAt := time.Now().Add(-3 * time.Hour)
+	firstSubmit := createdAt.Add(1 * time.Hour)
+	withdrawnAt := createdAt.Add(90 * time.Minute)
+	secondSubmit := createdAt.Add(2 * time.Hour)
+	userID := "user-id-1"
+
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		byMongoID: &modelpostgre.AchievementReference{
+			ID:          "ref-id-1",
+			StudentID:   "550e8400-e29b-41d4-a716-446655440000",
+			Status:      modelpostgre.AchievementStatusSubmitted,
+			SubmittedAt: &secondSubmit,
+			CreatedAt:   createdAt,
+			UpdatedAt:   secondSubmit,
+		},
+		history: []modelpostgre.AchievementHistory{
+			{Action: modelpostgre.AchievementHistoryActionSubmitted, Status: modelpostgre.AchievementStatusSubmitted, ChangedBy: &userID, CreatedAt: firstSubmit},
+			{Action: modelpostgre.AchievementHistoryActionWithdrawn, Status: modelpostgre.AchievementStatusDraft, ChangedBy: &userID, CreatedAt: withdrawnAt},
+			{Action: modelpostgre.AchievementHistoryActionSubmitted, Status: modelpostgre.AchievementStatusSubmitted, ChangedBy: &userID, CreatedAt: secondSubmit},
+		},
+	}
+
+	service := servicepostgre.NewAchievementService(
+		&mockAchievementRepo{},
+		mockAchievementRefRepo,
+		&mockUserRepo{roleName: "Admin", byID: &modelpostgre.User{ID: userID, FullName: "Mahasiswa Test"}},
+		&mockStudentRepo{},
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	result, err := service.GetAchievementHistory(ctx, "admin-user-id", "role-id-1", "mongo-id-1")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	history := result["data"].([]map[string]interface{})
+	expected := []string{
+		modelpostgre.AchievementStatusDraft,
+		modelpostgre.AchievementStatusSubmitted,
+		modelpostgre.AchievementStatusDraft,
+		modelpostgre.AchievementStatusSubmitted,
+	}
+
+	if len(history) != len(expected) {
+		t.Fatalf("Expected %d history entries, got %+v", len(expected), history)
+	}
+
+	for i, status := range expected {
+		if history[i]["status"] != status {
+			t.Errorf("Expected entry #%d status %s, got %v", i+1, status, history[i]["status"])
+		}
+	}
+
+	if history[2]["action"] != modelpostgre.AchievementHistoryActionWithdrawn {
+		t.Errorf("Expected third entry to be withdrawn, got %v", history[2]["action"])
+	}
+}
@@ -0,0 +1,275 @@
+package service_test
+
+import (
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+)
+
+func newSagaStudentService(achievementRepo *mockAchievementRepo, refRepo *mockAchievementRefRepo) servicepostgre.IAchievementService {
+	return servicepostgre.NewAchievementService(
+		achievementRepo,
+		refRepo,
+		&mockUserRepo{roleName: "Mahasiswa"},
+		&mockStudentRepo{
+			studentIDByUserID: "550e8400-e29b-41d4-a716-446655440000",
+			byID:              &modelpostgre.Student{ID: "550e8400-e29b-41d4-a716-446655440000", UserID: "user-id-1"},
+		},
+		&mockNotificationService{},
+		&mockPointRubricService{calculation: &modelpostgre.PointCalculationResult{Points: 20, RubricVersion: 1}},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+}
+
+func newSagaCreateRequest() modelmongo.CreateAchievementRequest {
+	return modelmongo.CreateAchievementRequest{
+		AchievementType: "academic",
+		Title:           "Test Achievement",
+		Description:     "Test Description",
+	}
+}
+
+func TestCreateAchievement_SagaMongoFailureWritesNothing(t *testing.T) {
+	ctx := setupTestContext()
+
+	achievementRepo := &mockAchievementRepo{createErr: errors.New("mongo down")}
+	service := newSagaStudentService(achievementRepo, &mockAchievementRefRepo{})
+
+	_, err := service.CreateAchievement(ctx, "user-id-1", "role-id-1", newSagaCreateRequest())
+
+	if err == nil || !strings.Contains(err.Error(), "error menyimpan prestasi ke database") {
+		t.Fatalf("Expected mongo error, got %v", err)
+	}
+
+	if len(achievementRepo.deletedIDs) != 0 || len(achievementRepo.purgedIDs) != 0 {
+		t.Errorf("Expected no compensation, got deleted %v purged %v", achievementRepo.deletedIDs, achievementRepo.purgedIDs)
+	}
+}
+
+func TestCreateAchievement_SagaReferenceFailurePurgesDocument(t *testing.T) {
+	ctx := setupTestContext()
+
+	achievementRepo := &mockAchievementRepo{}
+	service := newSagaStudentService(achievementRepo, &mockAchievementRefRepo{createErr: errors.New("postgres down")})
+
+	_, err := service.CreateAchievement(ctx, "user-id-1", "role-id-1", newSagaCreateRequest())
+
+	if err == nil || !strings.Contains(err.Error(), "error membuat reference prestasi") {
+		t.Fatalf("Expected reference error, got %v", err)
+	}
+
+	if len(achievementRepo.deletedIDs) != 1 || len(achievementRepo.purgedIDs) != 1 || achievementRepo.deletedIDs[0] != achievementRepo.purgedIDs[0] {
+		t.Errorf("Expected created document soft deleted then purged, got deleted %v purged %v", achievementRepo.deletedIDs, achievementRepo.purgedIDs)
+	}
+}
+
+func TestCreateAchievement_SagaCompensationFailureKeepsOriginalError(t *testing.T) {
+	ctx := setupTestContext()
+
+	achievementRepo := &mockAchievementRepo{deleteErr: errors.New("mongo down")}
+	service := newSagaStudentService(achievementRepo, &mockAchievementRefRepo{createErr: errors.New("postgres down")})
+
+	_, err := service.CreateAchievement(ctx, "user-id-1", "role-id-1", newSagaCreateRequest())
+
+	if err == nil || !strings.Contains(err.Error(), "postgres down") {
+		t.Fatalf("Expected original reference error, got %v", err)
+	}
+}
+
+func TestDeleteAchievement_Saga(t *testing.T) {
+	ctx := setupTestContext()
+
+	testCases := []struct {
+		name            string
+		mongoErr        error
+		referenceErr    error
+		expectedRestore bool
+		expectedDeleted bool
+	}{
+		{name: "MongoDB gagal", mongoErr: errors.New("mongo down")},
+		{name: "PostgreSQL gagal", referenceErr: errors.New("postgres down"), expectedRestore: true},
+		{name: "reference sudah dihapus request lain", referenceErr: sql.ErrNoRows},
+		{name: "berhasil", expectedDeleted: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			achievementRepo := &mockAchievementRepo{deleteErr: tc.mongoErr}
+			refRepo := &mockAchievementRefRepo{
+				byMongoID: newVersionedReference(modelpostgre.AchievementStatusDraft, 1),
+				updateErr: tc.referenceErr,
+			}
+			service := newSagaStudentService(achievementRepo, refRepo)
+
+			_, err := service.DeleteAchievement(ctx, "user-id-1", "role-id-1", "mongo-id-1")
+
+			if (err == nil) != tc.expectedDeleted {
+				t.Fatalf("Expected deleted %v, got error %v", tc.expectedDeleted, err)
+			}
+
+			if restored := len(achievementRepo.restoredIDs) == 1; restored != tc.expectedRestore {
+				t.Errorf("Expected mongo restore %v, got %v", tc.expectedRestore, achievementRepo.restoredIDs)
+			}
+
+			if tc.mongoErr != nil && len(refRepo.softDeletedIDs) != 0 {
+				t.Errorf("Expected reference untouched, got %v", refRepo.softDeletedIDs)
+			}
+		})
+	}
+}
+
+func TestRestoreAchievement_SagaReferenceFailureDeletesDocumentAgain(t *testing.T) {
+	ctx := setupTestContext()
+
+	deletedRef := newDeletedRef("mongo-id-1", time.Now().Add(-24*time.Hour), modelpostgre.AchievementStatusDraft)
+	achievementRepo := &mockAchievementRepo{}
+	refRepo := &mockAchievementRefRepo{deletedRef: &deletedRef, updateErr: errors.New("postgres down")}
+	service := newTrashService(refRepo, achievementRepo, "Admin")
+
+	_, err := service.RestoreAchievement(ctx, "admin-id", "role-id-1", "mongo-id-1")
+
+	if err == nil || !strings.Contains(err.Error(), "error me-restore status prestasi") {
+		t.Fatalf("Expected reference error, got %v", err)
+	}
+
+	if len(achievementRepo.restoredIDs) != 1 || len(achievementRepo.deletedIDs) != 1 || achievementRepo.deletedIDs[0] != "mongo-id-1" {
+		t.Errorf("Expected restore compensated by soft delete, got restored %v deleted %v", achievementRepo.restoredIDs, achievementRepo.deletedIDs)
+	}
+}
+
+func TestVerifyAchievement_Saga(t *testing.T) {
+	ctx := setupTestContext()
+
+	testCases := []struct {
+		name           string
+		pointsErr      error
+		verifyErr      error
+		raced          bool
+		expectedErr    string
+		expectedPoints int
+	}{
+		{name: "simpan poin gagal", pointsErr: errors.New("mongo down"), expectedErr: "error menyimpan poin prestasi"},
+		{name: "verifikasi PostgreSQL gagal", verifyErr: errors.New("postgres down"), expectedErr: "error memverifikasi prestasi", expectedPoints: 5},
+		{name: "verifikasi balapan", raced: true, expectedErr: "konflik versi", expectedPoints: 5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			achievement := newVersionedAchievement(1)
+			achievement.Points = 5
+			achievementRepo := &mockAchievementRepo{byID: achievement, updatePointsErr: tc.pointsErr}
+			refRepo := &mockAchievementRefRepo{
+				byMongoID:       newVersionedReference(modelpostgre.AchievementStatusSubmitted, 1),
+				updateVerifyErr: tc.verifyErr,
+				versionRaced:    tc.raced,
+			}
+			service := newVersionTestService(achievementRepo, refRepo, newVersionTestLecturer())
+
+			_, err := service.VerifyAchievement(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1", modelpostgre.VerifyAchievementRequest{}, modelpostgre.AchievementVersion{Achievement: 1, Reference: 1})
+
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Fatalf("Expected error containing %q, got %v", tc.expectedErr, err)
+			}
+
+			if tc.pointsErr != nil && refRepo.verifyCalled {
+				t.Error("Expected reference not to be verified")
+			}
+
+			if achievementRepo.savedPoints != tc.expectedPoints {
+				t.Errorf("Expected points %d after compensation, got %d", tc.expectedPoints, achievementRepo.savedPoints)
+			}
+		})
+	}
+}
+
+func TestSubmitAchievement_SagaRestoresTeamVerifications(t *testing.T) {
+	ctx := setupTestContext()
+
+	achievement := newTeamAchievement(true)
+	previous := []modelmongo.TeamVerification{{AdvisorID: "lecturer-id-2", VerifiedBy: "lecturer-user-id-2", VerifiedAt: time.Now()}}
+	achievement.TeamVerifications = previous
+	achievementRepo := &mockAchievementRepo{byID: achievement}
+
+	service := servicepostgre.NewAchievementService(
+		achievementRepo,
+		&mockAchievementRefRepo{
+			byMongoID: &modelpostgre.AchievementReference{ID: "ref-id-1", StudentID: teamOwnerID, Status: modelpostgre.AchievementStatusDraft},
+			updateErr: errors.New("postgres down"),
+		},
+		&mockUserRepo{roleName: "Mahasiswa"},
+		newTeamStudentRepo("lecturer-id-1", "lecturer-id-2"),
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	_, err := service.SubmitAchievement(ctx, "user-id-1", "role-id-1", "mongo-id-1")
+
+	if err == nil || !strings.Contains(err.Error(), "error mengupdate status prestasi") {
+		t.Fatalf("Expected status error, got %v", err)
+	}
+
+	if len(achievementRepo.savedVerifications) != 1 || achievementRepo.savedVerifications[0].AdvisorID != "lecturer-id-2" {
+		t.Errorf("Expected previous team verifications restored, got %+v", achievementRepo.savedVerifications)
+	}
+}
+
+func TestVerifyAchievement_SagaRemovesFinalTeamVerification(t *testing.T) {
+	ctx := setupTestContext()
+
+	testCases := []struct {
+		name        string
+		pointsErr   error
+		verifyErr   error
+		expectedErr string
+	}{
+		{name: "simpan poin gagal", pointsErr: errors.New("mongo down"), expectedErr: "error menyimpan poin prestasi"},
+		{name: "verifikasi PostgreSQL gagal", verifyErr: errors.New("postgres down"), expectedErr: "error memverifikasi prestasi"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			achievement := newTeamAchievement(true)
+			achievement.TeamVerifications = []modelmongo.TeamVerification{{AdvisorID: "lecturer-id-2", VerifiedBy: "lecturer-user-id-2", VerifiedAt: time.Now()}}
+			achievementRepo := &mockAchievementRepo{byID: achievement, updatePointsErr: tc.pointsErr}
+			refRepo := &mockAchievementRefRepo{
+				byMongoID:       &modelpostgre.AchievementReference{ID: "ref-id-1", StudentID: teamOwnerID, Status: modelpostgre.AchievementStatusSubmitted},
+				updateVerifyErr: tc.verifyErr,
+			}
+			service := newTeamVerifyService(achievementRepo, refRepo, &mockUserRepo{
+				roleName:         "Dosen Wali",
+				lecturerByUserID: &modelpostgre.Lecturer{ID: "lecturer-id-1", UserID: "lecturer-user-id-1"},
+			})
+
+			_, err := service.VerifyAchievement(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1", modelpostgre.VerifyAchievementRequest{}, modelpostgre.AchievementVersion{})
+
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Fatalf("Expected error containing %q, got %v", tc.expectedErr, err)
+			}
+
+			if len(achievementRepo.removedVerifications) != 1 || achievementRepo.removedVerifications[0] != "lecturer-id-1" {
+				t.Errorf("Expected final team verification removed, got %v", achievementRepo.removedVerifications)
+			}
+
+			if len(achievement.TeamVerifications) != 1 || achievement.TeamVerifications[0].AdvisorID != "lecturer-id-2" {
+				t.Errorf("Expected earlier advisor verification kept, got %+v", achievement.TeamVerifications)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package service_test
+
+import (
+	"testing"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newSearchService(achievementRepo *mockAchievementRepo, refRepo *mockAchievementRefRepo, userRepo *mockUserRepo, studentRepo *mockStudentRepo) servicepostgre.IAchievementService {
+	return servicepostgre.NewAchievementService(
+		achievementRepo,
+		refRepo,
+		userRepo,
+		studentRepo,
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+}
+
+func TestSearchAchievements_StudentOnlySearchesOwnAchievements(t *testing.T) {
+	ctx := setupTestContext()
+
+	achievementID := primitive.NewObjectID()
+	mockAchievementRepo := &mockAchievementRepo{
+		searchResults: []repositorymongo.AchievementSearchResult{
+			{
+				Achievement: modelmongo.Achievement{ID: achievementID, StudentID: "student-id-1", Title: "Lomba Robotik Nasional"},
+				Score:       2.5,
+			},
+		},
+	}
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		listRows: []modelpostgre.AchievementListRow{
+			{
+				AchievementReference: modelpostgre.AchievementReference{ID: "ref-id-1", StudentID: "student-id-1", MongoAchievementID: achievementID.Hex(), Status: modelpostgre.AchievementStatusVerified},
+				StudentName:          "Budi",
+			},
+		},
+	}
+
+	service := newSearchService(
+		mockAchievementRepo,
+		mockAchievementRefRepo,
+		&mockUserRepo{roleName: "Mahasiswa"},
+		&mockStudentRepo{byUserID: &modelpostgre.Student{ID: "student-id-1"}},
+	)
+
+	result, err := service.SearchAchievements(ctx, "user-id-1", "role-id-1", "  robotik  ", 1, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if studentIDs := mockAchievementRefRepo.listFilter.StudentIDs; len(studentIDs) != 1 || studentIDs[0] != "student-id-1" {
+		t.Errorf("Expected references restricted to student-id-1, got %v", studentIDs)
+	}
+
+	if len(mockAchievementRepo.searchIDs) != 1 || mockAchievementRepo.searchIDs[0] != achievementID.Hex() {
+		t.Errorf("Expected search restricted to the student's achievement, got %v", mockAchievementRepo.searchIDs)
+	}
+
+	if result["query"] != "robotik" {
+		t.Errorf("Expected trimmed query, got %v", result["query"])
+	}
+
+	data := result["data"].([]map[string]interface{})
+	if len(data) != 1 || data[0]["score"] != 2.5 || data[0]["status"] != modelpostgre.AchievementStatusVerified || data[0]["student_name"] != "Budi" {
+		t.Errorf("Expected one verified result with score and student name, got %+v", data)
+	}
+}
+
+func TestSearchAchievements_TotalMatchesReferenceFilteredSearch(t *testing.T) {
+	ctx := setupTestContext()
+
+	achievementID := primitive.NewObjectID()
+	mockAchievementRepo := &mockAchievementRepo{
+		searchResults: []repositorymongo.AchievementSearchResult{
+			{Achievement: modelmongo.Achievement{ID: achievementID, Title: "Lomba Robotik Nasional"}, Score: 1.5},
+		},
+		searchTotal: 11,
+	}
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		listRows: []modelpostgre.AchievementListRow{
+			{AchievementReference: modelpostgre.AchievementReference{ID: "ref-id-1", MongoAchievementID: achievementID.Hex(), Status: modelpostgre.AchievementStatusSubmitted}},
+			{AchievementReference: modelpostgre.AchievementReference{ID: "ref-id-2", MongoAchievementID: primitive.NewObjectID().Hex(), Status: modelpostgre.AchievementStatusVerified}},
+		},
+	}
+
+	service := newSearchService(mockAchievementRepo, mockAchievementRefRepo, &mockUserRepo{roleName: "Admin"}, &mockStudentRepo{})
+
+	result, err := service.SearchAchievements(ctx, "admin-id", "role-id-1", "robotik", 2, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockAchievementRepo.searchIDs) != 2 {
+		t.Errorf("Expected search restricted to 2 active references, got %v", mockAchievementRepo.searchIDs)
+	}
+
+	pagination := result["pagination"].(map[string]interface{})
+	if pagination["total"] != 11 || pagination["total_pages"] != 2 {
+		t.Errorf("Expected total 11 over 2 pages from the filtered search, got %+v", pagination)
+	}
+
+	data := result["data"].([]map[string]interface{})
+	if len(data) != 1 || data[0]["status"] != modelpostgre.AchievementStatusSubmitted {
+		t.Errorf("Expected page item with reference status, got %+v", data)
+	}
+}
+
+func TestSearchAchievements_AdvisorSearchesAdvisees(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRefRepo := &mockAchievementRefRepo{}
+
+	service := newSearchService(
+		&mockAchievementRepo{},
+		mockAchievementRefRepo,
+		&mockUserRepo{roleName: "Dosen Wali", lecturerByUserID: &modelpostgre.Lecturer{ID: "lecturer-id-1"}},
+		&mockStudentRepo{byAdvisorID: []modelpostgre.Student{{ID: "student-id-1"}, {ID: "student-id-2"}}},
+	)
+
+	if _, err := service.SearchAchievements(ctx, "user-id-1", "role-id-1", "robotik", 1, 10); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockAchievementRefRepo.listFilter.StudentIDs) != 2 {
+		t.Errorf("Expected references restricted to 2 advisees, got %v", mockAchievementRefRepo.listFilter.StudentIDs)
+	}
+}
+
+func TestSearchAchievements_AdvisorWithoutAdvisees(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRepo := &mockAchievementRepo{}
+	mockAchievementRefRepo := &mockAchievementRefRepo{}
+
+	service := newSearchService(
+		mockAchievementRepo,
+		mockAchievementRefRepo,
+		&mockUserRepo{roleName: "Dosen Wali", lecturerByUserID: &modelpostgre.Lecturer{ID: "lecturer-id-1"}},
+		&mockStudentRepo{},
+	)
+
+	result, err := service.SearchAchievements(ctx, "user-id-1", "role-id-1", "robotik", 1, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockAchievementRepo.searchCalled || len(mockAchievementRefRepo.listFilters) != 0 {
+		t.Error("Expected no lookup for advisor without advisees")
+	}
+
+	if data := result["data"].([]map[string]interface{}); len(data) != 0 {
+		t.Errorf("Expected empty result, got %+v", data)
+	}
+}
+
+func TestSearchAchievements_AdminSearchesAll(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRepo := &mockAchievementRepo{}
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		listRows: []modelpostgre.AchievementListRow{
+			{AchievementReference: modelpostgre.AchievementReference{ID: "ref-id-1", MongoAchievementID: primitive.NewObjectID().Hex()}},
+		},
+	}
+
+	service := newSearchService(mockAchievementRepo, mockAchievementRefRepo, &mockUserRepo{roleName: "Admin"}, &mockStudentRepo{})
+
+	if _, err := service.SearchAchievements(ctx, "admin-id", "role-id-1", "robotik", 1, 10); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !mockAchievementRepo.searchCalled || mockAchievementRefRepo.listFilter.StudentIDs != nil {
+		t.Errorf("Expected unrestricted search, got %v", mockAchievementRefRepo.listFilter.StudentIDs)
+	}
+}
+
+func TestSearchAchievements_NoActiveReferences(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRepo := &mockAchievementRepo{}
+
+	service := newSearchService(mockAchievementRepo, &mockAchievementRefRepo{}, &mockUserRepo{roleName: "Admin"}, &mockStudentRepo{})
+
+	result, err := service.SearchAchievements(ctx, "admin-id", "role-id-1", "robotik", 1, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockAchievementRepo.searchCalled {
+		t.Error("Expected no search without active references")
+	}
+
+	if pagination := result["pagination"].(map[string]interface{}); pagination["total"] != 0 {
+		t.Errorf("Expected zero total, got %+v", pagination)
+	}
+}
+
+func TestSearchAchievements_EmptyQuery(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := newSearchService(&mockAchievementRepo{}, &mockAchievementRefRepo{}, &mockUserRepo{roleName: "Admin"}, &mockStudentRepo{})
+
+	_, err := service.SearchAchievements(ctx, "admin-id", "role-id-1", "   ", 1, 10)
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
@@ -1,14 +1,19 @@
 package service_test
 
 import (
+	"bytes"
 	"context"
 	"database/sql"
+	"fmt"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
 	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
 	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
 	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	repositorystorage "sistem-pelaporan-prestasi-mahasiswa/app/repository/storage"
 	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -26,6 +31,42 @@ type mockAchievementRepo struct {
 	updateErr            error
 	deleteErr            error
 	addAttachmentErr     error
+	updatePointsErr      error
+	savedPoints          int
+	savedRubricVersion   int
+	savedOverride        *modelmongo.PointsOverride
+	duplicateMatches     []modelmongo.Achievement
+	duplicateGroups      []repositorymongo.DuplicateKeyGroup
+	savedDuplicateKeys   []string
+	savedTeamMembers     []modelmongo.TeamMember
+	confirmedStudentID   string
+	savedVerifications   []modelmongo.TeamVerification
+	removedVerifications []string
+	beforeVerification   func()
+	deletedByIDs         []modelmongo.Achievement
+	restoredIDs          []string
+	purgedIDs            []string
+	restoredBeforePurge  bool
+	deletedIDs           []string
+	allStates            []repositorymongo.AchievementStoreState
+	validUntilBetween    []modelmongo.Achievement
+	searchResults        []repositorymongo.AchievementSearchResult
+	searchIDs            []string
+	searchTotal          int
+	searchCalled         bool
+	facetResult          *repositorymongo.AchievementFacetResult
+	facetResults         []*repositorymongo.AchievementFacetResult
+	facetFilter          *modelmongo.AchievementFilter
+	facetFilters         []modelmongo.AchievementFilter
+	matchingIDs          map[string]bool
+	matchFilters         []modelmongo.AchievementFilter
+	versionRaced         bool
+	fileRefs             map[string]int64
+	removedAttachmentID  string
+	replacedAttachment   *modelmongo.Attachment
+	studentUsage         int64
+	reservations         map[string]int64
+	releasedReservations []string
 }
 
 func (m *mockAchievementRepo) CreateAchievement(ctx context.Context, achievement *modelmongo.Achievement) (*modelmongo.Achievement, error) {
@@ -48,14 +89,14 @@ func (m *mockAchievementRepo) GetAchievementByID(ctx context.Context, id string)
 	return m.byID, nil
 }
 
-func (m *mockAchievementRepo) UpdateAchievement(ctx context.Context, id string, req modelmongo.UpdateAchievementRequest) (*modelmongo.Achievement, error) {
+func (m *mockAchievementRepo) UpdateAchievement(ctx context.Context, id string, expectedVersion int, req modelmongo.UpdateAchievementRequest) (*modelmongo.Achievement, error) {
 	if m.updateErr != nil {
 		return nil, m.updateErr
 	}
 	if m.err != nil {
 		return nil, m.err
 	}
-	if m.byID == nil {
+	if m.byID == nil || m.byID.Version != expectedVersion || m.versionRaced {
 		return nil, nil
 	}
 	return m.byID, nil
@@ -65,7 +106,11 @@ func (m *mockAchievementRepo) DeleteAchievement(ctx context.Context, id string)
 	if m.deleteErr != nil {
 		return m.deleteErr
 	}
-	return m.err
+	if m.err != nil {
+		return m.err
+	}
+	m.deletedIDs = append(m.deletedIDs, id)
+	return nil
 }
 
 func (m *mockAchievementRepo) GetAchievementsByStudentID(ctx context.Context, studentID string) ([]modelmongo.Achievement, error) {
@@ -113,8 +158,296 @@ func (m *mockAchievementRepo) GetTopStudentsByPoints(ctx context.Context, limit
 	return m.topStudents, nil
 }
 
+func (m *mockAchievementRepo) UpdateAchievementPoints(ctx context.Context, id string, points int, rubricVersion int, override *modelmongo.PointsOverride) error {
+	if m.updatePointsErr != nil {
+		return m.updatePointsErr
+	}
+	m.savedPoints = points
+	m.savedRubricVersion = rubricVersion
+	m.savedOverride = override
+	return nil
+}
+
+func (m *mockAchievementRepo) UpdateDuplicateKeys(ctx context.Context, id string, keys []string) error {
+	m.savedDuplicateKeys = keys
+	return nil
+}
+
+func (m *mockAchievementRepo) FindAchievementsByDuplicateKeys(ctx context.Context, excludeID string, keys []string, limit int) ([]modelmongo.Achievement, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.duplicateMatches, nil
+}
+
+func (m *mockAchievementRepo) GetDuplicateKeyGroups(ctx context.Context) ([]repositorymongo.DuplicateKeyGroup, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.duplicateGroups, nil
+}
+
+func (m *mockAchievementRepo) UpdateTeamMembers(ctx context.Context, id string, members []modelmongo.TeamMember, designatedAdvisorID string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.savedTeamMembers = members
+	return nil
+}
+
+func (m *mockAchievementRepo) ConfirmTeamMember(ctx context.Context, id string, studentID string, confirmedAt time.Time) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.confirmedStudentID = studentID
+	return nil
+}
+
+func (m *mockAchievementRepo) SetTeamVerifications(ctx context.Context, id string, verifications []modelmongo.TeamVerification) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.savedVerifications = verifications
+	return nil
+}
+
+func (m *mockAchievementRepo) AddTeamVerification(ctx context.Context, id string, verification modelmongo.TeamVerification, otherAdvisorIDs []string, completes bool) (bool, error) {
+	if m.err != nil {
+		return false, m.err
+	}
+	if hook := m.beforeVerification; hook != nil {
+		m.beforeVerification = nil
+		hook()
+	}
+
+	verified := map[string]bool{}
+	for _, existing := range m.byID.TeamVerifications {
+		verified[existing.AdvisorID] = true
+	}
+	allOthers := true
+	for _, advisorID := range otherAdvisorIDs {
+		allOthers = allOthers && verified[advisorID]
+	}
+	if verified[verification.AdvisorID] || allOthers != completes {
+		return false, nil
+	}
+
+	m.byID.TeamVerifications = append(m.byID.TeamVerifications, verification)
+	m.savedVerifications = m.byID.TeamVerifications
+	return true, nil
+}
+
+func (m *mockAchievementRepo) RemoveTeamVerification(ctx context.Context, id string, advisorID string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.removedVerifications = append(m.removedVerifications, advisorID)
+	kept := []modelmongo.TeamVerification{}
+	for _, existing := range m.byID.TeamVerifications {
+		if existing.AdvisorID != advisorID {
+			kept = append(kept, existing)
+		}
+	}
+	m.byID.TeamVerifications = kept
+	m.savedVerifications = kept
+	return nil
+}
+
+func (m *mockAchievementRepo) GetDeletedAchievementsByIDs(ctx context.Context, ids []string) ([]modelmongo.Achievement, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.deletedByIDs, nil
+}
+
+func (m *mockAchievementRepo) RestoreAchievement(ctx context.Context, id string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.restoredIDs = append(m.restoredIDs, id)
+	return nil
+}
+
+func (m *mockAchievementRepo) PurgeAchievement(ctx context.Context, id string) (bool, error) {
+	if m.deleteErr != nil {
+		return false, m.deleteErr
+	}
+	if m.restoredBeforePurge {
+		return false, nil
+	}
+	m.purgedIDs = append(m.purgedIDs, id)
+	return true, nil
+}
+
+func (m *mockAchievementRepo) GetAchievementsValidUntilBetween(ctx context.Context, from, to time.Time) ([]modelmongo.Achievement, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.validUntilBetween, nil
+}
+
+func (m *mockAchievementRepo) GetAchievementFacets(ctx context.Context, filter modelmongo.AchievementFilter) (*repositorymongo.AchievementFacetResult, error) {
+	m.facetFilter = &filter
+	m.facetFilters = append(m.facetFilters, filter)
+	if m.err != nil {
+		return nil, m.err
+	}
+	if len(m.facetResults) > 0 {
+		result := m.facetResults[0]
+		m.facetResults = m.facetResults[1:]
+		return result, nil
+	}
+	if m.facetResult == nil {
+		return &repositorymongo.AchievementFacetResult{Facets: map[string][]modelmongo.FacetCount{}}, nil
+	}
+	return m.facetResult, nil
+}
+
+func (m *mockAchievementRepo) GetMatchingAchievementIDs(ctx context.Context, filter modelmongo.AchievementFilter) ([]string, error) {
+	m.matchFilters = append(m.matchFilters, filter)
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.matchingIDs == nil {
+		return filter.IDs, nil
+	}
+	ids := []string{}
+	for _, id := range filter.IDs {
+		if m.matchingIDs[id] {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+func (m *mockAchievementRepo) SearchAchievements(ctx context.Context, query string, achievementIDs []string, page, limit int) ([]repositorymongo.AchievementSearchResult, int, error) {
+	m.searchCalled = true
+	m.searchIDs = achievementIDs
+	if m.err != nil {
+		return nil, 0, m.err
+	}
+	if m.searchTotal > 0 {
+		return m.searchResults, m.searchTotal, nil
+	}
+	return m.searchResults, len(m.searchResults), nil
+}
+
+func (m *mockAchievementRepo) GetAllAchievementStates(ctx context.Context) ([]repositorymongo.AchievementStoreState, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.allStates, nil
+}
+
+func (m *mockAchievementRepo) GetAchievementsByAttachmentURLPrefix(ctx context.Context, prefix string) ([]modelmongo.Achievement, error) {
+	return nil, m.err
+}
+
+func (m *mockAchievementRepo) UpdateAttachmentFileURL(ctx context.Context, id string, oldURL string, newURL string) (bool, error) {
+	return false, m.err
+}
+
+func (m *mockAchievementRepo) GetAchievementsByAttachmentScanStatus(ctx context.Context, status string, limit int) ([]modelmongo.Achievement, error) {
+	return nil, m.err
+}
+
+func (m *mockAchievementRepo) UpdateAttachmentScanResult(ctx context.Context, id string, fileURL string, status string, signature string, newURL string) (bool, error) {
+	return false, m.err
+}
+
+func (m *mockAchievementRepo) RemoveAttachment(ctx context.Context, id string, attachmentID string) (*modelmongo.Achievement, error) {
+	if m.updateErr != nil {
+		return nil, m.updateErr
+	}
+	if m.byID == nil {
+		return nil, nil
+	}
+	for i, attachment := range m.byID.Attachments {
+		if attachment.ID == attachmentID {
+			m.removedAttachmentID = attachmentID
+			m.byID.Attachments = append(m.byID.Attachments[:i:i], m.byID.Attachments[i+1:]...)
+			return m.byID, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockAchievementRepo) ReplaceAttachment(ctx context.Context, id string, attachmentID string, attachment modelmongo.Attachment) (*modelmongo.Achievement, error) {
+	if m.updateErr != nil {
+		return nil, m.updateErr
+	}
+	if m.byID == nil {
+		return nil, nil
+	}
+	for i := range m.byID.Attachments {
+		if m.byID.Attachments[i].ID == attachmentID {
+			attachment.ID = attachmentID
+			m.replacedAttachment = &attachment
+			m.byID.Attachments[i] = attachment
+			return m.byID, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockAchievementRepo) CountAttachmentFileReferences(ctx context.Context, fileURL string) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	count := m.fileRefs[fileURL]
+	if m.byID != nil {
+		for _, attachment := range m.byID.Attachments {
+			if attachment.FileURL == fileURL {
+				count++
+			}
+		}
+	}
+	return count, nil
+}
+
+func (m *mockAchievementRepo) GetStudentAttachmentUsage(ctx context.Context, studentID string) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	return m.studentUsage, nil
+}
+
+func (m *mockAchievementRepo) ReserveAttachmentQuota(ctx context.Context, studentID string, size int64, expiresAt time.Time) (string, error) {
+	if m.reservations == nil {
+		m.reservations = map[string]int64{}
+	}
+	id := fmt.Sprintf("reservation-%d", len(m.reservations)+len(m.releasedReservations)+1)
+	m.reservations[id] = size
+	return id, nil
+}
+
+func (m *mockAchievementRepo) GetStudentReservedAttachmentQuota(ctx context.Context, studentID string, excludeID string, now time.Time) (int64, error) {
+	var reserved int64
+	for id, size := range m.reservations {
+		if id != excludeID {
+			reserved += size
+		}
+	}
+	return reserved, nil
+}
+
+func (m *mockAchievementRepo) ReleaseAttachmentQuota(ctx context.Context, id string) error {
+	delete(m.reservations, id)
+	m.releasedReservations = append(m.releasedReservations, id)
+	return nil
+}
+
+func (m *mockAchievementRepo) GetAttachmentUsageByStudent(ctx context.Context) ([]repositorymongo.StudentAttachmentUsage, error) {
+	return nil, m.err
+}
+
+func (m *mockAchievementRepo) GetAttachmentStorageTotals(ctx context.Context) (*repositorymongo.AttachmentStorageTotals, error) {
+	return nil, m.err
+}
+
 type mockAchievementRefRepo struct {
 	byMongoID       *modelpostgre.AchievementReference
+	byMongoIDs      []modelpostgre.AchievementReference
+	mongoIDLookups  [][]string
 	byID            *modelpostgre.AchievementReference
 	byStudentID     []modelpostgre.AchievementReference
 	byAdvisorID     []modelpostgre.AchievementReference
@@ -123,11 +456,34 @@ type mockAchievementRefRepo struct {
 	statsVerified   int
 	byPeriod        map[string]int
 	allMongoIDs     []string
+	deletedMongoIDs []string
 	err             error
 	createErr       error
 	updateErr       error
 	updateVerifyErr error
 	updateRejectErr error
+	history         []modelpostgre.AchievementHistory
+	recordedHistory []modelpostgre.CreateAchievementHistoryRequest
+	withdrawBlocked bool
+	submittedBefore []modelpostgre.AchievementReference
+	deletedRef      *modelpostgre.DeletedAchievementReference
+	deletedRefs     []modelpostgre.DeletedAchievementReference
+	softDeletedIDs  []string
+	purgedRefIDs    []string
+	refRestored     bool
+	claimedAlerts   map[string]bool
+	releasedAlerts  []string
+	claimedSLA      map[string]bool
+	releasedSLA     []string
+	listRows        []modelpostgre.AchievementListRow
+	listRowBatches  [][]modelpostgre.AchievementListRow
+	listFilter      modelpostgre.AchievementListFilter
+	listFilters     []modelpostgre.AchievementListFilter
+	facetRowBatches [][]modelpostgre.AchievementListRow
+	facetRowFilter  modelpostgre.AchievementListFilter
+	facetRowAfters  []*modelpostgre.PageCursor
+	versionRaced    bool
+	verifyCalled    bool
 }
 
 func (m *mockAchievementRefRepo) CreateAchievementReference(ctx context.Context, req modelpostgre.CreateAchievementReferenceRequest) (*modelpostgre.AchievementReference, error) {
@@ -218,18 +574,19 @@ func (m *mockAchievementRefRepo) GetAllAchievementReferencesPaginated(ctx contex
 	return m.allReferences, len(m.allReferences), nil
 }
 
-func (m *mockAchievementRefRepo) UpdateAchievementReferenceVerify(ctx context.Context, id string, verifiedBy string) error {
+func (m *mockAchievementRefRepo) UpdateAchievementReferenceVerify(ctx context.Context, id string, verifiedBy string, expectedVersion int) (bool, error) {
+	m.verifyCalled = true
 	if m.updateVerifyErr != nil {
-		return m.updateVerifyErr
+		return false, m.updateVerifyErr
 	}
-	return m.err
+	return !m.versionRaced, m.err
 }
 
-func (m *mockAchievementRefRepo) UpdateAchievementReferenceReject(ctx context.Context, id string, verifiedBy string, rejectionNote string) error {
+func (m *mockAchievementRefRepo) UpdateAchievementReferenceReject(ctx context.Context, id string, verifiedBy string, rejectionNote string, expectedVersion int) (bool, error) {
 	if m.updateRejectErr != nil {
-		return m.updateRejectErr
+		return false, m.updateRejectErr
 	}
-	return m.err
+	return !m.versionRaced, m.err
 }
 
 func (m *mockAchievementRefRepo) GetAchievementStats(ctx context.Context) (int, int, error) {
@@ -253,6 +610,170 @@ func (m *mockAchievementRefRepo) GetAllAchievementMongoIDs(ctx context.Context)
 	return m.allMongoIDs, nil
 }
 
+func (m *mockAchievementRefRepo) GetDeletedAchievementMongoIDs(ctx context.Context) ([]string, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.deletedMongoIDs, nil
+}
+
+func (m *mockAchievementRefRepo) CreateAchievementHistory(ctx context.Context, req modelpostgre.CreateAchievementHistoryRequest) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.recordedHistory = append(m.recordedHistory, req)
+	return nil
+}
+
+func (m *mockAchievementRefRepo) GetAchievementHistoryByRefID(ctx context.Context, achievementRefID string) ([]modelpostgre.AchievementHistory, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.history, nil
+}
+
+func (m *mockAchievementRefRepo) WithdrawAchievementReference(ctx context.Context, id string) (bool, error) {
+	if m.updateErr != nil {
+		return false, m.updateErr
+	}
+	return !m.withdrawBlocked, nil
+}
+
+func (m *mockAchievementRefRepo) GetSubmittedReferencesBefore(ctx context.Context, before time.Time) ([]modelpostgre.AchievementReference, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.submittedBefore, nil
+}
+
+func (m *mockAchievementRefRepo) SoftDeleteAchievementReference(ctx context.Context, id string) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	m.softDeletedIDs = append(m.softDeletedIDs, id)
+	return nil
+}
+
+func (m *mockAchievementRefRepo) GetDeletedAchievementReferenceByMongoID(ctx context.Context, mongoID string) (*modelpostgre.DeletedAchievementReference, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.deletedRef == nil {
+		return nil, sql.ErrNoRows
+	}
+	return m.deletedRef, nil
+}
+
+func (m *mockAchievementRefRepo) RestoreAchievementReference(ctx context.Context, id string) (string, error) {
+	if m.updateErr != nil {
+		return "", m.updateErr
+	}
+	if m.deletedRef == nil {
+		return "", sql.ErrNoRows
+	}
+	return m.deletedRef.StatusBeforeDelete, nil
+}
+
+func (m *mockAchievementRefRepo) GetDeletedAchievementReferencesPaginated(ctx context.Context, page, limit int) ([]modelpostgre.DeletedAchievementReference, int, error) {
+	if m.err != nil {
+		return nil, 0, m.err
+	}
+	return m.deletedRefs, len(m.deletedRefs), nil
+}
+
+func (m *mockAchievementRefRepo) GetDeletedReferencesBefore(ctx context.Context, before time.Time) ([]modelpostgre.DeletedAchievementReference, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var refs []modelpostgre.DeletedAchievementReference
+	for _, ref := range m.deletedRefs {
+		if !ref.DeletedAt.After(before) {
+			refs = append(refs, ref)
+		}
+	}
+	return refs, nil
+}
+
+func (m *mockAchievementRefRepo) PurgeAchievementReference(ctx context.Context, id string) (bool, error) {
+	if m.updateErr != nil {
+		return false, m.updateErr
+	}
+	if m.refRestored {
+		return false, nil
+	}
+	m.purgedRefIDs = append(m.purgedRefIDs, id)
+	return true, nil
+}
+
+func (m *mockAchievementRefRepo) ClaimCertificationExpiryAlert(ctx context.Context, achievementRefID string, validUntil time.Time, daysBefore int) (bool, error) {
+	if m.createErr != nil {
+		return false, m.createErr
+	}
+	if m.claimedAlerts == nil {
+		m.claimedAlerts = map[string]bool{}
+	}
+	key := fmt.Sprintf("%s|%s|%d", achievementRefID, validUntil.Format("2006-01-02"), daysBefore)
+	if m.claimedAlerts[key] {
+		return false, nil
+	}
+	m.claimedAlerts[key] = true
+	return true, nil
+}
+
+func (m *mockAchievementRefRepo) GetAchievementListRows(ctx context.Context, filter modelpostgre.AchievementListFilter) ([]modelpostgre.AchievementListRow, error) {
+	m.listFilter = filter
+	m.listFilters = append(m.listFilters, filter)
+	if m.err != nil {
+		return nil, m.err
+	}
+	if len(m.listRowBatches) > 0 {
+		batch := m.listRowBatches[0]
+		m.listRowBatches = m.listRowBatches[1:]
+		return batch, nil
+	}
+	return m.listRows, nil
+}
+
+func (m *mockAchievementRefRepo) GetAchievementFacetRows(ctx context.Context, filter modelpostgre.AchievementListFilter, after *modelpostgre.PageCursor, limit int) ([]modelpostgre.AchievementListRow, error) {
+	m.facetRowFilter = filter
+	m.facetRowAfters = append(m.facetRowAfters, after)
+	if m.err != nil {
+		return nil, m.err
+	}
+	if len(m.facetRowBatches) > 0 {
+		batch := m.facetRowBatches[0]
+		m.facetRowBatches = m.facetRowBatches[1:]
+		return batch, nil
+	}
+	return m.listRows, nil
+}
+
+func (m *mockAchievementRefRepo) ReleaseCertificationExpiryAlert(ctx context.Context, achievementRefID string, validUntil time.Time, daysBefore int) error {
+	key := fmt.Sprintf("%s|%s|%d", achievementRefID, validUntil.Format("2006-01-02"), daysBefore)
+	delete(m.claimedAlerts, key)
+	m.releasedAlerts = append(m.releasedAlerts, key)
+	return nil
+}
+
+func (m *mockAchievementRefRepo) ClaimSLANotification(ctx context.Context, achievementRefID string, submittedAt time.Time, action string) (bool, error) {
+	if m.claimedSLA == nil {
+		m.claimedSLA = map[string]bool{}
+	}
+	key := achievementRefID + "|" + submittedAt.Format(time.RFC3339Nano) + "|" + action
+	if m.claimedSLA[key] {
+		return false, nil
+	}
+	m.claimedSLA[key] = true
+	return true, nil
+}
+
+func (m *mockAchievementRefRepo) ReleaseSLANotification(ctx context.Context, achievementRefID string, submittedAt time.Time, action string) error {
+	key := achievementRefID + "|" + submittedAt.Format(time.RFC3339Nano) + "|" + action
+	delete(m.claimedSLA, key)
+	m.releasedSLA = append(m.releasedSLA, key)
+	return nil
+}
+
 type mockUserRepo struct {
 	byID              *modelpostgre.User
 	byEmail           *modelpostgre.User
@@ -263,6 +784,7 @@ type mockUserRepo struct {
 	permissions       []string
 	lecturerByUserID  *modelpostgre.Lecturer
 	lecturerByID      *modelpostgre.Lecturer
+	userIDsByRole     []string
 	err               error
 }
 
@@ -366,12 +888,20 @@ func (m *mockUserRepo) GetLecturerByID(ctx context.Context, id string) (*modelpo
 	return m.lecturerByID, nil
 }
 
+func (m *mockUserRepo) GetActiveUserIDsByRoleName(ctx context.Context, roleName string) ([]string, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.userIDsByRole, nil
+}
+
 type mockStudentRepo struct {
 	byID              *modelpostgre.Student
 	byUserID          *modelpostgre.Student
 	studentIDByUserID string
 	byAdvisorID       []modelpostgre.Student
 	allStudents       []modelpostgre.Student
+	studentsByID      map[string]*modelpostgre.Student
 	err               error
 }
 
@@ -399,6 +929,12 @@ func (m *mockStudentRepo) GetStudentByID(ctx context.Context, id string) (*model
 		}
 		return nil, m.err
 	}
+	if m.studentsByID != nil {
+		if student, ok := m.studentsByID[id]; ok {
+			return student, nil
+		}
+		return nil, sql.ErrNoRows
+	}
 	return m.byID, nil
 }
 
@@ -444,13 +980,23 @@ func (m *mockStudentRepo) UpdateStudentAdvisor(ctx context.Context, id string, a
 }
 
 type mockNotificationService struct {
-	err error
+	err               error
+	invitedStudentIDs []string
+	retractedRefIDs   []string
+	remindedRefIDs    []string
+	escalations       map[string][]string
+	expiryAlerts      []int
+	infectedFiles     []string
 }
 
 func (m *mockNotificationService) GetNotifications(ctx context.Context, userID string, page, limit int) (*modelpostgre.GetNotificationsResponse, error) {
 	return nil, m.err
 }
 
+func (m *mockNotificationService) GetNotificationsByCursor(ctx context.Context, userID string, cursor *modelpostgre.PageCursor, limit int) (*modelpostgre.GetNotificationsCursorResponse, error) {
+	return nil, m.err
+}
+
 func (m *mockNotificationService) GetUnreadCount(ctx context.Context, userID string) (*modelpostgre.GetUnreadCountResponse, error) {
 	return nil, m.err
 }
@@ -471,6 +1017,144 @@ func (m *mockNotificationService) CreateSubmissionNotification(ctx context.Conte
 	return m.err
 }
 
+func (m *mockNotificationService) CreateTeamInvitationNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string) error {
+	m.invitedStudentIDs = append(m.invitedStudentIDs, studentID)
+	return m.err
+}
+
+func (m *mockNotificationService) RetractSubmissionNotification(ctx context.Context, achievementRefID string) error {
+	m.retractedRefIDs = append(m.retractedRefIDs, achievementRefID)
+	return m.err
+}
+
+func (m *mockNotificationService) CreateSLAReminderNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string, waitingDays int) error {
+	m.remindedRefIDs = append(m.remindedRefIDs, achievementRefID)
+	return m.err
+}
+
+func (m *mockNotificationService) CreateSLAEscalationNotification(ctx context.Context, recipientUserIDs []string, mongoAchievementID string, achievementRefID string, waitingDays int) error {
+	if m.escalations == nil {
+		m.escalations = map[string][]string{}
+	}
+	m.escalations[achievementRefID] = recipientUserIDs
+	return m.err
+}
+
+func (m *mockNotificationService) CreateCertificationExpiryNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string, validUntil time.Time, daysLeft int) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.expiryAlerts = append(m.expiryAlerts, daysLeft)
+	return nil
+}
+
+func (m *mockNotificationService) CreateAttachmentInfectedNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string, fileName string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.infectedFiles = append(m.infectedFiles, fileName)
+	return nil
+}
+
+type mockPointRubricService struct {
+	calculation *modelpostgre.PointCalculationResult
+	err         error
+}
+
+func (m *mockPointRubricService) GetPointRubrics(ctx context.Context) (*modelpostgre.GetAllPointRubricsResponse, error) {
+	return nil, m.err
+}
+
+func (m *mockPointRubricService) GetActivePointRubric(ctx context.Context) (*modelpostgre.GetPointRubricResponse, error) {
+	return nil, m.err
+}
+
+func (m *mockPointRubricService) GetPointRubricByVersion(ctx context.Context, version int) (*modelpostgre.GetPointRubricResponse, error) {
+	return nil, m.err
+}
+
+func (m *mockPointRubricService) CreatePointRubric(ctx context.Context, userID string, req modelpostgre.CreatePointRubricRequest) (*modelpostgre.GetPointRubricResponse, error) {
+	return nil, m.err
+}
+
+func (m *mockPointRubricService) ActivatePointRubric(ctx context.Context, version int) (*modelpostgre.GetPointRubricResponse, error) {
+	return nil, m.err
+}
+
+func (m *mockPointRubricService) CalculatePoints(ctx context.Context, achievementType string, details modelmongo.AchievementDetails) (*modelpostgre.PointCalculationResult, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.calculation == nil {
+		return &modelpostgre.PointCalculationResult{}, nil
+	}
+	return m.calculation, nil
+}
+
+type mockFileStorage struct {
+	objects map[string][]byte
+	puts    [][]byte
+	deleted []string
+	putErr  error
+}
+
+func (m *mockFileStorage) Driver() string {
+	return repositorystorage.StorageDriverLocal
+}
+
+func (m *mockFileStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if m.putErr != nil {
+		return m.putErr
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if m.objects == nil {
+		m.objects = map[string][]byte{}
+	}
+	m.objects[key] = data
+	m.puts = append(m.puts, data)
+	return nil
+}
+
+func (m *mockFileStorage) Get(ctx context.Context, key string) (io.ReadCloser, *repositorystorage.FileInfo, error) {
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, nil, repositorystorage.ErrFileNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), &repositorystorage.FileInfo{Key: key, Size: int64(len(data))}, nil
+}
+
+func (m *mockFileStorage) Delete(ctx context.Context, key string) error {
+	m.deleted = append(m.deleted, key)
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *mockFileStorage) Stat(ctx context.Context, key string) (*repositorystorage.FileInfo, error) {
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, repositorystorage.ErrFileNotFound
+	}
+	return &repositorystorage.FileInfo{Key: key, Size: int64(len(data))}, nil
+}
+
+func (m *mockFileStorage) URL(key string) string {
+	return "/uploads/" + key
+}
+
+func (m *mockFileStorage) KeyFromURL(fileURL string) (string, bool) {
+	if !strings.HasPrefix(fileURL, "/uploads/") {
+		return "", false
+	}
+	return strings.TrimPrefix(fileURL, "/uploads/"), true
+}
+
+func testAttachmentConfig() modelmongo.AttachmentConfig {
+	return modelmongo.AttachmentConfig{LinkSecret: []byte("test-attachment-secret"), LinkTTL: 15 * time.Minute}
+}
+
 func TestCreateAchievement_Success(t *testing.T) {
 	ctx := setupTestContext()
 
@@ -509,13 +1193,21 @@ func TestCreateAchievement_Success(t *testing.T) {
 		mockUserRepo,
 		mockStudentRepo,
 		mockNotificationService,
+		&mockPointRubricService{
+			calculation: &modelpostgre.PointCalculationResult{Points: 20, RubricVersion: 1},
+		},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
 	)
 
 	req := modelmongo.CreateAchievementRequest{
 		AchievementType: "academic",
 		Title:           "Test Achievement",
 		Description:     "Test Description",
-		Points:          100,
 	}
 
 	result, err := service.CreateAchievement(ctx, "user-id-1", "role-id-1", req)
@@ -531,6 +1223,14 @@ func TestCreateAchievement_Success(t *testing.T) {
 	if result.Status != "success" {
 		t.Errorf("Expected status 'success', got '%s'", result.Status)
 	}
+
+	if result.Data.Points != 20 {
+		t.Errorf("Expected points from rubric 20, got %d", result.Data.Points)
+	}
+
+	if result.Data.RubricVersion != 1 {
+		t.Errorf("Expected rubric version 1, got %d", result.Data.RubricVersion)
+	}
 }
 
 func TestCreateAchievement_InvalidRole(t *testing.T) {
@@ -546,13 +1246,19 @@ func TestCreateAchievement_InvalidRole(t *testing.T) {
 		mockUserRepo,
 		&mockStudentRepo{},
 		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
 	)
 
 	req := modelmongo.CreateAchievementRequest{
 		AchievementType: "academic",
 		Title:           "Test",
 		Description:     "Test",
-		Points:          100,
 	}
 
 	_, err := service.CreateAchievement(ctx, "user-id-1", "role-id-1", req)
@@ -583,13 +1289,19 @@ func TestCreateAchievement_MissingStudentProfile(t *testing.T) {
 		mockUserRepo,
 		mockStudentRepo,
 		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
 	)
 
 	req := modelmongo.CreateAchievementRequest{
 		AchievementType: "academic",
 		Title:           "Test",
 		Description:     "Test",
-		Points:          100,
 	}
 
 	_, err := service.CreateAchievement(ctx, "user-id-1", "role-id-1", req)
@@ -623,6 +1335,13 @@ func TestCreateAchievement_ValidationErrors(t *testing.T) {
 		mockUserRepo,
 		mockStudentRepo,
 		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
 	)
 
 	testCases := []struct {
@@ -635,7 +1354,6 @@ func TestCreateAchievement_ValidationErrors(t *testing.T) {
 			req: modelmongo.CreateAchievementRequest{
 				Title:       "Test",
 				Description: "Test",
-				Points:      100,
 			},
 			want: "achievement type wajib diisi",
 		},
@@ -644,7 +1362,6 @@ func TestCreateAchievement_ValidationErrors(t *testing.T) {
 			req: modelmongo.CreateAchievementRequest{
 				AchievementType: "academic",
 				Description:     "Test",
-				Points:          100,
 			},
 			want: "title wajib diisi",
 		},
@@ -653,7 +1370,6 @@ func TestCreateAchievement_ValidationErrors(t *testing.T) {
 			req: modelmongo.CreateAchievementRequest{
 				AchievementType: "academic",
 				Title:           "Test",
-				Points:          100,
 			},
 			want: "description wajib diisi",
 		},
@@ -663,7 +1379,6 @@ func TestCreateAchievement_ValidationErrors(t *testing.T) {
 				AchievementType: "invalid",
 				Title:           "Test",
 				Description:     "Test",
-				Points:          100,
 			},
 			want: "achievement type tidak valid",
 		},
@@ -714,12 +1429,29 @@ func TestSubmitAchievement_Success(t *testing.T) {
 		studentIDByUserID: "550e8400-e29b-41d4-a716-446655440000",
 	}
 
+	mockAchievementRepo := &mockAchievementRepo{
+		byID: &modelmongo.Achievement{
+			ID:              primitive.NewObjectID(),
+			StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+			AchievementType: "academic",
+			Title:           "Test Achievement",
+			Description:     "Test Description",
+		},
+	}
+
 	service := servicepostgre.NewAchievementService(
-		&mockAchievementRepo{},
+		mockAchievementRepo,
 		mockAchievementRefRepo,
 		mockUserRepo,
 		mockStudentRepo,
 		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
 	)
 
 	result, err := service.SubmitAchievement(ctx, "user-id-1", "role-id-1", "mongo-id-1")
@@ -767,6 +1499,13 @@ func TestSubmitAchievement_WrongStatus(t *testing.T) {
 		mockUserRepo,
 		mockStudentRepo,
 		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
 	)
 
 	_, err := service.SubmitAchievement(ctx, "user-id-1", "role-id-1", "mongo-id-1")
@@ -816,15 +1555,33 @@ func TestVerifyAchievement_Success(t *testing.T) {
 		},
 	}
 
+	mockAchievementRepo := &mockAchievementRepo{
+		byID: &modelmongo.Achievement{
+			ID:              primitive.NewObjectID(),
+			StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+			AchievementType: "competition",
+			Title:           "Juara 1 Lomba",
+		},
+	}
+
 	service := servicepostgre.NewAchievementService(
-		&mockAchievementRepo{},
+		mockAchievementRepo,
 		mockAchievementRefRepo,
 		mockUserRepo,
 		mockStudentRepo,
 		&mockNotificationService{},
+		&mockPointRubricService{
+			calculation: &modelpostgre.PointCalculationResult{Points: 90, RubricVersion: 2},
+		},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
 	)
 
-	result, err := service.VerifyAchievement(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1")
+	result, err := service.VerifyAchievement(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1", modelpostgre.VerifyAchievementRequest{}, modelpostgre.AchievementVersion{})
 
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
@@ -837,6 +1594,187 @@ func TestVerifyAchievement_Success(t *testing.T) {
 	if result.Status != "success" {
 		t.Errorf("Expected status 'success', got '%s'", result.Status)
 	}
+
+	if mockAchievementRepo.savedPoints != 90 || mockAchievementRepo.savedRubricVersion != 2 {
+		t.Errorf("Expected points 90 with rubric version 2, got %d with version %d", mockAchievementRepo.savedPoints, mockAchievementRepo.savedRubricVersion)
+	}
+
+	if mockAchievementRepo.savedOverride != nil {
+		t.Error("Expected no points override")
+	}
+}
+
+func TestVerifyAchievement_PointsOverride(t *testing.T) {
+	ctx := setupTestContext()
+
+	now := time.Now()
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		byMongoID: &modelpostgre.AchievementReference{
+			ID:                 "ref-id-1",
+			StudentID:          "550e8400-e29b-41d4-a716-446655440000",
+			MongoAchievementID: "mongo-id-1",
+			Status:             modelpostgre.AchievementStatusSubmitted,
+		},
+		byID: &modelpostgre.AchievementReference{
+			ID:                 "ref-id-1",
+			StudentID:          "550e8400-e29b-41d4-a716-446655440000",
+			MongoAchievementID: "mongo-id-1",
+			Status:             modelpostgre.AchievementStatusVerified,
+			VerifiedAt:         &now,
+			VerifiedBy:         stringPtr("lecturer-id-1"),
+		},
+	}
+
+	mockUserRepo := &mockUserRepo{
+		roleName: "Dosen Wali",
+		lecturerByUserID: &modelpostgre.Lecturer{
+			ID:     "lecturer-id-1",
+			UserID: "lecturer-user-id-1",
+		},
+	}
+
+	mockStudentRepo := &mockStudentRepo{
+		byID: &modelpostgre.Student{
+			ID:        "550e8400-e29b-41d4-a716-446655440000",
+			AdvisorID: "lecturer-id-1",
+		},
+	}
+
+	mockAchievementRepo := &mockAchievementRepo{
+		byID: &modelmongo.Achievement{
+			ID:              primitive.NewObjectID(),
+			StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+			AchievementType: "competition",
+			Title:           "Juara 1 Lomba",
+		},
+	}
+
+	service := servicepostgre.NewAchievementService(
+		mockAchievementRepo,
+		mockAchievementRefRepo,
+		mockUserRepo,
+		mockStudentRepo,
+		&mockNotificationService{},
+		&mockPointRubricService{
+			calculation: &modelpostgre.PointCalculationResult{Points: 90, RubricVersion: 2},
+		},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	points := 120
+	result, err := service.VerifyAchievement(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1", modelpostgre.VerifyAchievementRequest{
+		Points:              &points,
+		PointsJustification: "Kompetisi diikuti lebih dari 50 negara",
+	}, modelpostgre.AchievementVersion{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result == nil {
+		t.Fatal("Expected result, got nil")
+	}
+
+	if mockAchievementRepo.savedPoints != 120 {
+		t.Errorf("Expected overridden points 120, got %d", mockAchievementRepo.savedPoints)
+	}
+
+	if mockAchievementRepo.savedOverride == nil {
+		t.Fatal("Expected points override to be saved")
+	}
+
+	if mockAchievementRepo.savedOverride.CalculatedPoints != 90 {
+		t.Errorf("Expected calculated points 90, got %d", mockAchievementRepo.savedOverride.CalculatedPoints)
+	}
+
+	if mockAchievementRepo.savedOverride.OverriddenBy != "lecturer-user-id-1" {
+		t.Errorf("Expected overridden by 'lecturer-user-id-1', got '%s'", mockAchievementRepo.savedOverride.OverriddenBy)
+	}
+}
+
+func TestVerifyAchievement_PointsOverrideWithoutJustification(t *testing.T) {
+	ctx := setupTestContext()
+
+	now := time.Now()
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		byMongoID: &modelpostgre.AchievementReference{
+			ID:                 "ref-id-1",
+			StudentID:          "550e8400-e29b-41d4-a716-446655440000",
+			MongoAchievementID: "mongo-id-1",
+			Status:             modelpostgre.AchievementStatusSubmitted,
+		},
+		byID: &modelpostgre.AchievementReference{
+			ID:                 "ref-id-1",
+			StudentID:          "550e8400-e29b-41d4-a716-446655440000",
+			MongoAchievementID: "mongo-id-1",
+			Status:             modelpostgre.AchievementStatusVerified,
+			VerifiedAt:         &now,
+			VerifiedBy:         stringPtr("lecturer-id-1"),
+		},
+	}
+
+	mockUserRepo := &mockUserRepo{
+		roleName: "Dosen Wali",
+		lecturerByUserID: &modelpostgre.Lecturer{
+			ID:     "lecturer-id-1",
+			UserID: "lecturer-user-id-1",
+		},
+	}
+
+	mockStudentRepo := &mockStudentRepo{
+		byID: &modelpostgre.Student{
+			ID:        "550e8400-e29b-41d4-a716-446655440000",
+			AdvisorID: "lecturer-id-1",
+		},
+	}
+
+	mockAchievementRepo := &mockAchievementRepo{
+		byID: &modelmongo.Achievement{
+			ID:              primitive.NewObjectID(),
+			StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+			AchievementType: "competition",
+			Title:           "Juara 1 Lomba",
+		},
+	}
+
+	service := servicepostgre.NewAchievementService(
+		mockAchievementRepo,
+		mockAchievementRefRepo,
+		mockUserRepo,
+		mockStudentRepo,
+		&mockNotificationService{},
+		&mockPointRubricService{
+			calculation: &modelpostgre.PointCalculationResult{Points: 90, RubricVersion: 2},
+		},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	points := 120
+	_, err := service.VerifyAchievement(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1", modelpostgre.VerifyAchievementRequest{
+		Points: &points,
+	}, modelpostgre.AchievementVersion{})
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if !contains(err.Error(), "justifikasi wajib diisi") {
+		t.Errorf("Expected justification error, got: %v", err)
+	}
+
+	if mockAchievementRepo.savedOverride != nil || mockAchievementRepo.savedPoints != 0 {
+		t.Error("Expected points not to be saved when justification is missing")
+	}
 }
 
 func TestRejectAchievement_Success(t *testing.T) {
@@ -884,13 +1822,20 @@ func TestRejectAchievement_Success(t *testing.T) {
 		mockUserRepo,
 		mockStudentRepo,
 		mockNotificationService,
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
 	)
 
 	req := modelpostgre.RejectAchievementRequest{
 		RejectionNote: "Data tidak lengkap",
 	}
 
-	result, err := service.RejectAchievement(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1", req)
+	result, err := service.RejectAchievement(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1", req, modelpostgre.AchievementVersion{})
 
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
@@ -918,13 +1863,20 @@ func TestRejectAchievement_MissingRejectionNote(t *testing.T) {
 		mockUserRepo,
 		&mockStudentRepo{},
 		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
 	)
 
 	req := modelpostgre.RejectAchievementRequest{
 		RejectionNote: "",
 	}
 
-	_, err := service.RejectAchievement(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1", req)
+	_, err := service.RejectAchievement(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1", req, modelpostgre.AchievementVersion{})
 
 	if err == nil {
 		t.Fatal("Expected error, got nil")
@@ -961,6 +1913,13 @@ func TestDeleteAchievement_Success(t *testing.T) {
 		mockUserRepo,
 		mockStudentRepo,
 		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
 	)
 
 	result, err := service.DeleteAchievement(ctx, "user-id-1", "role-id-1", "mongo-id-1")
@@ -1017,6 +1976,13 @@ func TestGetAchievementByID_Success(t *testing.T) {
 		mockUserRepo,
 		mockStudentRepo,
 		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
 	)
 
 	result, err := service.GetAchievementByID(ctx, "user-id-1", "role-id-1", "mongo-id-1")
@@ -1074,6 +2040,13 @@ func TestGetAchievementHistory_Success(t *testing.T) {
 		mockUserRepo,
 		mockStudentRepo,
 		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
 	)
 
 	result, err := service.GetAchievementHistory(ctx, "user-id-1", "role-id-1", "mongo-id-1")
@@ -1117,6 +2090,13 @@ func TestGetAchievementHistory_NotFound(t *testing.T) {
 		mockUserRepo,
 		&mockStudentRepo{},
 		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
 	)
 
 	_, err := service.GetAchievementHistory(ctx, "user-id-1", "role-id-1", "mongo-id-1")
@@ -1144,6 +2124,13 @@ func TestGetAchievementStats_Success(t *testing.T) {
 		&mockUserRepo{},
 		&mockStudentRepo{},
 		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
 	)
 
 	result, err := service.GetAchievementStats(ctx)
@@ -1179,3 +2166,11 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func (m *mockAchievementRefRepo) GetAchievementReferencesByMongoIDs(ctx context.Context, mongoIDs []string) ([]modelpostgre.AchievementReference, error) {
+	m.mongoIDLookups = append(m.mongoIDLookups, mongoIDs)
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.byMongoIDs, nil
+}
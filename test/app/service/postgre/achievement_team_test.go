@@ -0,0 +1,441 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	teamOwnerID  = "550e8400-e29b-41d4-a716-446655440000"
+	teamMemberID = "660e8400-e29b-41d4-a716-446655440000"
+)
+
+func newTeamStudentRepo(ownerAdvisorID string, memberAdvisorID string) *mockStudentRepo {
+	return &mockStudentRepo{
+		studentIDByUserID: teamOwnerID,
+		studentsByID: map[string]*modelpostgre.Student{
+			teamOwnerID:  {ID: teamOwnerID, UserID: "user-id-1", AdvisorID: ownerAdvisorID},
+			teamMemberID: {ID: teamMemberID, UserID: "user-id-2", AdvisorID: memberAdvisorID},
+		},
+	}
+}
+
+func newTeamAchievement(confirmed bool) *modelmongo.Achievement {
+	return &modelmongo.Achievement{
+		ID:              primitive.NewObjectID(),
+		StudentID:       teamOwnerID,
+		AchievementType: "competition",
+		Title:           "Juara 1 Hackathon",
+		Points:          100,
+		Details: modelmongo.AchievementDetails{
+			CompetitionName:  stringPtr("Hackathon"),
+			CompetitionLevel: stringPtr(modelmongo.CompetitionLevelNational),
+		},
+		TeamMembers: []modelmongo.TeamMember{
+			{StudentID: teamOwnerID, Role: "Ketua", PointShare: 60, Confirmed: true},
+			{StudentID: teamMemberID, Role: "Anggota", PointShare: 40, Confirmed: confirmed},
+		},
+	}
+}
+
+func TestCreateAchievement_TeamInvalidShares(t *testing.T) {
+	ctx := setupTestContext()
+
+	eventDate := time.Now()
+	service := servicepostgre.NewAchievementService(
+		&mockAchievementRepo{},
+		&mockAchievementRefRepo{},
+		&mockUserRepo{roleName: "Mahasiswa"},
+		newTeamStudentRepo("lecturer-id-1", "lecturer-id-2"),
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	_, err := service.CreateAchievement(ctx, "user-id-1", "role-id-1", modelmongo.CreateAchievementRequest{
+		AchievementType: "competition",
+		Title:           "Juara 1 Hackathon",
+		Description:     "Juara 1 hackathon nasional",
+		Details: modelmongo.AchievementDetails{
+			CompetitionName:  stringPtr("Hackathon"),
+			CompetitionLevel: stringPtr(modelmongo.CompetitionLevelNational),
+			EventDate:        &eventDate,
+		},
+		TeamMembers: []modelmongo.TeamMemberRequest{
+			{StudentID: teamOwnerID, Role: "Ketua", PointShare: 70},
+			{StudentID: teamMemberID, Role: "", PointShare: 40},
+		},
+		DesignatedAdvisorID: "lecturer-id-9",
+	})
+
+	var validationErr *modelmongo.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+
+	fields := map[string]bool{}
+	for _, fieldErr := range validationErr.Errors {
+		fields[fieldErr.Field] = true
+	}
+
+	for _, field := range []string{"teamMembers[1].role", "teamMembers.pointShare", "designatedAdvisorId"} {
+		if !fields[field] {
+			t.Errorf("Expected field error for %s, got %+v", field, validationErr.Errors)
+		}
+	}
+}
+
+func TestCreateAchievement_TeamInvitesMembers(t *testing.T) {
+	ctx := setupTestContext()
+
+	eventDate := time.Now()
+	mockNotificationService := &mockNotificationService{}
+	service := servicepostgre.NewAchievementService(
+		&mockAchievementRepo{},
+		&mockAchievementRefRepo{},
+		&mockUserRepo{roleName: "Mahasiswa"},
+		newTeamStudentRepo("lecturer-id-1", "lecturer-id-2"),
+		mockNotificationService,
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	result, err := service.CreateAchievement(ctx, "user-id-1", "role-id-1", modelmongo.CreateAchievementRequest{
+		AchievementType: "competition",
+		Title:           "Juara 1 Hackathon",
+		Description:     "Juara 1 hackathon nasional",
+		Details: modelmongo.AchievementDetails{
+			CompetitionName:  stringPtr("Hackathon"),
+			CompetitionLevel: stringPtr(modelmongo.CompetitionLevelNational),
+			EventDate:        &eventDate,
+		},
+		TeamMembers: []modelmongo.TeamMemberRequest{
+			{StudentID: teamOwnerID, Role: "Ketua", PointShare: 60},
+			{StudentID: teamMemberID, Role: "Anggota", PointShare: 40},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	members := result.Data.TeamMembers
+	if len(members) != 2 || !members[0].Confirmed || members[1].Confirmed {
+		t.Errorf("Expected owner confirmed and member pending, got %+v", members)
+	}
+
+	if len(mockNotificationService.invitedStudentIDs) != 1 || mockNotificationService.invitedStudentIDs[0] != teamMemberID {
+		t.Errorf("Expected invitation for %s, got %v", teamMemberID, mockNotificationService.invitedStudentIDs)
+	}
+}
+
+func TestConfirmTeamParticipation_Success(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRepo := &mockAchievementRepo{byID: newTeamAchievement(false)}
+	studentRepo := newTeamStudentRepo("lecturer-id-1", "lecturer-id-2")
+	studentRepo.studentIDByUserID = teamMemberID
+
+	service := servicepostgre.NewAchievementService(
+		mockAchievementRepo,
+		&mockAchievementRefRepo{
+			byMongoID: &modelpostgre.AchievementReference{ID: "ref-id-1", StudentID: teamOwnerID, Status: modelpostgre.AchievementStatusDraft},
+		},
+		&mockUserRepo{roleName: "Mahasiswa"},
+		studentRepo,
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	result, err := service.ConfirmTeamParticipation(ctx, "user-id-2", "role-id-1", "mongo-id-1")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockAchievementRepo.confirmedStudentID != teamMemberID {
+		t.Errorf("Expected confirmation saved for %s, got %q", teamMemberID, mockAchievementRepo.confirmedStudentID)
+	}
+
+	if !result.Data[1].Confirmed || result.Data[1].ConfirmedAt == nil {
+		t.Errorf("Expected member confirmed in response, got %+v", result.Data[1])
+	}
+}
+
+func TestConfirmTeamParticipation_NotMember(t *testing.T) {
+	ctx := setupTestContext()
+
+	studentRepo := newTeamStudentRepo("lecturer-id-1", "lecturer-id-2")
+	studentRepo.studentIDByUserID = "770e8400-e29b-41d4-a716-446655440000"
+
+	service := servicepostgre.NewAchievementService(
+		&mockAchievementRepo{byID: newTeamAchievement(false)},
+		&mockAchievementRefRepo{
+			byMongoID: &modelpostgre.AchievementReference{ID: "ref-id-1", StudentID: teamOwnerID, Status: modelpostgre.AchievementStatusDraft},
+		},
+		&mockUserRepo{roleName: "Mahasiswa"},
+		studentRepo,
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	_, err := service.ConfirmTeamParticipation(ctx, "user-id-3", "role-id-1", "mongo-id-1")
+
+	if err == nil || !contains(err.Error(), "akses ditolak") {
+		t.Errorf("Expected 'akses ditolak' error, got %v", err)
+	}
+}
+
+func TestSubmitAchievement_TeamRequiresConfirmation(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := servicepostgre.NewAchievementService(
+		&mockAchievementRepo{byID: newTeamAchievement(false)},
+		&mockAchievementRefRepo{
+			byMongoID: &modelpostgre.AchievementReference{ID: "ref-id-1", StudentID: teamOwnerID, Status: modelpostgre.AchievementStatusDraft},
+		},
+		&mockUserRepo{roleName: "Mahasiswa"},
+		newTeamStudentRepo("lecturer-id-1", "lecturer-id-2"),
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	_, err := service.SubmitAchievement(ctx, "user-id-1", "role-id-1", "mongo-id-1")
+
+	if err == nil || !contains(err.Error(), teamMemberID) {
+		t.Errorf("Expected error listing unconfirmed member, got %v", err)
+	}
+}
+
+func TestVerifyAchievement_TeamWaitsForEveryAdvisor(t *testing.T) {
+	ctx := setupTestContext()
+
+	achievement := newTeamAchievement(true)
+	mockAchievementRepo := &mockAchievementRepo{byID: achievement}
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		byMongoID: &modelpostgre.AchievementReference{ID: "ref-id-1", StudentID: teamOwnerID, Status: modelpostgre.AchievementStatusSubmitted},
+		byID:      &modelpostgre.AchievementReference{ID: "ref-id-1", StudentID: teamOwnerID, Status: modelpostgre.AchievementStatusVerified},
+	}
+	mockUserRepo := &mockUserRepo{
+		roleName:         "Dosen Wali",
+		lecturerByUserID: &modelpostgre.Lecturer{ID: "lecturer-id-2", UserID: "lecturer-user-id-2"},
+	}
+
+	service := servicepostgre.NewAchievementService(
+		mockAchievementRepo,
+		mockAchievementRefRepo,
+		mockUserRepo,
+		newTeamStudentRepo("lecturer-id-1", "lecturer-id-2"),
+		&mockNotificationService{},
+		&mockPointRubricService{
+			calculation: &modelpostgre.PointCalculationResult{Points: 100, RubricVersion: 1},
+		},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	// verifikasi pertama oleh dosen wali anggota, masih menunggu dosen wali pembuat
+	result, err := service.VerifyAchievement(ctx, "lecturer-user-id-2", "role-id-1", "mongo-id-1", modelpostgre.VerifyAchievementRequest{}, modelpostgre.AchievementVersion{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.PendingAdvisorIDs) != 1 || result.PendingAdvisorIDs[0] != "lecturer-id-1" {
+		t.Fatalf("Expected pending advisor lecturer-id-1, got %v", result.PendingAdvisorIDs)
+	}
+
+	if mockAchievementRepo.savedRubricVersion != 0 {
+		t.Error("Expected points not calculated before all advisors verify")
+	}
+
+	// verifikasi ulang oleh dosen wali yang sama ditolak
+	achievement.TeamVerifications = mockAchievementRepo.savedVerifications
+	if _, err := service.VerifyAchievement(ctx, "lecturer-user-id-2", "role-id-1", "mongo-id-1", modelpostgre.VerifyAchievementRequest{}, modelpostgre.AchievementVersion{}); err == nil {
+		t.Error("Expected error for repeated verification by the same advisor")
+	}
+
+	// verifikasi terakhir oleh dosen wali pembuat menyelesaikan verifikasi
+	mockUserRepo.lecturerByUserID = &modelpostgre.Lecturer{ID: "lecturer-id-1", UserID: "lecturer-user-id-1"}
+	result, err = service.VerifyAchievement(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1", modelpostgre.VerifyAchievementRequest{}, modelpostgre.AchievementVersion{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.PendingAdvisorIDs) != 0 || result.Data.Status != modelpostgre.AchievementStatusVerified {
+		t.Errorf("Expected verified with no pending advisors, got %+v", result)
+	}
+
+	if mockAchievementRepo.savedPoints != 100 {
+		t.Errorf("Expected points 100, got %d", mockAchievementRepo.savedPoints)
+	}
+}
+
+func TestVerifyAchievement_TeamDesignatedAdvisorOnly(t *testing.T) {
+	ctx := setupTestContext()
+
+	achievement := newTeamAchievement(true)
+	achievement.DesignatedAdvisorID = "lecturer-id-1"
+
+	service := servicepostgre.NewAchievementService(
+		&mockAchievementRepo{byID: achievement},
+		&mockAchievementRefRepo{
+			byMongoID: &modelpostgre.AchievementReference{ID: "ref-id-1", StudentID: teamOwnerID, Status: modelpostgre.AchievementStatusSubmitted},
+		},
+		&mockUserRepo{
+			roleName:         "Dosen Wali",
+			lecturerByUserID: &modelpostgre.Lecturer{ID: "lecturer-id-2", UserID: "lecturer-user-id-2"},
+		},
+		newTeamStudentRepo("lecturer-id-1", "lecturer-id-2"),
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	_, err := service.VerifyAchievement(ctx, "lecturer-user-id-2", "role-id-1", "mongo-id-1", modelpostgre.VerifyAchievementRequest{}, modelpostgre.AchievementVersion{})
+
+	if err == nil || !contains(err.Error(), "akses ditolak") {
+		t.Errorf("Expected 'akses ditolak' error, got %v", err)
+	}
+}
+
+func newTeamVerifyService(achievementRepo *mockAchievementRepo, refRepo *mockAchievementRefRepo, userRepo *mockUserRepo) servicepostgre.IAchievementService {
+	return servicepostgre.NewAchievementService(
+		achievementRepo,
+		refRepo,
+		userRepo,
+		newTeamStudentRepo("lecturer-id-1", "lecturer-id-2"),
+		&mockNotificationService{},
+		&mockPointRubricService{
+			calculation: &modelpostgre.PointCalculationResult{Points: 100, RubricVersion: 1},
+		},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+}
+
+func TestVerifyAchievement_TeamConcurrentAdvisorsKeepBothVerifications(t *testing.T) {
+	ctx := setupTestContext()
+
+	achievementRepo := &mockAchievementRepo{byID: newTeamAchievement(true)}
+	refRepo := &mockAchievementRefRepo{
+		byMongoID: &modelpostgre.AchievementReference{ID: "ref-id-1", StudentID: teamOwnerID, Status: modelpostgre.AchievementStatusSubmitted},
+		byID:      &modelpostgre.AchievementReference{ID: "ref-id-1", StudentID: teamOwnerID, Status: modelpostgre.AchievementStatusVerified},
+	}
+	userRepo := &mockUserRepo{
+		roleName:         "Dosen Wali",
+		lecturerByUserID: &modelpostgre.Lecturer{ID: "lecturer-id-1", UserID: "lecturer-user-id-1"},
+	}
+	service := newTeamVerifyService(achievementRepo, refRepo, userRepo)
+
+	// dosen wali anggota memverifikasi setelah dosen wali pembuat membaca prestasi tetapi sebelum verifikasinya tersimpan
+	var concurrent *modelpostgre.VerifyAchievementResponse
+	achievementRepo.beforeVerification = func() {
+		userRepo.lecturerByUserID = &modelpostgre.Lecturer{ID: "lecturer-id-2", UserID: "lecturer-user-id-2"}
+		result, err := service.VerifyAchievement(ctx, "lecturer-user-id-2", "role-id-1", "mongo-id-1", modelpostgre.VerifyAchievementRequest{}, modelpostgre.AchievementVersion{})
+		if err != nil {
+			t.Fatalf("Expected no error for concurrent advisor, got %v", err)
+		}
+		concurrent = result
+		userRepo.lecturerByUserID = &modelpostgre.Lecturer{ID: "lecturer-id-1", UserID: "lecturer-user-id-1"}
+	}
+
+	result, err := service.VerifyAchievement(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1", modelpostgre.VerifyAchievementRequest{}, modelpostgre.AchievementVersion{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if concurrent == nil || len(concurrent.PendingAdvisorIDs) != 1 || concurrent.PendingAdvisorIDs[0] != "lecturer-id-1" {
+		t.Fatalf("Expected concurrent advisor waiting for lecturer-id-1, got %+v", concurrent)
+	}
+	if len(result.PendingAdvisorIDs) != 0 || result.Data.Status != modelpostgre.AchievementStatusVerified {
+		t.Errorf("Expected last advisor to finalize verification, got %+v", result)
+	}
+	if len(achievementRepo.savedVerifications) != 2 {
+		t.Errorf("Expected both advisor verifications kept, got %+v", achievementRepo.savedVerifications)
+	}
+	if achievementRepo.savedPoints != 100 {
+		t.Errorf("Expected points 100, got %d", achievementRepo.savedPoints)
+	}
+}
+
+func TestVerifyAchievement_TeamRetryAfterFailedFinalization(t *testing.T) {
+	ctx := setupTestContext()
+
+	achievement := newTeamAchievement(true)
+	achievement.TeamVerifications = []modelmongo.TeamVerification{{AdvisorID: "lecturer-id-2", VerifiedBy: "lecturer-user-id-2", VerifiedAt: time.Now()}}
+	achievementRepo := &mockAchievementRepo{byID: achievement}
+	refRepo := &mockAchievementRefRepo{
+		byMongoID:       &modelpostgre.AchievementReference{ID: "ref-id-1", StudentID: teamOwnerID, Status: modelpostgre.AchievementStatusSubmitted},
+		byID:            &modelpostgre.AchievementReference{ID: "ref-id-1", StudentID: teamOwnerID, Status: modelpostgre.AchievementStatusVerified},
+		updateVerifyErr: errors.New("postgres down"),
+	}
+	service := newTeamVerifyService(achievementRepo, refRepo, &mockUserRepo{
+		roleName:         "Dosen Wali",
+		lecturerByUserID: &modelpostgre.Lecturer{ID: "lecturer-id-1", UserID: "lecturer-user-id-1"},
+	})
+
+	if _, err := service.VerifyAchievement(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1", modelpostgre.VerifyAchievementRequest{}, modelpostgre.AchievementVersion{}); err == nil {
+		t.Fatal("Expected error when reference verification fails")
+	}
+	if len(achievementRepo.savedVerifications) != 1 || achievementRepo.savedVerifications[0].AdvisorID != "lecturer-id-2" {
+		t.Fatalf("Expected final verification removed by compensation, got %+v", achievementRepo.savedVerifications)
+	}
+
+	// percobaan ulang oleh dosen wali yang sama tidak ditolak sebagai verifikasi ganda
+	refRepo.updateVerifyErr = nil
+	result, err := service.VerifyAchievement(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1", modelpostgre.VerifyAchievementRequest{}, modelpostgre.AchievementVersion{})
+	if err != nil {
+		t.Fatalf("Expected retry to succeed, got %v", err)
+	}
+	if result.Data.Status != modelpostgre.AchievementStatusVerified || len(achievementRepo.savedVerifications) != 2 {
+		t.Errorf("Expected verified with both advisors recorded, got %+v and %+v", result, achievementRepo.savedVerifications)
+	}
+}
@@ -0,0 +1,454 @@
+package service_test
+
+import (
+	"testing"
+	"time"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newTestTrashConfig() modelpostgre.TrashRetentionConfig {
+	return modelpostgre.TrashRetentionConfig{RetentionDays: 30, PurgeInterval: 24 * time.Hour}
+}
+
+func newTrashService(refRepo *mockAchievementRefRepo, achievementRepo *mockAchievementRepo, roleName string) servicepostgre.IAchievementService {
+	return servicepostgre.NewAchievementService(
+		achievementRepo,
+		refRepo,
+		&mockUserRepo{roleName: roleName},
+		&mockStudentRepo{byID: &modelpostgre.Student{ID: "550e8400-e29b-41d4-a716-446655440000", FullName: "Budi"}},
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+}
+
+func newDeletedRef(mongoID string, deletedAt time.Time, statusBeforeDelete string) modelpostgre.DeletedAchievementReference {
+	return modelpostgre.DeletedAchievementReference{
+		AchievementReference: modelpostgre.AchievementReference{
+			ID:                 "ref-" + mongoID,
+			StudentID:          "550e8400-e29b-41d4-a716-446655440000",
+			MongoAchievementID: mongoID,
+			Status:             modelpostgre.AchievementStatusDeleted,
+		},
+		DeletedAt:          deletedAt,
+		StatusBeforeDelete: statusBeforeDelete,
+	}
+}
+
+func TestDeleteAchievement_SoftDeletesAndRecordsHistory(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		byMongoID: &modelpostgre.AchievementReference{
+			ID:                 "ref-id-1",
+			StudentID:          "550e8400-e29b-41d4-a716-446655440000",
+			MongoAchievementID: "mongo-id-1",
+			Status:             modelpostgre.AchievementStatusDraft,
+		},
+	}
+
+	service := servicepostgre.NewAchievementService(
+		&mockAchievementRepo{},
+		mockAchievementRefRepo,
+		&mockUserRepo{roleName: "Mahasiswa"},
+		&mockStudentRepo{studentIDByUserID: "550e8400-e29b-41d4-a716-446655440000"},
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	if _, err := service.DeleteAchievement(ctx, "user-id-1", "role-id-1", "mongo-id-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockAchievementRefRepo.softDeletedIDs) != 1 || mockAchievementRefRepo.softDeletedIDs[0] != "ref-id-1" {
+		t.Errorf("Expected ref-id-1 soft deleted, got %v", mockAchievementRefRepo.softDeletedIDs)
+	}
+
+	if len(mockAchievementRefRepo.recordedHistory) != 1 || mockAchievementRefRepo.recordedHistory[0].Action != modelpostgre.AchievementHistoryActionDeleted {
+		t.Errorf("Expected deleted history entry, got %+v", mockAchievementRefRepo.recordedHistory)
+	}
+}
+
+func TestGetDeletedAchievements_Success(t *testing.T) {
+	ctx := setupTestContext()
+
+	objectID := primitive.NewObjectID()
+	deletedAt := time.Now().Add(-48 * time.Hour)
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		deletedRefs: []modelpostgre.DeletedAchievementReference{
+			newDeletedRef(objectID.Hex(), deletedAt, modelpostgre.AchievementStatusVerified),
+		},
+	}
+	mockAchievementRepo := &mockAchievementRepo{
+		deletedByIDs: []modelmongo.Achievement{
+			{ID: objectID, Title: "Juara 1 Hackathon", AchievementType: "competition"},
+		},
+	}
+
+	service := newTrashService(mockAchievementRefRepo, mockAchievementRepo, "Admin")
+
+	result, err := service.GetDeletedAchievements(ctx, "admin-id", "role-id-1", 1, 10)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.Data) != 1 {
+		t.Fatalf("Expected 1 deleted achievement, got %d", len(result.Data))
+	}
+
+	item := result.Data[0]
+	if item.Title != "Juara 1 Hackathon" || item.StudentName != "Budi" {
+		t.Errorf("Expected title and student name filled, got %+v", item)
+	}
+
+	if item.StatusBeforeDelete != modelpostgre.AchievementStatusVerified {
+		t.Errorf("Expected status before delete verified, got %s", item.StatusBeforeDelete)
+	}
+
+	if !item.PurgeAt.Equal(deletedAt.AddDate(0, 0, 30)) {
+		t.Errorf("Expected purge at 30 days after delete, got %v", item.PurgeAt)
+	}
+
+	if result.Pagination.Total != 1 || result.Pagination.TotalPages != 1 {
+		t.Errorf("Expected total 1 and 1 page, got %+v", result.Pagination)
+	}
+}
+
+func TestGetDeletedAchievements_NotAdmin(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := newTrashService(&mockAchievementRefRepo{}, &mockAchievementRepo{}, "Mahasiswa")
+
+	_, err := service.GetDeletedAchievements(ctx, "user-id-1", "role-id-1", 1, 10)
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
+
+func TestRestoreAchievement_Success(t *testing.T) {
+	ctx := setupTestContext()
+
+	deletedRef := newDeletedRef("mongo-id-1", time.Now().Add(-24*time.Hour), modelpostgre.AchievementStatusSubmitted)
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		deletedRef: &deletedRef,
+		byID: &modelpostgre.AchievementReference{
+			ID:     "ref-mongo-id-1",
+			Status: modelpostgre.AchievementStatusSubmitted,
+		},
+	}
+	mockAchievementRepo := &mockAchievementRepo{}
+
+	service := newTrashService(mockAchievementRefRepo, mockAchievementRepo, "Admin")
+
+	result, err := service.RestoreAchievement(ctx, "admin-id", "role-id-1", "mongo-id-1")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Data.Status != modelpostgre.AchievementStatusSubmitted {
+		t.Errorf("Expected status submitted, got %s", result.Data.Status)
+	}
+
+	if len(mockAchievementRepo.restoredIDs) != 1 || mockAchievementRepo.restoredIDs[0] != "mongo-id-1" {
+		t.Errorf("Expected mongo-id-1 restored, got %v", mockAchievementRepo.restoredIDs)
+	}
+
+	history := mockAchievementRefRepo.recordedHistory
+	if len(history) != 1 || history[0].Action != modelpostgre.AchievementHistoryActionRestored || history[0].Status != modelpostgre.AchievementStatusSubmitted {
+		t.Errorf("Expected restored history entry with status submitted, got %+v", history)
+	}
+}
+
+func TestRestoreAchievement_NotInTrash(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRepo := &mockAchievementRepo{}
+	service := newTrashService(&mockAchievementRefRepo{}, mockAchievementRepo, "Admin")
+
+	_, err := service.RestoreAchievement(ctx, "admin-id", "role-id-1", "mongo-id-1")
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if len(mockAchievementRepo.restoredIDs) != 0 {
+		t.Errorf("Expected nothing restored, got %v", mockAchievementRepo.restoredIDs)
+	}
+}
+
+func TestRestoreAchievement_NotAdmin(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := newTrashService(&mockAchievementRefRepo{}, &mockAchievementRepo{}, "Dosen Wali")
+
+	_, err := service.RestoreAchievement(ctx, "user-id-1", "role-id-1", "mongo-id-1")
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
+
+func TestRunTrashPurge_DryRun(t *testing.T) {
+	ctx := setupTestContext()
+
+	objectID := primitive.NewObjectID()
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		deletedRefs: []modelpostgre.DeletedAchievementReference{
+			newDeletedRef(objectID.Hex(), time.Now().AddDate(0, 0, -40), modelpostgre.AchievementStatusDraft),
+			newDeletedRef(primitive.NewObjectID().Hex(), time.Now().AddDate(0, 0, -5), modelpostgre.AchievementStatusDraft),
+		},
+	}
+	mockAchievementRepo := &mockAchievementRepo{
+		deletedByIDs: []modelmongo.Achievement{
+			{
+				ID:          objectID,
+				Title:       "Lomba Lama",
+				Attachments: []modelmongo.Attachment{{FileName: "sertifikat.pdf", FileURL: "/uploads/sertifikat.pdf"}},
+			},
+		},
+	}
+
+	service := newTrashService(mockAchievementRefRepo, mockAchievementRepo, "Admin")
+
+	result, err := service.RunTrashPurge(ctx, 30, true)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Total != 1 || result.Purged != 0 {
+		t.Errorf("Expected 1 expired and 0 purged, got total %d purged %d", result.Total, result.Purged)
+	}
+
+	if len(result.Items) != 1 || result.Items[0].Title != "Lomba Lama" || len(result.Items[0].Files) != 1 {
+		t.Errorf("Expected expired item with its attachment listed, got %+v", result.Items)
+	}
+
+	if len(mockAchievementRepo.purgedIDs) != 0 || len(mockAchievementRefRepo.purgedRefIDs) != 0 {
+		t.Errorf("Expected nothing purged on dry run, got %v %v", mockAchievementRepo.purgedIDs, mockAchievementRefRepo.purgedRefIDs)
+	}
+}
+
+func TestRunTrashPurge_PurgesExpired(t *testing.T) {
+	ctx := setupTestContext()
+
+	objectID := primitive.NewObjectID()
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		deletedRefs: []modelpostgre.DeletedAchievementReference{
+			newDeletedRef(objectID.Hex(), time.Now().AddDate(0, 0, -40), modelpostgre.AchievementStatusDraft),
+			newDeletedRef(primitive.NewObjectID().Hex(), time.Now().AddDate(0, 0, -5), modelpostgre.AchievementStatusDraft),
+		},
+	}
+	mockAchievementRepo := &mockAchievementRepo{
+		deletedByIDs: []modelmongo.Achievement{{ID: objectID, Title: "Lomba Lama"}},
+	}
+
+	service := newTrashService(mockAchievementRefRepo, mockAchievementRepo, "Admin")
+
+	result, err := service.RunTrashPurge(ctx, 30, false)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Purged != 1 {
+		t.Errorf("Expected 1 purged, got %d", result.Purged)
+	}
+
+	if len(mockAchievementRepo.purgedIDs) != 1 || mockAchievementRepo.purgedIDs[0] != objectID.Hex() {
+		t.Errorf("Expected mongo document purged, got %v", mockAchievementRepo.purgedIDs)
+	}
+
+	if len(mockAchievementRefRepo.purgedRefIDs) != 1 || mockAchievementRefRepo.purgedRefIDs[0] != "ref-"+objectID.Hex() {
+		t.Errorf("Expected reference purged, got %v", mockAchievementRefRepo.purgedRefIDs)
+	}
+}
+
+func TestRunTrashPurge_KeepsFileSharedWithOtherAchievement(t *testing.T) {
+	ctx := setupTestContext()
+
+	objectID := primitive.NewObjectID()
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		deletedRefs: []modelpostgre.DeletedAchievementReference{
+			newDeletedRef(objectID.Hex(), time.Now().AddDate(0, 0, -40), modelpostgre.AchievementStatusDraft),
+		},
+	}
+	mockAchievementRepo := &mockAchievementRepo{
+		deletedByIDs: []modelmongo.Achievement{
+			{
+				ID:    objectID,
+				Title: "Lomba Lama",
+				Attachments: []modelmongo.Attachment{
+					{FileName: "sertifikat.pdf", FileURL: "/uploads/bersama.pdf"},
+					{FileName: "foto.png", FileURL: "/uploads/sendiri.png"},
+				},
+			},
+		},
+		fileRefs: map[string]int64{"/uploads/bersama.pdf": 2, "/uploads/sendiri.png": 1},
+	}
+
+	service := newTrashService(mockAchievementRefRepo, mockAchievementRepo, "Admin")
+
+	result, err := service.RunTrashPurge(ctx, 30, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.Items) != 1 || len(result.Items[0].Files) != 1 || result.Items[0].Files[0] != "sendiri.png" {
+		t.Errorf("Expected only the unshared file listed, got %+v", result.Items)
+	}
+}
+
+func TestPurgeDeletedAchievements_NotAdmin(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := newTrashService(&mockAchievementRefRepo{}, &mockAchievementRepo{}, "Mahasiswa")
+
+	_, err := service.PurgeDeletedAchievements(ctx, "user-id-1", "role-id-1", false)
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+}
+
+func newPurgeWithFilesFixture() (*mockAchievementRefRepo, *mockAchievementRepo, *mockFileStorage) {
+	objectID := primitive.NewObjectID()
+	refRepo := &mockAchievementRefRepo{
+		deletedRefs: []modelpostgre.DeletedAchievementReference{
+			newDeletedRef(objectID.Hex(), time.Now().AddDate(0, 0, -40), modelpostgre.AchievementStatusDraft),
+		},
+	}
+	achievementRepo := &mockAchievementRepo{
+		deletedByIDs: []modelmongo.Achievement{
+			{
+				ID:          objectID,
+				Title:       "Lomba Lama",
+				Attachments: []modelmongo.Attachment{{FileName: "foto.png", FileURL: "/uploads/foto.png"}},
+			},
+		},
+		fileRefs: map[string]int64{"/uploads/foto.png": 1},
+	}
+	return refRepo, achievementRepo, &mockFileStorage{}
+}
+
+func newTrashServiceWithStorage(refRepo *mockAchievementRefRepo, achievementRepo *mockAchievementRepo, storage *mockFileStorage) servicepostgre.IAchievementService {
+	return servicepostgre.NewAchievementService(
+		achievementRepo,
+		refRepo,
+		&mockUserRepo{roleName: "Admin"},
+		&mockStudentRepo{},
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		storage,
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+}
+
+func TestRunTrashPurge_DeletesFilesAfterPurge(t *testing.T) {
+	ctx := setupTestContext()
+
+	refRepo, achievementRepo, storage := newPurgeWithFilesFixture()
+	service := newTrashServiceWithStorage(refRepo, achievementRepo, storage)
+
+	result, err := service.RunTrashPurge(ctx, 30, false)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Purged != 1 || result.FilesDeleted != 1 {
+		t.Errorf("Expected 1 purged and 1 file deleted, got %+v", result)
+	}
+
+	if len(storage.deleted) != 1 || storage.deleted[0] != "foto.png" {
+		t.Errorf("Expected foto.png deleted, got %v", storage.deleted)
+	}
+}
+
+func TestRunTrashPurge_KeepsFilesWhenRestoredDuringPurge(t *testing.T) {
+	testCases := []struct {
+		name    string
+		prepare func(refRepo *mockAchievementRefRepo, achievementRepo *mockAchievementRepo)
+	}{
+		{
+			name: "dokumen MongoDB sudah di-restore",
+			prepare: func(refRepo *mockAchievementRefRepo, achievementRepo *mockAchievementRepo) {
+				achievementRepo.restoredBeforePurge = true
+			},
+		},
+		{
+			name: "reference sudah di-restore",
+			prepare: func(refRepo *mockAchievementRefRepo, achievementRepo *mockAchievementRepo) {
+				refRepo.refRestored = true
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := setupTestContext()
+
+			refRepo, achievementRepo, storage := newPurgeWithFilesFixture()
+			tc.prepare(refRepo, achievementRepo)
+			service := newTrashServiceWithStorage(refRepo, achievementRepo, storage)
+
+			result, err := service.RunTrashPurge(ctx, 30, false)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			if result.Purged != 0 || result.FilesDeleted != 0 {
+				t.Errorf("Expected nothing purged, got %+v", result)
+			}
+
+			if len(storage.deleted) != 0 {
+				t.Errorf("Expected attachment files kept, got %v deleted", storage.deleted)
+			}
+		})
+	}
+}
+
+func TestGetDeletedAchievements_UsesInjectedRetention(t *testing.T) {
+	ctx := setupTestContext()
+	t.Setenv("TRASH_RETENTION_DAYS", "90")
+
+	deletedAt := time.Now().AddDate(0, 0, -1)
+	refRepo := &mockAchievementRefRepo{
+		deletedRefs: []modelpostgre.DeletedAchievementReference{
+			newDeletedRef(primitive.NewObjectID().Hex(), deletedAt, modelpostgre.AchievementStatusDraft),
+		},
+	}
+
+	service := newTrashService(refRepo, &mockAchievementRepo{}, "Admin")
+
+	result, err := service.GetDeletedAchievements(ctx, "user-id-1", "role-id-1", 1, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.Data) != 1 || !result.Data[0].PurgeAt.Equal(deletedAt.AddDate(0, 0, 30)) {
+		t.Errorf("Expected purge at from injected 30 day retention, got %+v", result.Data)
+	}
+}
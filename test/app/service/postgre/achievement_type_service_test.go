@@ -0,0 +1,342 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+)
+
+type mockAchievementTypeRepo struct {
+	types     map[string]*modelmongo.AchievementTypeDefinition
+	err       error
+	created   *modelmongo.AchievementTypeDefinition
+	updateReq *modelmongo.UpdateAchievementTypeRequest
+}
+
+func (m *mockAchievementTypeRepo) CreateAchievementType(ctx context.Context, achievementType *modelmongo.AchievementTypeDefinition) (*modelmongo.AchievementTypeDefinition, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.created = achievementType
+	return achievementType, nil
+}
+
+func (m *mockAchievementTypeRepo) GetAchievementTypeByCode(ctx context.Context, code string) (*modelmongo.AchievementTypeDefinition, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.types[code], nil
+}
+
+func (m *mockAchievementTypeRepo) GetAllAchievementTypes(ctx context.Context, includeInactive bool) ([]modelmongo.AchievementTypeDefinition, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	result := []modelmongo.AchievementTypeDefinition{}
+	for _, achievementType := range m.types {
+		if includeInactive || achievementType.IsActive {
+			result = append(result, *achievementType)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockAchievementTypeRepo) UpdateAchievementType(ctx context.Context, code string, req modelmongo.UpdateAchievementTypeRequest) (*modelmongo.AchievementTypeDefinition, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	m.updateReq = &req
+	achievementType, ok := m.types[code]
+	if !ok {
+		return nil, nil
+	}
+	if req.IsActive != nil {
+		achievementType.IsActive = *req.IsActive
+	}
+	if req.CustomFields != nil {
+		achievementType.CustomFields = req.CustomFields
+	}
+	return achievementType, nil
+}
+
+func newTestAchievementTypeRepo() *mockAchievementTypeRepo {
+	types := map[string]*modelmongo.AchievementTypeDefinition{}
+	for _, builtIn := range modelmongo.BuiltInAchievementTypes {
+		achievementType := builtIn
+		achievementType.IsBuiltIn = true
+		achievementType.IsActive = true
+		types[achievementType.Code] = &achievementType
+	}
+	return &mockAchievementTypeRepo{types: types}
+}
+
+func newTestAchievementTypeService() servicepostgre.IAchievementTypeService {
+	return servicepostgre.NewAchievementTypeService(newTestAchievementTypeRepo())
+}
+
+func float64Ptr(f float64) *float64 {
+	return &f
+}
+
+func patentAchievementType() *modelmongo.AchievementTypeDefinition {
+	return &modelmongo.AchievementTypeDefinition{
+		Code:     "patent",
+		Name:     "Paten",
+		IsActive: true,
+		CustomFields: []modelmongo.CustomFieldDefinition{
+			{Key: "patentNumber", Label: "Nomor Paten", Type: modelmongo.CustomFieldTypeText, Required: true, Pattern: `^P\d{8}$`},
+			{Key: "status", Label: "Status", Type: modelmongo.CustomFieldTypeSelect, Required: true, Options: []string{"registered", "granted"}},
+			{Key: "inventorCount", Label: "Jumlah Inventor", Type: modelmongo.CustomFieldTypeNumber, Min: float64Ptr(1), Max: float64Ptr(10)},
+			{Key: "grantedAt", Label: "Tanggal Granted", Type: modelmongo.CustomFieldTypeDate},
+			{Key: "link", Label: "Link", Type: modelmongo.CustomFieldTypeURL},
+		},
+	}
+}
+
+func TestValidateAchievement_CustomFieldsValid(t *testing.T) {
+	ctx := setupTestContext()
+
+	repo := newTestAchievementTypeRepo()
+	repo.types["patent"] = patentAchievementType()
+	service := servicepostgre.NewAchievementTypeService(repo)
+
+	err := service.ValidateAchievement(ctx, "patent", modelmongo.AchievementDetails{
+		CustomFields: map[string]interface{}{
+			"patentNumber":  "P00202401",
+			"status":        "granted",
+			"inventorCount": float64(3),
+			"grantedAt":     "2024-05-01",
+			"link":          "https://pdki-indonesia.dgip.go.id/paten/P00202401",
+		},
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestValidateAchievement_CustomFieldErrors(t *testing.T) {
+	ctx := setupTestContext()
+
+	repo := newTestAchievementTypeRepo()
+	repo.types["patent"] = patentAchievementType()
+	service := servicepostgre.NewAchievementTypeService(repo)
+
+	err := service.ValidateAchievement(ctx, "patent", modelmongo.AchievementDetails{
+		CustomFields: map[string]interface{}{
+			"patentNumber":  "12345",
+			"inventorCount": float64(20),
+			"grantedAt":     "kemarin",
+			"link":          "ftp://example.com",
+			"color":         "blue",
+		},
+	})
+
+	var validationErr *modelmongo.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected ValidationError, got %v", err)
+	}
+
+	expected := []string{
+		"details.customFields.patentNumber",
+		"details.customFields.status",
+		"details.customFields.inventorCount",
+		"details.customFields.grantedAt",
+		"details.customFields.link",
+		"details.customFields.color",
+	}
+
+	if len(validationErr.Errors) != len(expected) {
+		t.Fatalf("Expected %d field errors, got %+v", len(expected), validationErr.Errors)
+	}
+
+	for i, field := range expected {
+		if validationErr.Errors[i].Field != field {
+			t.Errorf("Expected error #%d on %s, got %s", i+1, field, validationErr.Errors[i].Field)
+		}
+	}
+}
+
+func TestValidateAchievement_UnknownOrInactiveType(t *testing.T) {
+	ctx := setupTestContext()
+
+	repo := newTestAchievementTypeRepo()
+	inactive := patentAchievementType()
+	inactive.IsActive = false
+	repo.types["patent"] = inactive
+	service := servicepostgre.NewAchievementTypeService(repo)
+
+	for _, code := range []string{"sport", "patent"} {
+		err := service.ValidateAchievement(ctx, code, modelmongo.AchievementDetails{})
+		if err == nil {
+			t.Fatalf("Expected error for type %s, got nil", code)
+		}
+		if !contains(err.Error(), "achievement type tidak valid") {
+			t.Errorf("Expected 'achievement type tidak valid' for %s, got: %v", code, err)
+		}
+	}
+}
+
+func TestValidateAchievement_TypeWithoutSchemaAcceptsFreeCustomFields(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := newTestAchievementTypeService()
+
+	err := service.ValidateAchievement(ctx, "other", modelmongo.AchievementDetails{
+		CustomFields: map[string]interface{}{"field1": "value1"},
+	})
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestCreateAchievementType_DefinitionErrors(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := newTestAchievementTypeService()
+
+	testCases := []struct {
+		name string
+		req  modelmongo.CreateAchievementTypeRequest
+		want string
+	}{
+		{
+			name: "invalid code",
+			req:  modelmongo.CreateAchievementTypeRequest{Code: "Community Service", Name: "Pengabdian"},
+			want: "kode tipe prestasi tidak valid",
+		},
+		{
+			name: "empty name",
+			req:  modelmongo.CreateAchievementTypeRequest{Code: "community_service"},
+			want: "nama tipe prestasi wajib diisi",
+		},
+		{
+			name: "duplicate code",
+			req:  modelmongo.CreateAchievementTypeRequest{Code: "competition", Name: "Kompetisi"},
+			want: "kode tipe prestasi sudah digunakan",
+		},
+		{
+			name: "select without options",
+			req: modelmongo.CreateAchievementTypeRequest{
+				Code:         "community_service",
+				Name:         "Pengabdian",
+				CustomFields: []modelmongo.CustomFieldDefinition{{Key: "scope", Label: "Cakupan", Type: modelmongo.CustomFieldTypeSelect}},
+			},
+			want: "options wajib diisi",
+		},
+		{
+			name: "duplicate key",
+			req: modelmongo.CreateAchievementTypeRequest{
+				Code: "community_service",
+				Name: "Pengabdian",
+				CustomFields: []modelmongo.CustomFieldDefinition{
+					{Key: "location", Label: "Lokasi", Type: modelmongo.CustomFieldTypeText},
+					{Key: "location", Label: "Lokasi 2", Type: modelmongo.CustomFieldTypeText},
+				},
+			},
+			want: "duplikat",
+		},
+		{
+			name: "unknown field type",
+			req: modelmongo.CreateAchievementTypeRequest{
+				Code:         "community_service",
+				Name:         "Pengabdian",
+				CustomFields: []modelmongo.CustomFieldDefinition{{Key: "hours", Label: "Jam", Type: "integer"}},
+			},
+			want: "tipe field tidak valid",
+		},
+		{
+			name: "invalid pattern",
+			req: modelmongo.CreateAchievementTypeRequest{
+				Code:         "community_service",
+				Name:         "Pengabdian",
+				CustomFields: []modelmongo.CustomFieldDefinition{{Key: "code", Label: "Kode", Type: modelmongo.CustomFieldTypeText, Pattern: "[a-"}},
+			},
+			want: "pattern regex tidak valid",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := service.CreateAchievementType(ctx, "admin-id-1", tc.req)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !contains(err.Error(), tc.want) {
+				t.Errorf("Expected error containing '%s', got: %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestCreateAchievementType_Success(t *testing.T) {
+	ctx := setupTestContext()
+
+	repo := newTestAchievementTypeRepo()
+	service := servicepostgre.NewAchievementTypeService(repo)
+
+	result, err := service.CreateAchievementType(ctx, "admin-id-1", modelmongo.CreateAchievementTypeRequest{
+		Code: "community_service",
+		Name: "Pengabdian Masyarakat",
+		CustomFields: []modelmongo.CustomFieldDefinition{
+			{Key: "hours", Label: "Jumlah Jam", Type: modelmongo.CustomFieldTypeNumber, Required: true, Min: float64Ptr(1)},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !result.Data.IsActive || result.Data.IsBuiltIn {
+		t.Errorf("Expected active non built-in type, got %+v", result.Data)
+	}
+
+	if repo.created == nil || repo.created.CreatedBy != "admin-id-1" {
+		t.Errorf("Expected type created by admin-id-1, got %+v", repo.created)
+	}
+}
+
+func TestGetAchievementTypeSchema_Success(t *testing.T) {
+	ctx := setupTestContext()
+
+	repo := newTestAchievementTypeRepo()
+	competition := repo.types["competition"]
+	competition.CustomFields = []modelmongo.CustomFieldDefinition{
+		{Key: "teamSize", Label: "Jumlah Anggota Tim", Type: modelmongo.CustomFieldTypeNumber},
+	}
+	service := servicepostgre.NewAchievementTypeService(repo)
+
+	result, err := service.GetAchievementTypeSchema(ctx, "competition")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(result.Data.DetailFields) != 2 || result.Data.DetailFields[0].Field != "competitionName" {
+		t.Errorf("Expected competition detail fields, got %+v", result.Data.DetailFields)
+	}
+
+	if len(result.Data.CustomFields) != 1 || result.Data.CustomFields[0].Key != "teamSize" {
+		t.Errorf("Expected teamSize custom field, got %+v", result.Data.CustomFields)
+	}
+}
+
+func TestUpdateAchievementType_NotFound(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := newTestAchievementTypeService()
+
+	_, err := service.UpdateAchievementType(ctx, "patent", modelmongo.UpdateAchievementTypeRequest{Name: "Paten"})
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if err.Error() != "tipe prestasi tidak ditemukan" {
+		t.Errorf("Expected 'tipe prestasi tidak ditemukan', got: %v", err)
+	}
+}
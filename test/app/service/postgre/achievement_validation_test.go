@@ -0,0 +1,242 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestValidateAchievementDetails_Valid(t *testing.T) {
+	testCases := []struct {
+		name            string
+		achievementType string
+		details         modelmongo.AchievementDetails
+	}{
+		{
+			name:            "competition with required fields",
+			achievementType: "competition",
+			details: modelmongo.AchievementDetails{
+				CompetitionName:  stringPtr("Gemastik"),
+				CompetitionLevel: stringPtr(modelmongo.CompetitionLevelNational),
+				MedalType:        stringPtr(modelmongo.MedalTypeGold),
+			},
+		},
+		{
+			name:            "publication with ISSN and DOI",
+			achievementType: "publication",
+			details: modelmongo.AchievementDetails{
+				PublicationType:  stringPtr(modelmongo.PublicationTypeJournal),
+				PublicationTitle: stringPtr("Deep Learning"),
+				ISSN:             stringPtr("0317-8471"),
+				DOI:              stringPtr("10.1000/xyz123"),
+			},
+		},
+		{
+			name:            "academic without details",
+			achievementType: "academic",
+			details:         modelmongo.AchievementDetails{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := servicepostgre.ValidateAchievementDetails(tc.achievementType, tc.details); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateAchievementDetails_FieldErrors(t *testing.T) {
+	rank := 0
+	now := time.Now()
+
+	testCases := []struct {
+		name            string
+		achievementType string
+		details         modelmongo.AchievementDetails
+		wantField       string
+	}{
+		{
+			name:            "competition missing name",
+			achievementType: "competition",
+			details: modelmongo.AchievementDetails{
+				CompetitionLevel: stringPtr(modelmongo.CompetitionLevelNational),
+			},
+			wantField: "details.competitionName",
+		},
+		{
+			name:            "competition level outside enum",
+			achievementType: "competition",
+			details: modelmongo.AchievementDetails{
+				CompetitionName:  stringPtr("Gemastik"),
+				CompetitionLevel: stringPtr("galaxy"),
+			},
+			wantField: "details.competitionLevel",
+		},
+		{
+			name:            "publication with invalid ISSN checksum",
+			achievementType: "publication",
+			details: modelmongo.AchievementDetails{
+				PublicationType:  stringPtr(modelmongo.PublicationTypeJournal),
+				PublicationTitle: stringPtr("Deep Learning"),
+				ISSN:             stringPtr("0317-8472"),
+			},
+			wantField: "details.issn",
+		},
+		{
+			name:            "publication with invalid DOI",
+			achievementType: "publication",
+			details: modelmongo.AchievementDetails{
+				PublicationType:  stringPtr(modelmongo.PublicationTypeJournal),
+				PublicationTitle: stringPtr("Deep Learning"),
+				DOI:              stringPtr("doi:abc"),
+			},
+			wantField: "details.doi",
+		},
+		{
+			name:            "period end before start",
+			achievementType: "organization",
+			details: modelmongo.AchievementDetails{
+				OrganizationName: stringPtr("BEM"),
+				Position:         stringPtr("Ketua"),
+				Period: &modelmongo.Period{
+					Start: now,
+					End:   now.AddDate(0, -1, 0),
+				},
+			},
+			wantField: "details.period",
+		},
+		{
+			name:            "rank below one",
+			achievementType: "other",
+			details: modelmongo.AchievementDetails{
+				Rank: &rank,
+			},
+			wantField: "details.rank",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := servicepostgre.ValidateAchievementDetails(tc.achievementType, tc.details)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+
+			var validationErr *modelmongo.ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Expected ValidationError, got %T", err)
+			}
+
+			found := false
+			for _, fieldErr := range validationErr.Errors {
+				if fieldErr.Field == tc.wantField {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Expected field error for '%s', got %+v", tc.wantField, validationErr.Errors)
+			}
+		})
+	}
+}
+
+func TestCreateAchievement_InvalidDetails(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRepo := &mockAchievementRepo{}
+
+	service := servicepostgre.NewAchievementService(
+		mockAchievementRepo,
+		&mockAchievementRefRepo{},
+		&mockUserRepo{roleName: "Mahasiswa"},
+		&mockStudentRepo{
+			studentIDByUserID: "550e8400-e29b-41d4-a716-446655440000",
+			byID: &modelpostgre.Student{
+				ID: "550e8400-e29b-41d4-a716-446655440000",
+			},
+		},
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	_, err := service.CreateAchievement(ctx, "user-id-1", "role-id-1", modelmongo.CreateAchievementRequest{
+		AchievementType: "competition",
+		Title:           "Juara Lomba",
+		Description:     "Lomba tanpa detail",
+	})
+
+	var validationErr *modelmongo.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected ValidationError, got %v", err)
+	}
+
+	if len(validationErr.Errors) != 2 {
+		t.Errorf("Expected 2 field errors (competitionName, competitionLevel), got %+v", validationErr.Errors)
+	}
+}
+
+func TestSubmitAchievement_InvalidDetails(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		byMongoID: &modelpostgre.AchievementReference{
+			ID:                 "ref-id-1",
+			StudentID:          "550e8400-e29b-41d4-a716-446655440000",
+			MongoAchievementID: "mongo-id-1",
+			Status:             modelpostgre.AchievementStatusDraft,
+		},
+	}
+
+	mockAchievementRepo := &mockAchievementRepo{
+		byID: &modelmongo.Achievement{
+			ID:              primitive.NewObjectID(),
+			StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+			AchievementType: "publication",
+			Title:           "Paper",
+			Description:     "Paper lama tanpa judul publikasi",
+			Details: modelmongo.AchievementDetails{
+				PublicationType: stringPtr(modelmongo.PublicationTypeJournal),
+			},
+		},
+	}
+
+	service := servicepostgre.NewAchievementService(
+		mockAchievementRepo,
+		mockAchievementRefRepo,
+		&mockUserRepo{roleName: "Mahasiswa"},
+		&mockStudentRepo{studentIDByUserID: "550e8400-e29b-41d4-a716-446655440000"},
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	_, err := service.SubmitAchievement(ctx, "user-id-1", "role-id-1", "mongo-id-1")
+
+	var validationErr *modelmongo.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected ValidationError, got %v", err)
+	}
+
+	if validationErr.Errors[0].Field != "details.publicationTitle" {
+		t.Errorf("Expected error on details.publicationTitle, got %+v", validationErr.Errors)
+	}
+}
@@ -0,0 +1,179 @@
+package service_test
+
+import (
+	"strings"
+	"testing"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newVersionedAchievement(version int) *modelmongo.Achievement {
+	return &modelmongo.Achievement{
+		ID:              primitive.NewObjectID(),
+		StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+		AchievementType: "other",
+		Title:           "Relawan Bencana",
+		Version:         version,
+	}
+}
+
+func newVersionedReference(status string, version int) *modelpostgre.AchievementReference {
+	return &modelpostgre.AchievementReference{
+		ID:                 "ref-id-1",
+		StudentID:          "550e8400-e29b-41d4-a716-446655440000",
+		MongoAchievementID: "mongo-id-1",
+		Status:             status,
+		Version:            version,
+	}
+}
+
+func newVersionTestService(achievementRepo *mockAchievementRepo, refRepo *mockAchievementRefRepo, userRepo *mockUserRepo) servicepostgre.IAchievementService {
+	return servicepostgre.NewAchievementService(
+		achievementRepo,
+		refRepo,
+		userRepo,
+		&mockStudentRepo{
+			studentIDByUserID: "550e8400-e29b-41d4-a716-446655440000",
+			byID:              &modelpostgre.Student{ID: "550e8400-e29b-41d4-a716-446655440000", AdvisorID: "lecturer-id-1"},
+		},
+		&mockNotificationService{},
+		&mockPointRubricService{calculation: &modelpostgre.PointCalculationResult{Points: 10, RubricVersion: 1}},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+}
+
+func newVersionTestLecturer() *mockUserRepo {
+	return &mockUserRepo{
+		roleName:         "Dosen Wali",
+		lecturerByUserID: &modelpostgre.Lecturer{ID: "lecturer-id-1", UserID: "lecturer-user-id-1"},
+	}
+}
+
+func TestGetAchievementByID_IncludesETag(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := newVersionTestService(
+		&mockAchievementRepo{byID: newVersionedAchievement(4)},
+		&mockAchievementRefRepo{byMongoID: newVersionedReference(modelpostgre.AchievementStatusDraft, 2)},
+		&mockUserRepo{roleName: "Admin"},
+	)
+
+	result, err := service.GetAchievementByID(ctx, "admin-id", "role-id-1", "mongo-id-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result["etag"] != `"4-2"` {
+		t.Errorf("Expected etag \"4-2\", got %v", result["etag"])
+	}
+}
+
+func TestUpdateAchievement_VersionConflict(t *testing.T) {
+	ctx := setupTestContext()
+
+	testCases := []struct {
+		name     string
+		expected modelpostgre.AchievementVersion
+		raced    bool
+	}{
+		{name: "ETag lama", expected: modelpostgre.AchievementVersion{Achievement: 1, Reference: 1}},
+		{name: "update lain masuk lebih dulu", expected: modelpostgre.AchievementVersion{Achievement: 2, Reference: 1}, raced: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			service := newVersionTestService(
+				&mockAchievementRepo{byID: newVersionedAchievement(2), versionRaced: tc.raced},
+				&mockAchievementRefRepo{byMongoID: newVersionedReference(modelpostgre.AchievementStatusDraft, 1)},
+				&mockUserRepo{roleName: "Mahasiswa"},
+			)
+
+			_, err := service.UpdateAchievement(ctx, "user-id-1", "role-id-1", "mongo-id-1", modelmongo.UpdateAchievementRequest{Title: "Judul Baru"}, tc.expected)
+
+			if err == nil || !strings.Contains(err.Error(), "konflik versi") {
+				t.Fatalf("Expected version conflict, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUpdateAchievement_ReturnsNewETag(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := newVersionTestService(
+		&mockAchievementRepo{byID: newVersionedAchievement(2)},
+		&mockAchievementRefRepo{byMongoID: newVersionedReference(modelpostgre.AchievementStatusDraft, 1)},
+		&mockUserRepo{roleName: "Mahasiswa"},
+	)
+
+	result, err := service.UpdateAchievement(ctx, "user-id-1", "role-id-1", "mongo-id-1", modelmongo.UpdateAchievementRequest{Title: "Judul Baru"}, modelpostgre.AchievementVersion{Achievement: 2, Reference: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, ok := result["etag"].(string); !ok {
+		t.Errorf("Expected etag in response, got %+v", result)
+	}
+}
+
+func TestVerifyAchievement_StaleETagDoesNotWrite(t *testing.T) {
+	ctx := setupTestContext()
+
+	refRepo := &mockAchievementRefRepo{byMongoID: newVersionedReference(modelpostgre.AchievementStatusSubmitted, 2)}
+	service := newVersionTestService(&mockAchievementRepo{byID: newVersionedAchievement(3)}, refRepo, newVersionTestLecturer())
+
+	// mahasiswa sudah mengubah prestasi (versi MongoDB 3) sejak dosen wali membuka versi 2
+	_, err := service.VerifyAchievement(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1", modelpostgre.VerifyAchievementRequest{}, modelpostgre.AchievementVersion{Achievement: 2, Reference: 2})
+
+	if err == nil || !strings.Contains(err.Error(), "konflik versi") {
+		t.Fatalf("Expected version conflict, got %v", err)
+	}
+
+	if refRepo.verifyCalled {
+		t.Error("Expected reference not to be verified")
+	}
+}
+
+func TestVerifyAchievement_ConcurrentReviewConflict(t *testing.T) {
+	ctx := setupTestContext()
+
+	refRepo := &mockAchievementRefRepo{
+		byMongoID:    newVersionedReference(modelpostgre.AchievementStatusSubmitted, 2),
+		versionRaced: true,
+	}
+	service := newVersionTestService(&mockAchievementRepo{byID: newVersionedAchievement(3)}, refRepo, newVersionTestLecturer())
+
+	_, err := service.VerifyAchievement(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1", modelpostgre.VerifyAchievementRequest{}, modelpostgre.AchievementVersion{Achievement: 3, Reference: 2})
+
+	if err == nil || !strings.Contains(err.Error(), "konflik versi") {
+		t.Fatalf("Expected version conflict, got %v", err)
+	}
+}
+
+func TestRejectAchievement_ReturnsNewETag(t *testing.T) {
+	ctx := setupTestContext()
+
+	refRepo := &mockAchievementRefRepo{
+		byMongoID: newVersionedReference(modelpostgre.AchievementStatusSubmitted, 2),
+		byID:      newVersionedReference(modelpostgre.AchievementStatusRejected, 3),
+	}
+	service := newVersionTestService(&mockAchievementRepo{byID: newVersionedAchievement(3)}, refRepo, newVersionTestLecturer())
+
+	result, err := service.RejectAchievement(ctx, "lecturer-user-id-1", "role-id-1", "mongo-id-1", modelpostgre.RejectAchievementRequest{RejectionNote: "Bukti kurang"}, modelpostgre.AchievementVersion{Achievement: 3, Reference: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.ETag != `"3-3"` {
+		t.Errorf("Expected ETag \"3-3\", got %q", result.ETag)
+	}
+}
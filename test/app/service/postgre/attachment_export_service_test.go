@@ -0,0 +1,169 @@
+package service_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newExportTestAchievement(title string, createdAt time.Time, attachments ...modelmongo.Attachment) modelmongo.Achievement {
+	return modelmongo.Achievement{
+		ID:              primitive.NewObjectID(),
+		StudentID:       "11111111-aaaa-bbbb-cccc-000000000001",
+		AchievementType: "competition",
+		Title:           title,
+		Attachments:     attachments,
+		CreatedAt:       createdAt,
+	}
+}
+
+func newExportTestRow(achievement modelmongo.Achievement, status string) modelpostgre.AchievementListRow {
+	row := newListRow(achievement.ID.Hex(), status, "Teknik Informatika", "lecturer-id-1", "Dr. Siti")
+	row.StudentID = achievement.StudentID
+	row.StudentName = "Budi Santoso"
+	return row
+}
+
+func readExportZip(t *testing.T, data []byte) (map[string]string, [][]string) {
+	t.Helper()
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("Expected valid ZIP, got %v", err)
+	}
+	files := map[string]string{}
+	for _, file := range archive.File {
+		body, _ := file.Open()
+		content, _ := io.ReadAll(body)
+		body.Close()
+		files[file.Name] = string(content)
+	}
+	manifest, err := csv.NewReader(strings.NewReader(files["manifest.csv"])).ReadAll()
+	if err != nil {
+		t.Fatalf("Expected valid manifest, got %v", err)
+	}
+	return files, manifest
+}
+
+func TestAttachmentExport_WritesFilesAndManifest(t *testing.T) {
+	now := time.Now()
+	first := newExportTestAchievement("Juara 1 Hackathon", now,
+		modelmongo.Attachment{ID: "att-1", FileName: "sertifikat.pdf", FileURL: "/uploads/hash-1.pdf", FileType: "application/pdf", Size: 9, ScanStatus: modelmongo.AttachmentScanClean},
+		modelmongo.Attachment{ID: "att-2", FileName: "sertifikat.pdf", FileURL: "/uploads/hash-2.pdf", FileType: "application/pdf", Size: 9},
+	)
+	second := newExportTestAchievement("=HYPERLINK(\"x\")", now,
+		modelmongo.Attachment{ID: "att-3", FileName: "foto.jpg", FileURL: "/uploads/hash-3.jpg", FileType: "image/jpeg", ScanStatus: modelmongo.AttachmentScanPending},
+		modelmongo.Attachment{ID: "att-4", FileName: "virus.pdf", FileURL: "/uploads/hash-4.pdf", FileType: "application/pdf", ScanStatus: modelmongo.AttachmentScanInfected},
+		modelmongo.Attachment{ID: "att-5", FileName: "hilang.pdf", FileURL: "/uploads/hash-5.pdf", FileType: "application/pdf"},
+	)
+	refRepo := &mockAchievementRefRepo{listRows: []modelpostgre.AchievementListRow{
+		newExportTestRow(first, modelpostgre.AchievementStatusVerified),
+		newExportTestRow(second, modelpostgre.AchievementStatusSubmitted),
+	}}
+	fileStorage := &mockFileStorage{objects: map[string][]byte{
+		"hash-1.pdf": []byte("%PDF-satu"),
+		"hash-2.pdf": []byte("%PDF-dua!"),
+		"hash-3.jpg": []byte("jpeg"),
+	}}
+	service := servicepostgre.NewAttachmentExportService(&mockAchievementRepo{byIDs: []modelmongo.Achievement{second, first}}, refRepo, &mockUserRepo{roleName: "Admin"}, &mockStudentRepo{}, fileStorage)
+
+	export, err := service.PrepareAttachmentExport(context.Background(), "user-id-1", "role-id-1", modelpostgre.AchievementListFilter{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasPrefix(export.FileName, "lampiran-prestasi-") || !strings.HasSuffix(export.FileName, ".zip") {
+		t.Errorf("Expected export file name, got %s", export.FileName)
+	}
+
+	var buf bytes.Buffer
+	if err := service.WriteAttachmentExport(context.Background(), export, &buf); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	files, manifest := readExportZip(t, buf.Bytes())
+
+	dir := "Teknik_Informatika/Budi_Santoso-11111111/" + first.ID.Hex() + "/"
+	if files[dir+"sertifikat.pdf"] != "%PDF-satu" || files[dir+"sertifikat-2.pdf"] != "%PDF-dua!" {
+		t.Errorf("Expected both attachments with unique paths, got %v", files)
+	}
+	if len(files) != 3 {
+		t.Errorf("Expected 2 files and manifest, got %d entries", len(files))
+	}
+
+	if len(manifest) != 6 || manifest[0][0] != "path" {
+		t.Fatalf("Expected header and 5 manifest rows, got %v", manifest)
+	}
+	if manifest[1][0] != dir+"sertifikat.pdf" || manifest[1][1] != first.ID.Hex() || manifest[1][17] != "" {
+		t.Errorf("Expected first row mapped to achievement, got %v", manifest[1])
+	}
+	if manifest[3][2] != "'=HYPERLINK(\"x\")" {
+		t.Errorf("Expected formula escaped, got %s", manifest[3][2])
+	}
+	for i, note := range []string{"masih dipindai", "malware", "gagal dibaca"} {
+		row := manifest[3+i]
+		if row[0] != "" || !strings.Contains(row[17], note) {
+			t.Errorf("Expected skipped row with note %q, got %v", note, row)
+		}
+	}
+}
+
+func TestAttachmentExport_AdvisorScopeAndDateRange(t *testing.T) {
+	day := time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+	inRange := newExportTestAchievement("Dalam rentang", day,
+		modelmongo.Attachment{ID: "att-1", FileName: "a.pdf", FileURL: "/uploads/a.pdf", FileType: "application/pdf"})
+	outOfRange := newExportTestAchievement("Di luar rentang", day.AddDate(0, 0, 1),
+		modelmongo.Attachment{ID: "att-2", FileName: "b.pdf", FileURL: "/uploads/b.pdf", FileType: "application/pdf"})
+	refRepo := &mockAchievementRefRepo{listRows: []modelpostgre.AchievementListRow{
+		newExportTestRow(inRange, modelpostgre.AchievementStatusVerified),
+		newExportTestRow(outOfRange, modelpostgre.AchievementStatusVerified),
+	}}
+	userRepo := &mockUserRepo{roleName: "Dosen Wali", lecturerByUserID: &modelpostgre.Lecturer{ID: "lecturer-id-1"}}
+	service := servicepostgre.NewAttachmentExportService(&mockAchievementRepo{byIDs: []modelmongo.Achievement{inRange, outOfRange}}, refRepo, userRepo, &mockStudentRepo{}, &mockFileStorage{})
+
+	dateTo := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	export, err := service.PrepareAttachmentExport(context.Background(), "user-id-1", "role-id-1", modelpostgre.AchievementListFilter{
+		ProgramStudies: []string{"Teknik Informatika"},
+		DateFrom:       &dateTo,
+		DateTo:         &dateTo,
+		ScopeAdvisorID: "lecturer-lain",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if refRepo.listFilter.ScopeAdvisorID != "lecturer-id-1" || refRepo.listFilter.ProgramStudies[0] != "Teknik Informatika" {
+		t.Errorf("Expected filter scoped to advisor, got %+v", refRepo.listFilter)
+	}
+	if len(export.Entries) != 1 || export.Entries[0].AchievementID != inRange.ID.Hex() {
+		t.Errorf("Expected only achievement created on dateTo, got %+v", export.Entries)
+	}
+}
+
+func TestAttachmentExport_NoAttachments(t *testing.T) {
+	achievement := newExportTestAchievement("Tanpa lampiran", time.Now())
+	refRepo := &mockAchievementRefRepo{listRows: []modelpostgre.AchievementListRow{newExportTestRow(achievement, modelpostgre.AchievementStatusDraft)}}
+	service := servicepostgre.NewAttachmentExportService(&mockAchievementRepo{byIDs: []modelmongo.Achievement{achievement}}, refRepo, &mockUserRepo{roleName: "Admin"}, &mockStudentRepo{}, &mockFileStorage{})
+
+	_, err := service.PrepareAttachmentExport(context.Background(), "user-id-1", "role-id-1", modelpostgre.AchievementListFilter{})
+	if err == nil || !strings.Contains(err.Error(), "tidak ditemukan") {
+		t.Errorf("Expected not found error, got %v", err)
+	}
+}
+
+func TestAttachmentExport_RejectsUnknownRole(t *testing.T) {
+	service := servicepostgre.NewAttachmentExportService(&mockAchievementRepo{}, &mockAchievementRefRepo{}, &mockUserRepo{roleName: "Tamu"}, &mockStudentRepo{}, &mockFileStorage{})
+
+	_, err := service.PrepareAttachmentExport(context.Background(), "user-id-1", "role-id-1", modelpostgre.AchievementListFilter{})
+	if err == nil || !strings.Contains(err.Error(), "akses ditolak") {
+		t.Errorf("Expected access denied, got %v", err)
+	}
+}
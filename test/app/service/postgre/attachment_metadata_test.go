@@ -0,0 +1,248 @@
+package service_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const metadataTestSecret = "GPS-LOKASI-RUMAH"
+
+func uploadMetadataTestFile(t *testing.T, fileName string, fileType string, content []byte) (*modelmongo.Attachment, []byte) {
+	t.Helper()
+	attachment, stored, _ := uploadMetadataTestFileWithStorage(t, fileName, fileType, content)
+	return attachment, stored
+}
+
+func uploadMetadataTestFileWithStorage(t *testing.T, fileName string, fileType string, content []byte) (*modelmongo.Attachment, []byte, *mockFileStorage) {
+	t.Helper()
+	fileStorage := &mockFileStorage{}
+	service := newAttachmentTestService(&mockAchievementRepo{
+		byID: &modelmongo.Achievement{ID: primitive.NewObjectID(), StudentID: attachmentTestStudentID, Title: "Juara"},
+	}, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	attachment, err := service.UploadFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", fileName, fileType, int64(len(content)), bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	stored, ok := fileStorage.objects[strings.TrimPrefix(attachment.FileURL, "/uploads/")]
+	if !ok {
+		t.Fatalf("Expected file stored at %s", attachment.FileURL)
+	}
+	if attachment.Size != int64(len(stored)) {
+		t.Errorf("Expected size %d from stored file, got %d", len(stored), attachment.Size)
+	}
+	return attachment, stored, fileStorage
+}
+
+func exifSegment(orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	tiff = binary.BigEndian.AppendUint16(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, 0x0112)
+	tiff = binary.BigEndian.AppendUint16(tiff, 3)
+	tiff = binary.BigEndian.AppendUint32(tiff, 1)
+	tiff = binary.BigEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0, 0, 0, 0, 0)
+	tiff = append(tiff, metadataTestSecret...)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+func twoColorJPEG(t *testing.T) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 32, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 32; x++ {
+			if x < 16 {
+				img.Set(x, y, color.RGBA{R: 255, A: 255})
+			} else {
+				img.Set(x, y, color.RGBA{B: 255, A: 255})
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 95}); err != nil {
+		t.Fatalf("Failed to encode jpeg: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestUploadFile_StripsJPEGMetadata(t *testing.T) {
+	original := twoColorJPEG(t)
+	comment := append([]byte{0xFF, 0xFE, 0x00, byte(len(metadataTestSecret) + 2)}, metadataTestSecret...)
+	content := bytes.Join([][]byte{original[:2], exifSegment(1), comment, original[2:], []byte("trailing " + metadataTestSecret)}, nil)
+
+	_, stored, fileStorage := uploadMetadataTestFileWithStorage(t, "sertifikat.jpg", "image/jpeg", content)
+
+	for _, put := range fileStorage.puts {
+		if bytes.Contains(put, []byte(metadataTestSecret)) {
+			t.Fatalf("Expected original file with metadata never written to storage")
+		}
+	}
+
+	if bytes.Contains(stored, []byte(metadataTestSecret)) {
+		t.Errorf("Expected EXIF, comment, and trailing data removed")
+	}
+	if !bytes.Equal(stored, original) {
+		t.Errorf("Expected image data kept without re-encoding")
+	}
+}
+
+func TestUploadFile_AppliesJPEGOrientation(t *testing.T) {
+	original := twoColorJPEG(t)
+	content := bytes.Join([][]byte{original[:2], exifSegment(6), original[2:]}, nil)
+
+	_, stored := uploadMetadataTestFile(t, "foto.jpg", "image/jpeg", content)
+
+	if bytes.Contains(stored, []byte(metadataTestSecret)) {
+		t.Errorf("Expected EXIF removed")
+	}
+	img, err := jpeg.Decode(bytes.NewReader(stored))
+	if err != nil {
+		t.Fatalf("Expected valid JPEG, got %v", err)
+	}
+	if img.Bounds().Dx() != 16 || img.Bounds().Dy() != 32 {
+		t.Fatalf("Expected rotated 16x32 image, got %v", img.Bounds())
+	}
+
+	top, bottom := color.RGBAModel.Convert(img.At(8, 4)).(color.RGBA), color.RGBAModel.Convert(img.At(8, 28)).(color.RGBA)
+	if top.R < 200 || top.B > 60 || bottom.B < 200 || bottom.R > 60 {
+		t.Errorf("Expected left half rotated to the top, got top %v bottom %v", top, bottom)
+	}
+}
+
+func TestUploadFile_StripsPNGTextChunks(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 4))); err != nil {
+		t.Fatalf("Failed to encode png: %v", err)
+	}
+	original := buf.Bytes()
+
+	data := append([]byte("Comment\x00"), metadataTestSecret...)
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	chunk = append(chunk, "tEXt"...)
+	chunk = append(chunk, data...)
+	chunk = binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+	content := bytes.Join([][]byte{original[:33], chunk, original[33:]}, nil)
+
+	_, stored := uploadMetadataTestFile(t, "foto.png", "image/png", content)
+
+	if !bytes.Equal(stored, original) {
+		t.Errorf("Expected tEXt chunk removed and other chunks kept")
+	}
+}
+
+func TestUploadFile_StripsDOCXAuthor(t *testing.T) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	files := map[string]string{
+		"word/document.xml": "<w:document>isi dokumen</w:document>",
+		"docProps/core.xml": `<cp:coreProperties><dc:title>Laporan</dc:title><dc:creator>Budi Santoso</dc:creator><cp:lastModifiedBy>Budi Santoso</cp:lastModifiedBy></cp:coreProperties>`,
+	}
+	for _, name := range []string{"word/document.xml", "docProps/core.xml"} {
+		entry, _ := writer.Create(name)
+		entry.Write([]byte(files[name]))
+	}
+	writer.Close()
+
+	_, stored := uploadMetadataTestFile(t, "laporan.docx", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", buf.Bytes())
+
+	archive, err := zip.NewReader(bytes.NewReader(stored), int64(len(stored)))
+	if err != nil {
+		t.Fatalf("Expected valid DOCX, got %v", err)
+	}
+	contents := map[string]string{}
+	for _, file := range archive.File {
+		body, _ := file.Open()
+		data, _ := io.ReadAll(body)
+		body.Close()
+		contents[file.Name] = string(data)
+	}
+
+	if strings.Contains(contents["docProps/core.xml"], "Budi") || !strings.Contains(contents["docProps/core.xml"], "<dc:title>Laporan</dc:title>") {
+		t.Errorf("Expected only author fields emptied, got %s", contents["docProps/core.xml"])
+	}
+	if contents["word/document.xml"] != files["word/document.xml"] {
+		t.Errorf("Expected document body unchanged, got %s", contents["word/document.xml"])
+	}
+}
+
+func TestUploadFile_RejectsDOCXZipBomb(t *testing.T) {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	entry, _ := writer.Create("docProps/core.xml")
+	entry.Write([]byte("<cp:coreProperties><dc:creator>Budi</dc:creator>"))
+	entry.Write(bytes.Repeat([]byte(" "), 11*1024*1024))
+	entry.Write([]byte("</cp:coreProperties>"))
+	writer.Close()
+
+	fileStorage := &mockFileStorage{}
+	service := newAttachmentTestService(&mockAchievementRepo{}, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	_, err := service.UploadFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "laporan.docx", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", int64(buf.Len()), bytes.NewReader(buf.Bytes()))
+	if err == nil || !strings.Contains(err.Error(), "melebihi batas") {
+		t.Fatalf("Expected oversized entry rejection, got %v", err)
+	}
+	if len(fileStorage.puts) != 0 {
+		t.Errorf("Expected nothing written to storage, got %d puts", len(fileStorage.puts))
+	}
+}
+
+func TestUploadFile_StripsPDFAuthorInPlace(t *testing.T) {
+	content := "%PDF-1.4\n1 0 obj << /Title (Sertifikat) /Author (Budi \\(Santoso\\)) /Producer <4275646920> >> endobj\n" +
+		"2 0 obj << /Type /Metadata /Subtype /XML /Length 154 >> stream\n<x:xmpmeta><rdf:Description xmp:CreatorTool=\"Scanner Budi\"><dc:creator><rdf:Seq><rdf:li>Budi</rdf:li></rdf:Seq></dc:creator></rdf:Description></x:xmpmeta>\nendstream endobj\n" +
+		"3 0 obj << /Type /Catalog /Pages 4 0 R /Metadata 2 0 R >> endobj\n" +
+		"5 0 obj << /Length 27 >> stream\nBT (/Author \\(Budi\\)) Tj ET\nendstream endobj\n" +
+		"6 0 obj << /Type /Metadata /Filter /FlateDecode /Length 4 >> stream\nBudi\nendstream endobj\n" +
+		"trailer << /Root 3 0 R /Info 1 0 R >>\n%%EOF\n" +
+		"3 0 obj << /Type /Catalog /Pages 4 0 R /Metadata 6 0 R >> endobj\n" +
+		"trailer << /Root 3 0 R /Info 1 0 R /Prev 9 >>\n%%EOF\n"
+
+	_, stored := uploadMetadataTestFile(t, "sertifikat.pdf", "application/pdf", []byte(content))
+
+	if len(stored) != len(content) {
+		t.Fatalf("Expected PDF length unchanged, got %d want %d", len(stored), len(content))
+	}
+	if strings.Contains(string(stored), "Scanner Budi") || strings.Contains(string(stored), "Budi</rdf:li>") || strings.Contains(string(stored), "4275") || strings.Contains(string(stored), "Santoso") {
+		t.Errorf("Expected Info and XMP values removed, got %s", stored)
+	}
+	if !strings.Contains(string(stored), "/Title (Sertifikat)") || !strings.Contains(string(stored), "BT (/Author \\(Budi\\)) Tj ET") {
+		t.Errorf("Expected title and page content kept, got %s", stored)
+	}
+	if strings.Contains(string(stored), "/FlateDecode") || !strings.Contains(string(stored), "/Type /Metadata") {
+		t.Errorf("Expected compressed XMP emptied and its filter removed, got %s", stored)
+	}
+}
+
+func TestUploadFile_RemovesTempFileWhenPutFails(t *testing.T) {
+	fileStorage := &mockFileStorage{putErr: errors.New("disk penuh")}
+	service := newAttachmentTestService(&mockAchievementRepo{}, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	content := twoColorJPEG(t)
+	_, err := service.UploadFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "foto.jpg", "image/jpeg", int64(len(content)), bytes.NewReader(content))
+	if err == nil || !strings.Contains(err.Error(), "disk penuh") {
+		t.Fatalf("Expected storage error, got %v", err)
+	}
+	if len(fileStorage.deleted) != 1 {
+		t.Errorf("Expected temporary key deleted, got %v", fileStorage.deleted)
+	}
+}
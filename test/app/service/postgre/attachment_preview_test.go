@@ -0,0 +1,281 @@
+package service_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"strings"
+	"testing"
+
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+)
+
+// testPDF menyusun PDF dengan xref table dari isi object berurutan mulai nomor 1, object 1 adalah catalog
+func testPDF(objects ...string) string {
+	var out strings.Builder
+	out.WriteString("%PDF-1.4\n")
+	offsets := []int{}
+	for i, object := range objects {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return out.String()
+}
+
+func testPDFStream(dict string, data []byte) string {
+	return fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data)
+}
+
+func testFlate(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var out bytes.Buffer
+	writer := zlib.NewWriter(&out)
+	if _, err := writer.Write(data); err != nil {
+		t.Fatalf("Failed to compress: %v", err)
+	}
+	writer.Close()
+	return out.Bytes()
+}
+
+func uploadPreviewPDF(t *testing.T, content string) (*mockFileStorage, bool) {
+	t.Helper()
+	fileStorage := &mockFileStorage{}
+	service := newAttachmentTestService(&mockAchievementRepo{}, modelpostgre.AchievementStatusDraft, fileStorage)
+
+	attachment, err := service.UploadFile(context.Background(), "user-id-1", "role-id-1", "mongo-id-1", "sertifikat.pdf", "application/pdf", int64(len(content)), strings.NewReader(content))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return fileStorage, attachment.HasPreview
+}
+
+func assertPreviewColor(t *testing.T, preview image.Image, x, y int, want [3]int) {
+	t.Helper()
+	r, g, b, _ := preview.At(x, y).RGBA()
+	got := [3]int{int(r >> 8), int(g >> 8), int(b >> 8)}
+	for i := range got {
+		if diff := got[i] - want[i]; diff > 24 || diff < -24 {
+			t.Errorf("Expected color near %v at (%d,%d), got %v", want, x, y, got)
+			return
+		}
+	}
+}
+
+func TestUploadFile_PDFPreviewRendersVectorPage(t *testing.T) {
+	content := testPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 /MediaBox [0 0 200 100] >>",
+		"<< /Type /Page /Parent 2 0 R /Contents 4 0 R >>",
+		testPDFStream("", []byte("1 0 0 rg 0 0 100 100 re f 0 0 1 RG 10 w 150 0 m 150 100 l S")),
+	)
+
+	fileStorage, hasPreview := uploadPreviewPDF(t, content)
+	if !hasPreview {
+		t.Fatalf("Expected preview for vector page")
+	}
+
+	// halaman 200x100 pt dengan MediaBox yang diwarisi dari node Pages
+	_, preview := storedPreview(t, fileStorage)
+	if preview.Bounds().Dx() != 320 || preview.Bounds().Dy() != 160 {
+		t.Fatalf("Expected 320x160 preview, got %v", preview.Bounds())
+	}
+	assertPreviewColor(t, preview, 80, 80, [3]int{255, 0, 0})
+	assertPreviewColor(t, preview, 240, 80, [3]int{0, 0, 255})
+	assertPreviewColor(t, preview, 200, 80, [3]int{255, 255, 255})
+}
+
+func TestUploadFile_PDFPreviewFollowsPageRotation(t *testing.T) {
+	content := testPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 200 100] /Rotate 90 /Contents 4 0 R >>",
+		testPDFStream("", []byte("1 0 0 rg 0 0 100 100 re f")),
+	)
+
+	fileStorage, _ := uploadPreviewPDF(t, content)
+
+	// diputar 90 derajat searah jarum jam, setengah kiri halaman menjadi setengah atas
+	_, preview := storedPreview(t, fileStorage)
+	if preview.Bounds().Dx() != 160 || preview.Bounds().Dy() != 320 {
+		t.Fatalf("Expected 160x320 preview, got %v", preview.Bounds())
+	}
+	assertPreviewColor(t, preview, 80, 80, [3]int{255, 0, 0})
+	assertPreviewColor(t, preview, 80, 240, [3]int{255, 255, 255})
+}
+
+// testPDFWithObjectStreams menyimpan catalog, page tree, dan halaman di object stream terkompresi dengan cross-reference stream, seperti PDF hasil ekspor aplikasi perkantoran modern
+func testPDFWithObjectStreams(t *testing.T, pageResources string, extra ...string) string {
+	t.Helper()
+	packed := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 100 100] /Contents 4 0 R /Resources " + pageResources + " >>",
+	}
+	var header, body strings.Builder
+	for i, object := range packed {
+		fmt.Fprintf(&header, "%d %d ", i+1, body.Len())
+		body.WriteString(object + "\n")
+	}
+	objectStream := testFlate(t, []byte(header.String()+body.String()))
+
+	var out strings.Builder
+	out.WriteString("%PDF-1.5\n")
+	offsets := map[int]int{}
+	objectNumber := 4
+	for _, object := range extra {
+		offsets[objectNumber] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", objectNumber, object)
+		objectNumber++
+	}
+	streamNumber := objectNumber
+	offsets[streamNumber] = out.Len()
+	fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", streamNumber, testPDFStream(fmt.Sprintf("/Type /ObjStm /N %d /First %d /Filter /FlateDecode", len(packed), header.Len()), objectStream))
+
+	xrefNumber := streamNumber + 1
+	offsets[xrefNumber] = out.Len()
+	rows := []byte{}
+	for num := 0; num <= xrefNumber; num++ {
+		row := make([]byte, 7)
+		switch {
+		case num == 0:
+		case num <= len(packed):
+			row[0] = 2
+			binary.BigEndian.PutUint32(row[1:], uint32(streamNumber))
+			binary.BigEndian.PutUint16(row[5:], uint16(num-1))
+		default:
+			row[0] = 1
+			binary.BigEndian.PutUint32(row[1:], uint32(offsets[num]))
+		}
+		rows = append(rows, row...)
+	}
+	fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", xrefNumber, testPDFStream(fmt.Sprintf("/Type /XRef /Size %d /W [1 4 2] /Root 1 0 R /Filter /FlateDecode", xrefNumber+1), testFlate(t, rows)))
+	fmt.Fprintf(&out, "startxref\n%d\n%%%%EOF\n", offsets[xrefNumber])
+	return out.String()
+}
+
+func TestUploadFile_PDFPreviewReadsObjectStreamsAndFlateImage(t *testing.T) {
+	// gambar RGB 2x1: kiri hijau, kanan kuning
+	pixels := testFlate(t, []byte{0, 255, 0, 255, 255, 0})
+	content := testPDFWithObjectStreams(t, "<< /XObject << /Im0 5 0 R >> >>",
+		testPDFStream("", []byte("q 100 0 0 100 0 0 cm /Im0 Do Q")),
+		testPDFStream("/Type /XObject /Subtype /Image /Width 2 /Height 1 /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode", pixels),
+	)
+
+	fileStorage, hasPreview := uploadPreviewPDF(t, content)
+	if !hasPreview {
+		t.Fatalf("Expected preview from page inside object stream")
+	}
+
+	_, preview := storedPreview(t, fileStorage)
+	if preview.Bounds().Dx() != 320 || preview.Bounds().Dy() != 320 {
+		t.Fatalf("Expected 320x320 preview, got %v", preview.Bounds())
+	}
+	assertPreviewColor(t, preview, 80, 160, [3]int{0, 255, 0})
+	assertPreviewColor(t, preview, 240, 160, [3]int{255, 255, 0})
+}
+
+func TestUploadFile_PDFPreviewGreeksStandardFonts(t *testing.T) {
+	content := testPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 200 200] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		testPDFStream("", []byte("BT /F1 100 Tf 20 20 Td (H) Tj ET")),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+	)
+
+	fileStorage, _ := uploadPreviewPDF(t, content)
+
+	// font standar tanpa program font digambar sebagai kotak abu-abu setinggi x-height selebar advance glyph (H = 722/1000 em)
+	_, preview := storedPreview(t, fileStorage)
+	assertPreviewColor(t, preview, 80, 260, [3]int{128, 128, 128})
+	assertPreviewColor(t, preview, 80, 200, [3]int{255, 255, 255})
+	assertPreviewColor(t, preview, 180, 260, [3]int{255, 255, 255})
+}
+
+// testTrueTypeFont membuat font TrueType minimal dengan satu glyph persegi (100,100)-(600,600) untuk karakter A
+func testTrueTypeFont() []byte {
+	u16 := func(values ...int) []byte {
+		out := []byte{}
+		for _, value := range values {
+			out = binary.BigEndian.AppendUint16(out, uint16(value))
+		}
+		return out
+	}
+
+	head := make([]byte, 54)
+	binary.BigEndian.PutUint16(head[18:], 1000)
+	hhea := make([]byte, 36)
+	binary.BigEndian.PutUint16(hhea[34:], 2)
+	maxp := u16(0, 0x5000, 2)
+	hmtx := u16(500, 0, 700, 100)
+
+	glyph := u16(1, 100, 100, 600, 600, 3, 0)
+	glyph = append(glyph, 1, 1, 1, 1)
+	glyph = append(glyph, u16(100, 500, 0, -500, 100, 0, 500, 0)...)
+	loca := u16(0, 0, len(glyph)/2)
+
+	subtable := u16(4, 32, 0, 4, 4, 1, 0, 'A', 0xffff, 0, 'A', 0xffff, 1-'A', 1, 0, 0)
+	cmap := append(u16(0, 1, 3, 1, 0, 12), subtable...)
+
+	tables := []struct {
+		tag  string
+		data []byte
+	}{{"cmap", cmap}, {"glyf", glyph}, {"head", head}, {"hhea", hhea}, {"hmtx", hmtx}, {"loca", loca}, {"maxp", maxp}}
+	font := u16(1, 0, len(tables), 0, 0, 0)
+	offset := 12 + 16*len(tables)
+	for _, table := range tables {
+		font = append(font, table.tag...)
+		font = binary.BigEndian.AppendUint32(font, 0)
+		font = binary.BigEndian.AppendUint32(font, uint32(offset))
+		font = binary.BigEndian.AppendUint32(font, uint32(len(table.data)))
+		offset += len(table.data)
+	}
+	for _, table := range tables {
+		font = append(font, table.data...)
+	}
+	return font
+}
+
+func TestUploadFile_PDFPreviewDrawsEmbeddedTrueTypeGlyphs(t *testing.T) {
+	font := testTrueTypeFont()
+	content := testPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 200 200] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		testPDFStream("", []byte("BT /F1 100 Tf 20 20 Td (A) Tj ET")),
+		"<< /Type /Font /Subtype /TrueType /BaseFont /Kotak /FirstChar 65 /LastChar 65 /Widths [700] /Encoding /WinAnsiEncoding /FontDescriptor 6 0 R >>",
+		"<< /Type /FontDescriptor /FontName /Kotak /Flags 32 /FontBBox [0 0 700 700] /ItalicAngle 0 /Ascent 700 /Descent 0 /CapHeight 700 /StemV 80 /FontFile2 7 0 R >>",
+		testPDFStream(fmt.Sprintf("/Length1 %d", len(font)), font),
+	)
+
+	fileStorage, _ := uploadPreviewPDF(t, content)
+
+	// glyph menutupi (30,30)-(80,80) pt, yaitu piksel (48,192)-(128,272) dengan skala 1.6
+	_, preview := storedPreview(t, fileStorage)
+	assertPreviewColor(t, preview, 88, 200, [3]int{0, 0, 0})
+	assertPreviewColor(t, preview, 88, 284, [3]int{255, 255, 255})
+	assertPreviewColor(t, preview, 140, 232, [3]int{255, 255, 255})
+}
+
+func TestUploadFile_EncryptedPDFHasNoPreview(t *testing.T) {
+	content := strings.Replace(testPDF(
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 100 100] >>",
+		"<< /Filter /Standard /V 2 /R 3 /O <00> /U <00> /P -4 >>",
+	), "/Root 1 0 R", "/Root 1 0 R /Encrypt 4 0 R", 1)
+
+	fileStorage, hasPreview := uploadPreviewPDF(t, content)
+	if hasPreview || len(fileStorage.objects) != 1 {
+		t.Errorf("Expected encrypted PDF to be stored without preview, got %d objects", len(fileStorage.objects))
+	}
+}
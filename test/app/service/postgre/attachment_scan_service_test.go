@@ -0,0 +1,178 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositoryscanner "sistem-pelaporan-prestasi-mahasiswa/app/repository/scanner"
+	repositorystorage "sistem-pelaporan-prestasi-mahasiswa/app/repository/storage"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// attachmentScanRepo menyimpan dokumen di memori supaya hasil scan yang disimpan bisa diperiksa
+type attachmentScanRepo struct {
+	mockAchievementRepo
+	docs []*modelmongo.Achievement
+}
+
+func (m *attachmentScanRepo) GetAchievementsByAttachmentScanStatus(ctx context.Context, status string, limit int) ([]modelmongo.Achievement, error) {
+	var result []modelmongo.Achievement
+	for _, doc := range m.docs {
+		for _, attachment := range doc.Attachments {
+			if attachment.ScanStatus == status {
+				result = append(result, *doc)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func (m *attachmentScanRepo) UpdateAttachmentScanResult(ctx context.Context, id string, fileURL string, status string, signature string, newURL string) (bool, error) {
+	for _, doc := range m.docs {
+		if doc.ID.Hex() != id {
+			continue
+		}
+		for i := range doc.Attachments {
+			attachment := &doc.Attachments[i]
+			if attachment.FileURL != fileURL || attachment.ScanStatus != modelmongo.AttachmentScanPending {
+				continue
+			}
+			attachment.ScanStatus = status
+			attachment.ScanSignature = signature
+			if newURL != "" {
+				attachment.FileURL = newURL
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fakeMalwareScanner menandai file yang berisi "MALWARE" sebagai terinfeksi
+type fakeMalwareScanner struct {
+	err error
+}
+
+func (s *fakeMalwareScanner) Name() string {
+	return "fake"
+}
+
+func (s *fakeMalwareScanner) Scan(ctx context.Context, body io.Reader) (*repositoryscanner.ScanResult, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	data, _ := io.ReadAll(body)
+	if strings.Contains(string(data), "MALWARE") {
+		return &repositoryscanner.ScanResult{Infected: true, Signature: "Fake.Malware"}, nil
+	}
+	return &repositoryscanner.ScanResult{}, nil
+}
+
+func newAttachmentScanFixture(t *testing.T) (*attachmentScanRepo, repositorystorage.IFileStorage, repositorystorage.IFileStorage) {
+	ctx := context.Background()
+	fileStorage := repositorystorage.NewLocalFileStorage(t.TempDir(), "/uploads/")
+	quarantineStorage := repositorystorage.NewLocalFileStorage(t.TempDir(), "/quarantine/")
+
+	fileStorage.Put(ctx, "1-bersih.pdf", strings.NewReader("%PDF-1.4 bersih"), 15, "application/pdf")
+	fileStorage.Put(ctx, "2-virus.pdf", strings.NewReader("%PDF-1.4 MALWARE"), 16, "application/pdf")
+
+	repo := &attachmentScanRepo{docs: []*modelmongo.Achievement{
+		{
+			ID:        primitive.NewObjectID(),
+			StudentID: attachmentTestStudentID,
+			Attachments: []modelmongo.Attachment{
+				{FileName: "bersih.pdf", FileURL: "/uploads/1-bersih.pdf", FileType: "application/pdf", ScanStatus: modelmongo.AttachmentScanPending},
+				{FileName: "virus.pdf", FileURL: "/uploads/2-virus.pdf", FileType: "application/pdf", ScanStatus: modelmongo.AttachmentScanPending},
+				{FileName: "lama.pdf", FileURL: "/uploads/0-lama.pdf", FileType: "application/pdf"},
+			},
+		},
+	}}
+	return repo, fileStorage, quarantineStorage
+}
+
+func TestScanPendingAttachments_CleanAndQuarantine(t *testing.T) {
+	ctx := context.Background()
+	repo, fileStorage, quarantineStorage := newAttachmentScanFixture(t)
+	notificationService := &mockNotificationService{}
+
+	service := servicepostgre.NewAttachmentScanService(
+		repo,
+		&mockAchievementRefRepo{byMongoID: &modelpostgre.AchievementReference{ID: "ref-id-1", StudentID: attachmentTestStudentID}},
+		notificationService,
+		fileStorage,
+		quarantineStorage,
+		&fakeMalwareScanner{},
+	)
+
+	result, err := service.ScanPendingAttachments(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Scanned != 2 || result.Clean != 1 || result.Infected != 1 || result.Failed != 0 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	attachments := repo.docs[0].Attachments
+	if attachments[0].ScanStatus != modelmongo.AttachmentScanClean || attachments[0].FileURL != "/uploads/1-bersih.pdf" {
+		t.Errorf("Expected clean attachment untouched, got %+v", attachments[0])
+	}
+	if attachments[1].ScanStatus != modelmongo.AttachmentScanInfected || attachments[1].FileURL != "/quarantine/2-virus.pdf" || attachments[1].ScanSignature != "Fake.Malware" {
+		t.Errorf("Expected infected attachment quarantined, got %+v", attachments[1])
+	}
+	if attachments[2].ScanStatus != "" {
+		t.Errorf("Expected legacy attachment not scanned, got %+v", attachments[2])
+	}
+
+	// file terinfeksi pindah ke karantina dan tidak ada lagi di storage attachment
+	if _, err := fileStorage.Stat(ctx, "2-virus.pdf"); !errors.Is(err, repositorystorage.ErrFileNotFound) {
+		t.Errorf("Expected infected file removed from storage, got %v", err)
+	}
+	if _, err := quarantineStorage.Stat(ctx, "2-virus.pdf"); err != nil {
+		t.Errorf("Expected infected file in quarantine, got %v", err)
+	}
+
+	if len(notificationService.infectedFiles) != 1 || notificationService.infectedFiles[0] != "virus.pdf" {
+		t.Errorf("Expected student notified about virus.pdf, got %v", notificationService.infectedFiles)
+	}
+
+	// putaran berikutnya tidak memindai ulang attachment yang sudah punya hasil
+	result, _ = service.ScanPendingAttachments(ctx)
+	if result.Scanned != 0 {
+		t.Errorf("Expected nothing left to scan, got %+v", result)
+	}
+}
+
+func TestScanPendingAttachments_ScannerErrorKeepsPending(t *testing.T) {
+	ctx := context.Background()
+	repo, fileStorage, quarantineStorage := newAttachmentScanFixture(t)
+
+	service := servicepostgre.NewAttachmentScanService(
+		repo,
+		&mockAchievementRefRepo{},
+		&mockNotificationService{},
+		fileStorage,
+		quarantineStorage,
+		&fakeMalwareScanner{err: errors.New("clamd down")},
+	)
+
+	result, err := service.ScanPendingAttachments(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Failed != 2 {
+		t.Errorf("Expected 2 failed scans, got %+v", result)
+	}
+	for _, attachment := range repo.docs[0].Attachments[:2] {
+		if attachment.ScanStatus != modelmongo.AttachmentScanPending {
+			t.Errorf("Expected attachment still pending, got %+v", attachment)
+		}
+	}
+}
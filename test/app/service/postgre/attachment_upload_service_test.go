@@ -0,0 +1,267 @@
+package service_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const attachmentUploadTestChunkSize = 16
+
+type mockAttachmentUploadRepo struct {
+	uploads map[string]*modelmongo.AttachmentUpload
+	active  int64
+}
+
+func (m *mockAttachmentUploadRepo) CreateUpload(ctx context.Context, upload *modelmongo.AttachmentUpload) (*modelmongo.AttachmentUpload, error) {
+	if m.uploads == nil {
+		m.uploads = map[string]*modelmongo.AttachmentUpload{}
+	}
+	upload.ID = primitive.NewObjectID()
+	upload.Offset = 0
+	stored := *upload
+	m.uploads[upload.ID.Hex()] = &stored
+	return upload, nil
+}
+
+func (m *mockAttachmentUploadRepo) GetUploadByID(ctx context.Context, id string) (*modelmongo.AttachmentUpload, error) {
+	upload, ok := m.uploads[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *upload
+	return &copied, nil
+}
+
+func (m *mockAttachmentUploadRepo) CountActiveUploads(ctx context.Context, studentID string, now time.Time) (int64, error) {
+	return m.active, nil
+}
+
+func (m *mockAttachmentUploadRepo) AdvanceUploadOffset(ctx context.Context, id string, expectedOffset int64, newOffset int64, expiresAt time.Time) (bool, error) {
+	upload, ok := m.uploads[id]
+	if !ok || upload.Offset != expectedOffset {
+		return false, nil
+	}
+	upload.Offset = newOffset
+	upload.ExpiresAt = expiresAt
+	return true, nil
+}
+
+func (m *mockAttachmentUploadRepo) DeleteUpload(ctx context.Context, id string) error {
+	delete(m.uploads, id)
+	return nil
+}
+
+func (m *mockAttachmentUploadRepo) GetExpiredUploads(ctx context.Context, now time.Time, limit int) ([]modelmongo.AttachmentUpload, error) {
+	uploads := []modelmongo.AttachmentUpload{}
+	for _, upload := range m.uploads {
+		if !upload.ExpiresAt.After(now) {
+			uploads = append(uploads, *upload)
+		}
+	}
+	return uploads, nil
+}
+
+func newAttachmentUploadTestService(achievementRepo *mockAchievementRepo, uploadRepo *mockAttachmentUploadRepo, status string, fileStorage *mockFileStorage, config modelmongo.AttachmentConfig) servicepostgre.IAttachmentUploadService {
+	return servicepostgre.NewAttachmentUploadService(
+		uploadRepo,
+		achievementRepo,
+		&mockAchievementRefRepo{
+			byMongoID: &modelpostgre.AchievementReference{
+				ID:                 "ref-id-1",
+				StudentID:          attachmentTestStudentID,
+				MongoAchievementID: "mongo-id-1",
+				Status:             status,
+			},
+		},
+		&mockStudentRepo{studentIDByUserID: attachmentTestStudentID},
+		newAttachmentTestServiceWithConfig(achievementRepo, status, fileStorage, config),
+		fileStorage,
+		config,
+		modelmongo.AttachmentUploadConfig{ChunkSize: attachmentUploadTestChunkSize, TTL: time.Hour, MaxActive: 3},
+	)
+}
+
+func sendAttachmentUploadChunks(t *testing.T, service servicepostgre.IAttachmentUploadService, uploadID string, content string) {
+	t.Helper()
+	for offset := 0; offset < len(content); offset += attachmentUploadTestChunkSize {
+		end := min(offset+attachmentUploadTestChunkSize, len(content))
+		if _, err := service.AppendChunk(context.Background(), "user-id-1", "mongo-id-1", uploadID, int64(offset), []byte(content[offset:end])); err != nil {
+			t.Fatalf("Expected chunk at %d accepted, got %v", offset, err)
+		}
+	}
+}
+
+func TestAttachmentUpload_CompleteStoresAttachmentAndRemovesChunks(t *testing.T) {
+	ctx := context.Background()
+	fileStorage := &mockFileStorage{}
+	uploadRepo := &mockAttachmentUploadRepo{}
+	service := newAttachmentUploadTestService(&mockAchievementRepo{
+		byID: &modelmongo.Achievement{ID: primitive.NewObjectID(), StudentID: attachmentTestStudentID, Title: "Juara"},
+	}, uploadRepo, modelpostgre.AchievementStatusDraft, fileStorage, testAttachmentConfig())
+
+	created, err := service.CreateUpload(ctx, "user-id-1", "mongo-id-1", modelmongo.CreateAttachmentUploadRequest{FileName: "sertifikat lomba.pdf", Size: int64(len(attachmentTestPDF))})
+	if err != nil {
+		t.Fatalf("Expected upload created, got %v", err)
+	}
+	if created.Data.ChunkSize != attachmentUploadTestChunkSize || created.Data.FileName != "sertifikat_lomba.pdf" {
+		t.Errorf("Unexpected upload %+v", created.Data)
+	}
+
+	uploadID := created.Data.ID.Hex()
+	sendAttachmentUploadChunks(t, service, uploadID, attachmentTestPDF)
+	if len(fileStorage.objects) != 4 {
+		t.Fatalf("Expected 4 chunks stored, got %d", len(fileStorage.objects))
+	}
+
+	attachment, err := service.CompleteUpload(ctx, "user-id-1", "role-id-1", "mongo-id-1", uploadID)
+	if err != nil {
+		t.Fatalf("Expected upload completed, got %v", err)
+	}
+
+	hash := sha256.Sum256([]byte(attachmentTestPDF))
+	key := hex.EncodeToString(hash[:]) + ".pdf"
+	if attachment.FileURL != "/uploads/"+key || attachment.Size != int64(len(attachmentTestPDF)) {
+		t.Errorf("Unexpected attachment %+v", attachment)
+	}
+	if len(fileStorage.objects) != 1 || string(fileStorage.objects[key]) != attachmentTestPDF {
+		t.Errorf("Expected only assembled file left in storage, got %v", fileStorage.objects)
+	}
+	if len(uploadRepo.uploads) != 0 {
+		t.Errorf("Expected upload session removed")
+	}
+}
+
+func TestAttachmentUpload_RejectsWrongOffsetAndChunkSize(t *testing.T) {
+	ctx := context.Background()
+	uploadRepo := &mockAttachmentUploadRepo{}
+	service := newAttachmentUploadTestService(&mockAchievementRepo{}, uploadRepo, modelpostgre.AchievementStatusDraft, &mockFileStorage{}, testAttachmentConfig())
+
+	created, err := service.CreateUpload(ctx, "user-id-1", "mongo-id-1", modelmongo.CreateAttachmentUploadRequest{FileName: "a.pdf", Size: int64(len(attachmentTestPDF))})
+	if err != nil {
+		t.Fatalf("Expected upload created, got %v", err)
+	}
+	uploadID := created.Data.ID.Hex()
+
+	if _, err := service.AppendChunk(ctx, "user-id-1", "mongo-id-1", uploadID, 16, []byte(attachmentTestPDF[16:32])); err == nil || !strings.Contains(err.Error(), "offset upload tidak sesuai") {
+		t.Errorf("Expected offset mismatch, got %v", err)
+	}
+
+	var validationErr *modelmongo.AttachmentValidationError
+	if _, err := service.AppendChunk(ctx, "user-id-1", "mongo-id-1", uploadID, 0, []byte(attachmentTestPDF[:8])); !errors.As(err, &validationErr) {
+		t.Errorf("Expected short non-final chunk rejected, got %v", err)
+	}
+
+	if _, err := service.AppendChunk(ctx, "user-id-1", "mongo-id-1", uploadID, 0, []byte("MZ\x90\x00"+strings.Repeat("x", 12))); !errors.As(err, &validationErr) {
+		t.Errorf("Expected first chunk magic bytes checked, got %v", err)
+	}
+
+	if uploadRepo.uploads[uploadID].Offset != 0 {
+		t.Errorf("Expected offset unchanged, got %d", uploadRepo.uploads[uploadID].Offset)
+	}
+}
+
+func TestAttachmentUpload_CompleteRequiresAllChunks(t *testing.T) {
+	ctx := context.Background()
+	service := newAttachmentUploadTestService(&mockAchievementRepo{}, &mockAttachmentUploadRepo{}, modelpostgre.AchievementStatusDraft, &mockFileStorage{}, testAttachmentConfig())
+
+	created, _ := service.CreateUpload(ctx, "user-id-1", "mongo-id-1", modelmongo.CreateAttachmentUploadRequest{FileName: "a.pdf", Size: int64(len(attachmentTestPDF))})
+	uploadID := created.Data.ID.Hex()
+	if _, err := service.AppendChunk(ctx, "user-id-1", "mongo-id-1", uploadID, 0, []byte(attachmentTestPDF[:16])); err != nil {
+		t.Fatalf("Expected first chunk accepted, got %v", err)
+	}
+
+	_, err := service.CompleteUpload(ctx, "user-id-1", "role-id-1", "mongo-id-1", uploadID)
+	if err == nil || !strings.Contains(err.Error(), "belum lengkap") {
+		t.Errorf("Expected incomplete upload error, got %v", err)
+	}
+}
+
+func TestAttachmentUpload_InvalidAssembledFileDiscardsSession(t *testing.T) {
+	ctx := context.Background()
+	fileStorage := &mockFileStorage{}
+	uploadRepo := &mockAttachmentUploadRepo{}
+	service := newAttachmentUploadTestService(&mockAchievementRepo{}, uploadRepo, modelpostgre.AchievementStatusDraft, fileStorage, testAttachmentConfig())
+
+	content := "%PDF-1.7\n2 0 obj << /S /JavaScript /JS (app.alert(1)) >> endobj\n%%EOF\n"
+	created, _ := service.CreateUpload(ctx, "user-id-1", "mongo-id-1", modelmongo.CreateAttachmentUploadRequest{FileName: "a.pdf", Size: int64(len(content))})
+	uploadID := created.Data.ID.Hex()
+	sendAttachmentUploadChunks(t, service, uploadID, content)
+
+	_, err := service.CompleteUpload(ctx, "user-id-1", "role-id-1", "mongo-id-1", uploadID)
+	if err == nil || !strings.Contains(err.Error(), "JavaScript") {
+		t.Fatalf("Expected JavaScript rejection, got %v", err)
+	}
+	if len(fileStorage.objects) != 0 || len(uploadRepo.uploads) != 0 {
+		t.Errorf("Expected chunks and session removed, objects=%v sessions=%d", fileStorage.objects, len(uploadRepo.uploads))
+	}
+}
+
+func TestAttachmentUpload_CreateChecksLimits(t *testing.T) {
+	ctx := context.Background()
+	req := modelmongo.CreateAttachmentUploadRequest{FileName: "video.mp4", Size: 100 * 1024 * 1024}
+
+	service := newAttachmentUploadTestService(&mockAchievementRepo{}, &mockAttachmentUploadRepo{}, modelpostgre.AchievementStatusSubmitted, &mockFileStorage{}, testAttachmentConfig())
+	if _, err := service.CreateUpload(ctx, "user-id-1", "mongo-id-1", req); err == nil || !strings.Contains(err.Error(), "draft") {
+		t.Errorf("Expected non-draft achievement rejected, got %v", err)
+	}
+
+	service = newAttachmentUploadTestService(&mockAchievementRepo{}, &mockAttachmentUploadRepo{active: 3}, modelpostgre.AchievementStatusDraft, &mockFileStorage{}, testAttachmentConfig())
+	if _, err := service.CreateUpload(ctx, "user-id-1", "mongo-id-1", req); err == nil || !strings.Contains(err.Error(), "terlalu banyak upload") {
+		t.Errorf("Expected active upload limit, got %v", err)
+	}
+
+	config := testAttachmentConfig()
+	config.StudentQuota = 50 * 1024 * 1024
+	service = newAttachmentUploadTestService(&mockAchievementRepo{}, &mockAttachmentUploadRepo{}, modelpostgre.AchievementStatusDraft, &mockFileStorage{}, config)
+	var quotaErr *modelmongo.AttachmentQuotaError
+	if _, err := service.CreateUpload(ctx, "user-id-1", "mongo-id-1", req); !errors.As(err, &quotaErr) {
+		t.Errorf("Expected quota checked against declared size, got %v", err)
+	}
+
+	var validationErr *modelmongo.AttachmentValidationError
+	if _, err := service.CreateUpload(ctx, "user-id-1", "mongo-id-1", modelmongo.CreateAttachmentUploadRequest{FileName: "video.mp4", Size: 300 * 1024 * 1024}); !errors.As(err, &validationErr) || !validationErr.TooLarge {
+		t.Errorf("Expected too large error, got %v", err)
+	}
+}
+
+func TestAttachmentUpload_ExpiredSessionNotFoundAndCleanedUp(t *testing.T) {
+	ctx := context.Background()
+	fileStorage := &mockFileStorage{}
+	uploadRepo := &mockAttachmentUploadRepo{}
+	service := newAttachmentUploadTestService(&mockAchievementRepo{}, uploadRepo, modelpostgre.AchievementStatusDraft, fileStorage, testAttachmentConfig())
+
+	created, _ := service.CreateUpload(ctx, "user-id-1", "mongo-id-1", modelmongo.CreateAttachmentUploadRequest{FileName: "a.pdf", Size: int64(len(attachmentTestPDF))})
+	uploadID := created.Data.ID.Hex()
+	sendAttachmentUploadChunks(t, service, uploadID, attachmentTestPDF[:32])
+	uploadRepo.uploads[uploadID].ExpiresAt = time.Now().Add(-time.Minute)
+
+	if _, err := service.GetUpload(ctx, "user-id-1", "mongo-id-1", uploadID); err == nil || !strings.Contains(err.Error(), "tidak ditemukan") {
+		t.Errorf("Expected expired upload not found, got %v", err)
+	}
+
+	result, err := service.CleanupExpiredUploads(ctx)
+	if err != nil {
+		t.Fatalf("Expected cleanup without error, got %v", err)
+	}
+	if result.Expired != 1 || result.ChunksDeleted != 3 || result.Failed != 0 {
+		t.Errorf("Unexpected cleanup result %+v", result)
+	}
+	if len(fileStorage.objects) != 0 || len(uploadRepo.uploads) != 0 {
+		t.Errorf("Expected chunks and session removed, objects=%v sessions=%d", fileStorage.objects, len(uploadRepo.uploads))
+	}
+	if want := fmt.Sprintf("upload-%s-000002.part", uploadID); fileStorage.deleted[2] != want {
+		t.Errorf("Expected next chunk key %s deleted, got %v", want, fileStorage.deleted)
+	}
+}
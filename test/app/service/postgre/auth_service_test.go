@@ -90,6 +90,10 @@ func (m *mockAuthUserRepo) GetLecturerByID(ctx context.Context, id string) (*mod
 	return nil, m.err
 }
 
+func (m *mockAuthUserRepo) GetActiveUserIDsByRoleName(ctx context.Context, roleName string) ([]string, error) {
+	return nil, m.err
+}
+
 func TestLogin_Success(t *testing.T) {
 	ctx := setupTestContext()
 
@@ -0,0 +1,261 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func newCertificationAchievement(validUntil time.Time) modelmongo.Achievement {
+	return modelmongo.Achievement{
+		ID:              primitive.NewObjectID(),
+		StudentID:       "550e8400-e29b-41d4-a716-446655440000",
+		AchievementType: "certification",
+		Title:           "AWS Certified Developer",
+		Points:          30,
+		Details:         modelmongo.AchievementDetails{ValidUntil: &validUntil},
+	}
+}
+
+func newTestCertificationConfig() modelpostgre.CertificationExpiryConfig {
+	return modelpostgre.CertificationExpiryConfig{
+		AlertDays: []int{30, 7},
+	}
+}
+
+func newCertificationService(refRepo *mockAchievementRefRepo, achievementRepo *mockAchievementRepo, notificationService *mockNotificationService) servicepostgre.ICertificationService {
+	return servicepostgre.NewCertificationService(refRepo, achievementRepo, notificationService, newTestCertificationConfig())
+}
+
+func TestRunExpiryCheck_SendsAlertOncePerThreshold(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		byMongoID: &modelpostgre.AchievementReference{
+			ID:        "ref-id-1",
+			StudentID: "550e8400-e29b-41d4-a716-446655440000",
+			Status:    modelpostgre.AchievementStatusVerified,
+		},
+	}
+	mockAchievementRepo := &mockAchievementRepo{
+		validUntilBetween: []modelmongo.Achievement{newCertificationAchievement(time.Now().AddDate(0, 0, 20))},
+	}
+	mockNotificationService := &mockNotificationService{}
+
+	service := newCertificationService(mockAchievementRefRepo, mockAchievementRepo, mockNotificationService)
+
+	result, err := service.RunExpiryCheck(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Notified != 1 || len(mockNotificationService.expiryAlerts) != 1 || mockNotificationService.expiryAlerts[0] != 20 {
+		t.Errorf("Expected one alert with 20 days left, got result %+v alerts %v", result, mockNotificationService.expiryAlerts)
+	}
+
+	// pengecekan berikutnya pada batas 30 hari yang sama tidak mengirim ulang
+	result, err = service.RunExpiryCheck(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Notified != 0 || len(mockNotificationService.expiryAlerts) != 1 {
+		t.Errorf("Expected no repeated alert, got result %+v alerts %v", result, mockNotificationService.expiryAlerts)
+	}
+
+	// masuk batas 7 hari, peringatan kedua dikirim
+	sevenDayWindow := time.Now().AddDate(0, 0, 5)
+	mockAchievementRepo.validUntilBetween[0].Details.ValidUntil = &sevenDayWindow
+	result, err = service.RunExpiryCheck(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Notified != 1 || len(mockNotificationService.expiryAlerts) != 2 || mockNotificationService.expiryAlerts[1] != 5 {
+		t.Errorf("Expected 7 day alert with 5 days left, got result %+v alerts %v", result, mockNotificationService.expiryAlerts)
+	}
+}
+
+func TestRunExpiryCheck_OutsideAlertWindow(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		byMongoID: &modelpostgre.AchievementReference{ID: "ref-id-1", Status: modelpostgre.AchievementStatusVerified},
+	}
+	mockAchievementRepo := &mockAchievementRepo{
+		validUntilBetween: []modelmongo.Achievement{newCertificationAchievement(time.Now().AddDate(0, 0, 45))},
+	}
+	mockNotificationService := &mockNotificationService{}
+
+	service := newCertificationService(mockAchievementRefRepo, mockAchievementRepo, mockNotificationService)
+
+	result, err := service.RunExpiryCheck(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Notified != 0 || len(mockNotificationService.expiryAlerts) != 0 {
+		t.Errorf("Expected no alert, got %v", mockNotificationService.expiryAlerts)
+	}
+}
+
+func TestRunExpiryCheck_SkipsDeletedAchievement(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		byMongoID: &modelpostgre.AchievementReference{ID: "ref-id-1", Status: modelpostgre.AchievementStatusDeleted},
+	}
+	mockAchievementRepo := &mockAchievementRepo{
+		validUntilBetween: []modelmongo.Achievement{newCertificationAchievement(time.Now().AddDate(0, 0, 3))},
+	}
+	mockNotificationService := &mockNotificationService{}
+
+	service := newCertificationService(mockAchievementRefRepo, mockAchievementRepo, mockNotificationService)
+
+	if _, err := service.RunExpiryCheck(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockNotificationService.expiryAlerts) != 0 {
+		t.Errorf("Expected no alert for deleted achievement, got %v", mockNotificationService.expiryAlerts)
+	}
+}
+
+func TestRunExpiryCheck_ReleasesAlertWhenNotificationFails(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		byMongoID: &modelpostgre.AchievementReference{ID: "ref-id-1", Status: modelpostgre.AchievementStatusVerified},
+	}
+	mockAchievementRepo := &mockAchievementRepo{
+		validUntilBetween: []modelmongo.Achievement{newCertificationAchievement(time.Now().AddDate(0, 0, 3))},
+	}
+	mockNotificationService := &mockNotificationService{err: errors.New("database error")}
+
+	service := newCertificationService(mockAchievementRefRepo, mockAchievementRepo, mockNotificationService)
+
+	result, err := service.RunExpiryCheck(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Notified != 0 {
+		t.Errorf("Expected 0 notified, got %d", result.Notified)
+	}
+
+	if len(mockAchievementRefRepo.releasedAlerts) != 1 || len(mockAchievementRefRepo.claimedAlerts) != 0 {
+		t.Errorf("Expected alert claim released, got released %v claimed %v", mockAchievementRefRepo.releasedAlerts, mockAchievementRefRepo.claimedAlerts)
+	}
+}
+
+func TestLoadCertificationExpiryConfig(t *testing.T) {
+	t.Setenv("CERTIFICATION_EXPIRY_ALERT_DAYS", "7, 60,abc,7")
+	t.Setenv("CERTIFICATION_EXPIRY_CHECK_INTERVAL_HOURS", "0")
+	t.Setenv("CERTIFICATION_EXCLUDE_EXPIRED_POINTS", "true")
+
+	config, err := servicepostgre.LoadCertificationExpiryConfig()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(config.AlertDays) != 2 || config.AlertDays[0] != 60 || config.AlertDays[1] != 7 {
+		t.Errorf("Expected alert days [60 7], got %v", config.AlertDays)
+	}
+
+	if config.CheckInterval != 0 {
+		t.Errorf("Expected scheduler disabled, got %v", config.CheckInterval)
+	}
+
+	if !config.ExcludeExpiredPoints {
+		t.Error("Expected expired points excluded")
+	}
+}
+
+func TestValidateCertificationExpiryConfig(t *testing.T) {
+	testCases := []struct {
+		name      string
+		alertDays []int
+		wantErr   bool
+	}{
+		{name: "urut dari terbesar", alertDays: []int{30, 7}, wantErr: false},
+		{name: "kosong", alertDays: nil, wantErr: true},
+		{name: "tidak urut", alertDays: []int{7, 30}, wantErr: true},
+		{name: "duplikat", alertDays: []int{7, 7}, wantErr: true},
+		{name: "nol", alertDays: []int{30, 0}, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := servicepostgre.ValidateCertificationExpiryConfig(modelpostgre.CertificationExpiryConfig{AlertDays: tc.alertDays})
+
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Expected error %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestGetAchievements_CertificationStatusFilter(t *testing.T) {
+	ctx := setupTestContext()
+
+	expiringSoon := newCertificationAchievement(time.Now().AddDate(0, 0, 10))
+	row := modelpostgre.AchievementListRow{
+		AchievementReference: modelpostgre.AchievementReference{
+			ID:                 "ref-id-1",
+			StudentID:          expiringSoon.StudentID,
+			MongoAchievementID: expiringSoon.ID.Hex(),
+			Status:             modelpostgre.AchievementStatusVerified,
+		},
+	}
+
+	mockAchievementRepo := &mockAchievementRepo{
+		facetResult: &repositorymongo.AchievementFacetResult{
+			Total:      1,
+			MatchedIDs: []string{expiringSoon.ID.Hex()},
+			Facets:     map[string][]modelmongo.FacetCount{},
+		},
+		byIDs: []modelmongo.Achievement{expiringSoon},
+	}
+
+	service := servicepostgre.NewAchievementService(
+		mockAchievementRepo,
+		&mockAchievementRefRepo{listRows: []modelpostgre.AchievementListRow{row}},
+		&mockUserRepo{roleName: "Admin"},
+		&mockStudentRepo{},
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		newTestSLAConfig(),
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+
+	result, err := service.GetAchievements(ctx, "admin-id", "role-id-1", modelpostgre.AchievementListFilter{
+		Page:                  1,
+		Limit:                 10,
+		CertificationStatuses: []string{modelpostgre.CertificationStatusExpiringSoon},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// filter status sertifikasi diteruskan ke query MongoDB dengan batas hari yang sama seperti status di response
+	mongoFilter := mockAchievementRepo.facetFilter
+	if mongoFilter == nil || len(mongoFilter.CertificationStatuses) != 1 || mongoFilter.CertificationSoonDays != 30 {
+		t.Fatalf("Expected certification filter passed to MongoDB, got %+v", mongoFilter)
+	}
+
+	data := result["data"].([]map[string]interface{})
+	if len(data) != 1 || data[0]["certification_status"] != modelpostgre.CertificationStatusExpiringSoon {
+		t.Errorf("Expected certification_status expiring_soon, got %+v", data)
+	}
+}
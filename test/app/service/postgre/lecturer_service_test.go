@@ -149,6 +149,10 @@ func (m *mockLecturerServiceUserRepo) GetLecturerByID(ctx context.Context, id st
 	return m.lecturerByID, nil
 }
 
+func (m *mockLecturerServiceUserRepo) GetActiveUserIDsByRoleName(ctx context.Context, roleName string) ([]string, error) {
+	return nil, m.err
+}
+
 func TestGetAllLecturers_Success(t *testing.T) {
 	ctx := setupTestContext()
 
@@ -3,6 +3,7 @@ package service_test
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
 	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
 	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -21,6 +23,11 @@ type mockNotificationServiceNotificationRepo struct {
 	createErr        error
 	markAsReadErr    error
 	markAllAsReadErr error
+	deletedRefID     string
+	deletedType      string
+	created          []modelpostgre.CreateNotificationRequest
+	cursor           *modelpostgre.PageCursor
+	cursorLimit      int
 }
 
 func (m *mockNotificationServiceNotificationRepo) CreateNotification(ctx context.Context, req modelpostgre.CreateNotificationRequest) (*modelpostgre.Notification, error) {
@@ -30,6 +37,7 @@ func (m *mockNotificationServiceNotificationRepo) CreateNotification(ctx context
 	if m.err != nil {
 		return nil, m.err
 	}
+	m.created = append(m.created, req)
 	notif := &modelpostgre.Notification{
 		ID:        "notif-id-1",
 		UserID:    req.UserID,
@@ -56,6 +64,18 @@ func (m *mockNotificationServiceNotificationRepo) GetNotificationsByUserIDPagina
 	return m.notifications, len(m.notifications), nil
 }
 
+func (m *mockNotificationServiceNotificationRepo) GetNotificationsByUserIDAfterCursor(ctx context.Context, userID string, cursor *modelpostgre.PageCursor, limit int) ([]modelpostgre.Notification, error) {
+	m.cursor = cursor
+	m.cursorLimit = limit
+	if m.err != nil {
+		return nil, m.err
+	}
+	if len(m.notifications) > limit {
+		return m.notifications[:limit], nil
+	}
+	return m.notifications, nil
+}
+
 func (m *mockNotificationServiceNotificationRepo) GetUnreadCountByUserID(ctx context.Context, userID string) (int, error) {
 	if m.err != nil {
 		return 0, m.err
@@ -77,6 +97,15 @@ func (m *mockNotificationServiceNotificationRepo) MarkAllAsRead(ctx context.Cont
 	return m.err
 }
 
+func (m *mockNotificationServiceNotificationRepo) DeleteNotificationsByAchievement(ctx context.Context, achievementRefID string, notificationType string) (int64, error) {
+	if m.err != nil {
+		return 0, m.err
+	}
+	m.deletedRefID = achievementRefID
+	m.deletedType = notificationType
+	return 1, nil
+}
+
 type mockNotificationServiceStudentRepo struct {
 	byID              *modelpostgre.Student
 	studentIDByUserID string
@@ -140,7 +169,7 @@ func (m *mockNotificationServiceAchievementRepo) GetAchievementByID(ctx context.
 	return m.byID, nil
 }
 
-func (m *mockNotificationServiceAchievementRepo) UpdateAchievement(ctx context.Context, id string, req modelmongo.UpdateAchievementRequest) (*modelmongo.Achievement, error) {
+func (m *mockNotificationServiceAchievementRepo) UpdateAchievement(ctx context.Context, id string, expectedVersion int, req modelmongo.UpdateAchievementRequest) (*modelmongo.Achievement, error) {
 	return nil, m.err
 }
 
@@ -172,6 +201,22 @@ func (m *mockNotificationServiceAchievementRepo) GetTopStudentsByPoints(ctx cont
 	return nil, m.err
 }
 
+func (m *mockNotificationServiceAchievementRepo) UpdateAchievementPoints(ctx context.Context, id string, points int, rubricVersion int, override *modelmongo.PointsOverride) error {
+	return m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) UpdateDuplicateKeys(ctx context.Context, id string, keys []string) error {
+	return m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) FindAchievementsByDuplicateKeys(ctx context.Context, excludeID string, keys []string, limit int) ([]modelmongo.Achievement, error) {
+	return nil, m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) GetDuplicateKeyGroups(ctx context.Context) ([]repositorymongo.DuplicateKeyGroup, error) {
+	return nil, m.err
+}
+
 type mockNotificationServiceUserRepo struct {
 	roleName         string
 	lecturerByID     *modelpostgre.Lecturer
@@ -243,6 +288,10 @@ func (m *mockNotificationServiceUserRepo) GetLecturerByID(ctx context.Context, i
 	return m.lecturerByID, nil
 }
 
+func (m *mockNotificationServiceUserRepo) GetActiveUserIDsByRoleName(ctx context.Context, roleName string) ([]string, error) {
+	return nil, m.err
+}
+
 func TestGetNotifications_Success(t *testing.T) {
 	ctx := setupTestContext()
 
@@ -297,6 +346,104 @@ func TestGetNotifications_Success(t *testing.T) {
 	}
 }
 
+func newCursorNotifications() []modelpostgre.Notification {
+	base := time.Date(2025, 3, 1, 8, 0, 0, 0, time.UTC)
+	return []modelpostgre.Notification{
+		{ID: "550e8400-e29b-41d4-a716-446655440003", UserID: "user-id-1", CreatedAt: base},
+		{ID: "550e8400-e29b-41d4-a716-446655440002", UserID: "user-id-1", CreatedAt: base.Add(-time.Minute)},
+		{ID: "550e8400-e29b-41d4-a716-446655440001", UserID: "user-id-1", CreatedAt: base.Add(-2 * time.Minute)},
+	}
+}
+
+func TestGetNotifications_PageModeIncludesNextCursor(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockNotificationRepo := &mockNotificationServiceNotificationRepo{notifications: newCursorNotifications()[:2]}
+	service := servicepostgre.NewNotificationService(
+		mockNotificationRepo,
+		&mockNotificationServiceStudentRepo{},
+		&mockNotificationServiceUserRepo{},
+		&mockNotificationServiceAchievementRepo{},
+	)
+
+	// mock selalu mengembalikan 2 notifikasi dengan total 2, limit 1 berarti masih ada halaman berikutnya
+	result, err := service.GetNotifications(ctx, "user-id-1", 1, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Pagination.NextCursor == "" {
+		t.Fatal("Expected next_cursor in page mode when more pages exist")
+	}
+
+	// halaman terakhir tidak punya next_cursor
+	result, err = service.GetNotifications(ctx, "user-id-1", 1, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Pagination.NextCursor != "" {
+		t.Errorf("Expected no next_cursor on last page, got %q", result.Pagination.NextCursor)
+	}
+}
+
+func TestGetNotificationsByCursor_HasMore(t *testing.T) {
+	ctx := setupTestContext()
+
+	notifications := newCursorNotifications()
+	mockNotificationRepo := &mockNotificationServiceNotificationRepo{notifications: notifications}
+	service := servicepostgre.NewNotificationService(
+		mockNotificationRepo,
+		&mockNotificationServiceStudentRepo{},
+		&mockNotificationServiceUserRepo{},
+		&mockNotificationServiceAchievementRepo{},
+	)
+
+	cursor := &modelpostgre.PageCursor{CreatedAt: time.Now(), ID: "550e8400-e29b-41d4-a716-446655440009"}
+	result, err := service.GetNotificationsByCursor(ctx, "user-id-1", cursor, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockNotificationRepo.cursor != cursor || mockNotificationRepo.cursorLimit != 3 {
+		t.Errorf("Expected cursor forwarded with limit+1, got %v limit %d", mockNotificationRepo.cursor, mockNotificationRepo.cursorLimit)
+	}
+
+	if len(result.Data) != 2 || !result.Pagination.HasMore {
+		t.Fatalf("Expected 2 notifications and has_more, got %d %+v", len(result.Data), result.Pagination)
+	}
+
+	createdAt, id, err := helper.DecodeCursor(result.Pagination.NextCursor)
+	if err != nil {
+		t.Fatalf("Expected decodable next_cursor, got %v", err)
+	}
+
+	if id != notifications[1].ID || !createdAt.Equal(notifications[1].CreatedAt) {
+		t.Errorf("Expected next_cursor at second notification, got %s %v", id, createdAt)
+	}
+}
+
+func TestGetNotificationsByCursor_LastPage(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockNotificationRepo := &mockNotificationServiceNotificationRepo{notifications: newCursorNotifications()[:1]}
+	service := servicepostgre.NewNotificationService(
+		mockNotificationRepo,
+		&mockNotificationServiceStudentRepo{},
+		&mockNotificationServiceUserRepo{},
+		&mockNotificationServiceAchievementRepo{},
+	)
+
+	result, err := service.GetNotificationsByCursor(ctx, "user-id-1", nil, 10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Pagination.HasMore || result.Pagination.NextCursor != "" {
+		t.Errorf("Expected no more pages, got %+v", result.Pagination)
+	}
+}
+
 func TestGetNotifications_PaginationNormalization(t *testing.T) {
 	ctx := setupTestContext()
 
@@ -619,3 +766,261 @@ func TestCreateSubmissionNotification_NoAdvisor(t *testing.T) {
 		t.Fatalf("Expected no error when no advisor, got %v", err)
 	}
 }
+
+func (m *mockNotificationServiceAchievementRepo) UpdateTeamMembers(ctx context.Context, id string, members []modelmongo.TeamMember, designatedAdvisorID string) error {
+	return m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) ConfirmTeamMember(ctx context.Context, id string, studentID string, confirmedAt time.Time) error {
+	return m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) SetTeamVerifications(ctx context.Context, id string, verifications []modelmongo.TeamVerification) error {
+	return m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) AddTeamVerification(ctx context.Context, id string, verification modelmongo.TeamVerification, otherAdvisorIDs []string, completes bool) (bool, error) {
+	return m.err == nil, m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) RemoveTeamVerification(ctx context.Context, id string, advisorID string) error {
+	return m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) GetDeletedAchievementsByIDs(ctx context.Context, ids []string) ([]modelmongo.Achievement, error) {
+	return nil, m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) RestoreAchievement(ctx context.Context, id string) error {
+	return m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) PurgeAchievement(ctx context.Context, id string) (bool, error) {
+	return false, m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) GetAchievementsValidUntilBetween(ctx context.Context, from, to time.Time) ([]modelmongo.Achievement, error) {
+	return nil, m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) GetAchievementFacets(ctx context.Context, filter modelmongo.AchievementFilter) (*repositorymongo.AchievementFacetResult, error) {
+	return &repositorymongo.AchievementFacetResult{}, nil
+}
+
+func (m *mockNotificationServiceAchievementRepo) GetMatchingAchievementIDs(ctx context.Context, filter modelmongo.AchievementFilter) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockNotificationServiceAchievementRepo) SearchAchievements(ctx context.Context, query string, achievementIDs []string, page, limit int) ([]repositorymongo.AchievementSearchResult, int, error) {
+	return nil, 0, m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) GetAllAchievementStates(ctx context.Context) ([]repositorymongo.AchievementStoreState, error) {
+	return nil, m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) GetAchievementsByAttachmentURLPrefix(ctx context.Context, prefix string) ([]modelmongo.Achievement, error) {
+	return nil, m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) UpdateAttachmentFileURL(ctx context.Context, id string, oldURL string, newURL string) (bool, error) {
+	return false, m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) GetAchievementsByAttachmentScanStatus(ctx context.Context, status string, limit int) ([]modelmongo.Achievement, error) {
+	return nil, m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) UpdateAttachmentScanResult(ctx context.Context, id string, fileURL string, status string, signature string, newURL string) (bool, error) {
+	return false, m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) RemoveAttachment(ctx context.Context, id string, attachmentID string) (*modelmongo.Achievement, error) {
+	return nil, m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) ReplaceAttachment(ctx context.Context, id string, attachmentID string, attachment modelmongo.Attachment) (*modelmongo.Achievement, error) {
+	return nil, m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) CountAttachmentFileReferences(ctx context.Context, fileURL string) (int64, error) {
+	return 0, m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) ReserveAttachmentQuota(ctx context.Context, studentID string, size int64, expiresAt time.Time) (string, error) {
+	return "", m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) GetStudentReservedAttachmentQuota(ctx context.Context, studentID string, excludeID string, now time.Time) (int64, error) {
+	return 0, m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) ReleaseAttachmentQuota(ctx context.Context, id string) error {
+	return m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) GetStudentAttachmentUsage(ctx context.Context, studentID string) (int64, error) {
+	return 0, m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) GetAttachmentUsageByStudent(ctx context.Context) ([]repositorymongo.StudentAttachmentUsage, error) {
+	return nil, m.err
+}
+
+func (m *mockNotificationServiceAchievementRepo) GetAttachmentStorageTotals(ctx context.Context) (*repositorymongo.AttachmentStorageTotals, error) {
+	return nil, m.err
+}
+
+func TestRetractSubmissionNotification_Success(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockNotificationRepo := &mockNotificationServiceNotificationRepo{}
+
+	service := servicepostgre.NewNotificationService(
+		mockNotificationRepo,
+		&mockNotificationServiceStudentRepo{},
+		&mockNotificationServiceUserRepo{},
+		&mockNotificationServiceAchievementRepo{},
+	)
+
+	if err := service.RetractSubmissionNotification(ctx, "ref-id-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if mockNotificationRepo.deletedRefID != "ref-id-1" || mockNotificationRepo.deletedType != modelpostgre.NotificationTypeAchievementSubmitted {
+		t.Errorf("Expected achievement_submitted notifications deleted for ref-id-1, got %q/%q", mockNotificationRepo.deletedRefID, mockNotificationRepo.deletedType)
+	}
+}
+
+func TestCreateSLAReminderNotification_Success(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockNotificationRepo := &mockNotificationServiceNotificationRepo{}
+
+	service := servicepostgre.NewNotificationService(
+		mockNotificationRepo,
+		&mockNotificationServiceStudentRepo{
+			byID: &modelpostgre.Student{ID: "student-id-1", AdvisorID: "lecturer-id-1"},
+		},
+		&mockNotificationServiceUserRepo{
+			lecturerByID: &modelpostgre.Lecturer{ID: "lecturer-id-1", UserID: "lecturer-user-id-1"},
+		},
+		&mockNotificationServiceAchievementRepo{
+			byID: &modelmongo.Achievement{ID: primitive.NewObjectID(), StudentID: "student-id-1", Title: "Test Achievement"},
+		},
+	)
+
+	if err := service.CreateSLAReminderNotification(ctx, "student-id-1", "mongo-id-1", "ref-id-1", 4); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockNotificationRepo.created) != 1 {
+		t.Fatalf("Expected 1 notification, got %d", len(mockNotificationRepo.created))
+	}
+
+	created := mockNotificationRepo.created[0]
+	if created.UserID != "lecturer-user-id-1" || created.Type != modelpostgre.NotificationTypeSLAReminder {
+		t.Errorf("Expected sla reminder for lecturer-user-id-1, got %s/%s", created.UserID, created.Type)
+	}
+
+	if !strings.Contains(created.Message, "4 hari") {
+		t.Errorf("Expected message to contain waiting days, got %s", created.Message)
+	}
+}
+
+func TestCreateSLAEscalationNotification_Success(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockNotificationRepo := &mockNotificationServiceNotificationRepo{}
+
+	service := servicepostgre.NewNotificationService(
+		mockNotificationRepo,
+		&mockNotificationServiceStudentRepo{},
+		&mockNotificationServiceUserRepo{},
+		&mockNotificationServiceAchievementRepo{},
+	)
+
+	err := service.CreateSLAEscalationNotification(ctx, []string{"admin-user-id-1", "admin-user-id-2"}, "mongo-id-1", "ref-id-1", 8)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockNotificationRepo.created) != 2 {
+		t.Fatalf("Expected 2 notifications, got %d", len(mockNotificationRepo.created))
+	}
+
+	for _, created := range mockNotificationRepo.created {
+		if created.Type != modelpostgre.NotificationTypeSLAEscalated {
+			t.Errorf("Expected type %s, got %s", modelpostgre.NotificationTypeSLAEscalated, created.Type)
+		}
+	}
+}
+
+func TestCreateCertificationExpiryNotification_Success(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockNotificationRepo := &mockNotificationServiceNotificationRepo{}
+
+	service := servicepostgre.NewNotificationService(
+		mockNotificationRepo,
+		&mockNotificationServiceStudentRepo{
+			byID: &modelpostgre.Student{ID: "student-id-1", UserID: "student-user-id-1"},
+		},
+		&mockNotificationServiceUserRepo{},
+		&mockNotificationServiceAchievementRepo{
+			byID: &modelmongo.Achievement{ID: primitive.NewObjectID(), StudentID: "student-id-1", Title: "AWS Certified Developer"},
+		},
+	)
+
+	validUntil := time.Date(2026, 11, 20, 0, 0, 0, 0, time.UTC)
+	if err := service.CreateCertificationExpiryNotification(ctx, "student-id-1", "mongo-id-1", "ref-id-1", validUntil, 7); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockNotificationRepo.created) != 1 {
+		t.Fatalf("Expected 1 notification, got %d", len(mockNotificationRepo.created))
+	}
+
+	created := mockNotificationRepo.created[0]
+	if created.UserID != "student-user-id-1" || created.Type != modelpostgre.NotificationTypeCertificationExpiring {
+		t.Errorf("Expected certification expiring notification for student-user-id-1, got %s/%s", created.UserID, created.Type)
+	}
+
+	if !strings.Contains(created.Message, "7 hari") || !strings.Contains(created.Message, "20-11-2026") {
+		t.Errorf("Expected message to contain days left and expiry date, got %s", created.Message)
+	}
+}
+
+func TestCreateAttachmentInfectedNotification_Success(t *testing.T) {
+	ctx := setupTestContext()
+
+	mockNotificationRepo := &mockNotificationServiceNotificationRepo{}
+
+	service := servicepostgre.NewNotificationService(
+		mockNotificationRepo,
+		&mockNotificationServiceStudentRepo{
+			byID: &modelpostgre.Student{ID: "student-id-1", UserID: "student-user-id-1"},
+		},
+		&mockNotificationServiceUserRepo{},
+		&mockNotificationServiceAchievementRepo{
+			byID: &modelmongo.Achievement{ID: primitive.NewObjectID(), StudentID: "student-id-1", Title: "Juara 1 Hackathon"},
+		},
+	)
+
+	if err := service.CreateAttachmentInfectedNotification(ctx, "student-id-1", "mongo-id-1", "ref-id-1", "sertifikat.pdf"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(mockNotificationRepo.created) != 1 {
+		t.Fatalf("Expected 1 notification, got %d", len(mockNotificationRepo.created))
+	}
+
+	created := mockNotificationRepo.created[0]
+	if created.UserID != "student-user-id-1" || created.Type != modelpostgre.NotificationTypeAttachmentInfected {
+		t.Errorf("Expected attachment infected notification for student-user-id-1, got %s/%s", created.UserID, created.Type)
+	}
+
+	if !strings.Contains(created.Message, "sertifikat.pdf") || !strings.Contains(created.Message, "Juara 1 Hackathon") {
+		t.Errorf("Expected message to contain file name and achievement title, got %s", created.Message)
+	}
+}
@@ -0,0 +1,259 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+)
+
+type mockPointRubricRepo struct {
+	active       *modelpostgre.PointRubric
+	byVersion    *modelpostgre.PointRubric
+	all          []modelpostgre.PointRubric
+	created      *modelpostgre.PointRubric
+	err          error
+	activatedVer int
+}
+
+func (m *mockPointRubricRepo) CreatePointRubric(ctx context.Context, req modelpostgre.CreatePointRubricRequest, createdBy string) (*modelpostgre.PointRubric, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.created, nil
+}
+
+func (m *mockPointRubricRepo) GetActivePointRubric(ctx context.Context) (*modelpostgre.PointRubric, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.active == nil {
+		return nil, sql.ErrNoRows
+	}
+	return m.active, nil
+}
+
+func (m *mockPointRubricRepo) GetPointRubricByVersion(ctx context.Context, version int) (*modelpostgre.PointRubric, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.byVersion == nil {
+		return nil, sql.ErrNoRows
+	}
+	return m.byVersion, nil
+}
+
+func (m *mockPointRubricRepo) GetAllPointRubrics(ctx context.Context) ([]modelpostgre.PointRubric, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.all, nil
+}
+
+func (m *mockPointRubricRepo) ActivatePointRubric(ctx context.Context, version int) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.activatedVer = version
+	return nil
+}
+
+func defaultTestRubric() *modelpostgre.PointRubric {
+	return &modelpostgre.PointRubric{
+		ID:       "rubric-id-1",
+		Version:  3,
+		Name:     "Rubrik Test",
+		IsActive: true,
+		Rules: []modelpostgre.PointRule{
+			{AchievementType: "competition", Points: 10},
+			{AchievementType: "competition", Field: modelpostgre.PointRuleFieldCompetitionLevel, Value: "national", Points: 50},
+			{AchievementType: "competition", Field: modelpostgre.PointRuleFieldRank, Value: "1", Points: 30},
+			{AchievementType: "competition", Field: modelpostgre.PointRuleFieldMedalType, Value: "gold", Points: 15},
+			{AchievementType: "publication", Field: modelpostgre.PointRuleFieldPublicationType, Value: "journal", Points: 60},
+		},
+	}
+}
+
+func TestCalculatePoints_CompetitionMatchesRules(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := servicepostgre.NewPointRubricService(&mockPointRubricRepo{active: defaultTestRubric()}, newTestAchievementTypeService())
+
+	level := "National"
+	rank := 1
+	medal := "silver"
+	result, err := service.CalculatePoints(ctx, "competition", modelmongo.AchievementDetails{
+		CompetitionLevel: &level,
+		Rank:             &rank,
+		MedalType:        &medal,
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Points != 90 {
+		t.Errorf("Expected 90 points (10 base + 50 national + 30 rank 1), got %d", result.Points)
+	}
+
+	if result.RubricVersion != 3 {
+		t.Errorf("Expected rubric version 3, got %d", result.RubricVersion)
+	}
+
+	if len(result.MatchedRules) != 3 {
+		t.Errorf("Expected 3 matched rules, got %d", len(result.MatchedRules))
+	}
+}
+
+func TestCalculatePoints_OtherTypeIgnoresRules(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := servicepostgre.NewPointRubricService(&mockPointRubricRepo{active: defaultTestRubric()}, newTestAchievementTypeService())
+
+	level := "national"
+	result, err := service.CalculatePoints(ctx, "academic", modelmongo.AchievementDetails{
+		CompetitionLevel: &level,
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Points != 0 {
+		t.Errorf("Expected 0 points for type without rules, got %d", result.Points)
+	}
+}
+
+func TestCalculatePoints_NoActiveRubric(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := servicepostgre.NewPointRubricService(&mockPointRubricRepo{}, newTestAchievementTypeService())
+
+	result, err := service.CalculatePoints(ctx, "competition", modelmongo.AchievementDetails{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Points != 0 || result.RubricVersion != 0 {
+		t.Errorf("Expected 0 points with version 0, got %d with version %d", result.Points, result.RubricVersion)
+	}
+}
+
+func TestCreatePointRubric_ValidationErrors(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := servicepostgre.NewPointRubricService(&mockPointRubricRepo{}, newTestAchievementTypeService())
+
+	testCases := []struct {
+		name string
+		req  modelpostgre.CreatePointRubricRequest
+		want string
+	}{
+		{
+			name: "empty name",
+			req: modelpostgre.CreatePointRubricRequest{
+				Rules: []modelpostgre.PointRule{{AchievementType: "academic", Points: 10}},
+			},
+			want: "nama rubrik wajib diisi",
+		},
+		{
+			name: "no rules",
+			req: modelpostgre.CreatePointRubricRequest{
+				Name: "Rubrik",
+			},
+			want: "minimal satu aturan",
+		},
+		{
+			name: "invalid achievement type",
+			req: modelpostgre.CreatePointRubricRequest{
+				Name:  "Rubrik",
+				Rules: []modelpostgre.PointRule{{AchievementType: "sport", Points: 10}},
+			},
+			want: "achievement type tidak valid",
+		},
+		{
+			name: "unknown field",
+			req: modelpostgre.CreatePointRubricRequest{
+				Name:  "Rubrik",
+				Rules: []modelpostgre.PointRule{{AchievementType: "competition", Field: "location", Value: "Jakarta", Points: 10}},
+			},
+			want: "field aturan tidak dikenal",
+		},
+		{
+			name: "field without value",
+			req: modelpostgre.CreatePointRubricRequest{
+				Name:  "Rubrik",
+				Rules: []modelpostgre.PointRule{{AchievementType: "competition", Field: "rank", Points: 10}},
+			},
+			want: "value wajib diisi",
+		},
+		{
+			name: "negative points",
+			req: modelpostgre.CreatePointRubricRequest{
+				Name:  "Rubrik",
+				Rules: []modelpostgre.PointRule{{AchievementType: "academic", Points: -5}},
+			},
+			want: "poin tidak boleh negatif",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := service.CreatePointRubric(ctx, "admin-id-1", tc.req)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+			if !contains(err.Error(), tc.want) {
+				t.Errorf("Expected error containing '%s', got: %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestCreatePointRubric_ActivateImmediately(t *testing.T) {
+	ctx := setupTestContext()
+
+	created := &modelpostgre.PointRubric{ID: "rubric-id-4", Version: 4, Name: "Rubrik Baru"}
+	active := &modelpostgre.PointRubric{ID: "rubric-id-4", Version: 4, Name: "Rubrik Baru", IsActive: true}
+	repo := &mockPointRubricRepo{created: created, byVersion: active}
+
+	service := servicepostgre.NewPointRubricService(repo, newTestAchievementTypeService())
+
+	result, err := service.CreatePointRubric(ctx, "admin-id-1", modelpostgre.CreatePointRubricRequest{
+		Name:     "Rubrik Baru",
+		Rules:    []modelpostgre.PointRule{{AchievementType: "academic", Points: 25}},
+		Activate: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if repo.activatedVer != 4 {
+		t.Errorf("Expected version 4 to be activated, got %d", repo.activatedVer)
+	}
+
+	if !result.Data.IsActive {
+		t.Error("Expected returned rubric to be active")
+	}
+}
+
+func TestActivatePointRubric_NotFound(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := servicepostgre.NewPointRubricService(&mockPointRubricRepo{err: sql.ErrNoRows}, newTestAchievementTypeService())
+
+	_, err := service.ActivatePointRubric(ctx, 99)
+
+	if err == nil {
+		t.Fatal("Expected error, got nil")
+	}
+
+	if err.Error() != "rubrik poin tidak ditemukan" {
+		t.Errorf("Expected 'rubrik poin tidak ditemukan', got: %v", err)
+	}
+}
@@ -0,0 +1,346 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	repositorymongo "sistem-pelaporan-prestasi-mahasiswa/app/repository/mongo"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// reconcileMongoRepo menyimpan dokumen di memori supaya rekonsiliasi bisa membaca ulang hasil perbaikan
+type reconcileMongoRepo struct {
+	mockAchievementRepo
+	docs map[string]*modelmongo.Achievement
+}
+
+func (m *reconcileMongoRepo) GetAllAchievementStates(ctx context.Context) ([]repositorymongo.AchievementStoreState, error) {
+	var states []repositorymongo.AchievementStoreState
+	for _, doc := range m.docs {
+		states = append(states, repositorymongo.AchievementStoreState{ID: doc.ID, StudentID: doc.StudentID, CreatedAt: doc.CreatedAt, DeletedAt: doc.DeletedAt})
+	}
+	return states, nil
+}
+
+func (m *reconcileMongoRepo) GetAchievementByID(ctx context.Context, id string) (*modelmongo.Achievement, error) {
+	if doc, ok := m.docs[id]; ok && doc.DeletedAt == nil {
+		return doc, nil
+	}
+	return nil, nil
+}
+
+func (m *reconcileMongoRepo) GetDeletedAchievementsByIDs(ctx context.Context, ids []string) ([]modelmongo.Achievement, error) {
+	var result []modelmongo.Achievement
+	for _, id := range ids {
+		if doc, ok := m.docs[id]; ok && doc.DeletedAt != nil {
+			result = append(result, *doc)
+		}
+	}
+	return result, nil
+}
+
+func (m *reconcileMongoRepo) DeleteAchievement(ctx context.Context, id string) error {
+	now := time.Now()
+	m.docs[id].DeletedAt = &now
+	return nil
+}
+
+func (m *reconcileMongoRepo) RestoreAchievement(ctx context.Context, id string) error {
+	m.docs[id].DeletedAt = nil
+	return nil
+}
+
+func (m *reconcileMongoRepo) PurgeAchievement(ctx context.Context, id string) (bool, error) {
+	if doc, ok := m.docs[id]; ok && doc.DeletedAt != nil {
+		delete(m.docs, id)
+		return true, nil
+	}
+	return false, nil
+}
+
+// reconcileRefRepo menyimpan reference di memori dengan key mongo achievement ID
+type reconcileRefRepo struct {
+	mockAchievementRefRepo
+	refs map[string]*modelpostgre.AchievementReference
+}
+
+func (m *reconcileRefRepo) mongoIDs(deleted bool) []string {
+	var ids []string
+	for id, ref := range m.refs {
+		if (ref.Status == modelpostgre.AchievementStatusDeleted) == deleted {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func (m *reconcileRefRepo) findByID(id string) *modelpostgre.AchievementReference {
+	for _, ref := range m.refs {
+		if ref.ID == id {
+			return ref
+		}
+	}
+	return nil
+}
+
+func (m *reconcileRefRepo) GetAllAchievementMongoIDs(ctx context.Context) ([]string, error) {
+	return m.mongoIDs(false), nil
+}
+
+func (m *reconcileRefRepo) GetDeletedAchievementMongoIDs(ctx context.Context) ([]string, error) {
+	return m.mongoIDs(true), nil
+}
+
+func (m *reconcileRefRepo) GetAchievementReferenceByMongoID(ctx context.Context, mongoID string) (*modelpostgre.AchievementReference, error) {
+	if ref, ok := m.refs[mongoID]; ok && ref.Status != modelpostgre.AchievementStatusDeleted {
+		return ref, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *reconcileRefRepo) GetDeletedAchievementReferenceByMongoID(ctx context.Context, mongoID string) (*modelpostgre.DeletedAchievementReference, error) {
+	if ref, ok := m.refs[mongoID]; ok && ref.Status == modelpostgre.AchievementStatusDeleted {
+		return &modelpostgre.DeletedAchievementReference{AchievementReference: *ref, StatusBeforeDelete: modelpostgre.AchievementStatusDraft}, nil
+	}
+	return nil, sql.ErrNoRows
+}
+
+func (m *reconcileRefRepo) CreateAchievementReference(ctx context.Context, req modelpostgre.CreateAchievementReferenceRequest) (*modelpostgre.AchievementReference, error) {
+	ref := &modelpostgre.AchievementReference{ID: "ref-" + req.MongoAchievementID, StudentID: req.StudentID, MongoAchievementID: req.MongoAchievementID, Status: req.Status}
+	m.refs[req.MongoAchievementID] = ref
+	return ref, nil
+}
+
+func (m *reconcileRefRepo) SoftDeleteAchievementReference(ctx context.Context, id string) error {
+	m.findByID(id).Status = modelpostgre.AchievementStatusDeleted
+	return nil
+}
+
+func (m *reconcileRefRepo) RestoreAchievementReference(ctx context.Context, id string) (string, error) {
+	m.findByID(id).Status = modelpostgre.AchievementStatusDraft
+	return modelpostgre.AchievementStatusDraft, nil
+}
+
+func (m *reconcileRefRepo) DeleteAchievementReference(ctx context.Context, id string) error {
+	delete(m.refs, m.findByID(id).MongoAchievementID)
+	return nil
+}
+
+func (m *reconcileRefRepo) PurgeAchievementReference(ctx context.Context, id string) (bool, error) {
+	delete(m.refs, m.findByID(id).MongoAchievementID)
+	return true, nil
+}
+
+// newReconcileFixture membuat satu contoh untuk setiap kategori ditambah satu pasangan yang konsisten
+func newReconcileFixture() (*reconcileMongoRepo, *reconcileRefRepo, map[string]string) {
+	ids := map[string]string{}
+	for _, name := range []string{"consistent", "mongoOrphan", "recentOrphan", "refOrphan", "mongoDeleted", "refDeleted"} {
+		ids[name] = primitive.NewObjectID().Hex()
+	}
+
+	old := time.Now().Add(-24 * time.Hour)
+	deletedAt := time.Now().Add(-time.Hour)
+	newDoc := func(id string, deleted bool, createdAt time.Time) *modelmongo.Achievement {
+		objectID, _ := primitive.ObjectIDFromHex(id)
+		doc := &modelmongo.Achievement{ID: objectID, StudentID: "student-1", Title: "Prestasi", CreatedAt: createdAt}
+		if deleted {
+			doc.DeletedAt = &deletedAt
+		}
+		return doc
+	}
+	newRef := func(id string, status string) *modelpostgre.AchievementReference {
+		return &modelpostgre.AchievementReference{ID: "ref-" + id, StudentID: "student-1", MongoAchievementID: id, Status: status}
+	}
+
+	mongoRepo := &reconcileMongoRepo{docs: map[string]*modelmongo.Achievement{
+		ids["consistent"]:   newDoc(ids["consistent"], false, old),
+		ids["mongoOrphan"]:  newDoc(ids["mongoOrphan"], false, old),
+		ids["recentOrphan"]: newDoc(ids["recentOrphan"], false, time.Now()),
+		ids["mongoDeleted"]: newDoc(ids["mongoDeleted"], true, old),
+		ids["refDeleted"]:   newDoc(ids["refDeleted"], false, old),
+	}}
+	refRepo := &reconcileRefRepo{refs: map[string]*modelpostgre.AchievementReference{
+		ids["consistent"]:   newRef(ids["consistent"], modelpostgre.AchievementStatusSubmitted),
+		ids["refOrphan"]:    newRef(ids["refOrphan"], modelpostgre.AchievementStatusDraft),
+		ids["mongoDeleted"]: newRef(ids["mongoDeleted"], modelpostgre.AchievementStatusVerified),
+		ids["refDeleted"]:   newRef(ids["refDeleted"], modelpostgre.AchievementStatusDeleted),
+	}}
+
+	return mongoRepo, refRepo, ids
+}
+
+func newReconcileService(mongoRepo *reconcileMongoRepo, refRepo *reconcileRefRepo, roleName string) servicepostgre.IReconciliationService {
+	return servicepostgre.NewReconciliationService(refRepo, mongoRepo, &mockUserRepo{roleName: roleName}, &mockFileStorage{}, modelpostgre.ReconcileConfig{GracePeriod: 10 * time.Minute})
+}
+
+func findReconcileIssue(result *modelpostgre.ReconcileResult, mongoID string) *modelpostgre.ReconcileIssue {
+	for i := range result.Issues {
+		if result.Issues[i].MongoAchievementID == mongoID {
+			return &result.Issues[i]
+		}
+	}
+	return nil
+}
+
+func TestGetReconciliationReport_CategorizesWithoutRepair(t *testing.T) {
+	ctx := setupTestContext()
+
+	mongoRepo, refRepo, ids := newReconcileFixture()
+	service := newReconcileService(mongoRepo, refRepo, "Admin")
+
+	response, err := service.GetReconciliationReport(ctx, "admin-id", "role-id-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	result := &response.Data
+	expected := map[string]string{
+		ids["mongoOrphan"]:  modelpostgre.ReconcileCategoryMongoWithoutReference,
+		ids["refOrphan"]:    modelpostgre.ReconcileCategoryReferenceWithoutMongo,
+		ids["mongoDeleted"]: modelpostgre.ReconcileCategoryDeletedMismatch,
+		ids["refDeleted"]:   modelpostgre.ReconcileCategoryDeletedMismatch,
+	}
+	if result.TotalIssues != len(expected) {
+		t.Fatalf("Expected %d issues, got %+v", len(expected), result.Issues)
+	}
+	for mongoID, category := range expected {
+		issue := findReconcileIssue(result, mongoID)
+		if issue == nil || issue.Category != category || issue.Repaired {
+			t.Errorf("Expected unrepaired %s issue for %s, got %+v", category, mongoID, issue)
+		}
+	}
+
+	if result.SkippedRecent != 1 {
+		t.Errorf("Expected recent orphan skipped by grace period, got %d", result.SkippedRecent)
+	}
+
+	if len(mongoRepo.docs) != 5 || len(refRepo.refs) != 4 {
+		t.Error("Expected report not to change any data")
+	}
+}
+
+func TestRepairInconsistencies_Policies(t *testing.T) {
+	ctx := setupTestContext()
+
+	testCases := []struct {
+		name   string
+		policy modelpostgre.ReconcilePolicy
+		check  func(t *testing.T, mongoRepo *reconcileMongoRepo, refRepo *reconcileRefRepo, ids map[string]string)
+	}{
+		{
+			name:   "purge dan follow_reference",
+			policy: modelpostgre.ReconcilePolicy{MongoWithoutReference: "purge", ReferenceWithoutMongo: "delete_reference", DeletedMismatch: "follow_reference"},
+			check: func(t *testing.T, mongoRepo *reconcileMongoRepo, refRepo *reconcileRefRepo, ids map[string]string) {
+				if _, ok := mongoRepo.docs[ids["mongoOrphan"]]; ok {
+					t.Error("Expected orphan document purged")
+				}
+				if _, ok := refRepo.refs[ids["refOrphan"]]; ok {
+					t.Error("Expected orphan reference deleted")
+				}
+				if mongoRepo.docs[ids["mongoDeleted"]].DeletedAt != nil {
+					t.Error("Expected document restored to follow active reference")
+				}
+				if mongoRepo.docs[ids["refDeleted"]].DeletedAt == nil {
+					t.Error("Expected document soft deleted to follow deleted reference")
+				}
+			},
+		},
+		{
+			name:   "adopt dan follow_mongo",
+			policy: modelpostgre.ReconcilePolicy{MongoWithoutReference: "adopt", DeletedMismatch: "follow_mongo"},
+			check: func(t *testing.T, mongoRepo *reconcileMongoRepo, refRepo *reconcileRefRepo, ids map[string]string) {
+				if ref, ok := refRepo.refs[ids["mongoOrphan"]]; !ok || ref.Status != modelpostgre.AchievementStatusDraft {
+					t.Errorf("Expected draft reference adopted, got %+v", ref)
+				}
+				if _, ok := refRepo.refs[ids["refOrphan"]]; !ok {
+					t.Error("Expected orphan reference kept with report policy")
+				}
+				if refRepo.refs[ids["mongoDeleted"]].Status != modelpostgre.AchievementStatusDeleted {
+					t.Error("Expected reference moved to trash to follow deleted document")
+				}
+				if refRepo.refs[ids["refDeleted"]].Status == modelpostgre.AchievementStatusDeleted {
+					t.Error("Expected reference restored to follow active document")
+				}
+				if len(refRepo.recordedHistory) != 3 {
+					t.Errorf("Expected reconciled history for each surviving reference, got %+v", refRepo.recordedHistory)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mongoRepo, refRepo, ids := newReconcileFixture()
+			service := newReconcileService(mongoRepo, refRepo, "Admin")
+
+			if _, err := service.RepairInconsistencies(ctx, "admin-id", "role-id-1", tc.policy); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			tc.check(t, mongoRepo, refRepo, ids)
+
+			// rekonsiliasi kedua hanya menyisakan kategori yang memakai policy report
+			result, err := service.RunReconciliation(ctx, tc.policy)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			for _, issue := range result.Issues {
+				if issue.Action != modelpostgre.ReconcilePolicyReport {
+					t.Errorf("Expected repaired issue gone on second run, got %+v", issue)
+				}
+			}
+		})
+	}
+}
+
+func TestRepairInconsistencies_InvalidPolicy(t *testing.T) {
+	ctx := setupTestContext()
+
+	mongoRepo, refRepo, _ := newReconcileFixture()
+	service := newReconcileService(mongoRepo, refRepo, "Admin")
+
+	_, err := service.RepairInconsistencies(ctx, "admin-id", "role-id-1", modelpostgre.ReconcilePolicy{ReferenceWithoutMongo: "adopt"})
+
+	if err == nil || !strings.Contains(err.Error(), "policy tidak valid") {
+		t.Fatalf("Expected invalid policy error, got %v", err)
+	}
+}
+
+func TestGetReconciliationReport_NotAdmin(t *testing.T) {
+	ctx := setupTestContext()
+
+	mongoRepo, refRepo, _ := newReconcileFixture()
+	service := newReconcileService(mongoRepo, refRepo, "Dosen Wali")
+
+	_, err := service.GetReconciliationReport(ctx, "user-id-1", "role-id-1")
+
+	if err == nil || !strings.Contains(err.Error(), "akses ditolak") {
+		t.Fatalf("Expected access denied, got %v", err)
+	}
+}
+
+func TestFormatReconcileReport(t *testing.T) {
+	ctx := setupTestContext()
+
+	mongoRepo, refRepo, ids := newReconcileFixture()
+	service := newReconcileService(mongoRepo, refRepo, "Admin")
+
+	result, err := service.RunReconciliation(ctx, modelpostgre.ReconcilePolicy{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	text := servicepostgre.FormatReconcileReport(result)
+	for _, expected := range []string{modelpostgre.ReconcileCategoryMongoWithoutReference, ids["refOrphan"], "4 ditemukan"} {
+		if !strings.Contains(text, expected) {
+			t.Errorf("Expected report to contain %q, got:\n%s", expected, text)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package service_test
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"testing"
 	"time"
 
@@ -20,6 +21,9 @@ type mockReportServiceAchievementRepo struct {
 	byType               map[string]int
 	competitionLevelDist map[string]int
 	topStudents          []repositorymongo.TopStudentResult
+	duplicateGroups      []repositorymongo.DuplicateKeyGroup
+	studentUsages        []repositorymongo.StudentAttachmentUsage
+	storageTotals        *repositorymongo.AttachmentStorageTotals
 	err                  error
 }
 
@@ -31,7 +35,7 @@ func (m *mockReportServiceAchievementRepo) GetAchievementByID(ctx context.Contex
 	return nil, m.err
 }
 
-func (m *mockReportServiceAchievementRepo) UpdateAchievement(ctx context.Context, id string, req modelmongo.UpdateAchievementRequest) (*modelmongo.Achievement, error) {
+func (m *mockReportServiceAchievementRepo) UpdateAchievement(ctx context.Context, id string, expectedVersion int, req modelmongo.UpdateAchievementRequest) (*modelmongo.Achievement, error) {
 	return nil, m.err
 }
 
@@ -78,15 +82,36 @@ func (m *mockReportServiceAchievementRepo) GetTopStudentsByPoints(ctx context.Co
 	return m.topStudents, nil
 }
 
+func (m *mockReportServiceAchievementRepo) UpdateAchievementPoints(ctx context.Context, id string, points int, rubricVersion int, override *modelmongo.PointsOverride) error {
+	return m.err
+}
+
+func (m *mockReportServiceAchievementRepo) UpdateDuplicateKeys(ctx context.Context, id string, keys []string) error {
+	return m.err
+}
+
+func (m *mockReportServiceAchievementRepo) FindAchievementsByDuplicateKeys(ctx context.Context, excludeID string, keys []string, limit int) ([]modelmongo.Achievement, error) {
+	return nil, m.err
+}
+
+func (m *mockReportServiceAchievementRepo) GetDuplicateKeyGroups(ctx context.Context) ([]repositorymongo.DuplicateKeyGroup, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.duplicateGroups, nil
+}
+
 type mockReportServiceAchievementRefRepo struct {
-	byStudentID   []modelpostgre.AchievementReference
-	byAdvisorID   []modelpostgre.AchievementReference
-	allReferences []modelpostgre.AchievementReference
-	statsTotal    int
-	statsVerified int
-	byPeriod      map[string]int
-	allMongoIDs   []string
-	err           error
+	byStudentID    []modelpostgre.AchievementReference
+	byAdvisorID    []modelpostgre.AchievementReference
+	allReferences  []modelpostgre.AchievementReference
+	statsTotal     int
+	statsVerified  int
+	byPeriod       map[string]int
+	allMongoIDs    []string
+	byMongoIDs     []modelpostgre.AchievementReference
+	mongoIDLookups int
+	err            error
 }
 
 func (m *mockReportServiceAchievementRefRepo) CreateAchievementReference(ctx context.Context, req modelpostgre.CreateAchievementReferenceRequest) (*modelpostgre.AchievementReference, error) {
@@ -142,12 +167,12 @@ func (m *mockReportServiceAchievementRefRepo) GetAllAchievementReferencesPaginat
 	return nil, 0, m.err
 }
 
-func (m *mockReportServiceAchievementRefRepo) UpdateAchievementReferenceVerify(ctx context.Context, id string, verifiedBy string) error {
-	return m.err
+func (m *mockReportServiceAchievementRefRepo) UpdateAchievementReferenceVerify(ctx context.Context, id string, verifiedBy string, expectedVersion int) (bool, error) {
+	return true, m.err
 }
 
-func (m *mockReportServiceAchievementRefRepo) UpdateAchievementReferenceReject(ctx context.Context, id string, verifiedBy string, rejectionNote string) error {
-	return m.err
+func (m *mockReportServiceAchievementRefRepo) UpdateAchievementReferenceReject(ctx context.Context, id string, verifiedBy string, rejectionNote string, expectedVersion int) (bool, error) {
+	return true, m.err
 }
 
 func (m *mockReportServiceAchievementRefRepo) GetAchievementStats(ctx context.Context) (int, int, error) {
@@ -171,10 +196,79 @@ func (m *mockReportServiceAchievementRefRepo) GetAllAchievementMongoIDs(ctx cont
 	return m.allMongoIDs, nil
 }
 
+func (m *mockReportServiceAchievementRefRepo) GetDeletedAchievementMongoIDs(ctx context.Context) ([]string, error) {
+	return nil, m.err
+}
+
+func (m *mockReportServiceAchievementRefRepo) CreateAchievementHistory(ctx context.Context, req modelpostgre.CreateAchievementHistoryRequest) error {
+	return m.err
+}
+
+func (m *mockReportServiceAchievementRefRepo) GetAchievementHistoryByRefID(ctx context.Context, achievementRefID string) ([]modelpostgre.AchievementHistory, error) {
+	return nil, m.err
+}
+
+func (m *mockReportServiceAchievementRefRepo) WithdrawAchievementReference(ctx context.Context, id string) (bool, error) {
+	return false, m.err
+}
+
+func (m *mockReportServiceAchievementRefRepo) GetSubmittedReferencesBefore(ctx context.Context, before time.Time) ([]modelpostgre.AchievementReference, error) {
+	return nil, m.err
+}
+
+func (m *mockReportServiceAchievementRefRepo) SoftDeleteAchievementReference(ctx context.Context, id string) error {
+	return m.err
+}
+
+func (m *mockReportServiceAchievementRefRepo) GetDeletedAchievementReferenceByMongoID(ctx context.Context, mongoID string) (*modelpostgre.DeletedAchievementReference, error) {
+	return nil, m.err
+}
+
+func (m *mockReportServiceAchievementRefRepo) RestoreAchievementReference(ctx context.Context, id string) (string, error) {
+	return "", m.err
+}
+
+func (m *mockReportServiceAchievementRefRepo) GetDeletedAchievementReferencesPaginated(ctx context.Context, page, limit int) ([]modelpostgre.DeletedAchievementReference, int, error) {
+	return nil, 0, m.err
+}
+
+func (m *mockReportServiceAchievementRefRepo) GetDeletedReferencesBefore(ctx context.Context, before time.Time) ([]modelpostgre.DeletedAchievementReference, error) {
+	return nil, m.err
+}
+
+func (m *mockReportServiceAchievementRefRepo) PurgeAchievementReference(ctx context.Context, id string) (bool, error) {
+	return false, m.err
+}
+
+func (m *mockReportServiceAchievementRefRepo) ClaimCertificationExpiryAlert(ctx context.Context, achievementRefID string, validUntil time.Time, daysBefore int) (bool, error) {
+	return false, m.err
+}
+
+func (m *mockReportServiceAchievementRefRepo) GetAchievementListRows(ctx context.Context, filter modelpostgre.AchievementListFilter) ([]modelpostgre.AchievementListRow, error) {
+	return nil, nil
+}
+
+func (m *mockReportServiceAchievementRefRepo) GetAchievementFacetRows(ctx context.Context, filter modelpostgre.AchievementListFilter, after *modelpostgre.PageCursor, limit int) ([]modelpostgre.AchievementListRow, error) {
+	return nil, nil
+}
+
+func (m *mockReportServiceAchievementRefRepo) ReleaseCertificationExpiryAlert(ctx context.Context, achievementRefID string, validUntil time.Time, daysBefore int) error {
+	return m.err
+}
+
+func (m *mockReportServiceAchievementRefRepo) ClaimSLANotification(ctx context.Context, achievementRefID string, submittedAt time.Time, action string) (bool, error) {
+	return false, m.err
+}
+
+func (m *mockReportServiceAchievementRefRepo) ReleaseSLANotification(ctx context.Context, achievementRefID string, submittedAt time.Time, action string) error {
+	return m.err
+}
+
 type mockReportServiceStudentRepo struct {
 	byID        *modelpostgre.Student
 	byUserID    *modelpostgre.Student
 	byAdvisorID []modelpostgre.Student
+	all         []modelpostgre.Student
 	err         error
 }
 
@@ -210,7 +304,7 @@ func (m *mockReportServiceStudentRepo) GetStudentsByAdvisorID(ctx context.Contex
 }
 
 func (m *mockReportServiceStudentRepo) GetAllStudents(ctx context.Context) ([]modelpostgre.Student, error) {
-	return nil, m.err
+	return m.all, m.err
 }
 
 func (m *mockReportServiceStudentRepo) CreateStudent(ctx context.Context, req modelpostgre.CreateStudentRequest) (*modelpostgre.Student, error) {
@@ -315,6 +409,10 @@ func (m *mockReportServiceUserRepo) GetLecturerByID(ctx context.Context, id stri
 	return m.lecturerByID, nil
 }
 
+func (m *mockReportServiceUserRepo) GetActiveUserIDsByRoleName(ctx context.Context, roleName string) ([]string, error) {
+	return nil, m.err
+}
+
 type mockReportServiceLecturerRepo struct {
 	byID *modelpostgre.Lecturer
 	err  error
@@ -395,6 +493,7 @@ func TestGetStatistics_Admin(t *testing.T) {
 		mockStudentRepo,
 		mockUserRepo,
 		&mockReportServiceLecturerRepo{},
+		newTestCertificationConfig(),
 	)
 
 	result, err := service.GetStatistics(ctx, "user-id-1", "role-id-1")
@@ -446,6 +545,7 @@ func TestGetStatistics_Mahasiswa(t *testing.T) {
 		mockStudentRepo,
 		mockUserRepo,
 		&mockReportServiceLecturerRepo{},
+		newTestCertificationConfig(),
 	)
 
 	result, err := service.GetStatistics(ctx, "user-id-1", "role-id-1")
@@ -503,6 +603,7 @@ func TestGetStatistics_DosenWali(t *testing.T) {
 		mockStudentRepo,
 		mockUserRepo,
 		&mockReportServiceLecturerRepo{},
+		newTestCertificationConfig(),
 	)
 
 	result, err := service.GetStatistics(ctx, "user-id-1", "role-id-1")
@@ -533,6 +634,7 @@ func TestGetStatistics_InvalidRole(t *testing.T) {
 		&mockReportServiceStudentRepo{},
 		mockUserRepo,
 		&mockReportServiceLecturerRepo{},
+		newTestCertificationConfig(),
 	)
 
 	_, err := service.GetStatistics(ctx, "user-id-1", "role-id-1")
@@ -601,6 +703,7 @@ func TestGetStudentReport_Success(t *testing.T) {
 		mockStudentRepo,
 		mockUserRepo,
 		&mockReportServiceLecturerRepo{},
+		newTestCertificationConfig(),
 	)
 
 	result, err := service.GetStudentReport(ctx, "student-id-1")
@@ -618,6 +721,68 @@ func TestGetStudentReport_Success(t *testing.T) {
 	}
 }
 
+func TestGetStudentReport_ExcludesExpiredCertificationPoints(t *testing.T) {
+	ctx := setupTestContext()
+
+	expiredID := primitive.NewObjectID()
+	activeID := primitive.NewObjectID()
+	expiredAt := time.Now().AddDate(0, 0, -3)
+
+	mockAchievementRefRepo := &mockReportServiceAchievementRefRepo{
+		byStudentID: []modelpostgre.AchievementReference{
+			{ID: "ref-id-1", StudentID: "student-id-1", MongoAchievementID: expiredID.Hex(), Status: modelpostgre.AchievementStatusVerified},
+			{ID: "ref-id-2", StudentID: "student-id-1", MongoAchievementID: activeID.Hex(), Status: modelpostgre.AchievementStatusVerified},
+		},
+	}
+
+	mockAchievementRepo := &mockReportServiceAchievementRepo{
+		byStudentID: []modelmongo.Achievement{
+			{
+				ID:              expiredID,
+				StudentID:       "student-id-1",
+				AchievementType: "certification",
+				Title:           "Sertifikasi Kedaluwarsa",
+				Points:          30,
+				Details:         modelmongo.AchievementDetails{ValidUntil: &expiredAt},
+			},
+			{
+				ID:              activeID,
+				StudentID:       "student-id-1",
+				AchievementType: "competition",
+				Title:           "Juara Lomba",
+				Points:          100,
+			},
+		},
+	}
+
+	excludeExpiredConfig := newTestCertificationConfig()
+	excludeExpiredConfig.ExcludeExpiredPoints = true
+
+	service := servicepostgre.NewReportService(
+		mockAchievementRepo,
+		mockAchievementRefRepo,
+		&mockReportServiceStudentRepo{byID: &modelpostgre.Student{ID: "student-id-1", UserID: "user-id-1"}},
+		&mockReportServiceUserRepo{byID: &modelpostgre.User{ID: "user-id-1", FullName: "Test User"}},
+		&mockReportServiceLecturerRepo{},
+		excludeExpiredConfig,
+	)
+
+	result, err := service.GetStudentReport(ctx, "student-id-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	statistics := result["data"].(map[string]interface{})["statistics"].(map[string]interface{})
+	if statistics["total_points"] != 100 {
+		t.Errorf("Expected total points 100 without expired certification, got %v", statistics["total_points"])
+	}
+
+	certifications := statistics["certifications"].(map[string]interface{})
+	if certifications["expired"] != 1 {
+		t.Errorf("Expected 1 expired certification, got %v", certifications["expired"])
+	}
+}
+
 func TestGetStudentReport_EmptyStudentID(t *testing.T) {
 	ctx := setupTestContext()
 
@@ -627,6 +792,7 @@ func TestGetStudentReport_EmptyStudentID(t *testing.T) {
 		&mockReportServiceStudentRepo{},
 		&mockReportServiceUserRepo{},
 		&mockReportServiceLecturerRepo{},
+		newTestCertificationConfig(),
 	)
 
 	_, err := service.GetStudentReport(ctx, "")
@@ -653,6 +819,7 @@ func TestGetStudentReport_NotFound(t *testing.T) {
 		mockStudentRepo,
 		&mockReportServiceUserRepo{},
 		&mockReportServiceLecturerRepo{},
+		newTestCertificationConfig(),
 	)
 
 	_, err := service.GetStudentReport(ctx, "nonexistent-id")
@@ -741,6 +908,7 @@ func TestGetLecturerReport_Success(t *testing.T) {
 		mockStudentRepo,
 		mockUserRepo,
 		mockLecturerRepo,
+		newTestCertificationConfig(),
 	)
 
 	result, err := service.GetLecturerReport(ctx, "lecturer-id-1")
@@ -767,6 +935,7 @@ func TestGetLecturerReport_EmptyLecturerID(t *testing.T) {
 		&mockReportServiceStudentRepo{},
 		&mockReportServiceUserRepo{},
 		&mockReportServiceLecturerRepo{},
+		newTestCertificationConfig(),
 	)
 
 	_, err := service.GetLecturerReport(ctx, "")
@@ -842,6 +1011,7 @@ func TestGetCurrentStudentReport_Success(t *testing.T) {
 		mockStudentRepo,
 		mockUserRepo,
 		&mockReportServiceLecturerRepo{},
+		newTestCertificationConfig(),
 	)
 
 	result, err := service.GetCurrentStudentReport(ctx, "user-id-1")
@@ -868,6 +1038,7 @@ func TestGetCurrentStudentReport_EmptyUserID(t *testing.T) {
 		&mockReportServiceStudentRepo{},
 		&mockReportServiceUserRepo{},
 		&mockReportServiceLecturerRepo{},
+		newTestCertificationConfig(),
 	)
 
 	_, err := service.GetCurrentStudentReport(ctx, "")
@@ -894,6 +1065,7 @@ func TestGetCurrentStudentReport_NoStudentProfile(t *testing.T) {
 		mockStudentRepo,
 		&mockReportServiceUserRepo{},
 		&mockReportServiceLecturerRepo{},
+		newTestCertificationConfig(),
 	)
 
 	_, err := service.GetCurrentStudentReport(ctx, "user-id-1")
@@ -988,6 +1160,7 @@ func TestGetCurrentLecturerReport_Success(t *testing.T) {
 		mockStudentRepo,
 		mockUserRepo,
 		mockLecturerRepo,
+		newTestCertificationConfig(),
 	)
 
 	result, err := service.GetCurrentLecturerReport(ctx, "user-id-1")
@@ -1014,6 +1187,7 @@ func TestGetCurrentLecturerReport_EmptyUserID(t *testing.T) {
 		&mockReportServiceStudentRepo{},
 		&mockReportServiceUserRepo{},
 		&mockReportServiceLecturerRepo{},
+		newTestCertificationConfig(),
 	)
 
 	_, err := service.GetCurrentLecturerReport(ctx, "")
@@ -1040,6 +1214,7 @@ func TestGetCurrentLecturerReport_NoLecturerProfile(t *testing.T) {
 		&mockReportServiceStudentRepo{},
 		mockUserRepo,
 		&mockReportServiceLecturerRepo{},
+		newTestCertificationConfig(),
 	)
 
 	_, err := service.GetCurrentLecturerReport(ctx, "user-id-1")
@@ -1052,3 +1227,189 @@ func TestGetCurrentLecturerReport_NoLecturerProfile(t *testing.T) {
 		t.Errorf("Expected specific error message, got: %v", err)
 	}
 }
+
+func (m *mockReportServiceAchievementRepo) UpdateTeamMembers(ctx context.Context, id string, members []modelmongo.TeamMember, designatedAdvisorID string) error {
+	return m.err
+}
+
+func (m *mockReportServiceAchievementRepo) ConfirmTeamMember(ctx context.Context, id string, studentID string, confirmedAt time.Time) error {
+	return m.err
+}
+
+func (m *mockReportServiceAchievementRepo) SetTeamVerifications(ctx context.Context, id string, verifications []modelmongo.TeamVerification) error {
+	return m.err
+}
+
+func (m *mockReportServiceAchievementRepo) AddTeamVerification(ctx context.Context, id string, verification modelmongo.TeamVerification, otherAdvisorIDs []string, completes bool) (bool, error) {
+	return m.err == nil, m.err
+}
+
+func (m *mockReportServiceAchievementRepo) RemoveTeamVerification(ctx context.Context, id string, advisorID string) error {
+	return m.err
+}
+
+func (m *mockReportServiceAchievementRepo) GetDeletedAchievementsByIDs(ctx context.Context, ids []string) ([]modelmongo.Achievement, error) {
+	return nil, m.err
+}
+
+func (m *mockReportServiceAchievementRepo) RestoreAchievement(ctx context.Context, id string) error {
+	return m.err
+}
+
+func (m *mockReportServiceAchievementRepo) PurgeAchievement(ctx context.Context, id string) (bool, error) {
+	return false, m.err
+}
+
+func (m *mockReportServiceAchievementRepo) GetAchievementsValidUntilBetween(ctx context.Context, from, to time.Time) ([]modelmongo.Achievement, error) {
+	return nil, m.err
+}
+
+func (m *mockReportServiceAchievementRepo) GetAchievementFacets(ctx context.Context, filter modelmongo.AchievementFilter) (*repositorymongo.AchievementFacetResult, error) {
+	return &repositorymongo.AchievementFacetResult{}, nil
+}
+
+func (m *mockReportServiceAchievementRepo) GetMatchingAchievementIDs(ctx context.Context, filter modelmongo.AchievementFilter) ([]string, error) {
+	return nil, nil
+}
+
+func (m *mockReportServiceAchievementRepo) GetAllAchievementStates(ctx context.Context) ([]repositorymongo.AchievementStoreState, error) {
+	return nil, m.err
+}
+
+func (m *mockReportServiceAchievementRepo) GetAchievementsByAttachmentURLPrefix(ctx context.Context, prefix string) ([]modelmongo.Achievement, error) {
+	return nil, m.err
+}
+
+func (m *mockReportServiceAchievementRepo) UpdateAttachmentFileURL(ctx context.Context, id string, oldURL string, newURL string) (bool, error) {
+	return false, m.err
+}
+
+func (m *mockReportServiceAchievementRepo) GetAchievementsByAttachmentScanStatus(ctx context.Context, status string, limit int) ([]modelmongo.Achievement, error) {
+	return nil, m.err
+}
+
+func (m *mockReportServiceAchievementRepo) UpdateAttachmentScanResult(ctx context.Context, id string, fileURL string, status string, signature string, newURL string) (bool, error) {
+	return false, m.err
+}
+
+func (m *mockReportServiceAchievementRepo) RemoveAttachment(ctx context.Context, id string, attachmentID string) (*modelmongo.Achievement, error) {
+	return nil, m.err
+}
+
+func (m *mockReportServiceAchievementRepo) ReplaceAttachment(ctx context.Context, id string, attachmentID string, attachment modelmongo.Attachment) (*modelmongo.Achievement, error) {
+	return nil, m.err
+}
+
+func (m *mockReportServiceAchievementRepo) CountAttachmentFileReferences(ctx context.Context, fileURL string) (int64, error) {
+	return 0, m.err
+}
+
+func (m *mockReportServiceAchievementRepo) ReserveAttachmentQuota(ctx context.Context, studentID string, size int64, expiresAt time.Time) (string, error) {
+	return "", m.err
+}
+
+func (m *mockReportServiceAchievementRepo) GetStudentReservedAttachmentQuota(ctx context.Context, studentID string, excludeID string, now time.Time) (int64, error) {
+	return 0, m.err
+}
+
+func (m *mockReportServiceAchievementRepo) ReleaseAttachmentQuota(ctx context.Context, id string) error {
+	return m.err
+}
+
+func (m *mockReportServiceAchievementRepo) GetStudentAttachmentUsage(ctx context.Context, studentID string) (int64, error) {
+	return 0, m.err
+}
+
+func (m *mockReportServiceAchievementRepo) GetAttachmentUsageByStudent(ctx context.Context) ([]repositorymongo.StudentAttachmentUsage, error) {
+	return m.studentUsages, m.err
+}
+
+func (m *mockReportServiceAchievementRepo) GetAttachmentStorageTotals(ctx context.Context) (*repositorymongo.AttachmentStorageTotals, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.storageTotals == nil {
+		return &repositorymongo.AttachmentStorageTotals{}, nil
+	}
+	return m.storageTotals, nil
+}
+
+func (m *mockReportServiceAchievementRepo) SearchAchievements(ctx context.Context, query string, achievementIDs []string, page, limit int) ([]repositorymongo.AchievementSearchResult, int, error) {
+	return nil, 0, m.err
+}
+
+func TestGetStorageUsageReport_Success(t *testing.T) {
+	ctx := setupTestContext()
+	t.Setenv("ATTACHMENT_QUOTA_MB", "1")
+
+	mockAchievementRepo := &mockReportServiceAchievementRepo{
+		studentUsages: []repositorymongo.StudentAttachmentUsage{
+			{StudentID: "student-1", Files: 3, Bytes: 2 * 1024 * 1024},
+			{StudentID: "student-2", Files: 1, Bytes: 512 * 1024},
+		},
+		storageTotals: &repositorymongo.AttachmentStorageTotals{Attachments: 5, UniqueFiles: 3, LogicalBytes: 3000, StoredBytes: 2000},
+	}
+
+	service := servicepostgre.NewReportService(
+		mockAchievementRepo,
+		&mockReportServiceAchievementRefRepo{},
+		&mockReportServiceStudentRepo{all: []modelpostgre.Student{
+			{ID: "student-1", StudentID: "2021001", FullName: "Budi"},
+		}},
+		&mockReportServiceUserRepo{roleName: "Admin"},
+		&mockReportServiceLecturerRepo{},
+		newTestCertificationConfig(),
+	)
+
+	result, err := service.GetStorageUsageReport(ctx, "user-id-1", "role-id-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	data := result["data"].(map[string]interface{})
+	if data["quota_bytes"] != int64(1024*1024) {
+		t.Errorf("Expected quota 1 MB, got %v", data["quota_bytes"])
+	}
+
+	totals := data["totals"].(map[string]interface{})
+	if totals["saved_bytes"] != int64(1000) {
+		t.Errorf("Expected 1000 saved bytes, got %v", totals["saved_bytes"])
+	}
+
+	students := data["students"].([]map[string]interface{})
+	if len(students) != 2 {
+		t.Fatalf("Expected 2 students, got %+v", students)
+	}
+	if students[0]["name"] != "Budi" || students[0]["student_id"] != "2021001" || students[0]["over_quota"] != true {
+		t.Errorf("Unexpected first student usage %+v", students[0])
+	}
+	if students[1]["over_quota"] != false || students[1]["quota_used_percent"] != 50.0 {
+		t.Errorf("Unexpected second student usage %+v", students[1])
+	}
+}
+
+func TestGetStorageUsageReport_NotAdmin(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := servicepostgre.NewReportService(
+		&mockReportServiceAchievementRepo{},
+		&mockReportServiceAchievementRefRepo{},
+		&mockReportServiceStudentRepo{},
+		&mockReportServiceUserRepo{roleName: "Mahasiswa"},
+		&mockReportServiceLecturerRepo{},
+		newTestCertificationConfig(),
+	)
+
+	_, err := service.GetStorageUsageReport(ctx, "user-id-1", "role-id-1")
+	if err == nil || !strings.Contains(err.Error(), "akses ditolak") {
+		t.Errorf("Expected akses ditolak error, got %v", err)
+	}
+}
+
+func (m *mockReportServiceAchievementRefRepo) GetAchievementReferencesByMongoIDs(ctx context.Context, mongoIDs []string) ([]modelpostgre.AchievementReference, error) {
+	m.mongoIDLookups++
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.byMongoIDs, nil
+}
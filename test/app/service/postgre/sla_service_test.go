@@ -0,0 +1,403 @@
+package service_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+)
+
+func newTestSLAConfig() modelpostgre.SLAConfig {
+	return modelpostgre.SLAConfig{
+		ReminderAfter: 72 * time.Hour,
+		EscalateAfter: 168 * time.Hour,
+		CheckInterval: time.Hour,
+	}
+}
+
+func TestRunSLACheck_SendsReminder(t *testing.T) {
+	ctx := setupTestContext()
+
+	submittedAt := time.Now().Add(-80 * time.Hour)
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		submittedBefore: []modelpostgre.AchievementReference{*newSubmittedRef(submittedAt)},
+	}
+	mockNotificationService := &mockNotificationService{}
+
+	service := servicepostgre.NewSLAService(mockAchievementRefRepo, &mockAchievementRepo{}, &mockStudentRepo{}, &mockUserRepo{}, mockNotificationService, newTestSLAConfig())
+
+	result, err := service.RunSLACheck(ctx)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Checked != 1 || result.Reminded != 1 || result.Escalated != 0 {
+		t.Errorf("Expected 1 checked and 1 reminded, got %+v", result)
+	}
+
+	if len(mockNotificationService.remindedRefIDs) != 1 || mockNotificationService.remindedRefIDs[0] != "ref-id-1" {
+		t.Errorf("Expected reminder for ref-id-1, got %v", mockNotificationService.remindedRefIDs)
+	}
+
+	if len(mockAchievementRefRepo.recordedHistory) != 1 || mockAchievementRefRepo.recordedHistory[0].Action != modelpostgre.AchievementHistoryActionSLAReminder {
+		t.Errorf("Expected sla_reminder history entry, got %+v", mockAchievementRefRepo.recordedHistory)
+	}
+}
+
+func TestRunSLACheck_ReminderAlreadySent(t *testing.T) {
+	ctx := setupTestContext()
+
+	submittedAt := time.Now().Add(-80 * time.Hour)
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		submittedBefore: []modelpostgre.AchievementReference{*newSubmittedRef(submittedAt)},
+		history: []modelpostgre.AchievementHistory{
+			{Action: modelpostgre.AchievementHistoryActionSLAReminder, CreatedAt: submittedAt.Add(73 * time.Hour)},
+		},
+	}
+	mockNotificationService := &mockNotificationService{}
+
+	service := servicepostgre.NewSLAService(mockAchievementRefRepo, &mockAchievementRepo{}, &mockStudentRepo{}, &mockUserRepo{}, mockNotificationService, newTestSLAConfig())
+
+	result, err := service.RunSLACheck(ctx)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Reminded != 0 || len(mockNotificationService.remindedRefIDs) != 0 {
+		t.Errorf("Expected no new reminder, got %+v and %v", result, mockNotificationService.remindedRefIDs)
+	}
+}
+
+func TestRunSLACheck_ReminderClaimedByAnotherReplica(t *testing.T) {
+	ctx := setupTestContext()
+
+	submittedAt := time.Now().Add(-80 * time.Hour)
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		submittedBefore: []modelpostgre.AchievementReference{*newSubmittedRef(submittedAt)},
+	}
+	mockNotificationService := &mockNotificationService{}
+
+	// replika lain sudah mengklaim pengingat tetapi riwayatnya belum tercatat
+	if _, err := mockAchievementRefRepo.ClaimSLANotification(ctx, "ref-id-1", submittedAt, modelpostgre.AchievementHistoryActionSLAReminder); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	service := servicepostgre.NewSLAService(mockAchievementRefRepo, &mockAchievementRepo{}, &mockStudentRepo{}, &mockUserRepo{}, mockNotificationService, newTestSLAConfig())
+
+	result, err := service.RunSLACheck(ctx)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Reminded != 0 || len(mockNotificationService.remindedRefIDs) != 0 {
+		t.Errorf("Expected no duplicate reminder, got %+v and %v", result, mockNotificationService.remindedRefIDs)
+	}
+
+	if len(mockAchievementRefRepo.recordedHistory) != 0 {
+		t.Errorf("Expected no history entry, got %+v", mockAchievementRefRepo.recordedHistory)
+	}
+}
+
+func TestRunSLACheck_ReleasesClaimWhenReminderFails(t *testing.T) {
+	ctx := setupTestContext()
+
+	submittedAt := time.Now().Add(-80 * time.Hour)
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		submittedBefore: []modelpostgre.AchievementReference{*newSubmittedRef(submittedAt)},
+	}
+	mockNotificationService := &mockNotificationService{err: errors.New("gagal mengirim")}
+
+	service := servicepostgre.NewSLAService(mockAchievementRefRepo, &mockAchievementRepo{}, &mockStudentRepo{}, &mockUserRepo{}, mockNotificationService, newTestSLAConfig())
+
+	result, err := service.RunSLACheck(ctx)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Reminded != 0 {
+		t.Errorf("Expected no reminder counted, got %+v", result)
+	}
+
+	if len(mockAchievementRefRepo.releasedSLA) != 1 {
+		t.Fatalf("Expected claim to be released, got %v", mockAchievementRefRepo.releasedSLA)
+	}
+
+	claimed, _ := mockAchievementRefRepo.ClaimSLANotification(ctx, "ref-id-1", submittedAt, modelpostgre.AchievementHistoryActionSLAReminder)
+	if !claimed {
+		t.Error("Expected reminder to be claimable again on the next check")
+	}
+}
+
+func TestRunSLACheck_ReminderResetAfterResubmit(t *testing.T) {
+	ctx := setupTestContext()
+
+	submittedAt := time.Now().Add(-80 * time.Hour)
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		submittedBefore: []modelpostgre.AchievementReference{*newSubmittedRef(submittedAt)},
+		history: []modelpostgre.AchievementHistory{
+			{Action: modelpostgre.AchievementHistoryActionSLAReminder, CreatedAt: submittedAt.Add(-24 * time.Hour)},
+		},
+	}
+	mockNotificationService := &mockNotificationService{}
+
+	service := servicepostgre.NewSLAService(mockAchievementRefRepo, &mockAchievementRepo{}, &mockStudentRepo{}, &mockUserRepo{}, mockNotificationService, newTestSLAConfig())
+
+	result, err := service.RunSLACheck(ctx)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Reminded != 1 {
+		t.Errorf("Expected reminder for resubmitted achievement, got %+v", result)
+	}
+}
+
+func TestRunSLACheck_EscalatesToAdmins(t *testing.T) {
+	ctx := setupTestContext()
+
+	submittedAt := time.Now().Add(-200 * time.Hour)
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		submittedBefore: []modelpostgre.AchievementReference{*newSubmittedRef(submittedAt)},
+		history: []modelpostgre.AchievementHistory{
+			{Action: modelpostgre.AchievementHistoryActionSLAReminder, CreatedAt: submittedAt.Add(73 * time.Hour)},
+		},
+	}
+	mockNotificationService := &mockNotificationService{}
+	mockUserRepo := &mockUserRepo{userIDsByRole: []string{"admin-user-id-1", "admin-user-id-2"}}
+
+	service := servicepostgre.NewSLAService(mockAchievementRefRepo, &mockAchievementRepo{}, &mockStudentRepo{}, mockUserRepo, mockNotificationService, newTestSLAConfig())
+
+	result, err := service.RunSLACheck(ctx)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Escalated != 1 || result.Reminded != 0 {
+		t.Errorf("Expected 1 escalated, got %+v", result)
+	}
+
+	recipients := mockNotificationService.escalations["ref-id-1"]
+	if len(recipients) != 2 || recipients[0] != "admin-user-id-1" {
+		t.Errorf("Expected escalation to admins, got %v", recipients)
+	}
+
+	if len(mockAchievementRefRepo.recordedHistory) != 1 || mockAchievementRefRepo.recordedHistory[0].Action != modelpostgre.AchievementHistoryActionSLAEscalated {
+		t.Errorf("Expected sla_escalated history entry, got %+v", mockAchievementRefRepo.recordedHistory)
+	}
+}
+
+func TestRunSLACheck_EscalatesToFallbackVerifier(t *testing.T) {
+	ctx := setupTestContext()
+
+	submittedAt := time.Now().Add(-200 * time.Hour)
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		submittedBefore: []modelpostgre.AchievementReference{*newSubmittedRef(submittedAt)},
+	}
+	mockNotificationService := &mockNotificationService{}
+	mockUserRepo := &mockUserRepo{
+		lecturerByID:  &modelpostgre.Lecturer{ID: "lecturer-id-9", UserID: "lecturer-user-id-9"},
+		userIDsByRole: []string{"admin-user-id-1"},
+	}
+
+	config := newTestSLAConfig()
+	config.FallbackVerifierID = "lecturer-id-9"
+	service := servicepostgre.NewSLAService(mockAchievementRefRepo, &mockAchievementRepo{}, &mockStudentRepo{}, mockUserRepo, mockNotificationService, config)
+
+	_, err := service.RunSLACheck(ctx)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	recipients := mockNotificationService.escalations["ref-id-1"]
+	if len(recipients) != 1 || recipients[0] != "lecturer-user-id-9" {
+		t.Errorf("Expected escalation to fallback verifier, got %v", recipients)
+	}
+}
+
+func TestRunSLACheck_AlreadyEscalated(t *testing.T) {
+	ctx := setupTestContext()
+
+	submittedAt := time.Now().Add(-200 * time.Hour)
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		submittedBefore: []modelpostgre.AchievementReference{*newSubmittedRef(submittedAt)},
+		history: []modelpostgre.AchievementHistory{
+			{Action: modelpostgre.AchievementHistoryActionSLAEscalated, CreatedAt: submittedAt.Add(169 * time.Hour)},
+		},
+	}
+	mockNotificationService := &mockNotificationService{}
+
+	service := servicepostgre.NewSLAService(mockAchievementRefRepo, &mockAchievementRepo{}, &mockStudentRepo{}, &mockUserRepo{userIDsByRole: []string{"admin-user-id-1"}}, mockNotificationService, newTestSLAConfig())
+
+	result, err := service.RunSLACheck(ctx)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Escalated != 0 || len(mockNotificationService.escalations) != 0 {
+		t.Errorf("Expected no new escalation, got %+v", result)
+	}
+}
+
+func TestGetOverdueAchievements_Success(t *testing.T) {
+	ctx := setupTestContext()
+
+	reminded := time.Now().Add(-100 * time.Hour)
+	escalated := time.Now().Add(-200 * time.Hour)
+	remindedRef := newSubmittedRef(reminded)
+	escalatedRef := newSubmittedRef(escalated)
+	escalatedRef.ID = "ref-id-2"
+
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		submittedBefore: []modelpostgre.AchievementReference{*escalatedRef, *remindedRef},
+	}
+	mockStudentRepo := &mockStudentRepo{
+		byID: &modelpostgre.Student{
+			ID:        "550e8400-e29b-41d4-a716-446655440000",
+			AdvisorID: "lecturer-id-1",
+			FullName:  "Test Student",
+		},
+	}
+	mockUserRepo := &mockUserRepo{
+		roleName:     "Admin",
+		lecturerByID: &modelpostgre.Lecturer{ID: "lecturer-id-1", UserID: "lecturer-user-id-1"},
+		byID:         &modelpostgre.User{ID: "lecturer-user-id-1", FullName: "Test Lecturer"},
+	}
+
+	service := servicepostgre.NewSLAService(mockAchievementRefRepo, &mockAchievementRepo{}, mockStudentRepo, mockUserRepo, &mockNotificationService{}, newTestSLAConfig())
+
+	result, err := service.GetOverdueAchievements(ctx, "admin-user-id-1", "role-id-1")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Data.Total != 2 {
+		t.Fatalf("Expected 2 overdue achievements, got %d", result.Data.Total)
+	}
+
+	first := result.Data.Achievements[0]
+	if first.Stage != modelpostgre.SLAStageEscalated || first.AgeDays != 8 {
+		t.Errorf("Expected escalated stage with age 8 days, got %s and %d", first.Stage, first.AgeDays)
+	}
+
+	second := result.Data.Achievements[1]
+	if second.Stage != modelpostgre.SLAStageReminder || second.AgeHours != 100 {
+		t.Errorf("Expected reminder stage with age 100 hours, got %s and %d", second.Stage, second.AgeHours)
+	}
+
+	if first.StudentName != "Test Student" || first.AdvisorName != "Test Lecturer" {
+		t.Errorf("Expected student and advisor names, got %s and %s", first.StudentName, first.AdvisorName)
+	}
+
+	if result.Data.ReminderAfterHours != 72 || result.Data.EscalateAfterHours != 168 {
+		t.Errorf("Expected SLA thresholds 72 and 168 hours, got %d and %d", result.Data.ReminderAfterHours, result.Data.EscalateAfterHours)
+	}
+}
+
+func TestGetOverdueAchievements_NotAdmin(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := servicepostgre.NewSLAService(&mockAchievementRefRepo{}, &mockAchievementRepo{}, &mockStudentRepo{}, &mockUserRepo{roleName: "Dosen Wali"}, &mockNotificationService{}, newTestSLAConfig())
+
+	_, err := service.GetOverdueAchievements(ctx, "user-id-1", "role-id-1")
+
+	if err == nil || !strings.Contains(err.Error(), "akses ditolak") {
+		t.Errorf("Expected access denied error, got %v", err)
+	}
+}
+
+func TestLoadSLAConfig_Defaults(t *testing.T) {
+	t.Setenv("SLA_REMINDER_HOURS", "")
+	t.Setenv("SLA_ESCALATION_HOURS", "invalid")
+	t.Setenv("SLA_CHECK_INTERVAL_MINUTES", "0")
+
+	config := servicepostgre.LoadSLAConfig()
+
+	if config.ReminderAfter != 72*time.Hour || config.EscalateAfter != 168*time.Hour {
+		t.Errorf("Expected default thresholds, got %v and %v", config.ReminderAfter, config.EscalateAfter)
+	}
+
+	if config.CheckInterval != 0 {
+		t.Errorf("Expected scheduler disabled with interval 0, got %v", config.CheckInterval)
+	}
+}
+
+func newFallbackRejectService(history []modelpostgre.AchievementHistory) servicepostgre.IAchievementService {
+	submittedAt := time.Now().Add(-200 * time.Hour)
+	mockAchievementRefRepo := &mockAchievementRefRepo{
+		byMongoID: newSubmittedRef(submittedAt),
+		byID: &modelpostgre.AchievementReference{
+			ID:     "ref-id-1",
+			Status: modelpostgre.AchievementStatusRejected,
+		},
+		history: history,
+	}
+	for i := range history {
+		history[i].CreatedAt = submittedAt.Add(169 * time.Hour)
+	}
+	slaConfig := newTestSLAConfig()
+	slaConfig.FallbackVerifierID = "lecturer-id-9"
+
+	return servicepostgre.NewAchievementService(
+		&mockAchievementRepo{},
+		mockAchievementRefRepo,
+		&mockUserRepo{
+			roleName:         "Dosen Wali",
+			lecturerByUserID: &modelpostgre.Lecturer{ID: "lecturer-id-9", UserID: "lecturer-user-id-9"},
+		},
+		&mockStudentRepo{
+			byID: &modelpostgre.Student{ID: "550e8400-e29b-41d4-a716-446655440000", AdvisorID: "lecturer-id-1"},
+		},
+		&mockNotificationService{},
+		&mockPointRubricService{},
+		newTestAchievementTypeService(),
+		&mockFileStorage{},
+		testAttachmentConfig(),
+		slaConfig,
+		newTestTrashConfig(),
+		newTestCertificationConfig(),
+	)
+}
+
+func TestRejectAchievement_FallbackVerifierAfterEscalation(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := newFallbackRejectService([]modelpostgre.AchievementHistory{
+		{Action: modelpostgre.AchievementHistoryActionSLAEscalated},
+	})
+
+	req := modelpostgre.RejectAchievementRequest{RejectionNote: "Data tidak lengkap"}
+	result, err := service.RejectAchievement(ctx, "lecturer-user-id-9", "role-id-1", "mongo-id-1", req, modelpostgre.AchievementVersion{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Data.Status != modelpostgre.AchievementStatusRejected {
+		t.Errorf("Expected status rejected, got %s", result.Data.Status)
+	}
+}
+
+func TestRejectAchievement_FallbackVerifierBeforeEscalation(t *testing.T) {
+	ctx := setupTestContext()
+
+	service := newFallbackRejectService(nil)
+
+	req := modelpostgre.RejectAchievementRequest{RejectionNote: "Data tidak lengkap"}
+	_, err := service.RejectAchievement(ctx, "lecturer-user-id-9", "role-id-1", "mongo-id-1", req, modelpostgre.AchievementVersion{})
+
+	if err == nil || !strings.Contains(err.Error(), "akses ditolak") {
+		t.Errorf("Expected access denied error, got %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package service_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	repositorystorage "sistem-pelaporan-prestasi-mahasiswa/app/repository/storage"
+	servicepostgre "sistem-pelaporan-prestasi-mahasiswa/app/service/postgre"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// storageMigrationRepo menyimpan dokumen di memori supaya URL attachment yang diganti bisa diperiksa
+type storageMigrationRepo struct {
+	mockAchievementRepo
+	docs []*modelmongo.Achievement
+}
+
+func (m *storageMigrationRepo) GetAchievementsByAttachmentURLPrefix(ctx context.Context, prefix string) ([]modelmongo.Achievement, error) {
+	var result []modelmongo.Achievement
+	for _, doc := range m.docs {
+		for _, attachment := range doc.Attachments {
+			if strings.HasPrefix(attachment.FileURL, prefix) {
+				result = append(result, *doc)
+				break
+			}
+		}
+	}
+	return result, nil
+}
+
+func (m *storageMigrationRepo) UpdateAttachmentFileURL(ctx context.Context, id string, oldURL string, newURL string) (bool, error) {
+	for _, doc := range m.docs {
+		if doc.ID.Hex() != id {
+			continue
+		}
+		for i := range doc.Attachments {
+			if doc.Attachments[i].FileURL == oldURL {
+				doc.Attachments[i].FileURL = newURL
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (m *storageMigrationRepo) CountAttachmentFileReferences(ctx context.Context, fileURL string) (int64, error) {
+	var count int64
+	for _, doc := range m.docs {
+		for _, attachment := range doc.Attachments {
+			if attachment.FileURL == fileURL {
+				count++
+				break
+			}
+		}
+	}
+	return count, nil
+}
+
+func newStorageMigrationFixture(t *testing.T) (*storageMigrationRepo, repositorystorage.IFileStorage, repositorystorage.IFileStorage) {
+	ctx := context.Background()
+	source := repositorystorage.NewLocalFileStorage(t.TempDir(), "/uploads/")
+	target := repositorystorage.NewLocalFileStorage(t.TempDir(), "https://files.example.ac.id/prestasi/")
+
+	if err := source.Put(ctx, "1-sertifikat.pdf", strings.NewReader("%PDF-1.4 sertifikat"), 19, "application/pdf"); err != nil {
+		t.Fatalf("Failed to prepare source file: %v", err)
+	}
+
+	repo := &storageMigrationRepo{docs: []*modelmongo.Achievement{
+		{
+			ID: primitive.NewObjectID(),
+			Attachments: []modelmongo.Attachment{
+				{FileName: "sertifikat.pdf", FileURL: "/uploads/1-sertifikat.pdf", FileType: "application/pdf"},
+				{FileName: "hilang.pdf", FileURL: "/uploads/2-hilang.pdf", FileType: "application/pdf"},
+				{FileName: "lain.pdf", FileURL: "https://cdn.example.com/lain.pdf", FileType: "application/pdf"},
+			},
+		},
+	}}
+	return repo, source, target
+}
+
+func TestMigrateAttachments_MovesFilesAndRewritesURL(t *testing.T) {
+	ctx := context.Background()
+	repo, source, target := newStorageMigrationFixture(t)
+	service := servicepostgre.NewStorageMigrationService(repo, source, target)
+
+	result, err := service.MigrateAttachments(ctx, false, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Total != 2 || result.Migrated != 1 || result.Missing != 1 || result.Failed != 0 {
+		t.Fatalf("Unexpected summary: %+v", result)
+	}
+
+	attachments := repo.docs[0].Attachments
+	if attachments[0].FileURL != "https://files.example.ac.id/prestasi/1-sertifikat.pdf" {
+		t.Errorf("Expected migrated URL rewritten, got %s", attachments[0].FileURL)
+	}
+	if attachments[1].FileURL != "/uploads/2-hilang.pdf" || attachments[2].FileURL != "https://cdn.example.com/lain.pdf" {
+		t.Errorf("Expected missing and foreign attachments untouched, got %+v", attachments)
+	}
+
+	body, _, err := target.Get(ctx, "1-sertifikat.pdf")
+	if err != nil {
+		t.Fatalf("Expected file in target storage, got %v", err)
+	}
+	data, _ := io.ReadAll(body)
+	body.Close()
+	if string(data) != "%PDF-1.4 sertifikat" {
+		t.Errorf("Unexpected target content %q", data)
+	}
+
+	if _, err := source.Stat(ctx, "1-sertifikat.pdf"); err != repositorystorage.ErrFileNotFound {
+		t.Errorf("Expected source file deleted, got %v", err)
+	}
+
+	// dijalankan ulang tidak ada lagi yang dipindah
+	again, err := service.MigrateAttachments(ctx, false, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if again.Migrated != 0 || again.Total != 1 || again.Missing != 1 {
+		t.Errorf("Expected rerun to only report missing file, got %+v", again)
+	}
+}
+
+func TestMigrateAttachments_DryRunChangesNothing(t *testing.T) {
+	ctx := context.Background()
+	repo, source, target := newStorageMigrationFixture(t)
+	service := servicepostgre.NewStorageMigrationService(repo, source, target)
+
+	result, err := service.MigrateAttachments(ctx, true, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Items[0].Status != modelmongo.StorageMigrationStatusPending || result.Items[0].Size != 19 {
+		t.Errorf("Expected pending item with size, got %+v", result.Items[0])
+	}
+	if repo.docs[0].Attachments[0].FileURL != "/uploads/1-sertifikat.pdf" {
+		t.Errorf("Expected URL unchanged on dry run")
+	}
+	if _, err := target.Stat(ctx, "1-sertifikat.pdf"); err != repositorystorage.ErrFileNotFound {
+		t.Errorf("Expected nothing copied on dry run, got %v", err)
+	}
+	if _, err := source.Stat(ctx, "1-sertifikat.pdf"); err != nil {
+		t.Errorf("Expected source kept on dry run, got %v", err)
+	}
+}
+
+func TestMigrateAttachments_RejectsSameStorage(t *testing.T) {
+	source := repositorystorage.NewLocalFileStorage(t.TempDir(), "/uploads/")
+	service := servicepostgre.NewStorageMigrationService(&storageMigrationRepo{}, source, source)
+
+	if _, err := service.MigrateAttachments(context.Background(), false, true); err == nil {
+		t.Errorf("Expected error when source and target are the same storage")
+	}
+}
+
+func TestMigrateAttachments_MovesPreviewNextToFile(t *testing.T) {
+	ctx := context.Background()
+	repo, source, target := newStorageMigrationFixture(t)
+	repo.docs[0].Attachments[0].HasPreview = true
+	if err := source.Put(ctx, "1-sertifikat.pdf.preview.jpg", strings.NewReader("jpeg"), 4, "image/jpeg"); err != nil {
+		t.Fatalf("Failed to prepare preview: %v", err)
+	}
+	service := servicepostgre.NewStorageMigrationService(repo, source, target)
+
+	if _, err := service.MigrateAttachments(ctx, false, true); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := target.Stat(ctx, "1-sertifikat.pdf.preview.jpg"); err != nil {
+		t.Errorf("Expected preview copied to target, got %v", err)
+	}
+	if _, err := source.Stat(ctx, "1-sertifikat.pdf.preview.jpg"); err == nil {
+		t.Errorf("Expected preview removed from source")
+	}
+}
+
+func TestMigrateAttachments_DeletesSharedSourceAfterLastReference(t *testing.T) {
+	ctx := context.Background()
+	repo, source, target := newStorageMigrationFixture(t)
+	repo.docs = append(repo.docs, &modelmongo.Achievement{
+		ID: primitive.NewObjectID(),
+		Attachments: []modelmongo.Attachment{
+			{FileName: "salinan.pdf", FileURL: "/uploads/1-sertifikat.pdf", FileType: "application/pdf"},
+		},
+	})
+	service := servicepostgre.NewStorageMigrationService(repo, source, target)
+
+	result, err := service.MigrateAttachments(ctx, false, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if result.Migrated != 2 || result.Missing != 1 {
+		t.Fatalf("Expected both references to the shared file migrated, got %+v", result)
+	}
+	for _, doc := range repo.docs {
+		if doc.Attachments[0].FileURL != "https://files.example.ac.id/prestasi/1-sertifikat.pdf" {
+			t.Errorf("Expected URL rewritten, got %s", doc.Attachments[0].FileURL)
+		}
+	}
+	if _, err := source.Stat(ctx, "1-sertifikat.pdf"); err == nil {
+		t.Errorf("Expected shared source file deleted after the last reference moved")
+	}
+}
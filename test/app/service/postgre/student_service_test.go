@@ -168,6 +168,10 @@ func (m *mockStudentServiceUserRepo) GetLecturerByID(ctx context.Context, id str
 	return m.lecturerByID, nil
 }
 
+func (m *mockStudentServiceUserRepo) GetActiveUserIDsByRoleName(ctx context.Context, roleName string) ([]string, error) {
+	return nil, m.err
+}
+
 type mockStudentServiceLecturerRepo struct {
 	byID *modelpostgre.Lecturer
 	err  error
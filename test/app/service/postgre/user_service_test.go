@@ -108,6 +108,10 @@ func (m *mockUserServiceUserRepo) GetLecturerByID(ctx context.Context, id string
 	return nil, m.err
 }
 
+func (m *mockUserServiceUserRepo) GetActiveUserIDsByRoleName(ctx context.Context, roleName string) ([]string, error) {
+	return nil, m.err
+}
+
 type mockUserServiceStudentRepo struct {
 	byID        *modelpostgre.Student
 	byUserID    *modelpostgre.Student
@@ -0,0 +1,391 @@
+package route_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	routepostgre "sistem-pelaporan-prestasi-mahasiswa/route/postgre"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestDownloadAttachmentRoute_StreamsFile(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:read").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+	mockService := &mockAchievementService{
+		downloadResp: &modelmongo.AttachmentDownload{
+			FileName:    "sertifikat lomba.pdf",
+			ContentType: "application/pdf",
+			Size:        8,
+			Body:        io.NopCloser(strings.NewReader("%PDF-1.4")),
+		},
+	}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, db)
+
+	req := createRequestWithToken("GET", "/api/v1/achievements/507f1f77bcf86cd799439011/attachments/1-sertifikat%20lomba.pdf", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "%PDF-1.4" {
+		t.Errorf("Expected file content, got %q", body)
+	}
+	if mockService.downloadKey != "1-sertifikat lomba.pdf" {
+		t.Errorf("Expected unescaped key, got %q", mockService.downloadKey)
+	}
+	if resp.Header.Get("Content-Type") != "application/pdf" || !strings.Contains(resp.Header.Get("Content-Disposition"), "sertifikat lomba.pdf") {
+		t.Errorf("Unexpected headers: %v", resp.Header)
+	}
+}
+
+func TestDownloadAttachmentRoute_Forbidden(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:read").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+	mockService := &mockAchievementService{
+		downloadErr: errors.New("akses ditolak. Anda hanya dapat melihat prestasi milik Anda sendiri"),
+	}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, db)
+
+	req := createRequestWithToken("GET", "/api/v1/achievements/507f1f77bcf86cd799439011/attachments/1-a.pdf", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusForbidden)
+}
+
+func TestDownloadAttachmentRoute_RequiresLogin(t *testing.T) {
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, &mockAchievementService{}, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/achievements/507f1f77bcf86cd799439011/attachments/1-a.pdf", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusUnauthorized)
+}
+
+func TestDownloadSignedAttachmentRoute_NoLoginNeeded(t *testing.T) {
+	mockService := &mockAchievementService{
+		downloadResp: &modelmongo.AttachmentDownload{
+			FileName:    "a.png",
+			ContentType: "image/png",
+			Size:        3,
+			Body:        io.NopCloser(strings.NewReader("png")),
+		},
+	}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/attachments/507f1f77bcf86cd799439011/1-a.png?expires=1700000000&signature=abc", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+	if mockService.downloadSignature != "abc" {
+		t.Errorf("Expected signature passed to service, got %q", mockService.downloadSignature)
+	}
+}
+
+func TestDownloadSignedAttachmentRoute_InvalidExpires(t *testing.T) {
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, &mockAchievementService{}, nil)
+
+	req := httptest.NewRequest("GET", "/api/v1/attachments/507f1f77bcf86cd799439011/1-a.png?expires=besok&signature=abc", nil)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusForbidden)
+}
+
+func createUploadRequest(t *testing.T, fileName string, content []byte, token string) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", fileName)
+	if err != nil {
+		t.Fatalf("Failed to create form file: %v", err)
+	}
+	part.Write(content)
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/api/v1/achievements/507f1f77bcf86cd799439011/attachments", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
+func TestUploadAttachmentRoute_SanitizesNameAndSniffsType(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:update").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+	mockService := &mockAchievementService{uploadFileResp: &modelmongo.Attachment{FileName: "foto_juara.png"}}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, db)
+
+	resp, err := app.Test(createUploadRequest(t, "foto <juara>.PNG", []byte("\x89PNG\r\n\x1a\n0000IHDR"), token))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+	if mockService.uploadFileName != "foto_juara.png" || mockService.uploadFileType != "image/png" {
+		t.Errorf("Unexpected file passed to service: %q %q", mockService.uploadFileName, mockService.uploadFileType)
+	}
+}
+
+func TestUploadAttachmentRoute_RejectsRenamedExecutable(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:update").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+	mockService := &mockAchievementService{}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, db)
+
+	resp, err := app.Test(createUploadRequest(t, "sertifikat.pdf", []byte("MZ\x90\x00\x03\x00\x00\x00"), token))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusBadRequest)
+	if mockService.uploadFileName != "" {
+		t.Errorf("Expected service not called, got %q", mockService.uploadFileName)
+	}
+}
+
+func TestUploadAttachmentRoute_TooLargeImage(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:update").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+	app := fiber.New(fiber.Config{BodyLimit: 10 * 1024 * 1024})
+	routepostgre.AchievementRoutes(app, &mockAchievementService{}, db)
+
+	content := append([]byte("\xff\xd8\xff\xe0"), make([]byte, 5*1024*1024)...)
+	resp, err := app.Test(createUploadRequest(t, "foto.jpg", content, token), -1)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusRequestEntityTooLarge)
+}
+
+func TestUploadAttachmentRoute_QuotaExceeded(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:update").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+	mockService := &mockAchievementService{uploadFileErr: &modelmongo.AttachmentQuotaError{Message: "kuota storage attachment terlampaui", Used: 90, Quota: 100}}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, db)
+
+	resp, err := app.Test(createUploadRequest(t, "sertifikat.pdf", []byte("%PDF-1.4\n%%EOF\n"), token))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusRequestEntityTooLarge)
+}
+
+func createReplaceRequest(t *testing.T, attachmentID string, fileName string, content []byte, token string) *http.Request {
+	req := createUploadRequest(t, fileName, content, token)
+	req.Method = "PUT"
+	req.URL.Path += "/" + attachmentID
+	req.RequestURI = req.URL.RequestURI()
+	return req
+}
+
+func TestDeleteAttachmentRoute_StatusCodes(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+	}{
+		{"success", nil, http.StatusOK},
+		{"not owner", errors.New("akses ditolak. Anda hanya dapat mengubah attachment prestasi milik Anda sendiri"), http.StatusForbidden},
+		{"not found", errors.New("attachment tidak ditemukan"), http.StatusNotFound},
+		{"submitted", errors.New("attachment hanya dapat dihapus jika status prestasi adalah draft atau rejected"), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock := setupTestDBForRoute(t)
+			defer db.Close()
+
+			userID := "550e8400-e29b-41d4-a716-446655440000"
+			token, err := createTestToken(userID, "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+			if err != nil {
+				t.Fatalf("Failed to create token: %v", err)
+			}
+
+			mock.ExpectQuery(getPermissionQuery()).
+				WithArgs(userID, "achievement:update").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+			mockService := &mockAchievementService{
+				attachmentListResp:  &modelmongo.AttachmentListResponse{Status: "success", Data: []modelmongo.Attachment{}},
+				attachmentChangeErr: tt.err,
+			}
+
+			app := setupTestApp()
+			routepostgre.AchievementRoutes(app, mockService, db)
+
+			req := createRequestWithToken("DELETE", "/api/v1/achievements/507f1f77bcf86cd799439011/attachments/att-1", nil, token)
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+
+			assertStatusCode(t, resp, tt.expectedStatus)
+			if mockService.changedAttachmentID != "att-1" {
+				t.Errorf("Expected attachment ID passed to service, got %q", mockService.changedAttachmentID)
+			}
+		})
+	}
+}
+
+func TestReplaceAttachmentRoute_ValidatesAndReplaces(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:update").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+	mockService := &mockAchievementService{uploadFileResp: &modelmongo.Attachment{ID: "att-1", FileName: "revisi.pdf"}}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, db)
+
+	resp, err := app.Test(createReplaceRequest(t, "att-1", "revisi.PDF", []byte("%PDF-1.4\n%%EOF\n"), token))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+	if mockService.changedAttachmentID != "att-1" || mockService.uploadFileName != "revisi.pdf" || mockService.uploadFileType != "application/pdf" {
+		t.Errorf("Unexpected replacement passed to service: %q %q %q", mockService.changedAttachmentID, mockService.uploadFileName, mockService.uploadFileType)
+	}
+}
+
+func TestReplaceAttachmentRoute_RejectsRenamedExecutable(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:update").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+	mockService := &mockAchievementService{}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, db)
+
+	resp, err := app.Test(createReplaceRequest(t, "att-1", "revisi.pdf", []byte("MZ\x90\x00"), token))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusBadRequest)
+	if mockService.changedAttachmentID != "" {
+		t.Errorf("Expected service not called")
+	}
+}
@@ -2,6 +2,9 @@ package route_test
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -9,6 +12,7 @@ import (
 
 	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
 	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
 	routepostgre "sistem-pelaporan-prestasi-mahasiswa/route/postgre"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -38,8 +42,33 @@ type mockAchievementService struct {
 	statsErr            error
 	uploadFileResp      *modelmongo.Attachment
 	uploadFileErr       error
+	uploadFileName      string
+	uploadFileType      string
 	historyResp         map[string]interface{}
 	historyErr          error
+	confirmResp         *modelmongo.ConfirmTeamParticipationResponse
+	confirmErr          error
+	withdrawResponse    *modelpostgre.UpdateAchievementReferenceResponse
+	withdrawErr         error
+	trashResp           *modelpostgre.GetDeletedAchievementsResponse
+	trashErr            error
+	restoreResponse     *modelpostgre.UpdateAchievementReferenceResponse
+	restoreErr          error
+	purgeResp           *modelpostgre.TrashPurgeResponse
+	purgeErr            error
+	purgeDryRun         *bool
+	searchResp          map[string]interface{}
+	searchErr           error
+	searchQuery         string
+	listFilter          modelpostgre.AchievementListFilter
+	expectedVersion     *modelpostgre.AchievementVersion
+	downloadResp        *modelmongo.AttachmentDownload
+	downloadErr         error
+	downloadKey         string
+	downloadSignature   string
+	attachmentListResp  *modelmongo.AttachmentListResponse
+	attachmentChangeErr error
+	changedAttachmentID string
 }
 
 func (m *mockAchievementService) CreateAchievement(ctx context.Context, userID string, roleID string, req modelmongo.CreateAchievementRequest) (*modelmongo.CreateAchievementResponse, error) {
@@ -56,14 +85,52 @@ func (m *mockAchievementService) SubmitAchievement(ctx context.Context, userID s
 	return m.submitResponse, nil
 }
 
-func (m *mockAchievementService) VerifyAchievement(ctx context.Context, userID string, roleID string, mongoID string) (*modelpostgre.VerifyAchievementResponse, error) {
+func (m *mockAchievementService) WithdrawAchievement(ctx context.Context, userID string, roleID string, mongoID string) (*modelpostgre.UpdateAchievementReferenceResponse, error) {
+	if m.withdrawErr != nil {
+		return nil, m.withdrawErr
+	}
+	return m.withdrawResponse, nil
+}
+
+func (m *mockAchievementService) GetDeletedAchievements(ctx context.Context, userID string, roleID string, page, limit int) (*modelpostgre.GetDeletedAchievementsResponse, error) {
+	return m.trashResp, m.trashErr
+}
+
+func (m *mockAchievementService) RestoreAchievement(ctx context.Context, userID string, roleID string, mongoID string) (*modelpostgre.UpdateAchievementReferenceResponse, error) {
+	return m.restoreResponse, m.restoreErr
+}
+
+func (m *mockAchievementService) PurgeDeletedAchievements(ctx context.Context, userID string, roleID string, dryRun bool) (*modelpostgre.TrashPurgeResponse, error) {
+	m.purgeDryRun = &dryRun
+	return m.purgeResp, m.purgeErr
+}
+
+func (m *mockAchievementService) RunTrashPurge(ctx context.Context, retentionDays int, dryRun bool) (*modelpostgre.TrashPurgeResult, error) {
+	return nil, m.purgeErr
+}
+
+func (m *mockAchievementService) SearchAchievements(ctx context.Context, userID string, roleID string, query string, page, limit int) (map[string]interface{}, error) {
+	m.searchQuery = query
+	return m.searchResp, m.searchErr
+}
+
+func (m *mockAchievementService) ConfirmTeamParticipation(ctx context.Context, userID string, roleID string, mongoID string) (*modelmongo.ConfirmTeamParticipationResponse, error) {
+	if m.confirmErr != nil {
+		return nil, m.confirmErr
+	}
+	return m.confirmResp, nil
+}
+
+func (m *mockAchievementService) VerifyAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelpostgre.VerifyAchievementRequest, expected modelpostgre.AchievementVersion) (*modelpostgre.VerifyAchievementResponse, error) {
+	m.expectedVersion = &expected
 	if m.verifyErr != nil {
 		return nil, m.verifyErr
 	}
 	return m.verifyResponse, nil
 }
 
-func (m *mockAchievementService) RejectAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelpostgre.RejectAchievementRequest) (*modelpostgre.RejectAchievementResponse, error) {
+func (m *mockAchievementService) RejectAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelpostgre.RejectAchievementRequest, expected modelpostgre.AchievementVersion) (*modelpostgre.RejectAchievementResponse, error) {
+	m.expectedVersion = &expected
 	if m.rejectErr != nil {
 		return nil, m.rejectErr
 	}
@@ -77,7 +144,8 @@ func (m *mockAchievementService) DeleteAchievement(ctx context.Context, userID s
 	return m.deleteResponse, nil
 }
 
-func (m *mockAchievementService) GetAchievements(ctx context.Context, userID string, roleID string, page, limit int, statusFilter string, achievementTypeFilter string, sortBy string, sortOrder string) (map[string]interface{}, error) {
+func (m *mockAchievementService) GetAchievements(ctx context.Context, userID string, roleID string, filter modelpostgre.AchievementListFilter) (map[string]interface{}, error) {
+	m.listFilter = filter
 	if m.getAchievementsErr != nil {
 		return nil, m.getAchievementsErr
 	}
@@ -98,7 +166,8 @@ func (m *mockAchievementService) GetAchievementByID(ctx context.Context, userID
 	return m.getByIDResp, nil
 }
 
-func (m *mockAchievementService) UpdateAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelmongo.UpdateAchievementRequest) (map[string]interface{}, error) {
+func (m *mockAchievementService) UpdateAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelmongo.UpdateAchievementRequest, expected modelpostgre.AchievementVersion) (map[string]interface{}, error) {
+	m.expectedVersion = &expected
 	if m.updateErr != nil {
 		return nil, m.updateErr
 	}
@@ -112,13 +181,57 @@ func (m *mockAchievementService) GetAchievementStats(ctx context.Context) (map[s
 	return m.statsResp, nil
 }
 
-func (m *mockAchievementService) UploadFile(ctx context.Context, userID string, roleID string, mongoID string, fileName string, fileURL string, fileType string) (*modelmongo.Attachment, error) {
+func (m *mockAchievementService) UploadFile(ctx context.Context, userID string, roleID string, mongoID string, fileName string, fileType string, size int64, content io.Reader) (*modelmongo.Attachment, error) {
+	m.uploadFileName = fileName
+	m.uploadFileType = fileType
 	if m.uploadFileErr != nil {
 		return nil, m.uploadFileErr
 	}
 	return m.uploadFileResp, nil
 }
 
+func (m *mockAchievementService) GetAttachmentFile(ctx context.Context, userID string, roleID string, mongoID string, key string) (*modelmongo.AttachmentDownload, error) {
+	m.downloadKey = key
+	if m.downloadErr != nil {
+		return nil, m.downloadErr
+	}
+	return m.downloadResp, nil
+}
+
+func (m *mockAchievementService) CreateAttachmentLink(ctx context.Context, userID string, roleID string, mongoID string, key string) (*modelmongo.AttachmentLinkResponse, error) {
+	if m.downloadErr != nil {
+		return nil, m.downloadErr
+	}
+	return &modelmongo.AttachmentLinkResponse{Status: "success", Data: modelmongo.AttachmentLink{URL: "/api/v1/attachments/" + mongoID + "/" + key + "?expires=1&signature=abc"}}, nil
+}
+
+func (m *mockAchievementService) GetSignedAttachmentFile(ctx context.Context, mongoID string, key string, expires int64, signature string) (*modelmongo.AttachmentDownload, error) {
+	m.downloadKey = key
+	m.downloadSignature = signature
+	if m.downloadErr != nil {
+		return nil, m.downloadErr
+	}
+	return m.downloadResp, nil
+}
+
+func (m *mockAchievementService) DeleteAttachment(ctx context.Context, userID string, roleID string, mongoID string, attachmentID string) (*modelmongo.AttachmentListResponse, error) {
+	m.changedAttachmentID = attachmentID
+	if m.attachmentChangeErr != nil {
+		return nil, m.attachmentChangeErr
+	}
+	return m.attachmentListResp, nil
+}
+
+func (m *mockAchievementService) ReplaceAttachment(ctx context.Context, userID string, roleID string, mongoID string, attachmentID string, fileName string, fileType string, size int64, content io.Reader) (*modelmongo.Attachment, error) {
+	m.changedAttachmentID = attachmentID
+	m.uploadFileName = fileName
+	m.uploadFileType = fileType
+	if m.attachmentChangeErr != nil {
+		return nil, m.attachmentChangeErr
+	}
+	return m.uploadFileResp, nil
+}
+
 func (m *mockAchievementService) GetAchievementHistory(ctx context.Context, userID string, roleID string, mongoID string) (map[string]interface{}, error) {
 	if m.historyErr != nil {
 		return nil, m.historyErr
@@ -216,6 +329,7 @@ func TestGetAchievementByIDRoute_Success(t *testing.T) {
 				"id":    mongoID,
 				"title": "Test Achievement",
 			},
+			"etag": `"2-1"`,
 		},
 	}
 
@@ -230,6 +344,10 @@ func TestGetAchievementByIDRoute_Success(t *testing.T) {
 
 	assertStatusCode(t, resp, http.StatusOK)
 
+	if etag := resp.Header.Get("ETag"); etag != `"2-1"` {
+		t.Errorf("Expected ETag header \"2-1\", got %q", etag)
+	}
+
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Unfulfilled expectations: %v", err)
 	}
@@ -278,7 +396,6 @@ func TestCreateAchievementRoute_Success(t *testing.T) {
 		AchievementType: "academic",
 		Title:           "Test Achievement",
 		Description:     "Test Description",
-		Points:          100,
 	}
 
 	req := createRequestWithToken("POST", "/api/v1/achievements", reqBody, token)
@@ -330,6 +447,64 @@ func TestCreateAchievementRoute_InvalidBody(t *testing.T) {
 	}
 }
 
+func TestCreateAchievementRoute_DetailValidationErrors(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:create").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+	mockService := &mockAchievementService{
+		createErr: &modelmongo.ValidationError{
+			Message: "data prestasi tidak valid",
+			Errors: []modelmongo.FieldError{
+				{Field: "details.competitionName", Message: "wajib diisi untuk tipe competition"},
+				{Field: "details.competitionLevel", Message: "harus salah satu dari: international, national, regional, local"},
+			},
+		},
+	}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, db)
+
+	reqBody := modelmongo.CreateAchievementRequest{
+		AchievementType: "competition",
+		Title:           "Juara Lomba",
+		Description:     "Test Description",
+	}
+
+	req := createRequestWithToken("POST", "/api/v1/achievements", reqBody, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusUnprocessableEntity)
+
+	var body struct {
+		Message string                  `json:"message"`
+		Errors  []modelmongo.FieldError `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(body.Errors) != 2 || body.Errors[0].Field != "details.competitionName" {
+		t.Errorf("Expected 2 field errors starting with details.competitionName, got %+v", body.Errors)
+	}
+
+	if body.Message != "data prestasi tidak valid" {
+		t.Errorf("Expected message 'data prestasi tidak valid', got '%s'", body.Message)
+	}
+}
+
 func TestSubmitAchievementRoute_Success(t *testing.T) {
 	db, mock := setupTestDBForRoute(t)
 	defer db.Close()
@@ -383,6 +558,176 @@ func TestSubmitAchievementRoute_Success(t *testing.T) {
 	}
 }
 
+func TestWithdrawAchievementRoute_Success(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "test@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"count"}).
+		AddRow(true)
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:update").
+		WillReturnRows(rows)
+
+	mongoID := primitive.NewObjectID().Hex()
+	mockService := &mockAchievementService{
+		withdrawResponse: &modelpostgre.UpdateAchievementReferenceResponse{
+			Status: "success",
+			Data: modelpostgre.AchievementReference{
+				ID:                 "ref-id-1",
+				StudentID:          "student-id-1",
+				MongoAchievementID: mongoID,
+				Status:             modelpostgre.AchievementStatusDraft,
+				CreatedAt:          time.Now(),
+				UpdatedAt:          time.Now(),
+			},
+		},
+	}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, db)
+
+	req := createRequestWithToken("POST", "/api/v1/achievements/"+mongoID+"/withdraw", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestWithdrawAchievementRoute_ReviewStarted(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "test@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"count"}).
+		AddRow(true)
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:update").
+		WillReturnRows(rows)
+
+	mockService := &mockAchievementService{
+		withdrawErr: errors.New("prestasi tidak dapat di-withdraw karena dosen wali sudah mulai mereview"),
+	}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, db)
+
+	req := createRequestWithToken("POST", "/api/v1/achievements/"+primitive.NewObjectID().Hex()+"/withdraw", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusConflict)
+}
+
+func TestConfirmTeamParticipationRoute_Success(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "test@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"count"}).
+		AddRow(true)
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:update").
+		WillReturnRows(rows)
+
+	mongoID := primitive.NewObjectID().Hex()
+	mockService := &mockAchievementService{
+		confirmResp: &modelmongo.ConfirmTeamParticipationResponse{
+			Status: "success",
+			Data: []modelmongo.TeamMember{
+				{StudentID: "student-id-1", Role: "Ketua", PointShare: 60, Confirmed: true},
+				{StudentID: "student-id-2", Role: "Anggota", PointShare: 40, Confirmed: true},
+			},
+		},
+	}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, db)
+
+	req := createRequestWithToken("POST", "/api/v1/achievements/"+mongoID+"/confirm", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestConfirmTeamParticipationRoute_NotMember(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "test@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"count"}).
+		AddRow(true)
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:update").
+		WillReturnRows(rows)
+
+	mockService := &mockAchievementService{
+		confirmErr: errors.New("akses ditolak. Anda bukan anggota tim prestasi ini"),
+	}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, db)
+
+	req := createRequestWithToken("POST", "/api/v1/achievements/"+primitive.NewObjectID().Hex()+"/confirm", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusForbidden)
+}
+
 func TestVerifyAchievementRoute_Success(t *testing.T) {
 	db, mock := setupTestDBForRoute(t)
 	defer db.Close()
@@ -425,6 +770,7 @@ func TestVerifyAchievementRoute_Success(t *testing.T) {
 	routepostgre.AchievementRoutes(app, mockService, db)
 
 	req := createRequestWithToken("POST", "/api/v1/achievements/"+mongoID+"/verify", nil, token)
+	req.Header.Set("If-Match", `"2-3"`)
 	resp, err := app.Test(req)
 	if err != nil {
 		t.Fatalf("Failed to make request: %v", err)
@@ -432,6 +778,10 @@ func TestVerifyAchievementRoute_Success(t *testing.T) {
 
 	assertStatusCode(t, resp, http.StatusOK)
 
+	if mockService.expectedVersion == nil || *mockService.expectedVersion != (modelpostgre.AchievementVersion{Achievement: 2, Reference: 3}) {
+		t.Errorf("Expected If-Match version passed to service, got %+v", mockService.expectedVersion)
+	}
+
 	if err := mock.ExpectationsWereMet(); err != nil {
 		t.Errorf("Unfulfilled expectations: %v", err)
 	}
@@ -483,6 +833,7 @@ func TestRejectAchievementRoute_Success(t *testing.T) {
 	}
 
 	req := createRequestWithToken("POST", "/api/v1/achievements/"+mongoID+"/reject", reqBody, token)
+	req.Header.Set("If-Match", `"1-2"`)
 	resp, err := app.Test(req)
 	if err != nil {
 		t.Fatalf("Failed to make request: %v", err)
@@ -586,3 +937,358 @@ func TestGetAchievementHistoryRoute_Success(t *testing.T) {
 		t.Errorf("Unfulfilled expectations: %v", err)
 	}
 }
+
+func TestGetDeletedAchievementsRoute_Success(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "admin@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"count"}).
+		AddRow(true)
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:delete").
+		WillReturnRows(rows)
+
+	mockService := &mockAchievementService{
+		trashResp: &modelpostgre.GetDeletedAchievementsResponse{
+			Status: "success",
+			Data:   []modelpostgre.DeletedAchievement{{Title: "Juara 1 Hackathon"}},
+		},
+	}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, db)
+
+	req := createRequestWithToken("GET", "/api/v1/achievements/trash?page=1&limit=10", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestRestoreAchievementRoute_NotInTrash(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "admin@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"count"}).
+		AddRow(true)
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:delete").
+		WillReturnRows(rows)
+
+	mockService := &mockAchievementService{
+		restoreErr: errors.New("prestasi tidak ditemukan di tempat sampah"),
+	}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, db)
+
+	req := createRequestWithToken("POST", "/api/v1/achievements/"+primitive.NewObjectID().Hex()+"/restore", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusNotFound)
+}
+
+func TestPurgeDeletedAchievementsRoute_DefaultsToDryRun(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "admin@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	rows := sqlmock.NewRows([]string{"count"}).
+		AddRow(true)
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:delete").
+		WillReturnRows(rows)
+
+	mockService := &mockAchievementService{
+		purgeResp: &modelpostgre.TrashPurgeResponse{Status: "success"},
+	}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, db)
+
+	req := createRequestWithToken("POST", "/api/v1/achievements/trash/purge", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+
+	if mockService.purgeDryRun == nil || !*mockService.purgeDryRun {
+		t.Errorf("Expected purge to default to dry run, got %v", mockService.purgeDryRun)
+	}
+}
+
+func TestSearchAchievementsRoute_Success(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "test@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mockService := &mockAchievementService{
+		searchResp: map[string]interface{}{
+			"status": "success",
+			"data":   []map[string]interface{}{},
+		},
+	}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, nil)
+
+	req := createRequestWithToken("GET", "/api/v1/achievements/search?q=lomba+robotik", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+
+	if mockService.searchQuery != "lomba robotik" {
+		t.Errorf("Expected query 'lomba robotik', got %q", mockService.searchQuery)
+	}
+}
+
+func TestSearchAchievementsRoute_EmptyQuery(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "test@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mockService := &mockAchievementService{
+		searchErr: errors.New("query pencarian wajib diisi"),
+	}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, nil)
+
+	req := createRequestWithToken("GET", "/api/v1/achievements/search", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusBadRequest)
+}
+
+func TestGetAchievementsRoute_ParsesMultiSelectFilters(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "test@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mockService := &mockAchievementService{
+		getAchievementsResp: map[string]interface{}{"status": "success"},
+	}
+
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, nil)
+
+	req := createRequestWithToken("GET", "/api/v1/achievements?status=submitted,verified&competitionLevel=national&tags=robotik,%20ai&programStudy=Teknik%20Informatika&dateFrom=2025-01-01&dateTo=2025-06-30&minPoints=10&hasAttachments=true&sortBy=points&sortOrder=asc", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+
+	filter := mockService.listFilter
+	if len(filter.Statuses) != 2 || filter.Statuses[1] != "verified" {
+		t.Errorf("Expected two statuses, got %v", filter.Statuses)
+	}
+
+	if len(filter.Tags) != 2 || filter.Tags[1] != "ai" {
+		t.Errorf("Expected trimmed tags, got %v", filter.Tags)
+	}
+
+	if len(filter.ProgramStudies) != 1 || filter.ProgramStudies[0] != "Teknik Informatika" {
+		t.Errorf("Expected program study filter, got %v", filter.ProgramStudies)
+	}
+
+	if filter.DateFrom == nil || filter.DateTo == nil || filter.MinPoints == nil || *filter.MinPoints != 10 {
+		t.Errorf("Expected date range and min points parsed, got %+v", filter)
+	}
+
+	if filter.HasAttachments == nil || !*filter.HasAttachments {
+		t.Errorf("Expected hasAttachments true, got %v", filter.HasAttachments)
+	}
+
+	if filter.SortBy != "points" || filter.SortOrder != "ASC" {
+		t.Errorf("Expected sort points ASC, got %s %s", filter.SortBy, filter.SortOrder)
+	}
+}
+
+func TestGetAchievementsRoute_InvalidFilter(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "test@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	testCases := []string{
+		"/api/v1/achievements?dateFrom=01-01-2025",
+		"/api/v1/achievements?minPoints=banyak",
+		"/api/v1/achievements?hasAttachments=maybe",
+		"/api/v1/achievements?dateFrom=2025-06-01&dateTo=2025-01-01",
+		"/api/v1/achievements?minPoints=50&maxPoints=10",
+		"/api/v1/achievements?cursor=bukan-cursor",
+		"/api/v1/achievements?cursor=" + helper.EncodeCursor(time.Now(), "bukan-object-id"),
+		"/api/v1/achievements?cursor=&sortBy=points",
+	}
+
+	for _, url := range testCases {
+		t.Run(url, func(t *testing.T) {
+			app := setupTestApp()
+			routepostgre.AchievementRoutes(app, &mockAchievementService{}, nil)
+
+			resp, err := app.Test(createRequestWithToken("GET", url, nil, token))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+
+			assertStatusCode(t, resp, http.StatusBadRequest)
+		})
+	}
+}
+
+func TestGetAchievementsRoute_CursorMode(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "test@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	cursorID := primitive.NewObjectID().Hex()
+	mockService := &mockAchievementService{}
+	app := setupTestApp()
+	routepostgre.AchievementRoutes(app, mockService, nil)
+
+	url := "/api/v1/achievements?limit=5&cursor=" + helper.EncodeCursor(time.Now(), cursorID)
+	resp, err := app.Test(createRequestWithToken("GET", url, nil, token))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+
+	filter := mockService.listFilter
+	if !filter.CursorMode || filter.Cursor == nil || filter.Cursor.ID != cursorID || filter.Limit != 5 {
+		t.Errorf("Expected cursor mode with decoded cursor, got %+v", filter)
+	}
+}
+
+func TestAchievementRoutes_IfMatchPreconditions(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "test@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mongoID := primitive.NewObjectID().Hex()
+	conflictErr := errors.New("konflik versi: prestasi sudah diubah sejak terakhir diambil, muat ulang data terbaru lalu coba lagi")
+	testCases := []struct {
+		name       string
+		method     string
+		path       string
+		permission string
+		body       interface{}
+		ifMatch    string
+		service    *mockAchievementService
+		wantStatus int
+	}{
+		{name: "update tanpa If-Match", method: "PUT", path: "", permission: "achievement:update", body: map[string]string{"title": "Baru"}, service: &mockAchievementService{}, wantStatus: http.StatusPreconditionRequired},
+		{name: "verify tanpa If-Match", method: "POST", path: "/verify", permission: "achievement:verify", service: &mockAchievementService{}, wantStatus: http.StatusPreconditionRequired},
+		{name: "reject tanpa If-Match", method: "POST", path: "/reject", permission: "achievement:verify", body: map[string]string{"rejection_note": "Kurang bukti"}, service: &mockAchievementService{}, wantStatus: http.StatusPreconditionRequired},
+		{name: "If-Match bukan ETag prestasi", method: "PUT", path: "", permission: "achievement:update", body: map[string]string{"title": "Baru"}, ifMatch: "*", service: &mockAchievementService{}, wantStatus: http.StatusPreconditionFailed},
+		{name: "update konflik versi", method: "PUT", path: "", permission: "achievement:update", body: map[string]string{"title": "Baru"}, ifMatch: `"1-1"`, service: &mockAchievementService{updateErr: conflictErr}, wantStatus: http.StatusPreconditionFailed},
+		{name: "verify konflik versi", method: "POST", path: "/verify", permission: "achievement:verify", ifMatch: `"1-2"`, service: &mockAchievementService{verifyErr: conflictErr}, wantStatus: http.StatusPreconditionFailed},
+		{name: "reject konflik versi", method: "POST", path: "/reject", permission: "achievement:verify", body: map[string]string{"rejection_note": "Kurang bukti"}, ifMatch: `"1-2"`, service: &mockAchievementService{rejectErr: conflictErr}, wantStatus: http.StatusPreconditionFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := setupTestDBForRoute(t)
+			defer db.Close()
+
+			mock.ExpectQuery(getPermissionQuery()).
+				WithArgs(userID, tc.permission).
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+			app := setupTestApp()
+			routepostgre.AchievementRoutes(app, tc.service, db)
+
+			req := createRequestWithToken(tc.method, "/api/v1/achievements/"+mongoID+tc.path, tc.body, token)
+			if tc.ifMatch != "" {
+				req.Header.Set("If-Match", tc.ifMatch)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+
+			assertStatusCode(t, resp, tc.wantStatus)
+
+			if tc.ifMatch == "" && tc.service.expectedVersion != nil {
+				t.Error("Expected service not to be called without If-Match")
+			}
+		})
+	}
+}
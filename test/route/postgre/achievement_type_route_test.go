@@ -0,0 +1,220 @@
+package route_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	routepostgre "sistem-pelaporan-prestasi-mahasiswa/route/postgre"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type mockAchievementTypeService struct {
+	allResp         *modelmongo.GetAllAchievementTypesResponse
+	typeResp        *modelmongo.GetAchievementTypeResponse
+	schemaResp      *modelmongo.GetAchievementTypeSchemaResponse
+	err             error
+	includeInactive bool
+	createCalled    bool
+}
+
+func (m *mockAchievementTypeService) GetAchievementTypes(ctx context.Context, includeInactive bool) (*modelmongo.GetAllAchievementTypesResponse, error) {
+	m.includeInactive = includeInactive
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.allResp, nil
+}
+
+func (m *mockAchievementTypeService) GetAchievementTypeByCode(ctx context.Context, code string) (*modelmongo.GetAchievementTypeResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.typeResp, nil
+}
+
+func (m *mockAchievementTypeService) GetAchievementTypeSchema(ctx context.Context, code string) (*modelmongo.GetAchievementTypeSchemaResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.schemaResp, nil
+}
+
+func (m *mockAchievementTypeService) CreateAchievementType(ctx context.Context, userID string, req modelmongo.CreateAchievementTypeRequest) (*modelmongo.GetAchievementTypeResponse, error) {
+	m.createCalled = true
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.typeResp, nil
+}
+
+func (m *mockAchievementTypeService) UpdateAchievementType(ctx context.Context, code string, req modelmongo.UpdateAchievementTypeRequest) (*modelmongo.GetAchievementTypeResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.typeResp, nil
+}
+
+func (m *mockAchievementTypeService) ValidateAchievement(ctx context.Context, achievementType string, details modelmongo.AchievementDetails) error {
+	return m.err
+}
+
+func (m *mockAchievementTypeService) IsValidAchievementType(ctx context.Context, code string) (bool, error) {
+	return m.err == nil, m.err
+}
+
+func TestGetAchievementTypesRoute_IncludeInactive(t *testing.T) {
+	token, err := createTestToken("550e8400-e29b-41d4-a716-446655440000", "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mockService := &mockAchievementTypeService{
+		allResp: &modelmongo.GetAllAchievementTypesResponse{
+			Status: "success",
+			Data:   []modelmongo.AchievementTypeDefinition{{Code: "patent", Name: "Paten"}},
+		},
+	}
+
+	app := setupTestApp()
+	routepostgre.AchievementTypeRoutes(app, mockService, nil)
+
+	req := createRequestWithToken("GET", "/api/v1/achievement-types?includeInactive=true", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+
+	if !mockService.includeInactive {
+		t.Error("Expected includeInactive to be passed to service")
+	}
+}
+
+func TestGetAchievementTypeSchemaRoute_Success(t *testing.T) {
+	token, err := createTestToken("550e8400-e29b-41d4-a716-446655440000", "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mockService := &mockAchievementTypeService{
+		schemaResp: &modelmongo.GetAchievementTypeSchemaResponse{
+			Status: "success",
+			Data: modelmongo.AchievementTypeSchema{
+				Code:         "patent",
+				Name:         "Paten",
+				DetailFields: []modelmongo.DetailFieldRule{},
+				CustomFields: []modelmongo.CustomFieldDefinition{
+					{Key: "status", Label: "Status", Type: modelmongo.CustomFieldTypeSelect, Options: []string{"registered", "granted"}},
+				},
+			},
+		},
+	}
+
+	app := setupTestApp()
+	routepostgre.AchievementTypeRoutes(app, mockService, nil)
+
+	req := createRequestWithToken("GET", "/api/v1/achievement-types/patent/schema", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+
+	var result modelmongo.GetAchievementTypeSchemaResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if len(result.Data.CustomFields) != 1 || len(result.Data.CustomFields[0].Options) != 2 {
+		t.Errorf("Expected 1 select custom field with 2 options, got %+v", result.Data.CustomFields)
+	}
+}
+
+func TestGetAchievementTypeSchemaRoute_NotFound(t *testing.T) {
+	token, err := createTestToken("550e8400-e29b-41d4-a716-446655440000", "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	app := setupTestApp()
+	routepostgre.AchievementTypeRoutes(app, &mockAchievementTypeService{err: errors.New("tipe prestasi tidak ditemukan")}, nil)
+
+	req := createRequestWithToken("GET", "/api/v1/achievement-types/unknown/schema", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusNotFound)
+}
+
+func TestCreateAchievementTypeRoute_DuplicateCode(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "admin@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement_type:manage").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+	app := setupTestApp()
+	routepostgre.AchievementTypeRoutes(app, &mockAchievementTypeService{err: errors.New("kode tipe prestasi sudah digunakan")}, db)
+
+	body := modelmongo.CreateAchievementTypeRequest{Code: "competition", Name: "Kompetisi"}
+	req := createRequestWithToken("POST", "/api/v1/achievement-types", body, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusConflict)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestCreateAchievementTypeRoute_Forbidden(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "student@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement_type:manage").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(false))
+
+	mockService := &mockAchievementTypeService{}
+
+	app := setupTestApp()
+	routepostgre.AchievementTypeRoutes(app, mockService, db)
+
+	body := modelmongo.CreateAchievementTypeRequest{Code: "patent", Name: "Paten"}
+	req := createRequestWithToken("POST", "/api/v1/achievement-types", body, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusForbidden)
+
+	if mockService.createCalled {
+		t.Error("Expected create not to be called without permission")
+	}
+}
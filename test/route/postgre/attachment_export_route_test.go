@@ -0,0 +1,114 @@
+package route_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	routepostgre "sistem-pelaporan-prestasi-mahasiswa/route/postgre"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type mockAttachmentExportService struct {
+	export *modelmongo.AttachmentExport
+	err    error
+	filter modelpostgre.AchievementListFilter
+}
+
+func (m *mockAttachmentExportService) PrepareAttachmentExport(ctx context.Context, userID string, roleID string, filter modelpostgre.AchievementListFilter) (*modelmongo.AttachmentExport, error) {
+	m.filter = filter
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.export, nil
+}
+
+func (m *mockAttachmentExportService) WriteAttachmentExport(ctx context.Context, export *modelmongo.AttachmentExport, w io.Writer) error {
+	archive := zip.NewWriter(w)
+	for _, entry := range export.Entries {
+		file, err := archive.Create(entry.Path)
+		if err != nil {
+			return err
+		}
+		file.Write([]byte("isi " + entry.Path))
+	}
+	return archive.Close()
+}
+
+func testAttachmentExportRoute(t *testing.T, exportService *mockAttachmentExportService, query string) *http.Response {
+	t.Helper()
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:read").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+	app := setupTestApp()
+	routepostgre.AttachmentExportRoutes(app, exportService, db)
+
+	req := httptest.NewRequest("GET", "/api/v1/reports/attachments/export"+query, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	return resp
+}
+
+func TestExportAttachmentsRoute_StreamsZip(t *testing.T) {
+	exportService := &mockAttachmentExportService{export: &modelmongo.AttachmentExport{
+		FileName: "lampiran-prestasi-20250310-120000.zip",
+		Entries:  []modelmongo.AttachmentExportEntry{{Path: "TI/Budi/a.pdf"}, {Path: "TI/Budi/b.pdf"}},
+	}}
+
+	resp := testAttachmentExportRoute(t, exportService, "?programStudy=TI,SI&studentId=student-1&dateFrom=2025-01-01&dateTo=2025-03-31")
+	assertStatusCode(t, resp, http.StatusOK)
+
+	if resp.Header.Get("Content-Type") != "application/zip" {
+		t.Errorf("Expected application/zip, got %s", resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(resp.Header.Get("Content-Disposition"), "lampiran-prestasi-20250310-120000.zip") {
+		t.Errorf("Expected file name in Content-Disposition, got %s", resp.Header.Get("Content-Disposition"))
+	}
+	if len(exportService.filter.ProgramStudies) != 2 || exportService.filter.StudentIDs[0] != "student-1" || exportService.filter.DateTo == nil {
+		t.Errorf("Expected filter parsed from query, got %+v", exportService.filter)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("Expected valid ZIP body, got %v", err)
+	}
+	if len(archive.File) != 2 || archive.File[1].Name != "TI/Budi/b.pdf" {
+		t.Errorf("Expected 2 files in ZIP, got %d", len(archive.File))
+	}
+}
+
+func TestExportAttachmentsRoute_NotFound(t *testing.T) {
+	exportService := &mockAttachmentExportService{err: errors.New("attachment tidak ditemukan untuk filter yang dipilih")}
+
+	resp := testAttachmentExportRoute(t, exportService, "")
+	assertStatusCode(t, resp, http.StatusNotFound)
+}
+
+func TestExportAttachmentsRoute_InvalidDateRange(t *testing.T) {
+	resp := testAttachmentExportRoute(t, &mockAttachmentExportService{}, "?dateFrom=2025-03-31&dateTo=2025-01-01")
+	assertStatusCode(t, resp, http.StatusBadRequest)
+}
@@ -0,0 +1,146 @@
+package route_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	routepostgre "sistem-pelaporan-prestasi-mahasiswa/route/postgre"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type mockAttachmentUploadService struct {
+	createResp  *modelmongo.AttachmentUploadResponse
+	createReq   modelmongo.CreateAttachmentUploadRequest
+	appendResp  *modelmongo.AttachmentUploadResponse
+	appendErr   error
+	chunkOffset int64
+	chunk       []byte
+}
+
+func (m *mockAttachmentUploadService) CreateUpload(ctx context.Context, userID string, mongoID string, req modelmongo.CreateAttachmentUploadRequest) (*modelmongo.AttachmentUploadResponse, error) {
+	m.createReq = req
+	if m.createResp == nil {
+		return nil, errors.New("terlalu banyak upload yang belum selesai, selesaikan atau batalkan upload lain terlebih dahulu (maksimal 3)")
+	}
+	return m.createResp, nil
+}
+
+func (m *mockAttachmentUploadService) GetUpload(ctx context.Context, userID string, mongoID string, uploadID string) (*modelmongo.AttachmentUploadResponse, error) {
+	return nil, errors.New("upload tidak ditemukan atau sudah kedaluwarsa")
+}
+
+func (m *mockAttachmentUploadService) AppendChunk(ctx context.Context, userID string, mongoID string, uploadID string, offset int64, chunk []byte) (*modelmongo.AttachmentUploadResponse, error) {
+	m.chunkOffset = offset
+	m.chunk = chunk
+	if m.appendErr != nil {
+		return nil, m.appendErr
+	}
+	return m.appendResp, nil
+}
+
+func (m *mockAttachmentUploadService) CompleteUpload(ctx context.Context, userID string, roleID string, mongoID string, uploadID string) (*modelmongo.Attachment, error) {
+	return nil, &modelmongo.AttachmentValidationError{Message: "upload belum lengkap, baru diterima 16 dari 52 byte"}
+}
+
+func (m *mockAttachmentUploadService) CancelUpload(ctx context.Context, userID string, mongoID string, uploadID string) error {
+	return nil
+}
+
+func (m *mockAttachmentUploadService) CleanupExpiredUploads(ctx context.Context) (*modelmongo.AttachmentUploadCleanupResult, error) {
+	return &modelmongo.AttachmentUploadCleanupResult{}, nil
+}
+
+func testAttachmentUploadRoute(t *testing.T, uploadService *mockAttachmentUploadService, method string, path string, body []byte, headers map[string]string) *http.Response {
+	t.Helper()
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "achievement:update").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+	app := setupTestApp()
+	routepostgre.AttachmentUploadRoutes(app, uploadService, db)
+
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+	return resp
+}
+
+func TestCreateAttachmentUploadRoute_Created(t *testing.T) {
+	mockService := &mockAttachmentUploadService{createResp: &modelmongo.AttachmentUploadResponse{Status: "success"}}
+
+	resp := testAttachmentUploadRoute(t, mockService, "POST", "/api/v1/achievements/507f1f77bcf86cd799439011/uploads", []byte(`{"file_name":"video.mp4","size":157286400}`), map[string]string{"Content-Type": "application/json"})
+
+	assertStatusCode(t, resp, http.StatusCreated)
+	if mockService.createReq.FileName != "video.mp4" || mockService.createReq.Size != 157286400 {
+		t.Errorf("Unexpected request %+v", mockService.createReq)
+	}
+}
+
+func TestCreateAttachmentUploadRoute_TooManyUploads(t *testing.T) {
+	resp := testAttachmentUploadRoute(t, &mockAttachmentUploadService{}, "POST", "/api/v1/achievements/507f1f77bcf86cd799439011/uploads", []byte(`{"file_name":"video.mp4","size":1}`), map[string]string{"Content-Type": "application/json"})
+
+	assertStatusCode(t, resp, http.StatusTooManyRequests)
+}
+
+func TestUploadAttachmentChunkRoute_PassesOffsetAndBody(t *testing.T) {
+	mockService := &mockAttachmentUploadService{appendResp: &modelmongo.AttachmentUploadResponse{Status: "success", Data: modelmongo.AttachmentUpload{Offset: 8}}}
+
+	resp := testAttachmentUploadRoute(t, mockService, "PATCH", "/api/v1/achievements/507f1f77bcf86cd799439011/uploads/6650f1f77bcf86cd79943901", []byte("%PDF-1.4"), map[string]string{"Content-Type": "application/octet-stream", "Upload-Offset": "0"})
+
+	assertStatusCode(t, resp, http.StatusOK)
+	if string(mockService.chunk) != "%PDF-1.4" || mockService.chunkOffset != 0 {
+		t.Errorf("Unexpected chunk %q at %d", mockService.chunk, mockService.chunkOffset)
+	}
+	if resp.Header.Get("Upload-Offset") != "8" {
+		t.Errorf("Expected Upload-Offset header 8, got %q", resp.Header.Get("Upload-Offset"))
+	}
+}
+
+func TestUploadAttachmentChunkRoute_ErrorStatus(t *testing.T) {
+	cases := []struct {
+		name    string
+		headers map[string]string
+		err     error
+		status  int
+	}{
+		{"missing offset", nil, nil, http.StatusBadRequest},
+		{"offset mismatch", map[string]string{"Upload-Offset": "16"}, errors.New("offset upload tidak sesuai, server sudah menerima 0 byte"), http.StatusConflict},
+		{"invalid chunk", map[string]string{"Upload-Offset": "0"}, &modelmongo.AttachmentValidationError{Message: "potongan file kosong"}, http.StatusBadRequest},
+		{"expired", map[string]string{"Upload-Offset": "0"}, errors.New("upload tidak ditemukan atau sudah kedaluwarsa"), http.StatusNotFound},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := testAttachmentUploadRoute(t, &mockAttachmentUploadService{appendErr: tc.err}, "PATCH", "/api/v1/achievements/507f1f77bcf86cd799439011/uploads/6650f1f77bcf86cd79943901", []byte("x"), tc.headers)
+			assertStatusCode(t, resp, tc.status)
+		})
+	}
+}
+
+func TestCompleteAttachmentUploadRoute_IncompleteUpload(t *testing.T) {
+	resp := testAttachmentUploadRoute(t, &mockAttachmentUploadService{}, "POST", "/api/v1/achievements/507f1f77bcf86cd799439011/uploads/6650f1f77bcf86cd79943901/complete", nil, nil)
+
+	assertStatusCode(t, resp, http.StatusBadRequest)
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	"sistem-pelaporan-prestasi-mahasiswa/helper"
 	routepostgre "sistem-pelaporan-prestasi-mahasiswa/route/postgre"
 )
 
@@ -21,6 +22,8 @@ type mockNotificationService struct {
 	markAsReadErr        error
 	markAllAsReadResp    *modelpostgre.MarkAllAsReadResponse
 	markAllAsReadErr     error
+	cursorCalled         bool
+	cursor               *modelpostgre.PageCursor
 }
 
 func (m *mockNotificationService) GetNotifications(ctx context.Context, userID string, page, limit int) (*modelpostgre.GetNotificationsResponse, error) {
@@ -30,6 +33,15 @@ func (m *mockNotificationService) GetNotifications(ctx context.Context, userID s
 	return m.getNotificationsResp, nil
 }
 
+func (m *mockNotificationService) GetNotificationsByCursor(ctx context.Context, userID string, cursor *modelpostgre.PageCursor, limit int) (*modelpostgre.GetNotificationsCursorResponse, error) {
+	m.cursorCalled = true
+	m.cursor = cursor
+	if m.getNotificationsErr != nil {
+		return nil, m.getNotificationsErr
+	}
+	return &modelpostgre.GetNotificationsCursorResponse{Status: "success", Data: []modelpostgre.Notification{}}, nil
+}
+
 func (m *mockNotificationService) GetUnreadCount(ctx context.Context, userID string) (*modelpostgre.GetUnreadCountResponse, error) {
 	if m.getUnreadCountErr != nil {
 		return nil, m.getUnreadCountErr
@@ -59,6 +71,30 @@ func (m *mockNotificationService) CreateSubmissionNotification(ctx context.Conte
 	return nil
 }
 
+func (m *mockNotificationService) CreateTeamInvitationNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string) error {
+	return nil
+}
+
+func (m *mockNotificationService) RetractSubmissionNotification(ctx context.Context, achievementRefID string) error {
+	return nil
+}
+
+func (m *mockNotificationService) CreateSLAReminderNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string, waitingDays int) error {
+	return nil
+}
+
+func (m *mockNotificationService) CreateSLAEscalationNotification(ctx context.Context, recipientUserIDs []string, mongoAchievementID string, achievementRefID string, waitingDays int) error {
+	return nil
+}
+
+func (m *mockNotificationService) CreateCertificationExpiryNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string, validUntil time.Time, daysLeft int) error {
+	return nil
+}
+
+func (m *mockNotificationService) CreateAttachmentInfectedNotification(ctx context.Context, studentID string, mongoAchievementID string, achievementRefID string, fileName string) error {
+	return nil
+}
+
 func TestGetNotificationsRoute_Success(t *testing.T) {
 	userID := "550e8400-e29b-41d4-a716-446655440000"
 	email := "test@example.com"
@@ -83,10 +119,11 @@ func TestGetNotificationsRoute_Success(t *testing.T) {
 				},
 			},
 			Pagination: struct {
-				Page       int `json:"page"`
-				Limit      int `json:"limit"`
-				Total      int `json:"total"`
-				TotalPages int `json:"total_pages"`
+				Page       int    `json:"page"`
+				Limit      int    `json:"limit"`
+				Total      int    `json:"total"`
+				TotalPages int    `json:"total_pages"`
+				NextCursor string `json:"next_cursor,omitempty"`
 			}{
 				Page:       1,
 				Limit:      10,
@@ -253,3 +290,50 @@ func TestMarkAllAsReadRoute_Success(t *testing.T) {
 func timePtr(t time.Time) *time.Time {
 	return &t
 }
+
+func TestGetNotificationsRoute_CursorMode(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "test@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	cursorID := "660e8400-e29b-41d4-a716-446655440000"
+	testCases := []struct {
+		name       string
+		url        string
+		wantStatus int
+		wantCursor bool
+	}{
+		{name: "halaman pertama", url: "/api/v1/notifications?cursor=", wantStatus: http.StatusOK},
+		{name: "halaman berikutnya", url: "/api/v1/notifications?cursor=" + helper.EncodeCursor(time.Now(), cursorID), wantStatus: http.StatusOK, wantCursor: true},
+		{name: "cursor rusak", url: "/api/v1/notifications?cursor=bukan-cursor", wantStatus: http.StatusBadRequest},
+		{name: "id bukan uuid", url: "/api/v1/notifications?cursor=" + helper.EncodeCursor(time.Now(), "abc"), wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockService := &mockNotificationService{}
+			app := setupTestApp()
+			routepostgre.NotificationRoutes(app, mockService)
+
+			resp, err := app.Test(createRequestWithToken("GET", tc.url, nil, token))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+
+			assertStatusCode(t, resp, tc.wantStatus)
+
+			if tc.wantStatus == http.StatusOK && !mockService.cursorCalled {
+				t.Error("Expected cursor mode to be used")
+			}
+
+			if tc.wantCursor && (mockService.cursor == nil || mockService.cursor.ID != cursorID) {
+				t.Errorf("Expected decoded cursor %s, got %+v", cursorID, mockService.cursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package route_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	modelmongo "sistem-pelaporan-prestasi-mahasiswa/app/model/mongo"
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	routepostgre "sistem-pelaporan-prestasi-mahasiswa/route/postgre"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type mockPointRubricService struct {
+	allResp         *modelpostgre.GetAllPointRubricsResponse
+	rubricResp      *modelpostgre.GetPointRubricResponse
+	calculation     *modelpostgre.PointCalculationResult
+	err             error
+	calculatedType  string
+	activateVersion int
+}
+
+func (m *mockPointRubricService) GetPointRubrics(ctx context.Context) (*modelpostgre.GetAllPointRubricsResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.allResp, nil
+}
+
+func (m *mockPointRubricService) GetActivePointRubric(ctx context.Context) (*modelpostgre.GetPointRubricResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.rubricResp, nil
+}
+
+func (m *mockPointRubricService) GetPointRubricByVersion(ctx context.Context, version int) (*modelpostgre.GetPointRubricResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.rubricResp, nil
+}
+
+func (m *mockPointRubricService) CreatePointRubric(ctx context.Context, userID string, req modelpostgre.CreatePointRubricRequest) (*modelpostgre.GetPointRubricResponse, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.rubricResp, nil
+}
+
+func (m *mockPointRubricService) ActivatePointRubric(ctx context.Context, version int) (*modelpostgre.GetPointRubricResponse, error) {
+	m.activateVersion = version
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.rubricResp, nil
+}
+
+func (m *mockPointRubricService) CalculatePoints(ctx context.Context, achievementType string, details modelmongo.AchievementDetails) (*modelpostgre.PointCalculationResult, error) {
+	m.calculatedType = achievementType
+	if m.err != nil {
+		return nil, m.err
+	}
+	return m.calculation, nil
+}
+
+func TestGetActivePointRubricRoute_Success(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mockService := &mockPointRubricService{
+		rubricResp: &modelpostgre.GetPointRubricResponse{
+			Status: "success",
+			Data:   modelpostgre.PointRubric{ID: "rubric-id-1", Version: 1, IsActive: true},
+		},
+	}
+
+	app := setupTestApp()
+	routepostgre.PointRubricRoutes(app, mockService, nil)
+
+	req := createRequestWithToken("GET", "/api/v1/point-rubrics/active", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+}
+
+func TestGetPointRubricByVersionRoute_InvalidVersion(t *testing.T) {
+	token, err := createTestToken("550e8400-e29b-41d4-a716-446655440000", "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	app := setupTestApp()
+	routepostgre.PointRubricRoutes(app, &mockPointRubricService{}, nil)
+
+	req := createRequestWithToken("GET", "/api/v1/point-rubrics/abc", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusBadRequest)
+}
+
+func TestGetPointRubricByVersionRoute_NotFound(t *testing.T) {
+	token, err := createTestToken("550e8400-e29b-41d4-a716-446655440000", "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	app := setupTestApp()
+	routepostgre.PointRubricRoutes(app, &mockPointRubricService{err: errors.New("rubrik poin tidak ditemukan")}, nil)
+
+	req := createRequestWithToken("GET", "/api/v1/point-rubrics/7", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusNotFound)
+}
+
+func TestCalculatePointsRoute_Success(t *testing.T) {
+	token, err := createTestToken("550e8400-e29b-41d4-a716-446655440000", "test@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mockService := &mockPointRubricService{
+		calculation: &modelpostgre.PointCalculationResult{Points: 60, RubricVersion: 1},
+	}
+
+	app := setupTestApp()
+	routepostgre.PointRubricRoutes(app, mockService, nil)
+
+	level := "national"
+	body := modelmongo.CalculatePointsRequest{
+		AchievementType: "competition",
+		Details:         modelmongo.AchievementDetails{CompetitionLevel: &level},
+	}
+
+	req := createRequestWithToken("POST", "/api/v1/point-rubrics/calculate", body, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+
+	var result modelpostgre.CalculatePointsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if result.Data.Points != 60 {
+		t.Errorf("Expected points 60, got %d", result.Data.Points)
+	}
+
+	if mockService.calculatedType != "competition" {
+		t.Errorf("Expected achievement type 'competition', got '%s'", mockService.calculatedType)
+	}
+}
+
+func TestCreatePointRubricRoute_Success(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "admin@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "rubric:manage").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+	mockService := &mockPointRubricService{
+		rubricResp: &modelpostgre.GetPointRubricResponse{
+			Status: "success",
+			Data:   modelpostgre.PointRubric{ID: "rubric-id-2", Version: 2},
+		},
+	}
+
+	app := setupTestApp()
+	routepostgre.PointRubricRoutes(app, mockService, db)
+
+	body := modelpostgre.CreatePointRubricRequest{
+		Name:  "Rubrik 2025",
+		Rules: []modelpostgre.PointRule{{AchievementType: "academic", Points: 25}},
+	}
+
+	req := createRequestWithToken("POST", "/api/v1/point-rubrics", body, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusCreated)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestActivatePointRubricRoute_Forbidden(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "student@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "rubric:manage").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(false))
+
+	mockService := &mockPointRubricService{}
+
+	app := setupTestApp()
+	routepostgre.PointRubricRoutes(app, mockService, db)
+
+	req := createRequestWithToken("POST", "/api/v1/point-rubrics/2/activate", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusForbidden)
+
+	if mockService.activateVersion != 0 {
+		t.Error("Expected activate not to be called without permission")
+	}
+}
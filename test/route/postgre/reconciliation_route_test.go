@@ -0,0 +1,163 @@
+package route_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	routepostgre "sistem-pelaporan-prestasi-mahasiswa/route/postgre"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type mockReconciliationService struct {
+	response     *modelpostgre.ReconcileResponse
+	err          error
+	repairPolicy modelpostgre.ReconcilePolicy
+}
+
+func (m *mockReconciliationService) RunReconciliation(ctx context.Context, policy modelpostgre.ReconcilePolicy) (*modelpostgre.ReconcileResult, error) {
+	return &m.response.Data, m.err
+}
+
+func (m *mockReconciliationService) GetReconciliationReport(ctx context.Context, userID string, roleID string) (*modelpostgre.ReconcileResponse, error) {
+	return m.response, m.err
+}
+
+func (m *mockReconciliationService) RepairInconsistencies(ctx context.Context, userID string, roleID string, policy modelpostgre.ReconcilePolicy) (*modelpostgre.ReconcileResponse, error) {
+	m.repairPolicy = policy
+	return m.response, m.err
+}
+
+func newReconcileRouteResponse() *modelpostgre.ReconcileResponse {
+	response := &modelpostgre.ReconcileResponse{Status: "success"}
+	response.Data.TotalIssues = 1
+	response.Data.Categories = []modelpostgre.ReconcileCategorySummary{
+		{Category: modelpostgre.ReconcileCategoryMongoWithoutReference, Policy: modelpostgre.ReconcilePolicyReport, Found: 1},
+	}
+	response.Data.Issues = []modelpostgre.ReconcileIssue{
+		{Category: modelpostgre.ReconcileCategoryMongoWithoutReference, MongoAchievementID: "mongo-id-1", Action: modelpostgre.ReconcilePolicyReport},
+	}
+	return response
+}
+
+func TestReconciliationRoutes(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "admin@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	testCases := []struct {
+		name         string
+		method       string
+		path         string
+		body         interface{}
+		err          error
+		expectedCode int
+		expectedText string
+	}{
+		{name: "laporan JSON", method: "GET", path: "/api/v1/reports/reconciliation", expectedCode: http.StatusOK, expectedText: `"mongo_without_reference"`},
+		{name: "laporan teks", method: "GET", path: "/api/v1/reports/reconciliation?format=text", expectedCode: http.StatusOK, expectedText: "mongo=mongo-id-1"},
+		{name: "bukan admin", method: "GET", path: "/api/v1/reports/reconciliation", err: errors.New("akses ditolak. Hanya admin yang dapat menjalankan rekonsiliasi data prestasi"), expectedCode: http.StatusForbidden},
+		{name: "perbaikan", method: "POST", path: "/api/v1/reports/reconciliation/repair", body: modelpostgre.ReconcilePolicy{MongoWithoutReference: "purge"}, expectedCode: http.StatusOK},
+		{name: "policy tidak valid", method: "POST", path: "/api/v1/reports/reconciliation/repair", body: modelpostgre.ReconcilePolicy{MongoWithoutReference: "hapus"}, err: errors.New("policy tidak valid untuk mongo_without_reference: hapus"), expectedCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := setupTestDBForRoute(t)
+			defer db.Close()
+
+			mock.ExpectQuery(getPermissionQuery()).
+				WithArgs(userID, "report:reconcile").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+			mockService := &mockReconciliationService{response: newReconcileRouteResponse(), err: tc.err}
+
+			app := setupTestApp()
+			routepostgre.ReconciliationRoutes(app, mockService, db)
+
+			resp, err := app.Test(createRequestWithToken(tc.method, tc.path, tc.body, token))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+
+			assertStatusCode(t, resp, tc.expectedCode)
+
+			body, _ := io.ReadAll(resp.Body)
+			if tc.expectedText != "" && !strings.Contains(string(body), tc.expectedText) {
+				t.Errorf("Expected body to contain %q, got %s", tc.expectedText, body)
+			}
+
+			if tc.method == "POST" && mockService.repairPolicy.MongoWithoutReference != tc.body.(modelpostgre.ReconcilePolicy).MongoWithoutReference {
+				t.Errorf("Expected policy passed to service, got %+v", mockService.repairPolicy)
+			}
+		})
+	}
+}
+
+func TestReconciliationRoutes_Forbidden(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "lecturer@example.com", "550e8400-e29b-41d4-a716-446655440002")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	for _, method := range []string{"GET", "POST"} {
+		t.Run(method, func(t *testing.T) {
+			db, mock := setupTestDBForRoute(t)
+			defer db.Close()
+
+			mock.ExpectQuery(getPermissionQuery()).
+				WithArgs(userID, "report:reconcile").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(false))
+
+			mockService := &mockReconciliationService{response: newReconcileRouteResponse()}
+
+			app := setupTestApp()
+			routepostgre.ReconciliationRoutes(app, mockService, db)
+
+			path := "/api/v1/reports/reconciliation"
+			var body interface{}
+			if method == "POST" {
+				path = "/api/v1/reports/reconciliation/repair"
+				body = modelpostgre.ReconcilePolicy{MongoWithoutReference: "purge"}
+			}
+
+			resp, err := app.Test(createRequestWithToken(method, path, body, token))
+			if err != nil {
+				t.Fatalf("Failed to make request: %v", err)
+			}
+
+			assertStatusCode(t, resp, http.StatusForbidden)
+
+			if mockService.repairPolicy.MongoWithoutReference != "" {
+				t.Errorf("Expected repair not to run, got policy %+v", mockService.repairPolicy)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestReconciliationRoutes_Unauthorized(t *testing.T) {
+	db, _ := setupTestDBForRoute(t)
+	defer db.Close()
+
+	app := setupTestApp()
+	routepostgre.ReconciliationRoutes(app, &mockReconciliationService{}, db)
+
+	resp, err := app.Test(createRequestWithToken("GET", "/api/v1/reports/reconciliation", nil, ""))
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusUnauthorized)
+}
@@ -21,6 +21,10 @@ type mockReportService struct {
 	getCurrentLecturerReportErr  error
 	getLecturerReportResp        map[string]interface{}
 	getLecturerReportErr         error
+	getDuplicateReportResp       map[string]interface{}
+	getDuplicateReportErr        error
+	getStorageUsageReportResp    map[string]interface{}
+	getStorageUsageReportErr     error
 }
 
 func (m *mockReportService) GetStatistics(ctx context.Context, userID string, roleID string) (map[string]interface{}, error) {
@@ -58,6 +62,20 @@ func (m *mockReportService) GetLecturerReport(ctx context.Context, lecturerID st
 	return m.getLecturerReportResp, nil
 }
 
+func (m *mockReportService) GetDuplicateReport(ctx context.Context, userID string, roleID string) (map[string]interface{}, error) {
+	if m.getDuplicateReportErr != nil {
+		return nil, m.getDuplicateReportErr
+	}
+	return m.getDuplicateReportResp, nil
+}
+
+func (m *mockReportService) GetStorageUsageReport(ctx context.Context, userID string, roleID string) (map[string]interface{}, error) {
+	if m.getStorageUsageReportErr != nil {
+		return nil, m.getStorageUsageReportErr
+	}
+	return m.getStorageUsageReportResp, nil
+}
+
 func TestGetStatisticsRoute_Success(t *testing.T) {
 	userID := "550e8400-e29b-41d4-a716-446655440000"
 	email := "test@example.com"
@@ -314,3 +332,111 @@ func TestGetStatisticsRoute_Error(t *testing.T) {
 
 	assertStatusCode(t, resp, http.StatusInternalServerError)
 }
+
+func TestGetDuplicateReportRoute_Success(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "admin@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mockService := &mockReportService{
+		getDuplicateReportResp: map[string]interface{}{
+			"status": "success",
+			"data": map[string]interface{}{
+				"totalGroups": 0,
+				"groups":      []interface{}{},
+			},
+		},
+	}
+
+	app := setupTestApp()
+	routepostgre.ReportRoutes(app, mockService, nil)
+
+	req := createRequestWithToken("GET", "/api/v1/reports/duplicates", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+}
+
+func TestGetDuplicateReportRoute_Forbidden(t *testing.T) {
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "student@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mockService := &mockReportService{
+		getDuplicateReportErr: errors.New("akses ditolak. Hanya admin yang dapat melihat laporan duplikat"),
+	}
+
+	app := setupTestApp()
+	routepostgre.ReportRoutes(app, mockService, nil)
+
+	req := createRequestWithToken("GET", "/api/v1/reports/duplicates", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusForbidden)
+}
+
+func TestGetStorageUsageReportRoute_Success(t *testing.T) {
+	token, err := createTestToken("550e8400-e29b-41d4-a716-446655440000", "admin@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mockService := &mockReportService{
+		getStorageUsageReportResp: map[string]interface{}{
+			"status": "success",
+			"data": map[string]interface{}{
+				"quota_bytes": 200 * 1024 * 1024,
+				"students":    []interface{}{},
+			},
+		},
+	}
+
+	app := setupTestApp()
+	routepostgre.ReportRoutes(app, mockService, nil)
+
+	req := createRequestWithToken("GET", "/api/v1/reports/storage-usage", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+}
+
+func TestGetStorageUsageReportRoute_Forbidden(t *testing.T) {
+	token, err := createTestToken("550e8400-e29b-41d4-a716-446655440000", "student@example.com", "550e8400-e29b-41d4-a716-446655440001")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mockService := &mockReportService{
+		getStorageUsageReportErr: errors.New("akses ditolak. Hanya admin yang dapat melihat laporan pemakaian storage"),
+	}
+
+	app := setupTestApp()
+	routepostgre.ReportRoutes(app, mockService, nil)
+
+	req := createRequestWithToken("GET", "/api/v1/reports/storage-usage", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusForbidden)
+}
@@ -0,0 +1,132 @@
+package route_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	modelpostgre "sistem-pelaporan-prestasi-mahasiswa/app/model/postgre"
+	routepostgre "sistem-pelaporan-prestasi-mahasiswa/route/postgre"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+type mockSLAService struct {
+	checkResult *modelpostgre.SLACheckResult
+	checkErr    error
+	overdueResp *modelpostgre.GetOverdueAchievementsResponse
+	overdueErr  error
+}
+
+func (m *mockSLAService) RunSLACheck(ctx context.Context) (*modelpostgre.SLACheckResult, error) {
+	return m.checkResult, m.checkErr
+}
+
+func (m *mockSLAService) GetOverdueAchievements(ctx context.Context, userID string, roleID string) (*modelpostgre.GetOverdueAchievementsResponse, error) {
+	return m.overdueResp, m.overdueErr
+}
+
+func TestGetOverdueAchievementsRoute_Success(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "admin@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "report:read").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+	overdueResp := &modelpostgre.GetOverdueAchievementsResponse{Status: "success"}
+	overdueResp.Data.Total = 1
+	overdueResp.Data.Achievements = []modelpostgre.OverdueAchievement{
+		{AchievementRefID: "ref-id-1", AgeDays: 8, Stage: modelpostgre.SLAStageEscalated},
+	}
+
+	app := setupTestApp()
+	routepostgre.SLARoutes(app, &mockSLAService{overdueResp: overdueResp}, db)
+
+	req := createRequestWithToken("GET", "/api/v1/reports/overdue", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusOK)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
+
+func TestGetOverdueAchievementsRoute_Forbidden(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	email := "lecturer@example.com"
+	roleID := "550e8400-e29b-41d4-a716-446655440001"
+
+	token, err := createTestToken(userID, email, roleID)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "report:read").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(true))
+
+	mockService := &mockSLAService{
+		overdueErr: errors.New("akses ditolak. Hanya admin yang dapat melihat prestasi yang melewati batas SLA"),
+	}
+
+	app := setupTestApp()
+	routepostgre.SLARoutes(app, mockService, db)
+
+	req := createRequestWithToken("GET", "/api/v1/reports/overdue", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusForbidden)
+}
+
+func TestGetOverdueAchievementsRoute_MissingPermission(t *testing.T) {
+	db, mock := setupTestDBForRoute(t)
+	defer db.Close()
+
+	userID := "550e8400-e29b-41d4-a716-446655440000"
+	token, err := createTestToken(userID, "guest@example.com", "550e8400-e29b-41d4-a716-446655440002")
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	mock.ExpectQuery(getPermissionQuery()).
+		WithArgs(userID, "report:read").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(false))
+
+	mockService := &mockSLAService{}
+
+	app := setupTestApp()
+	routepostgre.SLARoutes(app, mockService, db)
+
+	req := createRequestWithToken("GET", "/api/v1/reports/overdue", nil, token)
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to make request: %v", err)
+	}
+
+	assertStatusCode(t, resp, http.StatusForbidden)
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %v", err)
+	}
+}
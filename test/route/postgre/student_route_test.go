@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"io"
 	"net/http"
 	"testing"
 	"time"
@@ -96,11 +97,39 @@ func (m *mockAchievementServiceForRoute) SubmitAchievement(ctx context.Context,
 	return nil, errors.New("not implemented")
 }
 
-func (m *mockAchievementServiceForRoute) VerifyAchievement(ctx context.Context, userID string, roleID string, mongoID string) (*modelpostgre.VerifyAchievementResponse, error) {
+func (m *mockAchievementServiceForRoute) WithdrawAchievement(ctx context.Context, userID string, roleID string, mongoID string) (*modelpostgre.UpdateAchievementReferenceResponse, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (m *mockAchievementServiceForRoute) RejectAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelpostgre.RejectAchievementRequest) (*modelpostgre.RejectAchievementResponse, error) {
+func (m *mockAchievementServiceForRoute) GetDeletedAchievements(ctx context.Context, userID string, roleID string, page, limit int) (*modelpostgre.GetDeletedAchievementsResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAchievementServiceForRoute) RestoreAchievement(ctx context.Context, userID string, roleID string, mongoID string) (*modelpostgre.UpdateAchievementReferenceResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAchievementServiceForRoute) PurgeDeletedAchievements(ctx context.Context, userID string, roleID string, dryRun bool) (*modelpostgre.TrashPurgeResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAchievementServiceForRoute) RunTrashPurge(ctx context.Context, retentionDays int, dryRun bool) (*modelpostgre.TrashPurgeResult, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAchievementServiceForRoute) SearchAchievements(ctx context.Context, userID string, roleID string, query string, page, limit int) (map[string]interface{}, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAchievementServiceForRoute) ConfirmTeamParticipation(ctx context.Context, userID string, roleID string, mongoID string) (*modelmongo.ConfirmTeamParticipationResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAchievementServiceForRoute) VerifyAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelpostgre.VerifyAchievementRequest, expected modelpostgre.AchievementVersion) (*modelpostgre.VerifyAchievementResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAchievementServiceForRoute) RejectAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelpostgre.RejectAchievementRequest, expected modelpostgre.AchievementVersion) (*modelpostgre.RejectAchievementResponse, error) {
 	return nil, errors.New("not implemented")
 }
 
@@ -108,7 +137,7 @@ func (m *mockAchievementServiceForRoute) DeleteAchievement(ctx context.Context,
 	return nil, errors.New("not implemented")
 }
 
-func (m *mockAchievementServiceForRoute) GetAchievements(ctx context.Context, userID string, roleID string, page, limit int, statusFilter string, achievementTypeFilter string, sortBy string, sortOrder string) (map[string]interface{}, error) {
+func (m *mockAchievementServiceForRoute) GetAchievements(ctx context.Context, userID string, roleID string, filter modelpostgre.AchievementListFilter) (map[string]interface{}, error) {
 	return nil, errors.New("not implemented")
 }
 
@@ -123,7 +152,7 @@ func (m *mockAchievementServiceForRoute) GetAchievementByID(ctx context.Context,
 	return nil, errors.New("not implemented")
 }
 
-func (m *mockAchievementServiceForRoute) UpdateAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelmongo.UpdateAchievementRequest) (map[string]interface{}, error) {
+func (m *mockAchievementServiceForRoute) UpdateAchievement(ctx context.Context, userID string, roleID string, mongoID string, req modelmongo.UpdateAchievementRequest, expected modelpostgre.AchievementVersion) (map[string]interface{}, error) {
 	return nil, errors.New("not implemented")
 }
 
@@ -131,7 +160,27 @@ func (m *mockAchievementServiceForRoute) GetAchievementStats(ctx context.Context
 	return nil, errors.New("not implemented")
 }
 
-func (m *mockAchievementServiceForRoute) UploadFile(ctx context.Context, userID string, roleID string, mongoID string, fileName string, fileURL string, fileType string) (*modelmongo.Attachment, error) {
+func (m *mockAchievementServiceForRoute) UploadFile(ctx context.Context, userID string, roleID string, mongoID string, fileName string, fileType string, size int64, content io.Reader) (*modelmongo.Attachment, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAchievementServiceForRoute) GetAttachmentFile(ctx context.Context, userID string, roleID string, mongoID string, key string) (*modelmongo.AttachmentDownload, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAchievementServiceForRoute) CreateAttachmentLink(ctx context.Context, userID string, roleID string, mongoID string, key string) (*modelmongo.AttachmentLinkResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAchievementServiceForRoute) GetSignedAttachmentFile(ctx context.Context, mongoID string, key string, expires int64, signature string) (*modelmongo.AttachmentDownload, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAchievementServiceForRoute) DeleteAttachment(ctx context.Context, userID string, roleID string, mongoID string, attachmentID string) (*modelmongo.AttachmentListResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockAchievementServiceForRoute) ReplaceAttachment(ctx context.Context, userID string, roleID string, mongoID string, attachmentID string, fileName string, fileType string, size int64, content io.Reader) (*modelmongo.Attachment, error) {
 	return nil, errors.New("not implemented")
 }
 